@@ -13,9 +13,13 @@ var tokenEnvVars = map[entities.ServiceType][]string{ //nolint:gochecknoglobals
 	entities.GITHUB:      {"GITHUB_TOKEN", "GH_TOKEN"},
 	entities.GITLAB:      {"GITLAB_TOKEN", "GL_TOKEN"},
 	entities.AZUREDEVOPS: {"AZURE_DEVOPS_EXT_PAT", "SYSTEM_ACCESSTOKEN"},
-	entities.BITBUCKET:   {},
-	entities.CODECOMMIT:  {},
+	entities.BITBUCKET:   {"BITBUCKET_TOKEN", "BITBUCKET_ACCESS_TOKEN"},
+	entities.CODECOMMIT:  {}, // the codecommit provider reads AWS_* credentials itself
 	entities.CODEBERG:    {"CODEBERG_TOKEN"},
+	entities.GITEA:       {"GITEA_TOKEN", "FORGEJO_TOKEN"},
+	entities.BITBUCKETDC: {"BITBUCKET_DC_TOKEN", "BITBUCKET_SERVER_TOKEN"},
+	entities.GERRIT:      {"GERRIT_TOKEN", "GERRIT_HTTP_PASSWORD"},
+	entities.LOCAL:       {"LOCAL_FORGE_AUTHOR"}, // the local provider uses the token as the author name
 }
 
 // tokenEnvHints maps each ServiceType to a human-readable string listing
@@ -25,9 +29,13 @@ var tokenEnvHints = map[entities.ServiceType]string{ //nolint:gochecknoglobals /
 	entities.GITHUB:      "GITHUB_TOKEN or GH_TOKEN",
 	entities.GITLAB:      "GITLAB_TOKEN or GL_TOKEN",
 	entities.AZUREDEVOPS: "AZURE_DEVOPS_EXT_PAT or SYSTEM_ACCESSTOKEN",
-	entities.BITBUCKET:   "",
-	entities.CODECOMMIT:  "",
+	entities.BITBUCKET:   "BITBUCKET_TOKEN or BITBUCKET_ACCESS_TOKEN",
+	entities.CODECOMMIT:  "AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (or an AWS profile)",
 	entities.CODEBERG:    "CODEBERG_TOKEN",
+	entities.GITEA:       "GITEA_TOKEN or FORGEJO_TOKEN",
+	entities.BITBUCKETDC: "BITBUCKET_DC_TOKEN or BITBUCKET_SERVER_TOKEN",
+	entities.GERRIT:      "GERRIT_TOKEN or GERRIT_HTTP_PASSWORD",
+	entities.LOCAL:       "LOCAL_FORGE_AUTHOR (optional author name)",
 }
 
 // ResolveTokenFromEnv returns the first non-empty token found in
@@ -82,6 +82,65 @@ func TestResolveTokenFromEnv(t *testing.T) {
 		assert.Equal(t, "ado-system", token)
 	})
 
+	t.Run("should return BITBUCKET_TOKEN when set", func(t *testing.T) {
+		// given
+		t.Setenv("BITBUCKET_TOKEN", "bb-token")
+
+		// when
+		token := helpers.ResolveTokenFromEnv(entities.BITBUCKET)
+
+		// then
+		assert.Equal(t, "bb-token", token)
+	})
+
+	t.Run("should fall back to BITBUCKET_ACCESS_TOKEN when BITBUCKET_TOKEN is empty", func(t *testing.T) {
+		// given
+		t.Setenv("BITBUCKET_TOKEN", "")
+		t.Setenv("BITBUCKET_ACCESS_TOKEN", "bb-access")
+
+		// when
+		token := helpers.ResolveTokenFromEnv(entities.BITBUCKET)
+
+		// then
+		assert.Equal(t, "bb-access", token)
+	})
+
+	t.Run("should fall back to FORGEJO_TOKEN when GITEA_TOKEN is empty", func(t *testing.T) {
+		// given
+		t.Setenv("GITEA_TOKEN", "")
+		t.Setenv("FORGEJO_TOKEN", "forgejo-token")
+
+		// when
+		token := helpers.ResolveTokenFromEnv(entities.GITEA)
+
+		// then
+		assert.Equal(t, "forgejo-token", token)
+	})
+
+	t.Run("should fall back to BITBUCKET_SERVER_TOKEN when BITBUCKET_DC_TOKEN is empty", func(t *testing.T) {
+		// given
+		t.Setenv("BITBUCKET_DC_TOKEN", "")
+		t.Setenv("BITBUCKET_SERVER_TOKEN", "server-token")
+
+		// when
+		token := helpers.ResolveTokenFromEnv(entities.BITBUCKETDC)
+
+		// then
+		assert.Equal(t, "server-token", token)
+	})
+
+	t.Run("should fall back to GERRIT_HTTP_PASSWORD when GERRIT_TOKEN is empty", func(t *testing.T) {
+		// given
+		t.Setenv("GERRIT_TOKEN", "")
+		t.Setenv("GERRIT_HTTP_PASSWORD", "jdoe:http-password")
+
+		// when
+		token := helpers.ResolveTokenFromEnv(entities.GERRIT)
+
+		// then
+		assert.Equal(t, "jdoe:http-password", token)
+	})
+
 	t.Run("should return empty string when no env var is set", func(t *testing.T) {
 		// given
 		t.Setenv("GITHUB_TOKEN", "")
@@ -139,6 +198,66 @@ func TestTokenEnvHint(t *testing.T) {
 		assert.Equal(t, "AZURE_DEVOPS_EXT_PAT or SYSTEM_ACCESSTOKEN", hint)
 	})
 
+	t.Run("should return Bitbucket env var names", func(t *testing.T) {
+		t.Parallel()
+
+		// given / when
+		hint := helpers.TokenEnvHint(entities.BITBUCKET)
+
+		// then
+		assert.Equal(t, "BITBUCKET_TOKEN or BITBUCKET_ACCESS_TOKEN", hint)
+	})
+
+	t.Run("should return AWS credential hints for CodeCommit", func(t *testing.T) {
+		t.Parallel()
+
+		// given / when
+		hint := helpers.TokenEnvHint(entities.CODECOMMIT)
+
+		// then
+		assert.Equal(t, "AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (or an AWS profile)", hint)
+	})
+
+	t.Run("should return Gitea and Forgejo env var names", func(t *testing.T) {
+		t.Parallel()
+
+		// given / when
+		hint := helpers.TokenEnvHint(entities.GITEA)
+
+		// then
+		assert.Equal(t, "GITEA_TOKEN or FORGEJO_TOKEN", hint)
+	})
+
+	t.Run("should return Bitbucket Data Center env var names", func(t *testing.T) {
+		t.Parallel()
+
+		// given / when
+		hint := helpers.TokenEnvHint(entities.BITBUCKETDC)
+
+		// then
+		assert.Equal(t, "BITBUCKET_DC_TOKEN or BITBUCKET_SERVER_TOKEN", hint)
+	})
+
+	t.Run("should return Gerrit env var names", func(t *testing.T) {
+		t.Parallel()
+
+		// given / when
+		hint := helpers.TokenEnvHint(entities.GERRIT)
+
+		// then
+		assert.Equal(t, "GERRIT_TOKEN or GERRIT_HTTP_PASSWORD", hint)
+	})
+
+	t.Run("should return local forge env var name", func(t *testing.T) {
+		t.Parallel()
+
+		// given / when
+		hint := helpers.TokenEnvHint(entities.LOCAL)
+
+		// then
+		assert.Equal(t, "LOCAL_FORGE_AUTHOR (optional author name)", hint)
+	})
+
 	t.Run("should return unknown provider for unsupported type", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,53 @@
+package entities
+
+import "github.com/go-git/go-git/v5/plumbing"
+
+// MirrorSyncOptions configures a git-level mirror sync between two remotes.
+type MirrorSyncOptions struct {
+	// CacheDir is the bare repository the source refs are fetched into. It is
+	// created on first use and reused by later syncs, which then only fetch
+	// new objects.
+	CacheDir string
+
+	// SourceUsername and TargetUsername are passed to GetAuthMethods of the
+	// adapters matching the source and target URLs.
+	SourceUsername string
+	TargetUsername string
+
+	// Include limits the sync to refs matching at least one pattern; empty
+	// means every branch, plus every tag when Tags is set. Exclude drops refs
+	// matching any pattern. Patterns are full ref names with at most one '*'
+	// that, as in git refspecs, also matches '/' (e.g. "refs/heads/release/*").
+	Include []string
+	Exclude []string
+
+	// Tags mirrors refs/tags/* alongside the branches.
+	Tags bool
+
+	// DryRun reports the changes without fetching or pushing anything.
+	DryRun bool
+}
+
+// RefAction is what a mirror sync does to one ref of the target.
+type RefAction string
+
+const (
+	RefActionCreate RefAction = "create"
+	RefActionUpdate RefAction = "update"
+	RefActionDelete RefAction = "delete"
+)
+
+// RefChange is one ref a mirror sync creates, updates or deletes on the target.
+type RefChange struct {
+	Name   plumbing.ReferenceName
+	Action RefAction
+	Old    plumbing.Hash // zero for RefActionCreate
+	New    plumbing.Hash // zero for RefActionDelete
+}
+
+// MirrorSyncReport lists the ref changes of a mirror sync, sorted by ref name.
+// With DryRun set they are the changes the sync would have made.
+type MirrorSyncReport struct {
+	Changes []RefChange
+	DryRun  bool
+}
@@ -13,7 +13,8 @@ import (
 )
 
 // GetAuthMethods returns the authentication methods for the given service type.
-// It delegates to the appropriate adapter based on the service type.
+// It delegates to the first adapter registered for the service type; use
+// GetAuthMethodsByURL when several hosts of the same service are registered.
 // Exported for use by autobump (github.com/rios0rios0/autobump).
 func (o *GitOperations) GetAuthMethods(
 	service globalEntities.ServiceType,
@@ -37,6 +38,34 @@ func (o *GitOperations) GetAuthMethods(
 	return authMethods, nil
 }
 
+// GetAuthMethodsByURL returns the authentication methods for the remote at
+// remoteURL. Unlike GetAuthMethods, it takes them from the adapter matching
+// the URL, so two hosts of the same service type (github.com and a GitHub
+// Enterprise server, say) each get their own credentials.
+// Exported for use by autobump (github.com/rios0rios0/autobump).
+func (o *GitOperations) GetAuthMethodsByURL(remoteURL, username string) ([]transport.AuthMethod, error) {
+	_, authMethods, err := o.adapterAuthByURL(remoteURL, username)
+	return authMethods, err
+}
+
+// adapterAuthByURL returns the adapter matching rawURL with its transport
+// configured, and the auth methods it offers for username.
+func (o *GitOperations) adapterAuthByURL(
+	rawURL, username string,
+) (globalEntities.LocalGitAuthProvider, []transport.AuthMethod, error) {
+	adapter := o.adapterFinder.GetAdapterByURL(rawURL)
+	if adapter == nil {
+		return nil, nil, fmt.Errorf("%w: no adapter matches %s", ErrAuthNotImplemented, sanitizeURL(rawURL))
+	}
+	adapter.ConfigureTransport()
+
+	authMethods := adapter.GetAuthMethods(username)
+	if len(authMethods) == 0 {
+		return nil, nil, fmt.Errorf("%w for %s", ErrNoAuthMethodFound, sanitizeURL(rawURL))
+	}
+	return adapter, authMethods, nil
+}
+
 // GetRemoteServiceType returns the type of the remote service (e.g. GitHub, GitLab).
 // Exported for use by autobump (github.com/rios0rios0/autobump).
 func (o *GitOperations) GetRemoteServiceType(repo *git.Repository) (globalEntities.ServiceType, error) {
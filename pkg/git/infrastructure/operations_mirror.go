@@ -0,0 +1,263 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+	log "github.com/sirupsen/logrus"
+
+	gitEntities "github.com/rios0rios0/gitforge/pkg/git/domain/entities"
+)
+
+// Remote names MirrorRemotes configures in its bare cache.
+const (
+	mirrorSourceRemoteName = "source"
+	mirrorTargetRemoteName = "target"
+)
+
+// MirrorRemotes makes the selected refs of targetURL match those of sourceURL,
+// for forges that cannot pull from the source themselves. It fetches the
+// changed refs into the bare cache at opts.CacheDir with the source adapter's
+// auth methods, then pushes them to the target with the target adapter's,
+// deleting selected target refs that no longer exist at the source. Target
+// refs outside the selection are left untouched.
+func (o *GitOperations) MirrorRemotes(
+	ctx context.Context,
+	sourceURL, targetURL string,
+	opts gitEntities.MirrorSyncOptions,
+) (*gitEntities.MirrorSyncReport, error) {
+	sourceURL, sourceAuths, err := o.mirrorEndpoint(sourceURL, opts.SourceUsername)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve source auth: %w", err)
+	}
+	targetURL, targetAuths, err := o.mirrorEndpoint(targetURL, opts.TargetUsername)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve target auth: %w", err)
+	}
+
+	sourceRefs, sourceAuth, err := listMirrorRefs(ctx, sourceURL, sourceAuths, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs of %s: %w", sanitizeURL(sourceURL), err)
+	}
+	targetRefs, targetAuth, err := listMirrorRefs(ctx, targetURL, targetAuths, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list refs of %s: %w", sanitizeURL(targetURL), err)
+	}
+
+	report := &gitEntities.MirrorSyncReport{
+		Changes: planRefChanges(sourceRefs, targetRefs),
+		DryRun:  opts.DryRun,
+	}
+	if opts.DryRun || len(report.Changes) == 0 {
+		return report, nil
+	}
+
+	cache, err := openMirrorCache(opts.CacheDir, sourceURL, targetURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var fetchSpecs, pushSpecs []config.RefSpec
+	for _, change := range report.Changes {
+		if change.Action == gitEntities.RefActionDelete {
+			pushSpecs = append(pushSpecs, config.RefSpec(":"+change.Name))
+			continue
+		}
+		spec := config.RefSpec("+" + change.Name + ":" + change.Name)
+		fetchSpecs = append(fetchSpecs, spec)
+		pushSpecs = append(pushSpecs, spec)
+	}
+
+	if len(fetchSpecs) > 0 {
+		log.Infof("Fetching %d refs from %s", len(fetchSpecs), sanitizeURL(sourceURL))
+		fetchErr := cache.FetchContext(ctx, &git.FetchOptions{
+			RemoteName: mirrorSourceRemoteName,
+			RefSpecs:   fetchSpecs,
+			Auth:       sourceAuth,
+			Tags:       git.NoTags,
+			Force:      true,
+		})
+		if fetchErr != nil && !errors.Is(fetchErr, git.NoErrAlreadyUpToDate) {
+			return nil, fmt.Errorf("failed to fetch from %s: %w", sanitizeURL(sourceURL), fetchErr)
+		}
+	}
+
+	log.Infof("Pushing %d ref changes to %s", len(pushSpecs), sanitizeURL(targetURL))
+	pushErr := cache.PushContext(ctx, &git.PushOptions{
+		RemoteName: mirrorTargetRemoteName,
+		RefSpecs:   pushSpecs,
+		Auth:       targetAuth,
+		Force:      true,
+	})
+	if pushErr != nil && !errors.Is(pushErr, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("failed to push to %s: %w", sanitizeURL(targetURL), pushErr)
+	}
+
+	return report, nil
+}
+
+// mirrorEndpoint returns the URL to reach rawURL at and the auth methods to try
+// on it, both from the adapter matching rawURL, so two hosts of the same forge
+// (github.com and a GitHub Enterprise server, say) each get their own
+// credentials. Remotes on the local filesystem need no auth.
+func (o *GitOperations) mirrorEndpoint(rawURL, username string) (string, []transport.AuthMethod, error) {
+	if strings.HasPrefix(rawURL, "file://") || filepath.IsAbs(rawURL) {
+		return rawURL, []transport.AuthMethod{nil}, nil
+	}
+
+	adapter, authMethods, err := o.adapterAuthByURL(rawURL, username)
+	if err != nil {
+		return "", nil, err
+	}
+	return adapter.PrepareCloneURL(rawURL), authMethods, nil
+}
+
+// listMirrorRefs lists the branches and tags of the remote at url that opts
+// selects, trying each auth method until one succeeds, and returns the one
+// that did. An empty remote has no refs.
+func listMirrorRefs(
+	ctx context.Context,
+	url string,
+	authMethods []transport.AuthMethod,
+	opts gitEntities.MirrorSyncOptions,
+) (map[plumbing.ReferenceName]plumbing.Hash, transport.AuthMethod, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: mirrorSourceRemoteName,
+		URLs: []string{url},
+	})
+
+	var lastErr error
+	for _, auth := range authMethods {
+		refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return map[plumbing.ReferenceName]plumbing.Hash{}, auth, nil
+		}
+		if err != nil {
+			lastErr = err
+			log.Debugf("Listing refs failed with auth method %T: %v", auth, err)
+			continue
+		}
+
+		selected := make(map[plumbing.ReferenceName]plumbing.Hash)
+		for _, ref := range refs {
+			if ref.Type() == plumbing.HashReference && selectsRef(ref.Name(), opts) {
+				selected[ref.Name()] = ref.Hash()
+			}
+		}
+		return selected, auth, nil
+	}
+
+	if lastErr == nil {
+		lastErr = ErrNoAuthMethodFound
+	}
+	return nil, nil, lastErr
+}
+
+// selectsRef reports whether opts mirrors the ref called name.
+func selectsRef(name plumbing.ReferenceName, opts gitEntities.MirrorSyncOptions) bool {
+	if !name.IsBranch() && !(opts.Tags && name.IsTag()) {
+		return false
+	}
+	if len(opts.Include) > 0 && !slices.ContainsFunc(opts.Include, refPatternMatcher(name)) {
+		return false
+	}
+	return !slices.ContainsFunc(opts.Exclude, refPatternMatcher(name))
+}
+
+// refPatternMatcher returns a predicate matching patterns against name. A '*'
+// matches any run of characters, '/' included, as in git refspecs.
+func refPatternMatcher(name plumbing.ReferenceName) func(pattern string) bool {
+	return func(pattern string) bool {
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard {
+			return string(name) == pattern
+		}
+		return len(name) >= len(prefix)+len(suffix) &&
+			strings.HasPrefix(string(name), prefix) &&
+			strings.HasSuffix(string(name), suffix)
+	}
+}
+
+// planRefChanges returns the changes that make target match source, sorted by
+// ref name.
+func planRefChanges(source, target map[plumbing.ReferenceName]plumbing.Hash) []gitEntities.RefChange {
+	var changes []gitEntities.RefChange
+	for name, hash := range source {
+		old, exists := target[name]
+		switch {
+		case !exists:
+			changes = append(changes, gitEntities.RefChange{
+				Name: name, Action: gitEntities.RefActionCreate, New: hash,
+			})
+		case old != hash:
+			changes = append(changes, gitEntities.RefChange{
+				Name: name, Action: gitEntities.RefActionUpdate, Old: old, New: hash,
+			})
+		}
+	}
+	for name, hash := range target {
+		if _, exists := source[name]; !exists {
+			changes = append(changes, gitEntities.RefChange{
+				Name: name, Action: gitEntities.RefActionDelete, Old: hash,
+			})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b gitEntities.RefChange) int {
+		return strings.Compare(string(a.Name), string(b.Name))
+	})
+	return changes
+}
+
+// openMirrorCache opens the bare cache at dir, initializing it on first use,
+// and points its source and target remotes at the given URLs.
+func openMirrorCache(dir, sourceURL, targetURL string) (*git.Repository, error) {
+	if dir == "" {
+		return nil, errors.New("mirror cache directory is required")
+	}
+
+	cache, err := git.PlainOpen(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		cache, err = git.PlainInit(dir, true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mirror cache %s: %w", dir, err)
+	}
+
+	for name, url := range map[string]string{
+		mirrorSourceRemoteName: sourceURL,
+		mirrorTargetRemoteName: targetURL,
+	} {
+		if remoteErr := setRemoteURL(cache, name, url); remoteErr != nil {
+			return nil, remoteErr
+		}
+	}
+	return cache, nil
+}
+
+// setRemoteURL creates remote name with url, replacing it if it points elsewhere.
+func setRemoteURL(repo *git.Repository, name, url string) error {
+	if remote, err := repo.Remote(name); err == nil {
+		if slices.Equal(remote.Config().URLs, []string{url}) {
+			return nil
+		}
+		if deleteErr := repo.DeleteRemote(name); deleteErr != nil {
+			return fmt.Errorf("failed to replace remote %q: %w", name, deleteErr)
+		}
+	}
+
+	_, err := repo.CreateRemote(&config.RemoteConfig{Name: name, URLs: []string{url}})
+	if err != nil {
+		return fmt.Errorf("failed to configure remote %q: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+//go:build unit
+
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gitEntities "github.com/rios0rios0/gitforge/pkg/git/domain/entities"
+	gitops "github.com/rios0rios0/gitforge/pkg/git/infrastructure"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/test/builders"
+	"github.com/rios0rios0/gitforge/test/doubles"
+)
+
+func TestMirrorRemotes(t *testing.T) {
+	t.Parallel()
+
+	newOps := func() *gitops.GitOperations {
+		return gitops.NewGitOperations(builders.NewAdapterFinderStubBuilder().Build().(*doubles.AdapterFinderStub))
+	}
+
+	t.Run("should create every branch and tag on an empty target", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source, sourceDir := initBareRepo(t)
+		mainHash := commitOnBranch(t, source, "main", "initial")
+		featureHash := commitOnBranch(t, source, "feature", "feature")
+		setRef(t, source, "refs/tags/v1.0.0", mainHash)
+		target, targetDir := initBareRepo(t)
+
+		// when
+		report, err := newOps().MirrorRemotes(context.Background(), sourceDir, targetDir, gitEntities.MirrorSyncOptions{
+			CacheDir: t.TempDir(),
+			Tags:     true,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []gitEntities.RefChange{
+			{Name: "refs/heads/feature", Action: gitEntities.RefActionCreate, New: featureHash},
+			{Name: "refs/heads/main", Action: gitEntities.RefActionCreate, New: mainHash},
+			{Name: "refs/tags/v1.0.0", Action: gitEntities.RefActionCreate, New: mainHash},
+		}, report.Changes)
+		assert.Equal(t, mainHash, refHash(t, target, "refs/heads/main"))
+		assert.Equal(t, featureHash, refHash(t, target, "refs/heads/feature"))
+		assert.Equal(t, mainHash, refHash(t, target, "refs/tags/v1.0.0"))
+	})
+
+	t.Run("should force rewritten branches and prune removed ones with a fresh cache", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source, sourceDir := initBareRepo(t)
+		oldMain := commitOnBranch(t, source, "main", "initial")
+		commitOnBranch(t, source, "feature", "feature")
+		target, targetDir := initBareRepo(t)
+		_, err := newOps().MirrorRemotes(context.Background(), sourceDir, targetDir, gitEntities.MirrorSyncOptions{
+			CacheDir: t.TempDir(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, source.Storer.RemoveReference("refs/heads/main"))
+		require.NoError(t, source.Storer.RemoveReference("refs/heads/feature"))
+		newMain := commitOnBranch(t, source, "main", "rewritten")
+
+		// when
+		report, err := newOps().MirrorRemotes(context.Background(), sourceDir, targetDir, gitEntities.MirrorSyncOptions{
+			CacheDir: t.TempDir(),
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, report.Changes, 2)
+		assert.Equal(t, gitEntities.RefActionDelete, report.Changes[0].Action)
+		assert.Equal(t, plumbing.ReferenceName("refs/heads/feature"), report.Changes[0].Name)
+		assert.Equal(t, gitEntities.RefChange{
+			Name: "refs/heads/main", Action: gitEntities.RefActionUpdate, Old: oldMain, New: newMain,
+		}, report.Changes[1])
+		assert.Equal(t, newMain, refHash(t, target, "refs/heads/main"))
+		_, err = target.Reference("refs/heads/feature", false)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("should report the changes without pushing them on a dry run", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source, sourceDir := initBareRepo(t)
+		mainHash := commitOnBranch(t, source, "main", "initial")
+		target, targetDir := initBareRepo(t)
+
+		// when
+		report, err := newOps().MirrorRemotes(context.Background(), sourceDir, targetDir, gitEntities.MirrorSyncOptions{
+			CacheDir: t.TempDir(),
+			DryRun:   true,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []gitEntities.RefChange{
+			{Name: "refs/heads/main", Action: gitEntities.RefActionCreate, New: mainHash},
+		}, report.Changes)
+		_, err = target.Reference("refs/heads/main", false)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("should leave excluded refs and unselected tags untouched", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source, sourceDir := initBareRepo(t)
+		mainHash := commitOnBranch(t, source, "main", "initial")
+		commitOnBranch(t, source, "wip/spike", "spike")
+		setRef(t, source, "refs/tags/v1.0.0", mainHash)
+		target, targetDir := initBareRepo(t)
+		keepHash := commitOnBranch(t, target, "keep", "target only")
+
+		// when
+		report, err := newOps().MirrorRemotes(context.Background(), sourceDir, targetDir, gitEntities.MirrorSyncOptions{
+			CacheDir: t.TempDir(),
+			Exclude:  []string{"refs/heads/wip/*", "refs/heads/keep"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []gitEntities.RefChange{
+			{Name: "refs/heads/main", Action: gitEntities.RefActionCreate, New: mainHash},
+		}, report.Changes)
+		assert.Equal(t, keepHash, refHash(t, target, "refs/heads/keep"))
+		_, err = target.Reference("refs/tags/v1.0.0", false)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("should only mirror included refs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source, sourceDir := initBareRepo(t)
+		commitOnBranch(t, source, "main", "initial")
+		releaseHash := commitOnBranch(t, source, "release/1.x", "release")
+		_, targetDir := initBareRepo(t)
+
+		// when
+		report, err := newOps().MirrorRemotes(context.Background(), sourceDir, targetDir, gitEntities.MirrorSyncOptions{
+			CacheDir: t.TempDir(),
+			Include:  []string{"refs/heads/release/*"},
+			DryRun:   true,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []gitEntities.RefChange{
+			{Name: "refs/heads/release/1.x", Action: gitEntities.RefActionCreate, New: releaseHash},
+		}, report.Changes)
+	})
+
+	t.Run("should return an error when the cache directory is missing", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source, sourceDir := initBareRepo(t)
+		commitOnBranch(t, source, "main", "initial")
+		_, targetDir := initBareRepo(t)
+
+		// when
+		_, err := newOps().MirrorRemotes(context.Background(), sourceDir, targetDir, gitEntities.MirrorSyncOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "mirror cache directory is required")
+	})
+}
+
+func TestMirrorRemotesAuth(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should authenticate each end with the adapter matching its URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source, sourceDir := initBareRepo(t)
+		mainHash := commitOnBranch(t, source, "main", "initial")
+		target, targetDir := initBareRepo(t)
+		sourceURL := "https://github.com/my-org/my-repo.git"
+		targetURL := "https://ghe.example.com/my-org/my-repo.git"
+		github := &doubles.ForgeProviderStub{
+			MatchURLValue:         sourceURL,
+			ServiceTypeValue:      globalEntities.GITHUB,
+			AuthMethodsValue:      []transport.AuthMethod{&doubles.AuthStub{}},
+			PreparedCloneURLValue: sourceDir,
+		}
+		enterprise := &doubles.ForgeProviderStub{
+			MatchURLValue:         targetURL,
+			ServiceTypeValue:      globalEntities.GITHUB,
+			AuthMethodsValue:      []transport.AuthMethod{&doubles.AuthStub{}},
+			PreparedCloneURLValue: targetDir,
+		}
+		finder := builders.NewAdapterFinderStubBuilder().WithAdapters(github, enterprise).Build()
+		ops := gitops.NewGitOperations(finder.(*doubles.AdapterFinderStub))
+
+		// when
+		_, err := ops.MirrorRemotes(context.Background(), sourceURL, targetURL, gitEntities.MirrorSyncOptions{
+			CacheDir:       t.TempDir(),
+			SourceUsername: "source-user",
+			TargetUsername: "target-user",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, mainHash, refHash(t, target, "refs/heads/main"))
+		assert.Equal(t, []string{"source-user"}, github.AuthUsernames)
+		assert.Equal(t, []string{"target-user"}, enterprise.AuthUsernames)
+		assert.Equal(t, 1, github.TransportConfigured)
+		assert.Equal(t, 1, enterprise.TransportConfigured)
+	})
+
+	t.Run("should return an error when no adapter matches the URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, targetDir := initBareRepo(t)
+		github := &doubles.ForgeProviderStub{
+			MatchURLValue:    "https://github.com/my-org/my-repo.git",
+			ServiceTypeValue: globalEntities.GITHUB,
+			AuthMethodsValue: []transport.AuthMethod{&doubles.AuthStub{}},
+		}
+		finder := builders.NewAdapterFinderStubBuilder().WithAdapters(github).Build()
+		ops := gitops.NewGitOperations(finder.(*doubles.AdapterFinderStub))
+
+		// when
+		_, err := ops.MirrorRemotes(
+			context.Background(), "https://ghe.example.com/my-org/my-repo.git", targetDir,
+			gitEntities.MirrorSyncOptions{CacheDir: t.TempDir()},
+		)
+
+		// then
+		require.ErrorIs(t, err, gitops.ErrAuthNotImplemented)
+		assert.Empty(t, github.AuthUsernames)
+	})
+}
+
+// initBareRepo creates an empty bare repository in a temporary directory.
+func initBareRepo(t *testing.T) (*git.Repository, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, true)
+	require.NoError(t, err)
+	return repo, dir
+}
+
+// commitOnBranch adds an empty-tree commit on top of branch, creating the
+// branch if needed, and returns its hash.
+func commitOnBranch(t *testing.T, repo *git.Repository, branch, message string) plumbing.Hash {
+	t.Helper()
+
+	name := plumbing.NewBranchReferenceName(branch)
+	commitObj := &object.Commit{
+		Author:    object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+		Committer: object.Signature{Name: "Test User", Email: "test@example.com", When: time.Now()},
+		Message:   message,
+	}
+	if ref, err := repo.Reference(name, false); err == nil {
+		commitObj.ParentHashes = []plumbing.Hash{ref.Hash()}
+	}
+
+	tree := repo.Storer.NewEncodedObject()
+	require.NoError(t, (&object.Tree{}).Encode(tree))
+	treeHash, err := repo.Storer.SetEncodedObject(tree)
+	require.NoError(t, err)
+	commitObj.TreeHash = treeHash
+
+	obj := repo.Storer.NewEncodedObject()
+	require.NoError(t, commitObj.Encode(obj))
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	require.NoError(t, err)
+	setRef(t, repo, string(name), hash)
+	return hash
+}
+
+func setRef(t *testing.T, repo *git.Repository, name string, hash plumbing.Hash) {
+	t.Helper()
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), hash)))
+}
+
+func refHash(t *testing.T, repo *git.Repository, name string) plumbing.Hash {
+	t.Helper()
+	ref, err := repo.Reference(plumbing.ReferenceName(name), false)
+	require.NoError(t, err)
+	return ref.Hash()
+}
@@ -44,7 +44,8 @@ func PushChangesSSH(repo *git.Repository, refSpec config.RefSpec, authMethods []
 }
 
 // PushChangesHTTPS pushes the changes to the remote repository over HTTPS.
-// It tries each authentication method returned by the adapter until one succeeds.
+// It tries each authentication method of the adapter matching the origin URL
+// until one succeeds.
 func (o *GitOperations) PushChangesHTTPS(
 	repo *git.Repository,
 	username string,
@@ -56,11 +57,11 @@ func (o *GitOperations) PushChangesHTTPS(
 		RemoteName: originRemoteName,
 	}
 
-	service, err := o.GetRemoteServiceType(repo)
+	remoteURL, err := GetRemoteRepoURL(repo)
 	if err != nil {
 		return err
 	}
-	authMethods, err := o.GetAuthMethods(service, username)
+	authMethods, err := o.GetAuthMethodsByURL(remoteURL, username)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,9 @@
 package infrastructure_test
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 
 	gitcfg "github.com/go-git/go-git/v5/config"
@@ -11,6 +14,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	gitops "github.com/rios0rios0/gitforge/pkg/git/infrastructure"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/github"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/gitlab"
+	"github.com/rios0rios0/gitforge/test/builders"
+	"github.com/rios0rios0/gitforge/test/doubles"
 )
 
 func TestPushWithTransportDetection(t *testing.T) {
@@ -129,3 +137,117 @@ func TestPushWithTransportDetection(t *testing.T) {
 		assert.Contains(t, err.Error(), "unsupported remote URL scheme")
 	})
 }
+
+func TestPushChangesHTTPS(t *testing.T) {
+	t.Parallel()
+
+	refSpec := gitcfg.RefSpec("refs/heads/master:refs/heads/master")
+
+	tests := []struct {
+		name string
+		// adapters registers a public adapter first and one for the server
+		// at serverURL second, both of the same service type.
+		adapters func(t *testing.T, serverURL string) []globalEntities.LocalGitAuthProvider
+		expected string
+	}{
+		{
+			name: "should push to a GitHub Enterprise remote with the enterprise token",
+			adapters: func(t *testing.T, serverURL string) []globalEntities.LocalGitAuthProvider {
+				t.Helper()
+				enterprise, err := github.NewEnterpriseProvider("ghe-token", serverURL, "")
+				require.NoError(t, err)
+				return []globalEntities.LocalGitAuthProvider{
+					github.NewProvider("github-token").(globalEntities.LocalGitAuthProvider),
+					enterprise.(globalEntities.LocalGitAuthProvider),
+				}
+			},
+			expected: "ghe-token",
+		},
+		{
+			name: "should push to a self-managed GitLab remote with the self-managed token",
+			adapters: func(t *testing.T, serverURL string) []globalEntities.LocalGitAuthProvider {
+				t.Helper()
+				selfManaged, err := gitlab.NewSelfManagedProvider("self-managed-token", serverURL, 0)
+				require.NoError(t, err)
+				return []globalEntities.LocalGitAuthProvider{
+					gitlab.NewProvider("gitlab-token").(globalEntities.LocalGitAuthProvider),
+					selfManaged.(globalEntities.LocalGitAuthProvider),
+				}
+			},
+			expected: "self-managed-token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			server, passwords := newAuthRecordingServer(t)
+			finder := builders.NewAdapterFinderStubBuilder().WithAdapters(tt.adapters(t, server.URL)...).Build()
+			ops := gitops.NewGitOperations(finder.(*doubles.AdapterFinderStub))
+			repo := createInMemoryRepoWithCommit(t)
+			_, err := repo.CreateRemote(&gitcfg.RemoteConfig{
+				Name: "origin",
+				URLs: []string{server.URL + "/my-org/my-repo.git"},
+			})
+			require.NoError(t, err)
+
+			// when
+			err = ops.PushChangesHTTPS(repo, "", refSpec)
+
+			// then
+			require.Error(t, err, "the recording server rejects every push")
+			recorded := passwords()
+			require.NotEmpty(t, recorded)
+			for _, password := range recorded {
+				assert.Equal(t, tt.expected, password)
+			}
+		})
+	}
+
+	t.Run("should return an error when no adapter matches the origin URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		finder := builders.NewAdapterFinderStubBuilder().
+			WithAdapters(github.NewProvider("github-token").(globalEntities.LocalGitAuthProvider)).
+			Build()
+		ops := gitops.NewGitOperations(finder.(*doubles.AdapterFinderStub))
+		repo := createInMemoryRepoWithCommit(t)
+		_, err := repo.CreateRemote(&gitcfg.RemoteConfig{
+			Name: "origin",
+			URLs: []string{"https://ghe.example.com/my-org/my-repo.git"},
+		})
+		require.NoError(t, err)
+
+		// when
+		err = ops.PushChangesHTTPS(repo, "", refSpec)
+
+		// then
+		require.ErrorIs(t, err, gitops.ErrAuthNotImplemented)
+	})
+}
+
+// newAuthRecordingServer starts a git HTTP server that answers every request
+// with 401 and returns a func listing the basic auth passwords it received.
+func newAuthRecordingServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var passwords []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, ok := r.BasicAuth(); ok {
+			mu.Lock()
+			passwords = append(passwords, password)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return passwords
+	}
+}
@@ -1,9 +1,11 @@
 package infrastructure
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -33,49 +35,188 @@ type remoteURLParser func(cleaned string) (*RemoteURLInfo, bool)
 // prURLParser defines a function that attempts to parse PR URL path segments.
 type prURLParser func(segments []string) (*PullRequestURLInfo, error)
 
+// prURLParserEntry pairs a host with the parser of its pull request URLs.
+type prURLParserEntry struct {
+	host  string
+	parse prURLParser
+}
+
 // remoteURLParserEntry pairs a URL detection predicate with its parsing function.
 type remoteURLParserEntry struct {
 	matches func(string) bool
 	parse   remoteURLParser
 }
 
+// URLParserOption configures ParseRemoteURL and ParsePullRequestURL, e.g. to
+// recognise self-hosted instances on private hostnames.
+type URLParserOption func(*urlParserConfig)
+
+// urlParserConfig holds the hosts each provider's parsers accept.
+type urlParserConfig struct {
+	githubHosts            []string
+	gitlabHosts            []string
+	azureDevOpsServerHosts []string
+}
+
+// WithGitHubHosts makes the GitHub parsers accept the given GitHub Enterprise
+// Server hosts (e.g. "github.corp.example") in addition to github.com.
+func WithGitHubHosts(hosts ...string) URLParserOption {
+	return func(cfg *urlParserConfig) {
+		for _, host := range hosts {
+			if normalized := normalizeHost(host); normalized != "" {
+				cfg.githubHosts = append(cfg.githubHosts, normalized)
+			}
+		}
+	}
+}
+
+// WithGitLabHosts makes the GitLab parsers accept the given self-managed GitLab
+// hosts (e.g. "git.corp.example") in addition to gitlab.com.
+func WithGitLabHosts(hosts ...string) URLParserOption {
+	return func(cfg *urlParserConfig) {
+		for _, host := range hosts {
+			if normalized := normalizeHost(host); normalized != "" {
+				cfg.gitlabHosts = append(cfg.gitlabHosts, normalized)
+			}
+		}
+	}
+}
+
+// WithAzureDevOpsServerHosts makes the Azure DevOps parsers accept remotes and pull
+// request URLs of the given on-premises Azure DevOps Server hosts (e.g. "tfs.corp"),
+// in the form {server}/{collection}/{project}/_git/{repo}. The collection is
+// reported as the Organization.
+func WithAzureDevOpsServerHosts(hosts ...string) URLParserOption {
+	return func(cfg *urlParserConfig) {
+		for _, host := range hosts {
+			if normalized := normalizeHost(host); normalized != "" {
+				cfg.azureDevOpsServerHosts = append(cfg.azureDevOpsServerHosts, normalized)
+			}
+		}
+	}
+}
+
+func newURLParserConfig(opts []URLParserOption) *urlParserConfig {
+	cfg := &urlParserConfig{
+		githubHosts: []string{"github.com"},
+		gitlabHosts: []string{"gitlab.com"},
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// normalizeHost reduces "https://host/" or "host" to the bare host name.
+func normalizeHost(host string) string {
+	if _, after, found := strings.Cut(host, "://"); found {
+		host = after
+	}
+	host, _, _ = strings.Cut(host, "/")
+	return strings.ToLower(host)
+}
+
+// cutHostPath returns the path that follows the first of hosts found in cleaned,
+// accepting an optional ":port" between the host and the path.
+func cutHostPath(cleaned string, hosts []string) (string, bool) {
+	for _, host := range hosts {
+		_, rest, found := strings.Cut(cleaned, host)
+		if !found {
+			continue
+		}
+		if after, ok := strings.CutPrefix(rest, "/"); ok {
+			return after, true
+		}
+		if withPort, ok := strings.CutPrefix(rest, ":"); ok {
+			port, after, hasPath := strings.Cut(withPort, "/")
+			if _, err := strconv.Atoi(port); hasPath && err == nil {
+				return after, true
+			}
+		}
+	}
+	return "", false
+}
+
 // getRemoteURLParsers returns the ordered list of remote URL parsers.
 // Each entry is tried in order; the first match wins.
-func getRemoteURLParsers() []remoteURLParserEntry {
+func getRemoteURLParsers(cfg *urlParserConfig) []remoteURLParserEntry {
 	return []remoteURLParserEntry{
-		{matchesGitHubSSH, parseGitHubSSHRemote},
-		{matchesGitHubHTTPS, parseGitHubHTTPSRemote},
-		{matchesGitLabSSH, parseGitLabSSHRemote},
-		{matchesGitLabHTTPS, parseGitLabHTTPSRemote},
+		{
+			func(cleaned string) bool { return matchesGitHubSSH(cleaned, cfg.githubHosts) },
+			parseGitHubSSHRemote,
+		},
+		{
+			func(cleaned string) bool { return matchesGitHubHTTPS(cleaned, cfg.githubHosts) },
+			func(cleaned string) (*RemoteURLInfo, bool) { return parseGitHubHTTPSRemote(cleaned, cfg.githubHosts) },
+		},
+		{
+			func(cleaned string) bool { return matchesGitLabSSH(cleaned, cfg.gitlabHosts) },
+			parseGitLabSSHRemote,
+		},
+		{
+			func(cleaned string) bool { return matchesGitLabHTTPS(cleaned, cfg.gitlabHosts) },
+			func(cleaned string) (*RemoteURLInfo, bool) { return parseGitLabHTTPSRemote(cleaned, cfg.gitlabHosts) },
+		},
 		{matchesAzureDevOpsSSH, parseAzureDevOpsSSHRemote},
 		{matchesAzureDevOpsHTTPS, parseAzureDevOpsHTTPSRemote},
+		{
+			func(cleaned string) bool { return matchesAzureDevOpsServer(cleaned, cfg.azureDevOpsServerHosts) },
+			parseAzureDevOpsServerRemote,
+		},
+		{matchesBitbucketSSH, parseBitbucketSSHRemote},
+		{matchesBitbucketHTTPS, parseBitbucketHTTPSRemote},
 	}
 }
 
-// getPRURLParsers returns the map of host substrings to their PR URL parsing functions.
-func getPRURLParsers() map[string]prURLParser {
-	return map[string]prURLParser{
-		"github.com":    parseGitHubPRURL,
-		"dev.azure.com": parseAzureDevOpsPRURL,
+// getPRURLParsers returns the PR URL parsers by host, ordered from the host
+// with the most labels to the one with the fewest, so a configured host that
+// is a subdomain of another (e.g. "tfs.git.corp" and "git.corp") is tried
+// first. Hosts with as many labels keep their order.
+func getPRURLParsers(cfg *urlParserConfig) []prURLParserEntry {
+	parsers := []prURLParserEntry{
+		{"dev.azure.com", parseAzureDevOpsPRURL},
+		{"bitbucket.org", parseBitbucketPRURL},
+	}
+	for _, host := range cfg.githubHosts {
+		parsers = append(parsers, prURLParserEntry{host, parseGitHubPRURL})
+	}
+	for _, host := range cfg.azureDevOpsServerHosts {
+		parsers = append(parsers, prURLParserEntry{host, parseAzureDevOpsServerPRURL})
 	}
+	slices.SortStableFunc(parsers, func(a, b prURLParserEntry) int {
+		return cmp.Compare(strings.Count(b.host, "."), strings.Count(a.host, "."))
+	})
+	return parsers
+}
+
+// matchesHost reports whether hostname is host, ignoring any port of host, or
+// one of its subdomains.
+func matchesHost(hostname, host string) bool {
+	host, _, _ = strings.Cut(host, ":")
+	return hostname == host || strings.HasSuffix(hostname, "."+host)
 }
 
 // ParseRemoteURL extracts provider, organization, project, and repository name from a Git remote URL.
 // Supported formats:
 //   - GitHub SSH:           git@github.com:owner/repo.git
 //   - GitHub HTTPS:         https://github.com/owner/repo.git
+//   - GitHub Enterprise:    git@{host}:owner/repo.git or https://{host}/owner/repo.git (see WithGitHubHosts)
 //   - GitLab SSH:           git@gitlab.com:group/repo.git
 //   - GitLab HTTPS:         https://gitlab.com/group/repo.git
+//   - GitLab self-managed:  ssh://git@{host}:2222/group/sub/repo.git or https://{host}/group/repo.git (see WithGitLabHosts)
 //   - Azure DevOps SSH:     git@ssh.dev.azure.com:v3/org/project/repo
 //   - Azure DevOps HTTPS:   https://dev.azure.com/org/project/_git/repo
-func ParseRemoteURL(rawURL string) (*RemoteURLInfo, error) {
+//   - Azure DevOps Server:  https://{host}/tfs/collection/project/_git/repo (see WithAzureDevOpsServerHosts)
+//   - Bitbucket SSH:        git@bitbucket.org:workspace/repo.git
+//   - Bitbucket HTTPS:      https://bitbucket.org/workspace/repo.git
+func ParseRemoteURL(rawURL string, opts ...URLParserOption) (*RemoteURLInfo, error) {
 	if rawURL == "" {
 		return nil, errors.New("empty remote URL")
 	}
 
 	cleaned := strings.TrimSuffix(rawURL, ".git")
 
-	for _, entry := range getRemoteURLParsers() {
+	for _, entry := range getRemoteURLParsers(newURLParserConfig(opts)) {
 		if entry.matches(cleaned) {
 			info, ok := entry.parse(cleaned)
 			if ok {
@@ -89,9 +230,11 @@ func ParseRemoteURL(rawURL string) (*RemoteURLInfo, error) {
 
 // ParsePullRequestURL extracts provider, organization, project, repository, and PR ID from a PR URL.
 // Supported formats:
-//   - GitHub:        https://github.com/{org}/{repo}/pull/{id}
+//   - GitHub:        https://github.com/{org}/{repo}/pull/{id} (or a WithGitHubHosts host)
 //   - Azure DevOps:  https://dev.azure.com/{org}/{project}/_git/{repo}/pullrequest/{id}
-func ParsePullRequestURL(rawURL string) (*PullRequestURLInfo, error) {
+//   - Azure DevOps Server: https://{host}/tfs/{collection}/{project}/_git/{repo}/pullrequest/{id}
+//   - Bitbucket:     https://bitbucket.org/{workspace}/{repo}/pull-requests/{id}
+func ParsePullRequestURL(rawURL string, opts ...URLParserOption) (*PullRequestURLInfo, error) {
 	if rawURL == "" {
 		return nil, errors.New("empty pull request URL")
 	}
@@ -101,22 +244,31 @@ func ParsePullRequestURL(rawURL string) (*PullRequestURLInfo, error) {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	host := strings.ToLower(parsed.Host)
+	hostname := strings.ToLower(parsed.Hostname())
 	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
 
-	for hostKey, parser := range getPRURLParsers() {
-		if strings.Contains(host, hostKey) {
-			return parser(segments)
+	for _, entry := range getPRURLParsers(newURLParserConfig(opts)) {
+		if matchesHost(hostname, entry.host) {
+			return entry.parse(segments)
 		}
 	}
 
-	return nil, fmt.Errorf("unsupported provider host: %q", host)
+	return nil, fmt.Errorf("unsupported provider host: %q", hostname)
 }
 
 // --- GitHub remote URL matchers and parsers ---
 
-func matchesGitHubSSH(cleaned string) bool {
-	return strings.HasPrefix(cleaned, "git@") && strings.Contains(cleaned, "github.com")
+func matchesGitHubSSH(cleaned string, hosts []string) bool {
+	if !strings.HasPrefix(cleaned, "git@") {
+		return false
+	}
+	for _, host := range hosts {
+		hostname, _, _ := strings.Cut(host, ":")
+		if strings.Contains(cleaned, hostname) {
+			return true
+		}
+	}
+	return false
 }
 
 func parseGitHubSSHRemote(cleaned string) (*RemoteURLInfo, bool) {
@@ -135,12 +287,13 @@ func parseGitHubSSHRemote(cleaned string) (*RemoteURLInfo, bool) {
 	}, true
 }
 
-func matchesGitHubHTTPS(cleaned string) bool {
-	return strings.Contains(cleaned, "github.com/")
+func matchesGitHubHTTPS(cleaned string, hosts []string) bool {
+	_, ok := cutHostPath(cleaned, hosts)
+	return ok
 }
 
-func parseGitHubHTTPSRemote(cleaned string) (*RemoteURLInfo, bool) {
-	_, after, _ := strings.Cut(cleaned, "github.com/")
+func parseGitHubHTTPSRemote(cleaned string, hosts []string) (*RemoteURLInfo, bool) {
+	after, _ := cutHostPath(cleaned, hosts)
 	parts := strings.Split(after, "/")
 	if len(parts) < 2 { //nolint:mnd // owner/repo
 		return nil, false
@@ -154,16 +307,39 @@ func parseGitHubHTTPSRemote(cleaned string) (*RemoteURLInfo, bool) {
 
 // --- GitLab remote URL matchers and parsers ---
 
-func matchesGitLabSSH(cleaned string) bool {
-	return strings.HasPrefix(cleaned, "git@") && strings.Contains(cleaned, "gitlab")
+// matchesGitLabSSH accepts scp-style "git@host:path" remotes and "ssh://" remotes
+// (which may carry a custom port) whose host is a GitLab host or contains "gitlab".
+func matchesGitLabSSH(cleaned string, hosts []string) bool {
+	if !strings.HasPrefix(cleaned, "git@") && !strings.HasPrefix(cleaned, "ssh://") {
+		return false
+	}
+	if strings.Contains(cleaned, "gitlab") {
+		return true
+	}
+	for _, host := range hosts {
+		hostname, _, _ := strings.Cut(host, ":")
+		if strings.Contains(cleaned, hostname) {
+			return true
+		}
+	}
+	return false
 }
 
 func parseGitLabSSHRemote(cleaned string) (*RemoteURLInfo, bool) {
-	_, after, ok := strings.Cut(cleaned, ":")
-	if !ok {
-		return nil, false
+	var path string
+	if strings.HasPrefix(cleaned, "ssh://") {
+		parsed, err := url.Parse(cleaned)
+		if err != nil {
+			return nil, false
+		}
+		path = strings.TrimPrefix(parsed.Path, "/")
+	} else {
+		_, after, ok := strings.Cut(cleaned, ":")
+		if !ok {
+			return nil, false
+		}
+		path = after
 	}
-	path := after
 	parts := strings.Split(path, "/")
 	if len(parts) < 2 { //nolint:mnd // group/repo
 		return nil, false
@@ -175,12 +351,13 @@ func parseGitLabSSHRemote(cleaned string) (*RemoteURLInfo, bool) {
 	}, true
 }
 
-func matchesGitLabHTTPS(cleaned string) bool {
-	return strings.Contains(cleaned, "gitlab.com/")
+func matchesGitLabHTTPS(cleaned string, hosts []string) bool {
+	_, ok := cutHostPath(cleaned, hosts)
+	return ok
 }
 
-func parseGitLabHTTPSRemote(cleaned string) (*RemoteURLInfo, bool) {
-	_, after, _ := strings.Cut(cleaned, "gitlab.com/")
+func parseGitLabHTTPSRemote(cleaned string, hosts []string) (*RemoteURLInfo, bool) {
+	after, _ := cutHostPath(cleaned, hosts)
 	parts := strings.Split(after, "/")
 	if len(parts) < 2 { //nolint:mnd // group/repo
 		return nil, false
@@ -228,6 +405,92 @@ func parseAzureDevOpsHTTPSRemote(cleaned string) (*RemoteURLInfo, bool) {
 	}, true
 }
 
+// matchesAzureDevOpsServer accepts http(s):// and ssh:// remotes on a configured
+// Azure DevOps Server host whose path contains a "_git" segment.
+func matchesAzureDevOpsServer(cleaned string, hosts []string) bool {
+	parsed, err := url.Parse(cleaned)
+	if err != nil || parsed.Host == "" || !strings.Contains(parsed.Path, "/_git/") {
+		return false
+	}
+	for _, host := range hosts {
+		hostname, _, _ := strings.Cut(host, ":")
+		if strings.EqualFold(parsed.Hostname(), hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseAzureDevOpsServerRemote(cleaned string) (*RemoteURLInfo, bool) {
+	parsed, err := url.Parse(cleaned)
+	if err != nil {
+		return nil, false
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	gitIndex, ok := findAzureDevOpsGitSegment(segments)
+	if !ok {
+		return nil, false
+	}
+	return &RemoteURLInfo{
+		ServiceType:  globalEntities.AZUREDEVOPS,
+		Organization: segments[gitIndex-2],
+		Project:      segments[gitIndex-1],
+		RepoName:     segments[gitIndex+1],
+	}, true
+}
+
+// findAzureDevOpsGitSegment returns the index of the "_git" segment of an Azure
+// DevOps Server path, which must be preceded by the collection and project and
+// followed by the repository. Anything before the collection is the server's
+// virtual directory (e.g. "tfs").
+func findAzureDevOpsGitSegment(segments []string) (int, bool) {
+	for i, segment := range segments {
+		if segment == "_git" {
+			return i, i >= 2 && i+1 < len(segments) //nolint:mnd // collection/project before _git
+		}
+	}
+	return 0, false
+}
+
+// --- Bitbucket remote URL matchers and parsers ---
+
+func matchesBitbucketSSH(cleaned string) bool {
+	return strings.HasPrefix(cleaned, "git@") && strings.Contains(cleaned, "bitbucket.org")
+}
+
+func parseBitbucketSSHRemote(cleaned string) (*RemoteURLInfo, bool) {
+	_, after, ok := strings.Cut(cleaned, ":")
+	if !ok {
+		return nil, false
+	}
+	parts := strings.Split(after, "/")
+	if len(parts) < 2 { //nolint:mnd // workspace/repo
+		return nil, false
+	}
+	return &RemoteURLInfo{
+		ServiceType:  globalEntities.BITBUCKET,
+		Organization: parts[0],
+		RepoName:     parts[1],
+	}, true
+}
+
+func matchesBitbucketHTTPS(cleaned string) bool {
+	return strings.Contains(cleaned, "bitbucket.org/")
+}
+
+func parseBitbucketHTTPSRemote(cleaned string) (*RemoteURLInfo, bool) {
+	_, after, _ := strings.Cut(cleaned, "bitbucket.org/")
+	parts := strings.Split(after, "/")
+	if len(parts) < 2 { //nolint:mnd // workspace/repo
+		return nil, false
+	}
+	return &RemoteURLInfo{
+		ServiceType:  globalEntities.BITBUCKET,
+		Organization: parts[0],
+		RepoName:     parts[1],
+	}, true
+}
+
 // --- Pull request URL parsers ---
 
 func parseGitHubPRURL(segments []string) (*PullRequestURLInfo, error) {
@@ -270,3 +533,47 @@ func parseAzureDevOpsPRURL(segments []string) (*PullRequestURLInfo, error) {
 		PRID:         prID,
 	}, nil
 }
+
+func parseAzureDevOpsServerPRURL(segments []string) (*PullRequestURLInfo, error) {
+	// Expected: [{virtual-dir}/]{collection}/{project}/_git/{repo}/pullrequest/{id}
+	gitIndex, ok := findAzureDevOpsGitSegment(segments)
+	if !ok || len(segments) < gitIndex+4 || segments[gitIndex+2] != "pullrequest" {
+		return nil, errors.New(
+			"invalid Azure DevOps Server PR URL format, expected: /{collection}/{project}/_git/{repo}/pullrequest/{id}",
+		)
+	}
+
+	prID, err := strconv.Atoi(segments[gitIndex+3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid PR ID %q: %w", segments[gitIndex+3], err)
+	}
+
+	return &PullRequestURLInfo{
+		ServiceType:  globalEntities.AZUREDEVOPS,
+		Organization: segments[gitIndex-2],
+		Project:      segments[gitIndex-1],
+		RepoName:     segments[gitIndex+1],
+		PRID:         prID,
+	}, nil
+}
+
+func parseBitbucketPRURL(segments []string) (*PullRequestURLInfo, error) {
+	// Expected: {workspace}/{repo}/pull-requests/{id}
+	if len(segments) < 4 || segments[2] != "pull-requests" {
+		return nil, errors.New(
+			"invalid Bitbucket PR URL format, expected: /{workspace}/{repo}/pull-requests/{id}",
+		)
+	}
+
+	prID, err := strconv.Atoi(segments[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid PR ID %q: %w", segments[3], err)
+	}
+
+	return &PullRequestURLInfo{
+		ServiceType:  globalEntities.BITBUCKET,
+		Organization: segments[0],
+		RepoName:     segments[1],
+		PRID:         prID,
+	}, nil
+}
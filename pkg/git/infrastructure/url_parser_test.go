@@ -80,6 +80,39 @@ func TestParseRemoteURL(t *testing.T) {
 		assert.Equal(t, "autobump", result.RepoName)
 	})
 
+	t.Run("should parse GitHub Enterprise URLs when the host is configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		opt := infrastructure.WithGitHubHosts("https://github.corp.example/")
+
+		// when
+		sshResult, sshErr := infrastructure.ParseRemoteURL("git@github.corp.example:my-org/my-repo.git", opt)
+		httpsResult, httpsErr := infrastructure.ParseRemoteURL("https://github.corp.example/my-org/my-repo.git", opt)
+
+		// then
+		require.NoError(t, sshErr)
+		require.NoError(t, httpsErr)
+		for _, result := range []*infrastructure.RemoteURLInfo{sshResult, httpsResult} {
+			assert.Equal(t, globalEntities.GITHUB, result.ServiceType)
+			assert.Equal(t, "my-org", result.Organization)
+			assert.Equal(t, "my-repo", result.RepoName)
+		}
+	})
+
+	t.Run("should reject GitHub Enterprise URLs when the host is not configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://github.corp.example/my-org/my-repo.git"
+
+		// when
+		_, err := infrastructure.ParseRemoteURL(rawURL)
+
+		// then
+		require.Error(t, err)
+	})
+
 	t.Run("should parse GitLab SSH URL", func(t *testing.T) {
 		t.Parallel()
 
@@ -145,6 +178,51 @@ func TestParseRemoteURL(t *testing.T) {
 		assert.Equal(t, "myrepo", result.RepoName)
 	})
 
+	t.Run("should parse self-managed GitLab ssh URL with custom port", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "ssh://git@git.corp.example:2222/group/sub/myrepo.git"
+
+		// when
+		result, err := infrastructure.ParseRemoteURL(rawURL, infrastructure.WithGitLabHosts("git.corp.example"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.GITLAB, result.ServiceType)
+		assert.Equal(t, "group/sub", result.Organization)
+		assert.Equal(t, "myrepo", result.RepoName)
+	})
+
+	t.Run("should parse self-managed GitLab HTTPS URL with port when the host is configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://git.corp.example:8443/group/myrepo.git"
+
+		// when
+		result, err := infrastructure.ParseRemoteURL(rawURL, infrastructure.WithGitLabHosts("https://git.corp.example"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.GITLAB, result.ServiceType)
+		assert.Equal(t, "group", result.Organization)
+		assert.Equal(t, "myrepo", result.RepoName)
+	})
+
+	t.Run("should reject self-managed GitLab URLs when the host is not configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://git.corp.example/group/myrepo.git"
+
+		// when
+		_, err := infrastructure.ParseRemoteURL(rawURL)
+
+		// then
+		require.Error(t, err)
+	})
+
 	t.Run("should parse Azure DevOps SSH URL", func(t *testing.T) {
 		t.Parallel()
 
@@ -179,6 +257,77 @@ func TestParseRemoteURL(t *testing.T) {
 		assert.Equal(t, "myrepo", result.RepoName)
 	})
 
+	t.Run("should parse Azure DevOps Server URLs when the host is configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		opt := infrastructure.WithAzureDevOpsServerHosts("https://tfs.corp/tfs")
+
+		// when
+		httpsResult, httpsErr := infrastructure.ParseRemoteURL(
+			"https://user@tfs.corp/tfs/MyCollection/myproject/_git/myrepo", opt,
+		)
+		sshResult, sshErr := infrastructure.ParseRemoteURL(
+			"ssh://tfs.corp:22/tfs/MyCollection/myproject/_git/myrepo", opt,
+		)
+
+		// then
+		require.NoError(t, httpsErr)
+		require.NoError(t, sshErr)
+		for _, result := range []*infrastructure.RemoteURLInfo{httpsResult, sshResult} {
+			assert.Equal(t, globalEntities.AZUREDEVOPS, result.ServiceType)
+			assert.Equal(t, "MyCollection", result.Organization)
+			assert.Equal(t, "myproject", result.Project)
+			assert.Equal(t, "myrepo", result.RepoName)
+		}
+	})
+
+	t.Run("should reject Azure DevOps Server URLs when the host is not configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://tfs.corp/tfs/MyCollection/myproject/_git/myrepo"
+
+		// when
+		_, err := infrastructure.ParseRemoteURL(rawURL)
+
+		// then
+		require.Error(t, err)
+	})
+
+	t.Run("should parse Bitbucket SSH URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "git@bitbucket.org:myworkspace/myrepo.git"
+
+		// when
+		result, err := infrastructure.ParseRemoteURL(rawURL)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.BITBUCKET, result.ServiceType)
+		assert.Equal(t, "myworkspace", result.Organization)
+		assert.Equal(t, "myrepo", result.RepoName)
+		assert.Empty(t, result.Project)
+	})
+
+	t.Run("should parse Bitbucket HTTPS URL with username", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://jdoe@bitbucket.org/myworkspace/myrepo.git"
+
+		// when
+		result, err := infrastructure.ParseRemoteURL(rawURL)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.BITBUCKET, result.ServiceType)
+		assert.Equal(t, "myworkspace", result.Organization)
+		assert.Equal(t, "myrepo", result.RepoName)
+	})
+
 	t.Run("should return error for empty URL", func(t *testing.T) {
 		t.Parallel()
 
@@ -197,7 +346,7 @@ func TestParseRemoteURL(t *testing.T) {
 		t.Parallel()
 
 		// given
-		rawURL := "https://bitbucket.org/owner/repo.git"
+		rawURL := "https://example.com/owner/repo.git"
 
 		// when
 		_, err := infrastructure.ParseRemoteURL(rawURL)
@@ -247,6 +396,99 @@ func TestParsePullRequestURL(t *testing.T) {
 		assert.Equal(t, 123, result.PRID)
 	})
 
+	t.Run("should parse GitHub Enterprise PR URL when the host is configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://github.corp.example/my-org/my-repo/pull/9"
+
+		// when
+		result, err := infrastructure.ParsePullRequestURL(rawURL, infrastructure.WithGitHubHosts("github.corp.example"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.GITHUB, result.ServiceType)
+		assert.Equal(t, "my-org", result.Organization)
+		assert.Equal(t, "my-repo", result.RepoName)
+		assert.Equal(t, 9, result.PRID)
+	})
+
+	t.Run("should parse Azure DevOps Server PR URL when the host is configured", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://tfs.corp:8080/tfs/MyCollection/myproject/_git/myrepo/pullrequest/77"
+
+		// when
+		result, err := infrastructure.ParsePullRequestURL(rawURL, infrastructure.WithAzureDevOpsServerHosts("tfs.corp:8080"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.AZUREDEVOPS, result.ServiceType)
+		assert.Equal(t, "MyCollection", result.Organization)
+		assert.Equal(t, "myproject", result.Project)
+		assert.Equal(t, "myrepo", result.RepoName)
+		assert.Equal(t, 77, result.PRID)
+	})
+
+	t.Run("should resolve overlapping configured hosts to the most specific one", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		opts := []infrastructure.URLParserOption{
+			infrastructure.WithGitHubHosts("git.corp"),
+			infrastructure.WithAzureDevOpsServerHosts("tfs.git.corp"),
+		}
+
+		for range 20 {
+			// when
+			server, serverErr := infrastructure.ParsePullRequestURL(
+				"https://tfs.git.corp/tfs/MyCollection/myproject/_git/myrepo/pullrequest/77", opts...,
+			)
+			enterprise, enterpriseErr := infrastructure.ParsePullRequestURL(
+				"https://git.corp/my-org/my-repo/pull/9", opts...,
+			)
+
+			// then
+			require.NoError(t, serverErr)
+			assert.Equal(t, globalEntities.AZUREDEVOPS, server.ServiceType)
+			require.NoError(t, enterpriseErr)
+			assert.Equal(t, globalEntities.GITHUB, enterprise.ServiceType)
+		}
+	})
+
+	t.Run("should not match a host that merely contains a configured one", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://notgithub.com/my-org/my-repo/pull/9"
+
+		// when
+		_, err := infrastructure.ParsePullRequestURL(rawURL)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported provider host")
+	})
+
+	t.Run("should parse Bitbucket PR URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rawURL := "https://bitbucket.org/myworkspace/myrepo/pull-requests/17"
+
+		// when
+		result, err := infrastructure.ParsePullRequestURL(rawURL)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.BITBUCKET, result.ServiceType)
+		assert.Equal(t, "myworkspace", result.Organization)
+		assert.Equal(t, "myrepo", result.RepoName)
+		assert.Equal(t, 17, result.PRID)
+		assert.Empty(t, result.Project)
+	})
+
 	t.Run("should return error for unsupported host", func(t *testing.T) {
 		t.Parallel()
 
@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+// CheckKind identifies the forge mechanism that reported a check.
+type CheckKind string
+
+const (
+	// CheckKindStatus is a commit status (GitHub, Forgejo, Bitbucket build
+	// statuses, Azure DevOps pull request statuses).
+	CheckKindStatus CheckKind = "status"
+
+	// CheckKindCheckRun is a GitHub check run, which GitHub Actions reports.
+	CheckKindCheckRun CheckKind = "check_run"
+
+	// CheckKindPolicy is a branch policy or submit requirement (Azure DevOps
+	// policy evaluations, the Gerrit Verified label).
+	CheckKindPolicy CheckKind = "policy"
+
+	// CheckKindPipeline is a CI pipeline run (the GitLab head pipeline, Azure
+	// DevOps build validation policies).
+	CheckKindPipeline CheckKind = "pipeline"
+)
+
+// CheckState is the state of a check, normalized across forges.
+type CheckState string
+
+const (
+	// CheckStatePending covers checks that are queued, running or waiting
+	// for an input such as a manual start.
+	CheckStatePending CheckState = "pending"
+
+	CheckStateSuccess CheckState = "success"
+
+	// CheckStateFailure covers failed, errored, canceled and timed out
+	// checks.
+	CheckStateFailure CheckState = "failure"
+
+	// CheckStateNeutral is a completed check that neither passed nor failed;
+	// it does not block.
+	CheckStateNeutral CheckState = "neutral"
+
+	// CheckStateSkipped is a check that did not run or does not apply; it
+	// does not block.
+	CheckStateSkipped CheckState = "skipped"
+)
+
+// Check is one CI check reported on a pull request.
+type Check struct {
+	Name     string
+	Kind     CheckKind
+	State    CheckState
+	Required bool   // the forge blocks merging until the check passes
+	URL      string // details page; empty when the forge has none
+
+	StartedAt   time.Time // zero when the forge does not report it
+	CompletedAt time.Time // zero until the check completes
+}
+
+// PullRequestChecks holds the checks of a pull request and the verdict they
+// add up to.
+type PullRequestChecks struct {
+	Checks []Check
+
+	// Verdict is CheckStatePending, CheckStateSuccess or CheckStateFailure,
+	// see NewPullRequestChecks.
+	Verdict CheckState
+}
+
+// NewPullRequestChecks aggregates checks into a verdict. When any check is
+// required only the required checks count, otherwise all of them do: the
+// verdict fails if a counted check failed, is pending while a counted check
+// is pending, and succeeds otherwise, including when there are no checks.
+func NewPullRequestChecks(checks []Check) *PullRequestChecks {
+	counted := checks
+	if slices.ContainsFunc(checks, func(c Check) bool { return c.Required }) {
+		counted = slices.DeleteFunc(slices.Clone(checks), func(c Check) bool { return !c.Required })
+	}
+
+	verdict := CheckStateSuccess
+	for _, check := range counted {
+		if check.State == CheckStateFailure {
+			return &PullRequestChecks{Checks: checks, Verdict: CheckStateFailure}
+		}
+		if check.State == CheckStatePending {
+			verdict = CheckStatePending
+		}
+	}
+	return &PullRequestChecks{Checks: checks, Verdict: verdict}
+}
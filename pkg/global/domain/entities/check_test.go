@@ -0,0 +1,71 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestNewPullRequestChecks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		checks  []entities.Check
+		verdict entities.CheckState
+	}{
+		{
+			name:    "should succeed without checks",
+			verdict: entities.CheckStateSuccess,
+		},
+		{
+			name: "should succeed when checks passed, were neutral or skipped",
+			checks: []entities.Check{
+				{Name: "build", State: entities.CheckStateSuccess},
+				{Name: "lint", State: entities.CheckStateNeutral},
+				{Name: "deploy", State: entities.CheckStateSkipped},
+			},
+			verdict: entities.CheckStateSuccess,
+		},
+		{
+			name: "should be pending while a check is pending",
+			checks: []entities.Check{
+				{Name: "build", State: entities.CheckStateSuccess},
+				{Name: "test", State: entities.CheckStatePending},
+			},
+			verdict: entities.CheckStatePending,
+		},
+		{
+			name: "should fail when a check failed even if another is pending",
+			checks: []entities.Check{
+				{Name: "test", State: entities.CheckStatePending},
+				{Name: "build", State: entities.CheckStateFailure},
+			},
+			verdict: entities.CheckStateFailure,
+		},
+		{
+			name: "should count only the required checks when some are required",
+			checks: []entities.Check{
+				{Name: "build", State: entities.CheckStateSuccess, Required: true},
+				{Name: "coverage", State: entities.CheckStateFailure},
+				{Name: "docs", State: entities.CheckStatePending},
+			},
+			verdict: entities.CheckStateSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			got := entities.NewPullRequestChecks(tt.checks)
+
+			// then
+			assert.Equal(t, tt.verdict, got.Verdict)
+			assert.Equal(t, tt.checks, got.Checks, "every check must be kept, required or not")
+		})
+	}
+}
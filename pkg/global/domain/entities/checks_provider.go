@@ -0,0 +1,18 @@
+package entities
+
+import "context"
+
+// ChecksProvider extends ReviewProvider with the individual CI checks of a
+// pull request, for callers that need more than GetPullRequestCheckStatus.
+type ChecksProvider interface {
+	ReviewProvider
+
+	// GetPullRequestChecks returns every check reported on the pull request
+	// (commit statuses, check runs, branch policies, pipelines) with its
+	// kind, state, required flag and details URL, plus the verdict they add
+	// up to, see NewPullRequestChecks. A pull request without checks has no
+	// CI configured and gets a success verdict.
+	GetPullRequestChecks(
+		ctx context.Context, repo Repository, prID int,
+	) (*PullRequestChecks, error)
+}
@@ -1,6 +1,7 @@
 package entities
 
-// File represents a file entry within a repository.
+// File represents a file entry within a repository. ObjectID is the blob ID
+// of the file, empty on forges whose listings expose none.
 type File struct {
 	Path     string
 	ObjectID string
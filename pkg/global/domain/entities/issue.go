@@ -0,0 +1,29 @@
+package entities
+
+import "time"
+
+// IssueState is the open/closed state of an issue. Azure DevOps work items
+// have process-specific states, which providers fold into these two.
+type IssueState string
+
+const (
+	IssueStateOpen   IssueState = "open"
+	IssueStateClosed IssueState = "closed"
+)
+
+// Issue is an issue, or an Azure Boards work item, returned by a provider.
+type Issue struct {
+	// ID is the number users see: the issue number on GitHub and Forgejo,
+	// the project-scoped IID on GitLab, and the work item ID on Azure DevOps.
+	ID int
+
+	Title     string
+	Body      string
+	State     IssueState
+	Labels    []string // tags on Azure DevOps
+	Assignees []string // logins, or unique names on Azure DevOps
+	Author    string
+	URL       string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// IssueComment is a comment on an issue or work item.
+type IssueComment struct {
+	// ID is the provider-specific comment identifier. Azure DevOps posts
+	// comments as history entries, so there it is the work item revision.
+	ID int64
+
+	Body      string
+	Author    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IssueLink references an issue or Azure Boards work item from a pull
+// request.
+type IssueLink struct {
+	ID int
+
+	// Closes asks for the issue to be closed when the pull request merges.
+	// Azure DevOps completes either all linked work items or none, so any
+	// closing link completes them all there.
+	Closes bool
+}
+
+var issueReferencePattern = regexp.MustCompile(
+	`(?i)\b(close[sd]?|fix(?:e[sd])?|resolve[sd]?|refs?|related to)\b:?\s+#(\d+)\b`,
+)
+
+// AppendIssueReferences appends a line per link to description, "Closes #n"
+// for closing links and "Refs #n" for the others. These are the keywords
+// GitHub, GitLab and Forgejo parse to close or relate issues. Links the
+// description already references as strongly are skipped.
+func AppendIssueReferences(description string, links []IssueLink) string {
+	existing := ParseIssueReferences(description)
+
+	var lines []string
+	for _, link := range links {
+		if slices.ContainsFunc(existing, func(e IssueLink) bool {
+			return e.ID == link.ID && (e.Closes || !link.Closes)
+		}) {
+			continue
+		}
+		keyword := "Refs"
+		if link.Closes {
+			keyword = "Closes"
+		}
+		lines = append(lines, fmt.Sprintf("%s #%d", keyword, link.ID))
+	}
+	if len(lines) == 0 {
+		return description
+	}
+
+	references := strings.Join(lines, "\n")
+	if strings.TrimSpace(description) == "" {
+		return references
+	}
+	return strings.TrimRight(description, "\n") + "\n\n" + references
+}
+
+// ParseIssueReferences returns the issues description references with a
+// closing keyword (close, fix, resolve and their inflections) or a
+// non-closing one (ref, refs, related to), in order of first mention. An
+// issue referenced both ways closes.
+func ParseIssueReferences(description string) []IssueLink {
+	var links []IssueLink
+	seen := map[int]int{} // issue ID -> index in links
+
+	for _, match := range issueReferencePattern.FindAllStringSubmatch(description, -1) {
+		id, err := strconv.Atoi(match[2])
+		if err != nil {
+			continue
+		}
+		keyword := strings.ToLower(match[1])
+		closes := !strings.HasPrefix(keyword, "ref") && keyword != "related to"
+
+		if index, ok := seen[id]; ok {
+			links[index].Closes = links[index].Closes || closes
+			continue
+		}
+		seen[id] = len(links)
+		links = append(links, IssueLink{ID: id, Closes: closes})
+	}
+	return links
+}
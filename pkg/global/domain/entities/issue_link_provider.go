@@ -0,0 +1,18 @@
+package entities
+
+import "context"
+
+// IssueLinkProvider extends ForgeProvider with links between pull requests
+// and issues or work items. Links given in PullRequestInput.IssueLinks are
+// applied the same way when the pull request is created.
+type IssueLinkProvider interface {
+	ForgeProvider
+
+	// LinkPullRequestIssues links the pull request to the issues; links it
+	// already has are kept.
+	LinkPullRequestIssues(ctx context.Context, repo Repository, prID int, links []IssueLink) error
+
+	// ListPullRequestIssues returns the issues the pull request is linked
+	// to.
+	ListPullRequestIssues(ctx context.Context, repo Repository, prID int) ([]IssueLink, error)
+}
@@ -0,0 +1,94 @@
+package entities_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestAppendIssueReferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should append a closing or reference line per link after a blank line", func(t *testing.T) {
+		t.Parallel()
+
+		// when
+		got := entities.AppendIssueReferences("Adds the feature.\n", []entities.IssueLink{
+			{ID: 41, Closes: true}, {ID: 42},
+		})
+
+		// then
+		assert.Equal(t, "Adds the feature.\n\nCloses #41\nRefs #42", got)
+	})
+
+	t.Run("should return only the references for an empty description", func(t *testing.T) {
+		t.Parallel()
+
+		// when
+		got := entities.AppendIssueReferences("", []entities.IssueLink{{ID: 41, Closes: true}})
+
+		// then
+		assert.Equal(t, "Closes #41", got)
+	})
+
+	t.Run("should skip issues the description already references as strongly", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		description := "Fixes #41 and relates to nothing else.\nRefs #42"
+
+		// when
+		got := entities.AppendIssueReferences(description, []entities.IssueLink{
+			{ID: 41}, {ID: 42, Closes: true},
+		})
+
+		// then
+		assert.Equal(t, description+"\n\nCloses #42", got)
+	})
+
+	t.Run("should return the description unchanged when nothing is missing", func(t *testing.T) {
+		t.Parallel()
+
+		// when
+		got := entities.AppendIssueReferences("Closes #41", []entities.IssueLink{{ID: 41, Closes: true}})
+
+		// then
+		assert.Equal(t, "Closes #41", got)
+	})
+}
+
+func TestParseIssueReferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return references in order of first mention", func(t *testing.T) {
+		t.Parallel()
+
+		// when
+		got := entities.ParseIssueReferences("Resolved #3. Related to #1, fix: #2 and see #9.")
+
+		// then
+		assert.Equal(t, []entities.IssueLink{{ID: 3, Closes: true}, {ID: 1}, {ID: 2, Closes: true}}, got)
+	})
+
+	t.Run("should close an issue referenced both ways", func(t *testing.T) {
+		t.Parallel()
+
+		// when
+		got := entities.ParseIssueReferences("Refs #5\nCloses #5")
+
+		// then
+		assert.Equal(t, []entities.IssueLink{{ID: 5, Closes: true}}, got)
+	})
+
+	t.Run("should return nothing without keywords", func(t *testing.T) {
+		t.Parallel()
+
+		// when
+		got := entities.ParseIssueReferences("Mentions #4 in passing.")
+
+		// then
+		assert.Empty(t, got)
+	})
+}
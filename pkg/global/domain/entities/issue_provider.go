@@ -0,0 +1,49 @@
+package entities
+
+import "context"
+
+// IssueInput contains the data needed to create an issue.
+type IssueInput struct {
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+
+	// WorkItemType is the Azure DevOps work item type to create; empty means
+	// "Task", which every stock process has. Other providers ignore it.
+	WorkItemType string
+}
+
+// IssueProvider extends ForgeProvider with issue tracking. Issues are scoped
+// to the repository's project: repo.Project on Azure DevOps, the repository
+// itself elsewhere.
+type IssueProvider interface {
+	ForgeProvider
+
+	// CreateIssue opens an issue.
+	CreateIssue(ctx context.Context, repo Repository, input IssueInput) (*Issue, error)
+
+	// GetIssue returns the issue with the given ID.
+	GetIssue(ctx context.Context, repo Repository, id int) (*Issue, error)
+
+	// UpdateIssueState closes or reopens the issue.
+	UpdateIssueState(ctx context.Context, repo Repository, id int, state IssueState) error
+
+	// AddIssueLabels adds labels to the issue, keeping the ones it has.
+	AddIssueLabels(ctx context.Context, repo Repository, id int, labels []string) error
+
+	// RemoveIssueLabels removes labels from the issue; labels it does not
+	// carry are ignored.
+	RemoveIssueLabels(ctx context.Context, repo Repository, id int, labels []string) error
+
+	// AssignIssue replaces the assignees of the issue; no assignees unassigns
+	// it. Azure DevOps work items take at most one assignee.
+	AssignIssue(ctx context.Context, repo Repository, id int, assignees []string) error
+
+	// CommentOnIssue posts a comment on the issue.
+	CommentOnIssue(ctx context.Context, repo Repository, id int, body string) (*IssueComment, error)
+
+	// SearchIssues returns the issues matching query, most recently updated
+	// first.
+	SearchIssues(ctx context.Context, repo Repository, query IssueQuery) ([]Issue, error)
+}
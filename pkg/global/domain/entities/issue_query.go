@@ -0,0 +1,12 @@
+package entities
+
+// IssueQuery filters SearchIssues. Zero-valued fields do not filter.
+type IssueQuery struct {
+	State    IssueState // empty matches both states
+	Labels   []string   // issues must carry every label
+	Assignee string
+	Text     string // matched against title and body
+
+	// Limit caps the number of results; zero returns every match.
+	Limit int
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// Mirror is a pull mirror: a repository the forge keeps in sync with an
+// upstream source. Providers fill every field their API reports and leave the
+// rest zero.
+type Mirror struct {
+	// Repository is the mirror repository itself, as DiscoverRepositories
+	// reports it.
+	Repository Repository
+
+	// SourceURL is the upstream the mirror pulls from, without credentials.
+	SourceURL string
+
+	// Interval is how often the forge syncs the mirror. Zero when periodic
+	// sync is disabled or the interval is set instance-wide (GitLab).
+	Interval time.Duration
+
+	// LastSync is when the mirror last synced successfully; zero if never or
+	// when the forge does not report it.
+	LastSync time.Time
+
+	// LastError is the error of the most recent sync attempt; empty when it
+	// succeeded or the forge does not report sync errors (Forgejo).
+	LastError string
+}
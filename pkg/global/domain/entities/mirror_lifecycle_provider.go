@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"context"
+	"time"
+)
+
+// MirrorLifecycleProvider extends MirrorProvider with the management of pull
+// mirrors that already exist. Operations the forge has no API for return an
+// error wrapping errors.ErrUnsupported.
+type MirrorLifecycleProvider interface {
+	MirrorProvider
+
+	// ListMirrors returns the pull mirrors among the repositories of org.
+	ListMirrors(ctx context.Context, org string) ([]Mirror, error)
+
+	// SyncMirror asks the forge to pull from the source now rather than at
+	// the next interval. The sync itself runs asynchronously on the forge.
+	SyncMirror(ctx context.Context, repo Repository) error
+
+	// SetMirrorInterval changes how often the forge syncs the mirror; zero
+	// disables periodic sync.
+	SetMirrorInterval(ctx context.Context, repo Repository, interval time.Duration) error
+
+	// ConvertMirror stops mirroring and turns the mirror into a normal,
+	// writable repository that keeps its current contents.
+	ConvertMirror(ctx context.Context, repo Repository) error
+}
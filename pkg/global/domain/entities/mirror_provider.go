@@ -11,6 +11,38 @@ type MirrorInput struct {
 	Description string
 	Mirror      bool   // true = create as pull mirror
 	Service     string // source service type (e.g., "github", "gitlab")
+
+	// AuthUsername and AuthPassword are the credentials used to read the source,
+	// for private sources whose CloneAddr does not embed them.
+	AuthUsername string
+	AuthPassword string
+
+	// Progress, when set, is called every time the import reports a new state,
+	// ending with MirrorStateCompleted or MirrorStateFailed.
+	Progress func(MirrorProgress)
+}
+
+// MirrorState is the stage a repository import has reached.
+type MirrorState string
+
+const (
+	MirrorStateQueued    MirrorState = "queued"
+	MirrorStateRunning   MirrorState = "running"
+	MirrorStateCompleted MirrorState = "completed"
+	MirrorStateFailed    MirrorState = "failed"
+)
+
+// MirrorProgress is one progress report of a repository import.
+type MirrorProgress struct {
+	State   MirrorState
+	Message string // provider-specific detail, e.g. the current import step or the failure reason
+}
+
+// Report passes progress to the input's Progress callback, if any.
+func (i MirrorInput) Report(state MirrorState, message string) {
+	if i.Progress != nil {
+		i.Progress(MirrorProgress{State: state, Message: message})
+	}
 }
 
 // MirrorProvider extends ForgeProvider with repository migration/mirror capabilities.
@@ -19,6 +51,7 @@ type MirrorProvider interface {
 
 	// MigrateRepository creates a repository on the target provider by migrating
 	// from a source URL. When Mirror is true, the target provider will periodically
-	// pull updates from the source.
+	// pull updates from the source. It returns once the import has completed or
+	// failed, reporting its progress through input.Progress.
 	MigrateRepository(ctx context.Context, input MirrorInput) error
 }
@@ -0,0 +1,57 @@
+package entities
+
+import "time"
+
+// PipelineStatus is the state of a pipeline run or job, normalized across
+// forges.
+type PipelineStatus string
+
+const (
+	// PipelineStatusQueued covers runs and jobs waiting for a runner, an
+	// approval or a manual start.
+	PipelineStatusQueued PipelineStatus = "queued"
+
+	PipelineStatusRunning  PipelineStatus = "running"
+	PipelineStatusSuccess  PipelineStatus = "success"
+	PipelineStatusFailure  PipelineStatus = "failure"
+	PipelineStatusCanceled PipelineStatus = "canceled"
+	PipelineStatusSkipped  PipelineStatus = "skipped"
+)
+
+// IsFinished reports whether the status is final, i.e. the run or job will
+// not change state again unless it is retried.
+func (s PipelineStatus) IsFinished() bool {
+	switch s {
+	case PipelineStatusSuccess, PipelineStatusFailure, PipelineStatusCanceled, PipelineStatusSkipped:
+		return true
+	default:
+		return false
+	}
+}
+
+// PipelineRun is one run of a CI pipeline: a GitHub Actions or Forgejo
+// Actions workflow run, a GitLab pipeline or an Azure Pipelines run.
+type PipelineRun struct {
+	// ID is the provider's identifier, as the other PipelineProvider
+	// operations expect it.
+	ID string
+
+	Pipeline  string // workflow or pipeline definition name; empty when the forge does not report it
+	Ref       string // branch or tag, without the "refs/heads/" or "refs/tags/" prefix
+	CommitSHA string
+	Status    PipelineStatus
+	URL       string // web page of the run
+
+	CreatedAt  time.Time
+	FinishedAt time.Time // zero until the run finishes
+}
+
+// PipelineJob is a job of a pipeline run.
+type PipelineJob struct {
+	ID         string
+	Name       string
+	Status     PipelineStatus
+	URL        string    // web page of the job, or of its run when the forge has none
+	StartedAt  time.Time // zero until the job starts
+	FinishedAt time.Time // zero until the job finishes
+}
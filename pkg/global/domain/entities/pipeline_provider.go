@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"context"
+	"io"
+)
+
+// PipelineRunInput contains the data needed to trigger a pipeline run.
+type PipelineRunInput struct {
+	// Pipeline names what to run: the workflow file name or ID on GitHub
+	// and Forgejo, the pipeline definition ID or name on Azure DevOps.
+	// GitLab runs the project's single pipeline configuration and ignores
+	// it.
+	Pipeline string
+
+	Ref string // branch or tag to run on; empty means the default branch
+
+	// Inputs are the workflow_dispatch inputs (GitHub, Forgejo), pipeline
+	// inputs (GitLab) or runtime parameters (Azure DevOps) of the run.
+	Inputs map[string]string
+
+	// Variables are the CI/CD variables (GitLab) or pipeline variables
+	// (Azure DevOps) set for the run. GitHub and Forgejo have no
+	// counterpart.
+	Variables map[string]string
+}
+
+// PipelineRunQuery filters ListPipelineRuns. Zero-valued fields do not filter.
+type PipelineRunQuery struct {
+	Branch    string
+	CommitSHA string
+
+	// Limit caps the number of runs, newest first; zero returns every match.
+	Limit int
+}
+
+// PipelineProvider extends ForgeProvider with CI pipeline runs. Runs are
+// addressed by the ID their PipelineRun reports. Options or operations a
+// forge cannot represent return an error wrapping errors.ErrUnsupported
+// rather than being silently dropped.
+type PipelineProvider interface {
+	ForgeProvider
+
+	// TriggerPipeline starts a run of input.Pipeline on input.Ref. GitHub
+	// does not identify the run it queues, so its ID is looked up among the
+	// newest runs and left empty when the run is not listed yet.
+	TriggerPipeline(ctx context.Context, repo Repository, input PipelineRunInput) (*PipelineRun, error)
+
+	// ListPipelineRuns returns the runs of the repository matching query,
+	// newest first.
+	ListPipelineRuns(ctx context.Context, repo Repository, query PipelineRunQuery) ([]PipelineRun, error)
+
+	// GetPipelineRun returns the run with runID.
+	GetPipelineRun(ctx context.Context, repo Repository, runID string) (*PipelineRun, error)
+
+	// ListPipelineJobs returns the jobs of the latest attempt of the run.
+	ListPipelineJobs(ctx context.Context, repo Repository, runID string) ([]PipelineJob, error)
+
+	// CancelPipelineRun asks the forge to cancel the run. Cancellation is
+	// asynchronous: the run may still be reported as running for a while.
+	CancelPipelineRun(ctx context.Context, repo Repository, runID string) error
+
+	// RetryPipelineRun runs the failed and canceled jobs of a finished run
+	// again and returns the run, which keeps its ID.
+	RetryPipelineRun(ctx context.Context, repo Repository, runID string) (*PipelineRun, error)
+
+	// GetPipelineJobLogs streams the log of the job with jobID of the run.
+	// The caller must close the reader.
+	GetPipelineJobLogs(ctx context.Context, repo Repository, runID, jobID string) (io.ReadCloser, error)
+}
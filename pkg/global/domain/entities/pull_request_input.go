@@ -7,4 +7,8 @@ type PullRequestInput struct {
 	Title        string
 	Description  string
 	AutoComplete bool
+
+	// IssueLinks links the pull request to issues or work items, see
+	// IssueLinkProvider. Providers that do not implement it ignore them.
+	IssueLinks []IssueLink
 }
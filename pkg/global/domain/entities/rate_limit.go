@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitError is returned, wrapped, when the forge throttled a request.
+// Match it with errors.As and wait RetryAfter before trying again.
+type RateLimitError struct {
+	// RetryAfter is how long the forge asked to wait; zero when it gave no
+	// hint.
+	RetryAfter time.Duration
+
+	Err error // the error the throttled request failed with
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited, retry after %s: %v", e.RetryAfter, e.Err)
+	}
+	return fmt.Sprintf("rate limited: %v", e.Err)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// ParseRetryAfter reads a Retry-After header, given either in seconds or as
+// an HTTP date, into the delay left from now. Missing or malformed values and
+// dates in the past give zero.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package entities_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "should read a delay in seconds", value: "120", expected: 2 * time.Minute},
+		{name: "should read an HTTP date", value: "Fri, 02 Jan 2026 10:00:30 GMT", expected: 30 * time.Second},
+		{name: "should give zero for a date in the past", value: "Fri, 02 Jan 2026 09:59:00 GMT", expected: 0},
+		{name: "should give zero for a negative delay", value: "-5", expected: 0},
+		{name: "should give zero for a missing header", value: "", expected: 0},
+		{name: "should give zero for a malformed header", value: "soon", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// when
+			got := entities.ParseRetryAfter(tt.value, now)
+
+			// then
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should be found through wrapping and unwrap to the cause", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		cause := errors.New("API error (status 429)")
+		err := fmt.Errorf("failed to get pull request: %w", &entities.RateLimitError{
+			RetryAfter: time.Minute, Err: cause,
+		})
+
+		// when
+		var rateLimitErr *entities.RateLimitError
+		found := errors.As(err, &rateLimitErr)
+
+		// then
+		assert.True(t, found)
+		assert.Equal(t, time.Minute, rateLimitErr.RetryAfter)
+		assert.ErrorIs(t, err, cause)
+		assert.EqualError(t, err, "failed to get pull request: rate limited, retry after 1m0s: API error (status 429)")
+	})
+}
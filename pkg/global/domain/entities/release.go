@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Release is a published (or draft) release of a repository, attached to a tag.
+type Release struct {
+	ID         string // provider-specific identifier; the tag name where the forge has none
+	TagName    string
+	Name       string
+	Body       string
+	Draft      bool
+	Prerelease bool
+	URL        string // web page of the release
+	CreatedAt  time.Time
+	Assets     []ReleaseAsset
+}
+
+// ReleaseAsset is a file attached to a release.
+type ReleaseAsset struct {
+	ID          string
+	Name        string
+	Size        int64 // zero when the forge does not report it
+	DownloadURL string
+}
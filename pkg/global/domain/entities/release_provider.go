@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"context"
+	"io"
+)
+
+// ReleaseInput contains the data needed to create or update a release.
+type ReleaseInput struct {
+	TagName string
+
+	// Target is the commit SHA or branch the tag is created on when TagName
+	// does not exist yet; empty means the default branch. Ignored on update.
+	Target string
+
+	Name       string
+	Body       string
+	Draft      bool
+	Prerelease bool
+
+	// Latest marks the release as the repository's latest release. When
+	// false the forge's own rule decides, usually the newest published
+	// release that is not a prerelease. Forges without a notion of a latest
+	// release ignore it.
+	Latest bool
+}
+
+// ReleaseAssetInput is a file to attach to a release.
+type ReleaseAssetInput struct {
+	Name        string
+	ContentType string // defaults to "application/octet-stream"
+	Content     io.Reader
+
+	// Size is the length of Content, if known. Forges that need it up front
+	// read Content into memory to measure it when it is zero.
+	Size int64
+}
+
+// ReleaseProvider extends ForgeProvider with release publishing. Releases are
+// addressed by tag name. Flags or operations a forge cannot represent return
+// an error wrapping errors.ErrUnsupported rather than being silently dropped.
+type ReleaseProvider interface {
+	ForgeProvider
+
+	// CreateRelease publishes a release for input.TagName, creating the tag
+	// on input.Target when it does not exist.
+	CreateRelease(ctx context.Context, repo Repository, input ReleaseInput) (*Release, error)
+
+	// UpdateRelease changes the name, body and flags of the release of
+	// input.TagName.
+	UpdateRelease(ctx context.Context, repo Repository, input ReleaseInput) (*Release, error)
+
+	// GetRelease returns the release of tagName, drafts included.
+	GetRelease(ctx context.Context, repo Repository, tagName string) (*Release, error)
+
+	// ListReleases returns the releases of the repository, newest first.
+	ListReleases(ctx context.Context, repo Repository) ([]Release, error)
+
+	// UploadReleaseAsset attaches a file to the release of tagName.
+	UploadReleaseAsset(
+		ctx context.Context, repo Repository, tagName string, asset ReleaseAssetInput,
+	) (*ReleaseAsset, error)
+
+	// DeleteReleaseAsset removes the asset with assetID from the release of tagName.
+	DeleteReleaseAsset(ctx context.Context, repo Repository, tagName, assetID string) error
+}
+
+// ContentTypeOrDefault returns the asset's content type, or
+// "application/octet-stream" when it is empty.
+func (a ReleaseAssetInput) ContentTypeOrDefault() string {
+	if a.ContentType == "" {
+		return "application/octet-stream"
+	}
+	return a.ContentType
+}
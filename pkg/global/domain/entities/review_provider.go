@@ -258,8 +258,8 @@ type ReviewProvider interface {
 
 	// UpdatePullRequestThreadStatus updates the status of an existing pull request thread
 	// (e.g. "fixed", "closed", "active"). The exact set of valid status strings is
-	// provider-specific. Providers that do not support thread status updates may return
-	// an error indicating the operation is unsupported.
+	// provider-specific. Providers that cannot update thread status at all return an
+	// error wrapping errors.ErrUnsupported.
 	UpdatePullRequestThreadStatus(
 		ctx context.Context, repo Repository, prID, threadID int, status string,
 	) error
@@ -272,6 +272,7 @@ type ReviewProvider interface {
 	) (string, error)
 
 	// GetPullRequestCheckStatus returns whether all CI checks/statuses have passed for a pull request.
+	// Providers that also implement ChecksProvider tell pending from failing checks and which check failed.
 	GetPullRequestCheckStatus(
 		ctx context.Context, repo Repository, prID int,
 	) (bool, error)
@@ -13,4 +13,15 @@ const (
 	// CODEBERG is intentionally excluded from ParseRemoteURL URL parsing.
 	// Providers that support Codeberg must select this ServiceType via other means.
 	CODEBERG
+	// GITEA covers self-hosted Gitea and Forgejo instances. Like CODEBERG, it is
+	// not detected by ParseRemoteURL because those hosts are not recognisable by name.
+	GITEA
+	// BITBUCKETDC covers self-hosted Bitbucket Data Center and Bitbucket Server
+	// instances, which ParseRemoteURL does not detect for the same reason.
+	BITBUCKETDC
+	// GERRIT covers Gerrit Code Review sites. It is not detected by ParseRemoteURL either.
+	GERRIT
+	// LOCAL covers the offline forge served from a directory of bare repositories
+	// on the local filesystem.
+	LOCAL
 )
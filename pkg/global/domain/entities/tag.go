@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Tag is a git tag as the forge reports it after creating it.
+type Tag struct {
+	Name      string
+	CommitSHA string // the tagged commit, also for annotated tags
+	Annotated bool
+	Message   string // empty for lightweight tags
+
+	// Date is the committer date of the tagged commit, as in LatestTag.
+	Date time.Time
+}
+
+// LatestTag returns the tag as a LatestTag, failing when its name is not a
+// semantic version.
+func (t Tag) LatestTag() (*LatestTag, error) {
+	version, err := semver.NewVersion(t.Name)
+	if err != nil {
+		return nil, fmt.Errorf("tag %q is not a semantic version: %w", t.Name, err)
+	}
+	return &LatestTag{Tag: version, Date: t.Date}, nil
+}
@@ -0,0 +1,31 @@
+package entities
+
+import "context"
+
+// TagInput contains the data needed to create a tag.
+type TagInput struct {
+	Name string
+
+	// Target is the commit SHA or branch whose head is tagged; empty means
+	// the default branch.
+	Target string
+
+	// Message makes the tag annotated; an empty message creates a
+	// lightweight tag.
+	Message string
+
+	// TaggerName and TaggerEmail record who made an annotated tag. Forges
+	// that always record the token's user return an error wrapping
+	// errors.ErrUnsupported when they are set.
+	TaggerName  string
+	TaggerEmail string
+}
+
+// TagProvider extends ForgeProvider with tag creation through the API, for
+// callers that have no local clone to tag in.
+type TagProvider interface {
+	ForgeProvider
+
+	// CreateTag creates the tag and returns it. It fails if the tag exists.
+	CreateTag(ctx context.Context, repo Repository, input TagInput) (*Tag, error)
+}
@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// WebhookEvent is a forge-neutral class of events a webhook subscribes to.
+// Each provider maps it onto its own event names.
+type WebhookEvent string
+
+const (
+	// WebhookEventPush covers branch and tag pushes.
+	WebhookEventPush WebhookEvent = "push"
+
+	// WebhookEventPullRequest covers pull requests being opened, updated,
+	// merged or closed.
+	WebhookEventPullRequest WebhookEvent = "pull_request"
+
+	// WebhookEventComment covers comments on pull requests and issues.
+	WebhookEventComment WebhookEvent = "comment"
+
+	WebhookEventIssue    WebhookEvent = "issue"
+	WebhookEventRelease  WebhookEvent = "release"
+	WebhookEventPipeline WebhookEvent = "pipeline"
+)
+
+// WebhookContentType is how a webhook encodes its payloads.
+type WebhookContentType string
+
+const (
+	WebhookContentTypeJSON WebhookContentType = "json"
+	WebhookContentTypeForm WebhookContentType = "form"
+)
+
+// Webhook is a repository webhook: a URL the forge posts events to. Secrets
+// are write-only on every forge, so they are never reported back.
+type Webhook struct {
+	// ID is the provider's identifier, as the other WebhookProvider
+	// operations expect it.
+	ID string
+
+	Repository  Repository
+	URL         string
+	Events      []WebhookEvent
+	ContentType WebhookContentType
+	Active      bool
+	CreatedAt   time.Time // zero when the forge does not report it
+}
+
+// WebhookDelivery is one attempt at posting an event to a webhook.
+type WebhookDelivery struct {
+	ID          string
+	Event       string // the forge's own event name
+	StatusCode  int    // zero when the request got no response
+	DeliveredAt time.Time
+	Redelivery  bool
+}
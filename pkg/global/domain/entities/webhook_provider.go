@@ -0,0 +1,55 @@
+package entities
+
+import "context"
+
+// WebhookInput contains the data needed to create or update a webhook.
+type WebhookInput struct {
+	URL    string
+	Events []WebhookEvent
+
+	// Secret signs (GitHub, Forgejo) or accompanies (GitLab token, Azure
+	// DevOps basic auth password) every delivery; empty sends none.
+	Secret string
+
+	ContentType WebhookContentType // defaults to WebhookContentTypeJSON
+}
+
+// WebhookProvider extends ForgeProvider with repository webhook management.
+// Events, content types or operations a forge cannot represent return an
+// error wrapping errors.ErrUnsupported rather than being silently dropped.
+type WebhookProvider interface {
+	ForgeProvider
+
+	// CreateWebhook registers a webhook on the repository. It is active
+	// right away.
+	CreateWebhook(ctx context.Context, repo Repository, input WebhookInput) (*Webhook, error)
+
+	// ListWebhooks returns the webhooks of the repository.
+	ListWebhooks(ctx context.Context, repo Repository) ([]Webhook, error)
+
+	// UpdateWebhook replaces the URL, events, secret and content type of the
+	// webhook with id.
+	UpdateWebhook(ctx context.Context, repo Repository, id string, input WebhookInput) (*Webhook, error)
+
+	// DeleteWebhook removes the webhook with id.
+	DeleteWebhook(ctx context.Context, repo Repository, id string) error
+
+	// PingWebhook asks the forge to send the webhook a test delivery.
+	PingWebhook(ctx context.Context, repo Repository, id string) error
+
+	// ListWebhookDeliveries returns the most recent deliveries of the
+	// webhook, newest first.
+	ListWebhookDeliveries(ctx context.Context, repo Repository, id string) ([]WebhookDelivery, error)
+
+	// RedeliverWebhook sends a past delivery of the webhook again.
+	RedeliverWebhook(ctx context.Context, repo Repository, id, deliveryID string) error
+}
+
+// ContentTypeOrDefault returns the webhook's content type, or
+// WebhookContentTypeJSON when it is empty.
+func (w WebhookInput) ContentTypeOrDefault() WebhookContentType {
+	if w.ContentType == "" {
+		return WebhookContentTypeJSON
+	}
+	return w.ContentType
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"cmp"
+	"strings"
+)
+
+const (
+	diffHeader    = "diff --git "
+	diffNewPrefix = "+++ b/"
+	diffOldPrefix = "--- a/"
+)
+
+// SplitDiffByFile splits a unified git diff into per-file chunks keyed by the
+// file's new path, or its old path when the file was deleted.
+func SplitDiffByFile(diff string) map[string]string {
+	patches := make(map[string]string)
+
+	var sb strings.Builder
+	flush := func() {
+		if chunk := sb.String(); chunk != "" {
+			if path := diffPath(chunk); path != "" {
+				patches[path] = chunk
+			}
+		}
+		sb.Reset()
+	}
+
+	for line := range strings.SplitAfterSeq(diff, "\n") {
+		if strings.HasPrefix(line, diffHeader) {
+			flush()
+		}
+		sb.WriteString(line)
+	}
+	flush()
+
+	return patches
+}
+
+// diffPath returns the path a per-file chunk is about. The `+++ b/` line is
+// authoritative; chunks without one (deletions, pure renames, binary and mode
+// changes) fall back to the rename target, the `--- a/` line and finally the
+// `diff --git` header. Lines after the first hunk are content, not headers.
+func diffPath(chunk string) string {
+	var header, oldPath string
+	for line := range strings.Lines(chunk) {
+		// git ends the ---/+++ lines of paths containing spaces with a tab
+		line = strings.TrimSuffix(strings.TrimRight(line, "\r\n"), "\t")
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			return cmp.Or(oldPath, headerPath(header))
+		case strings.HasPrefix(line, diffNewPrefix):
+			return line[len(diffNewPrefix):]
+		case strings.HasPrefix(line, "rename to "):
+			return line[len("rename to "):]
+		case strings.HasPrefix(line, diffOldPrefix):
+			oldPath = line[len(diffOldPrefix):]
+		case strings.HasPrefix(line, diffHeader):
+			header = line[len(diffHeader):]
+		}
+	}
+	return cmp.Or(oldPath, headerPath(header))
+}
+
+// headerPath reads the path from the `a/<old> b/<new>` part of a `diff --git`
+// header. Both paths are the same unless the file was renamed, which is how a
+// " b/" inside the path is told from the separator; renames are read from
+// their `rename to` line instead.
+func headerPath(header string) string {
+	const separator = " b/"
+	rest, ok := strings.CutPrefix(header, "a/")
+	if !ok {
+		return ""
+	}
+	if n := len(rest) - len(separator); n > 0 && n%2 == 0 {
+		half := n / 2
+		if rest[half:half+len(separator)] == separator && rest[:half] == rest[half+len(separator):] {
+			return rest[:half]
+		}
+	}
+	if idx := strings.LastIndex(rest, separator); idx >= 0 {
+		return rest[idx+len(separator):]
+	}
+	return ""
+}
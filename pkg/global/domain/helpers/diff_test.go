@@ -0,0 +1,93 @@
+package helpers_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+)
+
+func TestSplitDiffByFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should key each chunk by the path of its +++ line", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		modified := strings.Join([]string{
+			"diff --git a/docs/a b/c.md b/docs/a b/c.md",
+			"index 587be6b..23a2420 100644",
+			"--- a/docs/a b/c.md\t",
+			"+++ b/docs/a b/c.md\t",
+			"@@ -1 +1,2 @@",
+			" x",
+			"++++ b/not/a/header",
+			"",
+		}, "\n")
+		added := strings.Join([]string{
+			"diff --git a/new.go b/new.go",
+			"new file mode 100644",
+			"--- /dev/null",
+			"+++ b/new.go",
+			"@@ -0,0 +1 @@",
+			"+package main",
+			"",
+		}, "\n")
+
+		// when
+		patches := helpers.SplitDiffByFile(modified + added)
+
+		// then
+		assert.Equal(t, map[string]string{"docs/a b/c.md": modified, "new.go": added}, patches)
+	})
+
+	t.Run("should fall back to the old path, the rename target and the header", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		deleted := strings.Join([]string{
+			"diff --git a/gone.txt b/gone.txt",
+			"deleted file mode 100644",
+			"--- a/gone.txt",
+			"+++ /dev/null",
+			"@@ -1 +0,0 @@",
+			"-z",
+			"",
+		}, "\n")
+		renamed := strings.Join([]string{
+			"diff --git a/plain.txt b/moved.txt",
+			"similarity index 100%",
+			"rename from plain.txt",
+			"rename to moved.txt",
+			"",
+		}, "\n")
+		binary := strings.Join([]string{
+			"diff --git a/img/a b/logo.png b/img/a b/logo.png",
+			"index 1111111..2222222 100644",
+			"Binary files a/img/a b/logo.png and b/img/a b/logo.png differ",
+			"",
+		}, "\n")
+
+		// when
+		patches := helpers.SplitDiffByFile(deleted + renamed + binary)
+
+		// then
+		assert.Equal(t, map[string]string{
+			"gone.txt":         deleted,
+			"moved.txt":        renamed,
+			"img/a b/logo.png": binary,
+		}, patches)
+	})
+
+	t.Run("should return no chunks for an empty diff", func(t *testing.T) {
+		t.Parallel()
+
+		// when
+		patches := helpers.SplitDiffByFile("")
+
+		// then
+		assert.Empty(t, patches)
+	})
+}
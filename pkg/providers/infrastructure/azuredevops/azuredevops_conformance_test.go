@@ -0,0 +1,33 @@
+package azuredevops_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/azuredevops"
+	"github.com/rios0rios0/gitforge/test/conformance"
+	"github.com/rios0rios0/gitforge/test/fakes"
+)
+
+func TestProviderConformance(t *testing.T) {
+	t.Parallel()
+
+	conformance.Run(t, conformance.Harness{
+		NewBackend: func(t *testing.T) conformance.Backend {
+			return fakes.NewAzureDevOpsServer(t)
+		},
+		NewProvider: func(t *testing.T, backend conformance.Backend) globalEntities.ForgeProvider {
+			provider, err := azuredevops.NewServerProvider(
+				"token", backend.(*fakes.AzureDevOpsServer).URL(), "", "",
+			)
+			require.NoError(t, err)
+			return provider
+		},
+		Skip: map[string]string{
+			"FileAccessProvider/should reject unsupported change types": "CreateBranchWithChanges " +
+				"forwards change types to the pushes API unchecked",
+		},
+	})
+}
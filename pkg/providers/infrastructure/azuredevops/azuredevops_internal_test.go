@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -615,6 +616,27 @@ func TestDoRequest(t *testing.T) {
 		assert.Contains(t, err.Error(), "API error")
 	})
 
+	t.Run("should return a rate limit error with the Retry-After delay when throttled", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		_, err := p.doRequest(context.Background(), "https://dev.azure.com/org", http.MethodGet, "/test", nil)
+
+		// then
+		var rateLimitErr *globalEntities.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, 30*time.Second, rateLimitErr.RetryAfter)
+	})
+
 	t.Run("should send request body for POST", func(t *testing.T) {
 		t.Parallel()
 
@@ -1266,6 +1288,74 @@ func TestGetPullRequestStatus(t *testing.T) {
 	})
 }
 
+func TestGetPullRequestChecks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should map policy evaluations and keep the latest status per context", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var artifactID string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath, repositoryRefHandler)
+		mux.HandleFunc("GET /my-org/my-project/_apis/policy/evaluations", func(w http.ResponseWriter, r *http.Request) {
+			artifactID = r.URL.Query().Get("artifactId")
+			_, _ = w.Write([]byte(`{"value":[
+				{"status":"running","startedDate":"2026-01-02T10:00:00Z","context":{"buildId":301},
+					"configuration":{"isEnabled":true,"isBlocking":true,
+						"type":{"id":"0609b952-1397-4640-95ec-e00a01b2c241","displayName":"Build"},
+						"settings":{"displayName":"CI"}}},
+				{"status":"approved","configuration":{"isEnabled":true,"isBlocking":false,
+					"type":{"id":"fa4e907d-c16b-4a4c-9dfa-4906e5d171dd","displayName":"Minimum number of reviewers"}}},
+				{"status":"rejected","configuration":{"isEnabled":false,"isBlocking":true,
+					"type":{"id":"fa4e907d-c16b-4a4c-9dfa-4906e5d171dd","displayName":"Disabled"}}}]}`))
+		})
+		mux.HandleFunc("GET "+testReposPath+"/pullrequests/12/statuses", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[
+				{"id":1,"state":"pending","context":{"genre":"sonar","name":"quality"}},
+				{"id":2,"state":"failed","targetUrl":"https://sonar.example.com/1",
+					"updatedDate":"2026-01-02T10:07:00Z","context":{"genre":"sonar","name":"quality"}}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		checks, err := p.GetPullRequestChecks(context.Background(), repo, 12)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "vstfs:///CodeReview/CodeReviewId/project-guid/12", artifactID)
+		assert.Equal(t, &globalEntities.PullRequestChecks{
+			Checks: []globalEntities.Check{
+				{
+					Name:      "CI",
+					Kind:      globalEntities.CheckKindPipeline,
+					State:     globalEntities.CheckStatePending,
+					Required:  true,
+					URL:       "https://dev.azure.com/my-org/my-project/_build/results?buildId=301",
+					StartedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+				},
+				{
+					Name:  "Minimum number of reviewers",
+					Kind:  globalEntities.CheckKindPolicy,
+					State: globalEntities.CheckStateSuccess,
+				},
+				{
+					Name:        "sonar/quality",
+					Kind:        globalEntities.CheckKindStatus,
+					State:       globalEntities.CheckStateFailure,
+					URL:         "https://sonar.example.com/1",
+					CompletedAt: time.Date(2026, 1, 2, 10, 7, 0, 0, time.UTC),
+				},
+			},
+			Verdict: globalEntities.CheckStatePending,
+		}, checks)
+	})
+}
+
 // commentStatusOptionCase describes one row of the WithThreadStatus
 // option table reused by the two PostPullRequest* status tests below.
 // Defined as a package-level type (not inline in each test func) so the
@@ -2296,3 +2386,110 @@ func TestReplyToThread(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestServerProviderInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should discover repositories under the collection with the server api-version", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var apiVersions []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /tfs/MyCollection/_apis/projects", func(w http.ResponseWriter, r *http.Request) {
+			apiVersions = append(apiVersions, r.URL.Query().Get("api-version"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"value":[{"id":"proj-1","name":"ProjectA"}]}`))
+		})
+		mux.HandleFunc("GET /tfs/MyCollection/proj-1/_apis/git/repositories", func(w http.ResponseWriter, r *http.Request) {
+			apiVersions = append(apiVersions, r.URL.Query().Get("api-version"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"value":[{"id":"repo-1","name":"RepoA",` +
+				`"remoteUrl":"https://tfs.corp/tfs/MyCollection/ProjectA/_git/RepoA"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		provider, err := NewServerProvider("test-token", server.URL+"/tfs/", "MyCollection", APIVersionServer2019)
+		require.NoError(t, err)
+
+		// when
+		repos, err := provider.DiscoverRepositories(context.Background(), "MyCollection")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, repos, 1)
+		assert.Equal(t, "MyCollection", repos[0].Organization)
+		assert.Equal(t, "ProjectA", repos[0].Project)
+		assert.Equal(t, []string{"5.0", "5.0"}, apiVersions)
+	})
+
+	t.Run("should create pull requests in the default collection when the organization is empty", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var requestedPath string
+		mux := http.NewServeMux()
+		mux.HandleFunc(
+			"POST /tfs/DefaultCollection/ProjectA/_apis/git/repositories/repo-1/pullrequests",
+			func(w http.ResponseWriter, r *http.Request) {
+				requestedPath = r.URL.Path
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"pullRequestId":7,"title":"t","status":"active"}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		provider, err := NewServerProvider("test-token", server.URL+"/tfs", "", "")
+		require.NoError(t, err)
+		repo := globalEntities.Repository{ID: "repo-1", Project: "ProjectA"}
+
+		// when
+		pr, err := provider.CreatePullRequest(context.Background(), repo, globalEntities.PullRequestInput{
+			SourceBranch: "feature",
+			TargetBranch: "main",
+			Title:        "t",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 7, pr.ID)
+		assert.Equal(t, "/tfs/DefaultCollection/ProjectA/_apis/git/repositories/repo-1/pullrequests", requestedPath)
+	})
+
+	t.Run("should use the server api-version with the preview suffix on connectionData", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var capturedAPIVersion string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /tfs/MyCollection/_apis/connectionData", func(w http.ResponseWriter, r *http.Request) {
+			capturedAPIVersion = r.URL.Query().Get("api-version")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"authenticatedUser":{"id":"` + submitReviewerID + `"}}`))
+		})
+		mux.HandleFunc(
+			"PUT /tfs/MyCollection/ProjectA/_apis/git/repositories/repo-1/pullrequests/3/reviewers/"+submitReviewerID,
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		provider, err := NewServerProvider("test-token", server.URL+"/tfs", "MyCollection", APIVersionServer2020)
+		require.NoError(t, err)
+		reviewer, ok := provider.(globalEntities.ReviewProvider)
+		require.True(t, ok)
+		repo := globalEntities.Repository{Organization: "MyCollection", Project: "ProjectA", ID: "repo-1"}
+
+		// when
+		err = reviewer.SubmitPullRequestReview(context.Background(), repo, 3,
+			globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictApprove})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "6.0-preview.1", capturedAPIVersion)
+	})
+}
@@ -16,13 +16,13 @@ import (
 )
 
 const (
-	providerName     = "azuredevops"
-	apiVersion       = "7.0"
-	httpTimeout      = 30 * time.Second
-	httpStatusOKMin  = 200
-	httpStatusOKMax  = 300
-	paginationHeader = "X-Ms-Continuationtoken"
-	allZeroObjectID  = "0000000000000000000000000000000000000000"
+	providerName      = "azuredevops"
+	defaultAPIVersion = "7.0"
+	httpTimeout       = 30 * time.Second
+	httpStatusOKMin   = 200
+	httpStatusOKMax   = 300
+	paginationHeader  = "X-Ms-Continuationtoken"
+	allZeroObjectID   = "0000000000000000000000000000000000000000"
 
 	// httpStatusNonAuthoritative is the status Azure DevOps serves its sign-in
 	// page with. It sits inside the 2xx success window but never carries an API
@@ -32,32 +32,60 @@ const (
 	httpStatusRedirectMax      = 400
 
 	// JSON payload keys reused across multiple API requests.
-	jsonKeyItem            = "item"
-	jsonKeyPath            = "path"
-	jsonKeyContent         = "content"
-	jsonKeyName            = "name"
-	jsonKeySourceRefName   = "sourceRefName"
-	jsonKeyTargetRefName   = "targetRefName"
-	jsonKeyTitle           = "title"
-	jsonKeyComments        = "comments"
-	jsonKeyParentCommentID = "parentCommentId"
-	jsonKeyCommentType     = "commentType"
-	jsonKeyStatus          = "status"
-	jsonKeyFilePath        = "filePath"
-	jsonKeyLine            = "line"
+	jsonKeyItem                = "item"
+	jsonKeyPath                = "path"
+	jsonKeyContent             = "content"
+	jsonKeyName                = "name"
+	jsonKeySourceRefName       = "sourceRefName"
+	jsonKeyTargetRefName       = "targetRefName"
+	jsonKeyTitle               = "title"
+	jsonKeyComments            = "comments"
+	jsonKeyParentCommentID     = "parentCommentId"
+	jsonKeyCommentType         = "commentType"
+	jsonKeyStatus              = "status"
+	jsonKeyCompletionOptions   = "completionOptions"
+	jsonKeyTransitionWorkItems = "transitionWorkItems"
+	jsonKeyFilePath            = "filePath"
+	jsonKeyLine                = "line"
 
 	// prStatusAbandoned is the pull request status that closes a PR on Azure DevOps.
 	prStatusAbandoned = "abandoned"
 
 	// logFieldPRID is the structured-log field name for the pull request ID.
 	logFieldPRID = "prID"
+
+	// defaultCollection is the collection Azure DevOps Server creates on install.
+	defaultCollection = "DefaultCollection"
+	defaultSSHPort    = 22
+
+	// defaultImportPollInterval is how often MigrateRepository polls an import request.
+	defaultImportPollInterval = 2 * time.Second
+)
+
+// REST api-versions of the Azure DevOps Server releases, for use with NewServerProvider.
+// Each release rejects versions newer than the one it ships with.
+const (
+	APIVersionServer2019 = "5.0"
+	APIVersionServer2020 = "6.0"
+	APIVersionServer2022 = "7.0"
 )
 
-// Provider implements ForgeProvider, FileAccessProvider, and LocalGitAuthProvider for Azure DevOps.
+// Provider implements ForgeProvider, FileAccessProvider, ReviewProvider, ChecksProvider, LocalGitAuthProvider,
+// MirrorProvider, ReleaseProvider, TagProvider, IssueProvider, IssueLinkProvider, WebhookProvider, and
+// PipelineProvider for Azure DevOps (releases are annotated tags, issues are Azure Boards work items, webhooks are
+// service hook subscriptions, pipeline runs are Azure Pipelines builds).
 type Provider struct {
 	token      string
 	httpClient *http.Client
 
+	// serverURL is the Azure DevOps Server root including its virtual directory
+	// (e.g. "https://tfs.corp/tfs"); empty means Azure DevOps Services.
+	serverURL  string
+	collection string // collection used when a repository has no Organization
+	version    string // REST api-version; empty means defaultAPIVersion
+
+	importPollInterval time.Duration // 0 means defaultImportPollInterval
+
 	// reviewerIDMu guards reviewerIDCache and reviewerIDOnces so concurrent
 	// callers from different organizations can each lazily resolve their own
 	// reviewer ID. The cache is keyed by organization because the bot's
@@ -73,6 +101,32 @@ type Provider struct {
 
 // NewProvider creates a new Azure DevOps provider with the given PAT.
 func NewProvider(token string) globalEntities.ForgeProvider {
+	return newProvider(token)
+}
+
+// NewServerProvider creates an Azure DevOps provider for an on-premises Azure DevOps
+// Server (formerly TFS). serverURL is the server root including its virtual directory
+// (e.g. "https://tfs.corp/tfs") and collection plays the role of the organization,
+// defaulting to "DefaultCollection". version is the REST api-version the server
+// supports (see APIVersionServer2019 and friends); empty means the Services default.
+func NewServerProvider(token, serverURL, collection, version string) (globalEntities.ForgeProvider, error) {
+	serverURL = strings.TrimSuffix(serverURL, "/")
+	parsed, err := url.Parse(serverURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Azure DevOps Server URL %q", serverURL)
+	}
+	if collection == "" {
+		collection = defaultCollection
+	}
+
+	provider := newProvider(token)
+	provider.serverURL = serverURL
+	provider.collection = collection
+	provider.version = version
+	return provider, nil
+}
+
+func newProvider(token string) *Provider {
 	return &Provider{
 		token: token,
 		httpClient: &http.Client{
@@ -96,7 +150,10 @@ func (p *Provider) Name() string      { return providerName }
 func (p *Provider) AuthToken() string { return p.token }
 
 func (p *Provider) MatchesURL(rawURL string) bool {
-	return strings.Contains(rawURL, "dev.azure.com")
+	if p.serverURL == "" {
+		return strings.Contains(rawURL, "dev.azure.com")
+	}
+	return strings.Contains(rawURL, p.serverHostname())
 }
 
 // --- LocalGitAuthProvider ---
@@ -131,6 +188,12 @@ func (p *Provider) GetAuthMethods(_ string) []transport.AuthMethod {
 
 func (p *Provider) CloneURL(repo globalEntities.Repository) string {
 	remoteURL := repo.RemoteURL
+	if remoteURL == "" && p.serverURL != "" {
+		remoteURL = fmt.Sprintf(
+			"%s/%s/_git/%s",
+			p.orgBaseURL(repo.Organization), repo.Project, repo.Name,
+		)
+	}
 	if remoteURL == "" {
 		remoteURL = fmt.Sprintf(
 			"https://dev.azure.com/%s/%s/_git/%s",
@@ -148,7 +211,21 @@ func (p *Provider) CloneURL(repo globalEntities.Repository) string {
 	return parsed.String()
 }
 
+// SSHCloneURL returns the Services "v3" SSH URL, or for Azure DevOps Server the
+// ssh:// URL that mirrors the HTTP path of the repository.
 func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
+	if p.serverURL != "" {
+		host := p.serverHostname()
+		if sshAlias != "" {
+			host = fmt.Sprintf("%s-%s", host, sshAlias)
+		}
+		return fmt.Sprintf(
+			"ssh://%s:%d%s/%s/_git/%s",
+			host, defaultSSHPort, strings.TrimPrefix(p.orgBaseURL(repo.Organization), p.serverRoot()),
+			repo.Project, repo.Name,
+		)
+	}
+
 	host := "ssh.dev.azure.com"
 	if sshAlias != "" {
 		host = fmt.Sprintf("dev.azure.com-%s", sshAlias)
@@ -26,7 +26,7 @@ func (p *Provider) DiscoverRepositories(
 	ctx context.Context,
 	org string,
 ) ([]globalEntities.Repository, error) {
-	baseURL := normalizeOrgURL(org)
+	baseURL := p.orgURL(org)
 
 	projects, err := p.getProjects(ctx, baseURL)
 	if err != nil {
@@ -43,7 +43,7 @@ func (p *Provider) DiscoverRepositories(
 			repos = append(repos, globalEntities.Repository{
 				ID:            r.ID,
 				Name:          r.Name,
-				Organization:  extractOrgName(baseURL),
+				Organization:  p.orgName(baseURL),
 				Project:       proj.Name,
 				DefaultBranch: r.DefaultBranch,
 				RemoteURL:     r.RemoteURL,
@@ -64,7 +64,7 @@ func (p *Provider) getProjects(
 	continuationToken := ""
 
 	for {
-		endpoint := "/_apis/projects?api-version=" + apiVersion
+		endpoint := "/_apis/projects?api-version=" + p.apiVersion()
 		if continuationToken != "" {
 			endpoint += "&continuationToken=" + continuationToken
 		}
@@ -99,7 +99,7 @@ func (p *Provider) getRepositories(
 ) ([]adoRepository, error) {
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories?api-version=%s",
-		projectID, apiVersion,
+		projectID, p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -19,10 +19,10 @@ func (p *Provider) GetFileContent(
 	repo globalEntities.Repository,
 	path string,
 ) (string, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/items?path=%s&api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), url.QueryEscape(path), apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), url.QueryEscape(path), p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -38,10 +38,10 @@ func (p *Provider) ListFiles(
 	repo globalEntities.Repository,
 	pattern string,
 ) ([]globalEntities.File, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/items?recursionLevel=Full&api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -80,10 +80,10 @@ func (p *Provider) GetTags(
 	ctx context.Context,
 	repo globalEntities.Repository,
 ) ([]string, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/refs?filter=tags&api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -123,7 +123,7 @@ func (p *Provider) CreateBranchWithChanges(
 	repo globalEntities.Repository,
 	input globalEntities.BranchInput,
 ) error {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 
 	baseCommitID, err := p.getCommitID(ctx, baseURL, repo)
 	if err != nil {
@@ -163,7 +163,7 @@ func (p *Provider) CreateBranchWithChanges(
 
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pushes?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
 	)
 
 	_, err = p.doRequest(ctx, baseURL, http.MethodPost, endpoint, pushBody)
@@ -181,7 +181,7 @@ func (p *Provider) getCommitID(
 ) (string, error) {
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -199,7 +199,7 @@ func (p *Provider) getCommitID(
 	branchName := strings.TrimPrefix(repoInfo.DefaultBranch, "refs/heads/")
 	branchEndpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/refs?filter=heads/%s&api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), branchName, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), branchName, p.apiVersion(),
 	)
 
 	branchResp, branchErr := p.doRequest(
@@ -10,6 +10,9 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
 )
 
 // ErrAuthentication indicates Azure DevOps rejected the personal access token.
@@ -64,6 +67,27 @@ func (p *Provider) doRequestWithHeaders(
 	ctx context.Context,
 	baseURL, method, endpoint string,
 	body any,
+) ([]byte, http.Header, error) {
+	return p.doRequestWithContentType(ctx, baseURL, method, endpoint, "application/json", body)
+}
+
+// doJSONPatchRequest sends operations as a JSON Patch document, the body the
+// work item endpoints take for creates and updates.
+func (p *Provider) doJSONPatchRequest(
+	ctx context.Context,
+	baseURL, method, endpoint string,
+	operations []adoPatchOperation,
+) ([]byte, error) {
+	resp, _, err := p.doRequestWithContentType(
+		ctx, baseURL, method, endpoint, "application/json-patch+json", operations,
+	)
+	return resp, err
+}
+
+func (p *Provider) doRequestWithContentType(
+	ctx context.Context,
+	baseURL, method, endpoint, contentType string,
+	body any,
 ) ([]byte, http.Header, error) {
 	var reqBody io.Reader
 	if body != nil {
@@ -82,7 +106,7 @@ func (p *Provider) doRequestWithHeaders(
 
 	auth := base64.StdEncoding.EncodeToString([]byte(":" + p.token))
 	req.Header.Set("Authorization", "Basic "+auth)
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
@@ -107,10 +131,15 @@ func (p *Provider) doRequestWithHeaders(
 	}
 
 	if resp.StatusCode < httpStatusOKMin || resp.StatusCode >= httpStatusOKMax {
-		return nil, nil, fmt.Errorf(
-			"API error (status %d): %s",
-			resp.StatusCode, string(respBody),
-		)
+		apiErr := fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+		// throttled requests come back with a delay to honor in Retry-After
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, nil, &globalEntities.RateLimitError{
+				RetryAfter: globalEntities.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+				Err:        apiErr,
+			}
+		}
+		return nil, nil, apiErr
 	}
 
 	return respBody, resp.Header, nil
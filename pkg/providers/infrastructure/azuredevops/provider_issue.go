@@ -0,0 +1,483 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	defaultWorkItemType = "Task"
+	workItemsBatchSize  = 200
+	tagSeparator        = "; "
+
+	fieldTitle        = "/fields/System.Title"
+	fieldDescription  = "/fields/System.Description"
+	fieldState        = "/fields/System.State"
+	fieldTags         = "/fields/System.Tags"
+	fieldAssignedTo   = "/fields/System.AssignedTo"
+	fieldHistory      = "/fields/System.History"
+	patchOperationAdd = "add"
+)
+
+var errNoStateInCategory = errors.New("work item type has no state in category")
+
+// closedWorkItemStates are the states of the stock processes (Basic, Agile,
+// Scrum and CMMI) that count as closed. Work items report their state by name
+// only, so a custom process's closed states read as open.
+var closedWorkItemStates = []string{"Closed", "Done", "Resolved", "Removed", "Completed"}
+
+// adoPatchOperation is a JSON Patch operation on a work item field.
+type adoPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+type adoIdentityRef struct {
+	DisplayName string `json:"displayName"`
+	UniqueName  string `json:"uniqueName"`
+}
+
+type adoWorkItem struct {
+	ID     int `json:"id"`
+	Rev    int `json:"rev"`
+	Fields struct {
+		WorkItemType string          `json:"System.WorkItemType"`
+		Title        string          `json:"System.Title"`
+		Description  string          `json:"System.Description"`
+		State        string          `json:"System.State"`
+		Tags         string          `json:"System.Tags"`
+		AssignedTo   *adoIdentityRef `json:"System.AssignedTo"`
+		CreatedBy    *adoIdentityRef `json:"System.CreatedBy"`
+		ChangedBy    *adoIdentityRef `json:"System.ChangedBy"`
+		CreatedDate  time.Time       `json:"System.CreatedDate"`
+		ChangedDate  time.Time       `json:"System.ChangedDate"`
+	} `json:"fields"`
+}
+
+// CreateIssue creates a work item of input.WorkItemType (a Task by default)
+// in repo.Project. Work items take a single assignee and an HTML
+// description.
+func (p *Provider) CreateIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.IssueInput,
+) (*globalEntities.Issue, error) {
+	if len(input.Assignees) > 1 {
+		return nil, fmt.Errorf("Azure DevOps work items take one assignee: %w", errors.ErrUnsupported)
+	}
+
+	workItemType := input.WorkItemType
+	if workItemType == "" {
+		workItemType = defaultWorkItemType
+	}
+
+	operations := []adoPatchOperation{
+		{Op: patchOperationAdd, Path: fieldTitle, Value: input.Title},
+		{Op: patchOperationAdd, Path: fieldDescription, Value: input.Body},
+	}
+	if len(input.Labels) > 0 {
+		operations = append(operations, adoPatchOperation{
+			Op: patchOperationAdd, Path: fieldTags, Value: strings.Join(input.Labels, tagSeparator),
+		})
+	}
+	if len(input.Assignees) == 1 {
+		operations = append(operations, adoPatchOperation{
+			Op: patchOperationAdd, Path: fieldAssignedTo, Value: input.Assignees[0],
+		})
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/wit/workitems/$%s?api-version=%s",
+		repo.Project, url.PathEscape(workItemType), p.apiVersion(),
+	)
+	resp, err := p.doJSONPatchRequest(ctx, baseURL, http.MethodPost, endpoint, operations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create work item: %w", err)
+	}
+
+	workItem, err := parseWorkItem(resp)
+	if err != nil {
+		return nil, err
+	}
+	return p.workItemToIssue(repo, *workItem), nil
+}
+
+func (p *Provider) GetIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+) (*globalEntities.Issue, error) {
+	workItem, err := p.getWorkItem(ctx, repo, id)
+	if err != nil {
+		return nil, err
+	}
+	return p.workItemToIssue(repo, *workItem), nil
+}
+
+// UpdateIssueState moves the work item to the first state of its type in the
+// Completed category to close it, or in the Proposed category to reopen it.
+func (p *Provider) UpdateIssueState(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	state globalEntities.IssueState,
+) error {
+	workItem, err := p.getWorkItem(ctx, repo, id)
+	if err != nil {
+		return err
+	}
+
+	category := "Proposed"
+	if state == globalEntities.IssueStateClosed {
+		category = "Completed"
+	}
+	stateName, err := p.findWorkItemState(ctx, repo, workItem.Fields.WorkItemType, category)
+	if err != nil {
+		return fmt.Errorf("failed to set work item #%d %s: %w", id, state, err)
+	}
+
+	operations := []adoPatchOperation{{Op: patchOperationAdd, Path: fieldState, Value: stateName}}
+	if _, err = p.updateWorkItem(ctx, repo, id, operations); err != nil {
+		return fmt.Errorf("failed to set work item #%d %s: %w", id, state, err)
+	}
+	return nil
+}
+
+// AddIssueLabels adds tags to the work item. Tags are one field, so it is
+// read and written back whole.
+func (p *Provider) AddIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	workItem, err := p.getWorkItem(ctx, repo, id)
+	if err != nil {
+		return err
+	}
+
+	tags := splitWorkItemTags(workItem.Fields.Tags)
+	for _, label := range labels {
+		if !slices.Contains(tags, label) {
+			tags = append(tags, label)
+		}
+	}
+
+	operations := []adoPatchOperation{
+		{Op: patchOperationAdd, Path: fieldTags, Value: strings.Join(tags, tagSeparator)},
+	}
+	if _, err = p.updateWorkItem(ctx, repo, id, operations); err != nil {
+		return fmt.Errorf("failed to tag work item #%d: %w", id, err)
+	}
+	return nil
+}
+
+func (p *Provider) RemoveIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	workItem, err := p.getWorkItem(ctx, repo, id)
+	if err != nil {
+		return err
+	}
+
+	tags := slices.DeleteFunc(splitWorkItemTags(workItem.Fields.Tags), func(tag string) bool {
+		return slices.Contains(labels, tag)
+	})
+
+	operations := []adoPatchOperation{
+		{Op: patchOperationAdd, Path: fieldTags, Value: strings.Join(tags, tagSeparator)},
+	}
+	if _, err = p.updateWorkItem(ctx, repo, id, operations); err != nil {
+		return fmt.Errorf("failed to untag work item #%d: %w", id, err)
+	}
+	return nil
+}
+
+// AssignIssue sets the work item's assignee, a unique name such as an email
+// address. No assignees clears it.
+func (p *Provider) AssignIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	assignees []string,
+) error {
+	if len(assignees) > 1 {
+		return fmt.Errorf("Azure DevOps work items take one assignee: %w", errors.ErrUnsupported)
+	}
+
+	assignee := ""
+	if len(assignees) == 1 {
+		assignee = assignees[0]
+	}
+	operations := []adoPatchOperation{{Op: patchOperationAdd, Path: fieldAssignedTo, Value: assignee}}
+	if _, err := p.updateWorkItem(ctx, repo, id, operations); err != nil {
+		return fmt.Errorf("failed to assign work item #%d: %w", id, err)
+	}
+	return nil
+}
+
+// CommentOnIssue adds body to the work item's discussion through its history
+// field; the comment's ID is the revision that recorded it.
+func (p *Provider) CommentOnIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	body string,
+) (*globalEntities.IssueComment, error) {
+	operations := []adoPatchOperation{{Op: patchOperationAdd, Path: fieldHistory, Value: body}}
+	workItem, err := p.updateWorkItem(ctx, repo, id, operations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to comment on work item #%d: %w", id, err)
+	}
+
+	comment := &globalEntities.IssueComment{
+		ID:        int64(workItem.Rev),
+		Body:      body,
+		CreatedAt: workItem.Fields.ChangedDate,
+	}
+	if workItem.Fields.ChangedBy != nil {
+		comment.Author = workItem.Fields.ChangedBy.UniqueName
+	}
+	return comment, nil
+}
+
+// SearchIssues runs a WIQL query over the work items of repo.Project, of
+// every type, and fetches the matches in batches.
+func (p *Provider) SearchIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.IssueQuery,
+) ([]globalEntities.Issue, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf("/%s/_apis/wit/wiql?api-version=%s", repo.Project, p.apiVersion())
+	if query.Limit > 0 {
+		endpoint += "&$top=" + strconv.Itoa(query.Limit)
+	}
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, map[string]string{
+		"query": buildWIQLQuery(query),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search work items: %w", err)
+	}
+
+	var result struct {
+		WorkItems []struct {
+			ID int `json:"id"`
+		} `json:"workItems"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse WIQL response: %w", unmarshalErr)
+	}
+
+	ids := make([]string, 0, len(result.WorkItems))
+	for _, ref := range result.WorkItems {
+		ids = append(ids, strconv.Itoa(ref.ID))
+	}
+
+	var issues []globalEntities.Issue
+	for batch := range slices.Chunk(ids, workItemsBatchSize) {
+		workItems, batchErr := p.getWorkItems(ctx, baseURL, repo, batch)
+		if batchErr != nil {
+			return nil, batchErr
+		}
+		for _, workItem := range workItems {
+			issues = append(issues, *p.workItemToIssue(repo, workItem))
+		}
+	}
+
+	// the batch endpoint does not promise to keep the order WIQL sorted by
+	slices.SortStableFunc(issues, func(a, b globalEntities.Issue) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	return issues, nil
+}
+
+func (p *Provider) getWorkItem(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+) (*adoWorkItem, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf("/%s/_apis/wit/workitems/%d?api-version=%s", repo.Project, id, p.apiVersion())
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work item #%d: %w", id, err)
+	}
+	return parseWorkItem(resp)
+}
+
+// getWorkItems fetches the work items of ids, at most workItemsBatchSize.
+func (p *Provider) getWorkItems(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	ids []string,
+) ([]adoWorkItem, error) {
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/wit/workitems?ids=%s&api-version=%s",
+		repo.Project, strings.Join(ids, ","), p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work items: %w", err)
+	}
+
+	var result struct {
+		Value []adoWorkItem `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse work items response: %w", unmarshalErr)
+	}
+	return result.Value, nil
+}
+
+func (p *Provider) updateWorkItem(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	operations []adoPatchOperation,
+) (*adoWorkItem, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf("/%s/_apis/wit/workitems/%d?api-version=%s", repo.Project, id, p.apiVersion())
+
+	resp, err := p.doJSONPatchRequest(ctx, baseURL, http.MethodPatch, endpoint, operations)
+	if err != nil {
+		return nil, err
+	}
+	return parseWorkItem(resp)
+}
+
+// findWorkItemState returns the first state of workItemType in category.
+func (p *Provider) findWorkItemState(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	workItemType, category string,
+) (string, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/wit/workitemtypes/%s/states?api-version=%s",
+		repo.Project, url.PathEscape(workItemType), p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list %s states: %w", workItemType, err)
+	}
+
+	var result struct {
+		Value []struct {
+			Name     string `json:"name"`
+			Category string `json:"category"`
+		} `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return "", fmt.Errorf("failed to parse states response: %w", unmarshalErr)
+	}
+
+	for _, state := range result.Value {
+		if state.Category == category {
+			return state.Name, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s has none in %s", errNoStateInCategory, workItemType, category)
+}
+
+func (p *Provider) workItemToIssue(
+	repo globalEntities.Repository,
+	workItem adoWorkItem,
+) *globalEntities.Issue {
+	issue := &globalEntities.Issue{
+		ID:        workItem.ID,
+		Title:     workItem.Fields.Title,
+		Body:      workItem.Fields.Description,
+		State:     globalEntities.IssueStateOpen,
+		Labels:    splitWorkItemTags(workItem.Fields.Tags),
+		URL:       fmt.Sprintf("%s/%s/_workitems/edit/%d", p.orgBaseURL(repo.Organization), repo.Project, workItem.ID),
+		CreatedAt: workItem.Fields.CreatedDate,
+		UpdatedAt: workItem.Fields.ChangedDate,
+	}
+	if slices.Contains(closedWorkItemStates, workItem.Fields.State) {
+		issue.State = globalEntities.IssueStateClosed
+	}
+	if workItem.Fields.AssignedTo != nil {
+		issue.Assignees = []string{workItem.Fields.AssignedTo.UniqueName}
+	}
+	if workItem.Fields.CreatedBy != nil {
+		issue.Author = workItem.Fields.CreatedBy.UniqueName
+	}
+	return issue
+}
+
+func parseWorkItem(resp []byte) (*adoWorkItem, error) {
+	var workItem adoWorkItem
+	if unmarshalErr := json.Unmarshal(resp, &workItem); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse work item response: %w", unmarshalErr)
+	}
+	return &workItem, nil
+}
+
+// buildWIQLQuery translates query into WIQL, newest change first.
+func buildWIQLQuery(query globalEntities.IssueQuery) string {
+	conditions := []string{"[System.TeamProject] = @project"}
+
+	closedStates := make([]string, 0, len(closedWorkItemStates))
+	for _, state := range closedWorkItemStates {
+		closedStates = append(closedStates, wiqlString(state))
+	}
+	switch query.State {
+	case globalEntities.IssueStateOpen:
+		conditions = append(conditions, "[System.State] NOT IN ("+strings.Join(closedStates, ", ")+")")
+	case globalEntities.IssueStateClosed:
+		conditions = append(conditions, "[System.State] IN ("+strings.Join(closedStates, ", ")+")")
+	}
+
+	for _, label := range query.Labels {
+		conditions = append(conditions, "[System.Tags] CONTAINS "+wiqlString(label))
+	}
+	if query.Assignee != "" {
+		conditions = append(conditions, "[System.AssignedTo] = "+wiqlString(query.Assignee))
+	}
+	if query.Text != "" {
+		text := wiqlString(query.Text)
+		conditions = append(conditions, fmt.Sprintf(
+			"([System.Title] CONTAINS %s OR [System.Description] CONTAINS %s)", text, text,
+		))
+	}
+
+	return "SELECT [System.Id] FROM WorkItems WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY [System.ChangedDate] DESC"
+}
+
+// wiqlString quotes value as a WIQL string literal.
+func wiqlString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// splitWorkItemTags splits the System.Tags field, a "; " separated list.
+func splitWorkItemTags(tags string) []string {
+	var result []string
+	for tag := range strings.SplitSeq(tags, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const testWorkItemsPath = "/my-org/my-project/_apis/wit/workitems"
+
+func TestCreateIssueInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create a task work item through a JSON patch document", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var contentType string
+		var operations []adoPatchOperation
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST "+testWorkItemsPath+"/$Task", func(w http.ResponseWriter, r *http.Request) {
+			contentType = r.Header.Get("Content-Type")
+			_ = json.NewDecoder(r.Body).Decode(&operations)
+			_, _ = w.Write([]byte(`{"id":42,"rev":1,"fields":{
+				"System.WorkItemType":"Task","System.Title":"Autoupdate failed","System.State":"To Do",
+				"System.Tags":"autoupdate; ci","System.AssignedTo":{"uniqueName":"dev@example.com"},
+				"System.CreatedBy":{"uniqueName":"bot@example.com"},
+				"System.CreatedDate":"2026-03-04T05:06:07Z","System.ChangedDate":"2026-03-04T05:06:07Z"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		issue, err := p.CreateIssue(context.Background(), repo, globalEntities.IssueInput{
+			Title:     "Autoupdate failed",
+			Body:      "see the pipeline",
+			Labels:    []string{"autoupdate", "ci"},
+			Assignees: []string{"dev@example.com"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "application/json-patch+json", contentType)
+		assert.Equal(t, []adoPatchOperation{
+			{Op: "add", Path: "/fields/System.Title", Value: "Autoupdate failed"},
+			{Op: "add", Path: "/fields/System.Description", Value: "see the pipeline"},
+			{Op: "add", Path: "/fields/System.Tags", Value: "autoupdate; ci"},
+			{Op: "add", Path: "/fields/System.AssignedTo", Value: "dev@example.com"},
+		}, operations)
+		createdAt := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+		assert.Equal(t, &globalEntities.Issue{
+			ID:        42,
+			Title:     "Autoupdate failed",
+			State:     globalEntities.IssueStateOpen,
+			Labels:    []string{"autoupdate", "ci"},
+			Assignees: []string{"dev@example.com"},
+			Author:    "bot@example.com",
+			URL:       "https://dev.azure.com/my-org/my-project/_workitems/edit/42",
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}, issue)
+	})
+
+	t.Run("should reject more than one assignee", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		p := &Provider{}
+
+		// when
+		_, err := p.CreateIssue(context.Background(), globalEntities.Repository{}, globalEntities.IssueInput{
+			Title: "x", Assignees: []string{"a@example.com", "b@example.com"},
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestUpdateIssueStateInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should move the work item to the completed state of its type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var operations []adoPatchOperation
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testWorkItemsPath+"/42", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":42,"fields":{"System.WorkItemType":"User Story","System.State":"Active"}}`))
+		})
+		mux.HandleFunc(
+			"GET /my-org/my-project/_apis/wit/workitemtypes/User Story/states",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"value":[{"name":"New","category":"Proposed"},
+					{"name":"Active","category":"InProgress"},{"name":"Closed","category":"Completed"}]}`))
+			},
+		)
+		mux.HandleFunc("PATCH "+testWorkItemsPath+"/42", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&operations)
+			_, _ = w.Write([]byte(`{"id":42}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		err := p.UpdateIssueState(context.Background(), repo, 42, globalEntities.IssueStateClosed)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []adoPatchOperation{
+			{Op: "add", Path: "/fields/System.State", Value: "Closed"},
+		}, operations)
+	})
+}
+
+func TestSearchIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should query WIQL and return the matches most recently changed first", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var wiql map[string]string
+		var top, ids string
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /my-org/my-project/_apis/wit/wiql", func(w http.ResponseWriter, r *http.Request) {
+			top = r.URL.Query().Get("$top")
+			_ = json.NewDecoder(r.Body).Decode(&wiql)
+			_, _ = w.Write([]byte(`{"workItems":[{"id":7},{"id":3}]}`))
+		})
+		mux.HandleFunc("GET "+testWorkItemsPath, func(w http.ResponseWriter, r *http.Request) {
+			ids = r.URL.Query().Get("ids")
+			_, _ = w.Write([]byte(`{"value":[
+				{"id":3,"fields":{"System.State":"Done","System.ChangedDate":"2026-03-01T00:00:00Z"}},
+				{"id":7,"fields":{"System.State":"Done","System.ChangedDate":"2026-03-02T00:00:00Z"}}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		issues, err := p.SearchIssues(context.Background(), repo, globalEntities.IssueQuery{
+			State: globalEntities.IssueStateClosed, Labels: []string{"autoupdate"}, Text: "it's broken", Limit: 2,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "2", top)
+		assert.Equal(t, "SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project"+
+			" AND [System.State] IN ('Closed', 'Done', 'Resolved', 'Removed', 'Completed')"+
+			" AND [System.Tags] CONTAINS 'autoupdate'"+
+			" AND ([System.Title] CONTAINS 'it''s broken' OR [System.Description] CONTAINS 'it''s broken')"+
+			" ORDER BY [System.ChangedDate] DESC", wiql["query"])
+		assert.Equal(t, "7,3", ids)
+		require.Len(t, issues, 2)
+		assert.Equal(t, 7, issues[0].ID)
+		assert.Equal(t, 3, issues[1].ID)
+		assert.Equal(t, globalEntities.IssueStateClosed, issues[0].State)
+	})
+}
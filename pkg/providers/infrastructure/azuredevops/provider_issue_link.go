@@ -0,0 +1,169 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// adoPullRequestLinks holds the fields of a pull request that links to work
+// items need.
+type adoPullRequestLinks struct {
+	Repository struct {
+		ID      string `json:"id"`
+		Project struct {
+			ID string `json:"id"`
+		} `json:"project"`
+	} `json:"repository"`
+	CompletionOptions map[string]any `json:"completionOptions"`
+}
+
+// LinkPullRequestIssues adds an artifact link from each work item to the pull
+// request. A closing link sets the pull request's transitionWorkItems
+// completion option, which completes every linked work item on merge.
+func (p *Provider) LinkPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	links []globalEntities.IssueLink,
+) error {
+	baseURL := p.orgBaseURL(repo.Organization)
+	prEndpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
+	)
+
+	prResp, err := p.doRequest(ctx, baseURL, http.MethodGet, prEndpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request details: %w", err)
+	}
+	var pr adoPullRequestLinks
+	if unmarshalErr := json.Unmarshal(prResp, &pr); unmarshalErr != nil {
+		return fmt.Errorf("failed to parse pull request details: %w", unmarshalErr)
+	}
+
+	linked, err := p.listPullRequestWorkItems(ctx, repo, prID)
+	if err != nil {
+		return err
+	}
+
+	// the artifact URI escapes the separators between its three parts
+	artifactURL := fmt.Sprintf(
+		"vstfs:///Git/PullRequestId/%s%%2F%s%%2F%d", pr.Repository.Project.ID, pr.Repository.ID, prID,
+	)
+	for _, link := range links {
+		if slices.Contains(linked, link.ID) {
+			continue
+		}
+		operations := []adoPatchOperation{{
+			Op:   patchOperationAdd,
+			Path: "/relations/-",
+			Value: map[string]any{
+				"rel":        "ArtifactLink",
+				"url":        artifactURL,
+				"attributes": map[string]string{jsonKeyName: "Pull Request"},
+			},
+		}}
+		if _, err = p.updateWorkItem(ctx, repo, link.ID, operations); err != nil {
+			return fmt.Errorf("failed to link work item #%d: %w", link.ID, err)
+		}
+		linked = append(linked, link.ID)
+	}
+
+	if !closesWorkItems(links) || pr.CompletionOptions[jsonKeyTransitionWorkItems] == true {
+		return nil
+	}
+
+	// the options sent replace the pull request's, so the others are kept
+	completionOptions := pr.CompletionOptions
+	if completionOptions == nil {
+		completionOptions = map[string]any{}
+	}
+	completionOptions[jsonKeyTransitionWorkItems] = true
+	body := map[string]any{jsonKeyCompletionOptions: completionOptions}
+	if _, err = p.doRequest(ctx, baseURL, http.MethodPatch, prEndpoint, body); err != nil {
+		return fmt.Errorf("failed to set work item transition on pull request #%d: %w", prID, err)
+	}
+	return nil
+}
+
+// ListPullRequestIssues returns the work items linked to the pull request.
+// They all close when its transitionWorkItems completion option is set.
+func (p *Provider) ListPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.IssueLink, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	prEndpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
+	)
+
+	prResp, err := p.doRequest(ctx, baseURL, http.MethodGet, prEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request details: %w", err)
+	}
+	var pr adoPullRequestLinks
+	if unmarshalErr := json.Unmarshal(prResp, &pr); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request details: %w", unmarshalErr)
+	}
+
+	ids, err := p.listPullRequestWorkItems(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	closes := pr.CompletionOptions[jsonKeyTransitionWorkItems] == true
+	links := make([]globalEntities.IssueLink, 0, len(ids))
+	for _, id := range ids {
+		links = append(links, globalEntities.IssueLink{ID: id, Closes: closes})
+	}
+	return links, nil
+}
+
+func (p *Provider) listPullRequestWorkItems(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]int, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/pullRequests/%d/workitems?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list work items of pull request #%d: %w", prID, err)
+	}
+
+	var result struct {
+		Value []struct {
+			ID string `json:"id"`
+		} `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request work items: %w", unmarshalErr)
+	}
+
+	ids := make([]int, 0, len(result.Value))
+	for _, ref := range result.Value {
+		id, convErr := strconv.Atoi(ref.ID)
+		if convErr != nil {
+			return nil, fmt.Errorf("failed to parse work item ID %q: %w", ref.ID, convErr)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// closesWorkItems reports whether any of links asks to close its work item.
+func closesWorkItems(links []globalEntities.IssueLink) bool {
+	return slices.ContainsFunc(links, func(link globalEntities.IssueLink) bool { return link.Closes })
+}
@@ -0,0 +1,123 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestLinkPullRequestIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should add artifact links to unlinked work items and set the work item transition", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var linkedIDs []string
+		var relation []map[string]any
+		var prPatch map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/pullrequests/7", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"repository":{"id":"repo-guid","project":{"id":"project-guid"}},` +
+				`"completionOptions":{"deleteSourceBranch":true}}`))
+		})
+		mux.HandleFunc("GET "+testReposPath+"/pullRequests/7/workitems", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[{"id":"41"}]}`))
+		})
+		mux.HandleFunc("PATCH "+testWorkItemsPath+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+			linkedIDs = append(linkedIDs, r.PathValue("id"))
+			_ = json.NewDecoder(r.Body).Decode(&relation)
+			_, _ = w.Write([]byte(`{"id":42}`))
+		})
+		mux.HandleFunc("PATCH "+testReposPath+"/pullrequests/7", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&prPatch)
+			_, _ = w.Write([]byte(`{}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		err := p.LinkPullRequestIssues(context.Background(), repo, 7, []globalEntities.IssueLink{
+			{ID: 41}, {ID: 42, Closes: true},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"42"}, linkedIDs)
+		assert.Equal(t, []map[string]any{{
+			"op":   "add",
+			"path": "/relations/-",
+			"value": map[string]any{
+				"rel":        "ArtifactLink",
+				"url":        "vstfs:///Git/PullRequestId/project-guid%2Frepo-guid%2F7",
+				"attributes": map[string]any{"name": "Pull Request"},
+			},
+		}}, relation)
+		assert.Equal(t, map[string]any{
+			"completionOptions": map[string]any{"deleteSourceBranch": true, "transitionWorkItems": true},
+		}, prPatch)
+	})
+
+	t.Run("should leave the pull request alone when no link closes", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/pullrequests/7", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"repository":{"id":"repo-guid","project":{"id":"project-guid"}}}`))
+		})
+		mux.HandleFunc("GET "+testReposPath+"/pullRequests/7/workitems", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[{"id":"41"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		err := p.LinkPullRequestIssues(context.Background(), repo, 7, []globalEntities.IssueLink{{ID: 41}})
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestListPullRequestIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the linked work items as closing when they transition on completion", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/pullrequests/7", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"completionOptions":{"transitionWorkItems":true}}`))
+		})
+		mux.HandleFunc("GET "+testReposPath+"/pullRequests/7/workitems", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[{"id":"41"},{"id":"42"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		links, err := p.ListPullRequestIssues(context.Background(), repo, 7)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []globalEntities.IssueLink{{ID: 41, Closes: true}, {ID: 42, Closes: true}}, links)
+	})
+}
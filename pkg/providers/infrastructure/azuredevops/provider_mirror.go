@@ -0,0 +1,341 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	errImportFailed     = errors.New("repository import failed")
+	errAmbiguousProject = errors.New("organization has several projects")
+	errProjectNotFound  = errors.New("project not found")
+)
+
+// Import request statuses reported by Azure DevOps.
+const (
+	importStatusQueued     = "queued"
+	importStatusInProgress = "inProgress"
+	importStatusCompleted  = "completed"
+	importStatusFailed     = "failed"
+	importStatusAbandoned  = "abandoned"
+)
+
+type adoImportRequest struct {
+	ImportRequestID int    `json:"importRequestId"`
+	Status          string `json:"status"`
+	DetailedStatus  struct {
+		CurrentStep  int      `json:"currentStep"`
+		AllSteps     []string `json:"allSteps"`
+		ErrorMessage string   `json:"errorMessage"`
+	} `json:"detailedStatus"`
+}
+
+// step returns the name of the import step in progress, if reported.
+func (r adoImportRequest) step() string {
+	current := r.DetailedStatus.CurrentStep
+	if current < 1 || current > len(r.DetailedStatus.AllSteps) {
+		return r.Status
+	}
+	return r.DetailedStatus.AllSteps[current-1]
+}
+
+// MigrateRepository creates an empty repository and imports input.CloneAddr
+// into it through an import request, waiting for the import to finish.
+// RepoOwner is "{organization}/{project}", or just the organization when it
+// holds a single project. Source credentials, taken from AuthUsername and
+// AuthPassword or else from the userinfo of CloneAddr, are handed to the
+// import through a git service endpoint that Azure DevOps deletes once the
+// import is done. When the migration fails after the repository was created,
+// the repository is deleted again, and so is the service endpoint when no
+// import ran to delete it, so a retry does not find either left behind; both
+// deletions are best effort and only logged when they fail. Azure DevOps has
+// no pull mirrors, so with Mirror set the repository is still a one-time
+// import.
+func (p *Provider) MigrateRepository(
+	ctx context.Context,
+	input globalEntities.MirrorInput,
+) error {
+	if input.Mirror {
+		log.Warnf("Azure DevOps has no pull mirrors, %s/%s will be a one-time import of its source",
+			input.RepoOwner, input.RepoName)
+	}
+
+	org, projectName, _ := strings.Cut(input.RepoOwner, "/")
+	baseURL := p.orgURL(org)
+	project, err := p.findProject(ctx, baseURL, projectName)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the project of %q: %w", input.RepoOwner, err)
+	}
+
+	repo, err := p.createRepository(ctx, baseURL, project, input.RepoName)
+	if err != nil {
+		return err
+	}
+
+	if importErr := p.importRepository(ctx, baseURL, project, repo, input); importErr != nil {
+		p.deleteRepository(context.WithoutCancel(ctx), baseURL, project, repo)
+		return importErr
+	}
+	return nil
+}
+
+// importRepository imports the source of input into the empty repo and waits
+// for the import to finish. A service endpoint created for the source
+// credentials is deleted again when the import request cannot be made.
+func (p *Provider) importRepository(
+	ctx context.Context,
+	baseURL string,
+	project adoProject,
+	repo adoRepository,
+	input globalEntities.MirrorInput,
+) error {
+	sourceURL, username, password := splitCredentials(input)
+	serviceEndpointID := ""
+	if username != "" || password != "" {
+		var err error
+		serviceEndpointID, err = p.createGitServiceEndpoint(
+			ctx, baseURL, project, input.RepoName, sourceURL, username, password,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	request, err := p.requestImport(ctx, baseURL, project, repo, sourceURL, serviceEndpointID)
+	if err != nil {
+		if serviceEndpointID != "" {
+			p.deleteServiceEndpoint(context.WithoutCancel(ctx), baseURL, project, serviceEndpointID)
+		}
+		return err
+	}
+
+	return p.waitForImport(ctx, baseURL, project, repo, request, input)
+}
+
+// requestImport asks Azure DevOps to import sourceURL into repo, reading it
+// through the service endpoint serviceEndpointID when set.
+func (p *Provider) requestImport(
+	ctx context.Context,
+	baseURL string,
+	project adoProject,
+	repo adoRepository,
+	sourceURL, serviceEndpointID string,
+) (adoImportRequest, error) {
+	body := map[string]any{
+		"parameters": map[string]any{
+			"gitSource":                              map[string]any{"url": sourceURL, "overwrite": false},
+			"serviceEndpointId":                      serviceEndpointID,
+			"deleteServiceEndpointAfterImportIsDone": serviceEndpointID != "",
+		},
+	}
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/importRequests?api-version=%s",
+		project.ID, repo.ID, p.apiVersion(),
+	)
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, body)
+	if err != nil {
+		return adoImportRequest{}, fmt.Errorf("failed to request import of %q: %w", repo.Name, err)
+	}
+
+	var request adoImportRequest
+	if unmarshalErr := json.Unmarshal(resp, &request); unmarshalErr != nil {
+		return adoImportRequest{}, fmt.Errorf("failed to parse import request response: %w", unmarshalErr)
+	}
+	return request, nil
+}
+
+// findProject returns the project named name, or the organization's only
+// project when name is empty.
+func (p *Provider) findProject(ctx context.Context, baseURL, name string) (adoProject, error) {
+	projects, err := p.getProjects(ctx, baseURL)
+	if err != nil {
+		return adoProject{}, err
+	}
+
+	if name == "" {
+		if len(projects) != 1 {
+			return adoProject{}, fmt.Errorf(
+				"%w (%d), name one as {organization}/{project}", errAmbiguousProject, len(projects),
+			)
+		}
+		return projects[0], nil
+	}
+
+	for _, project := range projects {
+		if strings.EqualFold(project.Name, name) || project.ID == name {
+			return project, nil
+		}
+	}
+	return adoProject{}, fmt.Errorf("%w: %q", errProjectNotFound, name)
+}
+
+func (p *Provider) createRepository(
+	ctx context.Context,
+	baseURL string,
+	project adoProject,
+	name string,
+) (adoRepository, error) {
+	body := map[string]any{
+		jsonKeyName: name,
+		"project":   map[string]any{"id": project.ID},
+	}
+	endpoint := fmt.Sprintf("/%s/_apis/git/repositories?api-version=%s", project.ID, p.apiVersion())
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, body)
+	if err != nil {
+		return adoRepository{}, fmt.Errorf("failed to create repository %q: %w", name, err)
+	}
+
+	var repo adoRepository
+	if unmarshalErr := json.Unmarshal(resp, &repo); unmarshalErr != nil {
+		return adoRepository{}, fmt.Errorf("failed to parse repository response: %w", unmarshalErr)
+	}
+	return repo, nil
+}
+
+// deleteRepository deletes a repository a failed migration created. Failures
+// are logged, as the migration error is the one worth returning.
+func (p *Provider) deleteRepository(ctx context.Context, baseURL string, project adoProject, repo adoRepository) {
+	endpoint := fmt.Sprintf("/%s/_apis/git/repositories/%s?api-version=%s", project.ID, repo.ID, p.apiVersion())
+	if _, err := p.doRequest(ctx, baseURL, http.MethodDelete, endpoint, nil); err != nil {
+		log.Warnf("Failed to delete repository %q left by the failed import: %v", repo.Name, err)
+	}
+}
+
+// createGitServiceEndpoint stores the source credentials in a git service
+// endpoint of the project and returns its ID.
+func (p *Provider) createGitServiceEndpoint(
+	ctx context.Context,
+	baseURL string,
+	project adoProject,
+	repoName, sourceURL, username, password string,
+) (string, error) {
+	name := "gitforge-import-" + repoName
+	body := map[string]any{
+		jsonKeyName: name,
+		"type":      "git",
+		"url":       sourceURL,
+		"authorization": map[string]any{
+			"scheme":     "UsernamePassword",
+			"parameters": map[string]any{"username": username, "password": password},
+		},
+		"serviceEndpointProjectReferences": []map[string]any{{
+			"projectReference": map[string]any{"id": project.ID, jsonKeyName: project.Name},
+			jsonKeyName:        name,
+		}},
+	}
+	endpoint := "/_apis/serviceendpoint/endpoints?api-version=" + p.apiVersion() + "-preview"
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create service endpoint for the import of %q: %w", repoName, err)
+	}
+
+	var serviceEndpoint struct {
+		ID string `json:"id"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &serviceEndpoint); unmarshalErr != nil {
+		return "", fmt.Errorf("failed to parse service endpoint response: %w", unmarshalErr)
+	}
+	return serviceEndpoint.ID, nil
+}
+
+// deleteServiceEndpoint deletes a service endpoint no import ran to delete.
+// Failures are logged, as the migration error is the one worth returning.
+func (p *Provider) deleteServiceEndpoint(ctx context.Context, baseURL string, project adoProject, id string) {
+	endpoint := fmt.Sprintf(
+		"/_apis/serviceendpoint/endpoints/%s?projectIds=%s&api-version=%s-preview",
+		id, project.ID, p.apiVersion(),
+	)
+	if _, err := p.doRequest(ctx, baseURL, http.MethodDelete, endpoint, nil); err != nil {
+		log.Warnf("Failed to delete service endpoint %s holding the import credentials: %v", id, err)
+	}
+}
+
+// waitForImport polls the import request until it completes, fails or is
+// abandoned, reporting every status or step change.
+func (p *Provider) waitForImport(
+	ctx context.Context,
+	baseURL string,
+	project adoProject,
+	repo adoRepository,
+	request adoImportRequest,
+	input globalEntities.MirrorInput,
+) error {
+	interval := p.importPollInterval
+	if interval == 0 {
+		interval = defaultImportPollInterval
+	}
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/importRequests/%d?api-version=%s",
+		project.ID, repo.ID, request.ImportRequestID, p.apiVersion(),
+	)
+
+	lastReport := ""
+	for {
+		if report := request.Status + "/" + request.step(); report != lastReport {
+			lastReport = report
+			reportImportRequest(input, request)
+		}
+
+		switch request.Status {
+		case importStatusCompleted:
+			return nil
+		case importStatusFailed, importStatusAbandoned:
+			return fmt.Errorf("%w: %q is %s: %s", errImportFailed, repo.Name, request.Status,
+				request.DetailedStatus.ErrorMessage)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for import of %q: %w", repo.Name, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get import status of %q: %w", repo.Name, err)
+		}
+		if unmarshalErr := json.Unmarshal(resp, &request); unmarshalErr != nil {
+			return fmt.Errorf("failed to parse import request response: %w", unmarshalErr)
+		}
+	}
+}
+
+// reportImportRequest maps an import request status onto a mirror progress report.
+func reportImportRequest(input globalEntities.MirrorInput, request adoImportRequest) {
+	switch request.Status {
+	case importStatusQueued:
+		input.Report(globalEntities.MirrorStateQueued, request.step())
+	case importStatusInProgress:
+		input.Report(globalEntities.MirrorStateRunning, request.step())
+	case importStatusCompleted:
+		input.Report(globalEntities.MirrorStateCompleted, "")
+	default:
+		input.Report(globalEntities.MirrorStateFailed, request.DetailedStatus.ErrorMessage)
+	}
+}
+
+// splitCredentials returns the source URL without userinfo and the credentials
+// to read it with, preferring the explicit ones of the input.
+func splitCredentials(input globalEntities.MirrorInput) (string, string, string) {
+	parsed, err := url.Parse(input.CloneAddr)
+	if err != nil || parsed.User == nil {
+		return input.CloneAddr, input.AuthUsername, input.AuthPassword
+	}
+
+	username := parsed.User.Username()
+	password, _ := parsed.User.Password()
+	parsed.User = nil
+	if input.AuthUsername != "" || input.AuthPassword != "" {
+		username, password = input.AuthUsername, input.AuthPassword
+	}
+	return parsed.String(), username, password
+}
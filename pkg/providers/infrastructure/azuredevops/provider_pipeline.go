@@ -0,0 +1,440 @@
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	buildStatusCompleted = "completed"
+	timelineRecordJob    = "Job"
+)
+
+var errPipelineJobNotFound = errors.New("pipeline job not found")
+
+// adoBuild is a build as the Build API returns it; Azure Pipelines runs are
+// builds, addressed by the build ID.
+type adoBuild struct {
+	ID            int    `json:"id"`
+	Status        string `json:"status"`
+	Result        string `json:"result"`
+	SourceBranch  string `json:"sourceBranch"`
+	SourceVersion string `json:"sourceVersion"`
+	Definition    struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"definition"`
+	QueueTime  time.Time `json:"queueTime"`
+	FinishTime time.Time `json:"finishTime"`
+	Links      struct {
+		Web struct {
+			Href string `json:"href"`
+		} `json:"web"`
+	} `json:"_links"`
+}
+
+// adoTimelineRecord is a stage, phase, job or task of a build's timeline.
+type adoTimelineRecord struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	Name       string    `json:"name"`
+	State      string    `json:"state"`
+	Result     string    `json:"result"`
+	StartTime  time.Time `json:"startTime"`
+	FinishTime time.Time `json:"finishTime"`
+	Log        *struct {
+		ID int `json:"id"`
+	} `json:"log"`
+}
+
+// TriggerPipeline queues a build of the pipeline definition named by
+// input.Pipeline, either its numeric ID or its name. Inputs become runtime
+// parameters and Variables the queue-time variables of the build.
+func (p *Provider) TriggerPipeline(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PipelineRunInput,
+) (*globalEntities.PipelineRun, error) {
+	if input.Pipeline == "" {
+		return nil, errors.New("a pipeline definition ID or name is required to queue an Azure Pipelines run")
+	}
+	definitionID, err := p.resolveDefinitionID(ctx, repo, input.Pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := input.Ref
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	body := map[string]any{
+		"definition":   map[string]int{"id": definitionID},
+		"sourceBranch": ensureRefsPrefix(ref),
+	}
+	if len(input.Inputs) > 0 {
+		body["templateParameters"] = input.Inputs
+	}
+	if len(input.Variables) > 0 {
+		// the Build API takes the variables as a JSON-encoded string
+		variables, marshalErr := json.Marshal(input.Variables)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal pipeline variables: %w", marshalErr)
+		}
+		body["parameters"] = string(variables)
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf("/%s/_apis/build/builds?api-version=%s", repo.Project, p.apiVersion())
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue pipeline %s: %w", input.Pipeline, err)
+	}
+	return parseBuild(resp)
+}
+
+// ListPipelineRuns lists the builds of the repository. The Build API cannot
+// filter on a commit, so CommitSHA is matched on the listed builds.
+func (p *Provider) ListPipelineRuns(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.PipelineRunQuery,
+) ([]globalEntities.PipelineRun, error) {
+	ref, err := p.getRepositoryRef(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	var runs []globalEntities.PipelineRun
+	continuationToken := ""
+	for {
+		endpoint := fmt.Sprintf(
+			"/%s/_apis/build/builds?repositoryId=%s&repositoryType=TfsGit&queryOrder=queueTimeDescending&api-version=%s",
+			repo.Project, ref.ID, p.apiVersion(),
+		)
+		if query.Branch != "" {
+			endpoint += "&branchName=" + url.QueryEscape(ensureRefsPrefix(query.Branch))
+		}
+		if continuationToken != "" {
+			endpoint += "&continuationToken=" + continuationToken
+		}
+
+		resp, headers, reqErr := p.doRequestWithHeaders(ctx, baseURL, http.MethodGet, endpoint, nil)
+		if reqErr != nil {
+			return nil, fmt.Errorf("failed to list builds: %w", reqErr)
+		}
+		var result struct {
+			Value []adoBuild `json:"value"`
+		}
+		if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse builds response: %w", unmarshalErr)
+		}
+
+		for _, build := range result.Value {
+			if query.CommitSHA != "" && !strings.EqualFold(build.SourceVersion, query.CommitSHA) {
+				continue
+			}
+			runs = append(runs, *adoBuildToDomain(build))
+			if query.Limit > 0 && len(runs) == query.Limit {
+				return runs, nil
+			}
+		}
+		continuationToken = headers.Get(paginationHeader)
+		if continuationToken == "" {
+			break
+		}
+	}
+	return runs, nil
+}
+
+func (p *Provider) GetPipelineRun(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	build, err := p.getBuild(ctx, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+	return adoBuildToDomain(*build), nil
+}
+
+// ListPipelineJobs returns the job records of the build's timeline. Jobs
+// have no page of their own, so their URL is the build's.
+func (p *Provider) ListPipelineJobs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) ([]globalEntities.PipelineJob, error) {
+	build, err := p.getBuild(ctx, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := p.getTimelineJobs(ctx, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]globalEntities.PipelineJob, 0, len(records))
+	for _, record := range records {
+		jobs = append(jobs, globalEntities.PipelineJob{
+			ID:         record.ID,
+			Name:       record.Name,
+			Status:     adoTimelineStatus(record.State, record.Result),
+			URL:        build.Links.Web.Href,
+			StartedAt:  record.StartTime,
+			FinishedAt: record.FinishTime,
+		})
+	}
+	return jobs, nil
+}
+
+func (p *Provider) CancelPipelineRun(ctx context.Context, repo globalEntities.Repository, runID string) error {
+	endpoint, err := p.buildEndpoint(repo, runID, "")
+	if err != nil {
+		return err
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	if _, err = p.doRequest(ctx, baseURL, http.MethodPatch, endpoint, map[string]string{
+		"status": "cancelling",
+	}); err != nil {
+		return fmt.Errorf("failed to cancel build %s: %w", runID, err)
+	}
+	return nil
+}
+
+// RetryPipelineRun runs the failed stages of the build again.
+func (p *Provider) RetryPipelineRun(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	endpoint, err := p.buildEndpoint(repo, runID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPatch, endpoint+"&retry=true", map[string]any{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry build %s: %w", runID, err)
+	}
+	return parseBuild(resp)
+}
+
+// GetPipelineJobLogs returns the log of the job record with jobID. The Build
+// API serves logs whole, so the reader wraps the downloaded log.
+func (p *Provider) GetPipelineJobLogs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID, jobID string,
+) (io.ReadCloser, error) {
+	records, err := p.getTimelineJobs(ctx, repo, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	var logID int
+	for _, record := range records {
+		if strings.EqualFold(record.ID, jobID) && record.Log != nil {
+			logID = record.Log.ID
+			break
+		}
+	}
+	if logID == 0 {
+		return nil, fmt.Errorf("%w: no log for job %s of build %s", errPipelineJobNotFound, jobID, runID)
+	}
+
+	endpoint, err := p.buildEndpoint(repo, runID, "/logs/"+strconv.Itoa(logID))
+	if err != nil {
+		return nil, err
+	}
+	baseURL := p.orgBaseURL(repo.Organization)
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log of job %s: %w", jobID, err)
+	}
+	return io.NopCloser(bytes.NewReader(resp)), nil
+}
+
+// resolveDefinitionID returns pipeline as a definition ID, looking it up by
+// name when it is not numeric.
+func (p *Provider) resolveDefinitionID(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	pipeline string,
+) (int, error) {
+	if id, err := strconv.Atoi(pipeline); err == nil {
+		return id, nil
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/build/definitions?name=%s&api-version=%s",
+		repo.Project, url.QueryEscape(pipeline), p.apiVersion(),
+	)
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up pipeline %s: %w", pipeline, err)
+	}
+
+	var result struct {
+		Value []struct {
+			ID int `json:"id"`
+		} `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return 0, fmt.Errorf("failed to parse pipeline definitions response: %w", unmarshalErr)
+	}
+	switch len(result.Value) {
+	case 0:
+		return 0, fmt.Errorf("pipeline %q not found in project %s", pipeline, repo.Project)
+	case 1:
+		return result.Value[0].ID, nil
+	default:
+		return 0, fmt.Errorf("pipeline name %q is ambiguous in project %s, use its ID", pipeline, repo.Project)
+	}
+}
+
+func (p *Provider) getBuild(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*adoBuild, error) {
+	endpoint, err := p.buildEndpoint(repo, runID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build %s: %w", runID, err)
+	}
+	var build adoBuild
+	if unmarshalErr := json.Unmarshal(resp, &build); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse build response: %w", unmarshalErr)
+	}
+	return &build, nil
+}
+
+// getTimelineJobs returns the job records of the build's timeline. Retried
+// jobs keep their record, which always describes the latest attempt.
+func (p *Provider) getTimelineJobs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) ([]adoTimelineRecord, error) {
+	endpoint, err := p.buildEndpoint(repo, runID, "/timeline")
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timeline of build %s: %w", runID, err)
+	}
+	var timeline struct {
+		Records []adoTimelineRecord `json:"records"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &timeline); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse timeline response: %w", unmarshalErr)
+	}
+
+	var jobs []adoTimelineRecord
+	for _, record := range timeline.Records {
+		if record.Type == timelineRecordJob {
+			jobs = append(jobs, record)
+		}
+	}
+	return jobs, nil
+}
+
+// buildEndpoint returns the endpoint of the build with runID, followed by
+// suffix, with the API version as its first query parameter.
+func (p *Provider) buildEndpoint(repo globalEntities.Repository, runID, suffix string) (string, error) {
+	id, err := strconv.Atoi(runID)
+	if err != nil {
+		return "", fmt.Errorf("invalid build ID %q: %w", runID, err)
+	}
+	return fmt.Sprintf(
+		"/%s/_apis/build/builds/%d%s?api-version=%s", repo.Project, id, suffix, p.apiVersion(),
+	), nil
+}
+
+func parseBuild(resp []byte) (*globalEntities.PipelineRun, error) {
+	var build adoBuild
+	if unmarshalErr := json.Unmarshal(resp, &build); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse build response: %w", unmarshalErr)
+	}
+	return adoBuildToDomain(build), nil
+}
+
+func adoBuildToDomain(build adoBuild) *globalEntities.PipelineRun {
+	ref := strings.TrimPrefix(build.SourceBranch, "refs/heads/")
+	ref = strings.TrimPrefix(ref, "refs/tags/")
+	return &globalEntities.PipelineRun{
+		ID:         strconv.Itoa(build.ID),
+		Pipeline:   build.Definition.Name,
+		Ref:        ref,
+		CommitSHA:  build.SourceVersion,
+		Status:     adoBuildStatus(build.Status, build.Result),
+		URL:        build.Links.Web.Href,
+		CreatedAt:  build.QueueTime,
+		FinishedAt: build.FinishTime,
+	}
+}
+
+// adoBuildStatus maps the status and, once completed, the result of a build.
+func adoBuildStatus(status, result string) globalEntities.PipelineStatus {
+	switch {
+	case status == "inProgress" || status == "cancelling":
+		return globalEntities.PipelineStatusRunning
+	case status != buildStatusCompleted: // notStarted, postponed, none
+		return globalEntities.PipelineStatusQueued
+	}
+
+	switch result {
+	case "succeeded", "partiallySucceeded":
+		return globalEntities.PipelineStatusSuccess
+	case "canceled":
+		return globalEntities.PipelineStatusCanceled
+	default: // failed
+		return globalEntities.PipelineStatusFailure
+	}
+}
+
+// adoTimelineStatus maps the state and, once completed, the result of a
+// timeline record.
+func adoTimelineStatus(state, result string) globalEntities.PipelineStatus {
+	switch {
+	case state == "inProgress":
+		return globalEntities.PipelineStatusRunning
+	case state != buildStatusCompleted: // pending
+		return globalEntities.PipelineStatusQueued
+	}
+
+	switch result {
+	case "succeeded", "succeededWithIssues":
+		return globalEntities.PipelineStatusSuccess
+	case "canceled":
+		return globalEntities.PipelineStatusCanceled
+	case "skipped":
+		return globalEntities.PipelineStatusSkipped
+	default: // failed, abandoned
+		return globalEntities.PipelineStatusFailure
+	}
+}
@@ -0,0 +1,258 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	testBuildsPath = "/my-org/my-project/_apis/build/builds"
+	testBuildJSON  = `{"id":301,"status":"completed","result":"failed","sourceBranch":"refs/heads/feature",
+		"sourceVersion":"abc123","definition":{"id":7,"name":"CI"},
+		"queueTime":"2026-01-02T10:00:00Z","finishTime":"2026-01-02T10:05:00Z",
+		"_links":{"web":{"href":"https://dev.azure.com/my-org/my-project/_build/results?buildId=301"}}}`
+	testTimelineJSON = `{"records":[
+		{"id":"stage-1","type":"Stage","name":"Build","state":"completed","result":"failed"},
+		{"id":"job-1","type":"Job","name":"compile","state":"completed","result":"succeeded","log":{"id":4}},
+		{"id":"job-2","type":"Job","name":"test","state":"inProgress","log":{"id":5}},
+		{"id":"job-3","type":"Job","name":"deploy","state":"pending"}]}`
+)
+
+func TestTriggerPipelineInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should resolve the definition by name and queue a build with parameters and variables", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /my-org/my-project/_apis/build/definitions", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "CI", r.URL.Query().Get("name"))
+			_, _ = w.Write([]byte(`{"count":1,"value":[{"id":7,"name":"CI"}]}`))
+		})
+		mux.HandleFunc("POST "+testBuildsPath, func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"id":302,"status":"notStarted","sourceBranch":"refs/heads/main",
+				"definition":{"id":7,"name":"CI"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{
+			Organization: "my-org", Project: "my-project", Name: "my-repo", DefaultBranch: "refs/heads/main",
+		}
+
+		// when
+		run, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{
+			Pipeline:  "CI",
+			Inputs:    map[string]string{"environment": "staging"},
+			Variables: map[string]string{"DEBUG": "1"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"definition":         map[string]any{"id": float64(7)},
+			"sourceBranch":       "refs/heads/main",
+			"templateParameters": map[string]any{"environment": "staging"},
+			"parameters":         `{"DEBUG":"1"}`,
+		}, body)
+		assert.Equal(t, &globalEntities.PipelineRun{
+			ID: "302", Pipeline: "CI", Ref: "main", Status: globalEntities.PipelineStatusQueued,
+		}, run)
+	})
+
+	t.Run("should fail when the pipeline name matches no definition", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /my-org/my-project/_apis/build/definitions", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"count":0,"value":[]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		_, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{Pipeline: "missing"})
+
+		// then
+		require.ErrorContains(t, err, `pipeline "missing" not found`)
+	})
+}
+
+func TestListPipelineRunsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should filter by branch, match the commit across pages and stop at the limit", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var queries []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath, repositoryRefHandler)
+		mux.HandleFunc("GET "+testBuildsPath, func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.Query().Get("repositoryId")+"@"+r.URL.Query().Get("branchName"))
+			if r.URL.Query().Get("continuationToken") == "" {
+				w.Header().Set(paginationHeader, "page-2")
+				_, _ = w.Write([]byte(`{"value":[{"id":303,"sourceVersion":"def456"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"value":[` + testBuildJSON + `,{"id":300,"sourceVersion":"abc123"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		runs, err := p.ListPipelineRuns(context.Background(), repo, globalEntities.PipelineRunQuery{
+			Branch: "feature", CommitSHA: "abc123", Limit: 1,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"repo-guid@refs/heads/feature", "repo-guid@refs/heads/feature"}, queries)
+		assert.Equal(t, []globalEntities.PipelineRun{{
+			ID:         "301",
+			Pipeline:   "CI",
+			Ref:        "feature",
+			CommitSHA:  "abc123",
+			Status:     globalEntities.PipelineStatusFailure,
+			URL:        "https://dev.azure.com/my-org/my-project/_build/results?buildId=301",
+			CreatedAt:  time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+			FinishedAt: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC),
+		}}, runs)
+	})
+}
+
+func TestListPipelineJobsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the job records of the timeline with the build URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testBuildsPath+"/301", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(testBuildJSON))
+		})
+		mux.HandleFunc("GET "+testBuildsPath+"/301/timeline", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(testTimelineJSON))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		jobs, err := p.ListPipelineJobs(context.Background(), repo, "301")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, jobs, 3)
+		assert.Equal(t, "job-1", jobs[0].ID)
+		assert.Equal(t, globalEntities.PipelineStatusSuccess, jobs[0].Status)
+		assert.Equal(t, "https://dev.azure.com/my-org/my-project/_build/results?buildId=301", jobs[0].URL)
+		assert.Equal(t, globalEntities.PipelineStatusRunning, jobs[1].Status)
+		assert.Equal(t, globalEntities.PipelineStatusQueued, jobs[2].Status)
+	})
+}
+
+func TestCancelPipelineRunInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should set the build status to cancelling", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("PATCH "+testBuildsPath+"/301", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(testBuildJSON))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		err := p.CancelPipelineRun(context.Background(), repo, "301")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"status": "cancelling"}, body)
+	})
+}
+
+func TestGetPipelineJobLogsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should download the log of the job record", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testBuildsPath+"/301/timeline", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(testTimelineJSON))
+		})
+		mux.HandleFunc("GET "+testBuildsPath+"/301/logs/5", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("running tests\n"))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		logs, err := p.GetPipelineJobLogs(context.Background(), repo, "301", "job-2")
+
+		// then
+		require.NoError(t, err)
+		defer logs.Close()
+		content, readErr := io.ReadAll(logs)
+		require.NoError(t, readErr)
+		assert.Equal(t, "running tests\n", string(content))
+	})
+
+	t.Run("should fail for a job that has no log yet", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testBuildsPath+"/301/timeline", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(testTimelineJSON))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo"}
+
+		// when
+		_, err := p.GetPipelineJobLogs(context.Background(), repo, "301", "job-3")
+
+		// then
+		require.ErrorIs(t, err, errPipelineJobNotFound)
+	})
+}
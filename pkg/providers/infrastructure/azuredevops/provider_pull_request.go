@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -38,7 +39,7 @@ func (p *Provider) CreatePullRequest(
 	repo globalEntities.Repository,
 	input globalEntities.PullRequestInput,
 ) (*globalEntities.PullRequest, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	repoIdentifier := resolveRepoIdentifier(repo)
 
 	body := map[string]any{
@@ -47,10 +48,20 @@ func (p *Provider) CreatePullRequest(
 		jsonKeyTitle:         input.Title,
 		"description":        input.Description,
 	}
+	if len(input.IssueLinks) > 0 {
+		workItemRefs := make([]map[string]string, 0, len(input.IssueLinks))
+		for _, link := range input.IssueLinks {
+			workItemRefs = append(workItemRefs, map[string]string{"id": strconv.Itoa(link.ID)})
+		}
+		body["workItemRefs"] = workItemRefs
+	}
+	if closesWorkItems(input.IssueLinks) {
+		body[jsonKeyCompletionOptions] = map[string]any{jsonKeyTransitionWorkItems: true}
+	}
 
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests?api-version=%s",
-		repo.Project, repoIdentifier, apiVersion,
+		repo.Project, repoIdentifier, p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, body)
@@ -81,7 +92,7 @@ func (p *Provider) CreatePullRequest(
 		}
 		updateEndpoint := fmt.Sprintf(
 			"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
-			repo.Project, repoIdentifier, pr.ID, apiVersion,
+			repo.Project, repoIdentifier, pr.ID, p.apiVersion(),
 		)
 		_, _ = p.doRequest(ctx, baseURL, http.MethodPatch, updateEndpoint, updateBody)
 	}
@@ -91,13 +102,13 @@ func (p *Provider) CreatePullRequest(
 
 // activePullRequestsEndpoint builds the endpoint listing the active pull requests
 // whose source branch is sourceBranch.
-func activePullRequestsEndpoint(repo globalEntities.Repository, sourceBranch string) string {
+func (p *Provider) activePullRequestsEndpoint(repo globalEntities.Repository, sourceBranch string) string {
 	return fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests?searchCriteria.sourceRefName=%s&searchCriteria.status=active&api-version=%s",
 		repo.Project,
 		resolveRepoIdentifier(repo),
 		url.QueryEscape(ensureRefsPrefix(sourceBranch)),
-		apiVersion,
+		p.apiVersion(),
 	)
 }
 
@@ -106,8 +117,8 @@ func (p *Provider) PullRequestExists(
 	repo globalEntities.Repository,
 	sourceBranch string,
 ) (bool, error) {
-	baseURL := buildBaseURL(repo.Organization)
-	endpoint := activePullRequestsEndpoint(repo, sourceBranch)
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := p.activePullRequestsEndpoint(repo, sourceBranch)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -131,8 +142,8 @@ func (p *Provider) findActivePullRequestID(
 	repo globalEntities.Repository,
 	sourceBranch string,
 ) (int, bool, error) {
-	baseURL := buildBaseURL(repo.Organization)
-	endpoint := activePullRequestsEndpoint(repo, sourceBranch)
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := p.activePullRequestsEndpoint(repo, sourceBranch)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
 	if err != nil {
@@ -168,10 +179,10 @@ func (p *Provider) ClosePullRequest(
 		return false, nil
 	}
 
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 	body := map[string]any{jsonKeyStatus: prStatusAbandoned}
 
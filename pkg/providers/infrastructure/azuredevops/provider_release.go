@@ -0,0 +1,233 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var errReleaseNotFound = errors.New("release not found")
+
+// CreateRelease creates an annotated tag on input.Target (or the default
+// branch) whose message carries the release name and body. Azure Repos has no
+// releases of its own, so drafts, prereleases and assets are unsupported and
+// Latest is ignored.
+func (p *Provider) CreateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	if err := checkReleaseFlags(input); err != nil {
+		return nil, err
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	commitID, err := p.resolveCommitID(ctx, baseURL, repo, input.Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve release target: %w", err)
+	}
+
+	tag, err := p.createAnnotatedTag(ctx, baseURL, repo, input.TagName, commitID, releaseMessage(input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release %q: %w", input.TagName, err)
+	}
+	return p.annotatedTagToRelease(repo, *tag), nil
+}
+
+// UpdateRelease rewrites the message of the tag of input.TagName. Git tags are
+// immutable, so the tag is deleted and recreated on the same commit; a failure
+// in between leaves the tag deleted.
+func (p *Provider) UpdateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	if err := checkReleaseFlags(input); err != nil {
+		return nil, err
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	ref, err := p.findTagRef(ctx, baseURL, repo, input.TagName)
+	if err != nil {
+		return nil, err
+	}
+	commitID := ref.PeeledObjectID
+	if commitID == "" {
+		commitID = ref.ObjectID
+	}
+
+	if err = p.updateRef(ctx, baseURL, repo, ref.Name, ref.ObjectID, allZeroObjectID); err != nil {
+		return nil, fmt.Errorf("failed to update release %q: %w", input.TagName, err)
+	}
+	tag, err := p.createAnnotatedTag(ctx, baseURL, repo, input.TagName, commitID, releaseMessage(input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update release %q: %w", input.TagName, err)
+	}
+	return p.annotatedTagToRelease(repo, *tag), nil
+}
+
+// GetRelease returns the release of the annotated tag tagName. Lightweight
+// tags carry no message and are not releases.
+func (p *Provider) GetRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+) (*globalEntities.Release, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	ref, err := p.findTagRef(ctx, baseURL, repo, tagName)
+	if err != nil {
+		return nil, err
+	}
+	if ref.PeeledObjectID == "" {
+		return nil, fmt.Errorf("%w: %q is a lightweight tag", errReleaseNotFound, tagName)
+	}
+
+	tag, err := p.getAnnotatedTag(ctx, baseURL, repo, ref.ObjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %q: %w", tagName, err)
+	}
+	return p.annotatedTagToRelease(repo, *tag), nil
+}
+
+// ListReleases returns the releases of every annotated tag, newest first. Each
+// tag's message takes a request of its own.
+func (p *Provider) ListReleases(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Release, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	refs, err := p.listRefs(ctx, baseURL, repo, "tags/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var releases []globalEntities.Release
+	for _, ref := range refs {
+		if ref.PeeledObjectID == "" {
+			continue
+		}
+		tag, tagErr := p.getAnnotatedTag(ctx, baseURL, repo, ref.ObjectID)
+		if tagErr != nil {
+			return nil, fmt.Errorf("failed to get release %q: %w", ref.Name, tagErr)
+		}
+		releases = append(releases, *p.annotatedTagToRelease(repo, *tag))
+	}
+
+	slices.SortStableFunc(releases, func(a, b globalEntities.Release) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return releases, nil
+}
+
+func (p *Provider) UploadReleaseAsset(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ string,
+	_ globalEntities.ReleaseAssetInput,
+) (*globalEntities.ReleaseAsset, error) {
+	return nil, fmt.Errorf("Azure DevOps tags cannot carry release assets: %w", errors.ErrUnsupported)
+}
+
+func (p *Provider) DeleteReleaseAsset(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_, _ string,
+) error {
+	return fmt.Errorf("Azure DevOps tags cannot carry release assets: %w", errors.ErrUnsupported)
+}
+
+// findTagRef returns the ref of tagName, peeled so annotated tags report
+// their commit.
+func (p *Provider) findTagRef(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	tagName string,
+) (*adoRef, error) {
+	// the filter is a prefix match, so "v1.0" also lists "v1.0.1"
+	refs, err := p.listRefs(ctx, baseURL, repo, "tags/"+tagName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %q: %w", tagName, err)
+	}
+	for _, ref := range refs {
+		if ref.Name == "refs/tags/"+tagName {
+			return &ref, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", errReleaseNotFound, tagName)
+}
+
+func (p *Provider) getAnnotatedTag(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	objectID string,
+) (*adoAnnotatedTag, error) {
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/annotatedtags/%s?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), objectID, p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag adoAnnotatedTag
+	if unmarshalErr := json.Unmarshal(resp, &tag); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse annotated tag response: %w", unmarshalErr)
+	}
+	return &tag, nil
+}
+
+func (p *Provider) annotatedTagToRelease(
+	repo globalEntities.Repository,
+	tag adoAnnotatedTag,
+) *globalEntities.Release {
+	name, body := parseReleaseMessage(tag.Message)
+	return &globalEntities.Release{
+		ID:      tag.Name,
+		TagName: tag.Name,
+		Name:    name,
+		Body:    body,
+		URL: fmt.Sprintf(
+			"%s/%s/_git/%s?version=GT%s",
+			p.orgBaseURL(repo.Organization), repo.Project, repo.Name, url.QueryEscape(tag.Name),
+		),
+		CreatedAt: tag.TaggedBy.Date,
+	}
+}
+
+// checkReleaseFlags rejects the release states a tag cannot represent.
+func checkReleaseFlags(input globalEntities.ReleaseInput) error {
+	if input.Draft || input.Prerelease {
+		return fmt.Errorf("Azure DevOps tags cannot be drafts or prereleases: %w", errors.ErrUnsupported)
+	}
+	return nil
+}
+
+// releaseMessage lays the release out as a commit-style tag message: the name
+// as the subject line and the body after a blank line.
+func releaseMessage(input globalEntities.ReleaseInput) string {
+	name := input.Name
+	if name == "" {
+		name = input.TagName
+	}
+	if input.Body == "" {
+		return name
+	}
+	return name + "\n\n" + input.Body
+}
+
+// parseReleaseMessage splits a tag message written by releaseMessage.
+func parseReleaseMessage(message string) (string, string) {
+	name, body, _ := strings.Cut(strings.TrimRight(message, "\n"), "\n")
+	return name, strings.TrimLeft(body, "\n")
+}
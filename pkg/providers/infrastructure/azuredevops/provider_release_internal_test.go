@@ -0,0 +1,219 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	testCommitID  = "1111111111111111111111111111111111111111"
+	testReposPath = "/my-org/my-project/_apis/git/repositories/repo-id"
+)
+
+func TestCreateReleaseInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should tag the branch head with the name and body as the message", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/refs", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "heads/release/1.x", r.URL.Query().Get("filter"))
+			_, _ = w.Write([]byte(`{"value":[
+				{"name":"refs/heads/release/1.x","objectId":"` + testCommitID + `"},
+				{"name":"refs/heads/release/1.x-old","objectId":"2222222222222222222222222222222222222222"}]}`))
+		})
+		mux.HandleFunc(
+			"POST "+testReposPath+"/annotatedtags",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{"name":"v1.0.0","objectId":"tag-object",
+					"message":"First release\n\nChangelog",
+					"taggedBy":{"name":"bot","date":"2026-01-02T03:04:05Z"}}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		release, err := p.CreateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0", Target: "release/1.x", Name: "First release", Body: "Changelog",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "v1.0.0", body["name"])
+		assert.Equal(t, map[string]any{"objectId": testCommitID}, body["taggedObject"])
+		assert.Equal(t, "First release\n\nChangelog", body["message"])
+		assert.Equal(t, "First release", release.Name)
+		assert.Equal(t, "Changelog", release.Body)
+		assert.Equal(t, "https://dev.azure.com/my-org/my-project/_git/my-repo?version=GTv1.0.0", release.URL)
+	})
+
+	t.Run("should reject drafts as unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		_, err := p.CreateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0", Draft: true,
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestUpdateReleaseInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should recreate the tag on the same commit with the new message", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var deleted []map[string]string
+		var created map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/refs", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[
+				{"name":"refs/tags/v1.0.0","objectId":"tag-object","peeledObjectId":"` + testCommitID + `"}]}`))
+		})
+		mux.HandleFunc("POST "+testReposPath+"/refs", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&deleted)
+			_, _ = w.Write([]byte(`{"value":[{"success":true,"updateStatus":"succeeded"}]}`))
+		})
+		mux.HandleFunc(
+			"POST "+testReposPath+"/annotatedtags",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&created)
+				_, _ = w.Write([]byte(`{"name":"v1.0.0","objectId":"new-tag-object","message":"Renamed"}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		release, err := p.UpdateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0", Name: "Renamed",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]string{{
+			"name": "refs/tags/v1.0.0", "oldObjectId": "tag-object", "newObjectId": allZeroObjectID,
+		}}, deleted)
+		assert.Equal(t, map[string]any{"objectId": testCommitID}, created["taggedObject"])
+		assert.Equal(t, "Renamed", release.Name)
+	})
+
+	t.Run("should return an error when the ref update is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/refs", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[
+				{"name":"refs/tags/v1.0.0","objectId":"tag-object","peeledObjectId":"` + testCommitID + `"}]}`))
+		})
+		mux.HandleFunc("POST "+testReposPath+"/refs", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[{"success":false,"updateStatus":"rejectedByPolicy"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		_, err := p.UpdateRelease(context.Background(), repo, globalEntities.ReleaseInput{TagName: "v1.0.0"})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rejectedByPolicy")
+	})
+}
+
+func TestListReleasesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list annotated tags newest first and skip lightweight ones", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/refs", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[
+				{"name":"refs/tags/v1.0.0","objectId":"tag-1","peeledObjectId":"` + testCommitID + `"},
+				{"name":"refs/tags/light","objectId":"` + testCommitID + `"},
+				{"name":"refs/tags/v2.0.0","objectId":"tag-2","peeledObjectId":"` + testCommitID + `"}]}`))
+		})
+		mux.HandleFunc(
+			"GET "+testReposPath+"/annotatedtags/{id}",
+			func(w http.ResponseWriter, r *http.Request) {
+				version, date := "v1.0.0", "2026-01-01T00:00:00Z"
+				if r.PathValue("id") == "tag-2" {
+					version, date = "v2.0.0", "2026-02-01T00:00:00Z"
+				}
+				_, _ = w.Write([]byte(`{"name":"` + version + `","message":"` + strings.ToUpper(version) +
+					`","taggedBy":{"date":"` + date + `"}}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		releases, err := p.ListReleases(context.Background(), repo)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, releases, 2)
+		assert.Equal(t, "v2.0.0", releases[0].TagName)
+		assert.Equal(t, "V2.0.0", releases[0].Name)
+		assert.Equal(t, "v1.0.0", releases[1].TagName)
+	})
+}
+
+func TestUploadReleaseAssetInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report assets as unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		p := newProvider("test-token")
+
+		// when
+		_, err := p.UploadReleaseAsset(context.Background(), globalEntities.Repository{}, "v1.0.0",
+			globalEntities.ReleaseAssetInput{Name: "app.zip", Content: strings.NewReader("zip")})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
 	log "github.com/sirupsen/logrus"
@@ -22,10 +23,10 @@ func (p *Provider) ListOpenPullRequests(
 	ctx context.Context,
 	repo globalEntities.Repository,
 ) ([]globalEntities.PullRequestDetail, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests?searchCriteria.status=active&api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -74,10 +75,10 @@ func (p *Provider) GetPullRequestDiff(
 	prID int,
 ) (string, error) {
 	// get the PR details for source/target branches
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	prEndpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	prResp, err := p.doRequest(ctx, baseURL, http.MethodGet, prEndpoint, nil)
@@ -158,14 +159,14 @@ func (p *Provider) getFileContentAtVersion(
 	path string,
 	version string,
 ) (string, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/items?path=%s&versionDescriptor.version=%s&versionDescriptor.versionType=branch&api-version=%s",
 		repo.Project,
 		resolveRepoIdentifier(repo),
 		url.QueryEscape(path),
 		url.QueryEscape(version),
-		apiVersion,
+		p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -309,12 +310,12 @@ func (p *Provider) GetPullRequestFiles(
 	repo globalEntities.Repository,
 	prID int,
 ) ([]globalEntities.PullRequestFile, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 
 	// get the latest iteration
 	iterEndpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/iterations?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	iterResp, err := p.doRequest(ctx, baseURL, http.MethodGet, iterEndpoint, nil)
@@ -340,7 +341,7 @@ func (p *Provider) GetPullRequestFiles(
 	// get changes for the latest iteration
 	changesEndpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/iterations/%d/changes?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, latestIter, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, latestIter, p.apiVersion(),
 	)
 
 	changesResp, err := p.doRequest(ctx, baseURL, http.MethodGet, changesEndpoint, nil)
@@ -392,10 +393,10 @@ func (p *Provider) ListPullRequestComments(
 	repo globalEntities.Repository,
 	prID int,
 ) ([]globalEntities.PullRequestComment, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	basePath := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/threads?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	var comments []globalEntities.PullRequestComment
@@ -499,10 +500,10 @@ func (p *Provider) PostPullRequestComment(
 	body string,
 	opts ...globalEntities.CommentOption,
 ) error {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/threads?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	threadBody := map[string]any{
@@ -543,10 +544,10 @@ func (p *Provider) PostPullRequestThreadComment(
 	body string,
 	opts ...globalEntities.CommentOption,
 ) (int, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/threads?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	threadBody := map[string]any{
@@ -648,10 +649,10 @@ func (p *Provider) ReplyToThread(
 	prID, threadID int,
 	body string,
 ) (int, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/threads/%d/comments?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, threadID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, threadID, p.apiVersion(),
 	)
 
 	commentBody := map[string]any{
@@ -685,10 +686,10 @@ func (p *Provider) UpdatePullRequestThreadStatus(
 	prID, threadID int,
 	status string,
 ) error {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/threads/%d?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, threadID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, threadID, p.apiVersion(),
 	)
 
 	body := map[string]any{
@@ -710,10 +711,10 @@ func (p *Provider) GetPullRequestStatus(
 	repo globalEntities.Repository,
 	prID int,
 ) (string, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -738,10 +739,10 @@ func (p *Provider) getLatestPullRequestIterationID(
 	repo globalEntities.Repository,
 	prID int,
 ) (int, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/iterations?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -793,10 +794,10 @@ func (p *Provider) getChangeTrackingID(
 	iterationID int,
 	filePath string,
 ) (json.RawMessage, bool, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/iterations/%d/changes?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, iterationID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, iterationID, p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -837,10 +838,10 @@ func (p *Provider) GetPullRequestCheckStatus(
 	repo globalEntities.Repository,
 	prID int,
 ) (bool, error) {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/statuses?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
@@ -871,6 +872,203 @@ func (p *Provider) GetPullRequestCheckStatus(
 	return true, nil
 }
 
+// buildPolicyTypeID identifies the build validation policy, whose
+// evaluations carry the ID of the build they queued.
+const buildPolicyTypeID = "0609b952-1397-4640-95ec-e00a01b2c241"
+
+// GetPullRequestChecks combines the policy evaluations of the pull request,
+// required when the policy is blocking, with the latest status posted per
+// context.
+func (p *Provider) GetPullRequestChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*globalEntities.PullRequestChecks, error) {
+	ref, err := p.getRepositoryRef(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := p.listPolicyChecks(ctx, repo, ref.Project.ID, prID)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := p.listStatusChecks(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	return globalEntities.NewPullRequestChecks(append(checks, statuses...)), nil
+}
+
+func (p *Provider) listPolicyChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	projectID string,
+	prID int,
+) ([]globalEntities.Check, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	artifactID := fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d", projectID, prID)
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/policy/evaluations?artifactId=%s&api-version=%s-preview.1",
+		repo.Project, url.QueryEscape(artifactID), p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request policy evaluations: %w", err)
+	}
+
+	var result struct {
+		Value []struct {
+			Status        string    `json:"status"`
+			StartedDate   time.Time `json:"startedDate"`
+			CompletedDate time.Time `json:"completedDate"`
+			Context       struct {
+				BuildID int `json:"buildId"`
+			} `json:"context"`
+			Configuration struct {
+				IsEnabled  bool `json:"isEnabled"`
+				IsBlocking bool `json:"isBlocking"`
+				Type       struct {
+					ID          string `json:"id"`
+					DisplayName string `json:"displayName"`
+				} `json:"type"`
+				Settings struct {
+					DisplayName string `json:"displayName"`
+				} `json:"settings"`
+			} `json:"configuration"`
+		} `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse policy evaluations response: %w", unmarshalErr)
+	}
+
+	checks := make([]globalEntities.Check, 0, len(result.Value))
+	for _, evaluation := range result.Value {
+		configuration := evaluation.Configuration
+		if !configuration.IsEnabled {
+			continue
+		}
+		check := globalEntities.Check{
+			Name:        configuration.Settings.DisplayName,
+			Kind:        globalEntities.CheckKindPolicy,
+			State:       adoPolicyCheckState(evaluation.Status),
+			Required:    configuration.IsBlocking,
+			StartedAt:   evaluation.StartedDate,
+			CompletedAt: evaluation.CompletedDate,
+		}
+		if check.Name == "" {
+			check.Name = configuration.Type.DisplayName
+		}
+		if configuration.Type.ID == buildPolicyTypeID {
+			check.Kind = globalEntities.CheckKindPipeline
+			if evaluation.Context.BuildID != 0 {
+				check.URL = fmt.Sprintf(
+					"%s/%s/_build/results?buildId=%d",
+					baseURL, repo.Project, evaluation.Context.BuildID,
+				)
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// listStatusChecks returns the statuses posted on the pull request. Posting
+// again under the same context adds a status, so only the latest one counts.
+func (p *Provider) listStatusChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.Check, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/pullrequests/%d/statuses?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request statuses: %w", err)
+	}
+
+	var result struct {
+		Value []struct {
+			ID           int       `json:"id"`
+			State        string    `json:"state"`
+			TargetURL    string    `json:"targetUrl"`
+			CreationDate time.Time `json:"creationDate"`
+			UpdatedDate  time.Time `json:"updatedDate"`
+			Context      struct {
+				Name  string `json:"name"`
+				Genre string `json:"genre"`
+			} `json:"context"`
+		} `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse statuses response: %w", unmarshalErr)
+	}
+
+	// index of each context's check and the ID of the status it came from
+	latest := make(map[string]int, len(result.Value))
+	var checks []globalEntities.Check
+	var ids []int
+	for _, status := range result.Value {
+		name := status.Context.Name
+		if status.Context.Genre != "" {
+			name = status.Context.Genre + "/" + name
+		}
+		check := globalEntities.Check{
+			Name:      name,
+			Kind:      globalEntities.CheckKindStatus,
+			State:     adoStatusCheckState(status.State),
+			URL:       status.TargetURL,
+			StartedAt: status.CreationDate,
+		}
+		if check.State != globalEntities.CheckStatePending {
+			check.CompletedAt = status.UpdatedDate
+		}
+
+		index, seen := latest[name]
+		switch {
+		case !seen:
+			latest[name] = len(checks)
+			ids = append(ids, status.ID)
+			checks = append(checks, check)
+		case status.ID > ids[index]:
+			ids[index] = status.ID
+			checks[index] = check
+		}
+	}
+	return checks, nil
+}
+
+func adoPolicyCheckState(status string) globalEntities.CheckState {
+	switch status {
+	case "approved":
+		return globalEntities.CheckStateSuccess
+	case "rejected", "broken":
+		return globalEntities.CheckStateFailure
+	case "notApplicable":
+		return globalEntities.CheckStateSkipped
+	default: // queued, running
+		return globalEntities.CheckStatePending
+	}
+}
+
+func adoStatusCheckState(state string) globalEntities.CheckState {
+	switch state {
+	case "succeeded":
+		return globalEntities.CheckStateSuccess
+	case "failed", "error":
+		return globalEntities.CheckStateFailure
+	case "notApplicable":
+		return globalEntities.CheckStateSkipped
+	default: // pending, notSet
+		return globalEntities.CheckStatePending
+	}
+}
+
 func (p *Provider) MergePullRequest(
 	ctx context.Context,
 	repo globalEntities.Repository,
@@ -878,13 +1076,13 @@ func (p *Provider) MergePullRequest(
 	strategy string,
 	opts ...globalEntities.MergeOption,
 ) error {
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	bypass := globalEntities.ResolveMergeOptions(opts...)
 
 	// first get the PR to obtain the last merge source commit
 	getEndpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	prResp, err := p.doRequest(ctx, baseURL, http.MethodGet, getEndpoint, nil)
@@ -896,6 +1094,9 @@ func (p *Provider) MergePullRequest(
 		LastMergeSourceCommit struct {
 			CommitID string `json:"commitId"`
 		} `json:"lastMergeSourceCommit"`
+		CompletionOptions struct {
+			TransitionWorkItems bool `json:"transitionWorkItems"`
+		} `json:"completionOptions"`
 	}
 	if unmarshalErr := json.Unmarshal(prResp, &prData); unmarshalErr != nil {
 		return fmt.Errorf("failed to parse pull request data: %w", unmarshalErr)
@@ -904,7 +1105,7 @@ func (p *Provider) MergePullRequest(
 	// complete the PR
 	updateEndpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, p.apiVersion(),
 	)
 
 	completionOptions := map[string]any{
@@ -915,6 +1116,10 @@ func (p *Provider) MergePullRequest(
 		completionOptions["bypassPolicy"] = true
 		completionOptions["bypassReason"] = bypass.Reason
 	}
+	// the options sent replace the pull request's, so closing links are kept
+	if prData.CompletionOptions.TransitionWorkItems {
+		completionOptions[jsonKeyTransitionWorkItems] = true
+	}
 	body := map[string]any{
 		jsonKeyStatus:           "completed",
 		"lastMergeSourceCommit": prData.LastMergeSourceCommit,
@@ -1026,10 +1231,10 @@ func (p *Provider) SubmitPullRequestReview(
 		}
 	}
 
-	baseURL := buildBaseURL(repo.Organization)
+	baseURL := p.orgBaseURL(repo.Organization)
 	endpoint := fmt.Sprintf(
 		"/%s/_apis/git/repositories/%s/pullrequests/%d/reviewers/%s?api-version=%s",
-		repo.Project, resolveRepoIdentifier(repo), prID, reviewerID, apiVersion,
+		repo.Project, resolveRepoIdentifier(repo), prID, reviewerID, p.apiVersion(),
 	)
 
 	body := map[string]any{
@@ -1055,16 +1260,16 @@ func (p *Provider) SubmitPullRequestReview(
 func (p *Provider) getReviewerID(ctx context.Context, organization string) (string, error) {
 	once := p.reviewerIDOnceFor(organization)
 	once.Do(func() {
-		baseURL := buildBaseURL(organization)
+		baseURL := p.orgBaseURL(organization)
 		// Azure DevOps marks `/_apis/connectionData` as preview-only — calling it
-		// with `api-version=7.0` (the package-wide constant used everywhere else)
+		// with `api-version=7.0` (the version used everywhere else by default)
 		// returns HTTP 400 with `VssInvalidPreviewVersionException` and the literal
 		// hint "the -preview flag must be supplied". Captured live in dev pod logs
 		// at 2026-05-01 20:52 UTC where every native review submission failed with
 		// `failed to resolve reviewer ID`. The preview suffix is endpoint-specific,
 		// not organisation-specific, so it lives inline rather than as a new
 		// package constant.
-		endpoint := fmt.Sprintf("/_apis/connectionData?api-version=%s-preview.1", apiVersion)
+		endpoint := fmt.Sprintf("/_apis/connectionData?api-version=%s-preview.1", p.apiVersion())
 
 		resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
 		if err != nil {
@@ -0,0 +1,245 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// adoRef is a ref as listed by the refs endpoint. PeeledObjectID is only set
+// for annotated tags, where ObjectID is the tag object rather than the commit.
+type adoRef struct {
+	Name           string `json:"name"`
+	ObjectID       string `json:"objectId"`
+	PeeledObjectID string `json:"peeledObjectId"`
+}
+
+type adoAnnotatedTag struct {
+	Name         string `json:"name"`
+	ObjectID     string `json:"objectId"`
+	Message      string `json:"message"`
+	TaggedObject struct {
+		ObjectID string `json:"objectId"`
+	} `json:"taggedObject"`
+	TaggedBy struct {
+		Name  string    `json:"name"`
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+	} `json:"taggedBy"`
+}
+
+// CreateTag creates the tag on input.Target (or the default branch): an
+// annotated tag through the annotated tags API, a lightweight one as a ref.
+// Azure DevOps records the token's user as the tagger of annotated tags.
+func (p *Provider) CreateTag(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.TagInput,
+) (*globalEntities.Tag, error) {
+	if input.TaggerName != "" || input.TaggerEmail != "" {
+		return nil, fmt.Errorf("Azure DevOps tags the token's user: %w", errors.ErrUnsupported)
+	}
+
+	baseURL := p.orgBaseURL(repo.Organization)
+	commitID, err := p.resolveCommitID(ctx, baseURL, repo, input.Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tag target: %w", err)
+	}
+	commitDate, err := p.getCommitDate(ctx, baseURL, repo, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", commitID, err)
+	}
+
+	if input.Message != "" {
+		_, err = p.createAnnotatedTag(ctx, baseURL, repo, input.Name, commitID, input.Message)
+	} else {
+		err = p.updateRef(ctx, baseURL, repo, "refs/tags/"+input.Name, allZeroObjectID, commitID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag %q: %w", input.Name, err)
+	}
+
+	return &globalEntities.Tag{
+		Name:      input.Name,
+		CommitSHA: commitID,
+		Annotated: input.Message != "",
+		Message:   input.Message,
+		Date:      commitDate,
+	}, nil
+}
+
+// resolveCommitID returns the commit target names: a full commit SHA as is, a
+// branch as its head, and the default branch's head when target is empty.
+func (p *Provider) resolveCommitID(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	target string,
+) (string, error) {
+	if target == "" {
+		return p.getCommitID(ctx, baseURL, repo)
+	}
+	if commitSHAPattern.MatchString(target) {
+		return target, nil
+	}
+
+	refName := ensureRefsPrefix(target)
+	refs, err := p.listRefs(ctx, baseURL, repo, strings.TrimPrefix(refName, "refs/"))
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.Name == refName {
+			return ref.ObjectID, nil
+		}
+	}
+	return "", fmt.Errorf("branch %q not found", target)
+}
+
+// listRefs returns the refs whose name starts with "refs/"+filter, with
+// annotated tags peeled.
+func (p *Provider) listRefs(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	filter string,
+) ([]adoRef, error) {
+	var allRefs []adoRef
+	continuationToken := ""
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/%s/_apis/git/repositories/%s/refs?filter=%s&peelTags=true&api-version=%s",
+			repo.Project, resolveRepoIdentifier(repo), url.QueryEscape(filter), p.apiVersion(),
+		)
+		if continuationToken != "" {
+			endpoint += "&continuationToken=" + continuationToken
+		}
+
+		resp, headers, err := p.doRequestWithHeaders(ctx, baseURL, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Value []adoRef `json:"value"`
+		}
+		if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse refs response: %w", unmarshalErr)
+		}
+		allRefs = append(allRefs, result.Value...)
+
+		continuationToken = headers.Get(paginationHeader)
+		if continuationToken == "" {
+			break
+		}
+	}
+
+	return allRefs, nil
+}
+
+func (p *Provider) createAnnotatedTag(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	tagName, commitID, message string,
+) (*adoAnnotatedTag, error) {
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/annotatedtags?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
+	)
+	body := map[string]any{
+		jsonKeyName:    tagName,
+		"taggedObject": map[string]string{"objectId": commitID},
+		"message":      message,
+	}
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var tag adoAnnotatedTag
+	if unmarshalErr := json.Unmarshal(resp, &tag); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse annotated tag response: %w", unmarshalErr)
+	}
+	return &tag, nil
+}
+
+// updateRef moves ref name from oldObjectID to newObjectID; the all-zero ID
+// on either side creates or deletes the ref. The refs endpoint answers 200
+// even when an update is rejected, so each result's success flag is checked.
+func (p *Provider) updateRef(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	name, oldObjectID, newObjectID string,
+) error {
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/refs?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
+	)
+	body := []map[string]string{{
+		jsonKeyName:   name,
+		"oldObjectId": oldObjectID,
+		"newObjectId": newObjectID,
+	}}
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodPost, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", name, err)
+	}
+
+	var result struct {
+		Value []struct {
+			Success      bool   `json:"success"`
+			UpdateStatus string `json:"updateStatus"`
+		} `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return fmt.Errorf("failed to parse ref update response: %w", unmarshalErr)
+	}
+	for _, update := range result.Value {
+		if !update.Success {
+			return fmt.Errorf("failed to update %s: %s", name, update.UpdateStatus)
+		}
+	}
+	return nil
+}
+
+func (p *Provider) getCommitDate(
+	ctx context.Context,
+	baseURL string,
+	repo globalEntities.Repository,
+	commitID string,
+) (time.Time, error) {
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s/commits/%s?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), commitID, p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var commit struct {
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &commit); unmarshalErr != nil {
+		return time.Time{}, fmt.Errorf("failed to parse commit response: %w", unmarshalErr)
+	}
+	return commit.Committer.Date, nil
+}
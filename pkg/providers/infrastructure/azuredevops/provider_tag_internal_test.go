@@ -0,0 +1,90 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateTagInternal(t *testing.T) {
+	t.Parallel()
+
+	commitHandler := func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"commitId":"` + testCommitID + `","committer":{"date":"2026-03-04T05:06:07Z"}}`))
+	}
+
+	t.Run("should create a lightweight tag as a new ref on the commit", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var refUpdates []map[string]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath+"/commits/"+testCommitID, commitHandler)
+		mux.HandleFunc("POST "+testReposPath+"/refs", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&refUpdates)
+			_, _ = w.Write([]byte(`{"value":[{"success":true,"updateStatus":"succeeded"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		tag, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Target: testCommitID,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]string{{
+			"name": "refs/tags/v1.0.0", "oldObjectId": allZeroObjectID, "newObjectId": testCommitID,
+		}}, refUpdates)
+		assert.Equal(t, &globalEntities.Tag{
+			Name: "v1.0.0", CommitSHA: testCommitID, Date: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		}, tag)
+	})
+
+	t.Run("should create an annotated tag on the default branch head", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"defaultBranch":"refs/heads/main"}`))
+		})
+		mux.HandleFunc("GET "+testReposPath+"/refs", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[{"name":"refs/heads/main","objectId":"` + testCommitID + `"}]}`))
+		})
+		mux.HandleFunc("GET "+testReposPath+"/commits/"+testCommitID, commitHandler)
+		mux.HandleFunc("POST "+testReposPath+"/annotatedtags", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"name":"v1.0.0","objectId":"tag-object","message":"Release 1.0.0"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		tag, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Message: "Release 1.0.0",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"objectId": testCommitID}, body["taggedObject"])
+		assert.True(t, tag.Annotated)
+		assert.Equal(t, testCommitID, tag.CommitSHA)
+	})
+}
@@ -20,6 +20,68 @@ func buildBaseURL(orgName string) string {
 	return "https://dev.azure.com/" + strings.Split(orgName, "/")[0]
 }
 
+// orgBaseURL returns the API root of an organization on Azure DevOps Services, or of a
+// collection on Azure DevOps Server, falling back to the configured collection.
+func (p *Provider) orgBaseURL(orgName string) string {
+	if p.serverURL == "" {
+		return buildBaseURL(orgName)
+	}
+	collection := strings.Split(orgName, "/")[0]
+	if collection == "" {
+		collection = p.collection
+	}
+	return p.serverURL + "/" + collection
+}
+
+// orgURL is the server-aware counterpart of normalizeOrgURL.
+func (p *Provider) orgURL(org string) string {
+	if p.serverURL == "" {
+		return normalizeOrgURL(org)
+	}
+	org = strings.TrimSuffix(org, "/")
+	if strings.HasPrefix(org, "https://") || strings.HasPrefix(org, "http://") {
+		return org
+	}
+	return p.orgBaseURL(org)
+}
+
+// orgName is the server-aware counterpart of extractOrgName: on Azure DevOps Server
+// the organization is the collection segment that follows the virtual directory.
+func (p *Provider) orgName(baseURL string) string {
+	if p.serverURL != "" {
+		if rest, ok := strings.CutPrefix(baseURL, p.serverURL+"/"); ok {
+			return strings.Split(rest, "/")[0]
+		}
+	}
+	return extractOrgName(baseURL)
+}
+
+// serverRoot returns the scheme and host of the Azure DevOps Server URL.
+func (p *Provider) serverRoot() string {
+	parsed, err := url.Parse(p.serverURL)
+	if err != nil {
+		return p.serverURL
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+// serverHostname returns the host name of the Azure DevOps Server, without any port.
+func (p *Provider) serverHostname() string {
+	parsed, err := url.Parse(p.serverURL)
+	if err != nil {
+		return p.serverURL
+	}
+	return parsed.Hostname()
+}
+
+// apiVersion returns the REST api-version sent with every request.
+func (p *Provider) apiVersion() string {
+	if p.version == "" {
+		return defaultAPIVersion
+	}
+	return p.version
+}
+
 func extractOrgName(baseURL string) string {
 	u, err := url.Parse(baseURL)
 	if err != nil {
@@ -0,0 +1,396 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	hookPublisherTFS    = "tfs"
+	hookConsumerWebHook = "webHooks"
+	hookConsumerAction  = "httpRequest"
+
+	// subscriptionIDSeparator joins the IDs of the subscriptions that make
+	// up one webhook.
+	subscriptionIDSeparator = ","
+)
+
+var errWebhookNotFound = errors.New("webhook not found")
+
+// adoWebhookEventTypes maps each webhook event onto the service hook event
+// types it subscribes to. Only the Git publisher events filter on a
+// repository; build and work item events filter on the project alone.
+var adoWebhookEventTypes = map[globalEntities.WebhookEvent][]string{
+	globalEntities.WebhookEventPush:        {"git.push"},
+	globalEntities.WebhookEventPullRequest: {"git.pullrequest.created", "git.pullrequest.updated"},
+	globalEntities.WebhookEventComment:     {"ms.vss-code.git-pullrequest-comment-event"},
+}
+
+type adoSubscription struct {
+	ID              string            `json:"id"`
+	Status          string            `json:"status"`
+	EventType       string            `json:"eventType"`
+	PublisherInputs map[string]string `json:"publisherInputs"`
+	ConsumerInputs  map[string]string `json:"consumerInputs"`
+	CreatedDate     time.Time         `json:"createdDate"`
+}
+
+// adoRepositoryRef holds the GUIDs service hook filters need.
+type adoRepositoryRef struct {
+	ID      string `json:"id"`
+	Project struct {
+		ID string `json:"id"`
+	} `json:"project"`
+}
+
+// CreateWebhook creates a service hook subscription per event type, all
+// posting to input.URL; the webhook's ID joins their IDs. Service hooks send
+// JSON only, and the secret goes out as the basic auth password.
+func (p *Provider) CreateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	eventTypes, err := adoHookEventTypes(input)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := p.getRepositoryRef(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var created []adoSubscription
+	for _, eventType := range eventTypes {
+		subscription, createErr := p.saveSubscription(ctx, repo, "", adoSubscriptionBody(ref, eventType, input))
+		if createErr != nil {
+			// leave no partial webhook behind
+			deleteErr := p.deleteSubscriptions(ctx, repo, created)
+			return nil, errors.Join(fmt.Errorf("failed to create webhook: %w", createErr), deleteErr)
+		}
+		created = append(created, *subscription)
+	}
+	return adoSubscriptionsToWebhook(repo, created), nil
+}
+
+// ListWebhooks returns the webhooks posting the repository's Git events,
+// grouping the subscriptions by URL.
+func (p *Provider) ListWebhooks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Webhook, error) {
+	ref, err := p.getRepositoryRef(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions, err := p.listSubscriptions(ctx, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	byURL := map[string][]adoSubscription{}
+	for _, subscription := range subscriptions {
+		hookURL := subscription.ConsumerInputs["url"]
+		if _, ok := byURL[hookURL]; !ok {
+			urls = append(urls, hookURL)
+		}
+		byURL[hookURL] = append(byURL[hookURL], subscription)
+	}
+
+	webhooks := make([]globalEntities.Webhook, 0, len(urls))
+	for _, hookURL := range urls {
+		webhooks = append(webhooks, *adoSubscriptionsToWebhook(repo, byURL[hookURL]))
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook replaces the subscriptions of the webhook with id: those
+// whose event type is still wanted are updated in place, missing ones are
+// created and the rest deleted. The returned webhook's ID reflects the new
+// set of subscriptions.
+func (p *Provider) UpdateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id string,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	eventTypes, err := adoHookEventTypes(input)
+	if err != nil {
+		return nil, err
+	}
+	ref, err := p.getRepositoryRef(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := p.findSubscriptions(ctx, repo, ref, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []adoSubscription
+	for _, eventType := range eventTypes {
+		subscriptionID := ""
+		if index := slices.IndexFunc(existing, func(s adoSubscription) bool {
+			return s.EventType == eventType
+		}); index >= 0 {
+			subscriptionID = existing[index].ID
+			existing = slices.Delete(existing, index, index+1)
+		}
+
+		subscription, saveErr := p.saveSubscription(
+			ctx, repo, subscriptionID, adoSubscriptionBody(ref, eventType, input),
+		)
+		if saveErr != nil {
+			return nil, fmt.Errorf("failed to update webhook %s: %w", id, saveErr)
+		}
+		saved = append(saved, *subscription)
+	}
+
+	if err = p.deleteSubscriptions(ctx, repo, existing); err != nil {
+		return nil, fmt.Errorf("failed to update webhook %s: %w", id, err)
+	}
+	return adoSubscriptionsToWebhook(repo, saved), nil
+}
+
+func (p *Provider) DeleteWebhook(ctx context.Context, repo globalEntities.Repository, id string) error {
+	var subscriptions []adoSubscription
+	for subscriptionID := range strings.SplitSeq(id, subscriptionIDSeparator) {
+		subscriptions = append(subscriptions, adoSubscription{ID: subscriptionID})
+	}
+	if err := p.deleteSubscriptions(ctx, repo, subscriptions); err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// PingWebhook is unsupported: service hook test notifications are sent from
+// the web UI only.
+func (p *Provider) PingWebhook(_ context.Context, _ globalEntities.Repository, _ string) error {
+	return fmt.Errorf("Azure DevOps cannot ping a service hook: %w", errors.ErrUnsupported)
+}
+
+// ListWebhookDeliveries is unsupported: service hook history is kept per
+// subscription and cannot be resent, so it is left to the web UI.
+func (p *Provider) ListWebhookDeliveries(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ string,
+) ([]globalEntities.WebhookDelivery, error) {
+	return nil, fmt.Errorf("Azure DevOps webhook deliveries: %w", errors.ErrUnsupported)
+}
+
+// RedeliverWebhook is unsupported: service hooks cannot resend a
+// notification.
+func (p *Provider) RedeliverWebhook(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_, _ string,
+) error {
+	return fmt.Errorf("Azure DevOps cannot redeliver a service hook notification: %w", errors.ErrUnsupported)
+}
+
+func (p *Provider) getRepositoryRef(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) (adoRepositoryRef, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf(
+		"/%s/_apis/git/repositories/%s?api-version=%s",
+		repo.Project, resolveRepoIdentifier(repo), p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return adoRepositoryRef{}, fmt.Errorf("failed to get repository: %w", err)
+	}
+
+	var ref adoRepositoryRef
+	if unmarshalErr := json.Unmarshal(resp, &ref); unmarshalErr != nil {
+		return adoRepositoryRef{}, fmt.Errorf("failed to parse repository response: %w", unmarshalErr)
+	}
+	return ref, nil
+}
+
+// listSubscriptions returns the organization's web hook subscriptions that
+// filter on the repository of ref.
+func (p *Provider) listSubscriptions(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	ref adoRepositoryRef,
+) ([]adoSubscription, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	endpoint := fmt.Sprintf(
+		"/_apis/hooks/subscriptions?publisherId=%s&consumerId=%s&api-version=%s",
+		hookPublisherTFS, hookConsumerWebHook, p.apiVersion(),
+	)
+
+	resp, err := p.doRequest(ctx, baseURL, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	var result struct {
+		Value []adoSubscription `json:"value"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse webhooks response: %w", unmarshalErr)
+	}
+
+	subscriptions := make([]adoSubscription, 0, len(result.Value))
+	for _, subscription := range result.Value {
+		if strings.EqualFold(subscription.PublisherInputs["repository"], ref.ID) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+// findSubscriptions returns the subscriptions of the webhook with id.
+func (p *Provider) findSubscriptions(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	ref adoRepositoryRef,
+	id string,
+) ([]adoSubscription, error) {
+	subscriptions, err := p.listSubscriptions(ctx, repo, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []adoSubscription
+	for subscriptionID := range strings.SplitSeq(id, subscriptionIDSeparator) {
+		index := slices.IndexFunc(subscriptions, func(s adoSubscription) bool {
+			return strings.EqualFold(s.ID, subscriptionID)
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("%w: subscription %s", errWebhookNotFound, subscriptionID)
+		}
+		found = append(found, subscriptions[index])
+	}
+	return found, nil
+}
+
+// saveSubscription creates a subscription, or replaces the one with id when
+// it is not empty.
+func (p *Provider) saveSubscription(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id string,
+	body map[string]any,
+) (*adoSubscription, error) {
+	baseURL := p.orgBaseURL(repo.Organization)
+	method := http.MethodPost
+	endpoint := "/_apis/hooks/subscriptions?api-version=" + p.apiVersion()
+	if id != "" {
+		method = http.MethodPut
+		endpoint = fmt.Sprintf("/_apis/hooks/subscriptions/%s?api-version=%s", id, p.apiVersion())
+	}
+
+	resp, err := p.doRequest(ctx, baseURL, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var subscription adoSubscription
+	if unmarshalErr := json.Unmarshal(resp, &subscription); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse subscription response: %w", unmarshalErr)
+	}
+	return &subscription, nil
+}
+
+func (p *Provider) deleteSubscriptions(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	subscriptions []adoSubscription,
+) error {
+	baseURL := p.orgBaseURL(repo.Organization)
+	var errs []error
+	for _, subscription := range subscriptions {
+		endpoint := fmt.Sprintf(
+			"/_apis/hooks/subscriptions/%s?api-version=%s", subscription.ID, p.apiVersion(),
+		)
+		if _, err := p.doRequest(ctx, baseURL, http.MethodDelete, endpoint, nil); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete subscription %s: %w", subscription.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func adoHookEventTypes(input globalEntities.WebhookInput) ([]string, error) {
+	if input.ContentTypeOrDefault() != globalEntities.WebhookContentTypeJSON {
+		return nil, fmt.Errorf("webhook content type %q: %w", input.ContentType, errors.ErrUnsupported)
+	}
+
+	var eventTypes []string
+	for _, event := range input.Events {
+		types, ok := adoWebhookEventTypes[event]
+		if !ok {
+			return nil, fmt.Errorf("webhook event %q: %w", event, errors.ErrUnsupported)
+		}
+		eventTypes = append(eventTypes, types...)
+	}
+	return eventTypes, nil
+}
+
+func adoSubscriptionBody(ref adoRepositoryRef, eventType string, input globalEntities.WebhookInput) map[string]any {
+	consumerInputs := map[string]string{"url": input.URL}
+	if input.Secret != "" {
+		consumerInputs["basicAuthPassword"] = input.Secret
+	}
+
+	return map[string]any{
+		"publisherId":      hookPublisherTFS,
+		"eventType":        eventType,
+		"consumerId":       hookConsumerWebHook,
+		"consumerActionId": hookConsumerAction,
+		"publisherInputs": map[string]string{
+			"projectId":  ref.Project.ID,
+			"repository": ref.ID,
+		},
+		"consumerInputs": consumerInputs,
+	}
+}
+
+// adoSubscriptionsToWebhook merges the subscriptions of one webhook. It is
+// active only while all of them are.
+func adoSubscriptionsToWebhook(
+	repo globalEntities.Repository,
+	subscriptions []adoSubscription,
+) *globalEntities.Webhook {
+	webhook := &globalEntities.Webhook{
+		Repository:  repo,
+		ContentType: globalEntities.WebhookContentTypeJSON,
+		Active:      true,
+	}
+
+	ids := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		ids = append(ids, subscription.ID)
+		webhook.URL = subscription.ConsumerInputs["url"]
+		// "onProbation" subscriptions still deliver while Azure DevOps
+		// retries their failures
+		if subscription.Status != "enabled" && subscription.Status != "onProbation" {
+			webhook.Active = false
+		}
+		if webhook.CreatedAt.IsZero() || subscription.CreatedDate.Before(webhook.CreatedAt) {
+			webhook.CreatedAt = subscription.CreatedDate
+		}
+		for event, types := range adoWebhookEventTypes {
+			if slices.Contains(types, subscription.EventType) && !slices.Contains(webhook.Events, event) {
+				webhook.Events = append(webhook.Events, event)
+			}
+		}
+	}
+	webhook.ID = strings.Join(ids, subscriptionIDSeparator)
+	slices.Sort(webhook.Events)
+	return webhook
+}
@@ -0,0 +1,175 @@
+package azuredevops
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const testSubscriptionsPath = "/my-org/_apis/hooks/subscriptions"
+
+func repositoryRefHandler(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(`{"id":"repo-guid","project":{"id":"project-guid"}}`))
+}
+
+func TestCreateWebhookInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create a subscription per event type on the repository", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var mu sync.Mutex
+		var bodies []map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath, repositoryRefHandler)
+		mux.HandleFunc("POST "+testSubscriptionsPath, func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			bodies = append(bodies, body)
+			eventType, _ := body["eventType"].(string)
+			_, _ = w.Write([]byte(`{"id":"sub-` + eventType + `","status":"enabled","eventType":"` + eventType + `",
+				"consumerInputs":{"url":"https://bot.example.com/hook"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		webhook, err := p.CreateWebhook(context.Background(), repo, globalEntities.WebhookInput{
+			URL:    "https://bot.example.com/hook",
+			Events: []globalEntities.WebhookEvent{globalEntities.WebhookEventPullRequest},
+			Secret: "s3cret",
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, bodies, 2)
+		assert.Equal(t, map[string]any{
+			"publisherId":      "tfs",
+			"eventType":        "git.pullrequest.created",
+			"consumerId":       "webHooks",
+			"consumerActionId": "httpRequest",
+			"publisherInputs":  map[string]any{"projectId": "project-guid", "repository": "repo-guid"},
+			"consumerInputs":   map[string]any{"url": "https://bot.example.com/hook", "basicAuthPassword": "s3cret"},
+		}, bodies[0])
+		assert.Equal(t, "git.pullrequest.updated", bodies[1]["eventType"])
+		assert.Equal(t, &globalEntities.Webhook{
+			ID:          "sub-git.pullrequest.created,sub-git.pullrequest.updated",
+			Repository:  repo,
+			URL:         "https://bot.example.com/hook",
+			Events:      []globalEntities.WebhookEvent{globalEntities.WebhookEventPullRequest},
+			ContentType: globalEntities.WebhookContentTypeJSON,
+			Active:      true,
+		}, webhook)
+	})
+}
+
+func TestListWebhooksInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should group the repository's subscriptions by URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath, repositoryRefHandler)
+		mux.HandleFunc("GET "+testSubscriptionsPath, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[
+				{"id":"a","status":"enabled","eventType":"git.push",
+					"publisherInputs":{"repository":"repo-guid"},"consumerInputs":{"url":"https://one.example.com"}},
+				{"id":"b","status":"disabledBySystem","eventType":"ms.vss-code.git-pullrequest-comment-event",
+					"publisherInputs":{"repository":"repo-guid"},"consumerInputs":{"url":"https://one.example.com"}},
+				{"id":"c","status":"enabled","eventType":"git.push",
+					"publisherInputs":{"repository":"other-guid"},"consumerInputs":{"url":"https://one.example.com"}},
+				{"id":"d","status":"enabled","eventType":"git.pullrequest.updated",
+					"publisherInputs":{"repository":"repo-guid"},"consumerInputs":{"url":"https://two.example.com"}}
+			]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		webhooks, err := p.ListWebhooks(context.Background(), repo)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, webhooks, 2)
+		assert.Equal(t, "a,b", webhooks[0].ID)
+		assert.Equal(t, []globalEntities.WebhookEvent{
+			globalEntities.WebhookEventComment, globalEntities.WebhookEventPush,
+		}, webhooks[0].Events)
+		assert.False(t, webhooks[0].Active)
+		assert.Equal(t, "d", webhooks[1].ID)
+		assert.Equal(t, "https://two.example.com", webhooks[1].URL)
+		assert.True(t, webhooks[1].Active)
+	})
+}
+
+func TestUpdateWebhookInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should keep, create and delete subscriptions to match the events", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var mu sync.Mutex
+		var calls []string
+		record := func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, r.Method+" "+r.PathValue("id"))
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			eventType, _ := body["eventType"].(string)
+			_, _ = w.Write([]byte(`{"id":"new","status":"enabled","eventType":"` + eventType + `"}`))
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET "+testReposPath, repositoryRefHandler)
+		mux.HandleFunc("GET "+testSubscriptionsPath, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"value":[
+				{"id":"a","eventType":"git.push","publisherInputs":{"repository":"repo-guid"}},
+				{"id":"b","eventType":"ms.vss-code.git-pullrequest-comment-event",
+					"publisherInputs":{"repository":"repo-guid"}}
+			]}`))
+		})
+		mux.HandleFunc("POST "+testSubscriptionsPath, record)
+		mux.HandleFunc("PUT "+testSubscriptionsPath+"/{id}", record)
+		mux.HandleFunc("DELETE "+testSubscriptionsPath+"/{id}", record)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Project: "my-project", Name: "my-repo", ID: "repo-id"}
+
+		// when
+		webhook, err := p.UpdateWebhook(context.Background(), repo, "a,b", globalEntities.WebhookInput{
+			URL: "https://bot.example.com/hook",
+			Events: []globalEntities.WebhookEvent{
+				globalEntities.WebhookEventPush, globalEntities.WebhookEventPullRequest,
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"PUT a", "POST ", "POST ", "DELETE b"}, calls)
+		assert.Equal(t, []globalEntities.WebhookEvent{
+			globalEntities.WebhookEventPullRequest, globalEntities.WebhookEventPush,
+		}, webhook.Events)
+	})
+}
@@ -0,0 +1,465 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// newTestProvider creates a Provider pointed at the given test server.
+func newTestProvider(t *testing.T, server *httptest.Server) *Provider {
+	t.Helper()
+	return &Provider{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: server.Client(),
+	}
+}
+
+func TestDiscoverRepositoriesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should follow next links when the workspace spans several pages", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var serverURL string
+		var authHeader string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace", func(w http.ResponseWriter, r *http.Request) {
+			authHeader = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("page") == "2" {
+				_, _ = w.Write([]byte(`{"values":[{"uuid":"{2}","slug":"fork-repo",` +
+					`"parent":{"full_name":"upstream/fork-repo"}}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"values":[{"uuid":"{1}","slug":"my-repo","mainbranch":{"name":"develop"},` +
+				`"links":{"clone":[{"name":"https","href":"https://jdoe@bitbucket.org/my-workspace/my-repo.git"},` +
+				`{"name":"ssh","href":"git@bitbucket.org:my-workspace/my-repo.git"}]}}],` +
+				`"next":"` + serverURL + `/repositories/my-workspace?page=2"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+		serverURL = server.URL
+
+		p := newTestProvider(t, server)
+
+		// when
+		repos, err := p.DiscoverRepositories(context.Background(), "my-workspace")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, repos, 2)
+		assert.Equal(t, "Bearer test-token", authHeader)
+		assert.Equal(t, "{1}", repos[0].ID)
+		assert.Equal(t, "my-repo", repos[0].Name)
+		assert.Equal(t, "my-workspace", repos[0].Organization)
+		assert.Equal(t, "refs/heads/develop", repos[0].DefaultBranch)
+		assert.Equal(t, "https://bitbucket.org/my-workspace/my-repo.git", repos[0].RemoteURL)
+		assert.Equal(t, "git@bitbucket.org:my-workspace/my-repo.git", repos[0].SSHURL)
+		assert.Equal(t, "bitbucket", repos[0].ProviderName)
+		assert.False(t, repos[0].IsFork)
+		assert.Equal(t, "refs/heads/main", repos[1].DefaultBranch)
+		assert.True(t, repos[1].IsFork)
+	})
+
+	t.Run("should send basic auth when the token is an app password", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var username, password string
+		var hasBasic bool
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace", func(w http.ResponseWriter, r *http.Request) {
+			username, password, hasBasic = r.BasicAuth()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"values":[]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		p.token = "jdoe:app-secret"
+
+		// when
+		repos, err := p.DiscoverRepositories(context.Background(), "my-workspace")
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, repos)
+		assert.True(t, hasBasic)
+		assert.Equal(t, "jdoe", username)
+		assert.Equal(t, "app-secret", password)
+	})
+}
+
+func TestCreatePullRequestInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create a pull request between the given branches", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var capturedBody map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repositories/my-workspace/my-repo/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+			defer func() { _ = r.Body.Close() }()
+			_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":7,"title":"Bump","state":"OPEN",` +
+				`"links":{"html":{"href":"https://bitbucket.org/my-workspace/my-repo/pull-requests/7"}}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		pr, err := p.CreatePullRequest(context.Background(), repo, globalEntities.PullRequestInput{
+			SourceBranch: "refs/heads/chore/bump",
+			TargetBranch: "refs/heads/main",
+			Title:        "Bump",
+			Description:  "Bumps the version",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 7, pr.ID)
+		assert.Equal(t, "OPEN", pr.Status)
+		assert.Equal(t, "https://bitbucket.org/my-workspace/my-repo/pull-requests/7", pr.URL)
+		assert.Equal(t, map[string]any{"branch": map[string]any{"name": "chore/bump"}}, capturedBody["source"])
+		assert.Equal(t, map[string]any{"branch": map[string]any{"name": "main"}}, capturedBody["destination"])
+		assert.Equal(t, "Bumps the version", capturedBody["description"])
+	})
+}
+
+func TestPullRequestExistsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should query open pull requests by source branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var capturedQuery, capturedState string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/pullrequests", func(w http.ResponseWriter, r *http.Request) {
+			capturedQuery = r.URL.Query().Get("q")
+			capturedState = r.URL.Query().Get("state")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"values":[{"id":3,"source":{"branch":{"name":"chore/bump"}}}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		exists, err := p.PullRequestExists(context.Background(), repo, "refs/heads/chore/bump")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, `source.branch.name="chore/bump"`, capturedQuery)
+		assert.Equal(t, "OPEN", capturedState)
+	})
+}
+
+func TestClosePullRequestInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decline the open pull request when one exists for the branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		declined := false
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/pullrequests", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"values":[{"id":5,"source":{"branch":{"name":"chore/bump-1.2.3"}}}]}`))
+		})
+		mux.HandleFunc("POST /repositories/my-workspace/my-repo/pullrequests/5/decline",
+			func(w http.ResponseWriter, _ *http.Request) {
+				declined = true
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":5,"state":"DECLINED"}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		closed, err := p.ClosePullRequest(context.Background(), repo, "chore/bump-1.2.3")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, closed)
+		assert.True(t, declined)
+	})
+
+	t.Run("should report nothing closed when no pull request matches the branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		declined := false
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/pullrequests", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"values":[]}`))
+		})
+		mux.HandleFunc("POST /repositories/my-workspace/my-repo/pullrequests/", func(w http.ResponseWriter, _ *http.Request) {
+			declined = true
+			w.WriteHeader(http.StatusOK)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		closed, err := p.ClosePullRequest(context.Background(), repo, "chore/bump-1.2.3")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, closed)
+		assert.False(t, declined, "no pull request should be declined when none matches the branch")
+	})
+}
+
+func TestGetFileContentInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should read the file from the default branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/src/develop/docs/README.md",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("# Hello\n"))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{
+			Organization:  "my-workspace",
+			Name:          "my-repo",
+			DefaultBranch: "refs/heads/develop",
+		}
+
+		// when
+		content, err := p.GetFileContent(context.Background(), repo, "docs/README.md")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "# Hello\n", content)
+	})
+
+	t.Run("should resolve the main branch when the repository has no default branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"slug":"my-repo","mainbranch":{"name":"trunk"}}`))
+		})
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/src/trunk/go.mod",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("module example.com/x\n"))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		content, err := p.GetFileContent(context.Background(), repo, "go.mod")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "module example.com/x\n", content)
+	})
+
+	t.Run("should report a missing file through HasFile", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/src/main/missing.txt",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{
+			Organization:  "my-workspace",
+			Name:          "my-repo",
+			DefaultBranch: "refs/heads/main",
+		}
+
+		// when
+		found := p.HasFile(context.Background(), repo, "missing.txt")
+
+		// then
+		assert.False(t, found)
+	})
+}
+
+func TestListFilesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return entries matching the pattern suffix", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/src/main/", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"values":[` +
+				`{"path":"go.mod","type":"commit_file","commit":{"hash":"abc"}},` +
+				`{"path":"cmd","type":"commit_directory","commit":{"hash":"abc"}},` +
+				`{"path":"cmd/tool/go.mod","type":"commit_file","commit":{"hash":"abc"}}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{
+			Organization:  "my-workspace",
+			Name:          "my-repo",
+			DefaultBranch: "refs/heads/main",
+		}
+
+		// when
+		files, err := p.ListFiles(context.Background(), repo, "go.mod")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Equal(t, "go.mod", files[0].Path)
+		assert.Empty(t, files[0].ObjectID)
+		assert.False(t, files[0].IsDir)
+		assert.Equal(t, "cmd/tool/go.mod", files[1].Path)
+	})
+}
+
+func TestGetTagsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return tags sorted by version descending", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/refs/tags", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"values":[{"name":"1.2.0"},{"name":"1.10.0"},{"name":"1.9.1"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		tags, err := p.GetTags(context.Background(), repo)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.10.0", "1.9.1", "1.2.0"}, tags)
+	})
+}
+
+func TestCreateBranchWithChangesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should commit all changes in a single form post on top of the base branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var form map[string][]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/refs/branches/main",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"name":"main","target":{"hash":"deadbeef"}}`))
+			})
+		mux.HandleFunc("POST /repositories/my-workspace/my-repo/src", func(w http.ResponseWriter, r *http.Request) {
+			if err := r.ParseMultipartForm(1 << 20); err == nil {
+				form = r.MultipartForm.Value
+			}
+			w.WriteHeader(http.StatusCreated)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		err := p.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName:    "chore/bump",
+			BaseBranch:    "refs/heads/main",
+			CommitMessage: "chore: bump",
+			Changes: []globalEntities.FileChange{
+				{Path: "/VERSION", Content: "1.2.3\n", ChangeType: "edit"},
+				{Path: "old.txt", ChangeType: "delete"},
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, form)
+		assert.Equal(t, []string{"chore/bump"}, form["branch"])
+		assert.Equal(t, []string{"chore: bump"}, form["message"])
+		assert.Equal(t, []string{"deadbeef"}, form["parents"])
+		assert.Equal(t, []string{"1.2.3\n"}, form["VERSION"])
+		assert.Equal(t, []string{"old.txt"}, form["files"])
+	})
+
+	t.Run("should return error when a change type is unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repositories/my-workspace/my-repo/refs/branches/main",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"name":"main","target":{"hash":"deadbeef"}}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-workspace", Name: "my-repo"}
+
+		// when
+		err := p.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName: "chore/bump",
+			BaseBranch: "main",
+			Changes:    []globalEntities.FileChange{{Path: "a.txt", ChangeType: "rename"}},
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported change type "rename"`)
+	})
+}
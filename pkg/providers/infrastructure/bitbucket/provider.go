@@ -0,0 +1,140 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gohttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	providerName    = "bitbucket"
+	defaultBaseURL  = "https://api.bitbucket.org/2.0"
+	webHost         = "bitbucket.org"
+	perPage         = 100
+	httpTimeout     = 30 * time.Second
+	httpStatusOKMin = 200
+	httpStatusOKMax = 300
+
+	// accessTokenUsername is the fixed git username Bitbucket expects when a
+	// repository, project or workspace access token is used over HTTPS.
+	accessTokenUsername = "x-token-auth"
+)
+
+// Provider implements ForgeProvider, FileAccessProvider, and LocalGitAuthProvider for Bitbucket Cloud.
+//
+// The token is either an access token (repository, project or workspace
+// scoped), sent as a Bearer token, or an app password written as
+// "username:app_password", sent as HTTP Basic credentials.
+type Provider struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewProvider creates a new Bitbucket Cloud provider with the given access
+// token or "username:app_password" pair.
+func NewProvider(token string) globalEntities.ForgeProvider {
+	return NewProviderWithClient(token, nil)
+}
+
+// NewProviderWithClient creates a new Bitbucket Cloud provider with the given
+// credentials and an explicit HTTP client. If client is nil, a default client
+// with the standard timeout is used. This is primarily intended for testing.
+func NewProviderWithClient(token string, client *http.Client) globalEntities.ForgeProvider {
+	if client == nil {
+		client = &http.Client{
+			Timeout: httpTimeout,
+		}
+	}
+
+	return &Provider{
+		token:      token,
+		baseURL:    defaultBaseURL,
+		httpClient: client,
+	}
+}
+
+func (p *Provider) Name() string      { return providerName }
+func (p *Provider) AuthToken() string { return p.token }
+
+func (p *Provider) MatchesURL(rawURL string) bool {
+	return strings.Contains(rawURL, webHost)
+}
+
+// appPassword splits an app-password token into its username and password.
+// The boolean is false when the token is a plain access token.
+func (p *Provider) appPassword() (string, string, bool) {
+	return strings.Cut(p.token, ":")
+}
+
+// gitCredentials returns the username and password used for HTTPS git
+// operations with the configured token.
+func (p *Provider) gitCredentials() (string, string) {
+	if username, password, ok := p.appPassword(); ok {
+		return username, password
+	}
+	return accessTokenUsername, p.token
+}
+
+// --- LocalGitAuthProvider ---
+
+func (p *Provider) GetServiceType() globalEntities.ServiceType {
+	return globalEntities.BITBUCKET
+}
+
+func (p *Provider) PrepareCloneURL(cloneURL string) string {
+	return cloneURL
+}
+
+func (p *Provider) ConfigureTransport() {
+	// Bitbucket doesn't need special transport configuration
+}
+
+func (p *Provider) GetAuthMethods(_ string) []transport.AuthMethod {
+	var authMethods []transport.AuthMethod
+
+	if p.token != "" {
+		username, password := p.gitCredentials()
+		log.Infof("Using access token to authenticate with Bitbucket")
+		authMethods = append(authMethods, &gohttp.BasicAuth{
+			Username: username,
+			Password: password,
+		})
+	}
+
+	return authMethods
+}
+
+func (p *Provider) CloneURL(repo globalEntities.Repository) string {
+	remoteURL := repo.RemoteURL
+	if remoteURL == "" {
+		remoteURL = fmt.Sprintf(
+			"https://%s/%s/%s.git",
+			webHost, repo.Organization, repo.Name,
+		)
+	}
+
+	parsed, err := url.Parse(remoteURL)
+	if err != nil {
+		return remoteURL
+	}
+
+	parsed.User = url.UserPassword(p.gitCredentials())
+
+	return parsed.String()
+}
+
+func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
+	host := webHost
+	if sshAlias != "" {
+		host = fmt.Sprintf("%s-%s", webHost, sshAlias)
+	}
+	return fmt.Sprintf("git@%s:%s/%s.git", host, repo.Organization, repo.Name)
+}
@@ -0,0 +1,93 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+type bitbucketLink struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+type bitbucketRepo struct {
+	UUID       string `json:"uuid"`
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	FullName   string `json:"full_name"`
+	IsPrivate  bool   `json:"is_private"`
+	Mainbranch *struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+	Parent *struct {
+		FullName string `json:"full_name"`
+	} `json:"parent"`
+	Links struct {
+		Clone []bitbucketLink `json:"clone"`
+	} `json:"links"`
+}
+
+// DiscoverRepositories lists every repository in the given workspace.
+func (p *Provider) DiscoverRepositories(
+	ctx context.Context,
+	org string,
+) ([]globalEntities.Repository, error) {
+	endpoint := fmt.Sprintf(
+		"/repositories/%s?pagelen=%d",
+		url.PathEscape(org), perPage,
+	)
+
+	repos, err := listAll[bitbucketRepo](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover workspace repos for %q: %w", org, err)
+	}
+
+	allRepos := make([]globalEntities.Repository, 0, len(repos))
+	for _, r := range repos {
+		allRepos = append(allRepos, bitbucketRepoToDomain(r, org))
+	}
+
+	return allRepos, nil
+}
+
+func bitbucketRepoToDomain(r bitbucketRepo, workspace string) globalEntities.Repository {
+	defaultBranch := "main"
+	if r.Mainbranch != nil && r.Mainbranch.Name != "" {
+		defaultBranch = r.Mainbranch.Name
+	}
+
+	var remoteURL, sshURL string
+	for _, link := range r.Links.Clone {
+		switch link.Name {
+		case "https":
+			remoteURL = stripUserInfo(link.Href)
+		case "ssh":
+			sshURL = link.Href
+		}
+	}
+
+	return globalEntities.Repository{
+		ID:            r.UUID,
+		Name:          r.Slug,
+		Organization:  workspace,
+		DefaultBranch: "refs/heads/" + defaultBranch,
+		RemoteURL:     remoteURL,
+		SSHURL:        sshURL,
+		ProviderName:  providerName,
+		IsFork:        r.Parent != nil,
+	}
+}
+
+// stripUserInfo removes the "user@" part Bitbucket embeds in HTTPS clone links
+// so that CloneURL can inject the configured credentials instead.
+func stripUserInfo(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	parsed.User = nil
+	return parsed.String()
+}
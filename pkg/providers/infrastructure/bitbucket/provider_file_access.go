@@ -0,0 +1,234 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+)
+
+// maxTreeDepth bounds how many nested directory levels ListFiles asks the
+// `/src` endpoint to expand in a single listing.
+const maxTreeDepth = 64
+
+type bitbucketTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type bitbucketRef struct {
+	Name   string `json:"name"`
+	Target struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+func (p *Provider) GetFileContent(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	path string,
+) (string, error) {
+	ref, err := p.resolveRef(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/repositories/%s/%s/src/%s/%s",
+		repo.Organization, repo.Name, url.PathEscape(ref), strings.TrimPrefix(path, "/"),
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file %q: %w", path, err)
+	}
+
+	return string(resp), nil
+}
+
+// ListFiles lists the files and directories of the default branch. The `/src`
+// listing only carries the commit each entry was read at, not a blob ID, so
+// File.ObjectID is left empty.
+func (p *Provider) ListFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	pattern string,
+) ([]globalEntities.File, error) {
+	ref, err := p.resolveRef(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/repositories/%s/%s/src/%s/?max_depth=%d&pagelen=%d",
+		repo.Organization, repo.Name, url.PathEscape(ref), maxTreeDepth, perPage,
+	)
+
+	entries, err := listAll[bitbucketTreeEntry](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repo tree: %w", err)
+	}
+
+	var files []globalEntities.File
+	for _, entry := range entries {
+		if pattern != "" && !strings.HasSuffix(entry.Path, pattern) {
+			continue
+		}
+		files = append(files, globalEntities.File{
+			Path:  entry.Path,
+			IsDir: entry.Type == "commit_directory",
+		})
+	}
+
+	return files, nil
+}
+
+func (p *Provider) GetTags(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]string, error) {
+	endpoint := fmt.Sprintf(
+		"/repositories/%s/%s/refs/tags?pagelen=%d",
+		repo.Organization, repo.Name, perPage,
+	)
+
+	tags, err := listAll[bitbucketRef](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	allTags := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		allTags = append(allTags, tag.Name)
+	}
+
+	globalHelpers.SortVersionsDescending(allTags)
+	return allTags, nil
+}
+
+func (p *Provider) HasFile(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	path string,
+) bool {
+	_, err := p.GetFileContent(ctx, repo, path)
+	return err == nil
+}
+
+// CreateBranchWithChanges creates the branch and its commit in a single
+// form POST to the `/src` endpoint. Bitbucket creates the branch when it does
+// not exist yet, using the `parents` commit as its starting point.
+func (p *Provider) CreateBranchWithChanges(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.BranchInput,
+) error {
+	baseBranch := strings.TrimPrefix(input.BaseBranch, "refs/heads/")
+
+	parent, err := p.getBranchHead(ctx, repo, baseBranch)
+	if err != nil {
+		return fmt.Errorf("failed to resolve base branch %q: %w", baseBranch, err)
+	}
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+
+	fields := [][2]string{
+		{"branch", input.BranchName},
+		{"message", input.CommitMessage},
+		{"parents", parent},
+	}
+
+	for _, change := range input.Changes {
+		filePath := strings.TrimPrefix(change.Path, "/")
+		changeType := strings.ToLower(strings.TrimSpace(change.ChangeType))
+
+		// deleted paths are listed under the repeated "files" field
+		if changeType == "delete" {
+			fields = append(fields, [2]string{"files", filePath})
+			continue
+		}
+
+		// treat unknown, non-empty change types as an error
+		if changeType != "" && changeType != "add" && changeType != "edit" && changeType != "create" &&
+			changeType != "update" {
+			return fmt.Errorf("unsupported change type %q for file %q", change.ChangeType, filePath)
+		}
+
+		// default behavior: the field name is the path, the value its content
+		fields = append(fields, [2]string{filePath, change.Content})
+	}
+
+	for _, field := range fields {
+		if writeErr := form.WriteField(field[0], field[1]); writeErr != nil {
+			return fmt.Errorf("failed to write form field %q: %w", field[0], writeErr)
+		}
+	}
+	if closeErr := form.Close(); closeErr != nil {
+		return fmt.Errorf("failed to finalize form: %w", closeErr)
+	}
+
+	endpoint := fmt.Sprintf("/repositories/%s/%s/src", repo.Organization, repo.Name)
+	if _, sendErr := p.send(
+		ctx, http.MethodPost, endpoint, form.FormDataContentType(), &buf,
+	); sendErr != nil {
+		return fmt.Errorf("failed to create commit on branch %q: %w", input.BranchName, sendErr)
+	}
+
+	return nil
+}
+
+// resolveRef returns the branch name used for `/src` reads. It falls back to
+// the repository's main branch when the repository carries no default branch.
+func (p *Provider) resolveRef(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) (string, error) {
+	if branch := strings.TrimPrefix(repo.DefaultBranch, "refs/heads/"); branch != "" {
+		return branch, nil
+	}
+
+	endpoint := fmt.Sprintf("/repositories/%s/%s", repo.Organization, repo.Name)
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository %q: %w", repo.Name, err)
+	}
+
+	var r bitbucketRepo
+	if unmarshalErr := json.Unmarshal(resp, &r); unmarshalErr != nil {
+		return "", fmt.Errorf("failed to parse repository response: %w", unmarshalErr)
+	}
+
+	return strings.TrimPrefix(bitbucketRepoToDomain(r, repo.Organization).DefaultBranch, "refs/heads/"), nil
+}
+
+// getBranchHead returns the commit hash the given branch points at.
+func (p *Provider) getBranchHead(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	branch string,
+) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/repositories/%s/%s/refs/branches/%s",
+		repo.Organization, repo.Name, url.PathEscape(branch),
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var ref bitbucketRef
+	if unmarshalErr := json.Unmarshal(resp, &ref); unmarshalErr != nil {
+		return "", fmt.Errorf("failed to parse branch response: %w", unmarshalErr)
+	}
+
+	return ref.Target.Hash, nil
+}
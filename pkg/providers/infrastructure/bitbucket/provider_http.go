@@ -0,0 +1,124 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// apiError represents an HTTP API error with the status code preserved.
+type apiError struct {
+	statusCode int
+	body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.statusCode, e.body)
+}
+
+// StatusCode returns the HTTP status code of the failed request.
+func (e *apiError) StatusCode() int {
+	return e.statusCode
+}
+
+// page is the envelope Bitbucket wraps every paginated collection in. `Next`
+// is the absolute URL of the following page and is empty on the last one.
+type page[T any] struct {
+	Values []T    `json:"values"`
+	Next   string `json:"next"`
+}
+
+// doRequest sends a JSON request to the Bitbucket API. endpoint is either a
+// path relative to the API base URL or an absolute URL, such as the `next`
+// link of a paginated response.
+func (p *Provider) doRequest(
+	ctx context.Context,
+	method, endpoint string,
+	body any,
+) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, marshalErr := json.Marshal(body)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", marshalErr)
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	return p.send(ctx, method, endpoint, "application/json", reqBody)
+}
+
+// send performs the HTTP round-trip with authentication and turns non-2xx
+// responses into *apiError.
+func (p *Provider) send(
+	ctx context.Context,
+	method, endpoint, contentType string,
+	reqBody io.Reader,
+) ([]byte, error) {
+	fullURL := endpoint
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		fullURL = p.baseURL + endpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if username, password, ok := p.appPassword(); ok {
+		req.SetBasicAuth(username, password)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < httpStatusOKMin || resp.StatusCode >= httpStatusOKMax {
+		return nil, &apiError{
+			statusCode: resp.StatusCode,
+			body:       string(respBody),
+		}
+	}
+
+	return respBody, nil
+}
+
+// listAll walks every page of a paginated collection starting at endpoint.
+func listAll[T any](ctx context.Context, p *Provider, endpoint string) ([]T, error) {
+	var all []T
+	next := endpoint
+
+	for next != "" {
+		resp, err := p.doRequest(ctx, http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var pg page[T]
+		if unmarshalErr := json.Unmarshal(resp, &pg); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse paginated response: %w", unmarshalErr)
+		}
+
+		all = append(all, pg.Values...)
+		next = pg.Next
+	}
+
+	return all, nil
+}
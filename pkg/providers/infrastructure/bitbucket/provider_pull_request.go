@@ -0,0 +1,137 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+type bitbucketBranchRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+}
+
+type bitbucketPR struct {
+	ID          int                `json:"id"`
+	Title       string             `json:"title"`
+	State       string             `json:"state"`
+	Source      bitbucketBranchRef `json:"source"`
+	Destination bitbucketBranchRef `json:"destination"`
+	Links       struct {
+		HTML bitbucketLink `json:"html"`
+	} `json:"links"`
+}
+
+func (p *Provider) CreatePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PullRequestInput,
+) (*globalEntities.PullRequest, error) {
+	sourceBranch := strings.TrimPrefix(input.SourceBranch, "refs/heads/")
+	targetBranch := strings.TrimPrefix(input.TargetBranch, "refs/heads/")
+
+	endpoint := fmt.Sprintf(
+		"/repositories/%s/%s/pullrequests",
+		repo.Organization, repo.Name,
+	)
+
+	body := map[string]any{
+		"title":       input.Title,
+		"description": input.Description,
+		"source": map[string]any{
+			"branch": map[string]string{"name": sourceBranch},
+		},
+		"destination": map[string]any{
+			"branch": map[string]string{"name": targetBranch},
+		},
+		"close_source_branch": true,
+	}
+
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	var pr bitbucketPR
+	if unmarshalErr := json.Unmarshal(resp, &pr); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request response: %w", unmarshalErr)
+	}
+
+	return &globalEntities.PullRequest{
+		ID:     pr.ID,
+		Title:  pr.Title,
+		URL:    pr.Links.HTML.Href,
+		Status: pr.State,
+	}, nil
+}
+
+// findOpenPullRequestID returns the ID of the open pull request whose source branch
+// is sourceBranch. The boolean reports whether such a pull request was found.
+func (p *Provider) findOpenPullRequestID(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (int, bool, error) {
+	branch := strings.TrimPrefix(sourceBranch, "refs/heads/")
+	query := fmt.Sprintf("source.branch.name=%q", branch)
+
+	endpoint := fmt.Sprintf(
+		"/repositories/%s/%s/pullrequests?state=OPEN&pagelen=%d&q=%s",
+		repo.Organization, repo.Name, perPage, url.QueryEscape(query),
+	)
+
+	prs, err := listAll[bitbucketPR](ctx, p, endpoint)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	for _, pr := range prs {
+		if pr.Source.Branch.Name == branch {
+			return pr.ID, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func (p *Provider) PullRequestExists(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	_, found, err := p.findOpenPullRequestID(ctx, repo, sourceBranch)
+	return found, err
+}
+
+// ClosePullRequest declines the open pull request for sourceBranch, which is
+// how Bitbucket closes a pull request without merging it.
+func (p *Provider) ClosePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	id, found, err := p.findOpenPullRequestID(ctx, repo, sourceBranch)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+
+	endpoint := fmt.Sprintf(
+		"/repositories/%s/%s/pullrequests/%d/decline",
+		repo.Organization, repo.Name, id,
+	)
+
+	if _, declineErr := p.doRequest(ctx, http.MethodPost, endpoint, nil); declineErr != nil {
+		return false, fmt.Errorf("failed to decline pull request %d: %w", id, declineErr)
+	}
+
+	return true, nil
+}
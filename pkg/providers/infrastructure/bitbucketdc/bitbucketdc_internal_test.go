@@ -0,0 +1,548 @@
+package bitbucketdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// newTestProvider creates a Provider pointed at the given test server.
+func newTestProvider(t *testing.T, server *httptest.Server) *Provider {
+	t.Helper()
+	return &Provider{
+		token:      "test-token",
+		baseURL:    server.URL,
+		httpClient: server.Client(),
+	}
+}
+
+func testRepo() globalEntities.Repository {
+	return globalEntities.Repository{Organization: "PROJ", Name: "my-repo"}
+}
+
+func TestDiscoverRepositoriesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should walk every page of the project and resolve default branches", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var authHeader string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos", func(w http.ResponseWriter, r *http.Request) {
+			authHeader = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("start") == "1" {
+				_, _ = w.Write([]byte(`{"values":[{"id":2,"slug":"fork-repo","archived":true,` +
+					`"origin":{"slug":"upstream"}}],"isLastPage":true}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"values":[{"id":1,"slug":"my-repo","project":{"key":"PROJ"},` +
+				`"links":{"clone":[{"name":"http","href":"https://jdoe@bitbucket.corp.example/scm/proj/my-repo.git"},` +
+				`{"name":"ssh","href":"ssh://git@bitbucket.corp.example:7999/proj/my-repo.git"}]}}],` +
+				`"isLastPage":false,"nextPageStart":1}`))
+		})
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/branches/default",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"id":"refs/heads/develop","displayId":"develop"}`))
+			})
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/fork-repo/branches/default",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		repos, err := p.DiscoverRepositories(context.Background(), "PROJ")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, repos, 2)
+		assert.Equal(t, "Bearer test-token", authHeader)
+		assert.Equal(t, "1", repos[0].ID)
+		assert.Equal(t, "my-repo", repos[0].Name)
+		assert.Equal(t, "PROJ", repos[0].Organization)
+		assert.Equal(t, "refs/heads/develop", repos[0].DefaultBranch)
+		assert.Equal(t, "https://bitbucket.corp.example/scm/proj/my-repo.git", repos[0].RemoteURL)
+		assert.Equal(t, "ssh://git@bitbucket.corp.example:7999/proj/my-repo.git", repos[0].SSHURL)
+		assert.Equal(t, "bitbucketdc", repos[0].ProviderName)
+		assert.False(t, repos[0].IsFork)
+		assert.Equal(t, "refs/heads/main", repos[1].DefaultBranch)
+		assert.True(t, repos[1].IsFork)
+		assert.True(t, repos[1].IsArchived)
+	})
+
+	t.Run("should send basic auth when the token is a username and token pair", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var username, password string
+		var hasBasic bool
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos", func(w http.ResponseWriter, r *http.Request) {
+			username, password, hasBasic = r.BasicAuth()
+			_, _ = w.Write([]byte(`{"values":[],"isLastPage":true}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		p.token = "jdoe:secret"
+
+		// when
+		_, err := p.DiscoverRepositories(context.Background(), "PROJ")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, hasBasic)
+		assert.Equal(t, "jdoe", username)
+		assert.Equal(t, "secret", password)
+	})
+}
+
+func TestPullRequestInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create the pull request between fully qualified refs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{"id":7,"title":"Bump","state":"OPEN",` +
+					`"links":{"self":[{"href":"https://bitbucket.corp.example/pr/7"}]}}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		pr, err := p.CreatePullRequest(context.Background(), testRepo(), globalEntities.PullRequestInput{
+			SourceBranch: "feature/bump",
+			TargetBranch: "refs/heads/main",
+			Title:        "Bump",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 7, pr.ID)
+		assert.Equal(t, "https://bitbucket.corp.example/pr/7", pr.URL)
+		fromRef := body["fromRef"].(map[string]any)
+		toRef := body["toRef"].(map[string]any)
+		assert.Equal(t, "refs/heads/feature/bump", fromRef["id"])
+		assert.Equal(t, "refs/heads/main", toRef["id"])
+		assert.Equal(t, "my-repo", fromRef["repository"].(map[string]any)["slug"])
+	})
+
+	t.Run("should decline the open pull request with its current version", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var declineVersion string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests",
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "refs/heads/feature/bump", r.URL.Query().Get("at"))
+				_, _ = w.Write([]byte(`{"values":[{"id":7,"version":3,"state":"OPEN",` +
+					`"fromRef":{"id":"refs/heads/feature/bump"}}],"isLastPage":true}`))
+			})
+		mux.HandleFunc("POST /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/decline",
+			func(w http.ResponseWriter, r *http.Request) {
+				declineVersion = r.URL.Query().Get("version")
+				_, _ = w.Write([]byte(`{}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		closed, err := p.ClosePullRequest(context.Background(), testRepo(), "feature/bump")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, closed)
+		assert.Equal(t, "3", declineVersion)
+	})
+}
+
+func TestCreateBranchWithChangesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should commit every file on the new branch chaining the commit IDs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var forms []map[string]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/branches",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"values":[{"displayId":"main-old","latestCommit":"bad"},` +
+					`{"displayId":"main","latestCommit":"base-sha"}],"isLastPage":true}`))
+			})
+		mux.HandleFunc("PUT /rest/api/1.0/projects/PROJ/repos/my-repo/browse/",
+			func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseMultipartForm(1<<20))
+				form := map[string]string{"path": r.URL.Path}
+				for key, values := range r.MultipartForm.Value {
+					form[key] = values[0]
+				}
+				forms = append(forms, form)
+				_, _ = w.Write([]byte(`{"id":"commit-` + string(rune('0'+len(forms))) + `"}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		err := p.CreateBranchWithChanges(context.Background(), testRepo(), globalEntities.BranchInput{
+			BranchName:    "feature/bump",
+			BaseBranch:    "refs/heads/main",
+			CommitMessage: "chore: bump",
+			Changes: []globalEntities.FileChange{
+				{Path: "/go.mod", Content: "module x", ChangeType: "edit"},
+				{Path: "docs/new file.md", Content: "hello", ChangeType: "add"},
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, forms, 2)
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/my-repo/browse/go.mod", forms[0]["path"])
+		assert.Equal(t, "feature/bump", forms[0]["branch"])
+		assert.Equal(t, "main", forms[0]["sourceBranch"])
+		assert.Equal(t, "base-sha", forms[0]["sourceCommitId"])
+		assert.Equal(t, "module x", forms[0]["content"])
+		assert.Equal(t, "/rest/api/1.0/projects/PROJ/repos/my-repo/browse/docs/new file.md", forms[1]["path"])
+		assert.NotContains(t, forms[1], "sourceBranch")
+		assert.NotContains(t, forms[1], "sourceCommitId")
+	})
+
+	t.Run("should reject deletions before writing anything", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			requests++
+		}))
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		err := p.CreateBranchWithChanges(context.Background(), testRepo(), globalEntities.BranchInput{
+			BranchName: "feature/bump",
+			BaseBranch: "main",
+			Changes:    []globalEntities.FileChange{{Path: "old.txt", ChangeType: "delete"}},
+		})
+
+		// then
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrFileDeletionUnsupported))
+		assert.Zero(t, requests)
+	})
+}
+
+func TestReviewInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should count lines and split patches per changed file", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/changes",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"values":[{"type":"MODIFY","path":{"toString":"a.go"}},` +
+					`{"type":"MOVE","path":{"toString":"c.go"},"srcPath":{"toString":"b.go"}}],"isLastPage":true}`))
+			})
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7.diff",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1,2 @@\n-old\n+new\n+more\n" +
+					"diff --git a/b.go b/c.go\nsimilarity index 100%\n"))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		files, err := p.GetPullRequestFiles(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Equal(t, "modified", files[0].Status)
+		assert.Equal(t, 2, files[0].Additions)
+		assert.Equal(t, 1, files[0].Deletions)
+		assert.Contains(t, files[0].Patch, "+more")
+		assert.Equal(t, "renamed", files[1].Status)
+		assert.Equal(t, "b.go", files[1].OldPath)
+	})
+
+	t.Run("should flatten nested replies into threads with their file anchor", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/activities",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"values":[` +
+					`{"action":"COMMENTED","comment":{"id":12,"text":"reply","author":{"name":"bob"}}},` +
+					`{"action":"APPROVED"},` +
+					`{"action":"COMMENTED","commentAnchor":{"path":"main.go","line":4},` +
+					`"comment":{"id":10,"text":"root","author":{"name":"alice"},` +
+					`"comments":[{"id":11,"text":"first","author":{"name":"bob"},` +
+					`"comments":[{"id":12,"text":"reply","author":{"name":"bob"}}]}]}},` +
+					`{"action":"COMMENTED","comment":{"id":20,"text":"general","author":{"name":"carol"}}}` +
+					`],"isLastPage":true}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		comments, err := p.ListPullRequestComments(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, comments, 4)
+		assert.Equal(t, globalEntities.PullRequestComment{
+			ID: 10, ThreadID: 10, Body: "root", Author: "alice", FilePath: "main.go", Line: 4,
+		}, comments[0])
+		assert.Equal(t, int64(10), comments[1].ThreadID)
+		assert.Equal(t, int64(10), comments[1].InReplyToID)
+		assert.Equal(t, int64(10), comments[2].ThreadID)
+		assert.Equal(t, int64(11), comments[2].InReplyToID)
+		assert.Equal(t, "main.go", comments[2].FilePath)
+		assert.Equal(t, int64(20), comments[3].ThreadID)
+		assert.Empty(t, comments[3].FilePath)
+	})
+
+	t.Run("should anchor thread comments to the new side of the effective diff", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/comments",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{"id":42}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		id, err := p.PostPullRequestThreadComment(context.Background(), testRepo(), 7, "main.go", 4, "nit")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 42, id)
+		assert.Equal(t, map[string]any{
+			"path": "main.go", "line": float64(4), "lineType": "ADDED", "fileType": "TO", "diffType": "EFFECTIVE",
+		}, body["anchor"])
+	})
+
+	t.Run("should resolve the thread with the current comment version", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/comments/10",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"id":10,"version":2}`))
+			})
+		mux.HandleFunc("PUT /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/comments/10",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		err := p.UpdatePullRequestThreadStatus(context.Background(), testRepo(), 7, 10, "fixed")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"version": float64(2), "threadResolved": true}, body)
+	})
+
+	t.Run("should report failing checks when any build status is not successful", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"id":7,"fromRef":{"latestCommit":"abc123"}}`))
+			})
+		mux.HandleFunc("GET /rest/build-status/1.0/commits/abc123", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"values":[{"state":"SUCCESSFUL"},{"state":"INPROGRESS"}],"isLastPage":true}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		passed, err := p.GetPullRequestCheckStatus(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, passed)
+	})
+
+	t.Run("should map each build status to a check", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"id":7,"fromRef":{"latestCommit":"abc123"}}`))
+			})
+		mux.HandleFunc("GET /rest/build-status/1.0/commits/abc123", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"values":[
+				{"state":"FAILED","key":"build-1","name":"Build","url":"https://ci.example.com/1","dateAdded":1767348300000},
+				{"state":"INPROGRESS","key":"lint","dateAdded":1767348000000}],"isLastPage":true}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		checks, err := p.GetPullRequestChecks(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &globalEntities.PullRequestChecks{
+			Checks: []globalEntities.Check{
+				{
+					Name:        "Build",
+					Kind:        globalEntities.CheckKindStatus,
+					State:       globalEntities.CheckStateFailure,
+					URL:         "https://ci.example.com/1",
+					CompletedAt: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC),
+				},
+				{
+					Name:      "lint",
+					Kind:      globalEntities.CheckKindStatus,
+					State:     globalEntities.CheckStatePending,
+					StartedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			Verdict: globalEntities.CheckStateFailure,
+		}, checks)
+	})
+
+	t.Run("should merge with the mapped strategy and delete the source branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var mergeVersion string
+		var mergeBody, deleteBody map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"id":7,"version":5,"fromRef":{"id":"refs/heads/feature/bump"}}`))
+			})
+		mux.HandleFunc("POST /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/merge",
+			func(w http.ResponseWriter, r *http.Request) {
+				mergeVersion = r.URL.Query().Get("version")
+				_ = json.NewDecoder(r.Body).Decode(&mergeBody)
+				_, _ = w.Write([]byte(`{}`))
+			})
+		mux.HandleFunc("DELETE /rest/branch-utils/1.0/projects/PROJ/repos/my-repo/branches",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&deleteBody)
+				w.WriteHeader(http.StatusNoContent)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		err := p.MergePullRequest(context.Background(), testRepo(), 7, "squash",
+			globalEntities.WithDeleteSourceBranch())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "5", mergeVersion)
+		assert.Equal(t, "squash", mergeBody["strategyId"])
+		assert.Equal(t, "refs/heads/feature/bump", deleteBody["name"])
+	})
+
+	t.Run("should set the participant status of the authenticated user", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var statuses []string
+		var comment map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /plugins/servlet/applinks/whoami", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("John.Doe@corp\n"))
+		})
+		mux.HandleFunc("GET /rest/api/1.0/users/John.Doe@corp", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"name":"John.Doe@corp","slug":"john.doe_corp"}`))
+		})
+		mux.HandleFunc("POST /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/comments",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&comment)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			})
+		mux.HandleFunc("PUT /rest/api/1.0/projects/PROJ/repos/my-repo/pull-requests/7/participants/john.doe_corp",
+			func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]string
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				statuses = append(statuses, body["status"])
+				_, _ = w.Write([]byte(`{}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		approveErr := p.SubmitPullRequestReview(context.Background(), testRepo(), 7, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictApprove,
+		})
+		changesErr := p.SubmitPullRequestReview(context.Background(), testRepo(), 7, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictRequestChanges,
+			Body:    "please fix",
+		})
+
+		// then
+		require.NoError(t, approveErr)
+		require.NoError(t, changesErr)
+		assert.Equal(t, []string{"APPROVED", "NEEDS_WORK"}, statuses)
+		assert.Equal(t, "please fix", comment["text"])
+	})
+}
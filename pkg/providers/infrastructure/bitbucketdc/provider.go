@@ -0,0 +1,176 @@
+package bitbucketdc
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gohttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	providerName    = "bitbucketdc"
+	apiPath         = "/rest/api/1.0"
+	perPage         = 100
+	httpTimeout     = 30 * time.Second
+	httpStatusOKMin = 200
+	httpStatusOKMax = 300
+
+	// defaultSSHPort is the port Bitbucket Data Center serves SSH on by default.
+	defaultSSHPort = 7999
+
+	// accessTokenUsername is the git username used in clone URLs when the
+	// token is an HTTP access token without an explicit username.
+	accessTokenUsername = "x-token-auth"
+)
+
+// Provider implements ForgeProvider, FileAccessProvider, ReviewProvider, ChecksProvider, and LocalGitAuthProvider
+// for Bitbucket Data Center (and Bitbucket Server) through the `/rest/api/1.0` API.
+//
+// Repository.Organization is the project key and Repository.Name the repository slug.
+// The token is an HTTP access token, sent as a Bearer token, or "username:token",
+// sent as HTTP Basic credentials.
+type Provider struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+
+	// userSlugMu guards userSlug, the slug of the authenticated user, which is
+	// resolved lazily the first time a review verdict is submitted.
+	userSlugMu sync.Mutex
+	userSlug   string
+}
+
+// NewProvider creates a new Bitbucket Data Center provider for the instance rooted
+// at baseURL (e.g. "https://bitbucket.corp.example").
+func NewProvider(token, baseURL string) (globalEntities.ForgeProvider, error) {
+	return NewProviderWithClient(token, baseURL, nil)
+}
+
+// NewProviderWithClient creates a new Bitbucket Data Center provider with an explicit
+// HTTP client. If client is nil, a default client with the standard timeout is used.
+func NewProviderWithClient(token, baseURL string, client *http.Client) (globalEntities.ForgeProvider, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Bitbucket Data Center URL %q", baseURL)
+	}
+
+	if client == nil {
+		client = &http.Client{
+			Timeout: httpTimeout,
+		}
+	}
+
+	return &Provider{
+		token:      token,
+		baseURL:    baseURL,
+		httpClient: client,
+	}, nil
+}
+
+func (p *Provider) Name() string      { return providerName }
+func (p *Provider) AuthToken() string { return p.token }
+
+func (p *Provider) MatchesURL(rawURL string) bool {
+	return strings.Contains(rawURL, p.hostname())
+}
+
+// hostname returns the host name of the instance, without any port.
+func (p *Provider) hostname() string {
+	parsed, err := url.Parse(p.baseURL)
+	if err != nil {
+		return p.baseURL
+	}
+	return parsed.Hostname()
+}
+
+// basicCredentials splits a "username:token" token. The boolean is false when
+// the token is a plain HTTP access token.
+func (p *Provider) basicCredentials() (string, string, bool) {
+	return strings.Cut(p.token, ":")
+}
+
+// repoPath returns the API path of a repository.
+func repoPath(repo globalEntities.Repository) string {
+	return fmt.Sprintf(
+		"%s/projects/%s/repos/%s",
+		apiPath, url.PathEscape(repo.Organization), url.PathEscape(repo.Name),
+	)
+}
+
+// --- LocalGitAuthProvider ---
+
+func (p *Provider) GetServiceType() globalEntities.ServiceType {
+	return globalEntities.BITBUCKETDC
+}
+
+func (p *Provider) PrepareCloneURL(cloneURL string) string {
+	return cloneURL
+}
+
+func (p *Provider) ConfigureTransport() {
+	// Bitbucket Data Center doesn't need special transport configuration
+}
+
+// GetAuthMethods returns HTTP Basic credentials for "username:token" tokens and
+// a Bearer token otherwise, which Bitbucket Data Center accepts for git over HTTPS.
+func (p *Provider) GetAuthMethods(_ string) []transport.AuthMethod {
+	var authMethods []transport.AuthMethod
+
+	if p.token != "" {
+		log.Infof("Using access token to authenticate with Bitbucket Data Center")
+		if username, password, ok := p.basicCredentials(); ok {
+			authMethods = append(authMethods, &gohttp.BasicAuth{
+				Username: username,
+				Password: password,
+			})
+		} else {
+			authMethods = append(authMethods, &gohttp.TokenAuth{
+				Token: p.token,
+			})
+		}
+	}
+
+	return authMethods
+}
+
+func (p *Provider) CloneURL(repo globalEntities.Repository) string {
+	remoteURL := repo.RemoteURL
+	if remoteURL == "" {
+		remoteURL = fmt.Sprintf(
+			"%s/scm/%s/%s.git",
+			p.baseURL, strings.ToLower(repo.Organization), repo.Name,
+		)
+	}
+
+	parsed, err := url.Parse(remoteURL)
+	if err != nil {
+		return remoteURL
+	}
+
+	if username, password, ok := p.basicCredentials(); ok {
+		parsed.User = url.UserPassword(username, password)
+	} else {
+		parsed.User = url.UserPassword(accessTokenUsername, p.token)
+	}
+
+	return parsed.String()
+}
+
+func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
+	host := p.hostname()
+	if sshAlias != "" {
+		host = fmt.Sprintf("%s-%s", host, sshAlias)
+	}
+	return fmt.Sprintf(
+		"ssh://git@%s:%d/%s/%s.git",
+		host, defaultSSHPort, strings.ToLower(repo.Organization), repo.Name,
+	)
+}
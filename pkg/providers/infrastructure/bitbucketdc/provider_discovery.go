@@ -0,0 +1,113 @@
+package bitbucketdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+type bitbucketLink struct {
+	Href string `json:"href"`
+	Name string `json:"name"`
+}
+
+type bitbucketProject struct {
+	Key string `json:"key"`
+}
+
+type bitbucketRepo struct {
+	ID       int              `json:"id"`
+	Slug     string           `json:"slug"`
+	Name     string           `json:"name"`
+	Archived bool             `json:"archived"`
+	Project  bitbucketProject `json:"project"`
+	Origin   *struct {
+		Slug string `json:"slug"`
+	} `json:"origin"`
+	Links struct {
+		Clone []bitbucketLink `json:"clone"`
+	} `json:"links"`
+}
+
+// DiscoverRepositories lists every repository in the project whose key is org.
+func (p *Provider) DiscoverRepositories(
+	ctx context.Context,
+	org string,
+) ([]globalEntities.Repository, error) {
+	endpoint := fmt.Sprintf("%s/projects/%s/repos", apiPath, url.PathEscape(org))
+
+	repos, err := listAll[bitbucketRepo](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover project repos for %q: %w", org, err)
+	}
+
+	allRepos := make([]globalEntities.Repository, 0, len(repos))
+	for _, r := range repos {
+		repo := bitbucketRepoToDomain(r, org)
+		repo.DefaultBranch = p.getDefaultBranch(ctx, repo)
+		allRepos = append(allRepos, repo)
+	}
+
+	return allRepos, nil
+}
+
+func bitbucketRepoToDomain(r bitbucketRepo, projectKey string) globalEntities.Repository {
+	if r.Project.Key != "" {
+		projectKey = r.Project.Key
+	}
+
+	var remoteURL, sshURL string
+	for _, link := range r.Links.Clone {
+		switch link.Name {
+		case "http":
+			remoteURL = stripUserInfo(link.Href)
+		case "ssh":
+			sshURL = link.Href
+		}
+	}
+
+	return globalEntities.Repository{
+		ID:           strconv.Itoa(r.ID),
+		Name:         r.Slug,
+		Organization: projectKey,
+		RemoteURL:    remoteURL,
+		SSHURL:       sshURL,
+		ProviderName: providerName,
+		IsFork:       r.Origin != nil,
+		IsArchived:   r.Archived,
+	}
+}
+
+// getDefaultBranch returns the repository's default branch. Empty repositories
+// have no default branch yet, in which case "refs/heads/main" is assumed.
+func (p *Provider) getDefaultBranch(ctx context.Context, repo globalEntities.Repository) string {
+	const fallback = "refs/heads/main"
+
+	resp, err := p.doRequest(ctx, http.MethodGet, repoPath(repo)+"/branches/default", nil)
+	if err != nil {
+		return fallback
+	}
+
+	var branch bitbucketRef
+	if unmarshalErr := json.Unmarshal(resp, &branch); unmarshalErr != nil || branch.ID == "" {
+		return fallback
+	}
+
+	return branch.ID
+}
+
+// stripUserInfo removes the "user@" part Bitbucket embeds in HTTP clone links
+// so that CloneURL can inject the configured credentials instead.
+func stripUserInfo(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	parsed.User = nil
+	return parsed.String()
+}
@@ -0,0 +1,245 @@
+package bitbucketdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+)
+
+// ErrFileDeletionUnsupported is returned by CreateBranchWithChanges when a
+// change deletes a file. The Bitbucket Data Center REST API can create and
+// edit files through the `/browse` endpoint but has no way to delete them.
+var ErrFileDeletionUnsupported = errors.New(
+	"deleting files is not supported on Bitbucket Data Center",
+)
+
+func (p *Provider) GetFileContent(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	path string,
+) (string, error) {
+	endpoint := fmt.Sprintf("%s/raw/%s", repoPath(repo), escapeFilePath(path))
+	if ref := repo.DefaultBranch; ref != "" {
+		endpoint += "?at=" + url.QueryEscape(ref)
+	}
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file %q: %w", path, err)
+	}
+
+	return string(resp), nil
+}
+
+// ListFiles lists every file of the repository. The `/files` endpoint returns
+// paths only, so File.ObjectID is left empty and directories are never listed.
+func (p *Provider) ListFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	pattern string,
+) ([]globalEntities.File, error) {
+	endpoint := repoPath(repo) + "/files"
+	if ref := repo.DefaultBranch; ref != "" {
+		endpoint += "?at=" + url.QueryEscape(ref)
+	}
+
+	paths, err := listAll[string](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var files []globalEntities.File
+	for _, path := range paths {
+		if pattern != "" && !strings.HasSuffix(path, pattern) {
+			continue
+		}
+		files = append(files, globalEntities.File{Path: path})
+	}
+
+	return files, nil
+}
+
+func (p *Provider) GetTags(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]string, error) {
+	tags, err := listAll[bitbucketRef](ctx, p, repoPath(repo)+"/tags")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	allTags := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		allTags = append(allTags, tag.DisplayID)
+	}
+
+	globalHelpers.SortVersionsDescending(allTags)
+	return allTags, nil
+}
+
+func (p *Provider) HasFile(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	path string,
+) bool {
+	_, err := p.GetFileContent(ctx, repo, path)
+	return err == nil
+}
+
+// CreateBranchWithChanges writes each change with a multipart PUT to the
+// `/browse` endpoint, which commits one file at a time. The first commit
+// creates the branch from the base branch head; every later commit is chained
+// on the previous one through `sourceCommitId`. Changes of type "add" or
+// "create" are sent without `sourceCommitId`, as Bitbucket expects for new files.
+// Deletions return ErrFileDeletionUnsupported before anything is written.
+func (p *Provider) CreateBranchWithChanges(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.BranchInput,
+) error {
+	for _, change := range input.Changes {
+		filePath := strings.TrimPrefix(change.Path, "/")
+		changeType := strings.ToLower(strings.TrimSpace(change.ChangeType))
+
+		if changeType == "delete" {
+			return fmt.Errorf("%w: %q", ErrFileDeletionUnsupported, filePath)
+		}
+
+		// treat unknown, non-empty change types as an error
+		if changeType != "" && changeType != "add" && changeType != "edit" && changeType != "create" &&
+			changeType != "update" {
+			return fmt.Errorf("unsupported change type %q for file %q", change.ChangeType, filePath)
+		}
+	}
+
+	baseBranch := strings.TrimPrefix(input.BaseBranch, "refs/heads/")
+	head, err := p.getBranchHead(ctx, repo, baseBranch)
+	if err != nil {
+		return fmt.Errorf("failed to resolve base branch %q: %w", baseBranch, err)
+	}
+
+	if len(input.Changes) == 0 {
+		return p.createBranch(ctx, repo, input.BranchName, head)
+	}
+
+	for i, change := range input.Changes {
+		changeType := strings.ToLower(strings.TrimSpace(change.ChangeType))
+		isNew := changeType == "add" || changeType == "create"
+
+		fields := [][2]string{
+			{"content", change.Content},
+			{"message", input.CommitMessage},
+			{"branch", input.BranchName},
+		}
+		if i == 0 {
+			fields = append(fields, [2]string{"sourceBranch", baseBranch})
+		}
+		if !isNew {
+			fields = append(fields, [2]string{"sourceCommitId", head})
+		}
+
+		commit, commitErr := p.commitFile(ctx, repo, strings.TrimPrefix(change.Path, "/"), fields)
+		if commitErr != nil {
+			return fmt.Errorf("failed to commit %q on branch %q: %w", change.Path, input.BranchName, commitErr)
+		}
+		head = commit
+	}
+
+	return nil
+}
+
+// commitFile sends one multipart PUT to the `/browse` endpoint and returns the
+// ID of the commit it created.
+func (p *Provider) commitFile(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	filePath string,
+	fields [][2]string,
+) (string, error) {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+
+	for _, field := range fields {
+		if writeErr := form.WriteField(field[0], field[1]); writeErr != nil {
+			return "", fmt.Errorf("failed to write form field %q: %w", field[0], writeErr)
+		}
+	}
+	if closeErr := form.Close(); closeErr != nil {
+		return "", fmt.Errorf("failed to finalize form: %w", closeErr)
+	}
+
+	endpoint := fmt.Sprintf("%s/browse/%s", repoPath(repo), escapeFilePath(filePath))
+	resp, err := p.send(ctx, http.MethodPut, endpoint, form.FormDataContentType(), &buf)
+	if err != nil {
+		return "", err
+	}
+
+	var commit struct {
+		ID string `json:"id"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &commit); unmarshalErr != nil {
+		return "", fmt.Errorf("failed to parse commit response: %w", unmarshalErr)
+	}
+
+	return commit.ID, nil
+}
+
+// createBranch creates branchName pointing at startPoint.
+func (p *Provider) createBranch(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	branchName, startPoint string,
+) error {
+	body := map[string]string{
+		"name":       branchName,
+		"startPoint": startPoint,
+	}
+
+	if _, err := p.doRequest(ctx, http.MethodPost, repoPath(repo)+"/branches", body); err != nil {
+		return fmt.Errorf("failed to create branch %q: %w", branchName, err)
+	}
+
+	return nil
+}
+
+// getBranchHead returns the commit hash the given branch points at. The
+// branches endpoint only filters by substring, so the exact match is picked
+// from the results.
+func (p *Provider) getBranchHead(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	branch string,
+) (string, error) {
+	endpoint := fmt.Sprintf("%s/branches?filterText=%s", repoPath(repo), url.QueryEscape(branch))
+
+	branches, err := listAll[bitbucketRef](ctx, p, endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	for _, b := range branches {
+		if b.DisplayID == branch {
+			return b.LatestCommit, nil
+		}
+	}
+
+	return "", fmt.Errorf("branch %q not found", branch)
+}
+
+// escapeFilePath escapes every segment of a repository file path.
+func escapeFilePath(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,126 @@
+package bitbucketdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// apiError represents an HTTP API error with the status code preserved.
+type apiError struct {
+	statusCode int
+	body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.statusCode, e.body)
+}
+
+// StatusCode returns the HTTP status code of the failed request.
+func (e *apiError) StatusCode() int {
+	return e.statusCode
+}
+
+// page is the envelope Bitbucket Data Center wraps every paged collection in.
+type page[T any] struct {
+	Values        []T  `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// doRequest sends a JSON request to the instance. endpoint is the path below
+// the instance root, e.g. "/rest/api/1.0/projects/KEY/repos".
+func (p *Provider) doRequest(
+	ctx context.Context,
+	method, endpoint string,
+	body any,
+) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, marshalErr := json.Marshal(body)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", marshalErr)
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	return p.send(ctx, method, endpoint, "application/json", reqBody)
+}
+
+// send performs the HTTP round-trip with authentication and turns non-2xx
+// responses into *apiError.
+func (p *Provider) send(
+	ctx context.Context,
+	method, endpoint, contentType string,
+	reqBody io.Reader,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+endpoint, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if username, password, ok := p.basicCredentials(); ok {
+		req.SetBasicAuth(username, password)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < httpStatusOKMin || resp.StatusCode >= httpStatusOKMax {
+		return nil, &apiError{
+			statusCode: resp.StatusCode,
+			body:       string(respBody),
+		}
+	}
+
+	return respBody, nil
+}
+
+// listAll walks every page of a paged collection starting at endpoint, using
+// the `start` and `limit` query parameters Bitbucket Data Center pages with.
+func listAll[T any](ctx context.Context, p *Provider, endpoint string) ([]T, error) {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+
+	var all []T
+	start := 0
+
+	for {
+		pageEndpoint := fmt.Sprintf("%s%sstart=%d&limit=%d", endpoint, separator, start, perPage)
+		resp, err := p.doRequest(ctx, http.MethodGet, pageEndpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var pg page[T]
+		if unmarshalErr := json.Unmarshal(resp, &pg); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse paged response: %w", unmarshalErr)
+		}
+
+		all = append(all, pg.Values...)
+		if pg.IsLastPage || len(pg.Values) == 0 {
+			return all, nil
+		}
+		start = pg.NextPageStart
+	}
+}
@@ -0,0 +1,156 @@
+package bitbucketdc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+type bitbucketRef struct {
+	ID           string `json:"id"`
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+}
+
+type bitbucketUser struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type bitbucketPR struct {
+	ID      int          `json:"id"`
+	Version int          `json:"version"`
+	Title   string       `json:"title"`
+	State   string       `json:"state"`
+	Draft   bool         `json:"draft"`
+	FromRef bitbucketRef `json:"fromRef"`
+	ToRef   bitbucketRef `json:"toRef"`
+	Author  struct {
+		User bitbucketUser `json:"user"`
+	} `json:"author"`
+	Links struct {
+		Self []bitbucketLink `json:"self"`
+	} `json:"links"`
+}
+
+// htmlURL returns the web URL of the pull request.
+func (pr bitbucketPR) htmlURL() string {
+	if len(pr.Links.Self) == 0 {
+		return ""
+	}
+	return pr.Links.Self[0].Href
+}
+
+func (p *Provider) CreatePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PullRequestInput,
+) (*globalEntities.PullRequest, error) {
+	repoRef := map[string]any{
+		"slug":    repo.Name,
+		"project": map[string]string{"key": repo.Organization},
+	}
+
+	body := map[string]any{
+		"title":       input.Title,
+		"description": input.Description,
+		"fromRef": map[string]any{
+			"id":         branchRef(input.SourceBranch),
+			"repository": repoRef,
+		},
+		"toRef": map[string]any{
+			"id":         branchRef(input.TargetBranch),
+			"repository": repoRef,
+		},
+	}
+
+	resp, err := p.doRequest(ctx, http.MethodPost, repoPath(repo)+"/pull-requests", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	var pr bitbucketPR
+	if unmarshalErr := json.Unmarshal(resp, &pr); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request response: %w", unmarshalErr)
+	}
+
+	return &globalEntities.PullRequest{
+		ID:     pr.ID,
+		Title:  pr.Title,
+		URL:    pr.htmlURL(),
+		Status: pr.State,
+	}, nil
+}
+
+// branchRef returns the fully qualified ref of a branch name.
+func branchRef(branch string) string {
+	return "refs/heads/" + strings.TrimPrefix(branch, "refs/heads/")
+}
+
+// findOpenPullRequest returns the open pull request whose source branch is
+// sourceBranch, or nil when there is none.
+func (p *Provider) findOpenPullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (*bitbucketPR, error) {
+	ref := branchRef(sourceBranch)
+	endpoint := fmt.Sprintf(
+		"%s/pull-requests?state=OPEN&direction=OUTGOING&at=%s",
+		repoPath(repo), url.QueryEscape(ref),
+	)
+
+	prs, err := listAll[bitbucketPR](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	for _, pr := range prs {
+		if pr.FromRef.ID == ref {
+			return &pr, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil // no open pull request is not an error
+}
+
+func (p *Provider) PullRequestExists(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	pr, err := p.findOpenPullRequest(ctx, repo, sourceBranch)
+	return pr != nil, err
+}
+
+// ClosePullRequest declines the open pull request for sourceBranch, which is
+// how Bitbucket closes a pull request without merging it.
+func (p *Provider) ClosePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	pr, err := p.findOpenPullRequest(ctx, repo, sourceBranch)
+	if err != nil {
+		return false, err
+	}
+	if pr == nil {
+		return false, nil
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/pull-requests/%d/decline?version=%d",
+		repoPath(repo), pr.ID, pr.Version,
+	)
+
+	if _, declineErr := p.doRequest(ctx, http.MethodPost, endpoint, nil); declineErr != nil {
+		return false, fmt.Errorf("failed to decline pull request %d: %w", pr.ID, declineErr)
+	}
+
+	return true, nil
+}
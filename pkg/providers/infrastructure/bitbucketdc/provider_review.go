@@ -0,0 +1,690 @@
+package bitbucketdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+	log "github.com/sirupsen/logrus"
+)
+
+// Participant statuses accepted by the pull request participants endpoint.
+const (
+	participantStatusApproved  = "APPROVED"
+	participantStatusNeedsWork = "NEEDS_WORK"
+)
+
+// Merge strategy IDs of the Bitbucket Data Center Git SCM.
+const (
+	mergeStrategyNoFastForward = "no-ff"
+	mergeStrategyFastForward   = "ff-only"
+	mergeStrategySquash        = "squash"
+	mergeStrategyRebase        = "rebase-ff-only"
+	mergeStrategyRebaseMerge   = "rebase-no-ff"
+)
+
+// buildStatusSuccessful is the state of a passing build status.
+const buildStatusSuccessful = "SUCCESSFUL"
+
+type bitbucketChange struct {
+	Type string `json:"type"`
+	Path struct {
+		ToString string `json:"toString"`
+	} `json:"path"`
+	SrcPath *struct {
+		ToString string `json:"toString"`
+	} `json:"srcPath"`
+}
+
+type bitbucketComment struct {
+	ID             int64              `json:"id"`
+	Version        int                `json:"version"`
+	Text           string             `json:"text"`
+	Author         bitbucketUser      `json:"author"`
+	ThreadResolved bool               `json:"threadResolved"`
+	Comments       []bitbucketComment `json:"comments"`
+}
+
+type bitbucketAnchor struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+type bitbucketActivity struct {
+	Action        string            `json:"action"`
+	Comment       *bitbucketComment `json:"comment"`
+	CommentAnchor *bitbucketAnchor  `json:"commentAnchor"`
+}
+
+type bitbucketBuildStatus struct {
+	State     string `json:"state"`
+	Key       string `json:"key"`
+	Name      string `json:"name"`
+	URL       string `json:"url"`
+	DateAdded int64  `json:"dateAdded"`
+}
+
+// --- ReviewProvider ---
+
+func (p *Provider) ListOpenPullRequests(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.PullRequestDetail, error) {
+	prs, err := listAll[bitbucketPR](ctx, p, repoPath(repo)+"/pull-requests?state=OPEN")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open pull requests: %w", err)
+	}
+
+	allPRs := make([]globalEntities.PullRequestDetail, 0, len(prs))
+	for _, pr := range prs {
+		allPRs = append(allPRs, globalEntities.PullRequestDetail{
+			ID:           pr.ID,
+			Title:        pr.Title,
+			URL:          pr.htmlURL(),
+			Status:       mapPullRequestState(pr.State),
+			SourceBranch: pr.FromRef.DisplayID,
+			TargetBranch: pr.ToRef.DisplayID,
+			Author:       pr.Author.User.Name,
+			IsDraft:      pr.Draft,
+		})
+	}
+
+	return allPRs, nil
+}
+
+func (p *Provider) GetPullRequestDiff(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	endpoint := fmt.Sprintf("%s/pull-requests/%d.diff", repoPath(repo), prID)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+
+	return string(resp), nil
+}
+
+// GetPullRequestFiles lists the changed files of the pull request. The
+// `/changes` endpoint carries neither patches nor line counts, so the PR's
+// `.diff` is fetched once, split by file, and the counts are taken from it.
+func (p *Provider) GetPullRequestFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestFile, error) {
+	endpoint := fmt.Sprintf("%s/pull-requests/%d/changes", repoPath(repo), prID)
+
+	changes, err := listAll[bitbucketChange](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request files: %w", err)
+	}
+
+	diff, err := p.GetPullRequestDiff(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	patches := globalHelpers.SplitDiffByFile(diff)
+
+	result := make([]globalEntities.PullRequestFile, 0, len(changes))
+	for _, c := range changes {
+		patch := patches[c.Path.ToString]
+		additions, deletions := countDiffLines(patch)
+
+		file := globalEntities.PullRequestFile{
+			Path:      c.Path.ToString,
+			Status:    mapChangeType(c.Type),
+			Additions: additions,
+			Deletions: deletions,
+			Patch:     patch,
+		}
+		if c.SrcPath != nil {
+			file.OldPath = c.SrcPath.ToString
+		}
+		result = append(result, file)
+	}
+
+	return result, nil
+}
+
+// mapChangeType translates a Bitbucket change type to the vocabulary used by
+// PullRequestFile.
+func mapChangeType(changeType string) string {
+	switch changeType {
+	case "ADD", "COPY":
+		return "added"
+	case "DELETE":
+		return "deleted"
+	case "MOVE":
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+// countDiffLines counts the added and removed lines of a single-file patch,
+// skipping the `+++` and `---` file headers.
+func countDiffLines(patch string) (int, int) {
+	var additions, deletions int
+	for line := range strings.SplitSeq(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// ListPullRequestComments returns every comment of the pull request, taken
+// from its activity stream. Bitbucket nests replies inside their parent, so the
+// tree is flattened: ThreadID is the ID of the root comment, InReplyToID the ID
+// of the direct parent, and replies inherit the root's file anchor. PR-wide
+// comments have ThreadID set to their own ID, since every root comment is a
+// thread that can be replied to and resolved.
+func (p *Provider) ListPullRequestComments(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestComment, error) {
+	endpoint := fmt.Sprintf("%s/pull-requests/%d/activities", repoPath(repo), prID)
+
+	activities, err := listAll[bitbucketActivity](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request activities: %w", err)
+	}
+
+	// the stream is newest first and replies get an activity of their own, so
+	// every comment nested under another one is collected first to tell roots apart
+	replies := make(map[int64]bool)
+	var collect func(c bitbucketComment)
+	collect = func(c bitbucketComment) {
+		for _, reply := range c.Comments {
+			replies[reply.ID] = true
+			collect(reply)
+		}
+	}
+	for _, a := range activities {
+		if a.Comment != nil {
+			collect(*a.Comment)
+		}
+	}
+
+	seen := make(map[int64]bool)
+	var out []globalEntities.PullRequestComment
+	var walk func(c bitbucketComment, rootID, parentID int64, anchor *bitbucketAnchor)
+	walk = func(c bitbucketComment, rootID, parentID int64, anchor *bitbucketAnchor) {
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			comment := globalEntities.PullRequestComment{
+				ID:          c.ID,
+				ThreadID:    rootID,
+				Body:        c.Text,
+				Author:      c.Author.Name,
+				InReplyToID: parentID,
+			}
+			if anchor != nil {
+				comment.FilePath = anchor.Path
+				comment.Line = anchor.Line
+			}
+			out = append(out, comment)
+		}
+		for _, reply := range c.Comments {
+			walk(reply, rootID, c.ID, anchor)
+		}
+	}
+
+	for _, a := range activities {
+		if a.Action != "COMMENTED" || a.Comment == nil || replies[a.Comment.ID] {
+			continue
+		}
+		walk(*a.Comment, a.Comment.ID, 0, a.CommentAnchor)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// PostPullRequestComment posts a PR-wide comment. Bitbucket threads are only
+// resolved after creation, so any entities.WithThreadStatus value supplied by
+// the caller is silently ignored; use UpdatePullRequestThreadStatus instead.
+func (p *Provider) PostPullRequestComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	body string,
+	_ ...globalEntities.CommentOption,
+) error {
+	if _, err := p.createComment(ctx, repo, prID, map[string]any{"text": body}); err != nil {
+		return fmt.Errorf("failed to post pull request comment: %w", err)
+	}
+
+	return nil
+}
+
+// PostPullRequestThreadComment posts a comment anchored to a new-side line of
+// filePath in the effective diff of the pull request and returns its ID, which
+// is the thread ID for ReplyToThread. Any entities.WithThreadStatus value is
+// silently ignored.
+func (p *Provider) PostPullRequestThreadComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	filePath string,
+	line int,
+	body string,
+	_ ...globalEntities.CommentOption,
+) (int, error) {
+	reqBody := map[string]any{
+		"text": body,
+		"anchor": map[string]any{
+			"path":     filePath,
+			"line":     line,
+			"lineType": "ADDED",
+			"fileType": "TO",
+			"diffType": "EFFECTIVE",
+		},
+	}
+
+	comment, err := p.createComment(ctx, repo, prID, reqBody)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post pull request thread comment: %w", err)
+	}
+
+	return int(comment.ID), nil
+}
+
+// ReplyToThread posts a reply to the comment whose ID is threadID.
+func (p *Provider) ReplyToThread(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	body string,
+) (int, error) {
+	reqBody := map[string]any{
+		"text":   body,
+		"parent": map[string]any{"id": threadID},
+	}
+
+	comment, err := p.createComment(ctx, repo, prID, reqBody)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reply to pull request thread: %w", err)
+	}
+
+	return int(comment.ID), nil
+}
+
+func (p *Provider) createComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	body map[string]any,
+) (*bitbucketComment, error) {
+	endpoint := fmt.Sprintf("%s/pull-requests/%d/comments", repoPath(repo), prID)
+
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment bitbucketComment
+	if unmarshalErr := json.Unmarshal(resp, &comment); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse comment response: %w", unmarshalErr)
+	}
+
+	return &comment, nil
+}
+
+// UpdatePullRequestThreadStatus resolves or reopens the thread rooted at the
+// comment threadID. The Azure DevOps statuses are mapped onto Bitbucket's
+// resolved flag: "active" and "pending" reopen the thread, while "fixed",
+// "closed", "wontFix", "byDesign", and "resolved" resolve it.
+func (p *Provider) UpdatePullRequestThreadStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	status string,
+) error {
+	resolved, ok := mapThreadStatus(status)
+	if !ok {
+		return fmt.Errorf("unsupported thread status %q", status)
+	}
+
+	endpoint := fmt.Sprintf("%s/pull-requests/%d/comments/%d", repoPath(repo), prID, threadID)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request comment: %w", err)
+	}
+
+	var comment bitbucketComment
+	if unmarshalErr := json.Unmarshal(resp, &comment); unmarshalErr != nil {
+		return fmt.Errorf("failed to parse comment response: %w", unmarshalErr)
+	}
+
+	body := map[string]any{
+		"version":        comment.Version,
+		"threadResolved": resolved,
+	}
+	if _, updateErr := p.doRequest(ctx, http.MethodPut, endpoint, body); updateErr != nil {
+		return fmt.Errorf("failed to update pull request thread status: %w", updateErr)
+	}
+
+	return nil
+}
+
+func mapThreadStatus(status string) (bool, bool) {
+	switch status {
+	case "active", "pending":
+		return false, true
+	case "fixed", "closed", "wontFix", "byDesign", "resolved":
+		return true, true
+	}
+	return false, false
+}
+
+func (p *Provider) getPullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*bitbucketPR, error) {
+	endpoint := fmt.Sprintf("%s/pull-requests/%d", repoPath(repo), prID)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	var pr bitbucketPR
+	if unmarshalErr := json.Unmarshal(resp, &pr); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request response: %w", unmarshalErr)
+	}
+
+	return &pr, nil
+}
+
+// GetPullRequestStatus returns "open", "merged", or "closed" for a declined
+// pull request.
+func (p *Provider) GetPullRequestStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return "", err
+	}
+
+	return mapPullRequestState(pr.State), nil
+}
+
+func mapPullRequestState(state string) string {
+	if state == "DECLINED" {
+		return "closed"
+	}
+	return strings.ToLower(state)
+}
+
+// GetPullRequestCheckStatus reports whether every build status reported on the
+// pull request's source commit is SUCCESSFUL. A commit without any build
+// status has no CI configured and is treated as passing.
+func (p *Provider) GetPullRequestCheckStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (bool, error) {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return false, err
+	}
+
+	endpoint := "/rest/build-status/1.0/commits/" + url.PathEscape(pr.FromRef.LatestCommit)
+
+	statuses, err := listAll[bitbucketBuildStatus](ctx, p, endpoint)
+	if err != nil {
+		return false, fmt.Errorf("failed to get build statuses: %w", err)
+	}
+
+	for _, status := range statuses {
+		if status.State != buildStatusSuccessful {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// GetPullRequestChecks returns the build statuses reported on the pull
+// request's source commit. Required builds are a merge check whose
+// configuration is not read, so no check is marked as required.
+func (p *Provider) GetPullRequestChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*globalEntities.PullRequestChecks, error) {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := "/rest/build-status/1.0/commits/" + url.PathEscape(pr.FromRef.LatestCommit)
+
+	statuses, err := listAll[bitbucketBuildStatus](ctx, p, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get build statuses: %w", err)
+	}
+
+	checks := make([]globalEntities.Check, 0, len(statuses))
+	for _, status := range statuses {
+		check := globalEntities.Check{
+			Name:  status.Name,
+			Kind:  globalEntities.CheckKindStatus,
+			State: bitbucketCheckState(status.State),
+			URL:   status.URL,
+		}
+		if check.Name == "" {
+			check.Name = status.Key
+		}
+		// dateAdded is when the build last reported its state
+		if status.DateAdded != 0 {
+			reportedAt := time.UnixMilli(status.DateAdded).UTC()
+			if check.State == globalEntities.CheckStatePending {
+				check.StartedAt = reportedAt
+			} else {
+				check.CompletedAt = reportedAt
+			}
+		}
+		checks = append(checks, check)
+	}
+	return globalEntities.NewPullRequestChecks(checks), nil
+}
+
+func bitbucketCheckState(state string) globalEntities.CheckState {
+	switch state {
+	case buildStatusSuccessful:
+		return globalEntities.CheckStateSuccess
+	case "FAILED", "CANCELLED":
+		return globalEntities.CheckStateFailure
+	default: // INPROGRESS, UNKNOWN
+		return globalEntities.CheckStatePending
+	}
+}
+
+// MergePullRequest merges the pull request with the merge strategy that matches
+// strategy, or the repository's default strategy when it is empty or unknown.
+// Bitbucket rejects the merge unless the current pull request version is sent,
+// so the pull request is fetched first. WithDeleteSourceBranch deletes the
+// source branch through the branch-utils API after a successful merge, as
+// best-effort cleanup. WithBypassPolicy is silently ignored: merge checks are
+// enforced server-side for every user.
+func (p *Provider) MergePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	strategy string,
+	opts ...globalEntities.MergeOption,
+) error {
+	resolved := globalEntities.ResolveMergeOptions(opts...)
+
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/pull-requests/%d/merge?version=%d",
+		repoPath(repo), prID, pr.Version,
+	)
+
+	body := map[string]any{}
+	if strategyID, ok := mapMergeStrategy(strategy); ok {
+		body["strategyId"] = strategyID
+	}
+
+	if _, mergeErr := p.doRequest(ctx, http.MethodPost, endpoint, body); mergeErr != nil {
+		return fmt.Errorf("failed to merge pull request: %w", mergeErr)
+	}
+
+	if resolved.DeleteSourceBranch {
+		p.deleteBranch(ctx, repo, pr.FromRef.ID)
+	}
+
+	return nil
+}
+
+// deleteBranch deletes a branch after a merge. Failures are logged and never
+// returned, so they cannot fail the merge that already happened.
+func (p *Provider) deleteBranch(ctx context.Context, repo globalEntities.Repository, ref string) {
+	endpoint := fmt.Sprintf(
+		"/rest/branch-utils/1.0/projects/%s/repos/%s/branches",
+		url.PathEscape(repo.Organization), url.PathEscape(repo.Name),
+	)
+
+	body := map[string]any{"name": ref, "dryRun": false}
+	if _, err := p.doRequest(ctx, http.MethodDelete, endpoint, body); err != nil {
+		log.WithFields(log.Fields{
+			"repo":   repo.Organization + "/" + repo.Name,
+			"branch": ref,
+		}).Warnf("failed to delete source branch after merge: %v", err)
+	}
+}
+
+func mapMergeStrategy(strategy string) (string, bool) {
+	strategyMap := map[string]string{
+		"merge":             mergeStrategyNoFastForward,
+		"squash":            mergeStrategySquash,
+		"rebase":            mergeStrategyRebase,
+		"rebaseMerge":       mergeStrategyRebaseMerge,
+		"rebase-merge":      mergeStrategyRebaseMerge,
+		"fast-forward-only": mergeStrategyFastForward,
+	}
+
+	strategyID, ok := strategyMap[strategy]
+	return strategyID, ok
+}
+
+// SubmitPullRequestReview records the verdict as the authenticated user's
+// participant status: ReviewVerdictApprove sets APPROVED, and both
+// ReviewVerdictRequestChanges and ReviewVerdictWaitingForAuthor set NEEDS_WORK.
+// ReviewVerdictComment leaves the status untouched. A non-empty body is posted
+// as a PR-wide comment before the status is set.
+func (p *Provider) SubmitPullRequestReview(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	sub globalEntities.ReviewSubmission,
+) error {
+	status, ok := mapVerdictToParticipantStatus(sub.Verdict)
+	if !ok {
+		return fmt.Errorf("unsupported review verdict %q", sub.Verdict)
+	}
+
+	if sub.Body != "" {
+		if err := p.PostPullRequestComment(ctx, repo, prID, sub.Body); err != nil {
+			return err
+		}
+	}
+
+	if status == "" {
+		return nil
+	}
+
+	slug, err := p.currentUserSlug(ctx)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/pull-requests/%d/participants/%s",
+		repoPath(repo), prID, url.PathEscape(slug),
+	)
+
+	body := map[string]any{"status": status}
+	if _, putErr := p.doRequest(ctx, http.MethodPut, endpoint, body); putErr != nil {
+		return fmt.Errorf("failed to submit pull request review: %w", putErr)
+	}
+
+	return nil
+}
+
+// mapVerdictToParticipantStatus translates a gitforge ReviewVerdict to a
+// Bitbucket participant status. The empty status means "comment only".
+func mapVerdictToParticipantStatus(v globalEntities.ReviewVerdict) (string, bool) {
+	switch v {
+	case globalEntities.ReviewVerdictApprove:
+		return participantStatusApproved, true
+	case globalEntities.ReviewVerdictRequestChanges,
+		globalEntities.ReviewVerdictWaitingForAuthor:
+		return participantStatusNeedsWork, true
+	case globalEntities.ReviewVerdictComment:
+		return "", true
+	}
+	return "", false
+}
+
+// currentUserSlug returns the slug of the authenticated user. The user name is
+// read from the application links "whoami" servlet, which echoes it as plain
+// text, and resolved to its slug once; the result is cached.
+func (p *Provider) currentUserSlug(ctx context.Context) (string, error) {
+	p.userSlugMu.Lock()
+	defer p.userSlugMu.Unlock()
+
+	if p.userSlug != "" {
+		return p.userSlug, nil
+	}
+
+	resp, err := p.send(ctx, http.MethodGet, "/plugins/servlet/applinks/whoami", "", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the authenticated user: %w", err)
+	}
+	username := strings.TrimSpace(string(resp))
+	if username == "" {
+		return "", errors.New("failed to resolve the authenticated user: empty response")
+	}
+
+	resp, err = p.doRequest(ctx, http.MethodGet, apiPath+"/users/"+url.PathEscape(username), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user %q: %w", username, err)
+	}
+
+	var user bitbucketUser
+	if unmarshalErr := json.Unmarshal(resp, &user); unmarshalErr != nil {
+		return "", fmt.Errorf("failed to parse user response: %w", unmarshalErr)
+	}
+
+	p.userSlug = user.Slug
+	return p.userSlug, nil
+}
@@ -22,11 +22,17 @@ const (
 	httpStatusOKMax = 300
 )
 
-// Provider implements ForgeProvider, LocalGitAuthProvider, and MirrorProvider for Codeberg (Forgejo).
+// Provider implements ForgeProvider, FileAccessProvider, ReviewProvider, ChecksProvider, LocalGitAuthProvider,
+// MirrorProvider, MirrorLifecycleProvider, ReleaseProvider, TagProvider, IssueProvider,
+// IssueLinkProvider, WebhookProvider, and PipelineProvider for Codeberg (Forgejo).
+// The same implementation backs the generic gitea provider for self-hosted Gitea and Forgejo
+// instances, see NewInstanceProvider.
 type Provider struct {
-	token      string
-	baseURL    string
-	httpClient *http.Client
+	token       string
+	baseURL     string
+	name        string                     // empty means codeberg
+	serviceType globalEntities.ServiceType // UNKNOWN means CODEBERG
+	httpClient  *http.Client
 }
 
 // NewProvider creates a new Codeberg provider with the given API token.
@@ -51,17 +57,65 @@ func NewProviderWithClient(token string, client *http.Client) globalEntities.For
 	}
 }
 
-func (p *Provider) Name() string      { return providerName }
+// NewInstanceProvider creates a provider for the Gitea or Forgejo instance rooted at
+// baseURL (e.g. "https://git.corp.example"), reporting the given provider name and
+// service type. If client is nil, a default client with the standard timeout is used.
+func NewInstanceProvider(
+	name string,
+	serviceType globalEntities.ServiceType,
+	token, baseURL string,
+	client *http.Client,
+) (*Provider, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid instance URL %q", baseURL)
+	}
+
+	if client == nil {
+		client = &http.Client{
+			Timeout: httpTimeout,
+		}
+	}
+
+	return &Provider{
+		token:       token,
+		baseURL:     baseURL,
+		name:        name,
+		serviceType: serviceType,
+		httpClient:  client,
+	}, nil
+}
+
+func (p *Provider) Name() string {
+	if p.name == "" {
+		return providerName
+	}
+	return p.name
+}
+
 func (p *Provider) AuthToken() string { return p.token }
 
 func (p *Provider) MatchesURL(rawURL string) bool {
-	return strings.Contains(rawURL, "codeberg.org")
+	return strings.Contains(rawURL, p.hostname())
+}
+
+// hostname returns the host name of the instance, without any port.
+func (p *Provider) hostname() string {
+	parsed, err := url.Parse(p.baseURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "codeberg.org"
+	}
+	return parsed.Hostname()
 }
 
 // --- LocalGitAuthProvider ---
 
 func (p *Provider) GetServiceType() globalEntities.ServiceType {
-	return globalEntities.CODEBERG
+	if p.serviceType == globalEntities.UNKNOWN {
+		return globalEntities.CODEBERG
+	}
+	return p.serviceType
 }
 
 func (p *Provider) PrepareCloneURL(cloneURL string) string {
@@ -69,14 +123,14 @@ func (p *Provider) PrepareCloneURL(cloneURL string) string {
 }
 
 func (p *Provider) ConfigureTransport() {
-	// Codeberg doesn't need special transport configuration
+	// Forgejo and Gitea don't need special transport configuration
 }
 
 func (p *Provider) GetAuthMethods(_ string) []transport.AuthMethod {
 	var authMethods []transport.AuthMethod
 
 	if p.token != "" {
-		log.Infof("Using access token to authenticate with Codeberg")
+		log.Infof("Using access token to authenticate with %s", p.hostname())
 		authMethods = append(authMethods, &gohttp.BasicAuth{
 			Username: "token",
 			Password: p.token,
@@ -106,9 +160,9 @@ func (p *Provider) CloneURL(repo globalEntities.Repository) string {
 }
 
 func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
-	host := "codeberg.org"
+	host := p.hostname()
 	if sshAlias != "" {
-		host = fmt.Sprintf("codeberg.org-%s", sshAlias)
+		host = fmt.Sprintf("%s-%s", host, sshAlias)
 	}
 	return fmt.Sprintf("git@%s:%s/%s.git", host, repo.Organization, repo.Name)
 }
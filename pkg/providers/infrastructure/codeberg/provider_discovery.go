@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
 )
@@ -21,7 +22,13 @@ type forgejoRepo struct {
 	Fork          bool   `json:"fork"`
 	Archived      bool   `json:"archived"`
 	Private       bool   `json:"private"`
-	Owner         struct {
+
+	Mirror         bool      `json:"mirror"`
+	MirrorInterval string    `json:"mirror_interval"`
+	MirrorUpdated  time.Time `json:"mirror_updated"`
+	OriginalURL    string    `json:"original_url"`
+
+	Owner struct {
 		Login string `json:"login"`
 	} `json:"owner"`
 }
@@ -30,12 +37,30 @@ func (p *Provider) DiscoverRepositories(
 	ctx context.Context,
 	org string,
 ) ([]globalEntities.Repository, error) {
-	repos, err := p.discoverOrgRepos(ctx, org)
+	repos, err := p.listRepos(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var allRepos []globalEntities.Repository
+	for _, r := range repos {
+		allRepos = append(allRepos, forgejoRepoToDomain(r, org, p.Name()))
+	}
+	return allRepos, nil
+}
+
+// listRepos returns the repositories of organization org, or of user org when
+// no such organization exists.
+func (p *Provider) listRepos(
+	ctx context.Context,
+	org string,
+) ([]forgejoRepo, error) {
+	repos, err := p.listOrgRepos(ctx, org)
 	if err != nil {
 		// Only fall back to user repositories when the organization is definitively not found (HTTP 404).
 		var ae *apiError
 		if errors.As(err, &ae) && ae.StatusCode() == http.StatusNotFound {
-			return p.discoverUserRepos(ctx, org)
+			return p.listUserRepos(ctx, org)
 		}
 
 		return nil, fmt.Errorf("failed to discover org repos for %q: %w", org, err)
@@ -43,11 +68,11 @@ func (p *Provider) DiscoverRepositories(
 	return repos, nil
 }
 
-func (p *Provider) discoverOrgRepos(
+func (p *Provider) listOrgRepos(
 	ctx context.Context,
 	org string,
-) ([]globalEntities.Repository, error) {
-	var allRepos []globalEntities.Repository
+) ([]forgejoRepo, error) {
+	var allRepos []forgejoRepo
 	page := 1
 
 	for {
@@ -70,9 +95,7 @@ func (p *Provider) discoverOrgRepos(
 			break
 		}
 
-		for _, r := range repos {
-			allRepos = append(allRepos, forgejoRepoToDomain(r, org))
-		}
+		allRepos = append(allRepos, repos...)
 
 		if len(repos) < perPage {
 			break
@@ -83,11 +106,11 @@ func (p *Provider) discoverOrgRepos(
 	return allRepos, nil
 }
 
-func (p *Provider) discoverUserRepos(
+func (p *Provider) listUserRepos(
 	ctx context.Context,
 	user string,
-) ([]globalEntities.Repository, error) {
-	var allRepos []globalEntities.Repository
+) ([]forgejoRepo, error) {
+	var allRepos []forgejoRepo
 	page := 1
 
 	for {
@@ -110,9 +133,7 @@ func (p *Provider) discoverUserRepos(
 			break
 		}
 
-		for _, r := range repos {
-			allRepos = append(allRepos, forgejoRepoToDomain(r, user))
-		}
+		allRepos = append(allRepos, repos...)
 
 		if len(repos) < perPage {
 			break
@@ -123,7 +144,7 @@ func (p *Provider) discoverUserRepos(
 	return allRepos, nil
 }
 
-func forgejoRepoToDomain(r forgejoRepo, org string) globalEntities.Repository {
+func forgejoRepoToDomain(r forgejoRepo, org, name string) globalEntities.Repository {
 	defaultBranch := r.DefaultBranch
 	if defaultBranch == "" {
 		defaultBranch = "main"
@@ -135,7 +156,7 @@ func forgejoRepoToDomain(r forgejoRepo, org string) globalEntities.Repository {
 		DefaultBranch: "refs/heads/" + defaultBranch,
 		RemoteURL:     r.CloneURL,
 		SSHURL:        r.SSHURL,
-		ProviderName:  providerName,
+		ProviderName:  name,
 		IsFork:        r.Fork,
 		IsArchived:    r.Archived,
 	}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
 	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
@@ -25,7 +26,13 @@ type forgejoTreeEntry struct {
 }
 
 type forgejoTag struct {
-	Name string `json:"name"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	ID      string `json:"id"` // the tag object for annotated tags, else the commit
+	Commit  struct {
+		SHA     string    `json:"sha"`
+		Created time.Time `json:"created"`
+	} `json:"commit"`
 }
 
 func (p *Provider) GetFileContent(
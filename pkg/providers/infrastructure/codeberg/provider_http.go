@@ -6,7 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
 )
 
 // apiError represents an HTTP API error with the status code preserved.
@@ -24,6 +29,22 @@ func (e *apiError) StatusCode() int {
 	return e.statusCode
 }
 
+// responseError returns the apiError of a failed response, wrapped in a
+// RateLimitError with the Retry-After delay when the request was throttled.
+func responseError(resp *http.Response, body []byte) error {
+	apiErr := &apiError{
+		statusCode: resp.StatusCode,
+		body:       string(body),
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &globalEntities.RateLimitError{
+			RetryAfter: globalEntities.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Err:        apiErr,
+		}
+	}
+	return apiErr
+}
+
 func (p *Provider) doRequest(
 	ctx context.Context,
 	method, endpoint string,
@@ -60,10 +81,61 @@ func (p *Provider) doRequest(
 	}
 
 	if resp.StatusCode < httpStatusOKMin || resp.StatusCode >= httpStatusOKMax {
-		return nil, &apiError{
-			statusCode: resp.StatusCode,
-			body:       string(respBody),
+		return nil, responseError(resp, respBody)
+	}
+
+	return respBody, nil
+}
+
+// doUploadRequest posts content as the multipart file field of endpoint,
+// streaming it rather than buffering the whole file.
+func (p *Provider) doUploadRequest(
+	ctx context.Context,
+	endpoint, field, fileName, contentType string,
+	content io.Reader,
+) ([]byte, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	form := multipart.NewWriter(pipeWriter)
+	go func() {
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(
+			`form-data; name=%q; filename=%q`, field, fileName,
+		))
+		header.Set("Content-Type", contentType)
+
+		part, err := form.CreatePart(header)
+		if err == nil {
+			_, err = io.Copy(part, content)
 		}
+		if err == nil {
+			err = form.Close()
+		}
+		pipeWriter.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+endpoint, pipeReader)
+	if err != nil {
+		pipeReader.CloseWithError(err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "token "+p.token)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < httpStatusOKMin || resp.StatusCode >= httpStatusOKMax {
+		return nil, responseError(resp, respBody)
 	}
 
 	return respBody, nil
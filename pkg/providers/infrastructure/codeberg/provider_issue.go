@@ -0,0 +1,341 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var (
+	errNotAnIssue    = errors.New("not an issue")
+	errLabelNotFound = errors.New("label not found")
+)
+
+type forgejoIssue struct {
+	Number int64  `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	HTMLURL     string    `json:"html_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+type forgejoLabel struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// CreateIssue opens an issue. Forgejo labels issues by label ID, so the
+// repository's labels are listed to resolve input.Labels.
+func (p *Provider) CreateIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.IssueInput,
+) (*globalEntities.Issue, error) {
+	body := map[string]any{
+		"title": input.Title,
+		"body":  input.Body,
+	}
+	if len(input.Labels) > 0 {
+		labelIDs, err := p.resolveLabelIDs(ctx, repo, input.Labels, false)
+		if err != nil {
+			return nil, err
+		}
+		body["labels"] = labelIDs
+	}
+	if len(input.Assignees) > 0 {
+		body["assignees"] = input.Assignees
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/issues", repo.Organization, repo.Name)
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return parseForgejoIssue(resp)
+}
+
+// GetIssue returns the issue with the given number. Forgejo numbers pull
+// requests and issues together; a pull request number is an error.
+func (p *Provider) GetIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+) (*globalEntities.Issue, error) {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d", repo.Organization, repo.Name, id)
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue #%d: %w", id, err)
+	}
+
+	var issue forgejoIssue
+	if unmarshalErr := json.Unmarshal(resp, &issue); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse issue response: %w", unmarshalErr)
+	}
+	if issue.PullRequest != nil {
+		return nil, fmt.Errorf("%w: #%d is a pull request", errNotAnIssue, id)
+	}
+	return forgejoIssueToDomain(issue), nil
+}
+
+func (p *Provider) UpdateIssueState(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	state globalEntities.IssueState,
+) error {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d", repo.Organization, repo.Name, id)
+	if _, err := p.doRequest(ctx, http.MethodPatch, endpoint, map[string]any{"state": string(state)}); err != nil {
+		return fmt.Errorf("failed to set issue #%d %s: %w", id, state, err)
+	}
+	return nil
+}
+
+func (p *Provider) AddIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	labelIDs, err := p.resolveLabelIDs(ctx, repo, labels, false)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/labels", repo.Organization, repo.Name, id)
+	if _, err = p.doRequest(ctx, http.MethodPost, endpoint, map[string]any{"labels": labelIDs}); err != nil {
+		return fmt.Errorf("failed to label issue #%d: %w", id, err)
+	}
+	return nil
+}
+
+// RemoveIssueLabels removes labels from the issue one request each. Labels
+// the repository does not define are skipped along with the ones the issue
+// does not carry.
+func (p *Provider) RemoveIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	labelIDs, err := p.resolveLabelIDs(ctx, repo, labels, true)
+	if err != nil {
+		return err
+	}
+
+	for _, labelID := range labelIDs {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/issues/%d/labels/%d",
+			repo.Organization, repo.Name, id, labelID,
+		)
+		_, err = p.doRequest(ctx, http.MethodDelete, endpoint, nil)
+		var ae *apiError
+		if errors.As(err, &ae) && ae.StatusCode() == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove label %d from issue #%d: %w", labelID, id, err)
+		}
+	}
+	return nil
+}
+
+func (p *Provider) AssignIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	assignees []string,
+) error {
+	if assignees == nil {
+		assignees = []string{}
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d", repo.Organization, repo.Name, id)
+	if _, err := p.doRequest(ctx, http.MethodPatch, endpoint, map[string]any{"assignees": assignees}); err != nil {
+		return fmt.Errorf("failed to assign issue #%d: %w", id, err)
+	}
+	return nil
+}
+
+func (p *Provider) CommentOnIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	body string,
+) (*globalEntities.IssueComment, error) {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/issues/%d/comments", repo.Organization, repo.Name, id)
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, map[string]string{"body": body})
+	if err != nil {
+		return nil, fmt.Errorf("failed to comment on issue #%d: %w", id, err)
+	}
+
+	var comment forgejoComment
+	if unmarshalErr := json.Unmarshal(resp, &comment); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse comment response: %w", unmarshalErr)
+	}
+	return &globalEntities.IssueComment{
+		ID:        comment.ID,
+		Body:      comment.Body,
+		Author:    comment.User.Login,
+		CreatedAt: comment.CreatedAt,
+	}, nil
+}
+
+// SearchIssues lists the repository's issues matching query. Forgejo orders
+// them by creation, so every match is fetched and sorted before Limit applies.
+func (p *Provider) SearchIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.IssueQuery,
+) ([]globalEntities.Issue, error) {
+	params := url.Values{}
+	params.Set("type", "issues")
+	params.Set("state", "all")
+	if query.State != "" {
+		params.Set("state", string(query.State))
+	}
+	if len(query.Labels) > 0 {
+		params.Set("labels", strings.Join(query.Labels, ","))
+	}
+	if query.Assignee != "" {
+		params.Set("assigned_by", query.Assignee)
+	}
+	if query.Text != "" {
+		params.Set("q", query.Text)
+	}
+
+	var allIssues []globalEntities.Issue
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/issues?%s&page=%d&limit=%d",
+			repo.Organization, repo.Name, params.Encode(), page, perPage,
+		)
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		var issues []forgejoIssue
+		if unmarshalErr := json.Unmarshal(resp, &issues); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse issues response: %w", unmarshalErr)
+		}
+
+		for _, issue := range issues {
+			allIssues = append(allIssues, *forgejoIssueToDomain(issue))
+		}
+
+		if len(issues) < perPage {
+			break
+		}
+		page++
+	}
+
+	slices.SortStableFunc(allIssues, func(a, b globalEntities.Issue) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	if query.Limit > 0 && len(allIssues) > query.Limit {
+		allIssues = allIssues[:query.Limit]
+	}
+	return allIssues, nil
+}
+
+// resolveLabelIDs maps label names to the IDs of the repository's labels. A
+// name the repository does not define is an error unless skipMissing is set.
+func (p *Provider) resolveLabelIDs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	names []string,
+	skipMissing bool,
+) ([]int64, error) {
+	labelIDs := map[string]int64{}
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/labels?page=%d&limit=%d",
+			repo.Organization, repo.Name, page, perPage,
+		)
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list labels: %w", err)
+		}
+
+		var labels []forgejoLabel
+		if unmarshalErr := json.Unmarshal(resp, &labels); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse labels response: %w", unmarshalErr)
+		}
+
+		for _, label := range labels {
+			labelIDs[label.Name] = label.ID
+		}
+
+		if len(labels) < perPage {
+			break
+		}
+		page++
+	}
+
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		id, ok := labelIDs[name]
+		if !ok {
+			if skipMissing {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %q", errLabelNotFound, name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func parseForgejoIssue(resp []byte) (*globalEntities.Issue, error) {
+	var issue forgejoIssue
+	if unmarshalErr := json.Unmarshal(resp, &issue); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse issue response: %w", unmarshalErr)
+	}
+	return forgejoIssueToDomain(issue), nil
+}
+
+func forgejoIssueToDomain(issue forgejoIssue) *globalEntities.Issue {
+	result := &globalEntities.Issue{
+		ID:        int(issue.Number),
+		Title:     issue.Title,
+		Body:      issue.Body,
+		State:     globalEntities.IssueState(issue.State),
+		Author:    issue.User.Login,
+		URL:       issue.HTMLURL,
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+	}
+	for _, label := range issue.Labels {
+		result.Labels = append(result.Labels, label.Name)
+	}
+	for _, assignee := range issue.Assignees {
+		result.Assignees = append(result.Assignees, assignee.Login)
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateIssueInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create the issue with its labels resolved to IDs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/labels", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[{"id":3,"name":"bug"},{"id":5,"name":"autoupdate"}]`))
+		})
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/issues", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":12,"title":"Autoupdate failed","state":"open",
+				"labels":[{"name":"autoupdate"}],"assignees":[{"login":"dev"}],"user":{"login":"bot"},
+				"html_url":"https://codeberg.org/my-org/my-repo/issues/12"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		issue, err := p.CreateIssue(context.Background(), repo, globalEntities.IssueInput{
+			Title: "Autoupdate failed", Labels: []string{"autoupdate"}, Assignees: []string{"dev"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []any{float64(5)}, body["labels"])
+		assert.Equal(t, []any{"dev"}, body["assignees"])
+		assert.Equal(t, &globalEntities.Issue{
+			ID:        12,
+			Title:     "Autoupdate failed",
+			State:     globalEntities.IssueStateOpen,
+			Labels:    []string{"autoupdate"},
+			Assignees: []string{"dev"},
+			Author:    "bot",
+			URL:       "https://codeberg.org/my-org/my-repo/issues/12",
+		}, issue)
+	})
+
+	t.Run("should fail when a label is not defined in the repository", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/labels", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.CreateIssue(context.Background(), repo, globalEntities.IssueInput{
+			Title: "x", Labels: []string{"missing"},
+		})
+
+		// then
+		require.ErrorIs(t, err, errLabelNotFound)
+	})
+}
+
+func TestGetIssueInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reject a pull request number", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/issues/4", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"number":4,"pull_request":{"merged":false}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.GetIssue(context.Background(), repo, 4)
+
+		// then
+		require.ErrorIs(t, err, errNotAnIssue)
+	})
+}
+
+func TestSearchIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should filter issues and return the most recently updated first", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var query map[string]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/issues", func(w http.ResponseWriter, r *http.Request) {
+			query = map[string]string{}
+			for key := range r.URL.Query() {
+				query[key] = r.URL.Query().Get(key)
+			}
+			_, _ = w.Write([]byte(`[
+				{"number":1,"state":"open","updated_at":"2026-03-01T00:00:00Z"},
+				{"number":2,"state":"open","updated_at":"2026-03-03T00:00:00Z"},
+				{"number":3,"state":"open","updated_at":"2026-03-02T00:00:00Z"}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		issues, err := p.SearchIssues(context.Background(), repo, globalEntities.IssueQuery{
+			State: globalEntities.IssueStateOpen, Labels: []string{"autoupdate", "ci"}, Assignee: "dev", Limit: 2,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "issues", query["type"])
+		assert.Equal(t, "open", query["state"])
+		assert.Equal(t, "autoupdate,ci", query["labels"])
+		assert.Equal(t, "dev", query["assigned_by"])
+		require.Len(t, issues, 2)
+		assert.Equal(t, 2, issues[0].ID)
+		assert.Equal(t, 3, issues[1].ID)
+	})
+}
@@ -0,0 +1,48 @@
+package codeberg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// LinkPullRequestIssues appends closing keywords ("Closes #n") and references
+// ("Refs #n") to the pull request body. Forgejo closes the former when the
+// pull request merges.
+func (p *Provider) LinkPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	links []globalEntities.IssueLink,
+) error {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return err
+	}
+
+	body := globalEntities.AppendIssueReferences(pr.Body, links)
+	if body == pr.Body {
+		return nil
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d", repo.Organization, repo.Name, prID)
+	if _, err = p.doRequest(ctx, http.MethodPatch, endpoint, map[string]string{"body": body}); err != nil {
+		return fmt.Errorf("failed to link pull request #%d: %w", prID, err)
+	}
+	return nil
+}
+
+// ListPullRequestIssues returns the issues the pull request body references.
+func (p *Provider) ListPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.IssueLink, error) {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	return globalEntities.ParseIssueReferences(pr.Body), nil
+}
@@ -10,6 +10,8 @@ import (
 
 // MigrateRepository creates a mirror repository on Codeberg by migrating from a source URL.
 // When Mirror is true, Codeberg will periodically pull updates from the source.
+// The migrate endpoint answers once the import is done, so progress is reported
+// as running while the request is in flight and completed or failed after it.
 func (p *Provider) MigrateRepository(
 	ctx context.Context,
 	input globalEntities.MirrorInput,
@@ -23,11 +25,18 @@ func (p *Provider) MigrateRepository(
 		"description": input.Description,
 		"service":     input.Service,
 	}
+	if input.AuthUsername != "" || input.AuthPassword != "" {
+		body["auth_username"] = input.AuthUsername
+		body["auth_password"] = input.AuthPassword
+	}
 
+	input.Report(globalEntities.MirrorStateRunning, "migrating "+input.CloneAddr)
 	_, err := p.doRequest(ctx, http.MethodPost, "/api/v1/repos/migrate", body)
 	if err != nil {
+		input.Report(globalEntities.MirrorStateFailed, err.Error())
 		return fmt.Errorf("failed to migrate repository %q: %w", input.RepoName, err)
 	}
 
+	input.Report(globalEntities.MirrorStateCompleted, "")
 	return nil
 }
@@ -0,0 +1,93 @@
+package codeberg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// ListMirrors returns the pull mirrors of org. Forgejo does not report sync
+// errors through the API, so LastError is always empty.
+func (p *Provider) ListMirrors(
+	ctx context.Context,
+	org string,
+) ([]globalEntities.Mirror, error) {
+	repos, err := p.listRepos(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	var mirrors []globalEntities.Mirror
+	for _, r := range repos {
+		if !r.Mirror {
+			continue
+		}
+		mirrors = append(mirrors, forgejoRepoToMirror(r, org, p.Name()))
+	}
+	return mirrors, nil
+}
+
+// SyncMirror queues an immediate sync through the mirror-sync endpoint.
+func (p *Provider) SyncMirror(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) error {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/mirror-sync", repo.Organization, repo.Name)
+	if _, err := p.doRequest(ctx, http.MethodPost, endpoint, nil); err != nil {
+		return fmt.Errorf("failed to sync mirror %q: %w", repo.Name, err)
+	}
+	return nil
+}
+
+// SetMirrorInterval changes the mirror interval. Instances reject intervals
+// below their configured minimum (10 minutes by default) other than zero.
+func (p *Provider) SetMirrorInterval(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	interval time.Duration,
+) error {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s", repo.Organization, repo.Name)
+	body := map[string]any{"mirror_interval": interval.String()}
+	if _, err := p.doRequest(ctx, http.MethodPatch, endpoint, body); err != nil {
+		return fmt.Errorf("failed to set mirror interval of %q: %w", repo.Name, err)
+	}
+	return nil
+}
+
+// ConvertMirror turns the mirror into a normal repository. The convert
+// endpoint is Forgejo-only; plain Gitea instances answer it with 404.
+func (p *Provider) ConvertMirror(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) error {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/convert", repo.Organization, repo.Name)
+	if _, err := p.doRequest(ctx, http.MethodPost, endpoint, nil); err != nil {
+		return fmt.Errorf("failed to convert mirror %q: %w", repo.Name, err)
+	}
+	return nil
+}
+
+func forgejoRepoToMirror(r forgejoRepo, org, name string) globalEntities.Mirror {
+	// an unparsable interval is reported as zero rather than failing the listing
+	interval, _ := time.ParseDuration(r.MirrorInterval)
+	return globalEntities.Mirror{
+		Repository: forgejoRepoToDomain(r, org, name),
+		SourceURL:  withoutCredentials(r.OriginalURL),
+		Interval:   interval,
+		LastSync:   r.MirrorUpdated,
+	}
+}
+
+// withoutCredentials strips any userinfo from rawURL.
+func withoutCredentials(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.User == nil {
+		return rawURL
+	}
+	parsed.User = nil
+	return parsed.String()
+}
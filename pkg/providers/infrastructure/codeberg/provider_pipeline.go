@@ -0,0 +1,216 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// forgejoRun is an Actions run as the Forgejo API returns it. PrettyRef is
+// the branch or tag name without its refs/ prefix.
+type forgejoRun struct {
+	ID         int64     `json:"id"`
+	Title      string    `json:"title"`
+	WorkflowID string    `json:"workflow_id"`
+	PrettyRef  string    `json:"prettyref"`
+	CommitSHA  string    `json:"commit_sha"`
+	Status     string    `json:"status"`
+	HTMLURL    string    `json:"html_url"`
+	Created    time.Time `json:"created"`
+	Stopped    time.Time `json:"stopped"`
+}
+
+// TriggerPipeline dispatches the workflow named by input.Pipeline, its file
+// name such as "ci.yml". Forgejo versions that cannot return the run they
+// queue answer without it, and the run is then returned without an ID.
+func (p *Provider) TriggerPipeline(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PipelineRunInput,
+) (*globalEntities.PipelineRun, error) {
+	if input.Pipeline == "" {
+		return nil, errors.New("a workflow file name is required to trigger a Forgejo Actions run")
+	}
+	if len(input.Variables) > 0 {
+		return nil, fmt.Errorf("Forgejo Actions cannot set variables on a run: %w", errors.ErrUnsupported)
+	}
+
+	ref := input.Ref
+	if ref == "" {
+		ref = strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")
+	}
+	body := map[string]any{"ref": ref, "return_run_info": true}
+	if len(input.Inputs) > 0 {
+		body["inputs"] = input.Inputs
+	}
+
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/actions/workflows/%s/dispatches",
+		repo.Organization, repo.Name, url.PathEscape(input.Pipeline),
+	)
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dispatch workflow %s: %w", input.Pipeline, err)
+	}
+
+	var dispatched struct {
+		ID int64 `json:"id"`
+	}
+	if len(resp) > 0 {
+		if unmarshalErr := json.Unmarshal(resp, &dispatched); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse dispatch response: %w", unmarshalErr)
+		}
+	}
+	if dispatched.ID == 0 {
+		return &globalEntities.PipelineRun{
+			Pipeline: input.Pipeline,
+			Ref:      ref,
+			Status:   globalEntities.PipelineStatusQueued,
+		}, nil
+	}
+	return p.GetPipelineRun(ctx, repo, strconv.FormatInt(dispatched.ID, 10))
+}
+
+// ListPipelineRuns lists the Actions runs of the repository. Forgejo cannot
+// filter runs on a branch, so Branch is matched on the listed runs.
+func (p *Provider) ListPipelineRuns(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.PipelineRunQuery,
+) ([]globalEntities.PipelineRun, error) {
+	var runs []globalEntities.PipelineRun
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/actions/runs?page=%d&limit=%d",
+			repo.Organization, repo.Name, page, perPage,
+		)
+		if query.CommitSHA != "" {
+			endpoint += "&head_sha=" + url.QueryEscape(query.CommitSHA)
+		}
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+
+		var result struct {
+			WorkflowRuns []forgejoRun `json:"workflow_runs"`
+		}
+		if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse workflow runs response: %w", unmarshalErr)
+		}
+
+		for _, run := range result.WorkflowRuns {
+			if query.Branch != "" && run.PrettyRef != query.Branch {
+				continue
+			}
+			runs = append(runs, *forgejoRunToDomain(run))
+			if query.Limit > 0 && len(runs) == query.Limit {
+				return runs, nil
+			}
+		}
+
+		if len(result.WorkflowRuns) < perPage {
+			break
+		}
+		page++
+	}
+
+	return runs, nil
+}
+
+func (p *Provider) GetPipelineRun(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/actions/runs/%s", repo.Organization, repo.Name, runID)
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run %s: %w", runID, err)
+	}
+
+	var run forgejoRun
+	if unmarshalErr := json.Unmarshal(resp, &run); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse workflow run response: %w", unmarshalErr)
+	}
+	return forgejoRunToDomain(run), nil
+}
+
+// ListPipelineJobs is unsupported: Forgejo shows the jobs of a run in the web
+// UI only.
+func (p *Provider) ListPipelineJobs(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ string,
+) ([]globalEntities.PipelineJob, error) {
+	return nil, fmt.Errorf("Forgejo has no API for the jobs of a run: %w", errors.ErrUnsupported)
+}
+
+// CancelPipelineRun is unsupported: Forgejo cancels runs from the web UI only.
+func (p *Provider) CancelPipelineRun(_ context.Context, _ globalEntities.Repository, _ string) error {
+	return fmt.Errorf("Forgejo has no API to cancel a run: %w", errors.ErrUnsupported)
+}
+
+// RetryPipelineRun is unsupported: Forgejo reruns jobs from the web UI only.
+func (p *Provider) RetryPipelineRun(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ string,
+) (*globalEntities.PipelineRun, error) {
+	return nil, fmt.Errorf("Forgejo has no API to rerun a run: %w", errors.ErrUnsupported)
+}
+
+// GetPipelineJobLogs is unsupported: Forgejo serves job logs from the web UI
+// only.
+func (p *Provider) GetPipelineJobLogs(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_, _ string,
+) (io.ReadCloser, error) {
+	return nil, fmt.Errorf("Forgejo has no API for job logs: %w", errors.ErrUnsupported)
+}
+
+func forgejoRunToDomain(run forgejoRun) *globalEntities.PipelineRun {
+	result := &globalEntities.PipelineRun{
+		ID:        strconv.FormatInt(run.ID, 10),
+		Pipeline:  run.WorkflowID,
+		Ref:       run.PrettyRef,
+		CommitSHA: run.CommitSHA,
+		Status:    forgejoPipelineStatus(run.Status),
+		URL:       run.HTMLURL,
+		CreatedAt: run.Created,
+	}
+	if result.Status.IsFinished() {
+		result.FinishedAt = run.Stopped
+	}
+	return result
+}
+
+func forgejoPipelineStatus(status string) globalEntities.PipelineStatus {
+	switch status {
+	case "running":
+		return globalEntities.PipelineStatusRunning
+	case "success":
+		return globalEntities.PipelineStatusSuccess
+	case "failure":
+		return globalEntities.PipelineStatusFailure
+	case "cancelled":
+		return globalEntities.PipelineStatusCanceled
+	case "skipped":
+		return globalEntities.PipelineStatusSkipped
+	default: // waiting, blocked, unknown
+		return globalEntities.PipelineStatusQueued
+	}
+}
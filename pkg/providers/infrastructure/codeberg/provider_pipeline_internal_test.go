@@ -0,0 +1,143 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const testActionRunJSON = `{"id":41,"title":"Fix build","workflow_id":"ci.yml","prettyref":"feature",
+	"commit_sha":"abc123","status":"failure","html_url":"https://codeberg.org/my-org/my-repo/actions/runs/7",
+	"created":"2026-01-02T10:00:00Z","stopped":"2026-01-02T10:05:00Z"}`
+
+func TestTriggerPipelineInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should dispatch the workflow and return the run it queued", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/actions/workflows/ci.yml/dispatches",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":41,"run_number":7,"jobs":["build"]}`))
+			})
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/actions/runs/41", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(testActionRunJSON))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo", DefaultBranch: "refs/heads/main"}
+
+		// when
+		run, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{
+			Pipeline: "ci.yml",
+			Inputs:   map[string]string{"environment": "staging"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"ref": "main", "return_run_info": true, "inputs": map[string]any{"environment": "staging"},
+		}, body)
+		assert.Equal(t, "41", run.ID)
+	})
+
+	t.Run("should return a queued run without ID when Forgejo does not return the run", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/actions/workflows/ci.yml/dispatches",
+			func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		run, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{
+			Pipeline: "ci.yml", Ref: "release",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &globalEntities.PipelineRun{
+			Pipeline: "ci.yml", Ref: "release", Status: globalEntities.PipelineStatusQueued,
+		}, run)
+	})
+}
+
+func TestListPipelineRunsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should filter by commit on the server and by branch on the listed runs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var headSHA string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+			headSHA = r.URL.Query().Get("head_sha")
+			_, _ = w.Write([]byte(`{"total_count":2,"workflow_runs":[
+				{"id":42,"prettyref":"main","commit_sha":"abc123","status":"running"},` + testActionRunJSON + `]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		runs, err := p.ListPipelineRuns(context.Background(), repo, globalEntities.PipelineRunQuery{
+			Branch: "feature", CommitSHA: "abc123",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", headSHA)
+		assert.Equal(t, []globalEntities.PipelineRun{{
+			ID:         "41",
+			Pipeline:   "ci.yml",
+			Ref:        "feature",
+			CommitSHA:  "abc123",
+			Status:     globalEntities.PipelineStatusFailure,
+			URL:        "https://codeberg.org/my-org/my-repo/actions/runs/7",
+			CreatedAt:  time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+			FinishedAt: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC),
+		}}, runs)
+	})
+}
+
+func TestListPipelineJobsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return ErrUnsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		p := &Provider{}
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.ListPipelineJobs(context.Background(), repo, "41")
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
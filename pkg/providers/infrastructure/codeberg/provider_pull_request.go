@@ -13,12 +13,22 @@ import (
 type forgejoPR struct {
 	Number  int    `json:"number"`
 	Title   string `json:"title"`
+	Body    string `json:"body"`
 	HTMLURL string `json:"html_url"`
 	State   string `json:"state"`
-	Head    struct {
+	Draft   bool   `json:"draft"`
+	Merged  bool   `json:"merged"`
+	User    struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Head struct {
 		Label string `json:"label"`
 		Ref   string `json:"ref"`
+		SHA   string `json:"sha"`
 	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
 }
 
 func (p *Provider) CreatePullRequest(
@@ -38,7 +48,7 @@ func (p *Provider) CreatePullRequest(
 		"title": input.Title,
 		"head":  sourceBranch,
 		"base":  targetBranch,
-		"body":  input.Description,
+		"body":  globalEntities.AppendIssueReferences(input.Description, input.IssueLinks),
 	}
 
 	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
@@ -0,0 +1,252 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+type forgejoRelease struct {
+	ID          int64                 `json:"id"`
+	TagName     string                `json:"tag_name"`
+	Name        string                `json:"name"`
+	Body        string                `json:"body"`
+	Draft       bool                  `json:"draft"`
+	Prerelease  bool                  `json:"prerelease"`
+	HTMLURL     string                `json:"html_url"`
+	CreatedAt   time.Time             `json:"created_at"`
+	Attachments []forgejoReleaseAsset `json:"assets"`
+}
+
+type forgejoReleaseAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// CreateRelease publishes a release, creating the tag on input.Target when it
+// does not exist yet. Forgejo's latest release is the newest published one
+// that is not a prerelease, which a new release is unless it is a draft or a
+// prerelease; asking for Latest on those is unsupported.
+func (p *Provider) CreateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	if input.Latest && (input.Draft || input.Prerelease) {
+		return nil, fmt.Errorf(
+			"Forgejo cannot mark a draft or prerelease as latest: %w", errors.ErrUnsupported,
+		)
+	}
+
+	body := forgejoReleaseBody(input)
+	body["tag_name"] = input.TagName
+	if input.Target != "" {
+		body["target_commitish"] = input.Target
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/releases", repo.Organization, repo.Name)
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release %q: %w", input.TagName, err)
+	}
+	return parseForgejoRelease(resp)
+}
+
+// UpdateRelease edits the name, body and flags of the release of
+// input.TagName. Forgejo cannot promote an existing release to latest.
+func (p *Provider) UpdateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	if input.Latest {
+		return nil, fmt.Errorf(
+			"Forgejo cannot mark an existing release as latest: %w", errors.ErrUnsupported,
+		)
+	}
+
+	existing, err := p.getRelease(ctx, repo, input.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/releases/%d", repo.Organization, repo.Name, existing.ID)
+	resp, err := p.doRequest(ctx, http.MethodPatch, endpoint, forgejoReleaseBody(input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update release %q: %w", input.TagName, err)
+	}
+	return parseForgejoRelease(resp)
+}
+
+func (p *Provider) GetRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+) (*globalEntities.Release, error) {
+	release, err := p.getRelease(ctx, repo, tagName)
+	if err != nil {
+		return nil, err
+	}
+	return forgejoReleaseToDomain(*release), nil
+}
+
+func (p *Provider) ListReleases(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Release, error) {
+	var allReleases []globalEntities.Release
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/releases?page=%d&limit=%d",
+			repo.Organization, repo.Name, page, perPage,
+		)
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+
+		var releases []forgejoRelease
+		if unmarshalErr := json.Unmarshal(resp, &releases); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse releases response: %w", unmarshalErr)
+		}
+
+		for _, release := range releases {
+			allReleases = append(allReleases, *forgejoReleaseToDomain(release))
+		}
+
+		if len(releases) < perPage {
+			break
+		}
+		page++
+	}
+
+	return allReleases, nil
+}
+
+// UploadReleaseAsset attaches a file to the release of tagName. The content is
+// streamed, so Size is not needed.
+func (p *Provider) UploadReleaseAsset(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+	asset globalEntities.ReleaseAssetInput,
+) (*globalEntities.ReleaseAsset, error) {
+	release, err := p.getRelease(ctx, repo, tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/releases/%d/assets?name=%s",
+		repo.Organization, repo.Name, release.ID, url.QueryEscape(asset.Name),
+	)
+	resp, err := p.doUploadRequest(
+		ctx, endpoint, "attachment", asset.Name, asset.ContentTypeOrDefault(), asset.Content,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload asset %q: %w", asset.Name, err)
+	}
+
+	var uploaded forgejoReleaseAsset
+	if unmarshalErr := json.Unmarshal(resp, &uploaded); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse asset response: %w", unmarshalErr)
+	}
+	return forgejoAssetToDomain(uploaded), nil
+}
+
+func (p *Provider) DeleteReleaseAsset(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName, assetID string,
+) error {
+	release, err := p.getRelease(ctx, repo, tagName)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/releases/%d/assets/%s",
+		repo.Organization, repo.Name, release.ID, url.PathEscape(assetID),
+	)
+	if _, err = p.doRequest(ctx, http.MethodDelete, endpoint, nil); err != nil {
+		return fmt.Errorf("failed to delete asset %s: %w", assetID, err)
+	}
+	return nil
+}
+
+func (p *Provider) getRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+) (*forgejoRelease, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/releases/tags/%s",
+		repo.Organization, repo.Name, url.PathEscape(tagName),
+	)
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %q: %w", tagName, err)
+	}
+
+	var release forgejoRelease
+	if unmarshalErr := json.Unmarshal(resp, &release); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse release response: %w", unmarshalErr)
+	}
+	return &release, nil
+}
+
+// forgejoReleaseBody maps the editable fields of input.
+func forgejoReleaseBody(input globalEntities.ReleaseInput) map[string]any {
+	return map[string]any{
+		"name":       input.Name,
+		"body":       input.Body,
+		"draft":      input.Draft,
+		"prerelease": input.Prerelease,
+	}
+}
+
+func parseForgejoRelease(resp []byte) (*globalEntities.Release, error) {
+	var release forgejoRelease
+	if unmarshalErr := json.Unmarshal(resp, &release); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse release response: %w", unmarshalErr)
+	}
+	return forgejoReleaseToDomain(release), nil
+}
+
+func forgejoReleaseToDomain(release forgejoRelease) *globalEntities.Release {
+	result := &globalEntities.Release{
+		ID:         strconv.FormatInt(release.ID, 10),
+		TagName:    release.TagName,
+		Name:       release.Name,
+		Body:       release.Body,
+		Draft:      release.Draft,
+		Prerelease: release.Prerelease,
+		URL:        release.HTMLURL,
+		CreatedAt:  release.CreatedAt,
+	}
+	for _, asset := range release.Attachments {
+		result.Assets = append(result.Assets, *forgejoAssetToDomain(asset))
+	}
+	return result
+}
+
+func forgejoAssetToDomain(asset forgejoReleaseAsset) *globalEntities.ReleaseAsset {
+	return &globalEntities.ReleaseAsset{
+		ID:          strconv.FormatInt(asset.ID, 10),
+		Name:        asset.Name,
+		Size:        asset.Size,
+		DownloadURL: asset.BrowserDownloadURL,
+	}
+}
@@ -0,0 +1,124 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateReleaseInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should post the tag, target and flags", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/releases", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":3,"tag_name":"v1.0.0","name":"First","draft":true,
+				"html_url":"https://codeberg.org/my-org/my-repo/releases/tag/v1.0.0",
+				"created_at":"2026-01-02T03:04:05Z"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		release, err := p.CreateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0", Target: "main", Name: "First", Draft: true,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "v1.0.0", body["tag_name"])
+		assert.Equal(t, "main", body["target_commitish"])
+		assert.Equal(t, true, body["draft"])
+		assert.Equal(t, "3", release.ID)
+		assert.True(t, release.Draft)
+	})
+
+	t.Run("should reject a prerelease marked as latest as unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.CreateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0-rc.1", Prerelease: true, Latest: true,
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestUploadReleaseAssetInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should stream the content as the attachment form field", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var gotName, gotFileName, gotType, gotContent string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/releases/tags/v1.0.0", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":3,"tag_name":"v1.0.0"}`))
+		})
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/releases/3/assets", func(w http.ResponseWriter, r *http.Request) {
+			gotName = r.URL.Query().Get("name")
+			file, header, err := r.FormFile("attachment")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			defer file.Close()
+			gotFileName = header.Filename
+			gotType = header.Header.Get("Content-Type")
+			data, _ := io.ReadAll(file)
+			gotContent = string(data)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":8,"name":"app.tar.gz","size":4,
+				"browser_download_url":"https://codeberg.org/attachments/abc"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		asset, err := p.UploadReleaseAsset(context.Background(), repo, "v1.0.0", globalEntities.ReleaseAssetInput{
+			Name: "app.tar.gz", ContentType: "application/gzip", Content: strings.NewReader("data"),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "app.tar.gz", gotName)
+		assert.Equal(t, "app.tar.gz", gotFileName)
+		assert.Equal(t, "application/gzip", gotType)
+		assert.Equal(t, "data", gotContent)
+		assert.Equal(t, globalEntities.ReleaseAsset{
+			ID: "8", Name: "app.tar.gz", Size: 4, DownloadURL: "https://codeberg.org/attachments/abc",
+		}, *asset)
+	})
+}
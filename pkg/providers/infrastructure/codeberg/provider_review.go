@@ -0,0 +1,872 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrThreadStatusUpdateUnsupported is returned by the Codeberg provider when
+// callers attempt to update the status of a review conversation. Forgejo only
+// exposes "resolve conversation" in its web UI; the REST API has no endpoint
+// for it. It wraps errors.ErrUnsupported.
+var ErrThreadStatusUpdateUnsupported = fmt.Errorf(
+	"updating pull request thread status is not supported on Codeberg: %w", errors.ErrUnsupported,
+)
+
+// ErrReviewBodyRequired signals that SubmitPullRequestReview was called with
+// ReviewVerdictRequestChanges but no body. Forgejo rejects a REQUEST_CHANGES
+// review without a body or inline comments with HTTP 422, so the caller is
+// told up-front instead.
+var ErrReviewBodyRequired = errors.New("review body is required for this verdict")
+
+// errReviewCommentNotFound is returned by ReplyToThread when the thread ID does
+// not match any inline review comment on the pull request.
+var errReviewCommentNotFound = errors.New("pull request review comment not found")
+
+// Forgejo review event strings accepted by the pull request reviews endpoint.
+const (
+	reviewEventApprove        = "APPROVED"
+	reviewEventRequestChanges = "REQUEST_CHANGES"
+	reviewEventComment        = "COMMENT"
+)
+
+// Forgejo merge styles accepted by the `Do` field of the merge endpoint.
+const (
+	mergeStyleMerge       = "merge"
+	mergeStyleRebase      = "rebase"
+	mergeStyleRebaseMerge = "rebase-merge"
+	mergeStyleSquash      = "squash"
+	mergeStyleFastForward = "fast-forward-only"
+)
+
+// selfReviewErrFragment is the substring Forgejo puts in the 422 response body
+// when the authenticated user approves or rejects their own pull request
+// ("approve your own pull is not allowed" / "reject your own pull is not
+// allowed").
+const selfReviewErrFragment = "your own pull is not allowed"
+
+type forgejoChangedFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+}
+
+type forgejoComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type forgejoReview struct {
+	ID int64 `json:"id"`
+}
+
+type forgejoReviewComment struct {
+	forgejoComment
+
+	Path             string `json:"path"`
+	Position         int    `json:"position"`
+	OriginalPosition int    `json:"original_position"`
+}
+
+type forgejoCombinedStatus struct {
+	State      string                `json:"state"`
+	TotalCount int                   `json:"total_count"`
+	Statuses   []forgejoCommitStatus `json:"statuses"`
+}
+
+type forgejoCommitStatus struct {
+	Context   string    `json:"context"`
+	Status    string    `json:"status"`
+	TargetURL string    `json:"target_url"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// --- ReviewProvider ---
+
+func (p *Provider) ListOpenPullRequests(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.PullRequestDetail, error) {
+	var allPRs []globalEntities.PullRequestDetail
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/pulls?state=open&page=%d&limit=%d",
+			repo.Organization, repo.Name, page, perPage,
+		)
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open pull requests: %w", err)
+		}
+
+		var prs []forgejoPR
+		if unmarshalErr := json.Unmarshal(resp, &prs); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse pull requests response: %w", unmarshalErr)
+		}
+
+		for _, pr := range prs {
+			allPRs = append(allPRs, globalEntities.PullRequestDetail{
+				ID:           pr.Number,
+				Title:        pr.Title,
+				URL:          pr.HTMLURL,
+				Status:       pr.State,
+				SourceBranch: pr.Head.Ref,
+				TargetBranch: pr.Base.Ref,
+				Author:       pr.User.Login,
+				IsDraft:      pr.Draft,
+			})
+		}
+
+		if len(prs) < perPage {
+			break
+		}
+		page++
+	}
+
+	return allPRs, nil
+}
+
+func (p *Provider) GetPullRequestDiff(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/pulls/%d.diff",
+		repo.Organization, repo.Name, prID,
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+
+	return string(resp), nil
+}
+
+// GetPullRequestFiles lists the changed files with their line counts. The
+// Forgejo files endpoint does not return per-file patches, so the PR's `.diff`
+// is fetched once and split by file to fill PullRequestFile.Patch.
+func (p *Provider) GetPullRequestFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestFile, error) {
+	var changed []forgejoChangedFile
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/pulls/%d/files?page=%d&limit=%d",
+			repo.Organization, repo.Name, prID, page, perPage,
+		)
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull request files: %w", err)
+		}
+
+		var files []forgejoChangedFile
+		if unmarshalErr := json.Unmarshal(resp, &files); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse pull request files response: %w", unmarshalErr)
+		}
+		changed = append(changed, files...)
+
+		if len(files) < perPage {
+			break
+		}
+		page++
+	}
+
+	diff, err := p.GetPullRequestDiff(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	patches := globalHelpers.SplitDiffByFile(diff)
+
+	result := make([]globalEntities.PullRequestFile, 0, len(changed))
+	for _, f := range changed {
+		result = append(result, globalEntities.PullRequestFile{
+			Path:      f.Filename,
+			OldPath:   f.PreviousFilename,
+			Status:    mapForgejoFileStatus(f.Status),
+			Additions: f.Additions,
+			Deletions: f.Deletions,
+			Patch:     patches[f.Filename],
+		})
+	}
+
+	return result, nil
+}
+
+// mapForgejoFileStatus translates Forgejo's changed-file status to the
+// vocabulary used by PullRequestFile. Forgejo reports edits as "changed".
+func mapForgejoFileStatus(status string) string {
+	if status == "changed" {
+		return "modified"
+	}
+	return status
+}
+
+// ListPullRequestComments returns the PR-wide issue comments followed by the
+// inline comments of every review. Forgejo has no thread object: the web UI
+// groups inline comments into a conversation by file and line. The same
+// grouping is applied here, and the earliest comment of each conversation is
+// its root — ThreadID is the root's ID and every later comment carries it as
+// InReplyToID. PR-wide comments have ThreadID zero, matching GitHub.
+func (p *Provider) ListPullRequestComments(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestComment, error) {
+	issueComments, err := p.listIssueComments(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewComments, err := p.listReviewComments(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]globalEntities.PullRequestComment, 0, len(issueComments)+len(reviewComments))
+	for _, c := range issueComments {
+		out = append(out, globalEntities.PullRequestComment{
+			ID:     c.ID,
+			Body:   c.Body,
+			Author: c.User.Login,
+		})
+	}
+
+	roots := make(map[string]int64)
+	for _, c := range reviewComments {
+		key := reviewCommentThreadKey(c)
+		rootID, seen := roots[key]
+		if !seen {
+			rootID = c.ID
+			roots[key] = rootID
+		}
+
+		comment := globalEntities.PullRequestComment{
+			ID:       c.ID,
+			ThreadID: rootID,
+			Body:     c.Body,
+			Author:   c.User.Login,
+			FilePath: c.Path,
+			Line:     reviewCommentLine(c),
+		}
+		if c.ID != rootID {
+			comment.InReplyToID = rootID
+		}
+		out = append(out, comment)
+	}
+
+	return out, nil
+}
+
+// listIssueComments returns the PR-wide comments. Forgejo exposes them through
+// the issue comments endpoint because every pull request is also an issue.
+func (p *Provider) listIssueComments(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]forgejoComment, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/issues/%d/comments",
+		repo.Organization, repo.Name, prID,
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issue comments: %w", err)
+	}
+
+	var comments []forgejoComment
+	if unmarshalErr := json.Unmarshal(resp, &comments); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse issue comments response: %w", unmarshalErr)
+	}
+
+	return comments, nil
+}
+
+// listReviewComments walks every review on the pull request and returns their
+// inline comments sorted by ID, i.e. in creation order.
+func (p *Provider) listReviewComments(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]forgejoReviewComment, error) {
+	reviews, err := p.listReviews(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []forgejoReviewComment
+	for _, r := range reviews {
+		comments, commentsErr := p.listReviewCommentsOf(ctx, repo, prID, r.ID)
+		if commentsErr != nil {
+			return nil, commentsErr
+		}
+		all = append(all, comments...)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+	return all, nil
+}
+
+func (p *Provider) listReviews(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]forgejoReview, error) {
+	var all []forgejoReview
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/pulls/%d/reviews?page=%d&limit=%d",
+			repo.Organization, repo.Name, prID, page, perPage,
+		)
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pull request reviews: %w", err)
+		}
+
+		var reviews []forgejoReview
+		if unmarshalErr := json.Unmarshal(resp, &reviews); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse pull request reviews response: %w", unmarshalErr)
+		}
+		all = append(all, reviews...)
+
+		if len(reviews) < perPage {
+			break
+		}
+		page++
+	}
+
+	return all, nil
+}
+
+func (p *Provider) listReviewCommentsOf(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	reviewID int64,
+) ([]forgejoReviewComment, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/pulls/%d/reviews/%d/comments",
+		repo.Organization, repo.Name, prID, reviewID,
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review comments: %w", err)
+	}
+
+	var comments []forgejoReviewComment
+	if unmarshalErr := json.Unmarshal(resp, &comments); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse review comments response: %w", unmarshalErr)
+	}
+
+	return comments, nil
+}
+
+// reviewCommentLine returns the line an inline comment is anchored to: the
+// new-side position, or the old-side position for comments on removed lines.
+func reviewCommentLine(c forgejoReviewComment) int {
+	if c.Position > 0 {
+		return c.Position
+	}
+	return c.OriginalPosition
+}
+
+// reviewCommentThreadKey identifies the conversation an inline comment
+// belongs to. Old-side lines are negated so they never collide with new-side
+// lines of the same number.
+func reviewCommentThreadKey(c forgejoReviewComment) string {
+	line := c.Position
+	if line == 0 {
+		line = -c.OriginalPosition
+	}
+	return fmt.Sprintf("%s:%d", c.Path, line)
+}
+
+// PostPullRequestComment posts a PR-wide comment through the issue comments
+// endpoint. Forgejo has no thread-status concept on comments, so any
+// entities.WithThreadStatus value supplied by the caller is silently ignored.
+func (p *Provider) PostPullRequestComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	body string,
+	_ ...globalEntities.CommentOption,
+) error {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/issues/%d/comments",
+		repo.Organization, repo.Name, prID,
+	)
+
+	if _, err := p.doRequest(ctx, http.MethodPost, endpoint, map[string]any{"body": body}); err != nil {
+		return fmt.Errorf("failed to post pull request comment: %w", err)
+	}
+
+	return nil
+}
+
+// PostPullRequestThreadComment posts an inline comment on a file and new-side
+// line by submitting a COMMENT review carrying a single inline comment, which
+// is the only way the Forgejo API creates inline comments. The returned ID is
+// the new comment's ID, so it can be passed to ReplyToThread when it opens a
+// new conversation. Any entities.WithThreadStatus value is silently ignored.
+func (p *Provider) PostPullRequestThreadComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	filePath string,
+	line int,
+	body string,
+	_ ...globalEntities.CommentOption,
+) (int, error) {
+	id, err := p.createInlineComment(ctx, repo, prID, filePath, line, 0, body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post pull request thread comment: %w", err)
+	}
+	return int(id), nil
+}
+
+// ReplyToThread appends a comment to an existing conversation. The Forgejo API
+// has no "reply to comment" endpoint; the web UI groups inline comments by file
+// and line, so the reply is posted as a new inline comment at the root
+// comment's position and lands in the same conversation. `threadID` is the
+// root comment ID carried on PullRequestComment.ThreadID.
+func (p *Provider) ReplyToThread(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	body string,
+) (int, error) {
+	comments, err := p.listReviewComments(ctx, repo, prID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range comments {
+		if c.ID != int64(threadID) {
+			continue
+		}
+		id, replyErr := p.createInlineComment(
+			ctx, repo, prID, c.Path, c.Position, c.OriginalPosition, body,
+		)
+		if replyErr != nil {
+			return 0, fmt.Errorf("failed to reply to pull request thread: %w", replyErr)
+		}
+		return int(id), nil
+	}
+
+	return 0, fmt.Errorf("%w: comment %d on pull request #%d", errReviewCommentNotFound, threadID, prID)
+}
+
+// createInlineComment submits a COMMENT review with one inline comment and
+// returns the ID of the created comment.
+func (p *Provider) createInlineComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	filePath string,
+	newLine, oldLine int,
+	body string,
+) (int64, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/pulls/%d/reviews",
+		repo.Organization, repo.Name, prID,
+	)
+
+	comment := map[string]any{"path": filePath, "body": body}
+	if newLine > 0 {
+		comment["new_position"] = newLine
+	} else {
+		comment["old_position"] = oldLine
+	}
+	reqBody := map[string]any{
+		"event":    reviewEventComment,
+		"comments": []map[string]any{comment},
+	}
+
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, reqBody)
+	if err != nil {
+		return 0, err
+	}
+
+	var review forgejoReview
+	if unmarshalErr := json.Unmarshal(resp, &review); unmarshalErr != nil {
+		return 0, fmt.Errorf("failed to parse review response: %w", unmarshalErr)
+	}
+
+	comments, err := p.listReviewCommentsOf(ctx, repo, prID, review.ID)
+	if err != nil {
+		return 0, err
+	}
+	if len(comments) == 0 {
+		return 0, nil
+	}
+
+	return comments[0].ID, nil
+}
+
+// UpdatePullRequestThreadStatus is not supported on Codeberg: Forgejo exposes
+// conversation resolution only in its web UI. This method always returns
+// ErrThreadStatusUpdateUnsupported.
+func (p *Provider) UpdatePullRequestThreadStatus(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_, _ int,
+	_ string,
+) error {
+	return ErrThreadStatusUpdateUnsupported
+}
+
+func (p *Provider) getPullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*forgejoPR, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/pulls/%d",
+		repo.Organization, repo.Name, prID,
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	var pr forgejoPR
+	if unmarshalErr := json.Unmarshal(resp, &pr); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request response: %w", unmarshalErr)
+	}
+
+	return &pr, nil
+}
+
+// GetPullRequestStatus returns "open" or "closed" as reported by Forgejo, or
+// "merged" for closed pull requests that were merged.
+func (p *Provider) GetPullRequestStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return "", err
+	}
+
+	if pr.Merged {
+		return "merged", nil
+	}
+
+	return pr.State, nil
+}
+
+// GetPullRequestCheckStatus reports whether the combined commit status of the
+// pull request's head commit is "success". A head commit without any status
+// has no CI configured and is treated as passing.
+func (p *Provider) GetPullRequestCheckStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (bool, error) {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return false, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/commits/%s/status",
+		repo.Organization, repo.Name, pr.Head.SHA,
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get combined status: %w", err)
+	}
+
+	var status forgejoCombinedStatus
+	if unmarshalErr := json.Unmarshal(resp, &status); unmarshalErr != nil {
+		return false, fmt.Errorf("failed to parse combined status response: %w", unmarshalErr)
+	}
+
+	if status.TotalCount == 0 {
+		return true, nil
+	}
+
+	return status.State == "success", nil
+}
+
+// GetPullRequestChecks returns the latest status posted per context on the
+// pull request's head commit. A status is required when the base branch's
+// protection requires its context.
+func (p *Provider) GetPullRequestChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*globalEntities.PullRequestChecks, error) {
+	pr, err := p.getPullRequest(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := p.requiredStatusContexts(ctx, repo, pr.Base.Ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []globalEntities.Check
+	for page := 1; ; page++ {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/commits/%s/status?page=%d&limit=%d",
+			repo.Organization, repo.Name, pr.Head.SHA, page, perPage,
+		)
+
+		resp, reqErr := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if reqErr != nil {
+			return nil, fmt.Errorf("failed to get combined status: %w", reqErr)
+		}
+
+		var status forgejoCombinedStatus
+		if unmarshalErr := json.Unmarshal(resp, &status); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse combined status response: %w", unmarshalErr)
+		}
+
+		for _, commitStatus := range status.Statuses {
+			check := globalEntities.Check{
+				Name:      commitStatus.Context,
+				Kind:      globalEntities.CheckKindStatus,
+				State:     forgejoCheckState(commitStatus.Status),
+				Required:  matchesAnyContext(required, commitStatus.Context),
+				URL:       commitStatus.TargetURL,
+				StartedAt: commitStatus.CreatedAt,
+			}
+			if check.State != globalEntities.CheckStatePending {
+				check.CompletedAt = commitStatus.UpdatedAt
+			}
+			checks = append(checks, check)
+		}
+		if len(status.Statuses) == 0 || len(checks) >= status.TotalCount {
+			break
+		}
+	}
+	return globalEntities.NewPullRequestChecks(checks), nil
+}
+
+// requiredStatusContexts returns the status check patterns the protection of
+// branch requires, or none when the branch does not require status checks.
+func (p *Provider) requiredStatusContexts(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	branch string,
+) ([]string, error) {
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/branches/%s",
+		repo.Organization, repo.Name, url.PathEscape(branch),
+	)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch %s: %w", branch, err)
+	}
+
+	var result struct {
+		EnableStatusCheck   bool     `json:"enable_status_check"`
+		StatusCheckContexts []string `json:"status_check_contexts"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse branch response: %w", unmarshalErr)
+	}
+	if !result.EnableStatusCheck {
+		return nil, nil
+	}
+	return result.StatusCheckContexts, nil
+}
+
+// matchesAnyContext reports whether statusContext matches one of the glob
+// patterns branch protections list required contexts with.
+func matchesAnyContext(patterns []string, statusContext string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, statusContext); matched || pattern == statusContext {
+			return true
+		}
+	}
+	return false
+}
+
+func forgejoCheckState(status string) globalEntities.CheckState {
+	switch status {
+	case "success":
+		return globalEntities.CheckStateSuccess
+	case "error", "failure":
+		return globalEntities.CheckStateFailure
+	case "warning":
+		return globalEntities.CheckStateNeutral
+	default: // pending
+		return globalEntities.CheckStatePending
+	}
+}
+
+// MergePullRequest merges the pull request with the Forgejo merge style that
+// matches strategy ("squash" when empty or unknown). WithDeleteSourceBranch
+// maps to `delete_branch_after_merge`, so Forgejo removes the head branch
+// server-side (it never touches branches in a fork). WithBypassPolicy maps to
+// `force_merge`, which lets repository admins merge despite failing status
+// checks or missing approvals.
+func (p *Provider) MergePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	strategy string,
+	opts ...globalEntities.MergeOption,
+) error {
+	resolved := globalEntities.ResolveMergeOptions(opts...)
+
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/pulls/%d/merge",
+		repo.Organization, repo.Name, prID,
+	)
+
+	body := map[string]any{
+		"Do":                        mapForgejoMergeStyle(strategy),
+		"delete_branch_after_merge": resolved.DeleteSourceBranch,
+	}
+	if resolved.Enabled {
+		body["force_merge"] = true
+	}
+
+	if _, err := p.doRequest(ctx, http.MethodPost, endpoint, body); err != nil {
+		return fmt.Errorf("failed to merge pull request: %w", err)
+	}
+
+	return nil
+}
+
+func mapForgejoMergeStyle(strategy string) string {
+	styleMap := map[string]string{
+		"squash":            mergeStyleSquash,
+		"merge":             mergeStyleMerge,
+		"rebase":            mergeStyleRebase,
+		"rebaseMerge":       mergeStyleRebaseMerge,
+		"rebase-merge":      mergeStyleRebaseMerge,
+		"fast-forward-only": mergeStyleFastForward,
+	}
+
+	if style, ok := styleMap[strategy]; ok {
+		return style
+	}
+
+	return mergeStyleSquash
+}
+
+// SubmitPullRequestReview records a native pull request review on Forgejo. The
+// verdict maps to the review `event`: ReviewVerdictApprove to APPROVED,
+// ReviewVerdictRequestChanges to REQUEST_CHANGES, and both
+// ReviewVerdictWaitingForAuthor and ReviewVerdictComment to COMMENT, mirroring
+// the GitHub mapping.
+//
+// A COMMENT review with an empty body is skipped without an API call, and a
+// REQUEST_CHANGES review with an empty body returns ErrReviewBodyRequired
+// because Forgejo rejects both with HTTP 422. A self-review attempt (Forgejo
+// answers 422 "approve your own pull is not allowed") is logged and swallowed
+// so the caller's fallback comment path still runs, as on GitHub.
+func (p *Provider) SubmitPullRequestReview(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	sub globalEntities.ReviewSubmission,
+) error {
+	event, ok := mapVerdictToReviewEvent(sub.Verdict)
+	if !ok {
+		return fmt.Errorf("unsupported review verdict %q", sub.Verdict)
+	}
+
+	if event == reviewEventComment && sub.Body == "" {
+		return nil
+	}
+
+	if event == reviewEventRequestChanges && sub.Body == "" {
+		return fmt.Errorf("%w: verdict %q requires a non-empty body on Codeberg",
+			ErrReviewBodyRequired, sub.Verdict)
+	}
+
+	endpoint := fmt.Sprintf(
+		"/api/v1/repos/%s/%s/pulls/%d/reviews",
+		repo.Organization, repo.Name, prID,
+	)
+
+	body := map[string]any{"event": event}
+	if sub.Body != "" {
+		body["body"] = sub.Body
+	}
+
+	if _, err := p.doRequest(ctx, http.MethodPost, endpoint, body); err != nil {
+		if isSelfReviewError(err) {
+			log.WithFields(log.Fields{
+				"repo":    repo.Organization + "/" + repo.Name,
+				"prID":    prID,
+				"verdict": sub.Verdict,
+			}).Warnf(
+				"Codeberg rejected native review submission (self-review): %v",
+				err,
+			)
+			return nil
+		}
+		return fmt.Errorf("failed to submit pull request review: %w", err)
+	}
+
+	return nil
+}
+
+// isSelfReviewError reports whether err is a Forgejo 422 caused by the
+// authenticated user reviewing their own pull request.
+func isSelfReviewError(err error) bool {
+	var ae *apiError
+	if !errors.As(err, &ae) || ae.StatusCode() != http.StatusUnprocessableEntity {
+		return false
+	}
+	return strings.Contains(ae.body, selfReviewErrFragment)
+}
+
+// mapVerdictToReviewEvent translates a gitforge ReviewVerdict to the Forgejo
+// review `event`. Forgejo has no "waiting on author" state, so
+// ReviewVerdictWaitingForAuthor collapses to COMMENT, as on GitHub.
+func mapVerdictToReviewEvent(v globalEntities.ReviewVerdict) (string, bool) {
+	switch v {
+	case globalEntities.ReviewVerdictApprove:
+		return reviewEventApprove, true
+	case globalEntities.ReviewVerdictRequestChanges:
+		return reviewEventRequestChanges, true
+	case globalEntities.ReviewVerdictWaitingForAuthor,
+		globalEntities.ReviewVerdictComment:
+		return reviewEventComment, true
+	}
+	return "", false
+}
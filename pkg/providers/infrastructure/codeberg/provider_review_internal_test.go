@@ -0,0 +1,580 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const reviewsPath = "/api/v1/repos/my-org/my-repo/pulls/5/reviews"
+
+// registerCommentFixtures wires one PR-wide comment plus two reviews whose
+// inline comments form a single conversation on main.go:12.
+func registerCommentFixtures(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/issues/5/comments", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":1,"body":"LGTM overall","user":{"login":"alice"}}]`))
+	})
+	mux.HandleFunc("GET "+reviewsPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":100},{"id":101}]`))
+	})
+	mux.HandleFunc("GET "+reviewsPath+"/100/comments", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":20,"body":"nit","user":{"login":"bot"},"path":"main.go","position":12}]`))
+	})
+	mux.HandleFunc("GET "+reviewsPath+"/101/comments", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id":21,"body":"done","user":{"login":"alice"},"path":"main.go","position":12}]`))
+	})
+}
+
+func TestProviderImplementsReviewProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should satisfy ReviewProvider when created by NewProvider", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		provider := NewProvider("token")
+
+		// when
+		_, ok := provider.(globalEntities.ReviewProvider)
+
+		// then
+		assert.True(t, ok)
+	})
+}
+
+func TestListOpenPullRequestsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should map open pull requests when the API returns them", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "open", r.URL.Query().Get("state"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"number":5,"title":"Add feature","state":"open","draft":true,
+				"user":{"login":"alice"},"head":{"ref":"feat"},"base":{"ref":"main"}}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		prs, err := p.ListOpenPullRequests(context.Background(), repo)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.Equal(t, 5, prs[0].ID)
+		assert.Equal(t, "feat", prs[0].SourceBranch)
+		assert.Equal(t, "main", prs[0].TargetBranch)
+		assert.Equal(t, "alice", prs[0].Author)
+		assert.True(t, prs[0].IsDraft)
+	})
+}
+
+func TestGetPullRequestFilesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should attach per-file patches split from the pull request diff", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		diff := "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n" +
+			"diff --git a/b.go b/b.go\nnew file mode 100644\n--- /dev/null\n+++ b/b.go\n@@ -0,0 +1 @@\n+hi\n"
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/pulls/5/files", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"filename":"a.go","status":"changed","additions":1,"deletions":1},
+				{"filename":"b.go","status":"added","additions":1}]`))
+		})
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/pulls/5.diff", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(diff))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		files, err := p.GetPullRequestFiles(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Equal(t, "modified", files[0].Status)
+		assert.Equal(t, 1, files[0].Additions)
+		assert.Contains(t, files[0].Patch, "+new")
+		assert.NotContains(t, files[0].Patch, "b.go")
+		assert.Equal(t, "added", files[1].Status)
+		assert.Contains(t, files[1].Patch, "+hi")
+	})
+}
+
+func TestListPullRequestCommentsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should group inline comments on the same line into one thread", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		registerCommentFixtures(mux)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		comments, err := p.ListPullRequestComments(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, comments, 3)
+		assert.Equal(t, int64(0), comments[0].ThreadID)
+		assert.Equal(t, int64(20), comments[1].ThreadID)
+		assert.Equal(t, "main.go", comments[1].FilePath)
+		assert.Equal(t, 12, comments[1].Line)
+		assert.Equal(t, int64(20), comments[2].ThreadID)
+		assert.Equal(t, int64(20), comments[2].InReplyToID)
+	})
+}
+
+func TestReplyToThreadInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should post an inline comment at the root comment position", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var capturedBody map[string]any
+		mux := http.NewServeMux()
+		registerCommentFixtures(mux)
+		mux.HandleFunc("POST "+reviewsPath, func(w http.ResponseWriter, r *http.Request) {
+			defer func() { _ = r.Body.Close() }()
+			_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":102}`))
+		})
+		mux.HandleFunc("GET "+reviewsPath+"/102/comments", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"id":22,"path":"main.go","position":12}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		replyID, err := p.ReplyToThread(context.Background(), repo, 5, 20, "thanks")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 22, replyID)
+		assert.Equal(t, reviewEventComment, capturedBody["event"])
+		comments, ok := capturedBody["comments"].([]any)
+		require.True(t, ok)
+		require.Len(t, comments, 1)
+		comment, ok := comments[0].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "main.go", comment["path"])
+		assert.InDelta(t, 12, comment["new_position"], 0)
+	})
+
+	t.Run("should return an error when the thread does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		registerCommentFixtures(mux)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.ReplyToThread(context.Background(), repo, 5, 999, "thanks")
+
+		// then
+		require.ErrorIs(t, err, errReviewCommentNotFound)
+	})
+}
+
+func TestUpdatePullRequestThreadStatusInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the unsupported sentinel", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		p := &Provider{}
+
+		// when
+		err := p.UpdatePullRequestThreadStatus(context.Background(), globalEntities.Repository{}, 5, 20, "fixed")
+
+		// then
+		require.ErrorIs(t, err, ErrThreadStatusUpdateUnsupported)
+		assert.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestGetPullRequestStatusInternal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+	}{
+		{name: "should return open when the pull request is open", payload: `{"state":"open"}`, expected: "open"},
+		{name: "should return closed when closed without merge", payload: `{"state":"closed"}`, expected: "closed"},
+		{name: "should return merged when closed with merge", payload: `{"state":"closed","merged":true}`, expected: "merged"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.payload))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			status, err := p.GetPullRequestStatus(context.Background(), repo, 5)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, status)
+		})
+	}
+}
+
+func TestGetPullRequestStatusRateLimitedInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return a rate limit error with the Retry-After delay when throttled", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "15")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.GetPullRequestStatus(context.Background(), repo, 5)
+
+		// then
+		var rateLimitErr *globalEntities.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, 15*time.Second, rateLimitErr.RetryAfter)
+	})
+}
+
+func TestGetPullRequestCheckStatusInternal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		payload  string
+		expected bool
+	}{
+		{name: "should pass when the head commit has no statuses", payload: `{"state":"","total_count":0}`, expected: true},
+		{name: "should pass when the combined state is success", payload: `{"state":"success","total_count":2}`, expected: true},
+		{name: "should fail when the combined state is pending", payload: `{"state":"pending","total_count":1}`, expected: false},
+		{name: "should fail when the combined state is failure", payload: `{"state":"failure","total_count":1}`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"number":5,"head":{"sha":"abc123"}}`))
+			})
+			mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/commits/abc123/status", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.payload))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			passed, err := p.GetPullRequestCheckStatus(context.Background(), repo, 5)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, passed)
+		})
+	}
+}
+
+func TestGetPullRequestChecksInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should map the statuses and require the contexts the base branch protects", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/pulls/5", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"number":5,"head":{"sha":"abc123"},"base":{"ref":"main"}}`))
+		})
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/branches/main", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name":"main","enable_status_check":true,"status_check_contexts":["ci/*"]}`))
+		})
+		mux.HandleFunc("GET /api/v1/repos/my-org/my-repo/commits/abc123/status", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"state":"warning","total_count":2,"statuses":[
+				{"context":"ci/build","status":"success","target_url":"https://ci.example.com/1",
+					"created_at":"2026-01-02T10:00:00Z","updated_at":"2026-01-02T10:05:00Z"},
+				{"context":"lint","status":"warning","created_at":"2026-01-02T10:00:00Z",
+					"updated_at":"2026-01-02T10:01:00Z"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		checks, err := p.GetPullRequestChecks(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &globalEntities.PullRequestChecks{
+			Checks: []globalEntities.Check{
+				{
+					Name:        "ci/build",
+					Kind:        globalEntities.CheckKindStatus,
+					State:       globalEntities.CheckStateSuccess,
+					Required:    true,
+					URL:         "https://ci.example.com/1",
+					StartedAt:   time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+					CompletedAt: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC),
+				},
+				{
+					Name:        "lint",
+					Kind:        globalEntities.CheckKindStatus,
+					State:       globalEntities.CheckStateNeutral,
+					StartedAt:   time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+					CompletedAt: time.Date(2026, 1, 2, 10, 1, 0, 0, time.UTC),
+				},
+			},
+			Verdict: globalEntities.CheckStateSuccess,
+		}, checks)
+	})
+}
+
+func TestMergePullRequestInternal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		strategy     string
+		opts         []globalEntities.MergeOption
+		style        string
+		deleteBranch bool
+		force        any
+	}{
+		{name: "should squash by default", strategy: "", style: "squash"},
+		{name: "should map rebaseMerge to rebase-merge", strategy: "rebaseMerge", style: "rebase-merge"},
+		{
+			name:         "should delete the head branch when WithDeleteSourceBranch is set",
+			strategy:     "merge",
+			opts:         []globalEntities.MergeOption{globalEntities.WithDeleteSourceBranch()},
+			style:        "merge",
+			deleteBranch: true,
+		},
+		{
+			name:     "should force the merge when WithBypassPolicy is set",
+			strategy: "squash",
+			opts:     []globalEntities.MergeOption{globalEntities.WithBypassPolicy("hotfix")},
+			style:    "squash",
+			force:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var capturedBody map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/pulls/5/merge", func(w http.ResponseWriter, r *http.Request) {
+				defer func() { _ = r.Body.Close() }()
+				_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+				w.WriteHeader(http.StatusOK)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			err := p.MergePullRequest(context.Background(), repo, 5, tt.strategy, tt.opts...)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.style, capturedBody["Do"])
+			assert.Equal(t, tt.deleteBranch, capturedBody["delete_branch_after_merge"])
+			assert.Equal(t, tt.force, capturedBody["force_merge"])
+		})
+	}
+}
+
+func TestSubmitPullRequestReviewInternal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		sub           globalEntities.ReviewSubmission
+		expectedEvent string
+	}{
+		{
+			name:          "should send APPROVED when verdict is approve",
+			sub:           globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictApprove},
+			expectedEvent: reviewEventApprove,
+		},
+		{
+			name:          "should send REQUEST_CHANGES when verdict is request_changes",
+			sub:           globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictRequestChanges, Body: "fix"},
+			expectedEvent: reviewEventRequestChanges,
+		},
+		{
+			name:          "should send COMMENT when verdict is waiting_for_author",
+			sub:           globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictWaitingForAuthor, Body: "ping"},
+			expectedEvent: reviewEventComment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var capturedBody map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST "+reviewsPath, func(w http.ResponseWriter, r *http.Request) {
+				defer func() { _ = r.Body.Close() }()
+				_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+				_, _ = w.Write([]byte(`{"id":1}`))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			err := p.SubmitPullRequestReview(context.Background(), repo, 5, tt.sub)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedEvent, capturedBody["event"])
+		})
+	}
+
+	t.Run("should skip the API when verdict is comment with an empty body", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		called := false
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST "+reviewsPath, func(_ http.ResponseWriter, _ *http.Request) { called = true })
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.SubmitPullRequestReview(context.Background(), repo, 5,
+			globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictComment})
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("should require a body when verdict is request_changes", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		p := &Provider{}
+
+		// when
+		err := p.SubmitPullRequestReview(context.Background(), globalEntities.Repository{}, 5,
+			globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictRequestChanges})
+
+		// then
+		require.ErrorIs(t, err, ErrReviewBodyRequired)
+	})
+
+	t.Run("should swallow a self-review rejection", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST "+reviewsPath, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message":"approve your own pull is not allowed"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.SubmitPullRequestReview(context.Background(), repo, 5,
+			globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictApprove})
+
+		// then
+		require.NoError(t, err)
+	})
+}
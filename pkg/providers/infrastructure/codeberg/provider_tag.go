@@ -0,0 +1,58 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// CreateTag creates the tag through the tags endpoint; an empty target tags
+// the default branch. Forgejo records the token's user as the tagger of
+// annotated tags.
+func (p *Provider) CreateTag(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.TagInput,
+) (*globalEntities.Tag, error) {
+	if input.TaggerName != "" || input.TaggerEmail != "" {
+		return nil, fmt.Errorf("Forgejo tags the token's user: %w", errors.ErrUnsupported)
+	}
+
+	body := map[string]any{
+		"tag_name": input.Name,
+		"message":  input.Message,
+	}
+	if target := strings.TrimPrefix(input.Target, "refs/heads/"); target != "" {
+		body["target"] = target
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/tags", repo.Organization, repo.Name)
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag %q: %w", input.Name, err)
+	}
+
+	var tag forgejoTag
+	if unmarshalErr := json.Unmarshal(resp, &tag); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse tag response: %w", unmarshalErr)
+	}
+
+	// Forgejo echoes the commit subject as the message of lightweight tags,
+	// so whether the tag is annotated is told by the tag object ID instead.
+	annotated := tag.ID != "" && tag.ID != tag.Commit.SHA
+	result := &globalEntities.Tag{
+		Name:      tag.Name,
+		CommitSHA: tag.Commit.SHA,
+		Annotated: annotated,
+		Date:      tag.Commit.Created,
+	}
+	if annotated {
+		result.Message = strings.TrimRight(tag.Message, "\n")
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateTagInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report a lightweight tag despite the echoed commit message", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/tags", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"name":"v1.0.0","message":"Fix the build\n","id":"abc123",
+				"commit":{"sha":"abc123","created":"2026-03-04T05:06:07Z"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		tag, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Target: "refs/heads/main",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "main", body["target"])
+		assert.Equal(t, "", body["message"])
+		assert.Equal(t, &globalEntities.Tag{
+			Name: "v1.0.0", CommitSHA: "abc123", Date: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		}, tag)
+	})
+
+	t.Run("should report an annotated tag with its message", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/tags", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"name":"v1.0.0","message":"Release 1.0.0\n","id":"tag-object",
+				"commit":{"sha":"abc123"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		tag, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Message: "Release 1.0.0",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, tag.Annotated)
+		assert.Equal(t, "Release 1.0.0", tag.Message)
+	})
+}
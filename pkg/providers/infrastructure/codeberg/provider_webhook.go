@@ -0,0 +1,196 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// forgejoWebhookEvents maps each webhook event onto the Forgejo events it
+// subscribes to. Forgejo has no webhook event for Actions runs.
+var forgejoWebhookEvents = map[globalEntities.WebhookEvent][]string{
+	globalEntities.WebhookEventPush:        {"push"},
+	globalEntities.WebhookEventPullRequest: {"pull_request", "pull_request_sync"},
+	globalEntities.WebhookEventComment:     {"issue_comment", "pull_request_comment"},
+	globalEntities.WebhookEventIssue:       {"issues"},
+	globalEntities.WebhookEventRelease:     {"release"},
+}
+
+type forgejoHook struct {
+	ID     int64 `json:"id"`
+	Config struct {
+		URL         string `json:"url"`
+		ContentType string `json:"content_type"`
+	} `json:"config"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWebhook adds a Gitea-type webhook, which signs deliveries with an
+// HMAC-SHA256 of the secret in the X-Forgejo-Signature (and X-Gitea-Signature)
+// header.
+func (p *Provider) CreateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	body, err := forgejoHookBody(input)
+	if err != nil {
+		return nil, err
+	}
+	body["type"] = "gitea"
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/hooks", repo.Organization, repo.Name)
+	resp, err := p.doRequest(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return parseForgejoHook(repo, resp)
+}
+
+func (p *Provider) ListWebhooks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Webhook, error) {
+	var webhooks []globalEntities.Webhook
+	page := 1
+
+	for {
+		endpoint := fmt.Sprintf(
+			"/api/v1/repos/%s/%s/hooks?page=%d&limit=%d",
+			repo.Organization, repo.Name, page, perPage,
+		)
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+
+		var hooks []forgejoHook
+		if unmarshalErr := json.Unmarshal(resp, &hooks); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse webhooks response: %w", unmarshalErr)
+		}
+
+		for _, hook := range hooks {
+			webhooks = append(webhooks, *forgejoHookToDomain(repo, hook))
+		}
+
+		if len(hooks) < perPage {
+			break
+		}
+		page++
+	}
+
+	return webhooks, nil
+}
+
+func (p *Provider) UpdateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id string,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	body, err := forgejoHookBody(input)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/hooks/%s", repo.Organization, repo.Name, id)
+	resp, err := p.doRequest(ctx, http.MethodPatch, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook %s: %w", id, err)
+	}
+	return parseForgejoHook(repo, resp)
+}
+
+func (p *Provider) DeleteWebhook(ctx context.Context, repo globalEntities.Repository, id string) error {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/hooks/%s", repo.Organization, repo.Name, id)
+	if _, err := p.doRequest(ctx, http.MethodDelete, endpoint, nil); err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// PingWebhook sends the webhook a test push event built from the default
+// branch's latest commit.
+func (p *Provider) PingWebhook(ctx context.Context, repo globalEntities.Repository, id string) error {
+	endpoint := fmt.Sprintf("/api/v1/repos/%s/%s/hooks/%s/tests", repo.Organization, repo.Name, id)
+	if _, err := p.doRequest(ctx, http.MethodPost, endpoint, nil); err != nil {
+		return fmt.Errorf("failed to ping webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries is unsupported: Forgejo shows a hook's deliveries in
+// the web UI only.
+func (p *Provider) ListWebhookDeliveries(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ string,
+) ([]globalEntities.WebhookDelivery, error) {
+	return nil, fmt.Errorf("Forgejo has no API for webhook deliveries: %w", errors.ErrUnsupported)
+}
+
+// RedeliverWebhook is unsupported: Forgejo redelivers from the web UI only.
+func (p *Provider) RedeliverWebhook(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_, _ string,
+) error {
+	return fmt.Errorf("Forgejo has no API for webhook redelivery: %w", errors.ErrUnsupported)
+}
+
+func forgejoHookBody(input globalEntities.WebhookInput) (map[string]any, error) {
+	var events []string
+	for _, event := range input.Events {
+		names, ok := forgejoWebhookEvents[event]
+		if !ok {
+			return nil, fmt.Errorf("webhook event %q: %w", event, errors.ErrUnsupported)
+		}
+		events = append(events, names...)
+	}
+
+	return map[string]any{
+		"config": map[string]string{
+			"url":          input.URL,
+			"content_type": string(input.ContentTypeOrDefault()),
+			"secret":       input.Secret,
+		},
+		"events": events,
+		"active": true,
+	}, nil
+}
+
+func parseForgejoHook(repo globalEntities.Repository, resp []byte) (*globalEntities.Webhook, error) {
+	var hook forgejoHook
+	if unmarshalErr := json.Unmarshal(resp, &hook); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse webhook response: %w", unmarshalErr)
+	}
+	return forgejoHookToDomain(repo, hook), nil
+}
+
+func forgejoHookToDomain(repo globalEntities.Repository, hook forgejoHook) *globalEntities.Webhook {
+	webhook := &globalEntities.Webhook{
+		ID:          strconv.FormatInt(hook.ID, 10),
+		Repository:  repo,
+		URL:         hook.Config.URL,
+		ContentType: globalEntities.WebhookContentType(hook.Config.ContentType),
+		Active:      hook.Active,
+		CreatedAt:   hook.CreatedAt,
+	}
+	for event, names := range forgejoWebhookEvents {
+		if slices.ContainsFunc(names, func(name string) bool { return slices.Contains(hook.Events, name) }) {
+			webhook.Events = append(webhook.Events, event)
+		}
+	}
+	slices.Sort(webhook.Events)
+	return webhook
+}
@@ -0,0 +1,102 @@
+package codeberg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateWebhookInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create a Gitea-type hook with the Forgejo events", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v1/repos/my-org/my-repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":12,"type":"gitea","active":true,"events":["pull_request","pull_request_sync"],
+				"config":{"url":"https://bot.example.com/hook","content_type":"form"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		webhook, err := p.CreateWebhook(context.Background(), repo, globalEntities.WebhookInput{
+			URL:         "https://bot.example.com/hook",
+			Events:      []globalEntities.WebhookEvent{globalEntities.WebhookEventPullRequest},
+			Secret:      "s3cret",
+			ContentType: globalEntities.WebhookContentTypeForm,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "gitea", body["type"])
+		assert.Equal(t, []any{"pull_request", "pull_request_sync"}, body["events"])
+		assert.Equal(t, map[string]any{
+			"url": "https://bot.example.com/hook", "content_type": "form", "secret": "s3cret",
+		}, body["config"])
+		assert.Equal(t, &globalEntities.Webhook{
+			ID:          "12",
+			Repository:  repo,
+			URL:         "https://bot.example.com/hook",
+			Events:      []globalEntities.WebhookEvent{globalEntities.WebhookEventPullRequest},
+			ContentType: globalEntities.WebhookContentTypeForm,
+			Active:      true,
+		}, webhook)
+	})
+
+	t.Run("should reject pipeline events", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NewServeMux())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.CreateWebhook(context.Background(), repo, globalEntities.WebhookInput{
+			URL: "https://bot.example.com/hook", Events: []globalEntities.WebhookEvent{globalEntities.WebhookEventPipeline},
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestRedeliverWebhookInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report redelivery as unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NewServeMux())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.RedeliverWebhook(context.Background(), repo, "12", "345")
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
@@ -0,0 +1,572 @@
+package codecommit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var fixedNow = time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC) //nolint:gochecknoglobals // test fixture
+
+// newTestProvider creates a Provider pointed at the given test server.
+func newTestProvider(t *testing.T, server *httptest.Server) *Provider {
+	t.Helper()
+	return &Provider{
+		token:      "AKIDEXAMPLE:secret",
+		creds:      awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret"},
+		region:     "eu-west-1",
+		endpoint:   server.URL,
+		httpClient: server.Client(),
+		now:        func() time.Time { return fixedNow },
+	}
+}
+
+// apiHandlers routes CodeCommit JSON API calls by their X-Amz-Target action.
+type apiHandlers map[string]func(body map[string]any) (int, string)
+
+func newAPIServer(t *testing.T, handlers apiHandlers) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		action := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), targetPrefix)
+		handler, ok := handlers[action]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"UnknownOperationException","message":"` + action + `"}`))
+			return
+		}
+
+		var body map[string]any
+		defer func() { _ = r.Body.Close() }()
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		status, resp := handler(body)
+		w.Header().Set("Content-Type", jsonContentType)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(resp))
+	}))
+}
+
+func TestSignAPIRequestInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should sign the request with a codecommit credential scope", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		req := httptest.NewRequest(http.MethodPost, "https://codecommit.eu-west-1.amazonaws.com/", nil)
+		req.Header.Set("Content-Type", jsonContentType)
+		req.Header.Set("X-Amz-Target", targetPrefix+"ListRepositories")
+		creds := awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret", sessionToken: "session"}
+
+		// when
+		signAPIRequest(req, []byte(`{}`), creds, "eu-west-1", fixedNow)
+
+		// then
+		assert.Equal(t, "20240102T030405Z", req.Header.Get("X-Amz-Date"))
+		assert.Equal(t, "session", req.Header.Get("X-Amz-Security-Token"))
+		assert.Regexp(t, regexp.MustCompile(
+			`^AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240102/eu-west-1/codecommit/aws4_request, `+
+				`SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, `+
+				`Signature=[0-9a-f]{64}$`,
+		), req.Header.Get("Authorization"))
+	})
+}
+
+func TestGitCredentialsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return a timestamped signature as the git password", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		repoURL, _ := url.Parse("https://git-codecommit.eu-west-1.amazonaws.com/v1/repos/my-repo")
+		creds := awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret"}
+
+		// when
+		username, password := gitCredentials(repoURL, creds, "eu-west-1", fixedNow)
+
+		// then
+		assert.Equal(t, "AKIDEXAMPLE", username)
+		assert.Regexp(t, regexp.MustCompile(`^20240102T030405Z[0-9a-f]{64}$`), password)
+	})
+
+	t.Run("should append the session token to the username when one is set", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		repoURL, _ := url.Parse("https://git-codecommit.eu-west-1.amazonaws.com/v1/repos/my-repo")
+		creds := awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret", sessionToken: "session"}
+
+		// when
+		username, _ := gitCredentials(repoURL, creds, "eu-west-1", fixedNow)
+
+		// then
+		assert.Equal(t, "AKIDEXAMPLE%session", username)
+	})
+
+	t.Run("should sign the repository path without the smart HTTP suffix", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		auth := &sigV4GitAuth{
+			creds:  awsCredentials{accessKeyID: "AKIDEXAMPLE", secretAccessKey: "secret"},
+			region: "us-east-1",
+			now:    func() time.Time { return fixedNow },
+		}
+		req := httptest.NewRequest(http.MethodGet,
+			"https://git-codecommit.eu-west-1.amazonaws.com/v1/repos/my-repo/info/refs?service=git-upload-pack", nil)
+		repoURL, _ := url.Parse("https://git-codecommit.eu-west-1.amazonaws.com/v1/repos/my-repo")
+		_, expected := gitCredentials(repoURL, auth.creds, "eu-west-1", fixedNow)
+
+		// when
+		auth.SetAuth(req)
+
+		// then
+		_, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		assert.Equal(t, expected, password, "the region should come from the host and the path should exclude /info/refs")
+	})
+}
+
+func TestResolveCredentialsInternal(t *testing.T) {
+	t.Run("should prefer credentials given in the token", func(t *testing.T) {
+		// given
+		t.Setenv("AWS_ACCESS_KEY_ID", "ENVKEY")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+
+		// when
+		creds := resolveCredentials("TOKENKEY:tokensecret:tokensession")
+
+		// then
+		assert.Equal(t, awsCredentials{
+			accessKeyID:     "TOKENKEY",
+			secretAccessKey: "tokensecret",
+			sessionToken:    "tokensession",
+		}, creds)
+	})
+
+	t.Run("should read the environment when the token is empty", func(t *testing.T) {
+		// given
+		t.Setenv("AWS_ACCESS_KEY_ID", "ENVKEY")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "envsecret")
+		t.Setenv("AWS_SESSION_TOKEN", "")
+
+		// when
+		creds := resolveCredentials("")
+
+		// then
+		assert.Equal(t, awsCredentials{accessKeyID: "ENVKEY", secretAccessKey: "envsecret"}, creds)
+	})
+
+	t.Run("should read the active profile from the shared files when the environment is empty", func(t *testing.T) {
+		// given
+		dir := t.TempDir()
+		credentialsFile := filepath.Join(dir, "credentials")
+		configFile := filepath.Join(dir, "config")
+		require.NoError(t, os.WriteFile(credentialsFile, []byte(
+			"[default]\naws_access_key_id = DEFAULTKEY\naws_secret_access_key = defaultsecret\n\n"+
+				"[work]\naws_access_key_id = WORKKEY\naws_secret_access_key = worksecret\n",
+		), 0o600))
+		require.NoError(t, os.WriteFile(configFile, []byte(
+			"[default]\nregion = us-west-2\n\n[profile work]\nregion = ap-southeast-2\n",
+		), 0o600))
+		t.Setenv("AWS_ACCESS_KEY_ID", "")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+		t.Setenv("AWS_REGION", "")
+		t.Setenv("AWS_DEFAULT_REGION", "")
+		t.Setenv("AWS_PROFILE", "work")
+		t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+		t.Setenv("AWS_CONFIG_FILE", configFile)
+
+		// when
+		creds := resolveCredentials("")
+		region := resolveRegion()
+
+		// then
+		assert.Equal(t, awsCredentials{accessKeyID: "WORKKEY", secretAccessKey: "worksecret"}, creds)
+		assert.Equal(t, "ap-southeast-2", region)
+	})
+}
+
+func TestDiscoverRepositoriesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list repository names across pages and map their metadata", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var batchNames []any
+		server := newAPIServer(t, apiHandlers{
+			"ListRepositories": func(body map[string]any) (int, string) {
+				if body["nextToken"] == "page-2" {
+					return http.StatusOK, `{"repositories":[{"repositoryName":"second"}]}`
+				}
+				return http.StatusOK, `{"repositories":[{"repositoryName":"first"}],"nextToken":"page-2"}`
+			},
+			"BatchGetRepositories": func(body map[string]any) (int, string) {
+				batchNames, _ = body["repositoryNames"].([]any)
+				return http.StatusOK, `{"repositories":[` +
+					`{"repositoryId":"id-1","repositoryName":"first","defaultBranch":"develop",` +
+					`"cloneUrlHttp":"https://git-codecommit.eu-west-1.amazonaws.com/v1/repos/first",` +
+					`"cloneUrlSsh":"ssh://git-codecommit.eu-west-1.amazonaws.com/v1/repos/first"},` +
+					`{"repositoryId":"id-2","repositoryName":"second"}]}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		repos, err := p.DiscoverRepositories(context.Background(), "")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []any{"first", "second"}, batchNames)
+		require.Len(t, repos, 2)
+		assert.Equal(t, "id-1", repos[0].ID)
+		assert.Equal(t, "first", repos[0].Name)
+		assert.Equal(t, "eu-west-1", repos[0].Organization)
+		assert.Equal(t, "refs/heads/develop", repos[0].DefaultBranch)
+		assert.Equal(t, "https://git-codecommit.eu-west-1.amazonaws.com/v1/repos/first", repos[0].RemoteURL)
+		assert.Equal(t, "codecommit", repos[0].ProviderName)
+		assert.Equal(t, "refs/heads/main", repos[1].DefaultBranch)
+	})
+
+	t.Run("should return the AWS error type when the call is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newAPIServer(t, apiHandlers{
+			"ListRepositories": func(map[string]any) (int, string) {
+				return http.StatusBadRequest,
+					`{"__type":"com.amazonaws.codecommit#InvalidContinuationTokenException","message":"bad token"}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		_, err := p.DiscoverRepositories(context.Background(), "eu-west-1")
+
+		// then
+		require.Error(t, err)
+		assert.True(t, isErrorType(err, "InvalidContinuationTokenException"))
+	})
+
+	t.Run("should return an error when no credentials are available", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newAPIServer(t, apiHandlers{})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		p.creds = awsCredentials{}
+
+		// when
+		_, err := p.DiscoverRepositories(context.Background(), "eu-west-1")
+
+		// then
+		require.ErrorIs(t, err, ErrCredentialsNotFound)
+	})
+}
+
+func TestPullRequestsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create a pull request with fully qualified references", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var targets []any
+		server := newAPIServer(t, apiHandlers{
+			"CreatePullRequest": func(body map[string]any) (int, string) {
+				targets, _ = body["targets"].([]any)
+				return http.StatusOK, `{"pullRequest":{"pullRequestId":"42","title":"Bump","pullRequestStatus":"OPEN"}}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Name: "my-repo", Organization: "eu-west-1"}
+
+		// when
+		pr, err := p.CreatePullRequest(context.Background(), repo, globalEntities.PullRequestInput{
+			SourceBranch: "chore/bump",
+			TargetBranch: "refs/heads/main",
+			Title:        "Bump",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 42, pr.ID)
+		assert.Equal(t, "OPEN", pr.Status)
+		assert.Contains(t, pr.URL, "/repositories/my-repo/pull-requests/42/")
+		require.Len(t, targets, 1)
+		assert.Equal(t, map[string]any{
+			"repositoryName":       "my-repo",
+			"sourceReference":      "refs/heads/chore/bump",
+			"destinationReference": "refs/heads/main",
+		}, targets[0])
+	})
+
+	t.Run("should close the open pull request whose source matches the branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var closedID any
+		server := newAPIServer(t, apiHandlers{
+			"ListPullRequests": func(map[string]any) (int, string) {
+				return http.StatusOK, `{"pullRequestIds":["7","8"]}`
+			},
+			"GetPullRequest": func(body map[string]any) (int, string) {
+				source := "refs/heads/feat/other"
+				if body["pullRequestId"] == "8" {
+					source = "refs/heads/chore/bump"
+				}
+				return http.StatusOK, fmt.Sprintf(`{"pullRequest":{"pullRequestId":%q,"pullRequestTargets":`+
+					`[{"repositoryName":"my-repo","sourceReference":%q}]}}`, body["pullRequestId"], source)
+			},
+			"UpdatePullRequestStatus": func(body map[string]any) (int, string) {
+				closedID = body["pullRequestId"]
+				return http.StatusOK, `{"pullRequest":{"pullRequestStatus":"CLOSED"}}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Name: "my-repo"}
+
+		// when
+		closed, err := p.ClosePullRequest(context.Background(), repo, "chore/bump")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, closed)
+		assert.Equal(t, "8", closedID)
+	})
+
+	t.Run("should report no pull request when none is open", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newAPIServer(t, apiHandlers{
+			"ListPullRequests": func(map[string]any) (int, string) {
+				return http.StatusOK, `{"pullRequestIds":[]}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		exists, err := p.PullRequestExists(context.Background(), globalEntities.Repository{Name: "my-repo"}, "x")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestFileAccessInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decode file content from the default branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var commitSpecifier any
+		server := newAPIServer(t, apiHandlers{
+			"GetFile": func(body map[string]any) (int, string) {
+				commitSpecifier = body["commitSpecifier"]
+				return http.StatusOK, `{"fileContent":"` + base64.StdEncoding.EncodeToString([]byte("1.2.3\n")) + `"}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Name: "my-repo", DefaultBranch: "refs/heads/main"}
+
+		// when
+		content, err := p.GetFileContent(context.Background(), repo, "VERSION")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3\n", content)
+		assert.Equal(t, "main", commitSpecifier)
+	})
+
+	t.Run("should report a missing file through HasFile", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newAPIServer(t, apiHandlers{
+			"GetFile": func(map[string]any) (int, string) {
+				return http.StatusBadRequest, `{"__type":"FileDoesNotExistException","message":"missing"}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		found := p.HasFile(context.Background(), globalEntities.Repository{Name: "my-repo"}, "missing.txt")
+
+		// then
+		assert.False(t, found)
+	})
+
+	t.Run("should walk sub folders and filter by pattern suffix", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newAPIServer(t, apiHandlers{
+			"GetFolder": func(body map[string]any) (int, string) {
+				if body["folderPath"] == "/" {
+					return http.StatusOK, `{"files":[{"absolutePath":"go.mod","blobId":"b1"}],` +
+						`"subFolders":[{"absolutePath":"cmd","treeId":"t1"}]}`
+				}
+				return http.StatusOK, `{"files":[{"absolutePath":"cmd/go.mod","blobId":"b2"},` +
+					`{"absolutePath":"cmd/main.go","blobId":"b3"}]}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Name: "my-repo", DefaultBranch: "refs/heads/main"}
+
+		// when
+		files, err := p.ListFiles(context.Background(), repo, "go.mod")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []globalEntities.File{
+			{Path: "go.mod", ObjectID: "b1"},
+			{Path: "cmd/go.mod", ObjectID: "b2"},
+		}, files)
+	})
+
+	t.Run("should create the branch and commit all changes on top of the base", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var branchBody, commitBody map[string]any
+		server := newAPIServer(t, apiHandlers{
+			"GetBranch": func(map[string]any) (int, string) {
+				return http.StatusOK, `{"branch":{"branchName":"main","commitId":"abc123"}}`
+			},
+			"CreateBranch": func(body map[string]any) (int, string) {
+				branchBody = body
+				return http.StatusOK, `{}`
+			},
+			"CreateCommit": func(body map[string]any) (int, string) {
+				commitBody = body
+				return http.StatusOK, `{"commitId":"def456"}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Name: "my-repo"}
+
+		// when
+		err := p.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName:    "chore/bump",
+			BaseBranch:    "refs/heads/main",
+			CommitMessage: "chore: bump",
+			Changes: []globalEntities.FileChange{
+				{Path: "/VERSION", Content: "1.2.3\n", ChangeType: "edit"},
+				{Path: "old.txt", ChangeType: "delete"},
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", branchBody["commitId"])
+		assert.Equal(t, "abc123", commitBody["parentCommitId"])
+		assert.Equal(t, "chore/bump", commitBody["branchName"])
+		assert.Equal(t, []any{map[string]any{
+			"filePath":    "VERSION",
+			"fileContent": base64.StdEncoding.EncodeToString([]byte("1.2.3\n")),
+		}}, commitBody["putFiles"])
+		assert.Equal(t, []any{map[string]any{"filePath": "old.txt"}}, commitBody["deleteFiles"])
+	})
+
+	t.Run("should return error when a change type is unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newAPIServer(t, apiHandlers{
+			"GetBranch": func(map[string]any) (int, string) {
+				return http.StatusOK, `{"branch":{"commitId":"abc123"}}`
+			},
+		})
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		err := p.CreateBranchWithChanges(context.Background(), globalEntities.Repository{Name: "my-repo"},
+			globalEntities.BranchInput{
+				BranchName: "chore/bump",
+				BaseBranch: "main",
+				Changes:    []globalEntities.FileChange{{Path: "a.txt", ChangeType: "rename"}},
+			})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported change type "rename"`)
+	})
+}
+
+func TestGetTagsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list tags from the git ref advertisement with signed credentials", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var username string
+		pktLine := func(s string) string { return fmt.Sprintf("%04x%s", len(s)+4, s) }
+		const sha = "1111111111111111111111111111111111111111"
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /v1/repos/my-repo/info/refs", func(w http.ResponseWriter, r *http.Request) {
+			username, _, _ = r.BasicAuth()
+			w.Header().Set("Content-Type", "application/x-git-upload-pack-advertisement")
+			_, _ = w.Write([]byte(pktLine("# service=git-upload-pack\n") + "0000" +
+				pktLine(sha+" HEAD\x00symref=HEAD:refs/heads/main\n") +
+				pktLine(sha+" refs/heads/main\n") +
+				pktLine(sha+" refs/tags/1.2.0\n") +
+				pktLine(sha+" refs/tags/1.10.0\n") +
+				"0000"))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		tags, err := p.GetTags(context.Background(), globalEntities.Repository{Name: "my-repo"})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.10.0", "1.2.0"}, tags)
+		assert.Equal(t, "AKIDEXAMPLE", username)
+	})
+}
@@ -0,0 +1,170 @@
+package codecommit
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	providerName    = "codecommit"
+	gitHostPrefix   = "git-codecommit."
+	hostSuffix      = ".amazonaws.com"
+	httpTimeout     = 30 * time.Second
+	httpStatusOKMin = 200
+	httpStatusOKMax = 300
+)
+
+// Provider implements ForgeProvider, FileAccessProvider, and LocalGitAuthProvider for AWS CodeCommit.
+//
+// CodeCommit has no organizations: repositories belong to an account and a
+// region, so Repository.Organization carries the AWS region. The token is
+// either "ACCESS_KEY_ID:SECRET_ACCESS_KEY[:SESSION_TOKEN]" or empty, in which
+// case credentials come from the standard AWS environment variables or the
+// shared credentials and config files.
+type Provider struct {
+	token      string
+	creds      awsCredentials
+	region     string
+	endpoint   string // overrides both the API and git endpoints when set
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewProvider creates a new CodeCommit provider. The region is taken from
+// AWS_REGION, AWS_DEFAULT_REGION or the active profile.
+func NewProvider(token string) globalEntities.ForgeProvider {
+	return NewProviderWithEndpoint(token, resolveRegion(), "", nil)
+}
+
+// NewProviderWithEndpoint creates a new CodeCommit provider for the given
+// region. A non-empty endpoint (e.g. an httptest server URL) receives both the
+// JSON API calls and the git smart HTTP traffic instead of the AWS hosts. If
+// client is nil, a default client with the standard timeout is used.
+func NewProviderWithEndpoint(
+	token, region, endpoint string,
+	client *http.Client,
+) globalEntities.ForgeProvider {
+	if client == nil {
+		client = &http.Client{
+			Timeout: httpTimeout,
+		}
+	}
+
+	return &Provider{
+		token:      token,
+		creds:      resolveCredentials(token),
+		region:     region,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: client,
+		now:        time.Now,
+	}
+}
+
+func (p *Provider) Name() string      { return providerName }
+func (p *Provider) AuthToken() string { return p.token }
+
+func (p *Provider) MatchesURL(rawURL string) bool {
+	return strings.Contains(rawURL, "git-codecommit") || strings.HasPrefix(rawURL, "codecommit:")
+}
+
+// repoRegion returns the region of the repository, defaulting to the
+// provider's region.
+func (p *Provider) repoRegion(repo globalEntities.Repository) string {
+	if repo.Organization != "" {
+		return repo.Organization
+	}
+	return p.region
+}
+
+// gitBaseURL returns the root of the git smart HTTP endpoint for a region.
+func (p *Provider) gitBaseURL(region string) string {
+	if p.endpoint != "" {
+		return p.endpoint
+	}
+	return fmt.Sprintf("https://%s%s%s", gitHostPrefix, region, hostSuffix)
+}
+
+// regionFromHost extracts the region from a "git-codecommit.{region}.amazonaws.com"
+// host, returning fallback for any other host.
+func regionFromHost(host, fallback string) string {
+	trimmed, ok := strings.CutPrefix(host, gitHostPrefix)
+	if !ok {
+		return fallback
+	}
+	region, ok := strings.CutSuffix(trimmed, hostSuffix)
+	if !ok || region == "" {
+		return fallback
+	}
+	return region
+}
+
+// --- LocalGitAuthProvider ---
+
+func (p *Provider) GetServiceType() globalEntities.ServiceType {
+	return globalEntities.CODECOMMIT
+}
+
+func (p *Provider) PrepareCloneURL(cloneURL string) string {
+	return cloneURL
+}
+
+func (p *Provider) ConfigureTransport() {
+	// CodeCommit doesn't need special transport configuration
+}
+
+// GetAuthMethods returns an auth method that signs every git HTTP request with
+// SigV4-derived credentials, as the CodeCommit credential helper does.
+func (p *Provider) GetAuthMethods(_ string) []transport.AuthMethod {
+	var authMethods []transport.AuthMethod
+
+	if p.creds.valid() {
+		log.Infof("Using AWS SigV4 credentials to authenticate with CodeCommit")
+		authMethods = append(authMethods, p.gitAuth())
+	}
+
+	return authMethods
+}
+
+func (p *Provider) gitAuth() *sigV4GitAuth {
+	return &sigV4GitAuth{creds: p.creds, region: p.region, now: p.now}
+}
+
+// CloneURL returns the HTTPS clone URL with a freshly signed git password
+// embedded. The signature is time-bound, so the URL should be used right away.
+func (p *Provider) CloneURL(repo globalEntities.Repository) string {
+	remoteURL := repo.RemoteURL
+	if remoteURL == "" {
+		remoteURL = fmt.Sprintf("%s/v1/repos/%s", p.gitBaseURL(p.repoRegion(repo)), repo.Name)
+	}
+
+	if !p.creds.valid() {
+		return remoteURL
+	}
+
+	parsed, err := url.Parse(remoteURL)
+	if err != nil {
+		return remoteURL
+	}
+
+	username, password := gitCredentials(
+		parsed, p.creds, regionFromHost(parsed.Host, p.repoRegion(repo)), p.now(),
+	)
+	parsed.User = url.UserPassword(username, password)
+
+	return parsed.String()
+}
+
+func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
+	host := gitHostPrefix + p.repoRegion(repo) + hostSuffix
+	if sshAlias != "" {
+		host = fmt.Sprintf("%s-%s", host, sshAlias)
+	}
+	return fmt.Sprintf("ssh://%s/v1/repos/%s", host, repo.Name)
+}
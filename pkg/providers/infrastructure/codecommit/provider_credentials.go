@@ -0,0 +1,167 @@
+package codecommit
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultRegion  = "us-east-1"
+	defaultProfile = "default"
+)
+
+// awsCredentials holds a resolved AWS access key pair and optional session token.
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+func (c awsCredentials) valid() bool {
+	return c.accessKeyID != "" && c.secretAccessKey != ""
+}
+
+// parseTokenCredentials reads credentials written as
+// "ACCESS_KEY_ID:SECRET_ACCESS_KEY[:SESSION_TOKEN]".
+func parseTokenCredentials(token string) (awsCredentials, bool) {
+	parts := strings.SplitN(token, ":", 3) //nolint:mnd // key id, secret, optional session token
+	if len(parts) < 2 {                    //nolint:mnd // key id and secret are mandatory
+		return awsCredentials{}, false
+	}
+
+	creds := awsCredentials{accessKeyID: parts[0], secretAccessKey: parts[1]}
+	if len(parts) == 3 { //nolint:mnd // session token present
+		creds.sessionToken = parts[2]
+	}
+	return creds, creds.valid()
+}
+
+// resolveCredentials follows the standard AWS lookup order: the explicit
+// token, then the AWS_* environment variables, then the shared credentials
+// and config files for the active profile.
+func resolveCredentials(token string) awsCredentials {
+	if creds, ok := parseTokenCredentials(token); ok {
+		return creds
+	}
+
+	envCreds := awsCredentials{
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if envCreds.valid() {
+		return envCreds
+	}
+
+	profile := activeProfile()
+	for _, section := range []map[string]string{
+		readProfile(credentialsFilePath(), profile, false),
+		readProfile(configFilePath(), profile, true),
+	} {
+		fileCreds := awsCredentials{
+			accessKeyID:     section["aws_access_key_id"],
+			secretAccessKey: section["aws_secret_access_key"],
+			sessionToken:    section["aws_session_token"],
+		}
+		if fileCreds.valid() {
+			return fileCreds
+		}
+	}
+
+	return awsCredentials{}
+}
+
+// resolveRegion returns the region from AWS_REGION, AWS_DEFAULT_REGION or the
+// active profile in the config file, falling back to us-east-1.
+func resolveRegion() string {
+	for _, envVar := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(envVar); region != "" {
+			return region
+		}
+	}
+
+	if region := readProfile(configFilePath(), activeProfile(), true)["region"]; region != "" {
+		return region
+	}
+
+	return defaultRegion
+}
+
+func activeProfile() string {
+	for _, envVar := range []string{"AWS_PROFILE", "AWS_DEFAULT_PROFILE"} {
+		if profile := os.Getenv(envVar); profile != "" {
+			return profile
+		}
+	}
+	return defaultProfile
+}
+
+func credentialsFilePath() string {
+	if path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); path != "" {
+		return path
+	}
+	return awsHomeFile("credentials")
+}
+
+func configFilePath() string {
+	if path := os.Getenv("AWS_CONFIG_FILE"); path != "" {
+		return path
+	}
+	return awsHomeFile("config")
+}
+
+func awsHomeFile(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".aws", name)
+}
+
+// readProfile returns the key/value pairs of one profile section of an AWS
+// INI file. In the config file, non-default profiles are written as
+// "[profile name]"; in the credentials file they are plain "[name]".
+func readProfile(path, profile string, isConfigFile bool) map[string]string {
+	values := map[string]string{}
+	if path == "" {
+		return values
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	header := profile
+	if isConfigFile && profile != defaultProfile {
+		header = "profile " + profile
+	}
+
+	inSection := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.Join(strings.Fields(strings.Trim(line, "[]")), " ")
+			inSection = name == header
+			continue
+		}
+
+		if !inSection {
+			continue
+		}
+
+		if key, value, ok := strings.Cut(line, "="); ok {
+			values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return values
+}
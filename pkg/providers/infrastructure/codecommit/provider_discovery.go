@@ -0,0 +1,114 @@
+package codecommit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// batchGetLimit is the maximum number of names BatchGetRepositories accepts.
+const batchGetLimit = 25
+
+type codecommitRepoMetadata struct {
+	RepositoryID   string `json:"repositoryId"`
+	RepositoryName string `json:"repositoryName"`
+	DefaultBranch  string `json:"defaultBranch"`
+	CloneURLHTTP   string `json:"cloneUrlHttp"`
+	CloneURLSSH    string `json:"cloneUrlSsh"`
+}
+
+// DiscoverRepositories lists every repository of the account in the region
+// given as org. An empty org uses the provider's configured region.
+func (p *Provider) DiscoverRepositories(
+	ctx context.Context,
+	org string,
+) ([]globalEntities.Repository, error) {
+	region := org
+	if region == "" {
+		region = p.region
+	}
+
+	names, err := p.listRepositoryNames(ctx, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover repos in region %q: %w", region, err)
+	}
+
+	allRepos := make([]globalEntities.Repository, 0, len(names))
+	for start := 0; start < len(names); start += batchGetLimit {
+		end := min(start+batchGetLimit, len(names))
+
+		resp, batchErr := p.doRequest(ctx, region, "BatchGetRepositories", map[string]any{
+			"repositoryNames": names[start:end],
+		})
+		if batchErr != nil {
+			return nil, fmt.Errorf("failed to get repository metadata: %w", batchErr)
+		}
+
+		var result struct {
+			Repositories []codecommitRepoMetadata `json:"repositories"`
+		}
+		if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse repositories response: %w", unmarshalErr)
+		}
+
+		for _, r := range result.Repositories {
+			allRepos = append(allRepos, codecommitRepoToDomain(r, region))
+		}
+	}
+
+	return allRepos, nil
+}
+
+func (p *Provider) listRepositoryNames(ctx context.Context, region string) ([]string, error) {
+	var names []string
+	nextToken := ""
+
+	for {
+		body := map[string]any{"sortBy": "repositoryName", "order": "ascending"}
+		if nextToken != "" {
+			body["nextToken"] = nextToken
+		}
+
+		resp, err := p.doRequest(ctx, region, "ListRepositories", body)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Repositories []struct {
+				RepositoryName string `json:"repositoryName"`
+			} `json:"repositories"`
+			NextToken string `json:"nextToken"`
+		}
+		if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse repos response: %w", unmarshalErr)
+		}
+
+		for _, r := range result.Repositories {
+			names = append(names, r.RepositoryName)
+		}
+
+		if result.NextToken == "" {
+			return names, nil
+		}
+		nextToken = result.NextToken
+	}
+}
+
+func codecommitRepoToDomain(r codecommitRepoMetadata, region string) globalEntities.Repository {
+	defaultBranch := r.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+	return globalEntities.Repository{
+		ID:            r.RepositoryID,
+		Name:          r.RepositoryName,
+		Organization:  region,
+		DefaultBranch: "refs/heads/" + defaultBranch,
+		RemoteURL:     r.CloneURLHTTP,
+		SSHURL:        r.CloneURLSSH,
+		ProviderName:  providerName,
+	}
+}
@@ -0,0 +1,242 @@
+package codecommit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/storage/memory"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+)
+
+type codecommitFolder struct {
+	Files []struct {
+		AbsolutePath string `json:"absolutePath"`
+		BlobID       string `json:"blobId"`
+	} `json:"files"`
+	SubFolders []struct {
+		AbsolutePath string `json:"absolutePath"`
+		TreeID       string `json:"treeId"`
+	} `json:"subFolders"`
+}
+
+func (p *Provider) GetFileContent(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	path string,
+) (string, error) {
+	body := map[string]string{
+		"repositoryName": repo.Name,
+		"filePath":       strings.TrimPrefix(path, "/"),
+	}
+	if branch := strings.TrimPrefix(repo.DefaultBranch, "refs/heads/"); branch != "" {
+		body["commitSpecifier"] = branch
+	}
+
+	resp, err := p.doRequest(ctx, p.repoRegion(repo), "GetFile", body)
+	if err != nil {
+		return "", fmt.Errorf("failed to get file %q: %w", path, err)
+	}
+
+	var result struct {
+		FileContent string `json:"fileContent"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return "", fmt.Errorf("failed to parse file content response: %w", unmarshalErr)
+	}
+
+	decoded, decodeErr := base64.StdEncoding.DecodeString(result.FileContent)
+	if decodeErr != nil {
+		return "", fmt.Errorf("failed to decode base64 content: %w", decodeErr)
+	}
+
+	return string(decoded), nil
+}
+
+// ListFiles walks the repository tree folder by folder, since CodeCommit's
+// GetFolder only returns the direct children of a path.
+func (p *Provider) ListFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	pattern string,
+) ([]globalEntities.File, error) {
+	branch := strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")
+	if branch == "" {
+		branch = "HEAD"
+	}
+
+	var files []globalEntities.File
+	pending := []string{"/"}
+
+	for len(pending) > 0 {
+		folderPath := pending[0]
+		pending = pending[1:]
+
+		resp, err := p.doRequest(ctx, p.repoRegion(repo), "GetFolder", map[string]string{
+			"repositoryName":  repo.Name,
+			"commitSpecifier": branch,
+			"folderPath":      folderPath,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get folder %q: %w", folderPath, err)
+		}
+
+		var folder codecommitFolder
+		if unmarshalErr := json.Unmarshal(resp, &folder); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse folder response: %w", unmarshalErr)
+		}
+
+		for _, sub := range folder.SubFolders {
+			pending = append(pending, sub.AbsolutePath)
+			if pattern == "" || strings.HasSuffix(sub.AbsolutePath, pattern) {
+				files = append(files, globalEntities.File{
+					Path:     sub.AbsolutePath,
+					ObjectID: sub.TreeID,
+					IsDir:    true,
+				})
+			}
+		}
+
+		for _, file := range folder.Files {
+			if pattern != "" && !strings.HasSuffix(file.AbsolutePath, pattern) {
+				continue
+			}
+			files = append(files, globalEntities.File{
+				Path:     file.AbsolutePath,
+				ObjectID: file.BlobID,
+			})
+		}
+	}
+
+	return files, nil
+}
+
+// GetTags lists tags through the git smart HTTP endpoint, because the
+// CodeCommit API has no operation that lists tags.
+func (p *Provider) GetTags(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]string, error) {
+	if !p.creds.valid() {
+		return nil, ErrCredentialsNotFound
+	}
+
+	remoteURL := repo.RemoteURL
+	if remoteURL == "" || p.endpoint != "" {
+		remoteURL = fmt.Sprintf("%s/v1/repos/%s", p.gitBaseURL(p.repoRegion(repo)), repo.Name)
+	}
+
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{remoteURL},
+	})
+
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: p.gitAuth()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	var allTags []string
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			allTags = append(allTags, ref.Name().Short())
+		}
+	}
+
+	globalHelpers.SortVersionsDescending(allTags)
+	return allTags, nil
+}
+
+func (p *Provider) HasFile(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	path string,
+) bool {
+	_, err := p.GetFileContent(ctx, repo, path)
+	return err == nil
+}
+
+func (p *Provider) CreateBranchWithChanges(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.BranchInput,
+) error {
+	region := p.repoRegion(repo)
+	baseBranch := strings.TrimPrefix(input.BaseBranch, "refs/heads/")
+
+	// resolve the commit the new branch starts from
+	resp, err := p.doRequest(ctx, region, "GetBranch", map[string]string{
+		"repositoryName": repo.Name,
+		"branchName":     baseBranch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get base branch %q: %w", baseBranch, err)
+	}
+
+	var branch struct {
+		Branch struct {
+			CommitID string `json:"commitId"`
+		} `json:"branch"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &branch); unmarshalErr != nil {
+		return fmt.Errorf("failed to parse branch response: %w", unmarshalErr)
+	}
+	parentCommitID := branch.Branch.CommitID
+
+	var putFiles, deleteFiles []map[string]string
+	for _, change := range input.Changes {
+		filePath := strings.TrimPrefix(change.Path, "/")
+		changeType := strings.ToLower(strings.TrimSpace(change.ChangeType))
+
+		// handle delete operations explicitly
+		if changeType == "delete" {
+			deleteFiles = append(deleteFiles, map[string]string{"filePath": filePath})
+			continue
+		}
+
+		// treat unknown, non-empty change types as an error
+		if changeType != "" && changeType != "add" && changeType != "edit" && changeType != "create" &&
+			changeType != "update" {
+			return fmt.Errorf("unsupported change type %q for file %q", change.ChangeType, filePath)
+		}
+
+		// default behavior: create or update file content
+		putFiles = append(putFiles, map[string]string{
+			"filePath":    filePath,
+			"fileContent": base64.StdEncoding.EncodeToString([]byte(change.Content)),
+		})
+	}
+
+	// create the branch from the base
+	if _, branchErr := p.doRequest(ctx, region, "CreateBranch", map[string]string{
+		"repositoryName": repo.Name,
+		"branchName":     input.BranchName,
+		"commitId":       parentCommitID,
+	}); branchErr != nil {
+		return fmt.Errorf("failed to create branch: %w", branchErr)
+	}
+
+	commitBody := map[string]any{
+		"repositoryName": repo.Name,
+		"branchName":     input.BranchName,
+		"parentCommitId": parentCommitID,
+		"commitMessage":  input.CommitMessage,
+	}
+	if len(putFiles) > 0 {
+		commitBody["putFiles"] = putFiles
+	}
+	if len(deleteFiles) > 0 {
+		commitBody["deleteFiles"] = deleteFiles
+	}
+
+	if _, commitErr := p.doRequest(ctx, region, "CreateCommit", commitBody); commitErr != nil {
+		return fmt.Errorf("failed to create commit on branch %q: %w", input.BranchName, commitErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package codecommit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	targetPrefix    = "CodeCommit_20150413."
+	jsonContentType = "application/x-amz-json-1.1"
+)
+
+// ErrCredentialsNotFound is returned when no AWS credentials could be resolved
+// from the token, the environment, or the shared profile files.
+var ErrCredentialsNotFound = errors.New("no AWS credentials found for CodeCommit")
+
+// apiError represents a CodeCommit API error with the status code and AWS
+// error type preserved.
+type apiError struct {
+	statusCode int
+	errorType  string
+	message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s: %s", e.statusCode, e.errorType, e.message)
+}
+
+// StatusCode returns the HTTP status code of the failed request.
+func (e *apiError) StatusCode() int {
+	return e.statusCode
+}
+
+// isErrorType reports whether err is a CodeCommit API error of the given type,
+// e.g. "FileDoesNotExistException".
+func isErrorType(err error, errorType string) bool {
+	var ae *apiError
+	return errors.As(err, &ae) && ae.errorType == errorType
+}
+
+// apiURL returns the JSON API endpoint for a region.
+func (p *Provider) apiURL(region string) string {
+	if p.endpoint != "" {
+		return p.endpoint + "/"
+	}
+	return fmt.Sprintf("https://codecommit.%s%s/", region, hostSuffix)
+}
+
+// doRequest invokes a CodeCommit JSON API action (e.g. "ListRepositories")
+// in the given region and returns the raw response body.
+func (p *Provider) doRequest(
+	ctx context.Context,
+	region, action string,
+	body any,
+) ([]byte, error) {
+	if !p.creds.valid() {
+		return nil, ErrCredentialsNotFound
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL(region), bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", jsonContentType)
+	req.Header.Set("X-Amz-Target", targetPrefix+action)
+	signAPIRequest(req, payload, p.creds, region, p.now())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < httpStatusOKMin || resp.StatusCode >= httpStatusOKMax {
+		var awsErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &awsErr)
+
+		// the type may be prefixed with a namespace, e.g. "com.amazonaws...#RepositoryDoesNotExistException"
+		errorType := awsErr.Type
+		if _, after, found := strings.Cut(errorType, "#"); found {
+			errorType = after
+		}
+
+		return nil, &apiError{
+			statusCode: resp.StatusCode,
+			errorType:  errorType,
+			message:    awsErr.Message,
+		}
+	}
+
+	return respBody, nil
+}
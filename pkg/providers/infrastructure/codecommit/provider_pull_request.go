@@ -0,0 +1,185 @@
+package codecommit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	pullRequestStatusOpen   = "OPEN"
+	pullRequestStatusClosed = "CLOSED"
+)
+
+type codecommitPR struct {
+	PullRequestID      string `json:"pullRequestId"`
+	Title              string `json:"title"`
+	PullRequestStatus  string `json:"pullRequestStatus"`
+	PullRequestTargets []struct {
+		RepositoryName       string `json:"repositoryName"`
+		SourceReference      string `json:"sourceReference"`
+		DestinationReference string `json:"destinationReference"`
+	} `json:"pullRequestTargets"`
+}
+
+func (p *Provider) CreatePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PullRequestInput,
+) (*globalEntities.PullRequest, error) {
+	region := p.repoRegion(repo)
+
+	body := map[string]any{
+		"title":       input.Title,
+		"description": input.Description,
+		"targets": []map[string]string{{
+			"repositoryName":       repo.Name,
+			"sourceReference":      qualifiedBranch(input.SourceBranch),
+			"destinationReference": qualifiedBranch(input.TargetBranch),
+		}},
+	}
+
+	resp, err := p.doRequest(ctx, region, "CreatePullRequest", body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+
+	var result struct {
+		PullRequest codecommitPR `json:"pullRequest"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request response: %w", unmarshalErr)
+	}
+
+	id, err := strconv.Atoi(result.PullRequest.PullRequestID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pull request ID %q: %w", result.PullRequest.PullRequestID, err)
+	}
+
+	return &globalEntities.PullRequest{
+		ID:     id,
+		Title:  result.PullRequest.Title,
+		URL:    pullRequestConsoleURL(region, repo.Name, result.PullRequest.PullRequestID),
+		Status: result.PullRequest.PullRequestStatus,
+	}, nil
+}
+
+// findOpenPullRequestID returns the ID of the open pull request whose source reference
+// is sourceBranch. The boolean reports whether such a pull request was found.
+func (p *Provider) findOpenPullRequestID(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (string, bool, error) {
+	region := p.repoRegion(repo)
+	ref := qualifiedBranch(sourceBranch)
+	nextToken := ""
+
+	for {
+		body := map[string]any{
+			"repositoryName":    repo.Name,
+			"pullRequestStatus": pullRequestStatusOpen,
+		}
+		if nextToken != "" {
+			body["nextToken"] = nextToken
+		}
+
+		resp, err := p.doRequest(ctx, region, "ListPullRequests", body)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to list pull requests: %w", err)
+		}
+
+		var result struct {
+			PullRequestIDs []string `json:"pullRequestIds"`
+			NextToken      string   `json:"nextToken"`
+		}
+		if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+			return "", false, fmt.Errorf("failed to parse pull requests response: %w", unmarshalErr)
+		}
+
+		// ListPullRequests only returns IDs, so each one is fetched to compare its source
+		for _, id := range result.PullRequestIDs {
+			pr, getErr := p.getPullRequest(ctx, region, id)
+			if getErr != nil {
+				return "", false, getErr
+			}
+			for _, target := range pr.PullRequestTargets {
+				if target.RepositoryName == repo.Name && target.SourceReference == ref {
+					return id, true, nil
+				}
+			}
+		}
+
+		if result.NextToken == "" {
+			return "", false, nil
+		}
+		nextToken = result.NextToken
+	}
+}
+
+func (p *Provider) getPullRequest(ctx context.Context, region, id string) (*codecommitPR, error) {
+	resp, err := p.doRequest(ctx, region, "GetPullRequest", map[string]string{"pullRequestId": id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request %s: %w", id, err)
+	}
+
+	var result struct {
+		PullRequest codecommitPR `json:"pullRequest"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &result); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse pull request response: %w", unmarshalErr)
+	}
+
+	return &result.PullRequest, nil
+}
+
+func (p *Provider) PullRequestExists(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	_, found, err := p.findOpenPullRequestID(ctx, repo, sourceBranch)
+	return found, err
+}
+
+func (p *Provider) ClosePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	id, found, err := p.findOpenPullRequestID(ctx, repo, sourceBranch)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+
+	body := map[string]string{
+		"pullRequestId":     id,
+		"pullRequestStatus": pullRequestStatusClosed,
+	}
+	if _, closeErr := p.doRequest(ctx, p.repoRegion(repo), "UpdatePullRequestStatus", body); closeErr != nil {
+		return false, fmt.Errorf("failed to close pull request %s: %w", id, closeErr)
+	}
+
+	return true, nil
+}
+
+// qualifiedBranch returns the branch as a full "refs/heads/..." reference,
+// which is how CodeCommit stores pull request sources and destinations.
+func qualifiedBranch(branch string) string {
+	return "refs/heads/" + strings.TrimPrefix(branch, "refs/heads/")
+}
+
+// pullRequestConsoleURL returns the AWS console page of a pull request.
+func pullRequestConsoleURL(region, repoName, id string) string {
+	return fmt.Sprintf(
+		"https://%s.console.aws.amazon.com/codesuite/codecommit/repositories/%s/pull-requests/%s/details?region=%s",
+		region, repoName, id, region,
+	)
+}
@@ -0,0 +1,728 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// newTestProvider creates a Provider pointed at the given test server.
+func newTestProvider(t *testing.T, server *httptest.Server) *Provider {
+	t.Helper()
+	return &Provider{
+		token:      "jdoe:secret",
+		baseURL:    server.URL,
+		httpClient: server.Client(),
+	}
+}
+
+func testRepo() globalEntities.Repository {
+	return globalEntities.Repository{Organization: "tools", Name: "my-repo"}
+}
+
+// writeJSON answers with the XSSI-prefixed JSON Gerrit sends.
+func writeJSON(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(")]}'\n" + body))
+}
+
+func TestDiscoverRepositoriesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should strip the XSSI prefix and map projects under the prefix", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var prefix, username string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/projects/", func(w http.ResponseWriter, r *http.Request) {
+			prefix = r.URL.Query().Get("p")
+			username, _, _ = r.BasicAuth()
+			writeJSON(w, `{"tools/my-repo":{"id":"tools%2Fmy-repo","state":"ACTIVE"},`+
+				`"tools/old":{"id":"tools%2Fold","state":"READ_ONLY"},`+
+				`"tools/secret":{"id":"tools%2Fsecret","state":"HIDDEN"},`+
+				`"All-Projects":{"id":"All-Projects"}}`)
+		})
+		mux.HandleFunc("GET /a/projects/tools%2Fmy-repo/HEAD", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `"refs/heads/master"`)
+		})
+		mux.HandleFunc("GET /a/projects/tools%2Fold/HEAD", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		repos, err := p.DiscoverRepositories(context.Background(), "tools")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "tools/", prefix)
+		assert.Equal(t, "jdoe", username)
+		require.Len(t, repos, 2)
+		assert.Equal(t, "my-repo", repos[0].Name)
+		assert.Equal(t, "tools", repos[0].Organization)
+		assert.Equal(t, "refs/heads/master", repos[0].DefaultBranch)
+		assert.Equal(t, server.URL+"/tools/my-repo", repos[0].RemoteURL)
+		assert.Equal(t, "gerrit", repos[0].ProviderName)
+		assert.False(t, repos[0].IsArchived)
+		assert.Equal(t, "old", repos[1].Name)
+		assert.Equal(t, "refs/heads/main", repos[1].DefaultBranch)
+		assert.True(t, repos[1].IsArchived)
+	})
+}
+
+func TestCreatePullRequestInternal(t *testing.T) {
+	t.Parallel()
+
+	createInput := globalEntities.PullRequestInput{
+		SourceBranch: "refs/heads/feature/bump",
+		TargetBranch: "main",
+	}
+
+	t.Run("should push the branch head with a Change-Id to refs/for and return the change", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		fake := newFakeGerrit(t)
+		head := fake.commitOnBranch(t, "chore: bump dependencies")
+		wantChangeID, err := derivedChangeID(head)
+		require.NoError(t, err)
+
+		// when
+		pr, err := fake.provider.CreatePullRequest(context.Background(), fake.repo, createInput)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, pr.ID)
+		assert.Equal(t, "open", pr.Status)
+		assert.Equal(t, fake.server.URL+"/c/tools/my-repo/+/1", pr.URL)
+		assert.Equal(t, []string{
+			`topic:"feature/bump" project:tools/my-repo status:open`,
+			"change:" + wantChangeID + " project:tools/my-repo status:open",
+		}, fake.queries)
+		assert.Equal(t, "chore: bump dependencies\n\nChange-Id: "+wantChangeID+"\n", fake.pushed)
+	})
+
+	t.Run("should add a patch set to the open change of the branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		fake := newFakeGerrit(t)
+		fake.commitOnBranch(t, "chore: bump dependencies")
+		first, err := fake.provider.CreatePullRequest(context.Background(), fake.repo, createInput)
+		require.NoError(t, err)
+		fake.commitOnBranch(t, "chore: bump more dependencies")
+
+		// when
+		second, err := fake.provider.CreatePullRequest(context.Background(), fake.repo, createInput)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		require.Len(t, fake.changes, 1)
+		assert.Contains(t, fake.pushed, "Change-Id: "+fake.changes[0].ChangeID)
+	})
+
+	t.Run("should open a new change when the branch name is reused after abandoning", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		fake := newFakeGerrit(t)
+		fake.commitOnBranch(t, "chore: bump dependencies")
+		first, err := fake.provider.CreatePullRequest(context.Background(), fake.repo, createInput)
+		require.NoError(t, err)
+		closed, err := fake.provider.ClosePullRequest(context.Background(), fake.repo, "feature/bump")
+		require.NoError(t, err)
+		require.True(t, closed)
+		fake.recreateBranch(t, "chore: bump dependencies")
+
+		// when
+		second, err := fake.provider.CreatePullRequest(context.Background(), fake.repo, createInput)
+
+		// then
+		require.NoError(t, err)
+		assert.NotEqual(t, first.ID, second.ID)
+		assert.Equal(t, "open", second.Status)
+		require.Len(t, fake.changes, 2)
+		assert.Equal(t, "ABANDONED", fake.changes[0].Status)
+		assert.NotEqual(t, fake.changes[0].ChangeID, fake.changes[1].ChangeID)
+	})
+}
+
+// fakeGerrit is a Gerrit REST API over a local repository standing in for the
+// git side. The first query after a push to refs/for/main takes the pushed
+// commit in, like Gerrit does on push: it opens a change for its Change-Id or
+// adds a patch set to the open one, and fails the query when that change is
+// closed, as Gerrit rejects such a push.
+type fakeGerrit struct {
+	server   *httptest.Server
+	provider *Provider
+	remote   *git.Repository
+	repo     globalEntities.Repository
+	now      time.Time
+
+	mu      sync.Mutex
+	changes []gerritChange
+	queries []string
+	pushed  string // message of the commit last pushed for review
+}
+
+const fakeGerritPushRef = "refs/for/main%topic=feature/bump"
+
+func newFakeGerrit(t *testing.T) *fakeGerrit {
+	t.Helper()
+
+	remoteDir := t.TempDir()
+	remote, err := git.PlainInit(remoteDir, false)
+	require.NoError(t, err)
+
+	f := &fakeGerrit{remote: remote, now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	f.commit(t, "initial")
+
+	// anonymous access, so the REST calls carry no "/a" prefix
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /changes/", f.queryChanges)
+	mux.HandleFunc("POST /changes/{id}/abandon", f.abandon)
+	mux.HandleFunc("GET /projects/{project}/branches/{branch}", func(w http.ResponseWriter, r *http.Request) {
+		ref, refErr := remote.Reference(plumbing.NewBranchReferenceName(r.PathValue("branch")), true)
+		if refErr != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(map[string]string{"revision": ref.Hash().String()})
+		writeJSON(w, string(body))
+	})
+	mux.HandleFunc("GET /projects/{project}/commits/{sha}", func(w http.ResponseWriter, r *http.Request) {
+		commit, commitErr := remote.CommitObject(plumbing.NewHash(r.PathValue("sha")))
+		if commitErr != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := json.Marshal(map[string]string{"message": commit.Message})
+		writeJSON(w, string(body))
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+
+	f.provider = newTestProvider(t, f.server)
+	f.provider.token = ""
+	f.repo = testRepo()
+	f.repo.RemoteURL = remoteDir
+	return f
+}
+
+// commit adds an empty commit on the checked out branch of the remote.
+func (f *fakeGerrit) commit(t *testing.T, message string) *object.Commit {
+	t.Helper()
+
+	worktree, err := f.remote.Worktree()
+	require.NoError(t, err)
+	f.now = f.now.Add(time.Minute)
+	hash, err := worktree.Commit(message, &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            &object.Signature{Name: "bot", Email: "bot@example.org", When: f.now},
+	})
+	require.NoError(t, err)
+	commit, err := f.remote.CommitObject(hash)
+	require.NoError(t, err)
+	return commit
+}
+
+// commitOnBranch adds a commit on feature/bump, creating the branch from the
+// checked out one when it does not exist yet.
+func (f *fakeGerrit) commitOnBranch(t *testing.T, message string) *object.Commit {
+	t.Helper()
+
+	worktree, err := f.remote.Worktree()
+	require.NoError(t, err)
+	branch := plumbing.NewBranchReferenceName("feature/bump")
+	_, refErr := f.remote.Reference(branch, true)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: branch, Create: refErr != nil}))
+	return f.commit(t, message)
+}
+
+// recreateBranch deletes feature/bump and creates it again from master with a
+// new commit, as autobump does for its next bump.
+func (f *fakeGerrit) recreateBranch(t *testing.T, message string) {
+	t.Helper()
+
+	worktree, err := f.remote.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}))
+	require.NoError(t, f.remote.Storer.RemoveReference(plumbing.NewBranchReferenceName("feature/bump")))
+	f.commitOnBranch(t, message)
+}
+
+func (f *fakeGerrit) queryChanges(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	query := r.URL.Query().Get("q")
+	f.queries = append(f.queries, query)
+	if !f.openPushedChange() {
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	matches := []gerritChange{}
+	for _, change := range f.changes {
+		if change.matches(query) {
+			matches = append(matches, change)
+		}
+	}
+	body, _ := json.Marshal(matches)
+	writeJSON(w, string(body))
+}
+
+// openPushedChange takes in the commit pushed for review since the last
+// query, if any, and removes the push ref so the next push need not fast
+// forward. It returns false when the change of the commit is closed.
+func (f *fakeGerrit) openPushedChange() bool {
+	ref, err := f.remote.Reference(fakeGerritPushRef, true)
+	if err != nil {
+		return true
+	}
+	commit, err := f.remote.CommitObject(ref.Hash())
+	if err != nil || f.remote.Storer.RemoveReference(ref.Name()) != nil {
+		return false
+	}
+	f.pushed = commit.Message
+	changeID, _ := extractChangeID(commit.Message)
+	for _, change := range f.changes {
+		if change.ChangeID == changeID {
+			return change.Status == "NEW"
+		}
+	}
+	f.changes = append(f.changes, gerritChange{
+		Project:  "tools/my-repo",
+		Branch:   "main",
+		Topic:    "feature/bump",
+		ChangeID: changeID,
+		Subject:  strings.SplitN(commit.Message, "\n", 2)[0],
+		Status:   "NEW",
+		Number:   len(f.changes) + 1,
+	})
+	return true
+}
+
+func (f *fakeGerrit) abandon(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, number, _ := strings.Cut(r.PathValue("id"), "~")
+	for i := range f.changes {
+		if fmt.Sprint(f.changes[i].Number) == number {
+			f.changes[i].Status = "ABANDONED"
+			body, _ := json.Marshal(f.changes[i])
+			writeJSON(w, string(body))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// matches reports whether the change satisfies every operator of a query
+// like "topic:\"x\" project:p status:open".
+func (c gerritChange) matches(query string) bool {
+	for _, term := range strings.Fields(query) {
+		operator, value, _ := strings.Cut(term, ":")
+		switch operator {
+		case "change":
+			if c.ChangeID != value {
+				return false
+			}
+		case "topic":
+			if c.Topic != strings.Trim(value, `"`) {
+				return false
+			}
+		case "project":
+			if c.Project != value {
+				return false
+			}
+		case "status":
+			if value == "open" && c.Status != "NEW" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestChangeIDHelpers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should join an existing trailer block", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		message := "fix: thing\n\nbody text\n\nSigned-off-by: bot <bot@example.org>\n"
+
+		// when
+		result := appendChangeID(message, "I0123")
+
+		// then
+		assert.Equal(t, "fix: thing\n\nbody text\n\nSigned-off-by: bot <bot@example.org>\nChange-Id: I0123\n", result)
+	})
+
+	t.Run("should read the Change-Id trailer of a commit message", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		changeID := "I" + strings.Repeat("ab", 20)
+
+		// when
+		result, found := extractChangeID("fix: thing\n\nChange-Id: " + changeID + "\n")
+
+		// then
+		assert.True(t, found)
+		assert.Equal(t, changeID, result)
+	})
+}
+
+func TestPullRequestLookupInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should abandon the open change found by the branch head Change-Id", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		changeID := "I" + strings.Repeat("cd", 20)
+		var query string
+		var abandoned bool
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/projects/tools%2Fmy-repo/branches/feature%2Fbump",
+			func(w http.ResponseWriter, _ *http.Request) {
+				writeJSON(w, `{"ref":"refs/heads/feature/bump","revision":"abc123"}`)
+			})
+		mux.HandleFunc("GET /a/projects/tools%2Fmy-repo/commits/abc123", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `{"message":"chore: bump\n\nChange-Id: `+changeID+`\n"}`)
+		})
+		mux.HandleFunc("GET /a/changes/", func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("q")
+			writeJSON(w, `[{"_number":42,"status":"NEW"}]`)
+		})
+		mux.HandleFunc("POST /a/changes/tools%2Fmy-repo~42/abandon", func(w http.ResponseWriter, _ *http.Request) {
+			abandoned = true
+			writeJSON(w, `{"_number":42,"status":"ABANDONED"}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		closed, err := p.ClosePullRequest(context.Background(), testRepo(), "feature/bump")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, closed)
+		assert.True(t, abandoned)
+		assert.Equal(t, "change:"+changeID+" project:tools/my-repo status:open", query)
+	})
+
+	t.Run("should report no change when the source branch does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/projects/tools%2Fmy-repo/branches/gone", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		exists, err := p.PullRequestExists(context.Background(), testRepo(), "gone")
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestReviewInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should decode the patch and list files without magic entries", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		patch := "From abc\nSubject: fix\n---\ndiff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n"
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7/revisions/current/patch",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString([]byte(patch))))
+			})
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7/revisions/current/files",
+			func(w http.ResponseWriter, _ *http.Request) {
+				writeJSON(w, `{"/COMMIT_MSG":{"status":"A","lines_inserted":7},`+
+					`"a.go":{"lines_inserted":1,"lines_deleted":1},`+
+					`"b.go":{"status":"R","old_path":"c.go"}}`)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		files, err := p.GetPullRequestFiles(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		assert.Equal(t, "a.go", files[0].Path)
+		assert.Equal(t, "modified", files[0].Status)
+		assert.Equal(t, 1, files[0].Additions)
+		assert.Contains(t, files[0].Patch, "+new")
+		assert.Equal(t, "renamed", files[1].Status)
+		assert.Equal(t, "c.go", files[1].OldPath)
+	})
+
+	t.Run("should map messages and comment chains onto threads", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7/messages", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `[{"id":"m1","message":"Uploaded patch set 1.","tag":"autogenerated:gerrit:newPatchSet"},`+
+				`{"id":"m2","message":"Looks good","author":{"username":"alice"}}]`)
+		})
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7/comments", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `{"main.go":[{"id":"c1","line":4,"message":"root","author":{"username":"alice"}},`+
+				`{"id":"c3","line":4,"in_reply_to":"c2","message":"again","author":{"username":"alice"}},`+
+				`{"id":"c2","line":4,"in_reply_to":"c1","message":"reply","author":{"name":"Bob"}}],`+
+				`"/PATCHSET_LEVEL":[{"id":"c4","message":"overall","author":{"username":"carol"}}]}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		comments, err := p.ListPullRequestComments(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, comments, 5)
+		assert.Equal(t, globalEntities.PullRequestComment{
+			ID: commentKey("m2"), Body: "Looks good", Author: "alice",
+		}, comments[0])
+		assert.Empty(t, comments[1].FilePath)
+		assert.Equal(t, commentKey("c4"), comments[1].ThreadID)
+		assert.Equal(t, globalEntities.PullRequestComment{
+			ID: commentKey("c3"), ThreadID: commentKey("c1"), Body: "again", Author: "alice",
+			FilePath: "main.go", Line: 4, InReplyToID: commentKey("c2"),
+		}, comments[3])
+		assert.Equal(t, "Bob", comments[4].Author)
+	})
+
+	t.Run("should save a draft and publish it for thread comments", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var draft, review map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("PUT /a/changes/tools%2Fmy-repo~7/revisions/current/drafts",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&draft)
+				writeJSON(w, `{"id":"d1"}`)
+			})
+		mux.HandleFunc("POST /a/changes/tools%2Fmy-repo~7/revisions/current/review",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&review)
+				writeJSON(w, `{}`)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		id, err := p.PostPullRequestThreadComment(context.Background(), testRepo(), 7, "main.go", 4, "nit")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int(commentKey("d1")), id)
+		assert.Equal(t, map[string]any{
+			"path": "main.go", "line": float64(4), "message": "nit", "unresolved": true,
+		}, draft)
+		assert.Equal(t, map[string]any{"drafts": "PUBLISH"}, review)
+	})
+
+	t.Run("should resolve a thread by publishing a resolving reply", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var draft map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7/comments", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `{"main.go":[{"id":"c1","line":4,"message":"root","unresolved":true}]}`)
+		})
+		mux.HandleFunc("PUT /a/changes/tools%2Fmy-repo~7/revisions/current/drafts",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&draft)
+				writeJSON(w, `{"id":"d2"}`)
+			})
+		mux.HandleFunc("POST /a/changes/tools%2Fmy-repo~7/revisions/current/review",
+			func(w http.ResponseWriter, _ *http.Request) {
+				writeJSON(w, `{}`)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		err := p.UpdatePullRequestThreadStatus(context.Background(), testRepo(), 7, int(commentKey("c1")), "fixed")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"path": "main.go", "line": float64(4), "in_reply_to": "c1", "message": "Done", "unresolved": false,
+		}, draft)
+	})
+
+	t.Run("should report checks from the Verified label", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "LABELS", r.URL.Query().Get("o"))
+			writeJSON(w, `{"_number":7,"labels":{"Verified":{"approved":{"name":"CI"}}}}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		passed, err := p.GetPullRequestCheckStatus(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, passed)
+	})
+
+	t.Run("should report a required Verified check failing on a rejecting vote", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `{"_number":7,"project":"tools/my-repo",
+				"labels":{"Verified":{"approved":{"name":"CI"},"rejected":{"name":"Lint"}}}}`)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		checks, err := p.GetPullRequestChecks(context.Background(), testRepo(), 7)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &globalEntities.PullRequestChecks{
+			Checks: []globalEntities.Check{{
+				Name:     "Verified",
+				Kind:     globalEntities.CheckKindPolicy,
+				State:    globalEntities.CheckStateFailure,
+				Required: true,
+				URL:      server.URL + "/c/tools/my-repo/+/7",
+			}},
+			Verdict: globalEntities.CheckStateFailure,
+		}, checks)
+	})
+
+	t.Run("should vote the highest permitted Code-Review score on approve", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var reviews []map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /a/changes/tools%2Fmy-repo~7", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `{"_number":7,"permitted_labels":{"Code-Review":["-1"," 0","+1"]}}`)
+		})
+		mux.HandleFunc("POST /a/changes/tools%2Fmy-repo~7/revisions/current/review",
+			func(w http.ResponseWriter, r *http.Request) {
+				var review map[string]any
+				_ = json.NewDecoder(r.Body).Decode(&review)
+				reviews = append(reviews, review)
+				writeJSON(w, `{}`)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		approveErr := p.SubmitPullRequestReview(context.Background(), testRepo(), 7, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictApprove,
+		})
+		changesErr := p.SubmitPullRequestReview(context.Background(), testRepo(), 7, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictRequestChanges,
+			Body:    "please fix",
+		})
+		commentErr := p.SubmitPullRequestReview(context.Background(), testRepo(), 7, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictComment,
+		})
+
+		// then
+		require.NoError(t, approveErr)
+		require.NoError(t, changesErr)
+		require.NoError(t, commentErr)
+		require.Len(t, reviews, 2)
+		assert.Equal(t, map[string]any{"Code-Review": float64(1)}, reviews[0]["labels"])
+		assert.Equal(t, map[string]any{"Code-Review": float64(-1)}, reviews[1]["labels"])
+		assert.Equal(t, "please fix", reviews[1]["message"])
+	})
+
+	t.Run("should submit the change and delete the topic branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var deleted bool
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /a/changes/tools%2Fmy-repo~7/submit", func(w http.ResponseWriter, _ *http.Request) {
+			writeJSON(w, `{"_number":7,"status":"MERGED","topic":"feature/bump"}`)
+		})
+		mux.HandleFunc("DELETE /a/projects/tools%2Fmy-repo/branches/feature%2Fbump",
+			func(w http.ResponseWriter, _ *http.Request) {
+				deleted = true
+				w.WriteHeader(http.StatusNoContent)
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+
+		// when
+		err := p.MergePullRequest(context.Background(), testRepo(), 7, "squash",
+			globalEntities.WithDeleteSourceBranch())
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, deleted)
+	})
+}
@@ -0,0 +1,191 @@
+package gerrit
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gohttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	providerName    = "gerrit"
+	perPage         = 100
+	httpTimeout     = 30 * time.Second
+	httpStatusOKMin = 200
+	httpStatusOKMax = 300
+
+	// defaultSSHPort is the port Gerrit serves SSH on by default.
+	defaultSSHPort = 29418
+
+	// authPrefix is the path prefix Gerrit requires for authenticated REST
+	// calls and authenticated git over HTTP.
+	authPrefix = "/a"
+)
+
+// Provider implements ForgeProvider, ReviewProvider, ChecksProvider, and LocalGitAuthProvider for Gerrit.
+//
+// Gerrit has no pull requests: a change is created by pushing a commit to the
+// magic `refs/for/{branch}` ref and is then reviewed patch set by patch set. The
+// provider maps changes onto pull requests, using the change number as the pull
+// request ID. Repository.Organization is the parent path of the Gerrit project
+// (empty for top-level projects) and Repository.Name its last path segment.
+//
+// The token is "username:http-password", sent as HTTP Basic credentials, or a
+// bare token sent as a Bearer token for instances with an OAuth plugin.
+type Provider struct {
+	token      string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewProvider creates a new Gerrit provider for the instance rooted at baseURL
+// (e.g. "https://review.example.org").
+func NewProvider(token, baseURL string) (globalEntities.ForgeProvider, error) {
+	return NewProviderWithClient(token, baseURL, nil)
+}
+
+// NewProviderWithClient creates a new Gerrit provider with an explicit HTTP client.
+// If client is nil, a default client with the standard timeout is used.
+func NewProviderWithClient(token, baseURL string, client *http.Client) (globalEntities.ForgeProvider, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Gerrit URL %q", baseURL)
+	}
+
+	if client == nil {
+		client = &http.Client{
+			Timeout: httpTimeout,
+		}
+	}
+
+	return &Provider{
+		token:      token,
+		baseURL:    baseURL,
+		httpClient: client,
+	}, nil
+}
+
+func (p *Provider) Name() string      { return providerName }
+func (p *Provider) AuthToken() string { return p.token }
+
+func (p *Provider) MatchesURL(rawURL string) bool {
+	return strings.Contains(rawURL, p.hostname())
+}
+
+// hostname returns the host name of the instance, without any port.
+func (p *Provider) hostname() string {
+	parsed, err := url.Parse(p.baseURL)
+	if err != nil {
+		return p.baseURL
+	}
+	return parsed.Hostname()
+}
+
+// basicCredentials splits a "username:http-password" token. The boolean is
+// false when the token is a bare Bearer token.
+func (p *Provider) basicCredentials() (string, string, bool) {
+	return strings.Cut(p.token, ":")
+}
+
+// projectName returns the full Gerrit project name of a repository.
+func projectName(repo globalEntities.Repository) string {
+	if repo.Organization == "" {
+		return repo.Name
+	}
+	return repo.Organization + "/" + repo.Name
+}
+
+// --- LocalGitAuthProvider ---
+
+func (p *Provider) GetServiceType() globalEntities.ServiceType {
+	return globalEntities.GERRIT
+}
+
+func (p *Provider) PrepareCloneURL(cloneURL string) string {
+	return cloneURL
+}
+
+func (p *Provider) ConfigureTransport() {
+	// Gerrit doesn't need special transport configuration
+}
+
+func (p *Provider) GetAuthMethods(_ string) []transport.AuthMethod {
+	var authMethods []transport.AuthMethod
+
+	if auth := p.gitAuth(); auth != nil {
+		log.Infof("Using HTTP credentials to authenticate with Gerrit")
+		authMethods = append(authMethods, auth)
+	}
+
+	return authMethods
+}
+
+// gitAuth returns the go-git auth method for the configured token, or nil
+// when no token is configured.
+func (p *Provider) gitAuth() transport.AuthMethod {
+	if p.token == "" {
+		return nil
+	}
+	if username, password, ok := p.basicCredentials(); ok {
+		return &gohttp.BasicAuth{
+			Username: username,
+			Password: password,
+		}
+	}
+	return &gohttp.TokenAuth{
+		Token: p.token,
+	}
+}
+
+// gitURL returns the URL git traffic for the repository goes to: the
+// repository's remote URL or, when it has none, the authenticated HTTP
+// endpoint of the project.
+func (p *Provider) gitURL(repo globalEntities.Repository) string {
+	if repo.RemoteURL != "" {
+		return repo.RemoteURL
+	}
+	if p.token == "" {
+		return fmt.Sprintf("%s/%s", p.baseURL, projectName(repo))
+	}
+	return fmt.Sprintf("%s%s/%s", p.baseURL, authPrefix, projectName(repo))
+}
+
+// CloneURL returns the HTTP clone URL with the username and HTTP password
+// embedded. Bearer tokens cannot be embedded in a URL, so the plain URL is
+// returned for them and GetAuthMethods supplies the token instead.
+func (p *Provider) CloneURL(repo globalEntities.Repository) string {
+	remoteURL := p.gitURL(repo)
+
+	username, password, ok := p.basicCredentials()
+	if !ok {
+		return remoteURL
+	}
+
+	parsed, err := url.Parse(remoteURL)
+	if err != nil {
+		return remoteURL
+	}
+	parsed.User = url.UserPassword(username, password)
+
+	return parsed.String()
+}
+
+// SSHCloneURL returns the SSH clone URL. Gerrit authenticates SSH by account
+// username, so it is included when the token carries one.
+func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
+	host := p.hostname()
+	if sshAlias != "" {
+		host = fmt.Sprintf("%s-%s", host, sshAlias)
+	}
+	if username, _, ok := p.basicCredentials(); ok {
+		host = username + "@" + host
+	}
+	return fmt.Sprintf("ssh://%s:%d/%s", host, defaultSSHPort, projectName(repo))
+}
@@ -0,0 +1,126 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// Projects every Gerrit site has for its own configuration. They hold no code
+// and are never returned by DiscoverRepositories.
+const (
+	allProjectsName = "All-Projects"
+	allUsersName    = "All-Users"
+)
+
+type gerritProject struct {
+	ID    string `json:"id"`
+	State string `json:"state"`
+}
+
+// DiscoverRepositories lists every project whose name starts with "org/", or
+// every project of the site when org is empty. Read-only projects are reported
+// as archived and hidden projects are skipped.
+func (p *Provider) DiscoverRepositories(
+	ctx context.Context,
+	org string,
+) ([]globalEntities.Repository, error) {
+	projects, err := p.listProjects(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover projects for %q: %w", org, err)
+	}
+
+	names := make([]string, 0, len(projects))
+	for name, project := range projects {
+		if name == allProjectsName || name == allUsersName || project.State == "HIDDEN" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	allRepos := make([]globalEntities.Repository, 0, len(names))
+	for _, name := range names {
+		repo := p.projectToDomain(name, projects[name])
+		repo.DefaultBranch = p.getDefaultBranch(ctx, name)
+		allRepos = append(allRepos, repo)
+	}
+
+	return allRepos, nil
+}
+
+// listProjects walks every page of the project list. Gerrit returns projects
+// as a map keyed by name and signals the last page by returning fewer entries
+// than requested.
+func (p *Provider) listProjects(ctx context.Context, org string) (map[string]gerritProject, error) {
+	all := make(map[string]gerritProject)
+	prefix := ""
+	if org != "" {
+		prefix = "&p=" + url.QueryEscape(strings.TrimSuffix(org, "/")+"/")
+	}
+
+	for start := 0; ; start += perPage {
+		endpoint := fmt.Sprintf("/projects/?n=%d&S=%d%s", perPage, start, prefix)
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var projects map[string]gerritProject
+		if unmarshalErr := json.Unmarshal(resp, &projects); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse projects response: %w", unmarshalErr)
+		}
+
+		for name, project := range projects {
+			all[name] = project
+		}
+		if len(projects) < perPage {
+			return all, nil
+		}
+	}
+}
+
+func (p *Provider) projectToDomain(name string, project gerritProject) globalEntities.Repository {
+	org := path.Dir(name)
+	if org == "." {
+		org = ""
+	}
+
+	repo := globalEntities.Repository{
+		ID:           project.ID,
+		Name:         path.Base(name),
+		Organization: org,
+		RemoteURL:    fmt.Sprintf("%s/%s", p.baseURL, name),
+		ProviderName: providerName,
+		IsArchived:   project.State == "READ_ONLY",
+	}
+	repo.SSHURL = p.SSHCloneURL(repo, "")
+
+	return repo
+}
+
+// getDefaultBranch returns the branch the project's HEAD points at, falling
+// back to "refs/heads/main" when it cannot be read.
+func (p *Provider) getDefaultBranch(ctx context.Context, project string) string {
+	const fallback = "refs/heads/main"
+
+	endpoint := fmt.Sprintf("/projects/%s/HEAD", url.PathEscape(project))
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fallback
+	}
+
+	var head string
+	if unmarshalErr := json.Unmarshal(resp, &head); unmarshalErr != nil || head == "" {
+		return fallback
+	}
+
+	return head
+}
@@ -0,0 +1,100 @@
+package gerrit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// xssiPrefix is the line Gerrit puts in front of every JSON response to
+// defeat cross-site script inclusion. It must be stripped before decoding.
+const xssiPrefix = ")]}'"
+
+// apiError represents an HTTP API error with the status code preserved.
+type apiError struct {
+	statusCode int
+	body       string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("API error (status %d): %s", e.statusCode, e.body)
+}
+
+// StatusCode returns the HTTP status code of the failed request.
+func (e *apiError) StatusCode() int {
+	return e.statusCode
+}
+
+// doRequest sends a JSON request to the Gerrit REST API and returns the
+// response body with the XSSI prefix removed. endpoint is the path below the
+// instance root, e.g. "/changes/"; the authenticated "/a" prefix is added when
+// a token is configured.
+func (p *Provider) doRequest(
+	ctx context.Context,
+	method, endpoint string,
+	body any,
+) ([]byte, error) {
+	var reqBody io.Reader
+	if body != nil {
+		jsonBody, marshalErr := json.Marshal(body)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", marshalErr)
+		}
+		reqBody = bytes.NewReader(jsonBody)
+	}
+
+	fullURL := p.baseURL + endpoint
+	if p.token != "" {
+		fullURL = p.baseURL + authPrefix + endpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if p.token != "" {
+		if username, password, ok := p.basicCredentials(); ok {
+			req.SetBasicAuth(username, password)
+		} else {
+			req.Header.Set("Authorization", "Bearer "+p.token)
+		}
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < httpStatusOKMin || resp.StatusCode >= httpStatusOKMax {
+		return nil, &apiError{
+			statusCode: resp.StatusCode,
+			body:       string(respBody),
+		}
+	}
+
+	return stripXSSIPrefix(respBody), nil
+}
+
+// stripXSSIPrefix removes the `)]}'` line Gerrit prepends to JSON responses.
+// Bodies without the prefix (e.g. base64 patches) are returned unchanged.
+func stripXSSIPrefix(body []byte) []byte {
+	trimmed, found := bytes.CutPrefix(body, []byte(xssiPrefix))
+	if !found {
+		return body
+	}
+	return bytes.TrimLeft(trimmed, "\r\n")
+}
@@ -0,0 +1,412 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// changeIDPattern matches a Change-Id trailer in a commit message.
+var changeIDPattern = regexp.MustCompile(`(?m)^Change-Id: (I[0-9a-f]{40})\s*$`)
+
+// trailerPattern matches a "Key: value" git trailer line.
+var trailerPattern = regexp.MustCompile(`^[A-Za-z0-9-]+: `)
+
+type gerritAccount struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// login returns the account's username, or its full name when the site does
+// not expose usernames.
+func (a gerritAccount) login() string {
+	if a.Username != "" {
+		return a.Username
+	}
+	return a.Name
+}
+
+type gerritChange struct {
+	Project         string                 `json:"project"`
+	Branch          string                 `json:"branch"`
+	Topic           string                 `json:"topic"`
+	ChangeID        string                 `json:"change_id"`
+	Subject         string                 `json:"subject"`
+	Status          string                 `json:"status"`
+	Number          int                    `json:"_number"`
+	WorkInProgress  bool                   `json:"work_in_progress"`
+	Owner           gerritAccount          `json:"owner"`
+	Labels          map[string]gerritLabel `json:"labels"`
+	PermittedLabels map[string][]string    `json:"permitted_labels"`
+	MoreChanges     bool                   `json:"_more_changes"`
+}
+
+type gerritLabel struct {
+	Approved *gerritAccount `json:"approved"`
+	Rejected *gerritAccount `json:"rejected"`
+}
+
+// changeURL returns the web URL of a change.
+func (p *Provider) changeURL(change gerritChange) string {
+	return fmt.Sprintf("%s/c/%s/+/%d", p.baseURL, change.Project, change.Number)
+}
+
+// changeEndpoint returns the REST path of a change, identified by project and
+// number so the lookup is unambiguous across projects.
+func changeEndpoint(repo globalEntities.Repository, number int) string {
+	return fmt.Sprintf("/changes/%s~%d", url.PathEscape(projectName(repo)), number)
+}
+
+// CreatePullRequest pushes the head of the source branch to
+// `refs/for/{target}`, which makes Gerrit open a change (or a new patch set of
+// an existing one) for it, with the source branch name as the change topic.
+//
+// Gerrit identifies changes by the Change-Id trailer of the commit message. A
+// head commit without one is amended in memory with the Change-Id of the open
+// change whose topic is the source branch, so later pushes of the branch add
+// patch sets to it. Without an open change, the Change-Id is derived from the
+// head commit like the commit-msg hook does, so a branch name reused after its
+// change was merged or abandoned opens a new change instead of pushing to the
+// closed one. The change subject and description come from the commit
+// message; input.Title and input.Description are not used.
+func (p *Provider) CreatePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PullRequestInput,
+) (*globalEntities.PullRequest, error) {
+	sourceBranch := strings.TrimPrefix(input.SourceBranch, "refs/heads/")
+	targetBranch := strings.TrimPrefix(input.TargetBranch, "refs/heads/")
+	localRef := plumbing.NewBranchReferenceName(sourceBranch)
+
+	storage := memory.NewStorage()
+	gitRepo, err := git.CloneContext(ctx, storage, nil, &git.CloneOptions{
+		URL:           p.gitURL(repo),
+		Auth:          p.gitAuth(),
+		ReferenceName: localRef,
+		SingleBranch:  true,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch source branch %q: %w", sourceBranch, err)
+	}
+
+	ref, err := gitRepo.Reference(localRef, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve source branch %q: %w", sourceBranch, err)
+	}
+
+	head, err := gitRepo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read head commit of %q: %w", sourceBranch, err)
+	}
+
+	changeID, found := extractChangeID(head.Message)
+	if !found {
+		changeID, err = p.branchChangeID(ctx, repo, sourceBranch, head)
+		if err != nil {
+			return nil, err
+		}
+
+		amended := *head
+		amended.Message = appendChangeID(head.Message, changeID)
+		amended.PGPSignature = ""
+
+		obj := storage.NewEncodedObject()
+		if encodeErr := amended.Encode(obj); encodeErr != nil {
+			return nil, fmt.Errorf("failed to add Change-Id to head commit: %w", encodeErr)
+		}
+		hash, setErr := storage.SetEncodedObject(obj)
+		if setErr != nil {
+			return nil, fmt.Errorf("failed to add Change-Id to head commit: %w", setErr)
+		}
+		if refErr := storage.SetReference(plumbing.NewHashReference(localRef, hash)); refErr != nil {
+			return nil, fmt.Errorf("failed to add Change-Id to head commit: %w", refErr)
+		}
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf(
+		"%s:refs/for/%s%%topic=%s", localRef, targetBranch, sourceBranch,
+	))
+	pushErr := gitRepo.PushContext(ctx, &git.PushOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       p.gitAuth(),
+	})
+	if pushErr != nil && !errors.Is(pushErr, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("failed to push to refs/for/%s: %w", targetBranch, pushErr)
+	}
+
+	change, err := p.findOpenChangeByID(ctx, repo, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if change == nil {
+		return nil, fmt.Errorf("no open change found for Change-Id %s after push", changeID)
+	}
+
+	return &globalEntities.PullRequest{
+		ID:     change.Number,
+		Title:  change.Subject,
+		URL:    p.changeURL(*change),
+		Status: mapChangeStatus(change.Status),
+	}, nil
+}
+
+// extractChangeID returns the last Change-Id trailer of a commit message.
+func extractChangeID(message string) (string, bool) {
+	matches := changeIDPattern.FindAllStringSubmatch(message, -1)
+	if len(matches) == 0 {
+		return "", false
+	}
+	return matches[len(matches)-1][1], true
+}
+
+// branchChangeID returns the Change-Id for a head commit of sourceBranch that
+// carries none: the one of the open change whose topic is the branch, or else
+// one derived from the commit.
+func (p *Provider) branchChangeID(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+	head *object.Commit,
+) (string, error) {
+	change, err := p.findOpenChangeByTopic(ctx, repo, sourceBranch)
+	if err != nil {
+		return "", err
+	}
+	if change != nil {
+		return change.ChangeID, nil
+	}
+	return derivedChangeID(head)
+}
+
+// derivedChangeID returns a Change-Id for a commit that carries none. Like the
+// commit-msg hook, it hashes the commit's tree, parents, author, committer and
+// message as a blob, so every new commit gets a new Change-Id.
+func derivedChangeID(commit *object.Commit) (string, error) {
+	unsigned := *commit
+	unsigned.PGPSignature = ""
+
+	obj := &plumbing.MemoryObject{}
+	if err := unsigned.Encode(obj); err != nil {
+		return "", fmt.Errorf("failed to derive Change-Id: %w", err)
+	}
+	reader, err := obj.Reader()
+	if err != nil {
+		return "", fmt.Errorf("failed to derive Change-Id: %w", err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive Change-Id: %w", err)
+	}
+	return "I" + plumbing.ComputeHash(plumbing.BlobObject, content).String(), nil
+}
+
+// appendChangeID adds a Change-Id trailer to a commit message, joining an
+// existing trailer block when the last paragraph is one.
+func appendChangeID(message, changeID string) string {
+	message = strings.TrimRight(message, "\n")
+	paragraphs := strings.Split(message, "\n\n")
+	last := paragraphs[len(paragraphs)-1]
+
+	isTrailerBlock := len(paragraphs) > 1
+	for line := range strings.SplitSeq(last, "\n") {
+		if !trailerPattern.MatchString(line) {
+			isTrailerBlock = false
+			break
+		}
+	}
+
+	separator := "\n\n"
+	if isTrailerBlock {
+		separator = "\n"
+	}
+	return message + separator + "Change-Id: " + changeID + "\n"
+}
+
+// sourceChangeID returns the Change-Id trailer of the head commit of
+// sourceBranch on the server, or an empty string when the commit carries none.
+// The boolean is false when the branch does not exist.
+func (p *Provider) sourceChangeID(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (string, bool, error) {
+	branch := strings.TrimPrefix(sourceBranch, "refs/heads/")
+	project := url.PathEscape(projectName(repo))
+
+	resp, err := p.doRequest(ctx, http.MethodGet,
+		fmt.Sprintf("/projects/%s/branches/%s", project, url.PathEscape(branch)), nil)
+	if err != nil {
+		var ae *apiError
+		if errors.As(err, &ae) && ae.StatusCode() == http.StatusNotFound {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get branch %q: %w", branch, err)
+	}
+
+	var branchInfo struct {
+		Revision string `json:"revision"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &branchInfo); unmarshalErr != nil {
+		return "", false, fmt.Errorf("failed to parse branch response: %w", unmarshalErr)
+	}
+
+	resp, err = p.doRequest(ctx, http.MethodGet,
+		fmt.Sprintf("/projects/%s/commits/%s", project, branchInfo.Revision), nil)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get commit %s: %w", branchInfo.Revision, err)
+	}
+
+	var commit struct {
+		Message string `json:"message"`
+	}
+	if unmarshalErr := json.Unmarshal(resp, &commit); unmarshalErr != nil {
+		return "", false, fmt.Errorf("failed to parse commit response: %w", unmarshalErr)
+	}
+
+	changeID, _ := extractChangeID(commit.Message)
+	return changeID, true, nil
+}
+
+// findOpenChange returns the open change for sourceBranch, looked up by the
+// Change-Id of the branch head, or by the branch as topic when the head
+// carries none. It returns nil when there is none.
+func (p *Provider) findOpenChange(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (*gerritChange, error) {
+	changeID, found, err := p.sourceChangeID(ctx, repo, sourceBranch)
+	if err != nil || !found {
+		return nil, err
+	}
+	if changeID == "" {
+		return p.findOpenChangeByTopic(ctx, repo, strings.TrimPrefix(sourceBranch, "refs/heads/"))
+	}
+	return p.findOpenChangeByID(ctx, repo, changeID)
+}
+
+// findOpenChangeByTopic returns the open change CreatePullRequest pushed for
+// sourceBranch, whose topic is the branch name, or nil when there is none.
+func (p *Provider) findOpenChangeByTopic(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (*gerritChange, error) {
+	return p.findOpenChangeBy(ctx, repo, fmt.Sprintf("topic:%q", sourceBranch))
+}
+
+func (p *Provider) findOpenChangeByID(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	changeID string,
+) (*gerritChange, error) {
+	return p.findOpenChangeBy(ctx, repo, "change:"+changeID)
+}
+
+// findOpenChangeBy returns the first open change of the project matching
+// operator, or nil when there is none.
+func (p *Provider) findOpenChangeBy(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	operator string,
+) (*gerritChange, error) {
+	query := fmt.Sprintf("%s project:%s status:open", operator, projectName(repo))
+
+	changes, err := p.queryChanges(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil //nolint:nilnil // no open change is not an error
+	}
+
+	return &changes[0], nil
+}
+
+// queryChanges runs a change query and walks every page of its results.
+// Gerrit flags a truncated page by setting `_more_changes` on its last entry.
+func (p *Provider) queryChanges(ctx context.Context, query string, options ...string) ([]gerritChange, error) {
+	var all []gerritChange
+
+	for start := 0; ; start += perPage {
+		endpoint := fmt.Sprintf("/changes/?q=%s&n=%d&S=%d", url.QueryEscape(query), perPage, start)
+		for _, option := range options {
+			endpoint += "&o=" + option
+		}
+
+		resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query changes: %w", err)
+		}
+
+		var changes []gerritChange
+		if unmarshalErr := json.Unmarshal(resp, &changes); unmarshalErr != nil {
+			return nil, fmt.Errorf("failed to parse changes response: %w", unmarshalErr)
+		}
+
+		all = append(all, changes...)
+		if len(changes) == 0 || !changes[len(changes)-1].MoreChanges {
+			return all, nil
+		}
+	}
+}
+
+func (p *Provider) PullRequestExists(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	change, err := p.findOpenChange(ctx, repo, sourceBranch)
+	return change != nil, err
+}
+
+// ClosePullRequest abandons the open change for sourceBranch, which is how
+// Gerrit closes a change without submitting it.
+func (p *Provider) ClosePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	change, err := p.findOpenChange(ctx, repo, sourceBranch)
+	if err != nil {
+		return false, err
+	}
+	if change == nil {
+		return false, nil
+	}
+
+	endpoint := changeEndpoint(repo, change.Number) + "/abandon"
+	if _, abandonErr := p.doRequest(ctx, http.MethodPost, endpoint, map[string]any{}); abandonErr != nil {
+		return false, fmt.Errorf("failed to abandon change %d: %w", change.Number, abandonErr)
+	}
+
+	return true, nil
+}
+
+// mapChangeStatus translates a Gerrit change status to "open", "merged", or
+// "closed" for abandoned changes.
+func mapChangeStatus(status string) string {
+	switch status {
+	case "NEW":
+		return "open"
+	case "ABANDONED":
+		return "closed"
+	default:
+		return strings.ToLower(status)
+	}
+}
@@ -0,0 +1,717 @@
+package gerrit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+	log "github.com/sirupsen/logrus"
+)
+
+// Labels the provider reads and votes on.
+const (
+	labelCodeReview = "Code-Review"
+	labelVerified   = "Verified"
+)
+
+// codeReviewNeedsWork is the Code-Review vote for "needs changes". The -2
+// veto is never used because it blocks submission until its owner removes it.
+const codeReviewNeedsWork = -1
+
+// patchsetLevelPath is the pseudo file path of comments on a whole patch set.
+const patchsetLevelPath = "/PATCHSET_LEVEL"
+
+// currentRevision is the revision ID Gerrit resolves to the latest patch set.
+const currentRevision = "current"
+
+// errCommentNotFound is returned when a comment key does not match any
+// comment on the change.
+var errCommentNotFound = errors.New("change comment not found")
+
+type gerritFileInfo struct {
+	Status        string `json:"status"`
+	OldPath       string `json:"old_path"`
+	LinesInserted int    `json:"lines_inserted"`
+	LinesDeleted  int    `json:"lines_deleted"`
+}
+
+type gerritComment struct {
+	ID         string        `json:"id"`
+	Path       string        `json:"path"`
+	Line       int           `json:"line"`
+	InReplyTo  string        `json:"in_reply_to"`
+	Message    string        `json:"message"`
+	Author     gerritAccount `json:"author"`
+	Unresolved bool          `json:"unresolved"`
+}
+
+type gerritMessage struct {
+	ID      string        `json:"id"`
+	Author  gerritAccount `json:"author"`
+	Message string        `json:"message"`
+	Tag     string        `json:"tag"`
+}
+
+// commentKey maps a Gerrit comment or message ID, which is an opaque string,
+// to the integer IDs the ReviewProvider interface uses. The key is a 31-bit
+// FNV-1a hash, so it is stable across calls and fits an int everywhere.
+func commentKey(id string) int64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return int64(h.Sum32() & 0x7fffffff)
+}
+
+// --- ReviewProvider ---
+
+// ListOpenPullRequests lists the open changes of the project. Gerrit changes
+// have no source branch; SourceBranch carries the change topic, which
+// CreatePullRequest sets to the pushed branch name.
+func (p *Provider) ListOpenPullRequests(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.PullRequestDetail, error) {
+	query := fmt.Sprintf("project:%s status:open", projectName(repo))
+
+	changes, err := p.queryChanges(ctx, query, "DETAILED_ACCOUNTS")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open changes: %w", err)
+	}
+
+	allPRs := make([]globalEntities.PullRequestDetail, 0, len(changes))
+	for _, change := range changes {
+		allPRs = append(allPRs, globalEntities.PullRequestDetail{
+			ID:           change.Number,
+			Title:        change.Subject,
+			URL:          p.changeURL(change),
+			Status:       mapChangeStatus(change.Status),
+			SourceBranch: change.Topic,
+			TargetBranch: change.Branch,
+			Author:       change.Owner.login(),
+			IsDraft:      change.WorkInProgress,
+		})
+	}
+
+	return allPRs, nil
+}
+
+// GetPullRequestDiff returns the patch of the current patch set. Gerrit serves
+// it base64-encoded, in `git format-patch` form.
+func (p *Provider) GetPullRequestDiff(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	endpoint := fmt.Sprintf("%s/revisions/%s/patch", changeEndpoint(repo, prID), currentRevision)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get change patch: %w", err)
+	}
+
+	patch, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(resp)))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode change patch: %w", err)
+	}
+
+	return string(patch), nil
+}
+
+// GetPullRequestFiles lists the files changed by the current patch set, with
+// the line counts Gerrit reports and the per-file slice of the patch. The
+// magic "/COMMIT_MSG" and "/MERGE_LIST" entries are left out.
+func (p *Provider) GetPullRequestFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestFile, error) {
+	endpoint := fmt.Sprintf("%s/revisions/%s/files", changeEndpoint(repo, prID), currentRevision)
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list change files: %w", err)
+	}
+
+	var files map[string]gerritFileInfo
+	if unmarshalErr := json.Unmarshal(resp, &files); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse change files response: %w", unmarshalErr)
+	}
+
+	diff, err := p.GetPullRequestDiff(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	patches := globalHelpers.SplitDiffByFile(diff)
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		if !strings.HasPrefix(path, "/") {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	result := make([]globalEntities.PullRequestFile, 0, len(paths))
+	for _, path := range paths {
+		f := files[path]
+		result = append(result, globalEntities.PullRequestFile{
+			Path:      path,
+			OldPath:   f.OldPath,
+			Status:    mapFileStatus(f.Status),
+			Additions: f.LinesInserted,
+			Deletions: f.LinesDeleted,
+			Patch:     patches[path],
+		})
+	}
+
+	return result, nil
+}
+
+// mapFileStatus translates a Gerrit file status letter to the vocabulary used
+// by PullRequestFile. Gerrit omits the status for modified files.
+func mapFileStatus(status string) string {
+	switch status {
+	case "A", "C":
+		return "added"
+	case "D":
+		return "deleted"
+	case "R":
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+// ListPullRequestComments returns the human change messages, as PR-wide
+// comments with ThreadID zero, followed by the published comments of every
+// patch set. Comment IDs are mapped through commentKey; ThreadID is the key of
+// the root of the `in_reply_to` chain and InReplyToID the key of the direct
+// parent. Patch-set-level comments have no FilePath. Messages Gerrit generates
+// itself (tagged "autogenerated:...") are left out.
+func (p *Provider) ListPullRequestComments(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestComment, error) {
+	messages, err := p.listMessages(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := p.listComments(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]globalEntities.PullRequestComment, 0, len(messages)+len(comments))
+	for _, m := range messages {
+		if strings.HasPrefix(m.Tag, "autogenerated:") {
+			continue
+		}
+		out = append(out, globalEntities.PullRequestComment{
+			ID:     commentKey(m.ID),
+			Body:   m.Message,
+			Author: m.Author.login(),
+		})
+	}
+
+	byID := make(map[string]gerritComment, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = c
+	}
+
+	for _, c := range comments {
+		comment := globalEntities.PullRequestComment{
+			ID:       commentKey(c.ID),
+			ThreadID: commentKey(threadRoot(c, byID).ID),
+			Body:     c.Message,
+			Author:   c.Author.login(),
+			Line:     c.Line,
+		}
+		if c.Path != patchsetLevelPath {
+			comment.FilePath = c.Path
+		}
+		if c.InReplyTo != "" {
+			comment.InReplyToID = commentKey(c.InReplyTo)
+		}
+		out = append(out, comment)
+	}
+
+	return out, nil
+}
+
+// threadRoot follows the `in_reply_to` chain of a comment up to its root.
+func threadRoot(c gerritComment, byID map[string]gerritComment) gerritComment {
+	seen := map[string]bool{c.ID: true}
+	for c.InReplyTo != "" {
+		parent, ok := byID[c.InReplyTo]
+		if !ok || seen[parent.ID] {
+			break
+		}
+		seen[parent.ID] = true
+		c = parent
+	}
+	return c
+}
+
+func (p *Provider) listMessages(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]gerritMessage, error) {
+	resp, err := p.doRequest(ctx, http.MethodGet, changeEndpoint(repo, prID)+"/messages", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list change messages: %w", err)
+	}
+
+	var messages []gerritMessage
+	if unmarshalErr := json.Unmarshal(resp, &messages); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse change messages response: %w", unmarshalErr)
+	}
+
+	return messages, nil
+}
+
+// listComments returns the published comments of every patch set, sorted by
+// path and line. Gerrit groups them by path and omits the path from each
+// comment, so it is filled in here.
+func (p *Provider) listComments(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]gerritComment, error) {
+	resp, err := p.doRequest(ctx, http.MethodGet, changeEndpoint(repo, prID)+"/comments", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list change comments: %w", err)
+	}
+
+	var byPath map[string][]gerritComment
+	if unmarshalErr := json.Unmarshal(resp, &byPath); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse change comments response: %w", unmarshalErr)
+	}
+
+	paths := make([]string, 0, len(byPath))
+	for path := range byPath {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var all []gerritComment
+	for _, path := range paths {
+		for _, c := range byPath[path] {
+			c.Path = path
+			all = append(all, c)
+		}
+	}
+
+	return all, nil
+}
+
+// findComment returns the comment whose commentKey is key.
+func (p *Provider) findComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	key int,
+) (*gerritComment, error) {
+	comments, err := p.listComments(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range comments {
+		if commentKey(c.ID) == int64(key) {
+			return &c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: comment %d on change %d", errCommentNotFound, key, prID)
+}
+
+// PostPullRequestComment posts a PR-wide message on the current patch set
+// through a review without votes. Gerrit messages have no resolved state, so
+// any entities.WithThreadStatus value is silently ignored.
+func (p *Provider) PostPullRequestComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	body string,
+	_ ...globalEntities.CommentOption,
+) error {
+	if err := p.postReview(ctx, repo, prID, map[string]any{"message": body}); err != nil {
+		return fmt.Errorf("failed to post change message: %w", err)
+	}
+
+	return nil
+}
+
+// PostPullRequestThreadComment saves a draft comment on a line of the current
+// patch set and publishes it. The draft is unresolved unless
+// entities.WithThreadStatus passes a resolving status (see
+// UpdatePullRequestThreadStatus). The returned ID is the comment's key.
+func (p *Provider) PostPullRequestThreadComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	filePath string,
+	line int,
+	body string,
+	opts ...globalEntities.CommentOption,
+) (int, error) {
+	status := globalEntities.ResolveCommentOptions(opts...)
+	resolved, ok := mapThreadStatus(status)
+	if !ok {
+		return 0, fmt.Errorf("unsupported thread status %q", status)
+	}
+
+	draft := map[string]any{
+		"path":       filePath,
+		"line":       line,
+		"message":    body,
+		"unresolved": !resolved,
+	}
+
+	id, err := p.publishDraft(ctx, repo, prID, draft)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post change thread comment: %w", err)
+	}
+
+	return id, nil
+}
+
+// ReplyToThread publishes a reply to the comment whose key is threadID. The
+// reply keeps the parent's resolved state.
+func (p *Provider) ReplyToThread(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	body string,
+) (int, error) {
+	parent, err := p.findComment(ctx, repo, prID, threadID)
+	if err != nil {
+		return 0, err
+	}
+
+	draft := map[string]any{
+		"path":        parent.Path,
+		"in_reply_to": parent.ID,
+		"message":     body,
+	}
+	if parent.Line > 0 {
+		draft["line"] = parent.Line
+	}
+
+	id, err := p.publishDraft(ctx, repo, prID, draft)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reply to change thread: %w", err)
+	}
+
+	return id, nil
+}
+
+// UpdatePullRequestThreadStatus resolves or reopens the thread rooted at the
+// comment whose key is threadID. A Gerrit thread's state is the `unresolved`
+// flag of its latest comment, so a short reply ("Done" or "Reopened") carrying
+// the new state is published. "active" and "pending" reopen the thread, while
+// "fixed", "closed", "wontFix", "byDesign", and "resolved" resolve it.
+func (p *Provider) UpdatePullRequestThreadStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	status string,
+) error {
+	resolved, ok := mapThreadStatus(status)
+	if !ok {
+		return fmt.Errorf("unsupported thread status %q", status)
+	}
+
+	root, err := p.findComment(ctx, repo, prID, threadID)
+	if err != nil {
+		return err
+	}
+
+	message := "Reopened"
+	if resolved {
+		message = "Done"
+	}
+
+	draft := map[string]any{
+		"path":        root.Path,
+		"in_reply_to": root.ID,
+		"message":     message,
+		"unresolved":  !resolved,
+	}
+	if root.Line > 0 {
+		draft["line"] = root.Line
+	}
+
+	if _, publishErr := p.publishDraft(ctx, repo, prID, draft); publishErr != nil {
+		return fmt.Errorf("failed to update change thread status: %w", publishErr)
+	}
+
+	return nil
+}
+
+func mapThreadStatus(status string) (bool, bool) {
+	switch status {
+	case "active", "pending":
+		return false, true
+	case "fixed", "closed", "wontFix", "byDesign", "resolved":
+		return true, true
+	}
+	return false, false
+}
+
+// publishDraft saves a draft comment on the current patch set, then publishes
+// the drafts of that patch set through a review, and returns the comment key.
+// Publishing also sends any other draft the authenticated user has pending on
+// the patch set, as the Gerrit UI does.
+func (p *Provider) publishDraft(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	draft map[string]any,
+) (int, error) {
+	endpoint := fmt.Sprintf("%s/revisions/%s/drafts", changeEndpoint(repo, prID), currentRevision)
+
+	resp, err := p.doRequest(ctx, http.MethodPut, endpoint, draft)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	var saved gerritComment
+	if unmarshalErr := json.Unmarshal(resp, &saved); unmarshalErr != nil {
+		return 0, fmt.Errorf("failed to parse draft response: %w", unmarshalErr)
+	}
+
+	if publishErr := p.postReview(ctx, repo, prID, map[string]any{"drafts": "PUBLISH"}); publishErr != nil {
+		return 0, fmt.Errorf("failed to publish draft: %w", publishErr)
+	}
+
+	return int(commentKey(saved.ID)), nil
+}
+
+// postReview posts a review on the current patch set.
+func (p *Provider) postReview(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	review map[string]any,
+) error {
+	endpoint := fmt.Sprintf("%s/revisions/%s/review", changeEndpoint(repo, prID), currentRevision)
+	_, err := p.doRequest(ctx, http.MethodPost, endpoint, review)
+	return err
+}
+
+func (p *Provider) getChange(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	options ...string,
+) (*gerritChange, error) {
+	endpoint := changeEndpoint(repo, prID)
+	for i, option := range options {
+		separator := "&"
+		if i == 0 {
+			separator = "?"
+		}
+		endpoint += separator + "o=" + url.QueryEscape(option)
+	}
+
+	resp, err := p.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change: %w", err)
+	}
+
+	var change gerritChange
+	if unmarshalErr := json.Unmarshal(resp, &change); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse change response: %w", unmarshalErr)
+	}
+
+	return &change, nil
+}
+
+// GetPullRequestStatus returns "open", "merged", or "closed" for an abandoned
+// change.
+func (p *Provider) GetPullRequestStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	change, err := p.getChange(ctx, repo, prID)
+	if err != nil {
+		return "", err
+	}
+
+	return mapChangeStatus(change.Status), nil
+}
+
+// GetPullRequestCheckStatus reports the Verified label, which is where CI
+// systems vote on Gerrit: a rejecting vote fails, an approving vote passes, and
+// no vote yet counts as pending (not passing). A project without a Verified
+// label has no CI gate and is treated as passing.
+func (p *Provider) GetPullRequestCheckStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (bool, error) {
+	change, err := p.getChange(ctx, repo, prID, "LABELS")
+	if err != nil {
+		return false, err
+	}
+
+	verified, ok := change.Labels[labelVerified]
+	if !ok {
+		return true, nil
+	}
+
+	return verified.Rejected == nil && verified.Approved != nil, nil
+}
+
+// GetPullRequestChecks reports the Verified label as the change's only check,
+// required since Gerrit projects that define it block submission on it.
+func (p *Provider) GetPullRequestChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*globalEntities.PullRequestChecks, error) {
+	change, err := p.getChange(ctx, repo, prID, "LABELS")
+	if err != nil {
+		return nil, err
+	}
+
+	verified, ok := change.Labels[labelVerified]
+	if !ok {
+		return globalEntities.NewPullRequestChecks(nil), nil
+	}
+
+	check := globalEntities.Check{
+		Name:     labelVerified,
+		Kind:     globalEntities.CheckKindPolicy,
+		State:    globalEntities.CheckStatePending,
+		Required: true,
+		URL:      p.changeURL(*change),
+	}
+	switch {
+	case verified.Rejected != nil:
+		check.State = globalEntities.CheckStateFailure
+	case verified.Approved != nil:
+		check.State = globalEntities.CheckStateSuccess
+	}
+	return globalEntities.NewPullRequestChecks([]globalEntities.Check{check}), nil
+}
+
+// MergePullRequest submits the change. Gerrit applies the submit type
+// configured on the project, so strategy is ignored, as is WithBypassPolicy:
+// submit requirements are enforced for every user. WithDeleteSourceBranch
+// deletes the branch named by the change topic (the branch CreatePullRequest
+// pushed from) as best-effort cleanup.
+func (p *Provider) MergePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	_ string,
+	opts ...globalEntities.MergeOption,
+) error {
+	resolved := globalEntities.ResolveMergeOptions(opts...)
+
+	resp, err := p.doRequest(ctx, http.MethodPost, changeEndpoint(repo, prID)+"/submit", map[string]any{})
+	if err != nil {
+		return fmt.Errorf("failed to submit change: %w", err)
+	}
+
+	var change gerritChange
+	if unmarshalErr := json.Unmarshal(resp, &change); unmarshalErr != nil {
+		return fmt.Errorf("failed to parse submit response: %w", unmarshalErr)
+	}
+
+	if resolved.DeleteSourceBranch && change.Topic != "" {
+		p.deleteBranch(ctx, repo, change.Topic)
+	}
+
+	return nil
+}
+
+// deleteBranch deletes a branch after a submit. Failures are logged and never
+// returned, so they cannot fail the submit that already happened.
+func (p *Provider) deleteBranch(ctx context.Context, repo globalEntities.Repository, branch string) {
+	endpoint := fmt.Sprintf(
+		"/projects/%s/branches/%s",
+		url.PathEscape(projectName(repo)), url.PathEscape(branch),
+	)
+
+	if _, err := p.doRequest(ctx, http.MethodDelete, endpoint, nil); err != nil {
+		log.WithFields(log.Fields{
+			"project": projectName(repo),
+			"branch":  branch,
+		}).Warnf("failed to delete source branch after submit: %v", err)
+	}
+}
+
+// SubmitPullRequestReview posts a review on the current patch set with a
+// Code-Review vote mapped from the verdict: ReviewVerdictApprove votes the
+// highest score the authenticated user may give (+2 for maintainers, +1
+// otherwise), ReviewVerdictRequestChanges and ReviewVerdictWaitingForAuthor
+// vote -1, and ReviewVerdictComment posts the body without a vote. A comment
+// review with an empty body is skipped without an API call.
+func (p *Provider) SubmitPullRequestReview(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	sub globalEntities.ReviewSubmission,
+) error {
+	review := map[string]any{}
+	if sub.Body != "" {
+		review["message"] = sub.Body
+	}
+
+	switch sub.Verdict {
+	case globalEntities.ReviewVerdictApprove:
+		change, err := p.getChange(ctx, repo, prID, "DETAILED_LABELS")
+		if err != nil {
+			return err
+		}
+		score, ok := highestScore(change.PermittedLabels[labelCodeReview])
+		if !ok {
+			return fmt.Errorf("approving change %d is not permitted: no positive %s vote allowed", prID, labelCodeReview)
+		}
+		review["labels"] = map[string]int{labelCodeReview: score}
+	case globalEntities.ReviewVerdictRequestChanges,
+		globalEntities.ReviewVerdictWaitingForAuthor:
+		review["labels"] = map[string]int{labelCodeReview: codeReviewNeedsWork}
+	case globalEntities.ReviewVerdictComment:
+		if sub.Body == "" {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported review verdict %q", sub.Verdict)
+	}
+
+	if err := p.postReview(ctx, repo, prID, review); err != nil {
+		return fmt.Errorf("failed to submit change review: %w", err)
+	}
+
+	return nil
+}
+
+// highestScore returns the highest positive score of a permitted label value
+// list such as ["-2", "-1", " 0", "+1", "+2"].
+func highestScore(values []string) (int, bool) {
+	best := 0
+	for _, value := range values {
+		score, err := strconv.Atoi(strings.TrimSpace(value))
+		if err == nil && score > best {
+			best = score
+		}
+	}
+	return best, best > 0
+}
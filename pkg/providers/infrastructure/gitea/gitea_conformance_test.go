@@ -0,0 +1,33 @@
+package gitea_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/gitea"
+	"github.com/rios0rios0/gitforge/test/conformance"
+	"github.com/rios0rios0/gitforge/test/fakes"
+)
+
+func TestProviderConformance(t *testing.T) {
+	t.Parallel()
+
+	conformance.Run(t, conformance.Harness{
+		NewBackend: func(t *testing.T) conformance.Backend {
+			return fakes.NewForgejoServer(t)
+		},
+		NewProvider: func(t *testing.T, backend conformance.Backend) globalEntities.ForgeProvider {
+			provider, err := gitea.NewProvider("token", backend.(*fakes.ForgejoServer).URL())
+			require.NoError(t, err)
+			return provider
+		},
+		Skip: map[string]string{
+			"FileAccessProvider/should create a branch with added and edited files": "CreateBranchWithChanges " +
+				"creates every non-delete change with POST, which Forgejo rejects for existing files",
+			"FileAccessProvider/should delete files on a new branch": "CreateBranchWithChanges " +
+				"deletes files without the blob SHA Forgejo requires",
+		},
+	})
+}
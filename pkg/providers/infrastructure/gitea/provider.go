@@ -0,0 +1,33 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/codeberg"
+)
+
+const providerName = "gitea"
+
+// NewProvider creates a provider for the self-hosted Gitea or Forgejo instance rooted at
+// baseURL (e.g. "https://git.corp.example"). The API is reached under "{baseURL}/api/v1".
+//
+// The provider reuses the Codeberg implementation, so it satisfies ForgeProvider,
+// FileAccessProvider, ReviewProvider, ChecksProvider, LocalGitAuthProvider, MirrorProvider,
+// MirrorLifecycleProvider, ReleaseProvider, TagProvider, IssueProvider, IssueLinkProvider,
+// WebhookProvider, and PipelineProvider, but it reports the "gitea" name and the GITEA service type and only
+// matches URLs on its own host.
+func NewProvider(token, baseURL string) (globalEntities.ForgeProvider, error) {
+	return NewProviderWithClient(token, baseURL, nil)
+}
+
+// NewProviderWithClient creates a new Gitea/Forgejo provider with an explicit HTTP client.
+// If client is nil, a default client with the standard timeout is used.
+func NewProviderWithClient(token, baseURL string, client *http.Client) (globalEntities.ForgeProvider, error) {
+	provider, err := codeberg.NewInstanceProvider(providerName, globalEntities.GITEA, token, baseURL, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Gitea provider: %w", err)
+	}
+	return provider, nil
+}
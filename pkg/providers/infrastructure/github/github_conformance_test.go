@@ -0,0 +1,35 @@
+package github_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/github"
+	"github.com/rios0rios0/gitforge/test/conformance"
+	"github.com/rios0rios0/gitforge/test/fakes"
+)
+
+func TestProviderConformance(t *testing.T) {
+	t.Parallel()
+
+	conformance.Run(t, conformance.Harness{
+		NewBackend: func(t *testing.T) conformance.Backend {
+			return fakes.NewGitHubServer(t)
+		},
+		NewProvider: func(t *testing.T, backend conformance.Backend) globalEntities.ForgeProvider {
+			provider, err := github.NewEnterpriseProvider("token", backend.(*fakes.GitHubServer).URL(), "")
+			require.NoError(t, err)
+			return provider
+		},
+		Skip: map[string]string{
+			"FileAccessProvider/should reject unsupported change types": "CreateBranchWithChanges " +
+				"does not validate change types",
+			"MirrorProvider/should migrate a repository that is then discoverable": "MigrateRepository " +
+				"pushes over git smart HTTP, which the fake GitHub server does not serve",
+			"MirrorProvider/should create a pull mirror that is then discoverable": "MigrateRepository " +
+				"pushes over git smart HTTP, which the fake GitHub server does not serve",
+		},
+	})
+}
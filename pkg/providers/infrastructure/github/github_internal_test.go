@@ -3,10 +3,12 @@ package github
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	gh "github.com/google/go-github/v66/github"
 	"github.com/stretchr/testify/assert"
@@ -529,6 +531,65 @@ func TestCreateBranchWithChangesInternal(t *testing.T) {
 		// then
 		require.NoError(t, err)
 	})
+
+	t.Run("should send deletions as tree entries with a nil SHA", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var treeBody struct {
+			Tree []map[string]any `json:"tree"`
+		}
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/git/ref/heads/main", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"abc123","type":"commit"}}`))
+		})
+		mux.HandleFunc("GET /repos/my-org/my-repo/git/commits/abc123", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"sha":"abc123","tree":{"sha":"tree123"}}`))
+		})
+		mux.HandleFunc("POST /repos/my-org/my-repo/git/trees", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&treeBody)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"sha":"newtree123"}`))
+		})
+		mux.HandleFunc("POST /repos/my-org/my-repo/git/commits", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"sha":"commit123"}`))
+		})
+		mux.HandleFunc("POST /repos/my-org/my-repo/git/refs", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/feature","object":{"sha":"commit123"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+		input := globalEntities.BranchInput{
+			BranchName:    "feature",
+			BaseBranch:    "refs/heads/main",
+			CommitMessage: "Remove old file",
+			Changes: []globalEntities.FileChange{
+				{Path: "/old.txt", ChangeType: "delete"},
+				{Path: "/new.txt", Content: "new", ChangeType: "add"},
+			},
+		}
+
+		// when
+		err := p.CreateBranchWithChanges(context.Background(), repo, input)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, treeBody.Tree, 2)
+		deleted := treeBody.Tree[0]
+		assert.Equal(t, "old.txt", deleted["path"])
+		assert.Contains(t, deleted, "sha")
+		assert.Nil(t, deleted["sha"])
+		assert.NotContains(t, deleted, "content")
+		assert.Equal(t, "new", treeBody.Tree[1]["content"])
+	})
 }
 
 func TestPostPullRequestThreadCommentReturnsID(t *testing.T) {
@@ -577,6 +638,7 @@ func TestUpdatePullRequestThreadStatus(t *testing.T) {
 		// then
 		require.Error(t, err)
 		assert.ErrorIs(t, err, ErrThreadStatusUpdateUnsupported)
+		assert.ErrorIs(t, err, errors.ErrUnsupported)
 	})
 }
 
@@ -721,6 +783,104 @@ func TestGetPullRequestStatus(t *testing.T) {
 		require.Error(t, err)
 		assert.Empty(t, status)
 	})
+
+	t.Run("should return a rate limit error with the Retry-After delay on a secondary rate limit", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"You have exceeded a secondary rate limit",` +
+				`"documentation_url":"https://docs.github.com/rest/overview/` +
+				`rate-limits-for-the-rest-api#about-secondary-rate-limits"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.GetPullRequestStatus(context.Background(), repo, 7)
+
+		// then
+		var rateLimitErr *globalEntities.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.Equal(t, time.Minute, rateLimitErr.RetryAfter)
+	})
+}
+
+func TestGetPullRequestChecks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report statuses and check runs with the checks the base branch requires", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"number":7,"head":{"sha":"abc123"},"base":{"ref":"main"}}`))
+		})
+		mux.HandleFunc("GET /repos/my-org/my-repo/branches/main", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"name":"main","protected":true,
+				"protection":{"required_status_checks":{"checks":[{"context":"build"}]}}}`))
+		})
+		mux.HandleFunc("GET /repos/my-org/my-repo/commits/abc123/status", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"state":"failure","total_count":1,"statuses":[
+				{"context":"coverage","state":"error","target_url":"https://ci.example.com/1",
+				"created_at":"2026-01-02T10:00:00Z","updated_at":"2026-01-02T10:01:00Z"}]}`))
+		})
+		mux.HandleFunc("GET /repos/my-org/my-repo/commits/abc123/check-runs",
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "latest", r.URL.Query().Get("filter"))
+				_, _ = w.Write([]byte(`{"total_count":2,"check_runs":[
+					{"name":"build","status":"completed","conclusion":"success",
+					"html_url":"https://github.com/my-org/my-repo/runs/1",
+					"started_at":"2026-01-02T10:00:00Z","completed_at":"2026-01-02T10:04:00Z"},
+					{"name":"lint","status":"in_progress","details_url":"https://lint.example.com/2"}]}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		checks, err := p.GetPullRequestChecks(context.Background(), repo, 7)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &globalEntities.PullRequestChecks{
+			Checks: []globalEntities.Check{
+				{
+					Name:        "coverage",
+					Kind:        globalEntities.CheckKindStatus,
+					State:       globalEntities.CheckStateFailure,
+					URL:         "https://ci.example.com/1",
+					StartedAt:   time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+					CompletedAt: time.Date(2026, 1, 2, 10, 1, 0, 0, time.UTC),
+				},
+				{
+					Name:        "build",
+					Kind:        globalEntities.CheckKindCheckRun,
+					State:       globalEntities.CheckStateSuccess,
+					Required:    true,
+					URL:         "https://github.com/my-org/my-repo/runs/1",
+					StartedAt:   time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+					CompletedAt: time.Date(2026, 1, 2, 10, 4, 0, 0, time.UTC),
+				},
+				{
+					Name:  "lint",
+					Kind:  globalEntities.CheckKindCheckRun,
+					State: globalEntities.CheckStatePending,
+					URL:   "https://lint.example.com/2",
+				},
+			},
+			Verdict: globalEntities.CheckStateSuccess,
+		}, checks)
+	})
 }
 
 // TestThreadStatusOptionIgnoredByGitHub pins the contract that
@@ -1483,3 +1643,35 @@ func TestReplyToThread(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestEnterpriseProviderInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send API calls to the enterprise API base URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var requestedPath string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v3/orgs/my-org/repos", func(w http.ResponseWriter, r *http.Request) {
+			requestedPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"id":1,"name":"repo-a","default_branch":"main",` +
+				`"clone_url":"https://github.corp.example/my-org/repo-a.git"}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		provider, err := NewEnterpriseProvider("test-token", "https://github.corp.example", server.URL)
+		require.NoError(t, err)
+
+		// when
+		repos, err := provider.DiscoverRepositories(context.Background(), "my-org")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, repos, 1)
+		assert.Equal(t, "/api/v3/orgs/my-org/repos", requestedPath)
+		assert.Equal(t, "https://github.corp.example/my-org/repo-a.git", repos[0].RemoteURL)
+	})
+}
@@ -3,7 +3,6 @@ package github
 import (
 	"fmt"
 	"net/url"
-
 	"strings"
 
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -23,16 +22,22 @@ const (
 	// defaultBranchName is the fallback branch name when GitHub does not report one.
 	defaultBranchName = "main"
 
+	// defaultWebBaseURL is the web root used when no GitHub Enterprise Server URL is configured.
+	defaultWebBaseURL = "https://github.com"
+
 	// Pull request state values reported by the GitHub API.
 	prStateOpen   = "open"
 	prStateClosed = "closed"
 	prStateMerged = "merged"
 )
 
-// Provider implements ForgeProvider, FileAccessProvider, and LocalGitAuthProvider for GitHub.
+// Provider implements ForgeProvider, FileAccessProvider, ReviewProvider, ChecksProvider, LocalGitAuthProvider,
+// MirrorProvider, ReleaseProvider, TagProvider, IssueProvider, IssueLinkProvider, WebhookProvider, and
+// PipelineProvider for GitHub.
 type Provider struct {
-	token  string
-	client *gh.Client
+	token      string
+	webBaseURL string // empty means github.com
+	client     *gh.Client
 }
 
 // NewProvider creates a new GitHub provider with the given token.
@@ -44,11 +49,55 @@ func NewProvider(token string) globalEntities.ForgeProvider {
 	}
 }
 
+// NewEnterpriseProvider creates a GitHub provider for a GitHub Enterprise Server
+// instance. webBaseURL is the instance root (e.g. "https://github.corp.example")
+// used for URL matching and clone URLs; apiBaseURL is the REST API root and
+// defaults to "{webBaseURL}/api/v3/" when empty.
+func NewEnterpriseProvider(token, webBaseURL, apiBaseURL string) (globalEntities.ForgeProvider, error) {
+	webBaseURL = strings.TrimSuffix(webBaseURL, "/")
+	parsed, err := url.Parse(webBaseURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid GitHub Enterprise web URL %q", webBaseURL)
+	}
+
+	if apiBaseURL == "" {
+		apiBaseURL = webBaseURL + "/api/v3/"
+	}
+
+	client, err := gh.NewClient(nil).WithAuthToken(token).WithEnterpriseURLs(apiBaseURL, webBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure GitHub Enterprise client: %w", err)
+	}
+
+	return &Provider{
+		token:      token,
+		webBaseURL: webBaseURL,
+		client:     client,
+	}, nil
+}
+
 func (p *Provider) Name() string      { return providerName }
 func (p *Provider) AuthToken() string { return p.token }
 
 func (p *Provider) MatchesURL(rawURL string) bool {
-	return strings.Contains(rawURL, "github.com")
+	return strings.Contains(rawURL, p.hostname())
+}
+
+// baseURL returns the web root of the GitHub instance.
+func (p *Provider) baseURL() string {
+	if p.webBaseURL == "" {
+		return defaultWebBaseURL
+	}
+	return p.webBaseURL
+}
+
+// hostname returns the host name of the GitHub instance, without any port.
+func (p *Provider) hostname() string {
+	parsed, err := url.Parse(p.baseURL())
+	if err != nil {
+		return "github.com"
+	}
+	return parsed.Hostname()
 }
 
 // --- LocalGitAuthProvider ---
@@ -83,8 +132,8 @@ func (p *Provider) CloneURL(repo globalEntities.Repository) string {
 	remoteURL := repo.RemoteURL
 	if remoteURL == "" {
 		remoteURL = fmt.Sprintf(
-			"https://github.com/%s/%s.git",
-			repo.Organization, repo.Name,
+			"%s/%s/%s.git",
+			p.baseURL(), repo.Organization, repo.Name,
 		)
 	}
 
@@ -99,9 +148,9 @@ func (p *Provider) CloneURL(repo globalEntities.Repository) string {
 }
 
 func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
-	host := "github.com"
+	host := p.hostname()
 	if sshAlias != "" {
-		host = fmt.Sprintf("github.com-%s", sshAlias)
+		host = fmt.Sprintf("%s-%s", host, sshAlias)
 	}
 	return fmt.Sprintf("git@%s:%s/%s.git", host, repo.Organization, repo.Name)
 }
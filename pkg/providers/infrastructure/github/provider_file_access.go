@@ -127,16 +127,21 @@ func (p *Provider) CreateBranchWithChanges(
 
 	var treeEntries []*gh.TreeEntry
 	for _, change := range input.Changes {
-		content := change.Content
 		path := strings.TrimPrefix(change.Path, "/")
 		mode := blobMode
 		entryType := blobType
-		treeEntries = append(treeEntries, &gh.TreeEntry{
-			Path:    &path,
-			Mode:    &mode,
-			Type:    &entryType,
-			Content: &content,
-		})
+		entry := &gh.TreeEntry{
+			Path: &path,
+			Mode: &mode,
+			Type: &entryType,
+		}
+		// A tree entry with neither content nor a SHA is sent as "sha": null,
+		// which removes the path from the base tree.
+		if change.ChangeType != "delete" {
+			content := change.Content
+			entry.Content = &content
+		}
+		treeEntries = append(treeEntries, entry)
 	}
 
 	newTree, _, err := p.client.Git.CreateTree(
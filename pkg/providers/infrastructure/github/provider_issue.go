@@ -0,0 +1,209 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	gh "github.com/google/go-github/v66/github"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var errNotAnIssue = errors.New("not an issue")
+
+func (p *Provider) CreateIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.IssueInput,
+) (*globalEntities.Issue, error) {
+	request := &gh.IssueRequest{
+		Title: gh.String(input.Title),
+		Body:  gh.String(input.Body),
+	}
+	if len(input.Labels) > 0 {
+		request.Labels = &input.Labels
+	}
+	if len(input.Assignees) > 0 {
+		request.Assignees = &input.Assignees
+	}
+
+	issue, _, err := p.client.Issues.Create(ctx, repo.Organization, repo.Name, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return githubIssueToDomain(issue), nil
+}
+
+// GetIssue returns the issue with the given number. GitHub numbers pull
+// requests and issues together; a pull request number is an error.
+func (p *Provider) GetIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+) (*globalEntities.Issue, error) {
+	issue, _, err := p.client.Issues.Get(ctx, repo.Organization, repo.Name, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue #%d: %w", id, err)
+	}
+	if issue.IsPullRequest() {
+		return nil, fmt.Errorf("%w: #%d is a pull request", errNotAnIssue, id)
+	}
+	return githubIssueToDomain(issue), nil
+}
+
+func (p *Provider) UpdateIssueState(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	state globalEntities.IssueState,
+) error {
+	request := &gh.IssueRequest{State: gh.String(string(state))}
+	if _, _, err := p.client.Issues.Edit(ctx, repo.Organization, repo.Name, id, request); err != nil {
+		return fmt.Errorf("failed to set issue #%d %s: %w", id, state, err)
+	}
+	return nil
+}
+
+func (p *Provider) AddIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	if _, _, err := p.client.Issues.AddLabelsToIssue(ctx, repo.Organization, repo.Name, id, labels); err != nil {
+		return fmt.Errorf("failed to label issue #%d: %w", id, err)
+	}
+	return nil
+}
+
+func (p *Provider) RemoveIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	for _, label := range labels {
+		_, err := p.client.Issues.RemoveLabelForIssue(ctx, repo.Organization, repo.Name, id, label)
+		var ghErr *gh.ErrorResponse
+		if errors.As(err, &ghErr) && ghErr.Response.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to remove label %q from issue #%d: %w", label, id, err)
+		}
+	}
+	return nil
+}
+
+func (p *Provider) AssignIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	assignees []string,
+) error {
+	if assignees == nil {
+		assignees = []string{}
+	}
+	request := &gh.IssueRequest{Assignees: &assignees}
+	if _, _, err := p.client.Issues.Edit(ctx, repo.Organization, repo.Name, id, request); err != nil {
+		return fmt.Errorf("failed to assign issue #%d: %w", id, err)
+	}
+	return nil
+}
+
+func (p *Provider) CommentOnIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	body string,
+) (*globalEntities.IssueComment, error) {
+	comment, _, err := p.client.Issues.CreateComment(
+		ctx, repo.Organization, repo.Name, id, &gh.IssueComment{Body: gh.String(body)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to comment on issue #%d: %w", id, err)
+	}
+	return &globalEntities.IssueComment{
+		ID:        comment.GetID(),
+		Body:      comment.GetBody(),
+		Author:    comment.GetUser().GetLogin(),
+		CreatedAt: comment.GetCreatedAt().Time,
+	}, nil
+}
+
+// SearchIssues runs the query through the search API, which caps results at
+// 1000 and is rate limited separately from the rest of the REST API.
+func (p *Provider) SearchIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.IssueQuery,
+) ([]globalEntities.Issue, error) {
+	var allIssues []globalEntities.Issue
+	opts := &gh.SearchOptions{
+		Sort:        "updated",
+		Order:       "desc",
+		ListOptions: gh.ListOptions{PerPage: perPage},
+	}
+
+	for {
+		result, resp, err := p.client.Search.Issues(ctx, githubIssueSearchQuery(repo, query), opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		for _, issue := range result.Issues {
+			allIssues = append(allIssues, *githubIssueToDomain(issue))
+			if query.Limit > 0 && len(allIssues) == query.Limit {
+				return allIssues, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allIssues, nil
+}
+
+// githubIssueSearchQuery builds the search syntax for query, restricted to
+// the issues of repo.
+func githubIssueSearchQuery(repo globalEntities.Repository, query globalEntities.IssueQuery) string {
+	terms := []string{fmt.Sprintf("repo:%s/%s", repo.Organization, repo.Name), "is:issue"}
+	if query.State != "" {
+		terms = append(terms, "state:"+string(query.State))
+	}
+	for _, label := range query.Labels {
+		terms = append(terms, fmt.Sprintf("label:%q", label))
+	}
+	if query.Assignee != "" {
+		terms = append(terms, "assignee:"+query.Assignee)
+	}
+	if query.Text != "" {
+		terms = append(terms, query.Text, "in:title,body")
+	}
+	return strings.Join(terms, " ")
+}
+
+func githubIssueToDomain(issue *gh.Issue) *globalEntities.Issue {
+	result := &globalEntities.Issue{
+		ID:        issue.GetNumber(),
+		Title:     issue.GetTitle(),
+		Body:      issue.GetBody(),
+		State:     globalEntities.IssueState(issue.GetState()),
+		Author:    issue.GetUser().GetLogin(),
+		URL:       issue.GetHTMLURL(),
+		CreatedAt: issue.GetCreatedAt().Time,
+		UpdatedAt: issue.GetUpdatedAt().Time,
+	}
+	for _, label := range issue.Labels {
+		result.Labels = append(result.Labels, label.GetName())
+	}
+	for _, assignee := range issue.Assignees {
+		result.Assignees = append(result.Assignees, assignee.GetLogin())
+	}
+	return result
+}
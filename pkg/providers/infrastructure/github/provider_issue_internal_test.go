@@ -0,0 +1,156 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateIssueInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create the issue with its labels and assignees", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repos/my-org/my-repo/issues", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":12,"title":"Autoupdate failed","state":"open",
+				"labels":[{"name":"autoupdate"}],"assignees":[{"login":"dev"}],"user":{"login":"bot"},
+				"html_url":"https://github.com/my-org/my-repo/issues/12"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		issue, err := p.CreateIssue(context.Background(), repo, globalEntities.IssueInput{
+			Title: "Autoupdate failed", Labels: []string{"autoupdate"}, Assignees: []string{"dev"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []any{"autoupdate"}, body["labels"])
+		assert.Equal(t, []any{"dev"}, body["assignees"])
+		assert.Equal(t, &globalEntities.Issue{
+			ID:        12,
+			Title:     "Autoupdate failed",
+			State:     globalEntities.IssueStateOpen,
+			Labels:    []string{"autoupdate"},
+			Assignees: []string{"dev"},
+			Author:    "bot",
+			URL:       "https://github.com/my-org/my-repo/issues/12",
+		}, issue)
+	})
+}
+
+func TestGetIssueInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should reject a pull request number", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/issues/4", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"number":4,"pull_request":{"url":"https://api.github.com/pulls/4"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.GetIssue(context.Background(), repo, 4)
+
+		// then
+		require.ErrorIs(t, err, errNotAnIssue)
+	})
+}
+
+func TestRemoveIssueLabelsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should ignore labels the issue does not carry", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var removed []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("DELETE /repos/my-org/my-repo/issues/12/labels/{label}", func(w http.ResponseWriter, r *http.Request) {
+			removed = append(removed, r.PathValue("label"))
+			if r.PathValue("label") == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Label does not exist"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`[]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.RemoveIssueLabels(context.Background(), repo, 12, []string{"missing", "autoupdate"})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"missing", "autoupdate"}, removed)
+	})
+}
+
+func TestSearchIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should search the repository's issues most recently updated first", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var query, sort string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /search/issues", func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("q")
+			sort = r.URL.Query().Get("sort") + " " + r.URL.Query().Get("order")
+			_, _ = w.Write([]byte(`{"total_count":3,"items":[{"number":3},{"number":2},{"number":1}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		issues, err := p.SearchIssues(context.Background(), repo, globalEntities.IssueQuery{
+			State:    globalEntities.IssueStateOpen,
+			Labels:   []string{"auto update"},
+			Assignee: "dev",
+			Text:     "failed",
+			Limit:    2,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t,
+			`repo:my-org/my-repo is:issue state:open label:"auto update" assignee:dev failed in:title,body`,
+			query,
+		)
+		assert.Equal(t, "updated desc", sort)
+		require.Len(t, issues, 2)
+		assert.Equal(t, 3, issues[0].ID)
+	})
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	gh "github.com/google/go-github/v66/github"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// LinkPullRequestIssues appends closing keywords ("Closes #n") and references
+// ("Refs #n") to the pull request body. GitHub closes the issues when the
+// pull request merges into the default branch.
+func (p *Provider) LinkPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	links []globalEntities.IssueLink,
+) error {
+	pr, _, err := p.client.PullRequests.Get(ctx, repo.Organization, repo.Name, prID)
+	if err != nil {
+		return fmt.Errorf("failed to get pull request #%d: %w", prID, err)
+	}
+
+	body := globalEntities.AppendIssueReferences(pr.GetBody(), links)
+	if body == pr.GetBody() {
+		return nil
+	}
+
+	if _, _, err = p.client.PullRequests.Edit(
+		ctx, repo.Organization, repo.Name, prID, &gh.PullRequest{Body: &body},
+	); err != nil {
+		return fmt.Errorf("failed to link pull request #%d: %w", prID, err)
+	}
+	return nil
+}
+
+// ListPullRequestIssues returns the issues the pull request body references.
+// The REST API does not expose GitHub's own closing references, so links made
+// from the web UI's sidebar are not seen.
+func (p *Provider) ListPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.IssueLink, error) {
+	pr, _, err := p.client.PullRequests.Get(ctx, repo.Organization, repo.Name, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request #%d: %w", prID, err)
+	}
+	return globalEntities.ParseIssueReferences(pr.GetBody()), nil
+}
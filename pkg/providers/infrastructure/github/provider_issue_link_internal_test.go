@@ -0,0 +1,94 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestLinkPullRequestIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should append the keywords the body is missing", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"number":7,"body":"Adds the feature.\n\nFixes #41"}`))
+		})
+		mux.HandleFunc("PATCH /repos/my-org/my-repo/pulls/7", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"number":7}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.LinkPullRequestIssues(context.Background(), repo, 7, []globalEntities.IssueLink{
+			{ID: 41, Closes: true}, {ID: 42, Closes: true}, {ID: 43},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Adds the feature.\n\nFixes #41\n\nCloses #42\nRefs #43", body["body"])
+	})
+
+	t.Run("should not edit the pull request when every issue is referenced", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"number":7,"body":"Resolves #41"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.LinkPullRequestIssues(context.Background(), repo, 7, []globalEntities.IssueLink{{ID: 41}})
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestListPullRequestIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return the issues the body references", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/pulls/7", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"number":7,"body":"Closes #41, refs #42"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		links, err := p.ListPullRequestIssues(context.Background(), repo, 7)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []globalEntities.IssueLink{{ID: 41, Closes: true}, {ID: 42}}, links)
+	})
+}
@@ -0,0 +1,105 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	gh "github.com/google/go-github/v66/github"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	log "github.com/sirupsen/logrus"
+)
+
+// mirrorRemoteName is the remote the mirror push goes to.
+const mirrorRemoteName = "mirror"
+
+// mirrorRefSpecs are the refs a mirror push copies. Pull request refs are left
+// out because GitHub rejects pushes to refs/pull/*.
+var mirrorRefSpecs = []config.RefSpec{ //nolint:gochecknoglobals // read-only lookup table
+	"+refs/heads/*:refs/heads/*",
+	"+refs/tags/*:refs/tags/*",
+}
+
+// MigrateRepository creates the repository on GitHub and then pushes every
+// branch and tag of input.CloneAddr to it. GitHub has no pull mirrors, so with
+// Mirror set the repository is still a one-time copy of the source.
+func (p *Provider) MigrateRepository(
+	ctx context.Context,
+	input globalEntities.MirrorInput,
+) error {
+	if input.Mirror {
+		log.Warnf("GitHub has no pull mirrors, %s/%s will be a one-time copy of its source", input.RepoOwner, input.RepoName)
+	}
+
+	// an empty owner makes the API create the repository for the authenticated user
+	owner := input.RepoOwner
+	authenticatedUser, _, err := p.client.Users.Get(ctx, "")
+	if err == nil && strings.EqualFold(authenticatedUser.GetLogin(), owner) {
+		owner = ""
+	}
+
+	input.Report(globalEntities.MirrorStateQueued, "creating repository")
+	created, _, err := p.client.Repositories.Create(ctx, owner, &gh.Repository{
+		Name:        gh.String(input.RepoName),
+		Description: gh.String(input.Description),
+		Private:     gh.Bool(input.Private),
+	})
+	if err != nil {
+		input.Report(globalEntities.MirrorStateFailed, err.Error())
+		return fmt.Errorf("failed to create repository %q: %w", input.RepoName, err)
+	}
+
+	input.Report(globalEntities.MirrorStateRunning, "pushing branches and tags")
+	if err = p.mirrorPush(ctx, input, created.GetCloneURL()); err != nil {
+		input.Report(globalEntities.MirrorStateFailed, err.Error())
+		return err
+	}
+
+	input.Report(globalEntities.MirrorStateCompleted, "")
+	return nil
+}
+
+// mirrorPush fetches every ref of the source into memory and force-pushes its
+// branches and tags to targetURL.
+func (p *Provider) mirrorPush(ctx context.Context, input globalEntities.MirrorInput, targetURL string) error {
+	var sourceAuth transport.AuthMethod
+	if input.AuthUsername != "" || input.AuthPassword != "" {
+		sourceAuth = &http.BasicAuth{Username: input.AuthUsername, Password: input.AuthPassword}
+	}
+
+	gitRepo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:    input.CloneAddr,
+		Auth:   sourceAuth,
+		Mirror: true,
+	})
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch source %q: %w", input.RepoName, err)
+	}
+
+	if _, err = gitRepo.CreateRemote(&config.RemoteConfig{
+		Name: mirrorRemoteName,
+		URLs: []string{targetURL},
+	}); err != nil {
+		return fmt.Errorf("failed to add mirror remote: %w", err)
+	}
+
+	pushErr := gitRepo.PushContext(ctx, &git.PushOptions{
+		RemoteName: mirrorRemoteName,
+		RefSpecs:   mirrorRefSpecs,
+		Auth:       &http.BasicAuth{Username: "x-access-token", Password: p.token},
+	})
+	if pushErr != nil && !errors.Is(pushErr, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to push mirror of %q: %w", input.RepoName, pushErr)
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// newMirrorSource creates a repository with one commit on "main" tagged v1.0.0.
+func newMirrorSource(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# source\n"), 0o600))
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	_, err = worktree.Add("README.md")
+	require.NoError(t, err)
+	hash, err := worktree.Commit("initial commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.0.0", hash, nil)
+	require.NoError(t, err)
+	return dir
+}
+
+// newMirrorServer answers the user and repository creation endpoints, handing
+// out a bare repository on disk as the clone URL of the created repository.
+func newMirrorServer(t *testing.T, login, createPath, targetDir string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /user", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"login": login})
+	})
+	mux.HandleFunc("POST "+createPath, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name":      body["name"],
+			"private":   body["private"],
+			"clone_url": "file://" + filepath.ToSlash(targetDir),
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMigrateRepositoryInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create the organization repository and push branches and tags to it", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		source := newMirrorSource(t)
+		targetDir := t.TempDir()
+		_, err := git.PlainInit(targetDir, true)
+		require.NoError(t, err)
+		p := newTestProvider(t, newMirrorServer(t, "someone-else", "/orgs/my-org/repos", targetDir))
+		var states []globalEntities.MirrorState
+
+		// when
+		err = p.MigrateRepository(context.Background(), globalEntities.MirrorInput{
+			CloneAddr: source,
+			RepoName:  "copy",
+			RepoOwner: "my-org",
+			Progress: func(progress globalEntities.MirrorProgress) {
+				states = append(states, progress.State)
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		target, err := git.PlainOpen(targetDir)
+		require.NoError(t, err)
+		_, err = target.Reference(plumbing.NewBranchReferenceName("main"), false)
+		require.NoError(t, err)
+		_, err = target.Reference(plumbing.NewTagReferenceName("v1.0.0"), false)
+		require.NoError(t, err)
+		assert.Equal(t, []globalEntities.MirrorState{
+			globalEntities.MirrorStateQueued,
+			globalEntities.MirrorStateRunning,
+			globalEntities.MirrorStateCompleted,
+		}, states)
+	})
+
+	t.Run("should create the repository for the authenticated user when they own it", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		targetDir := t.TempDir()
+		_, err := git.PlainInit(targetDir, true)
+		require.NoError(t, err)
+		p := newTestProvider(t, newMirrorServer(t, "me", "/user/repos", targetDir))
+
+		// when
+		err = p.MigrateRepository(context.Background(), globalEntities.MirrorInput{
+			CloneAddr: newMirrorSource(t),
+			RepoName:  "copy",
+			RepoOwner: "me",
+		})
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should report failure when the source cannot be fetched", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		targetDir := t.TempDir()
+		_, err := git.PlainInit(targetDir, true)
+		require.NoError(t, err)
+		p := newTestProvider(t, newMirrorServer(t, "someone-else", "/orgs/my-org/repos", targetDir))
+		var last globalEntities.MirrorProgress
+
+		// when
+		err = p.MigrateRepository(context.Background(), globalEntities.MirrorInput{
+			CloneAddr: filepath.Join(t.TempDir(), "missing"),
+			RepoName:  "copy",
+			RepoOwner: "my-org",
+			Progress: func(progress globalEntities.MirrorProgress) {
+				last = progress
+			},
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch source")
+		assert.Equal(t, globalEntities.MirrorStateFailed, last.State)
+	})
+}
@@ -0,0 +1,277 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v66/github"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	workflowDispatchEvent = "workflow_dispatch"
+	workflowRunCompleted  = "completed"
+
+	// maxLogRedirects follows the permanent redirects of renamed
+	// repositories before the job logs answer with their download URL.
+	maxLogRedirects = 3
+)
+
+// TriggerPipeline dispatches a workflow_dispatch event to the workflow named
+// by input.Pipeline. GitHub answers without the run it queues, so the run is
+// looked up among the workflow's dispatched runs created since.
+func (p *Provider) TriggerPipeline(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PipelineRunInput,
+) (*globalEntities.PipelineRun, error) {
+	if input.Pipeline == "" {
+		return nil, errors.New("a workflow file name or ID is required to trigger a GitHub Actions run")
+	}
+	if len(input.Variables) > 0 {
+		return nil, fmt.Errorf("GitHub Actions cannot set variables on a run: %w", errors.ErrUnsupported)
+	}
+
+	ref := input.Ref
+	if ref == "" {
+		ref = strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")
+	}
+	inputs := make(map[string]any, len(input.Inputs))
+	for key, value := range input.Inputs {
+		inputs[key] = value
+	}
+
+	// the created filter has a one second resolution
+	dispatchedAt := time.Now().UTC().Truncate(time.Second)
+	if _, err := p.client.Actions.CreateWorkflowDispatchEventByFileName(
+		ctx, repo.Organization, repo.Name, input.Pipeline,
+		gh.CreateWorkflowDispatchEventRequest{Ref: ref, Inputs: inputs},
+	); err != nil {
+		return nil, fmt.Errorf("failed to dispatch workflow %s: %w", input.Pipeline, err)
+	}
+
+	runs, _, err := p.client.Actions.ListWorkflowRunsByFileName(
+		ctx, repo.Organization, repo.Name, input.Pipeline, &gh.ListWorkflowRunsOptions{
+			Branch:      ref,
+			Event:       workflowDispatchEvent,
+			Created:     ">=" + dispatchedAt.Format(time.RFC3339),
+			ListOptions: gh.ListOptions{PerPage: 1},
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the dispatched run of workflow %s: %w", input.Pipeline, err)
+	}
+	if len(runs.WorkflowRuns) == 0 {
+		return &globalEntities.PipelineRun{
+			Pipeline: input.Pipeline,
+			Ref:      ref,
+			Status:   globalEntities.PipelineStatusQueued,
+		}, nil
+	}
+	return githubRunToDomain(runs.WorkflowRuns[0]), nil
+}
+
+func (p *Provider) ListPipelineRuns(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.PipelineRunQuery,
+) ([]globalEntities.PipelineRun, error) {
+	opts := &gh.ListWorkflowRunsOptions{
+		Branch:      query.Branch,
+		HeadSHA:     query.CommitSHA,
+		ListOptions: gh.ListOptions{PerPage: perPage},
+	}
+
+	var runs []globalEntities.PipelineRun
+	for {
+		page, resp, err := p.client.Actions.ListRepositoryWorkflowRuns(ctx, repo.Organization, repo.Name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list workflow runs: %w", err)
+		}
+		for _, run := range page.WorkflowRuns {
+			runs = append(runs, *githubRunToDomain(run))
+			if query.Limit > 0 && len(runs) == query.Limit {
+				return runs, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return runs, nil
+}
+
+func (p *Provider) GetPipelineRun(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	id, err := parseRunID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	run, _, err := p.client.Actions.GetWorkflowRunByID(ctx, repo.Organization, repo.Name, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow run %s: %w", runID, err)
+	}
+	return githubRunToDomain(run), nil
+}
+
+func (p *Provider) ListPipelineJobs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) ([]globalEntities.PipelineJob, error) {
+	id, err := parseRunID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &gh.ListWorkflowJobsOptions{Filter: "latest", ListOptions: gh.ListOptions{PerPage: perPage}}
+	var jobs []globalEntities.PipelineJob
+	for {
+		page, resp, listErr := p.client.Actions.ListWorkflowJobs(ctx, repo.Organization, repo.Name, id, opts)
+		if listErr != nil {
+			return nil, fmt.Errorf("failed to list jobs of workflow run %s: %w", runID, listErr)
+		}
+		for _, job := range page.Jobs {
+			jobs = append(jobs, globalEntities.PipelineJob{
+				ID:         strconv.FormatInt(job.GetID(), 10),
+				Name:       job.GetName(),
+				Status:     githubPipelineStatus(job.GetStatus(), job.GetConclusion()),
+				URL:        job.GetHTMLURL(),
+				StartedAt:  job.GetStartedAt().Time,
+				FinishedAt: job.GetCompletedAt().Time,
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return jobs, nil
+}
+
+func (p *Provider) CancelPipelineRun(ctx context.Context, repo globalEntities.Repository, runID string) error {
+	id, err := parseRunID(runID)
+	if err != nil {
+		return err
+	}
+
+	if _, err = p.client.Actions.CancelWorkflowRunByID(ctx, repo.Organization, repo.Name, id); err != nil {
+		// GitHub answers a queued cancellation with 202 Accepted
+		var acceptedErr *gh.AcceptedError
+		if errors.As(err, &acceptedErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to cancel workflow run %s: %w", runID, err)
+	}
+	return nil
+}
+
+// RetryPipelineRun re-runs the failed and canceled jobs of the run as a new
+// attempt.
+func (p *Provider) RetryPipelineRun(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	id, err := parseRunID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = p.client.Actions.RerunFailedJobsByID(ctx, repo.Organization, repo.Name, id); err != nil {
+		return nil, fmt.Errorf("failed to retry workflow run %s: %w", runID, err)
+	}
+	return p.GetPipelineRun(ctx, repo, runID)
+}
+
+// GetPipelineJobLogs downloads the job's log from the short-lived URL the API
+// redirects to. Job IDs are unique in the repository, so runID is not needed.
+func (p *Provider) GetPipelineJobLogs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	_, jobID string,
+) (io.ReadCloser, error) {
+	id, err := strconv.ParseInt(jobID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID %q: %w", jobID, err)
+	}
+
+	logURL, _, err := p.client.Actions.GetWorkflowJobLogs(ctx, repo.Organization, repo.Name, id, maxLogRedirects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get log URL of job %s: %w", jobID, err)
+	}
+
+	// the URL is pre-signed, so it is fetched without the API token
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download log of job %s: %w", jobID, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("failed to download log of job %s: status %d", jobID, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+func parseRunID(id string) (int64, error) {
+	runID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid workflow run ID %q: %w", id, err)
+	}
+	return runID, nil
+}
+
+func githubRunToDomain(run *gh.WorkflowRun) *globalEntities.PipelineRun {
+	result := &globalEntities.PipelineRun{
+		ID:        strconv.FormatInt(run.GetID(), 10),
+		Pipeline:  run.GetName(),
+		Ref:       run.GetHeadBranch(),
+		CommitSHA: run.GetHeadSHA(),
+		Status:    githubPipelineStatus(run.GetStatus(), run.GetConclusion()),
+		URL:       run.GetHTMLURL(),
+		CreatedAt: run.GetCreatedAt().Time,
+	}
+	// runs carry no completion time; the last update is when they finished
+	if run.GetStatus() == workflowRunCompleted {
+		result.FinishedAt = run.GetUpdatedAt().Time
+	}
+	return result
+}
+
+// githubPipelineStatus maps the status and, once completed, the conclusion of
+// a workflow run or job.
+func githubPipelineStatus(status, conclusion string) globalEntities.PipelineStatus {
+	switch {
+	case status == "in_progress":
+		return globalEntities.PipelineStatusRunning
+	case status != workflowRunCompleted: // queued, requested, waiting, pending
+		return globalEntities.PipelineStatusQueued
+	}
+
+	switch conclusion {
+	case "success", "neutral":
+		return globalEntities.PipelineStatusSuccess
+	case "cancelled":
+		return globalEntities.PipelineStatusCanceled
+	case "skipped":
+		return globalEntities.PipelineStatusSkipped
+	default: // failure, timed_out, action_required, stale, startup_failure
+		return globalEntities.PipelineStatusFailure
+	}
+}
@@ -0,0 +1,244 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const testWorkflowRunJSON = `{"id":501,"name":"CI","head_branch":"feature","head_sha":"abc123",
+	"status":"completed","conclusion":"failure","html_url":"https://github.com/my-org/my-repo/actions/runs/501",
+	"created_at":"2026-01-02T10:00:00Z","updated_at":"2026-01-02T10:05:00Z"}`
+
+func TestTriggerPipelineInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should dispatch the workflow and return the run it queued", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		var query map[string]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repos/my-org/my-repo/actions/workflows/ci.yml/dispatches",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.WriteHeader(http.StatusNoContent)
+			})
+		mux.HandleFunc("GET /repos/my-org/my-repo/actions/workflows/ci.yml/runs",
+			func(w http.ResponseWriter, r *http.Request) {
+				query = map[string]string{
+					"branch": r.URL.Query().Get("branch"),
+					"event":  r.URL.Query().Get("event"),
+				}
+				_, _ = w.Write([]byte(`{"total_count":1,"workflow_runs":[` + testWorkflowRunJSON + `]}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo", DefaultBranch: "refs/heads/main"}
+
+		// when
+		run, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{
+			Pipeline: "ci.yml",
+			Inputs:   map[string]string{"environment": "staging"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"ref": "main", "inputs": map[string]any{"environment": "staging"}}, body)
+		assert.Equal(t, map[string]string{"branch": "main", "event": "workflow_dispatch"}, query)
+		assert.Equal(t, "501", run.ID)
+	})
+
+	t.Run("should return a queued run without ID when the run is not listed yet", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repos/my-org/my-repo/actions/workflows/ci.yml/dispatches",
+			func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+		mux.HandleFunc("GET /repos/my-org/my-repo/actions/workflows/ci.yml/runs",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"total_count":0,"workflow_runs":[]}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		run, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{
+			Pipeline: "ci.yml", Ref: "release",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &globalEntities.PipelineRun{
+			Pipeline: "ci.yml", Ref: "release", Status: globalEntities.PipelineStatusQueued,
+		}, run)
+	})
+
+	t.Run("should return ErrUnsupported for variables", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		p := &Provider{}
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{
+			Pipeline: "ci.yml", Variables: map[string]string{"DEBUG": "1"},
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestListPipelineRunsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should filter by branch and commit and map the run", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var query string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/actions/runs", func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.Query().Get("branch") + "@" + r.URL.Query().Get("head_sha")
+			_, _ = w.Write([]byte(`{"total_count":2,"workflow_runs":[` + testWorkflowRunJSON + `,{"id":502}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		runs, err := p.ListPipelineRuns(context.Background(), repo, globalEntities.PipelineRunQuery{
+			Branch: "feature", CommitSHA: "abc123", Limit: 1,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "feature@abc123", query)
+		assert.Equal(t, []globalEntities.PipelineRun{{
+			ID:         "501",
+			Pipeline:   "CI",
+			Ref:        "feature",
+			CommitSHA:  "abc123",
+			Status:     globalEntities.PipelineStatusFailure,
+			URL:        "https://github.com/my-org/my-repo/actions/runs/501",
+			CreatedAt:  time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+			FinishedAt: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC),
+		}}, runs)
+	})
+}
+
+func TestListPipelineJobsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should map the status and conclusion of each job", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/actions/runs/501/jobs", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "latest", r.URL.Query().Get("filter"))
+			_, _ = w.Write([]byte(`{"total_count":4,"jobs":[
+				{"id":1,"name":"build","status":"completed","conclusion":"success"},
+				{"id":2,"name":"test","status":"in_progress"},
+				{"id":3,"name":"deploy","status":"queued"},
+				{"id":4,"name":"lint","status":"completed","conclusion":"cancelled"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		jobs, err := p.ListPipelineJobs(context.Background(), repo, "501")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, jobs, 4)
+		assert.Equal(t, globalEntities.PipelineStatusSuccess, jobs[0].Status)
+		assert.Equal(t, globalEntities.PipelineStatusRunning, jobs[1].Status)
+		assert.Equal(t, globalEntities.PipelineStatusQueued, jobs[2].Status)
+		assert.Equal(t, globalEntities.PipelineStatusCanceled, jobs[3].Status)
+	})
+}
+
+func TestCancelPipelineRunInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should treat 202 Accepted as success", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repos/my-org/my-repo/actions/runs/501/cancel", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.CancelPipelineRun(context.Background(), repo, "501")
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func TestGetPipelineJobLogsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should stream the log from the URL the API redirects to", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var blobAuth string
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+		mux.HandleFunc("GET /repos/my-org/my-repo/actions/jobs/7/logs", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, server.URL+"/blob/job-7.txt", http.StatusFound)
+		})
+		mux.HandleFunc("GET /blob/job-7.txt", func(w http.ResponseWriter, r *http.Request) {
+			blobAuth = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte("step 1\nstep 2\n"))
+		})
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		logs, err := p.GetPipelineJobLogs(context.Background(), repo, "501", "7")
+
+		// then
+		require.NoError(t, err)
+		defer logs.Close()
+		content, readErr := io.ReadAll(logs)
+		require.NoError(t, readErr)
+		assert.Equal(t, "step 1\nstep 2\n", string(content))
+		assert.Empty(t, blobAuth)
+	})
+}
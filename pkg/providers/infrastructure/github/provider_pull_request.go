@@ -17,6 +17,7 @@ func (p *Provider) CreatePullRequest(
 	sourceBranch := strings.TrimPrefix(input.SourceBranch, "refs/heads/")
 	targetBranch := strings.TrimPrefix(input.TargetBranch, "refs/heads/")
 	maintainerCanModify := true
+	body := globalEntities.AppendIssueReferences(input.Description, input.IssueLinks)
 
 	pr, _, err := p.client.PullRequests.Create(
 		ctx, repo.Organization, repo.Name,
@@ -24,7 +25,7 @@ func (p *Provider) CreatePullRequest(
 			Title:               &input.Title,
 			Head:                &sourceBranch,
 			Base:                &targetBranch,
-			Body:                &input.Description,
+			Body:                &body,
 			MaintainerCanModify: &maintainerCanModify,
 		},
 	)
@@ -0,0 +1,235 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	gh "github.com/google/go-github/v66/github"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var errReleaseNotFound = errors.New("release not found")
+
+// CreateRelease publishes a GitHub release, creating the tag on input.Target
+// when it does not exist yet.
+func (p *Provider) CreateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	release := githubReleaseInput(input)
+	release.TagName = gh.String(input.TagName)
+	if input.Target != "" {
+		release.TargetCommitish = gh.String(input.Target)
+	}
+
+	created, _, err := p.client.Repositories.CreateRelease(ctx, repo.Organization, repo.Name, release)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release %q: %w", input.TagName, err)
+	}
+	return githubReleaseToDomain(created), nil
+}
+
+// UpdateRelease edits the name, body and flags of the release of input.TagName.
+func (p *Provider) UpdateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	existing, err := p.findRelease(ctx, repo, input.TagName)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, _, err := p.client.Repositories.EditRelease(
+		ctx, repo.Organization, repo.Name, existing.GetID(), githubReleaseInput(input),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update release %q: %w", input.TagName, err)
+	}
+	return githubReleaseToDomain(updated), nil
+}
+
+func (p *Provider) GetRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+) (*globalEntities.Release, error) {
+	release, err := p.findRelease(ctx, repo, tagName)
+	if err != nil {
+		return nil, err
+	}
+	return githubReleaseToDomain(release), nil
+}
+
+func (p *Provider) ListReleases(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Release, error) {
+	releases, err := p.listReleases(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]globalEntities.Release, 0, len(releases))
+	for _, release := range releases {
+		result = append(result, *githubReleaseToDomain(release))
+	}
+	return result, nil
+}
+
+// UploadReleaseAsset uploads a file to the release of tagName. GitHub needs
+// the content length up front, so content of unknown size is buffered first.
+func (p *Provider) UploadReleaseAsset(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+	asset globalEntities.ReleaseAssetInput,
+) (*globalEntities.ReleaseAsset, error) {
+	release, err := p.findRelease(ctx, repo, tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	content, size := asset.Content, asset.Size
+	if size <= 0 {
+		data, readErr := io.ReadAll(asset.Content)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read asset %q: %w", asset.Name, readErr)
+		}
+		content, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	endpoint := fmt.Sprintf(
+		"repos/%s/%s/releases/%d/assets?name=%s",
+		repo.Organization, repo.Name, release.GetID(), url.QueryEscape(asset.Name),
+	)
+	req, err := p.client.NewUploadRequest(endpoint, content, size, asset.ContentTypeOrDefault())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+
+	uploaded := new(gh.ReleaseAsset)
+	if _, err = p.client.Do(ctx, req, uploaded); err != nil {
+		return nil, fmt.Errorf("failed to upload asset %q: %w", asset.Name, err)
+	}
+	return githubAssetToDomain(uploaded), nil
+}
+
+// DeleteReleaseAsset deletes an asset. GitHub asset IDs are unique within the
+// repository, so tagName is not needed to find it.
+func (p *Provider) DeleteReleaseAsset(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	_ string,
+	assetID string,
+) error {
+	id, err := strconv.ParseInt(assetID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid asset ID %q: %w", assetID, err)
+	}
+
+	if _, err = p.client.Repositories.DeleteReleaseAsset(ctx, repo.Organization, repo.Name, id); err != nil {
+		return fmt.Errorf("failed to delete asset %s: %w", assetID, err)
+	}
+	return nil
+}
+
+// findRelease returns the release of tagName. The by-tag endpoint does not
+// see drafts, so on a miss the release list is searched as well.
+func (p *Provider) findRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+) (*gh.RepositoryRelease, error) {
+	release, _, err := p.client.Repositories.GetReleaseByTag(ctx, repo.Organization, repo.Name, tagName)
+	if err == nil {
+		return release, nil
+	}
+	var ghErr *gh.ErrorResponse
+	if !errors.As(err, &ghErr) || ghErr.Response.StatusCode != http.StatusNotFound {
+		return nil, fmt.Errorf("failed to get release %q: %w", tagName, err)
+	}
+
+	releases, err := p.listReleases(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range releases {
+		if candidate.GetTagName() == tagName {
+			return candidate, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", errReleaseNotFound, tagName)
+}
+
+func (p *Provider) listReleases(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]*gh.RepositoryRelease, error) {
+	var allReleases []*gh.RepositoryRelease
+	opts := &gh.ListOptions{PerPage: perPage}
+
+	for {
+		releases, resp, err := p.client.Repositories.ListReleases(ctx, repo.Organization, repo.Name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+
+		allReleases = append(allReleases, releases...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allReleases, nil
+}
+
+// githubReleaseInput maps the editable fields of input. GitHub's own rule
+// decides the latest release unless input.Latest asks for this one.
+func githubReleaseInput(input globalEntities.ReleaseInput) *gh.RepositoryRelease {
+	release := &gh.RepositoryRelease{
+		Name:       gh.String(input.Name),
+		Body:       gh.String(input.Body),
+		Draft:      gh.Bool(input.Draft),
+		Prerelease: gh.Bool(input.Prerelease),
+	}
+	if input.Latest {
+		release.MakeLatest = gh.String("true")
+	}
+	return release
+}
+
+func githubReleaseToDomain(release *gh.RepositoryRelease) *globalEntities.Release {
+	result := &globalEntities.Release{
+		ID:         strconv.FormatInt(release.GetID(), 10),
+		TagName:    release.GetTagName(),
+		Name:       release.GetName(),
+		Body:       release.GetBody(),
+		Draft:      release.GetDraft(),
+		Prerelease: release.GetPrerelease(),
+		URL:        release.GetHTMLURL(),
+		CreatedAt:  release.GetCreatedAt().Time,
+	}
+	for _, asset := range release.Assets {
+		result.Assets = append(result.Assets, *githubAssetToDomain(asset))
+	}
+	return result
+}
+
+func githubAssetToDomain(asset *gh.ReleaseAsset) *globalEntities.ReleaseAsset {
+	return &globalEntities.ReleaseAsset{
+		ID:          strconv.FormatInt(asset.GetID(), 10),
+		Name:        asset.GetName(),
+		Size:        int64(asset.GetSize()),
+		DownloadURL: asset.GetBrowserDownloadURL(),
+	}
+}
@@ -0,0 +1,157 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateReleaseInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send the target and flags and only force latest when asked", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repos/my-org/my-repo/releases", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":7,"tag_name":"v1.0.0","name":"v1.0.0","prerelease":true,
+				"html_url":"https://github.com/my-org/my-repo/releases/tag/v1.0.0"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		release, err := p.CreateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0", Target: "main", Name: "v1.0.0", Prerelease: true,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "main", body["target_commitish"])
+		assert.Equal(t, true, body["prerelease"])
+		assert.NotContains(t, body, "make_latest")
+		assert.Equal(t, "7", release.ID)
+		assert.True(t, release.Prerelease)
+	})
+}
+
+func TestGetReleaseInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should find a draft through the release list", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/releases/tags/v2.0.0", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		})
+		mux.HandleFunc("GET /repos/my-org/my-repo/releases", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[{"id":1,"tag_name":"v1.0.0"},{"id":2,"tag_name":"v2.0.0","draft":true,
+				"assets":[{"id":9,"name":"app.tar.gz","size":42,"browser_download_url":"https://dl/app.tar.gz"}]}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		release, err := p.GetRelease(context.Background(), repo, "v2.0.0")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "2", release.ID)
+		assert.True(t, release.Draft)
+		assert.Equal(t, []globalEntities.ReleaseAsset{
+			{ID: "9", Name: "app.tar.gz", Size: 42, DownloadURL: "https://dl/app.tar.gz"},
+		}, release.Assets)
+	})
+
+	t.Run("should return an error when no release has the tag", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/releases/tags/v9.9.9", func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		})
+		mux.HandleFunc("GET /repos/my-org/my-repo/releases", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`[]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.GetRelease(context.Background(), repo, "v9.9.9")
+
+		// then
+		require.ErrorIs(t, err, errReleaseNotFound)
+	})
+}
+
+func TestUploadReleaseAssetInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should buffer content of unknown size and upload it to the release", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var gotName, gotType, gotContent string
+		var gotLength int64
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/releases/tags/v1.0.0", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":7,"tag_name":"v1.0.0"}`))
+		})
+		mux.HandleFunc("POST /repos/my-org/my-repo/releases/7/assets", func(w http.ResponseWriter, r *http.Request) {
+			gotName = r.URL.Query().Get("name")
+			gotType = r.Header.Get("Content-Type")
+			gotLength = r.ContentLength
+			data, _ := io.ReadAll(r.Body)
+			gotContent = string(data)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":11,"name":"notes.txt","size":5}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		p.client.UploadURL = p.client.BaseURL
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		asset, err := p.UploadReleaseAsset(context.Background(), repo, "v1.0.0", globalEntities.ReleaseAssetInput{
+			Name:    "notes.txt",
+			Content: strings.NewReader("hello"),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "notes.txt", gotName)
+		assert.Equal(t, "application/octet-stream", gotType)
+		assert.Equal(t, int64(5), gotLength)
+		assert.Equal(t, "hello", gotContent)
+		assert.Equal(t, "11", asset.ID)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	gh "github.com/google/go-github/v66/github"
 	log "github.com/sirupsen/logrus"
@@ -17,8 +18,9 @@ import (
 // attempt to update the status of a review thread. GitHub has no direct REST
 // equivalent of Azure DevOps' thread status field; thread resolution is exposed
 // only via the GraphQL resolveReviewThread mutation, which is not yet wired up.
-var ErrThreadStatusUpdateUnsupported = errors.New(
-	"updating pull request thread status is not supported on GitHub",
+// It wraps errors.ErrUnsupported.
+var ErrThreadStatusUpdateUnsupported = fmt.Errorf(
+	"updating pull request thread status is not supported on GitHub: %w", errors.ErrUnsupported,
 )
 
 // ErrReviewBodyRequired signals that SubmitPullRequestReview was called with a
@@ -325,6 +327,146 @@ func (p *Provider) GetPullRequestCheckStatus(
 	return true, nil
 }
 
+// GetPullRequestChecks returns the latest commit status of each context and
+// the latest run of each check on the pull request's head commit. The checks
+// the base branch's protection requires are marked Required.
+func (p *Provider) GetPullRequestChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*globalEntities.PullRequestChecks, error) {
+	pr, _, err := p.client.PullRequests.Get(ctx, repo.Organization, repo.Name, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request: %w", withRateLimitHint(err))
+	}
+	headSHA := pr.GetHead().GetSHA()
+
+	required, err := p.requiredCheckNames(ctx, repo, pr.GetBase().GetRef())
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []globalEntities.Check
+	statusOpts := &gh.ListOptions{PerPage: perPage}
+	for {
+		combined, resp, statusErr := p.client.Repositories.GetCombinedStatus(
+			ctx, repo.Organization, repo.Name, headSHA, statusOpts,
+		)
+		if statusErr != nil {
+			return nil, fmt.Errorf("failed to get combined status: %w", withRateLimitHint(statusErr))
+		}
+		for _, status := range combined.Statuses {
+			checks = append(checks, githubStatusToCheck(status, required))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		statusOpts.Page = resp.NextPage
+	}
+
+	latest := "latest"
+	runOpts := &gh.ListCheckRunsOptions{Filter: &latest, ListOptions: gh.ListOptions{PerPage: perPage}}
+	for {
+		runs, resp, runsErr := p.client.Checks.ListCheckRunsForRef(
+			ctx, repo.Organization, repo.Name, headSHA, runOpts,
+		)
+		if runsErr != nil {
+			return nil, fmt.Errorf("failed to list check runs: %w", withRateLimitHint(runsErr))
+		}
+		for _, run := range runs.CheckRuns {
+			checks = append(checks, githubCheckRunToCheck(run, required))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		runOpts.Page = resp.NextPage
+	}
+
+	return globalEntities.NewPullRequestChecks(checks), nil
+}
+
+// requiredCheckNames returns the status check names the protection of branch
+// requires; an unprotected branch requires none.
+func (p *Provider) requiredCheckNames(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	branch string,
+) (map[string]bool, error) {
+	b, _, err := p.client.Repositories.GetBranch(ctx, repo.Organization, repo.Name, branch, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get base branch %s: %w", branch, withRateLimitHint(err))
+	}
+
+	required := make(map[string]bool)
+	statusChecks := b.GetProtection().GetRequiredStatusChecks()
+	if statusChecks == nil {
+		return required, nil
+	}
+	if statusChecks.Contexts != nil {
+		for _, name := range *statusChecks.Contexts {
+			required[name] = true
+		}
+	}
+	if statusChecks.Checks != nil {
+		for _, check := range *statusChecks.Checks {
+			required[check.Context] = true
+		}
+	}
+	return required, nil
+}
+
+func githubStatusToCheck(status *gh.RepoStatus, required map[string]bool) globalEntities.Check {
+	check := globalEntities.Check{
+		Name:      status.GetContext(),
+		Kind:      globalEntities.CheckKindStatus,
+		Required:  required[status.GetContext()],
+		URL:       status.GetTargetURL(),
+		StartedAt: status.GetCreatedAt().Time,
+	}
+	switch status.GetState() {
+	case "success":
+		check.State = globalEntities.CheckStateSuccess
+	case "pending":
+		check.State = globalEntities.CheckStatePending
+	default: // failure, error
+		check.State = globalEntities.CheckStateFailure
+	}
+	if check.State != globalEntities.CheckStatePending {
+		check.CompletedAt = status.GetUpdatedAt().Time
+	}
+	return check
+}
+
+func githubCheckRunToCheck(run *gh.CheckRun, required map[string]bool) globalEntities.Check {
+	check := globalEntities.Check{
+		Name:        run.GetName(),
+		Kind:        globalEntities.CheckKindCheckRun,
+		Required:    required[run.GetName()],
+		URL:         run.GetDetailsURL(),
+		StartedAt:   run.GetStartedAt().Time,
+		CompletedAt: run.GetCompletedAt().Time,
+	}
+	if check.URL == "" {
+		check.URL = run.GetHTMLURL()
+	}
+
+	if run.GetStatus() != "completed" {
+		check.State = globalEntities.CheckStatePending
+		return check
+	}
+	switch run.GetConclusion() {
+	case "success":
+		check.State = globalEntities.CheckStateSuccess
+	case "neutral":
+		check.State = globalEntities.CheckStateNeutral
+	case "skipped":
+		check.State = globalEntities.CheckStateSkipped
+	default: // failure, cancelled, timed_out, action_required, stale, startup_failure
+		check.State = globalEntities.CheckStateFailure
+	}
+	return check
+}
+
 func (p *Provider) MergePullRequest(
 	ctx context.Context,
 	repo globalEntities.Repository,
@@ -583,6 +725,21 @@ func isSelfReviewError(err error) bool {
 		strings.Contains(err.Error(), selfReviewErrFragment)
 }
 
+// withRateLimitHint wraps GitHub's primary and secondary rate limit errors in
+// a RateLimitError telling how long to wait: until the limit resets, or the
+// Retry-After GitHub sent. Other errors are returned unchanged.
+func withRateLimitHint(err error) error {
+	var rateErr *gh.RateLimitError
+	if errors.As(err, &rateErr) {
+		return &globalEntities.RateLimitError{RetryAfter: max(time.Until(rateErr.Rate.Reset.Time), 0), Err: err}
+	}
+	var abuseErr *gh.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return &globalEntities.RateLimitError{RetryAfter: abuseErr.GetRetryAfter(), Err: err}
+	}
+	return err
+}
+
 // mapVerdictToReviewEvent translates a gitforge ReviewVerdict to the GitHub
 // `event` string accepted by CreateReview. GitHub has no "waiting on author"
 // state, so ReviewVerdictWaitingForAuthor collapses to COMMENT — a soft
@@ -622,7 +779,7 @@ func (p *Provider) GetPullRequestStatus(
 ) (string, error) {
 	pr, _, err := p.client.PullRequests.Get(ctx, repo.Organization, repo.Name, prID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get pull request: %w", err)
+		return "", fmt.Errorf("failed to get pull request: %w", withRateLimitHint(err))
 	}
 
 	if pr.GetState() == prStateClosed && !pr.GetMergedAt().IsZero() {
@@ -0,0 +1,99 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	gh "github.com/google/go-github/v66/github"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-fA-F]{40}$`)
+
+// CreateTag creates the tag through the git data API: an annotated tag is a
+// tag object with a ref pointing at it, a lightweight tag only the ref.
+func (p *Provider) CreateTag(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.TagInput,
+) (*globalEntities.Tag, error) {
+	commitSHA, err := p.resolveCommitSHA(ctx, repo, input.Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve tag target: %w", err)
+	}
+	commit, _, err := p.client.Git.GetCommit(ctx, repo.Organization, repo.Name, commitSHA)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit %s: %w", commitSHA, err)
+	}
+
+	refSHA := commitSHA
+	if input.Message != "" {
+		tag := &gh.Tag{
+			Tag:     gh.String(input.Name),
+			Message: gh.String(input.Message),
+			Object:  &gh.GitObject{Type: gh.String("commit"), SHA: gh.String(commitSHA)},
+		}
+		if input.TaggerName != "" || input.TaggerEmail != "" {
+			tag.Tagger = &gh.CommitAuthor{
+				Name:  gh.String(input.TaggerName),
+				Email: gh.String(input.TaggerEmail),
+				Date:  &gh.Timestamp{Time: time.Now()},
+			}
+		}
+
+		created, _, tagErr := p.client.Git.CreateTag(ctx, repo.Organization, repo.Name, tag)
+		if tagErr != nil {
+			return nil, fmt.Errorf("failed to create tag object %q: %w", input.Name, tagErr)
+		}
+		refSHA = created.GetSHA()
+	}
+
+	_, _, err = p.client.Git.CreateRef(ctx, repo.Organization, repo.Name, &gh.Reference{
+		Ref:    gh.String("refs/tags/" + input.Name),
+		Object: &gh.GitObject{SHA: gh.String(refSHA)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag %q: %w", input.Name, err)
+	}
+
+	return &globalEntities.Tag{
+		Name:      input.Name,
+		CommitSHA: commitSHA,
+		Annotated: input.Message != "",
+		Message:   input.Message,
+		Date:      commit.GetCommitter().GetDate().Time,
+	}, nil
+}
+
+// resolveCommitSHA returns the commit target names: a full commit SHA as is, a
+// branch as its head, and the default branch's head when target is empty.
+func (p *Provider) resolveCommitSHA(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	target string,
+) (string, error) {
+	if commitSHAPattern.MatchString(target) {
+		return target, nil
+	}
+
+	branch := strings.TrimPrefix(target, "refs/heads/")
+	if branch == "" {
+		branch = strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")
+	}
+	if branch == "" {
+		r, _, err := p.client.Repositories.Get(ctx, repo.Organization, repo.Name)
+		if err != nil {
+			return "", fmt.Errorf("failed to get repository: %w", err)
+		}
+		branch = r.GetDefaultBranch()
+	}
+
+	ref, _, err := p.client.Git.GetRef(ctx, repo.Organization, repo.Name, "refs/heads/"+branch)
+	if err != nil {
+		return "", fmt.Errorf("failed to get branch %q: %w", branch, err)
+	}
+	return ref.GetObject().GetSHA(), nil
+}
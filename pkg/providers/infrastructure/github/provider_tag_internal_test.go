@@ -0,0 +1,104 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const testCommitSHA = "1111111111111111111111111111111111111111"
+
+func TestCreateTagInternal(t *testing.T) {
+	t.Parallel()
+
+	commitHandler := func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"sha":"` + testCommitSHA + `","committer":{"date":"2026-03-04T05:06:07Z"}}`))
+	}
+
+	t.Run("should create a tag object with the tagger and point the ref at it", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var tagBody, refBody map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/git/ref/heads/main", func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"ref":"refs/heads/main","object":{"sha":"` + testCommitSHA + `"}}`))
+		})
+		mux.HandleFunc("GET /repos/my-org/my-repo/git/commits/"+testCommitSHA, commitHandler)
+		mux.HandleFunc("POST /repos/my-org/my-repo/git/tags", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&tagBody)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"sha":"tag-object","tag":"v1.0.0"}`))
+		})
+		mux.HandleFunc("POST /repos/my-org/my-repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&refBody)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ref":"refs/tags/v1.0.0"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo", DefaultBranch: "refs/heads/main"}
+
+		// when
+		tag, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Message: "Release 1.0.0", TaggerName: "Release Bot", TaggerEmail: "bot@example.com",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testCommitSHA, tagBody["object"])
+		assert.Equal(t, "commit", tagBody["type"])
+		assert.Equal(t, "Release Bot", tagBody["tagger"].(map[string]any)["name"])
+		assert.Equal(t, "refs/tags/v1.0.0", refBody["ref"])
+		assert.Equal(t, "tag-object", refBody["sha"])
+		assert.Equal(t, &globalEntities.Tag{
+			Name: "v1.0.0", CommitSHA: testCommitSHA, Annotated: true, Message: "Release 1.0.0",
+			Date: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		}, tag)
+	})
+
+	t.Run("should point a lightweight tag ref straight at the commit", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var refBody map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /repos/my-org/my-repo/git/commits/"+testCommitSHA, commitHandler)
+		mux.HandleFunc("POST /repos/my-org/my-repo/git/tags", func(_ http.ResponseWriter, _ *http.Request) {
+			assert.Fail(t, "no tag object expected for a lightweight tag")
+		})
+		mux.HandleFunc("POST /repos/my-org/my-repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&refBody)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"ref":"refs/tags/v1.0.0"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		tag, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Target: testCommitSHA,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, testCommitSHA, refBody["sha"])
+		assert.False(t, tag.Annotated)
+		latest, err := tag.LatestTag()
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", latest.Tag.String())
+	})
+}
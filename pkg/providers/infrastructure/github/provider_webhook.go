@@ -0,0 +1,212 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	gh "github.com/google/go-github/v66/github"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// githubWebhookEvents maps each webhook event onto the GitHub events it
+// subscribes to.
+var githubWebhookEvents = map[globalEntities.WebhookEvent][]string{
+	globalEntities.WebhookEventPush:        {"push"},
+	globalEntities.WebhookEventPullRequest: {"pull_request"},
+	globalEntities.WebhookEventComment:     {"issue_comment", "pull_request_review_comment"},
+	globalEntities.WebhookEventIssue:       {"issues"},
+	globalEntities.WebhookEventRelease:     {"release"},
+	globalEntities.WebhookEventPipeline:    {"workflow_run"},
+}
+
+func (p *Provider) CreateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	hook, err := githubHookInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	created, _, err := p.client.Repositories.CreateHook(ctx, repo.Organization, repo.Name, hook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return githubHookToDomain(repo, created), nil
+}
+
+func (p *Provider) ListWebhooks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Webhook, error) {
+	var webhooks []globalEntities.Webhook
+	opts := &gh.ListOptions{PerPage: perPage}
+	for {
+		hooks, resp, err := p.client.Repositories.ListHooks(ctx, repo.Organization, repo.Name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+		for _, hook := range hooks {
+			webhooks = append(webhooks, *githubHookToDomain(repo, hook))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return webhooks, nil
+}
+
+func (p *Provider) UpdateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id string,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := githubHookInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, _, err := p.client.Repositories.EditHook(ctx, repo.Organization, repo.Name, hookID, hook)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook %s: %w", id, err)
+	}
+	return githubHookToDomain(repo, updated), nil
+}
+
+func (p *Provider) DeleteWebhook(ctx context.Context, repo globalEntities.Repository, id string) error {
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return err
+	}
+	if _, err = p.client.Repositories.DeleteHook(ctx, repo.Organization, repo.Name, hookID); err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// PingWebhook sends the webhook a ping event.
+func (p *Provider) PingWebhook(ctx context.Context, repo globalEntities.Repository, id string) error {
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return err
+	}
+	if _, err = p.client.Repositories.PingHook(ctx, repo.Organization, repo.Name, hookID); err != nil {
+		return fmt.Errorf("failed to ping webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the webhook's 100 most recent deliveries.
+func (p *Provider) ListWebhookDeliveries(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id string,
+) ([]globalEntities.WebhookDelivery, error) {
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, _, err := p.client.Repositories.ListHookDeliveries(
+		ctx, repo.Organization, repo.Name, hookID, &gh.ListCursorOptions{PerPage: perPage},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries of webhook %s: %w", id, err)
+	}
+
+	result := make([]globalEntities.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, globalEntities.WebhookDelivery{
+			ID:          strconv.FormatInt(delivery.GetID(), 10),
+			Event:       delivery.GetEvent(),
+			StatusCode:  delivery.GetStatusCode(),
+			DeliveredAt: delivery.GetDeliveredAt().Time,
+			Redelivery:  delivery.GetRedelivery(),
+		})
+	}
+	return result, nil
+}
+
+func (p *Provider) RedeliverWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id, deliveryID string,
+) error {
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return err
+	}
+	delivery, err := strconv.ParseInt(deliveryID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid delivery ID %q: %w", deliveryID, err)
+	}
+
+	if _, _, err = p.client.Repositories.RedeliverHookDelivery(
+		ctx, repo.Organization, repo.Name, hookID, delivery,
+	); err != nil {
+		// GitHub answers a queued redelivery with 202 Accepted
+		var acceptedErr *gh.AcceptedError
+		if errors.As(err, &acceptedErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to redeliver delivery %s of webhook %s: %w", deliveryID, id, err)
+	}
+	return nil
+}
+
+func parseHookID(id string) (int64, error) {
+	hookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook ID %q: %w", id, err)
+	}
+	return hookID, nil
+}
+
+func githubHookInput(input globalEntities.WebhookInput) (*gh.Hook, error) {
+	var events []string
+	for _, event := range input.Events {
+		names, ok := githubWebhookEvents[event]
+		if !ok {
+			return nil, fmt.Errorf("webhook event %q: %w", event, errors.ErrUnsupported)
+		}
+		events = append(events, names...)
+	}
+
+	return &gh.Hook{
+		Config: &gh.HookConfig{
+			URL:         gh.String(input.URL),
+			ContentType: gh.String(string(input.ContentTypeOrDefault())),
+			Secret:      gh.String(input.Secret),
+		},
+		Events: events,
+		Active: gh.Bool(true),
+	}, nil
+}
+
+func githubHookToDomain(repo globalEntities.Repository, hook *gh.Hook) *globalEntities.Webhook {
+	webhook := &globalEntities.Webhook{
+		ID:          strconv.FormatInt(hook.GetID(), 10),
+		Repository:  repo,
+		URL:         hook.GetConfig().GetURL(),
+		ContentType: globalEntities.WebhookContentType(hook.GetConfig().GetContentType()),
+		Active:      hook.GetActive(),
+		CreatedAt:   hook.GetCreatedAt().Time,
+	}
+	for event, names := range githubWebhookEvents {
+		if slices.ContainsFunc(names, func(name string) bool { return slices.Contains(hook.Events, name) }) {
+			webhook.Events = append(webhook.Events, event)
+		}
+	}
+	slices.Sort(webhook.Events)
+	return webhook
+}
@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateWebhookInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should subscribe to the GitHub events of each webhook event", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repos/my-org/my-repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":12,"active":true,"events":["push","issue_comment","pull_request_review_comment"],
+				"config":{"url":"https://bot.example.com/hook","content_type":"json"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		webhook, err := p.CreateWebhook(context.Background(), repo, globalEntities.WebhookInput{
+			URL:    "https://bot.example.com/hook",
+			Events: []globalEntities.WebhookEvent{globalEntities.WebhookEventPush, globalEntities.WebhookEventComment},
+			Secret: "s3cret",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "web", body["name"])
+		assert.Equal(t, []any{"push", "issue_comment", "pull_request_review_comment"}, body["events"])
+		assert.Equal(t, map[string]any{
+			"url": "https://bot.example.com/hook", "content_type": "json", "secret": "s3cret",
+		}, body["config"])
+		assert.Equal(t, &globalEntities.Webhook{
+			ID:          "12",
+			Repository:  repo,
+			URL:         "https://bot.example.com/hook",
+			Events:      []globalEntities.WebhookEvent{globalEntities.WebhookEventComment, globalEntities.WebhookEventPush},
+			ContentType: globalEntities.WebhookContentTypeJSON,
+			Active:      true,
+		}, webhook)
+	})
+
+	t.Run("should reject an unknown event", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NewServeMux())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.CreateWebhook(context.Background(), repo, globalEntities.WebhookInput{
+			URL: "https://bot.example.com/hook", Events: []globalEntities.WebhookEvent{"deployment"},
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestRedeliverWebhookInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should treat the queued redelivery as success", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc(
+			"POST /repos/my-org/my-repo/hooks/12/deliveries/345/attempts",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				_, _ = w.Write([]byte(`{}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.RedeliverWebhook(context.Background(), repo, "12", "345")
+
+		// then
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,33 @@
+package gitlab_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/gitlab"
+	"github.com/rios0rios0/gitforge/test/conformance"
+	"github.com/rios0rios0/gitforge/test/fakes"
+)
+
+func TestProviderConformance(t *testing.T) {
+	t.Parallel()
+
+	conformance.Run(t, conformance.Harness{
+		NewBackend: func(t *testing.T) conformance.Backend {
+			return fakes.NewGitLabServer(t)
+		},
+		NewProvider: func(t *testing.T, backend conformance.Backend) globalEntities.ForgeProvider {
+			provider, err := gitlab.NewSelfManagedProvider("token", backend.(*fakes.GitLabServer).URL(), 0)
+			require.NoError(t, err)
+			return provider
+		},
+		Skip: map[string]string{
+			"FileAccessProvider/should reject unsupported change types": "CreateBranchWithChanges " +
+				"commits any change type other than add and delete as an update",
+			"MirrorLifecycleProvider/should change the sync interval": "GitLab sets the pull mirror " +
+				"interval instance-wide, so SetMirrorInterval returns errors.ErrUnsupported",
+		},
+	})
+}
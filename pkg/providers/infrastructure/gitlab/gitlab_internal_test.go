@@ -561,3 +561,35 @@ func TestCreateBranchWithChangesInternal(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestSelfManagedProviderInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should send API calls to the instance API base URL", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var requestedPath string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/groups/my-org/projects", func(w http.ResponseWriter, r *http.Request) {
+			requestedPath = r.URL.Path
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"id":1,"path":"repo-a","default_branch":"main",` +
+				`"http_url_to_repo":"https://gitlab.corp.example/my-org/repo-a.git"}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		provider, err := NewSelfManagedProvider("test-token", server.URL, 0)
+		require.NoError(t, err)
+
+		// when
+		repos, err := provider.DiscoverRepositories(context.Background(), "my-org")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, repos, 1)
+		assert.Equal(t, "/api/v4/groups/my-org/projects", requestedPath)
+		assert.Equal(t, "https://gitlab.corp.example/my-org/repo-a.git", repos[0].RemoteURL)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
@@ -16,14 +17,27 @@ import (
 const (
 	providerName = "gitlab"
 	perPage      = 100
+
+	// defaultWebBaseURL is the instance root used when no self-managed URL is configured.
+	defaultWebBaseURL = "https://gitlab.com"
+	defaultSSHPort    = 22
+
+	// defaultImportPollInterval is how often MigrateRepository polls the import status.
+	defaultImportPollInterval = 2 * time.Second
 )
 
 var errClientNotInitialized = errors.New("gitlab client not initialized")
 
-// Provider implements ForgeProvider, FileAccessProvider, and LocalGitAuthProvider for GitLab.
+// Provider implements ForgeProvider, FileAccessProvider, ReviewProvider, ChecksProvider, LocalGitAuthProvider,
+// MirrorProvider, MirrorLifecycleProvider, ReleaseProvider, TagProvider, IssueProvider, IssueLinkProvider,
+// WebhookProvider, and PipelineProvider for GitLab.
 type Provider struct {
-	token  string
-	client *gl.Client
+	token      string
+	webBaseURL string // empty means gitlab.com
+	sshPort    int    // 0 means the default SSH port
+	client     *gl.Client
+
+	importPollInterval time.Duration // 0 means defaultImportPollInterval
 }
 
 // NewProvider creates a new GitLab provider with the given token.
@@ -38,11 +52,52 @@ func NewProvider(token string) globalEntities.ForgeProvider {
 	}
 }
 
+// NewSelfManagedProvider creates a GitLab provider for a self-managed instance.
+// instanceURL is the instance root (e.g. "https://gitlab.corp.example"); the
+// REST API is reached under "{instanceURL}/api/v4". sshPort is the port of the
+// instance's SSH server, where 0 or 22 means the default port.
+func NewSelfManagedProvider(token, instanceURL string, sshPort int) (globalEntities.ForgeProvider, error) {
+	instanceURL = strings.TrimSuffix(instanceURL, "/")
+	parsed, err := url.Parse(instanceURL)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid GitLab instance URL %q", instanceURL)
+	}
+
+	client, err := gl.NewClient(token, gl.WithBaseURL(instanceURL))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitLab client: %w", err)
+	}
+
+	return &Provider{
+		token:      token,
+		webBaseURL: instanceURL,
+		sshPort:    sshPort,
+		client:     client,
+	}, nil
+}
+
 func (p *Provider) Name() string      { return providerName }
 func (p *Provider) AuthToken() string { return p.token }
 
 func (p *Provider) MatchesURL(rawURL string) bool {
-	return strings.Contains(rawURL, "gitlab.com")
+	return strings.Contains(rawURL, p.hostname())
+}
+
+// baseURL returns the web root of the GitLab instance.
+func (p *Provider) baseURL() string {
+	if p.webBaseURL == "" {
+		return defaultWebBaseURL
+	}
+	return p.webBaseURL
+}
+
+// hostname returns the host name of the GitLab instance, without any port.
+func (p *Provider) hostname() string {
+	parsed, err := url.Parse(p.baseURL())
+	if err != nil {
+		return "gitlab.com"
+	}
+	return parsed.Hostname()
 }
 
 // --- LocalGitAuthProvider ---
@@ -80,8 +135,8 @@ func (p *Provider) CloneURL(repo globalEntities.Repository) string {
 	remoteURL := repo.RemoteURL
 	if remoteURL == "" {
 		remoteURL = fmt.Sprintf(
-			"https://gitlab.com/%s/%s.git",
-			repo.Organization, repo.Name,
+			"%s/%s/%s.git",
+			p.baseURL(), repo.Organization, repo.Name,
 		)
 	}
 
@@ -95,10 +150,15 @@ func (p *Provider) CloneURL(repo globalEntities.Repository) string {
 	return parsed.String()
 }
 
+// SSHCloneURL returns the scp-style SSH URL, or an ssh:// URL carrying the
+// port when the instance serves SSH on a non-default port.
 func (p *Provider) SSHCloneURL(repo globalEntities.Repository, sshAlias string) string {
-	host := "gitlab.com"
+	host := p.hostname()
 	if sshAlias != "" {
-		host = fmt.Sprintf("gitlab.com-%s", sshAlias)
+		host = fmt.Sprintf("%s-%s", host, sshAlias)
+	}
+	if p.sshPort != 0 && p.sshPort != defaultSSHPort {
+		return fmt.Sprintf("ssh://git@%s:%d/%s/%s.git", host, p.sshPort, repo.Organization, repo.Name)
 	}
 	return fmt.Sprintf("git@%s:%s/%s.git", host, repo.Organization, repo.Name)
 }
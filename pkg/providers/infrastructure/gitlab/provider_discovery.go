@@ -14,23 +14,41 @@ func (p *Provider) DiscoverRepositories(
 	ctx context.Context,
 	group string,
 ) ([]globalEntities.Repository, error) {
+	projects, err := p.listProjects(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	var allRepos []globalEntities.Repository
+	for _, proj := range projects {
+		allRepos = append(allRepos, gitlabProjectToDomain(proj, group))
+	}
+	return allRepos, nil
+}
+
+// listProjects returns the projects of group and its subgroups, or the
+// projects the authenticated user owns when group cannot be listed.
+func (p *Provider) listProjects(
+	ctx context.Context,
+	group string,
+) ([]*gl.Project, error) {
 	if p.client == nil {
 		return nil, errClientNotInitialized
 	}
 
-	repos, err := p.discoverGroupProjects(ctx, group)
+	projects, err := p.listGroupProjects(ctx, group)
 	if err != nil {
 		log.Warnf("Failed to list group projects for %q, falling back to user projects: %v", group, err)
-		return p.discoverUserProjects(ctx, group)
+		return p.listUserProjects(ctx, group)
 	}
-	return repos, nil
+	return projects, nil
 }
 
-func (p *Provider) discoverGroupProjects(
+func (p *Provider) listGroupProjects(
 	ctx context.Context,
 	group string,
-) ([]globalEntities.Repository, error) {
-	var allRepos []globalEntities.Repository
+) ([]*gl.Project, error) {
+	var allProjects []*gl.Project
 	includeSubgroups := true
 	opts := &gl.ListGroupProjectsOptions{
 		PerPage:          perPage,
@@ -45,9 +63,7 @@ func (p *Provider) discoverGroupProjects(
 			return nil, fmt.Errorf("failed to list group projects: %w", err)
 		}
 
-		for _, proj := range projects {
-			allRepos = append(allRepos, gitlabProjectToDomain(proj, group))
-		}
+		allProjects = append(allProjects, projects...)
 
 		if resp.NextPage == 0 {
 			break
@@ -55,14 +71,14 @@ func (p *Provider) discoverGroupProjects(
 		opts.Page = resp.NextPage
 	}
 
-	return allRepos, nil
+	return allProjects, nil
 }
 
-func (p *Provider) discoverUserProjects(
+func (p *Provider) listUserProjects(
 	ctx context.Context,
 	user string,
-) ([]globalEntities.Repository, error) {
-	var allRepos []globalEntities.Repository
+) ([]*gl.Project, error) {
+	var allProjects []*gl.Project
 	owned := true
 	opts := &gl.ListProjectsOptions{
 		PerPage: perPage,
@@ -77,9 +93,7 @@ func (p *Provider) discoverUserProjects(
 			return nil, fmt.Errorf("failed to list projects for %q: %w", user, err)
 		}
 
-		for _, proj := range projects {
-			allRepos = append(allRepos, gitlabProjectToDomain(proj, user))
-		}
+		allProjects = append(allProjects, projects...)
 
 		if resp.NextPage == 0 {
 			break
@@ -87,7 +101,7 @@ func (p *Provider) discoverUserProjects(
 		opts.Page = resp.NextPage
 	}
 
-	return allRepos, nil
+	return allProjects, nil
 }
 
 func gitlabProjectToDomain(proj *gl.Project, org string) globalEntities.Repository {
@@ -0,0 +1,278 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+var errUserNotFound = errors.New("user not found")
+
+// CreateIssue opens an issue. Assignees are usernames, resolved to user IDs
+// one request each.
+func (p *Provider) CreateIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.IssueInput,
+) (*globalEntities.Issue, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	title := input.Title
+	description := input.Body
+	opts := &gl.CreateIssueOptions{Title: &title, Description: &description}
+	if len(input.Labels) > 0 {
+		labels := gl.LabelOptions(input.Labels)
+		opts.Labels = &labels
+	}
+	if len(input.Assignees) > 0 {
+		assigneeIDs, err := p.resolveUserIDs(ctx, input.Assignees)
+		if err != nil {
+			return nil, err
+		}
+		opts.AssigneeIDs = &assigneeIDs
+	}
+
+	issue, _, err := p.client.Issues.CreateIssue(pid, opts, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return gitlabIssueToDomain(issue), nil
+}
+
+// GetIssue returns the issue with the given project-scoped IID.
+func (p *Provider) GetIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+) (*globalEntities.Issue, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	issue, _, err := p.client.Issues.GetIssue(pid, int64(id), gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue #%d: %w", id, err)
+	}
+	return gitlabIssueToDomain(issue), nil
+}
+
+func (p *Provider) UpdateIssueState(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	state globalEntities.IssueState,
+) error {
+	stateEvent := "reopen"
+	if state == globalEntities.IssueStateClosed {
+		stateEvent = "close"
+	}
+	if err := p.updateIssue(ctx, repo, id, &gl.UpdateIssueOptions{StateEvent: &stateEvent}); err != nil {
+		return fmt.Errorf("failed to set issue #%d %s: %w", id, state, err)
+	}
+	return nil
+}
+
+func (p *Provider) AddIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	addLabels := gl.LabelOptions(labels)
+	if err := p.updateIssue(ctx, repo, id, &gl.UpdateIssueOptions{AddLabels: &addLabels}); err != nil {
+		return fmt.Errorf("failed to label issue #%d: %w", id, err)
+	}
+	return nil
+}
+
+func (p *Provider) RemoveIssueLabels(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	removeLabels := gl.LabelOptions(labels)
+	if err := p.updateIssue(ctx, repo, id, &gl.UpdateIssueOptions{RemoveLabels: &removeLabels}); err != nil {
+		return fmt.Errorf("failed to remove labels from issue #%d: %w", id, err)
+	}
+	return nil
+}
+
+func (p *Provider) AssignIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	assignees []string,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	assigneeIDs, err := p.resolveUserIDs(ctx, assignees)
+	if err != nil {
+		return err
+	}
+	if err = p.updateIssue(ctx, repo, id, &gl.UpdateIssueOptions{AssigneeIDs: &assigneeIDs}); err != nil {
+		return fmt.Errorf("failed to assign issue #%d: %w", id, err)
+	}
+	return nil
+}
+
+func (p *Provider) CommentOnIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	body string,
+) (*globalEntities.IssueComment, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	note, _, err := p.client.Notes.CreateIssueNote(
+		pid, int64(id), &gl.CreateIssueNoteOptions{Body: &body}, gl.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to comment on issue #%d: %w", id, err)
+	}
+
+	comment := &globalEntities.IssueComment{
+		ID:     note.ID,
+		Body:   note.Body,
+		Author: note.Author.Username,
+	}
+	if note.CreatedAt != nil {
+		comment.CreatedAt = *note.CreatedAt
+	}
+	return comment, nil
+}
+
+// SearchIssues lists the project's issues matching query. Text is searched in
+// titles and descriptions.
+func (p *Provider) SearchIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.IssueQuery,
+) ([]globalEntities.Issue, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	orderBy := "updated_at"
+	sort := "desc"
+	opts := &gl.ListProjectIssuesOptions{
+		ListOptions: gl.ListOptions{PerPage: perPage},
+		OrderBy:     &orderBy,
+		Sort:        &sort,
+	}
+	switch query.State {
+	case globalEntities.IssueStateOpen:
+		state := "opened"
+		opts.State = &state
+	case globalEntities.IssueStateClosed:
+		state := "closed"
+		opts.State = &state
+	}
+	if len(query.Labels) > 0 {
+		labels := gl.LabelOptions(query.Labels)
+		opts.Labels = &labels
+	}
+	if query.Assignee != "" {
+		assignee := query.Assignee
+		opts.AssigneeUsername = &assignee
+	}
+	if query.Text != "" {
+		search := query.Text
+		opts.Search = &search
+	}
+
+	var allIssues []globalEntities.Issue
+	for {
+		issues, resp, err := p.client.Issues.ListProjectIssues(pid, opts, gl.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to search issues: %w", err)
+		}
+
+		for _, issue := range issues {
+			allIssues = append(allIssues, *gitlabIssueToDomain(issue))
+			if query.Limit > 0 && len(allIssues) == query.Limit {
+				return allIssues, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allIssues, nil
+}
+
+func (p *Provider) updateIssue(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id int,
+	opts *gl.UpdateIssueOptions,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	_, _, err := p.client.Issues.UpdateIssue(pid, int64(id), opts, gl.WithContext(ctx))
+	return err
+}
+
+// resolveUserIDs maps usernames to the user IDs the issues API assigns by.
+func (p *Provider) resolveUserIDs(ctx context.Context, usernames []string) ([]int64, error) {
+	userIDs := make([]int64, 0, len(usernames))
+	for _, username := range usernames {
+		users, _, err := p.client.Users.ListUsers(
+			&gl.ListUsersOptions{Username: &username}, gl.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up user %q: %w", username, err)
+		}
+		if len(users) == 0 {
+			return nil, fmt.Errorf("%w: %q", errUserNotFound, username)
+		}
+		userIDs = append(userIDs, users[0].ID)
+	}
+	return userIDs, nil
+}
+
+func gitlabIssueToDomain(issue *gl.Issue) *globalEntities.Issue {
+	result := &globalEntities.Issue{
+		ID:     int(issue.IID),
+		Title:  issue.Title,
+		Body:   issue.Description,
+		State:  globalEntities.IssueStateOpen,
+		Labels: issue.Labels,
+		URL:    issue.WebURL,
+	}
+	if issue.State == "closed" {
+		result.State = globalEntities.IssueStateClosed
+	}
+	if issue.Author != nil {
+		result.Author = issue.Author.Username
+	}
+	for _, assignee := range issue.Assignees {
+		result.Assignees = append(result.Assignees, assignee.Username)
+	}
+	if issue.CreatedAt != nil {
+		result.CreatedAt = *issue.CreatedAt
+	}
+	if issue.UpdatedAt != nil {
+		result.UpdatedAt = *issue.UpdatedAt
+	}
+	return result
+}
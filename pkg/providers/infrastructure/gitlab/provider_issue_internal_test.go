@@ -0,0 +1,129 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateIssueInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create the issue with its assignees resolved to user IDs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "dev", r.URL.Query().Get("username"))
+			_, _ = w.Write([]byte(`[{"id":17,"username":"dev"}]`))
+		})
+		mux.HandleFunc("POST /api/v4/projects/my-group%2Fmy-repo/issues", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":900,"iid":5,"title":"Autoupdate failed","state":"opened",
+				"labels":["autoupdate"],"assignees":[{"username":"dev"}],"author":{"username":"bot"},
+				"web_url":"https://gitlab.com/my-group/my-repo/-/issues/5"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		issue, err := p.CreateIssue(context.Background(), repo, globalEntities.IssueInput{
+			Title: "Autoupdate failed", Labels: []string{"autoupdate"}, Assignees: []string{"dev"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []any{float64(17)}, body["assignee_ids"])
+		assert.Equal(t, "autoupdate", body["labels"])
+		assert.Equal(t, &globalEntities.Issue{
+			ID:        5,
+			Title:     "Autoupdate failed",
+			State:     globalEntities.IssueStateOpen,
+			Labels:    []string{"autoupdate"},
+			Assignees: []string{"dev"},
+			Author:    "bot",
+			URL:       "https://gitlab.com/my-group/my-repo/-/issues/5",
+		}, issue)
+	})
+}
+
+func TestUpdateIssueStateInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should close the issue through a state event", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("PUT /api/v4/projects/my-group%2Fmy-repo/issues/5", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"id":900,"iid":5,"state":"closed"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		err := p.UpdateIssueState(context.Background(), repo, 5, globalEntities.IssueStateClosed)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"state_event": "close"}, body)
+	})
+}
+
+func TestSearchIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list the matching issues most recently updated first", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var query map[string]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/my-group%2Fmy-repo/issues", func(w http.ResponseWriter, r *http.Request) {
+			query = map[string]string{}
+			for key := range r.URL.Query() {
+				query[key] = r.URL.Query().Get(key)
+			}
+			_, _ = w.Write([]byte(`[{"id":90,"iid":9,"state":"closed"},{"id":40,"iid":4,"state":"closed"}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		issues, err := p.SearchIssues(context.Background(), repo, globalEntities.IssueQuery{
+			State: globalEntities.IssueStateClosed, Labels: []string{"autoupdate"}, Text: "failed", Limit: 1,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "closed", query["state"])
+		assert.Equal(t, "autoupdate", query["labels"])
+		assert.Equal(t, "failed", query["search"])
+		assert.Equal(t, "updated_at", query["order_by"])
+		assert.Equal(t, "desc", query["sort"])
+		require.Len(t, issues, 1)
+		assert.Equal(t, 9, issues[0].ID)
+		assert.Equal(t, globalEntities.IssueStateClosed, issues[0].State)
+	})
+}
@@ -0,0 +1,103 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+// LinkPullRequestIssues appends closing patterns ("Closes #n") and mentions
+// ("Refs #n") to the merge request description. GitLab closes the former when
+// the merge request merges into the default branch and lists the latter as
+// related.
+func (p *Provider) LinkPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	links []globalEntities.IssueLink,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	mr, _, err := p.client.MergeRequests.GetMergeRequest(pid, int64(prID), nil, gl.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get merge request !%d: %w", prID, err)
+	}
+
+	description := globalEntities.AppendIssueReferences(mr.Description, links)
+	if description == mr.Description {
+		return nil
+	}
+
+	if _, _, err = p.client.MergeRequests.UpdateMergeRequest(
+		pid, int64(prID),
+		&gl.UpdateMergeRequestOptions{Description: &description},
+		gl.WithContext(ctx),
+	); err != nil {
+		return fmt.Errorf("failed to link merge request !%d: %w", prID, err)
+	}
+	return nil
+}
+
+// ListPullRequestIssues returns the issues the merge request closes on merge,
+// followed by the other issues it is related to.
+func (p *Provider) ListPullRequestIssues(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.IssueLink, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	var links []globalEntities.IssueLink
+	closing := map[int64]bool{}
+
+	closesOpts := &gl.GetIssuesClosedOnMergeOptions{ListOptions: gl.ListOptions{PerPage: perPage}}
+	for {
+		issues, resp, err := p.client.MergeRequests.GetIssuesClosedOnMerge(
+			pid, int64(prID), closesOpts, gl.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues closed by merge request !%d: %w", prID, err)
+		}
+
+		for _, issue := range issues {
+			closing[issue.IID] = true
+			links = append(links, globalEntities.IssueLink{ID: int(issue.IID), Closes: true})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		closesOpts.Page = resp.NextPage
+	}
+
+	relatedOpts := &gl.ListRelatedIssuesOptions{ListOptions: gl.ListOptions{PerPage: perPage}}
+	for {
+		issues, resp, err := p.client.MergeRequests.ListRelatedIssues(
+			pid, int64(prID), relatedOpts, gl.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues related to merge request !%d: %w", prID, err)
+		}
+
+		for _, issue := range issues {
+			if !closing[issue.IID] {
+				links = append(links, globalEntities.IssueLink{ID: int(issue.IID)})
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		relatedOpts.Page = resp.NextPage
+	}
+
+	return links, nil
+}
@@ -0,0 +1,88 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestLinkPullRequestIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should append closing patterns and mentions to the description", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc(
+			"GET /api/v4/projects/my-group%2Fmy-repo/merge_requests/7",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"id":1,"iid":7,"description":"Adds the feature."}`))
+			},
+		)
+		mux.HandleFunc(
+			"PUT /api/v4/projects/my-group%2Fmy-repo/merge_requests/7",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				_, _ = w.Write([]byte(`{"id":1,"iid":7}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		err := p.LinkPullRequestIssues(context.Background(), repo, 7, []globalEntities.IssueLink{
+			{ID: 41, Closes: true}, {ID: 42},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Adds the feature.\n\nCloses #41\nRefs #42", body["description"])
+	})
+}
+
+func TestListPullRequestIssuesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return closing issues before the other related ones", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc(
+			"GET /api/v4/projects/my-group%2Fmy-repo/merge_requests/7/closes_issues",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`[{"id":101,"iid":41}]`))
+			},
+		)
+		mux.HandleFunc(
+			"GET /api/v4/projects/my-group%2Fmy-repo/merge_requests/7/related_issues",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`[{"id":101,"iid":41},{"id":102,"iid":42}]`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		links, err := p.ListPullRequestIssues(context.Background(), repo, 7)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []globalEntities.IssueLink{{ID: 41, Closes: true}, {ID: 42}}, links)
+	})
+}
@@ -0,0 +1,134 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+var errImportFailed = errors.New("project import failed")
+
+// GitLab import_status values of a project created from an import_url.
+const (
+	importStatusNone      = "none"
+	importStatusScheduled = "scheduled"
+	importStatusStarted   = "started"
+	importStatusFinished  = "finished"
+	importStatusFailed    = "failed"
+)
+
+// MigrateRepository creates a project in the RepoOwner namespace imported from
+// input.CloneAddr and waits for the import to finish. When Mirror is true the
+// project is created as a pull mirror, which GitLab keeps in sync with the
+// source. Source credentials are passed as the userinfo of the import URL.
+func (p *Provider) MigrateRepository(
+	ctx context.Context,
+	input globalEntities.MirrorInput,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	namespace, _, err := p.client.Namespaces.GetNamespace(input.RepoOwner, gl.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to resolve namespace %q: %w", input.RepoOwner, err)
+	}
+
+	importURL, err := withCredentials(input.CloneAddr, input.AuthUsername, input.AuthPassword)
+	if err != nil {
+		return err
+	}
+
+	visibility := gl.PublicVisibility
+	if input.Private {
+		visibility = gl.PrivateVisibility
+	}
+	project, _, err := p.client.Projects.CreateProject(&gl.CreateProjectOptions{
+		Name:        &input.RepoName,
+		Path:        &input.RepoName,
+		NamespaceID: &namespace.ID,
+		Description: &input.Description,
+		ImportURL:   &importURL,
+		Mirror:      &input.Mirror,
+		Visibility:  &visibility,
+	}, gl.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to create project %q: %w", input.RepoName, err)
+	}
+
+	return p.waitForImport(ctx, project, input)
+}
+
+// waitForImport polls the project until its import finishes or fails,
+// reporting every status change.
+func (p *Provider) waitForImport(
+	ctx context.Context,
+	project *gl.Project,
+	input globalEntities.MirrorInput,
+) error {
+	interval := p.importPollInterval
+	if interval == 0 {
+		interval = defaultImportPollInterval
+	}
+
+	lastStatus := ""
+	for {
+		if project.ImportStatus != lastStatus {
+			lastStatus = project.ImportStatus
+			reportImportStatus(input, project)
+		}
+
+		switch project.ImportStatus {
+		case importStatusFinished, importStatusNone:
+			return nil
+		case importStatusFailed:
+			return fmt.Errorf("%w: %s: %s", errImportFailed, project.PathWithNamespace, project.ImportError)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for import of %q: %w", project.PathWithNamespace, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		var err error
+		project, _, err = p.client.Projects.GetProject(project.ID, nil, gl.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to get import status of %q: %w", input.RepoName, err)
+		}
+	}
+}
+
+// reportImportStatus maps a GitLab import_status onto a mirror progress report.
+func reportImportStatus(input globalEntities.MirrorInput, project *gl.Project) {
+	switch project.ImportStatus {
+	case importStatusScheduled:
+		input.Report(globalEntities.MirrorStateQueued, importStatusScheduled)
+	case importStatusStarted:
+		input.Report(globalEntities.MirrorStateRunning, importStatusStarted)
+	case importStatusFailed:
+		input.Report(globalEntities.MirrorStateFailed, project.ImportError)
+	default:
+		input.Report(globalEntities.MirrorStateCompleted, project.ImportStatus)
+	}
+}
+
+// withCredentials returns rawURL with username and password set as its
+// userinfo, or rawURL unchanged when both are empty.
+func withCredentials(rawURL, username, password string) (string, error) {
+	if username == "" && password == "" {
+		return rawURL, nil
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid import URL %q: %w", rawURL, err)
+	}
+	parsed.User = url.UserPassword(username, password)
+	return parsed.String(), nil
+}
@@ -0,0 +1,105 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+// ListMirrors returns the pull mirrors of group, with the sync status GitLab
+// reports for each. GitLab sets the sync interval instance-wide, so Interval
+// is always zero.
+func (p *Provider) ListMirrors(
+	ctx context.Context,
+	group string,
+) ([]globalEntities.Mirror, error) {
+	projects, err := p.listProjects(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	var mirrors []globalEntities.Mirror
+	for _, proj := range projects {
+		if !proj.Mirror {
+			continue
+		}
+
+		details, _, detailsErr := p.client.Projects.GetProjectPullMirrorDetails(proj.ID, gl.WithContext(ctx))
+		if detailsErr != nil {
+			return nil, fmt.Errorf("failed to get pull mirror details of %q: %w", proj.Path, detailsErr)
+		}
+
+		mirror := globalEntities.Mirror{
+			Repository: gitlabProjectToDomain(proj, group),
+			SourceURL:  withoutCredentials(details.URL),
+			LastError:  details.LastError,
+		}
+		if details.LastSuccessfulUpdateAt != nil {
+			mirror.LastSync = *details.LastSuccessfulUpdateAt
+		}
+		mirrors = append(mirrors, mirror)
+	}
+	return mirrors, nil
+}
+
+// SyncMirror starts an immediate pull through the mirror/pull endpoint.
+func (p *Provider) SyncMirror(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	if _, err := p.client.Projects.StartMirroringProject(pid, gl.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to sync mirror %q: %w", repo.Name, err)
+	}
+	return nil
+}
+
+// SetMirrorInterval is not supported: GitLab administrators set the pull
+// mirror interval for the whole instance.
+func (p *Provider) SetMirrorInterval(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ time.Duration,
+) error {
+	return fmt.Errorf("GitLab sets the pull mirror interval instance-wide: %w", errors.ErrUnsupported)
+}
+
+// ConvertMirror disables pull mirroring, leaving a normal project with the
+// contents of the last sync.
+func (p *Provider) ConvertMirror(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	enabled := false
+	_, _, err := p.client.Projects.ConfigureProjectPullMirror(
+		pid, &gl.ConfigureProjectPullMirrorOptions{Enabled: &enabled}, gl.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to convert mirror %q: %w", repo.Name, err)
+	}
+	return nil
+}
+
+// withoutCredentials strips any userinfo from rawURL.
+func withoutCredentials(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.User == nil {
+		return rawURL
+	}
+	parsed.User = nil
+	return parsed.String()
+}
@@ -0,0 +1,326 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+// TriggerPipeline creates a pipeline on input.Ref. GitLab runs the project's
+// single pipeline configuration, so input.Pipeline is ignored; inputs must be
+// declared in the configuration's spec:inputs.
+func (p *Provider) TriggerPipeline(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PipelineRunInput,
+) (*globalEntities.PipelineRun, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	ref := input.Ref
+	if ref == "" {
+		ref = strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")
+	}
+	opts := &gl.CreatePipelineOptions{Ref: &ref}
+	if len(input.Variables) > 0 {
+		variableType := gl.EnvVariableType
+		variables := make([]*gl.PipelineVariableOptions, 0, len(input.Variables))
+		for _, key := range slices.Sorted(maps.Keys(input.Variables)) {
+			value := input.Variables[key]
+			variables = append(variables, &gl.PipelineVariableOptions{
+				Key: &key, Value: &value, VariableType: &variableType,
+			})
+		}
+		opts.Variables = &variables
+	}
+	if len(input.Inputs) > 0 {
+		opts.Inputs = gl.PipelineInputsOption{}
+		for key, value := range input.Inputs {
+			opts.Inputs[key] = gl.NewPipelineInputValue(value)
+		}
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	pipeline, _, err := p.client.Pipelines.CreatePipeline(pid, opts, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipeline on %s: %w", ref, err)
+	}
+	return gitlabPipelineToDomain(pipeline), nil
+}
+
+func (p *Provider) ListPipelineRuns(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	query globalEntities.PipelineRunQuery,
+) ([]globalEntities.PipelineRun, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	orderBy, sort := "id", "desc"
+	opts := &gl.ListProjectPipelinesOptions{
+		ListOptions: gl.ListOptions{PerPage: perPage},
+		OrderBy:     &orderBy,
+		Sort:        &sort,
+	}
+	if query.Branch != "" {
+		opts.Ref = &query.Branch
+	}
+	if query.CommitSHA != "" {
+		opts.SHA = &query.CommitSHA
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	var runs []globalEntities.PipelineRun
+	for {
+		pipelines, resp, err := p.client.Pipelines.ListProjectPipelines(pid, opts, gl.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pipelines: %w", err)
+		}
+		for _, pipeline := range pipelines {
+			runs = append(runs, gitlabPipelineInfoToDomain(pipeline))
+			if query.Limit > 0 && len(runs) == query.Limit {
+				return runs, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return runs, nil
+}
+
+func (p *Provider) GetPipelineRun(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	id, err := parsePipelineID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	pipeline, _, err := p.client.Pipelines.GetPipeline(pid, id, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline %s: %w", runID, err)
+	}
+	return gitlabPipelineToDomain(pipeline), nil
+}
+
+// ListPipelineJobs returns the pipeline's jobs, leaving out the attempts that
+// were retried.
+func (p *Provider) ListPipelineJobs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) ([]globalEntities.PipelineJob, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	id, err := parsePipelineID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	opts := &gl.ListJobsOptions{ListOptions: gl.ListOptions{PerPage: perPage}}
+	var jobs []globalEntities.PipelineJob
+	for {
+		page, resp, listErr := p.client.Jobs.ListPipelineJobs(pid, id, opts, gl.WithContext(ctx))
+		if listErr != nil {
+			return nil, fmt.Errorf("failed to list jobs of pipeline %s: %w", runID, listErr)
+		}
+		for _, job := range page {
+			jobs = append(jobs, globalEntities.PipelineJob{
+				ID:         strconv.FormatInt(job.ID, 10),
+				Name:       job.Name,
+				Status:     gitlabPipelineStatus(job.Status),
+				URL:        job.WebURL,
+				StartedAt:  timeOrZero(job.StartedAt),
+				FinishedAt: timeOrZero(job.FinishedAt),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return jobs, nil
+}
+
+func (p *Provider) CancelPipelineRun(ctx context.Context, repo globalEntities.Repository, runID string) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+	id, err := parsePipelineID(runID)
+	if err != nil {
+		return err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	if _, _, err = p.client.Pipelines.CancelPipelineBuild(pid, id, gl.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to cancel pipeline %s: %w", runID, err)
+	}
+	return nil
+}
+
+func (p *Provider) RetryPipelineRun(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	id, err := parsePipelineID(runID)
+	if err != nil {
+		return nil, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	pipeline, _, err := p.client.Pipelines.RetryPipelineBuild(pid, id, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry pipeline %s: %w", runID, err)
+	}
+	return gitlabPipelineToDomain(pipeline), nil
+}
+
+// GetPipelineJobLogs streams the job's trace. Job IDs are unique in the
+// project, so runID is not needed.
+func (p *Provider) GetPipelineJobLogs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	_, jobID string,
+) (io.ReadCloser, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	id, err := strconv.ParseInt(jobID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job ID %q: %w", jobID, err)
+	}
+
+	path := fmt.Sprintf("projects/%s/jobs/%d/trace", gl.PathEscape(repo.Organization+"/"+repo.Name), id)
+	req, err := p.client.NewRequest(http.MethodGet, path, nil, []gl.RequestOptionFunc{gl.WithContext(ctx)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build job trace request: %w", err)
+	}
+
+	// Do copies the body into a writer, so the trace is piped to the caller
+	// as it arrives instead of being buffered whole. The first write comes
+	// after the response status is checked, which is when the reader is
+	// handed out; errors before it are returned directly.
+	reader, writer := io.Pipe()
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, doErr := p.client.Do(req, &startNotifyingWriter{writer: writer, started: started})
+		if doErr != nil {
+			doErr = fmt.Errorf("failed to get trace of job %s: %w", jobID, doErr)
+		}
+		done <- doErr
+		_ = writer.CloseWithError(doErr)
+	}()
+
+	select {
+	case <-started:
+		return reader, nil
+	case doErr := <-done:
+		if doErr != nil {
+			return nil, doErr
+		}
+		return reader, nil
+	}
+}
+
+// startNotifyingWriter closes started on the first write to writer.
+type startNotifyingWriter struct {
+	writer  io.Writer
+	started chan struct{}
+	once    sync.Once
+}
+
+func (w *startNotifyingWriter) Write(b []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	return w.writer.Write(b)
+}
+
+func parsePipelineID(id string) (int64, error) {
+	pipelineID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pipeline ID %q: %w", id, err)
+	}
+	return pipelineID, nil
+}
+
+func gitlabPipelineToDomain(pipeline *gl.Pipeline) *globalEntities.PipelineRun {
+	return &globalEntities.PipelineRun{
+		ID:         strconv.FormatInt(pipeline.ID, 10),
+		Pipeline:   pipeline.Name,
+		Ref:        pipeline.Ref,
+		CommitSHA:  pipeline.SHA,
+		Status:     gitlabPipelineStatus(pipeline.Status),
+		URL:        pipeline.WebURL,
+		CreatedAt:  timeOrZero(pipeline.CreatedAt),
+		FinishedAt: timeOrZero(pipeline.FinishedAt),
+	}
+}
+
+// gitlabPipelineInfoToDomain maps a listed pipeline, which carries no
+// completion time: the last update is when a finished pipeline finished.
+func gitlabPipelineInfoToDomain(pipeline *gl.PipelineInfo) globalEntities.PipelineRun {
+	run := globalEntities.PipelineRun{
+		ID:        strconv.FormatInt(pipeline.ID, 10),
+		Pipeline:  pipeline.Name,
+		Ref:       pipeline.Ref,
+		CommitSHA: pipeline.SHA,
+		Status:    gitlabPipelineStatus(pipeline.Status),
+		URL:       pipeline.WebURL,
+		CreatedAt: timeOrZero(pipeline.CreatedAt),
+	}
+	if run.Status.IsFinished() {
+		run.FinishedAt = timeOrZero(pipeline.UpdatedAt)
+	}
+	return run
+}
+
+// gitlabPipelineStatus maps the status of a pipeline or job.
+func gitlabPipelineStatus(status string) globalEntities.PipelineStatus {
+	switch status {
+	case "running", "canceling":
+		return globalEntities.PipelineStatusRunning
+	case "success":
+		return globalEntities.PipelineStatusSuccess
+	case "failed":
+		return globalEntities.PipelineStatusFailure
+	case "canceled":
+		return globalEntities.PipelineStatusCanceled
+	case "skipped":
+		return globalEntities.PipelineStatusSkipped
+	default: // created, waiting_for_resource, preparing, pending, manual, scheduled
+		return globalEntities.PipelineStatusQueued
+	}
+}
+
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
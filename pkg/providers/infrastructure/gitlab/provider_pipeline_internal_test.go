@@ -0,0 +1,198 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestTriggerPipelineInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create the pipeline with its variables and inputs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v4/projects/my-group%2Fmy-repo/pipeline", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":88,"ref":"main","sha":"abc123","status":"created",
+				"web_url":"https://gitlab.com/my-group/my-repo/-/pipelines/88","created_at":"2026-01-02T10:00:00Z"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo", DefaultBranch: "refs/heads/main"}
+
+		// when
+		run, err := p.TriggerPipeline(context.Background(), repo, globalEntities.PipelineRunInput{
+			Inputs:    map[string]string{"environment": "staging"},
+			Variables: map[string]string{"DEBUG": "1", "APP": "web"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "main", body["ref"])
+		assert.Equal(t, []any{
+			map[string]any{"key": "APP", "value": "web", "variable_type": "env_var"},
+			map[string]any{"key": "DEBUG", "value": "1", "variable_type": "env_var"},
+		}, body["variables"])
+		assert.Equal(t, map[string]any{"environment": "staging"}, body["inputs"])
+		assert.Equal(t, &globalEntities.PipelineRun{
+			ID:        "88",
+			Ref:       "main",
+			CommitSHA: "abc123",
+			Status:    globalEntities.PipelineStatusQueued,
+			URL:       "https://gitlab.com/my-group/my-repo/-/pipelines/88",
+			CreatedAt: time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+		}, run)
+	})
+}
+
+func TestListPipelineRunsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should filter by ref and commit, newest first, up to the limit", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var query map[string]string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/my-group%2Fmy-repo/pipelines", func(w http.ResponseWriter, r *http.Request) {
+			query = map[string]string{
+				"ref":      r.URL.Query().Get("ref"),
+				"sha":      r.URL.Query().Get("sha"),
+				"order_by": r.URL.Query().Get("order_by"),
+				"sort":     r.URL.Query().Get("sort"),
+			}
+			_, _ = w.Write([]byte(`[{"id":90,"ref":"feature","sha":"abc123","status":"failed",
+				"updated_at":"2026-01-02T10:05:00Z"},{"id":89,"status":"success"}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		runs, err := p.ListPipelineRuns(context.Background(), repo, globalEntities.PipelineRunQuery{
+			Branch: "feature", CommitSHA: "abc123", Limit: 1,
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"ref": "feature", "sha": "abc123", "order_by": "id", "sort": "desc"}, query)
+		assert.Equal(t, []globalEntities.PipelineRun{{
+			ID:         "90",
+			Ref:        "feature",
+			CommitSHA:  "abc123",
+			Status:     globalEntities.PipelineStatusFailure,
+			FinishedAt: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC),
+		}}, runs)
+	})
+}
+
+func TestListPipelineJobsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should map the status of each job", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/my-group%2Fmy-repo/pipelines/90/jobs",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`[{"id":1,"name":"build","status":"success"},
+					{"id":2,"name":"test","status":"canceling"},
+					{"id":3,"name":"deploy","status":"manual"},
+					{"id":4,"name":"lint","status":"failed","web_url":"https://gitlab.com/my-group/my-repo/-/jobs/4"}]`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		jobs, err := p.ListPipelineJobs(context.Background(), repo, "90")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, jobs, 4)
+		assert.Equal(t, globalEntities.PipelineStatusSuccess, jobs[0].Status)
+		assert.Equal(t, globalEntities.PipelineStatusRunning, jobs[1].Status)
+		assert.Equal(t, globalEntities.PipelineStatusQueued, jobs[2].Status)
+		assert.Equal(t, globalEntities.PipelineJob{
+			ID:     "4",
+			Name:   "lint",
+			Status: globalEntities.PipelineStatusFailure,
+			URL:    "https://gitlab.com/my-group/my-repo/-/jobs/4",
+		}, jobs[3])
+	})
+}
+
+func TestGetPipelineJobLogsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should stream the job trace", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/my-group%2Fmy-repo/jobs/4/trace",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("$ make lint\nerror\n"))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		logs, err := p.GetPipelineJobLogs(context.Background(), repo, "90", "4")
+
+		// then
+		require.NoError(t, err)
+		defer logs.Close()
+		content, readErr := io.ReadAll(logs)
+		require.NoError(t, readErr)
+		assert.Equal(t, "$ make lint\nerror\n", string(content))
+	})
+
+	t.Run("should return the error of an unknown job before streaming", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/my-group%2Fmy-repo/jobs/5/trace",
+			func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"404 Not found"}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		logs, err := p.GetPipelineJobLogs(context.Background(), repo, "90", "5")
+
+		// then
+		require.Error(t, err)
+		assert.Nil(t, logs)
+	})
+}
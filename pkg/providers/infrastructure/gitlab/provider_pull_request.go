@@ -23,7 +23,7 @@ func (p *Provider) CreatePullRequest(
 	targetBranch := strings.TrimPrefix(input.TargetBranch, "refs/heads/")
 
 	title := input.Title
-	description := input.Description
+	description := globalEntities.AppendIssueReferences(input.Description, input.IssueLinks)
 	removeSourceBranch := true
 	mr, _, err := p.client.MergeRequests.CreateMergeRequest(
 		pid,
@@ -0,0 +1,235 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+// CreateRelease publishes a GitLab release, creating the tag on input.Target
+// (or the default branch) when it does not exist yet. GitLab releases have no
+// draft or prerelease state, and the latest release is the one most recently
+// released, which a new release is.
+func (p *Provider) CreateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	if err := checkReleaseFlags(input); err != nil {
+		return nil, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	name := input.Name
+	tagName := input.TagName
+	description := input.Body
+	opts := &gl.CreateReleaseOptions{
+		Name:        &name,
+		TagName:     &tagName,
+		Description: &description,
+	}
+	ref := input.Target
+	if ref == "" {
+		ref = strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")
+	}
+	if ref != "" {
+		opts.Ref = &ref
+	}
+
+	release, _, err := p.client.Releases.CreateRelease(pid, opts, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release %q: %w", input.TagName, err)
+	}
+	return gitlabReleaseToDomain(release), nil
+}
+
+// UpdateRelease changes the name and description of the release of
+// input.TagName. Latest moves its release date to now, which makes it the
+// latest release.
+func (p *Provider) UpdateRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	if err := checkReleaseFlags(input); err != nil {
+		return nil, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	name := input.Name
+	description := input.Body
+	opts := &gl.UpdateReleaseOptions{
+		Name:        &name,
+		Description: &description,
+	}
+	if input.Latest {
+		releasedAt := time.Now().UTC()
+		opts.ReleasedAt = &releasedAt
+	}
+
+	release, _, err := p.client.Releases.UpdateRelease(pid, input.TagName, opts, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update release %q: %w", input.TagName, err)
+	}
+	return gitlabReleaseToDomain(release), nil
+}
+
+func (p *Provider) GetRelease(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+) (*globalEntities.Release, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	release, _, err := p.client.Releases.GetRelease(pid, tagName, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get release %q: %w", tagName, err)
+	}
+	return gitlabReleaseToDomain(release), nil
+}
+
+func (p *Provider) ListReleases(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Release, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	var allReleases []globalEntities.Release
+	opts := &gl.ListReleasesOptions{ListOptions: gl.ListOptions{PerPage: perPage}}
+
+	for {
+		releases, resp, err := p.client.Releases.ListReleases(pid, opts, gl.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list releases: %w", err)
+		}
+
+		for _, release := range releases {
+			allReleases = append(allReleases, *gitlabReleaseToDomain(release))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allReleases, nil
+}
+
+// UploadReleaseAsset uploads the file to the project and links it from the
+// release of tagName. The asset ID is the ID of the release link.
+func (p *Provider) UploadReleaseAsset(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName string,
+	asset globalEntities.ReleaseAssetInput,
+) (*globalEntities.ReleaseAsset, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	uploaded, _, err := p.client.ProjectMarkdownUploads.UploadProjectMarkdown(
+		pid, asset.Content, asset.Name, gl.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload asset %q: %w", asset.Name, err)
+	}
+
+	// full_path is absolute on current GitLab; older instances only return a
+	// URL relative to the project.
+	fileURL := p.baseURL() + uploaded.FullPath
+	if uploaded.FullPath == "" {
+		fileURL = p.baseURL() + "/" + pid + uploaded.URL
+	}
+
+	name := asset.Name
+	linkType := gl.OtherLinkType
+	link, _, err := p.client.ReleaseLinks.CreateReleaseLink(pid, tagName, &gl.CreateReleaseLinkOptions{
+		Name:     &name,
+		URL:      &fileURL,
+		LinkType: &linkType,
+	}, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to link asset %q to release %q: %w", asset.Name, tagName, err)
+	}
+	return gitlabLinkToDomain(link), nil
+}
+
+// DeleteReleaseAsset removes the release link. The uploaded file itself stays
+// in the project's uploads.
+func (p *Provider) DeleteReleaseAsset(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	tagName, assetID string,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	id, err := strconv.ParseInt(assetID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid asset ID %q: %w", assetID, err)
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	if _, _, err = p.client.ReleaseLinks.DeleteReleaseLink(pid, tagName, id, gl.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to delete asset %s: %w", assetID, err)
+	}
+	return nil
+}
+
+// checkReleaseFlags rejects the release states GitLab cannot represent.
+func checkReleaseFlags(input globalEntities.ReleaseInput) error {
+	if input.Draft || input.Prerelease {
+		return fmt.Errorf("GitLab releases cannot be drafts or prereleases: %w", errors.ErrUnsupported)
+	}
+	return nil
+}
+
+func gitlabReleaseToDomain(release *gl.Release) *globalEntities.Release {
+	result := &globalEntities.Release{
+		ID:      release.TagName,
+		TagName: release.TagName,
+		Name:    release.Name,
+		Body:    release.Description,
+		URL:     release.Links.Self,
+	}
+	if release.CreatedAt != nil {
+		result.CreatedAt = *release.CreatedAt
+	}
+	for _, link := range release.Assets.Links {
+		result.Assets = append(result.Assets, *gitlabLinkToDomain(link))
+	}
+	return result
+}
+
+func gitlabLinkToDomain(link *gl.ReleaseLink) *globalEntities.ReleaseAsset {
+	downloadURL := link.DirectAssetURL
+	if downloadURL == "" {
+		downloadURL = link.URL
+	}
+	return &globalEntities.ReleaseAsset{
+		ID:          strconv.FormatInt(link.ID, 10),
+		Name:        link.Name,
+		DownloadURL: downloadURL,
+	}
+}
@@ -0,0 +1,116 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateReleaseInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create the tag on the default branch when no target is given", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v4/projects/my-group%2Fmy-repo/releases", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"tag_name":"v1.0.0","name":"First","description":"notes",
+				"_links":{"self":"https://gitlab.com/my-group/my-repo/-/releases/v1.0.0"}}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo", DefaultBranch: "refs/heads/main"}
+
+		// when
+		release, err := p.CreateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0", Name: "First", Body: "notes",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "main", body["ref"])
+		assert.Equal(t, "notes", body["description"])
+		assert.Equal(t, "v1.0.0", release.ID)
+		assert.Equal(t, "https://gitlab.com/my-group/my-repo/-/releases/v1.0.0", release.URL)
+	})
+
+	t.Run("should reject drafts as unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		_, err := p.CreateRelease(context.Background(), repo, globalEntities.ReleaseInput{
+			TagName: "v1.0.0", Draft: true,
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestUploadReleaseAssetInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should upload the file and link it from the release", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var link map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v4/projects/my-group%2Fmy-repo/uploads", func(w http.ResponseWriter, r *http.Request) {
+			file, header, err := r.FormFile("file")
+			if err == nil {
+				defer file.Close()
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"url":"/uploads/abc/` + header.Filename + `",
+					"full_path":"/-/project/1/uploads/abc/` + header.Filename + `"}`))
+			}
+		})
+		mux.HandleFunc(
+			"POST /api/v4/projects/my-group%2Fmy-repo/releases/v1.0.0/assets/links",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&link)
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":5,"name":"app.zip","url":"` + link["url"].(string) + `"}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		asset, err := p.UploadReleaseAsset(context.Background(), repo, "v1.0.0", globalEntities.ReleaseAssetInput{
+			Name: "app.zip", Content: strings.NewReader("zip"),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://gitlab.com/-/project/1/uploads/abc/app.zip", link["url"])
+		assert.Equal(t, "other", link["link_type"])
+		assert.Equal(t, "5", asset.ID)
+		assert.Equal(t, "https://gitlab.com/-/project/1/uploads/abc/app.zip", asset.DownloadURL)
+	})
+}
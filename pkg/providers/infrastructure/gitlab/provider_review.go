@@ -0,0 +1,750 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+// errDiscussionNotFound is returned when a thread ID (the root note ID of a
+// discussion) does not match any discussion on the merge request.
+var errDiscussionNotFound = errors.New("merge request discussion not found")
+
+// mrStateOpened is the GitLab merge request state for open merge requests.
+const mrStateOpened = "opened"
+
+// Pipeline statuses that GetPullRequestCheckStatus treats as passing.
+const (
+	pipelineStatusSuccess = "success"
+	pipelineStatusSkipped = "skipped"
+)
+
+// --- ReviewProvider ---
+
+func (p *Provider) ListOpenPullRequests(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.PullRequestDetail, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	state := mrStateOpened
+	opts := &gl.ListProjectMergeRequestsOptions{
+		ListOptions: gl.ListOptions{PerPage: perPage},
+		State:       &state,
+	}
+
+	var allMRs []globalEntities.PullRequestDetail
+	for {
+		mrs, resp, err := p.client.MergeRequests.ListProjectMergeRequests(
+			pid, opts, gl.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list open merge requests: %w", err)
+		}
+
+		for _, mr := range mrs {
+			author := ""
+			if mr.Author != nil {
+				author = mr.Author.Username
+			}
+			allMRs = append(allMRs, globalEntities.PullRequestDetail{
+				ID:           int(mr.IID),
+				Title:        mr.Title,
+				URL:          mr.WebURL,
+				Status:       mr.State,
+				SourceBranch: mr.SourceBranch,
+				TargetBranch: mr.TargetBranch,
+				Author:       author,
+				IsDraft:      mr.Draft,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return allMRs, nil
+}
+
+// GetPullRequestDiff returns the merge request changes as a single unified
+// diff. GitLab's per-file diff entries carry only the hunks, so the git file
+// headers are rebuilt from the old/new paths and file flags. The per-file
+// endpoint is used instead of `raw_diffs` because the latter only exists on
+// recent GitLab releases and self-managed instances frequently lag behind.
+func (p *Provider) GetPullRequestDiff(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	diffs, err := p.listMergeRequestDiffs(ctx, repo, prID)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, d := range diffs {
+		sb.WriteString(formatFileDiff(d))
+	}
+
+	return sb.String(), nil
+}
+
+func (p *Provider) GetPullRequestFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestFile, error) {
+	diffs, err := p.listMergeRequestDiffs(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]globalEntities.PullRequestFile, 0, len(diffs))
+	for _, d := range diffs {
+		additions, deletions := countDiffLines(d.Diff)
+		file := globalEntities.PullRequestFile{
+			Path:      d.NewPath,
+			Status:    mapDiffStatus(d),
+			Additions: additions,
+			Deletions: deletions,
+			Patch:     d.Diff,
+		}
+		if d.RenamedFile {
+			file.OldPath = d.OldPath
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// listMergeRequestDiffs paginates GET /projects/:id/merge_requests/:iid/diffs.
+func (p *Provider) listMergeRequestDiffs(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]*gl.MergeRequestDiff, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	opts := &gl.ListMergeRequestDiffsOptions{
+		ListOptions: gl.ListOptions{PerPage: perPage},
+	}
+
+	var all []*gl.MergeRequestDiff
+	for {
+		diffs, resp, err := p.client.MergeRequests.ListMergeRequestDiffs(
+			pid, int64(prID), opts, gl.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge request diffs: %w", err)
+		}
+		all = append(all, diffs...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+// mapDiffStatus translates GitLab's new/renamed/deleted file flags to the
+// status vocabulary used by PullRequestFile.
+func mapDiffStatus(d *gl.MergeRequestDiff) string {
+	switch {
+	case d.NewFile:
+		return "added"
+	case d.DeletedFile:
+		return "deleted"
+	case d.RenamedFile:
+		return "renamed"
+	default:
+		return "modified"
+	}
+}
+
+// countDiffLines counts added and removed lines in a GitLab diff fragment.
+// The fragment starts at the first hunk header, so there are no `---`/`+++`
+// file headers to exclude.
+func countDiffLines(diff string) (int, int) {
+	var additions, deletions int
+	for line := range strings.SplitSeq(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// formatFileDiff prepends the git file headers GitLab omits from its diff
+// fragments so the concatenated output is a valid unified diff.
+func formatFileDiff(d *gl.MergeRequestDiff) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", d.OldPath, d.NewPath)
+
+	oldName := "a/" + d.OldPath
+	newName := "b/" + d.NewPath
+	switch {
+	case d.NewFile:
+		fmt.Fprintf(&sb, "new file mode %s\n", d.BMode)
+		oldName = "/dev/null"
+	case d.DeletedFile:
+		fmt.Fprintf(&sb, "deleted file mode %s\n", d.AMode)
+		newName = "/dev/null"
+	case d.RenamedFile:
+		fmt.Fprintf(&sb, "rename from %s\nrename to %s\n", d.OldPath, d.NewPath)
+	}
+
+	if d.Diff == "" {
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	sb.WriteString(d.Diff)
+	if !strings.HasSuffix(d.Diff, "\n") {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ListPullRequestComments returns every user note on the merge request by
+// walking its discussions. Every GitLab note belongs to a discussion — even a
+// plain MR comment is an "individual note" discussion that can later be
+// replied to — so ThreadID is always set. Discussion IDs are opaque SHA-like
+// strings, which do not fit the integer ThreadID, so the discussion's root
+// note ID stands in for it: ReplyToThread and UpdatePullRequestThreadStatus
+// resolve it back to the discussion. System notes ("added 1 commit",
+// "approved this merge request", ...) are skipped because they are generated
+// by GitLab rather than posted by a user.
+func (p *Provider) ListPullRequestComments(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestComment, error) {
+	discussions, err := p.listMergeRequestDiscussions(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []globalEntities.PullRequestComment
+	for _, d := range discussions {
+		if len(d.Notes) == 0 {
+			continue
+		}
+		rootID := d.Notes[0].ID
+		for _, n := range d.Notes {
+			if n.System {
+				continue
+			}
+			comment := globalEntities.PullRequestComment{
+				ID:       n.ID,
+				ThreadID: rootID,
+				Body:     n.Body,
+				Author:   n.Author.Username,
+			}
+			if n.ID != rootID {
+				comment.InReplyToID = rootID
+			}
+			if n.Position != nil {
+				comment.FilePath = n.Position.NewPath
+				comment.Line = int(n.Position.NewLine)
+				if comment.FilePath == "" {
+					comment.FilePath = n.Position.OldPath
+					comment.Line = int(n.Position.OldLine)
+				}
+			}
+			out = append(out, comment)
+		}
+	}
+
+	return out, nil
+}
+
+// listMergeRequestDiscussions paginates GET /projects/:id/merge_requests/:iid/discussions.
+func (p *Provider) listMergeRequestDiscussions(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]*gl.Discussion, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	opts := &gl.ListMergeRequestDiscussionsOptions{
+		ListOptions: gl.ListOptions{PerPage: perPage},
+	}
+
+	var all []*gl.Discussion
+	for {
+		discussions, resp, err := p.client.Discussions.ListMergeRequestDiscussions(
+			pid, int64(prID), opts, gl.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list merge request discussions: %w", err)
+		}
+		all = append(all, discussions...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return all, nil
+}
+
+// findDiscussionID maps a thread ID (the root note ID exposed on
+// PullRequestComment.ThreadID) back to the discussion's string identifier.
+func (p *Provider) findDiscussionID(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+) (string, error) {
+	discussions, err := p.listMergeRequestDiscussions(ctx, repo, prID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, d := range discussions {
+		if len(d.Notes) > 0 && d.Notes[0].ID == int64(threadID) {
+			return d.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: thread %d on merge request !%d", errDiscussionNotFound, threadID, prID)
+}
+
+// PostPullRequestComment posts a merge-request-wide comment. With the default
+// "active" status the comment is a plain note. A resolved status supplied via
+// entities.WithThreadStatus (e.g. "fixed" or "closed") instead opens a
+// resolvable discussion and resolves it immediately, so informational
+// annotations do not count towards the "all threads must be resolved" merge
+// check.
+func (p *Provider) PostPullRequestComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	body string,
+	opts ...globalEntities.CommentOption,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	resolved, err := mapThreadStatusToResolved(globalEntities.ResolveCommentOptions(opts...))
+	if err != nil {
+		return err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	if !resolved {
+		if _, _, noteErr := p.client.Notes.CreateMergeRequestNote(
+			pid, int64(prID),
+			&gl.CreateMergeRequestNoteOptions{Body: &body},
+			gl.WithContext(ctx),
+		); noteErr != nil {
+			return fmt.Errorf("failed to post merge request comment: %w", noteErr)
+		}
+		return nil
+	}
+
+	discussion, _, err := p.client.Discussions.CreateMergeRequestDiscussion(
+		pid, int64(prID),
+		&gl.CreateMergeRequestDiscussionOptions{Body: &body},
+		gl.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to post merge request comment: %w", err)
+	}
+
+	return p.resolveDiscussion(ctx, pid, prID, discussion.ID, true)
+}
+
+// PostPullRequestThreadComment opens a diff discussion anchored to a line on
+// the new side of the merge request diff. GitLab requires the base, start and
+// head SHAs of the current diff version in the position, so the merge request
+// is fetched first to read its `diff_refs`. The returned ID is the root note
+// ID of the new discussion, which is the same value ListPullRequestComments
+// exposes as ThreadID and is accepted by ReplyToThread and
+// UpdatePullRequestThreadStatus. A resolved status supplied via
+// entities.WithThreadStatus resolves the discussion right after creating it.
+func (p *Provider) PostPullRequestThreadComment(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	filePath string,
+	line int,
+	body string,
+	opts ...globalEntities.CommentOption,
+) (int, error) {
+	if p.client == nil {
+		return 0, errClientNotInitialized
+	}
+
+	resolved, err := mapThreadStatusToResolved(globalEntities.ResolveCommentOptions(opts...))
+	if err != nil {
+		return 0, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	mr, _, err := p.client.MergeRequests.GetMergeRequest(
+		pid, int64(prID), nil, gl.WithContext(ctx),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	positionType := "text"
+	newLine := int64(line)
+	discussion, _, err := p.client.Discussions.CreateMergeRequestDiscussion(
+		pid, int64(prID),
+		&gl.CreateMergeRequestDiscussionOptions{
+			Body: &body,
+			Position: &gl.PositionOptions{
+				BaseSHA:      &mr.DiffRefs.BaseSha,
+				StartSHA:     &mr.DiffRefs.StartSha,
+				HeadSHA:      &mr.DiffRefs.HeadSha,
+				PositionType: &positionType,
+				NewPath:      &filePath,
+				OldPath:      &filePath,
+				NewLine:      &newLine,
+			},
+		},
+		gl.WithContext(ctx),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post merge request thread comment: %w", err)
+	}
+
+	if resolved {
+		if resolveErr := p.resolveDiscussion(ctx, pid, prID, discussion.ID, true); resolveErr != nil {
+			return 0, resolveErr
+		}
+	}
+
+	if len(discussion.Notes) == 0 {
+		return 0, nil
+	}
+	return int(discussion.Notes[0].ID), nil
+}
+
+// ReplyToThread appends a note to an existing discussion. `threadID` is the
+// discussion's root note ID (PullRequestComment.ThreadID), which is mapped
+// back to the discussion ID before posting. Returns the new note's ID.
+func (p *Provider) ReplyToThread(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	body string,
+) (int, error) {
+	discussionID, err := p.findDiscussionID(ctx, repo, prID, threadID)
+	if err != nil {
+		return 0, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	note, _, err := p.client.Discussions.AddMergeRequestDiscussionNote(
+		pid, int64(prID), discussionID,
+		&gl.AddMergeRequestDiscussionNoteOptions{Body: &body},
+		gl.WithContext(ctx),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reply to merge request discussion: %w", err)
+	}
+
+	return int(note.ID), nil
+}
+
+// UpdatePullRequestThreadStatus resolves or unresolves a discussion. GitLab
+// discussions only have a resolved flag, so the Azure DevOps-style statuses
+// are folded onto it: "fixed", "closed", "resolved", "wontFix" and "byDesign"
+// resolve the discussion, while "active", "pending" and "unresolved" reopen
+// it. Any other status is rejected.
+func (p *Provider) UpdatePullRequestThreadStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	status string,
+) error {
+	resolved, err := mapThreadStatusToResolved(status)
+	if err != nil {
+		return err
+	}
+
+	discussionID, err := p.findDiscussionID(ctx, repo, prID, threadID)
+	if err != nil {
+		return err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	return p.resolveDiscussion(ctx, pid, prID, discussionID, resolved)
+}
+
+func (p *Provider) resolveDiscussion(
+	ctx context.Context,
+	pid string,
+	prID int,
+	discussionID string,
+	resolved bool,
+) error {
+	if _, _, err := p.client.Discussions.ResolveMergeRequestDiscussion(
+		pid, int64(prID), discussionID,
+		&gl.ResolveMergeRequestDiscussionOptions{Resolved: &resolved},
+		gl.WithContext(ctx),
+	); err != nil {
+		return fmt.Errorf("failed to update merge request discussion status: %w", err)
+	}
+	return nil
+}
+
+// mapThreadStatusToResolved folds a thread status string onto GitLab's
+// resolved flag.
+func mapThreadStatusToResolved(status string) (bool, error) {
+	switch status {
+	case "fixed", "closed", "resolved", "wontFix", "byDesign":
+		return true, nil
+	case "active", "pending", "unresolved":
+		return false, nil
+	}
+	return false, fmt.Errorf("unsupported thread status %q", status)
+}
+
+// GetPullRequestStatus returns the GitLab merge request state: "opened",
+// "closed", "merged" or "locked".
+func (p *Provider) GetPullRequestStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	if p.client == nil {
+		return "", errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	mr, _, err := p.client.MergeRequests.GetMergeRequest(
+		pid, int64(prID), nil, gl.WithContext(ctx),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	return mr.State, nil
+}
+
+// GetPullRequestCheckStatus reports whether the merge request's head pipeline
+// passed. A merge request without a head pipeline has no CI configured and is
+// treated as passing, matching the GitHub provider; a skipped pipeline is
+// treated as passing too.
+func (p *Provider) GetPullRequestCheckStatus(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (bool, error) {
+	if p.client == nil {
+		return false, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	mr, _, err := p.client.MergeRequests.GetMergeRequest(
+		pid, int64(prID), nil, gl.WithContext(ctx),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	if mr.HeadPipeline == nil {
+		return true, nil
+	}
+
+	status := mr.HeadPipeline.Status
+	return status == pipelineStatusSuccess || status == pipelineStatusSkipped, nil
+}
+
+// GetPullRequestChecks reports the merge request's head pipeline as its only
+// check, required when the project only allows merging once the pipeline
+// succeeds.
+func (p *Provider) GetPullRequestChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*globalEntities.PullRequestChecks, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	mr, _, err := p.client.MergeRequests.GetMergeRequest(
+		pid, int64(prID), nil, gl.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge request: %w", err)
+	}
+
+	pipeline := mr.HeadPipeline
+	if pipeline == nil {
+		return globalEntities.NewPullRequestChecks(nil), nil
+	}
+
+	project, _, err := p.client.Projects.GetProject(pid, nil, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	name := pipeline.Name
+	if name == "" {
+		name = "pipeline"
+	}
+	return globalEntities.NewPullRequestChecks([]globalEntities.Check{{
+		Name:        name,
+		Kind:        globalEntities.CheckKindPipeline,
+		State:       pipelineCheckState(gitlabPipelineStatus(pipeline.Status)),
+		Required:    project.OnlyAllowMergeIfPipelineSucceeds,
+		URL:         pipeline.WebURL,
+		StartedAt:   timeOrZero(pipeline.StartedAt),
+		CompletedAt: timeOrZero(pipeline.FinishedAt),
+	}}), nil
+}
+
+func pipelineCheckState(status globalEntities.PipelineStatus) globalEntities.CheckState {
+	switch status {
+	case globalEntities.PipelineStatusSuccess:
+		return globalEntities.CheckStateSuccess
+	case globalEntities.PipelineStatusFailure, globalEntities.PipelineStatusCanceled:
+		return globalEntities.CheckStateFailure
+	case globalEntities.PipelineStatusSkipped:
+		return globalEntities.CheckStateSkipped
+	default: // queued, running
+		return globalEntities.CheckStatePending
+	}
+}
+
+// MergePullRequest accepts the merge request. The "squash" strategy (also the
+// default when strategy is empty, matching the other providers) sets the
+// `squash` flag; any other strategy merges without squashing and leaves the
+// merge-commit / fast-forward choice to the project's merge method setting,
+// since GitLab does not take it per request. WithDeleteSourceBranch maps to
+// `should_remove_source_branch`, so GitLab removes the branch server-side.
+// WithBypassPolicy is silently ignored: GitLab has no per-call bypass and
+// approval rules are governed by the project settings.
+func (p *Provider) MergePullRequest(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	strategy string,
+	opts ...globalEntities.MergeOption,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	resolved := globalEntities.ResolveMergeOptions(opts...)
+	squash := strategy == "" || strategy == "squash"
+
+	pid := repo.Organization + "/" + repo.Name
+	if _, _, err := p.client.MergeRequests.AcceptMergeRequest(
+		pid, int64(prID),
+		&gl.AcceptMergeRequestOptions{
+			Squash:                   &squash,
+			ShouldRemoveSourceBranch: &resolved.DeleteSourceBranch,
+		},
+		gl.WithContext(ctx),
+	); err != nil {
+		return fmt.Errorf("failed to merge merge request: %w", err)
+	}
+
+	return nil
+}
+
+// approvalAction is the change SubmitPullRequestReview applies to the
+// authenticated user's approval on the merge request.
+type approvalAction int
+
+const (
+	approvalKeep approvalAction = iota
+	approvalGrant
+	approvalRevoke
+)
+
+// SubmitPullRequestReview records the verdict through GitLab's approvals API.
+// GitLab has no "changes requested" REST endpoint and no review event that
+// carries a body, so the mapping is:
+//
+//	ReviewVerdictApprove          -> approve (body posted as a note first)
+//	ReviewVerdictRequestChanges   -> revoke any prior approval + note
+//	ReviewVerdictWaitingForAuthor -> note only (soft signal, approval untouched)
+//	ReviewVerdictComment          -> note only (skipped when body is empty)
+//
+// Revoking an approval the user never gave returns 404 from GitLab; that case
+// is treated as a no-op so a first-pass "request changes" does not fail.
+func (p *Provider) SubmitPullRequestReview(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	sub globalEntities.ReviewSubmission,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+
+	action, ok := mapVerdictToApprovalAction(sub.Verdict)
+	if !ok {
+		return fmt.Errorf("unsupported review verdict %q", sub.Verdict)
+	}
+
+	if sub.Body != "" {
+		if err := p.PostPullRequestComment(ctx, repo, prID, sub.Body); err != nil {
+			return err
+		}
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	switch action {
+	case approvalGrant:
+		if _, _, err := p.client.MergeRequestApprovals.ApproveMergeRequest(
+			pid, int64(prID), nil, gl.WithContext(ctx),
+		); err != nil {
+			return fmt.Errorf("failed to approve merge request: %w", err)
+		}
+	case approvalRevoke:
+		if _, err := p.client.MergeRequestApprovals.UnapproveMergeRequest(
+			pid, int64(prID), gl.WithContext(ctx),
+		); err != nil && !errors.Is(err, gl.ErrNotFound) {
+			return fmt.Errorf("failed to revoke merge request approval: %w", err)
+		}
+	case approvalKeep:
+	}
+
+	return nil
+}
+
+// mapVerdictToApprovalAction translates a gitforge ReviewVerdict to the
+// approval change applied on GitLab.
+func mapVerdictToApprovalAction(v globalEntities.ReviewVerdict) (approvalAction, bool) {
+	switch v {
+	case globalEntities.ReviewVerdictApprove:
+		return approvalGrant, true
+	case globalEntities.ReviewVerdictRequestChanges:
+		return approvalRevoke, true
+	case globalEntities.ReviewVerdictWaitingForAuthor,
+		globalEntities.ReviewVerdictComment:
+		return approvalKeep, true
+	}
+	return approvalKeep, false
+}
@@ -0,0 +1,601 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// discussionsFixture is a merge request with one plain note, one diff
+// discussion with a reply, and one system note.
+const discussionsFixture = `[
+	{"id":"aaa","individual_note":true,"notes":[
+		{"id":10,"body":"LGTM overall","author":{"username":"alice"}}
+	]},
+	{"id":"bbb","individual_note":false,"notes":[
+		{"id":20,"body":"nit: rename","author":{"username":"bot"},
+		 "position":{"new_path":"main.go","new_line":12}},
+		{"id":21,"body":"done","author":{"username":"alice"}}
+	]},
+	{"id":"ccc","individual_note":true,"notes":[
+		{"id":30,"body":"added 1 commit","system":true,"author":{"username":"alice"}}
+	]}
+]`
+
+func TestReviewProviderNilClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should return an error when the client is not initialised", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		p := &Provider{token: "test", client: nil}
+		repo := globalEntities.Repository{Organization: "org", Name: "repo"}
+		ctx := context.Background()
+
+		// when
+		_, listErr := p.ListOpenPullRequests(ctx, repo)
+		_, diffErr := p.GetPullRequestDiff(ctx, repo, 1)
+		_, commentsErr := p.ListPullRequestComments(ctx, repo, 1)
+		mergeErr := p.MergePullRequest(ctx, repo, 1, "squash")
+		reviewErr := p.SubmitPullRequestReview(ctx, repo, 1, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictApprove,
+		})
+
+		// then
+		require.ErrorIs(t, listErr, errClientNotInitialized)
+		require.ErrorIs(t, diffErr, errClientNotInitialized)
+		require.ErrorIs(t, commentsErr, errClientNotInitialized)
+		require.ErrorIs(t, mergeErr, errClientNotInitialized)
+		require.ErrorIs(t, reviewErr, errClientNotInitialized)
+	})
+}
+
+func TestListOpenPullRequestsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should map opened merge requests when the API returns them", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var capturedState string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests", func(w http.ResponseWriter, r *http.Request) {
+			capturedState = r.URL.Query().Get("state")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`[{"iid":5,"title":"Add feature","web_url":"https://gitlab.com/o/r/-/merge_requests/5",
+				"state":"opened","source_branch":"feat","target_branch":"main","draft":true,
+				"author":{"username":"alice"}}]`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		prs, err := p.ListOpenPullRequests(context.Background(), repo)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, prs, 1)
+		assert.Equal(t, "opened", capturedState)
+		assert.Equal(t, 5, prs[0].ID)
+		assert.Equal(t, "feat", prs[0].SourceBranch)
+		assert.Equal(t, "main", prs[0].TargetBranch)
+		assert.Equal(t, "alice", prs[0].Author)
+		assert.True(t, prs[0].IsDraft)
+	})
+}
+
+func TestGetPullRequestDiffAndFilesInternal(t *testing.T) {
+	t.Parallel()
+
+	diffs := `[
+		{"old_path":"a.go","new_path":"a.go","diff":"@@ -1,2 +1,2 @@\n-old\n+new\n ctx\n"},
+		{"old_path":"b.go","new_path":"b.go","b_mode":"100644","new_file":true,"diff":"@@ -0,0 +1 @@\n+hello\n"},
+		{"old_path":"c.go","new_path":"d.go","renamed_file":true,"diff":""}
+	]`
+
+	newServer := func() *httptest.Server {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5/diffs", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(diffs))
+		})
+		return httptest.NewServer(mux)
+	}
+
+	t.Run("should rebuild git file headers when assembling the diff", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newServer()
+		defer server.Close()
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		diff, err := p.GetPullRequestDiff(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, diff, "diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -1,2 +1,2 @@")
+		assert.Contains(t, diff, "new file mode 100644\n--- /dev/null\n+++ b/b.go\n")
+		assert.Contains(t, diff, "rename from c.go\nrename to d.go\n")
+	})
+
+	t.Run("should map file status and line counts when listing files", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := newServer()
+		defer server.Close()
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		files, err := p.GetPullRequestFiles(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, files, 3)
+		assert.Equal(t, "modified", files[0].Status)
+		assert.Equal(t, 1, files[0].Additions)
+		assert.Equal(t, 1, files[0].Deletions)
+		assert.Equal(t, "added", files[1].Status)
+		assert.Equal(t, 1, files[1].Additions)
+		assert.Equal(t, "renamed", files[2].Status)
+		assert.Equal(t, "c.go", files[2].OldPath)
+		assert.Equal(t, "d.go", files[2].Path)
+	})
+}
+
+func TestListPullRequestCommentsInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should key threads by root note and skip system notes", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5/discussions", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(discussionsFixture))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		comments, err := p.ListPullRequestComments(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, comments, 3)
+		assert.Equal(t, int64(10), comments[0].ThreadID)
+		assert.Empty(t, comments[0].FilePath)
+		assert.Equal(t, int64(20), comments[1].ThreadID)
+		assert.Equal(t, "main.go", comments[1].FilePath)
+		assert.Equal(t, 12, comments[1].Line)
+		assert.Equal(t, int64(20), comments[2].ThreadID)
+		assert.Equal(t, int64(20), comments[2].InReplyToID)
+	})
+}
+
+func TestPostPullRequestThreadCommentInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should anchor the discussion to the diff refs and return the root note ID", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var capturedBody map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"iid":5,"diff_refs":{"base_sha":"b1","head_sha":"h1","start_sha":"s1"}}`))
+		})
+		mux.HandleFunc("POST /api/v4/projects/{pid}/merge_requests/5/discussions", func(w http.ResponseWriter, r *http.Request) {
+			defer func() { _ = r.Body.Close() }()
+			_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"ddd","notes":[{"id":77,"body":"x"}]}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		threadID, err := p.PostPullRequestThreadComment(context.Background(), repo, 5, "main.go", 12, "x")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 77, threadID)
+		position, ok := capturedBody["position"].(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, "b1", position["base_sha"])
+		assert.Equal(t, "h1", position["head_sha"])
+		assert.Equal(t, "s1", position["start_sha"])
+		assert.Equal(t, "main.go", position["new_path"])
+		assert.InDelta(t, 12, position["new_line"], 0)
+	})
+
+	t.Run("should reject an unknown thread status", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.PostPullRequestThreadComment(
+			context.Background(), repo, 5, "main.go", 12, "x",
+			globalEntities.WithThreadStatus("bogus"),
+		)
+
+		// then
+		require.ErrorContains(t, err, "unsupported thread status")
+	})
+}
+
+func TestReplyToThreadInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should post to the discussion owning the root note", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var capturedDiscussion string
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5/discussions", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(discussionsFixture))
+		})
+		mux.HandleFunc("POST /api/v4/projects/{pid}/merge_requests/5/discussions/{id}/notes",
+			func(w http.ResponseWriter, r *http.Request) {
+				capturedDiscussion = r.PathValue("id")
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":99}`))
+			})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		replyID, err := p.ReplyToThread(context.Background(), repo, 5, 20, "fixed now")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 99, replyID)
+		assert.Equal(t, "bbb", capturedDiscussion)
+	})
+
+	t.Run("should return an error when no discussion has the root note", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5/discussions", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(discussionsFixture))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		_, err := p.ReplyToThread(context.Background(), repo, 5, 21, "reply")
+
+		// then
+		require.ErrorIs(t, err, errDiscussionNotFound)
+	})
+}
+
+func TestUpdatePullRequestThreadStatusInternal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status   string
+		resolved bool
+	}{
+		{status: "fixed", resolved: true},
+		{status: "closed", resolved: true},
+		{status: "active", resolved: false},
+	}
+
+	for _, tt := range tests {
+		t.Run("should map the resolved flag when status is "+tt.status, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var capturedBody map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5/discussions", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(discussionsFixture))
+			})
+			mux.HandleFunc("PUT /api/v4/projects/{pid}/merge_requests/5/discussions/bbb", func(w http.ResponseWriter, r *http.Request) {
+				defer func() { _ = r.Body.Close() }()
+				_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"bbb"}`))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			err := p.UpdatePullRequestThreadStatus(context.Background(), repo, 5, 20, tt.status)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.resolved, capturedBody["resolved"])
+		})
+	}
+}
+
+func TestGetPullRequestCheckStatusInternal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		payload  string
+		expected bool
+	}{
+		{name: "should pass when there is no head pipeline", payload: `{"iid":5}`, expected: true},
+		{name: "should pass when the head pipeline succeeded", payload: `{"iid":5,"head_pipeline":{"status":"success"}}`, expected: true},
+		{name: "should fail when the head pipeline is running", payload: `{"iid":5,"head_pipeline":{"status":"running"}}`, expected: false},
+		{name: "should fail when the head pipeline failed", payload: `{"iid":5,"head_pipeline":{"status":"failed"}}`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.payload))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			passed, err := p.GetPullRequestCheckStatus(context.Background(), repo, 5)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, passed)
+		})
+	}
+}
+
+func TestGetPullRequestChecksInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should report the head pipeline, required when the project requires it", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"iid":5,"head_pipeline":{"id":77,"status":"canceled",
+				"web_url":"https://gitlab.com/my-org/my-repo/-/pipelines/77",
+				"started_at":"2026-01-02T10:00:00Z","finished_at":"2026-01-02T10:05:00Z"}}`))
+		})
+		mux.HandleFunc("GET /api/v4/projects/{pid}", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":1,"only_allow_merge_if_pipeline_succeeds":true}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		checks, err := p.GetPullRequestChecks(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &globalEntities.PullRequestChecks{
+			Checks: []globalEntities.Check{{
+				Name:        "pipeline",
+				Kind:        globalEntities.CheckKindPipeline,
+				State:       globalEntities.CheckStateFailure,
+				Required:    true,
+				URL:         "https://gitlab.com/my-org/my-repo/-/pipelines/77",
+				StartedAt:   time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+				CompletedAt: time.Date(2026, 1, 2, 10, 5, 0, 0, time.UTC),
+			}},
+			Verdict: globalEntities.CheckStateFailure,
+		}, checks)
+	})
+
+	t.Run("should succeed without checks when there is no head pipeline", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /api/v4/projects/{pid}/merge_requests/5", func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"iid":5}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		checks, err := p.GetPullRequestChecks(context.Background(), repo, 5)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, checks.Checks)
+		assert.Equal(t, globalEntities.CheckStateSuccess, checks.Verdict)
+	})
+}
+
+func TestMergePullRequestInternal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		strategy     string
+		opts         []globalEntities.MergeOption
+		squash       bool
+		removeSource bool
+	}{
+		{name: "should squash by default and keep the source branch", strategy: "", squash: true},
+		{
+			name:         "should remove the source branch when WithDeleteSourceBranch is set",
+			strategy:     "squash",
+			opts:         []globalEntities.MergeOption{globalEntities.WithDeleteSourceBranch()},
+			squash:       true,
+			removeSource: true,
+		},
+		{name: "should not squash when strategy is merge", strategy: "merge", squash: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var capturedBody map[string]any
+			mux := http.NewServeMux()
+			mux.HandleFunc("PUT /api/v4/projects/{pid}/merge_requests/5/merge", func(w http.ResponseWriter, r *http.Request) {
+				defer func() { _ = r.Body.Close() }()
+				_ = json.NewDecoder(r.Body).Decode(&capturedBody)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"iid":5,"state":"merged"}`))
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			err := p.MergePullRequest(context.Background(), repo, 5, tt.strategy, tt.opts...)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.squash, capturedBody["squash"])
+			assert.Equal(t, tt.removeSource, capturedBody["should_remove_source_branch"])
+		})
+	}
+}
+
+func TestSubmitPullRequestReviewInternal(t *testing.T) {
+	t.Parallel()
+
+	newServer := func(calls *[]string) *httptest.Server {
+		mux := http.NewServeMux()
+		record := func(name string) http.HandlerFunc {
+			return func(w http.ResponseWriter, _ *http.Request) {
+				*calls = append(*calls, name)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":1}`))
+			}
+		}
+		mux.HandleFunc("POST /api/v4/projects/{pid}/merge_requests/5/notes", record("note"))
+		mux.HandleFunc("POST /api/v4/projects/{pid}/merge_requests/5/approve", record("approve"))
+		mux.HandleFunc("POST /api/v4/projects/{pid}/merge_requests/5/unapprove", func(w http.ResponseWriter, _ *http.Request) {
+			*calls = append(*calls, "unapprove")
+			w.WriteHeader(http.StatusNotFound)
+		})
+		return httptest.NewServer(mux)
+	}
+
+	tests := []struct {
+		name     string
+		sub      globalEntities.ReviewSubmission
+		expected []string
+	}{
+		{
+			name:     "should approve after posting the body when verdict is approve",
+			sub:      globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictApprove, Body: "ship it"},
+			expected: []string{"note", "approve"},
+		},
+		{
+			name:     "should revoke approval and tolerate 404 when verdict is request_changes",
+			sub:      globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictRequestChanges, Body: "fix it"},
+			expected: []string{"note", "unapprove"},
+		},
+		{
+			name:     "should only post a note when verdict is waiting_for_author",
+			sub:      globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictWaitingForAuthor, Body: "ping"},
+			expected: []string{"note"},
+		},
+		{
+			name:     "should skip the API entirely when verdict is comment with an empty body",
+			sub:      globalEntities.ReviewSubmission{Verdict: globalEntities.ReviewVerdictComment},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			var calls []string
+			server := newServer(&calls)
+			defer server.Close()
+			p := newTestProvider(t, server)
+			repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+			// when
+			err := p.SubmitPullRequestReview(context.Background(), repo, 5, tt.sub)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, calls)
+		})
+	}
+
+	t.Run("should reject an unknown verdict", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-org", Name: "my-repo"}
+
+		// when
+		err := p.SubmitPullRequestReview(context.Background(), repo, 5,
+			globalEntities.ReviewSubmission{Verdict: "bogus"})
+
+		// then
+		require.ErrorContains(t, err, "unsupported review verdict")
+	})
+}
@@ -0,0 +1,64 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+// CreateTag creates the tag through the tags endpoint. GitLab records the
+// token's user as the tagger of annotated tags.
+func (p *Provider) CreateTag(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.TagInput,
+) (*globalEntities.Tag, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	if input.TaggerName != "" || input.TaggerEmail != "" {
+		return nil, fmt.Errorf("GitLab tags the token's user: %w", errors.ErrUnsupported)
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	ref := strings.TrimPrefix(input.Target, "refs/heads/")
+	if ref == "" {
+		ref = strings.TrimPrefix(repo.DefaultBranch, "refs/heads/")
+	}
+	if ref == "" {
+		project, _, err := p.client.Projects.GetProject(pid, nil, gl.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get project: %w", err)
+		}
+		ref = project.DefaultBranch
+	}
+
+	tagName := input.Name
+	opts := &gl.CreateTagOptions{TagName: &tagName, Ref: &ref}
+	if input.Message != "" {
+		message := input.Message
+		opts.Message = &message
+	}
+
+	tag, _, err := p.client.Tags.CreateTag(pid, opts, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag %q: %w", input.Name, err)
+	}
+
+	result := &globalEntities.Tag{
+		Name:      tag.Name,
+		Annotated: tag.Message != "",
+		Message:   tag.Message,
+	}
+	if tag.Commit != nil {
+		result.CommitSHA = tag.Commit.ID
+		if tag.Commit.CommittedDate != nil {
+			result.Date = *tag.Commit.CommittedDate
+		}
+	}
+	return result, nil
+}
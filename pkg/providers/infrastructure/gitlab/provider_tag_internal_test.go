@@ -0,0 +1,75 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateTagInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create an annotated tag on the target branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc(
+			"POST /api/v4/projects/my-group%2Fmy-repo/repository/tags",
+			func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"name":"v1.0.0","message":"Release 1.0.0","target":"tag-object",
+					"commit":{"id":"abc123","committed_date":"2026-03-04T05:06:07Z"}}`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		tag, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Target: "release/1.x", Message: "Release 1.0.0",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "release/1.x", body["ref"])
+		assert.Equal(t, "Release 1.0.0", body["message"])
+		assert.Equal(t, &globalEntities.Tag{
+			Name: "v1.0.0", CommitSHA: "abc123", Annotated: true, Message: "Release 1.0.0",
+			Date: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		}, tag)
+	})
+
+	t.Run("should reject a tagger as unsupported", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		_, err := p.CreateTag(context.Background(), repo, globalEntities.TagInput{
+			Name: "v1.0.0", Message: "Release 1.0.0", TaggerName: "Release Bot",
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
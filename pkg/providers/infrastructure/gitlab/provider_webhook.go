@@ -0,0 +1,308 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	gl "gitlab.com/gitlab-org/api/client-go"
+)
+
+// gitlabHookEvents holds the event switches of a project hook. Every switch is
+// sent, so an update turns off the events the input leaves out.
+type gitlabHookEvents struct {
+	push, tagPush, mergeRequests, notes, issues, releases, pipelines bool
+}
+
+// gitlabHookEvent is a project hook event log entry, which client-go does not
+// model.
+type gitlabHookEvent struct {
+	ID             int64     `json:"id"`
+	Trigger        string    `json:"trigger"`
+	ResponseStatus string    `json:"response_status"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CreateWebhook adds a project hook. GitLab sends JSON only and passes the
+// secret verbatim in the X-Gitlab-Token header.
+func (p *Provider) CreateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	events, err := gitlabHookInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	hookURL := input.URL
+	token := input.Secret
+	hook, _, err := p.client.Projects.AddProjectHook(pid, &gl.AddProjectHookOptions{
+		URL:                 &hookURL,
+		Token:               &token,
+		PushEvents:          &events.push,
+		TagPushEvents:       &events.tagPush,
+		MergeRequestsEvents: &events.mergeRequests,
+		NoteEvents:          &events.notes,
+		IssuesEvents:        &events.issues,
+		ReleasesEvents:      &events.releases,
+		PipelineEvents:      &events.pipelines,
+	}, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return gitlabHookToDomain(repo, hook), nil
+}
+
+func (p *Provider) ListWebhooks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.Webhook, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	var webhooks []globalEntities.Webhook
+	opts := &gl.ListProjectHooksOptions{ListOptions: gl.ListOptions{PerPage: perPage}}
+	for {
+		hooks, resp, err := p.client.Projects.ListProjectHooks(pid, opts, gl.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+		for _, hook := range hooks {
+			webhooks = append(webhooks, *gitlabHookToDomain(repo, hook))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return webhooks, nil
+}
+
+func (p *Provider) UpdateWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id string,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return nil, err
+	}
+	events, err := gitlabHookInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	hookURL := input.URL
+	token := input.Secret
+	hook, _, err := p.client.Projects.EditProjectHook(pid, hookID, &gl.EditProjectHookOptions{
+		URL:                 &hookURL,
+		Token:               &token,
+		PushEvents:          &events.push,
+		TagPushEvents:       &events.tagPush,
+		MergeRequestsEvents: &events.mergeRequests,
+		NoteEvents:          &events.notes,
+		IssuesEvents:        &events.issues,
+		ReleasesEvents:      &events.releases,
+		PipelineEvents:      &events.pipelines,
+	}, gl.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update webhook %s: %w", id, err)
+	}
+	return gitlabHookToDomain(repo, hook), nil
+}
+
+func (p *Provider) DeleteWebhook(ctx context.Context, repo globalEntities.Repository, id string) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	if _, err = p.client.Projects.DeleteProjectHook(pid, hookID, gl.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// PingWebhook triggers a test push event, which GitLab builds from the
+// project's latest commit.
+func (p *Provider) PingWebhook(ctx context.Context, repo globalEntities.Repository, id string) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return err
+	}
+
+	pid := repo.Organization + "/" + repo.Name
+	if _, err = p.client.Projects.TriggerTestProjectHook(
+		pid, hookID, gl.ProjectHookEventPush, gl.WithContext(ctx),
+	); err != nil {
+		return fmt.Errorf("failed to ping webhook %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the first page of the hook's event log, which
+// GitLab keeps for seven days.
+func (p *Provider) ListWebhookDeliveries(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id string,
+) ([]globalEntities.WebhookDelivery, error) {
+	if p.client == nil {
+		return nil, errClientNotInitialized
+	}
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf(
+		"projects/%s/hooks/%d/events", gl.PathEscape(repo.Organization+"/"+repo.Name), hookID,
+	)
+	req, err := p.client.NewRequest(
+		http.MethodGet, path, &gl.ListOptions{PerPage: perPage}, []gl.RequestOptionFunc{gl.WithContext(ctx)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook events request: %w", err)
+	}
+	var events []gitlabHookEvent
+	if _, err = p.client.Do(req, &events); err != nil {
+		return nil, fmt.Errorf("failed to list deliveries of webhook %s: %w", id, err)
+	}
+
+	deliveries := make([]globalEntities.WebhookDelivery, 0, len(events))
+	for _, event := range events {
+		// the status is the response code, or the error for failed requests
+		statusCode, _ := strconv.Atoi(event.ResponseStatus)
+		deliveries = append(deliveries, globalEntities.WebhookDelivery{
+			ID:          strconv.FormatInt(event.ID, 10),
+			Event:       event.Trigger,
+			StatusCode:  statusCode,
+			DeliveredAt: event.CreatedAt,
+		})
+	}
+	return deliveries, nil
+}
+
+func (p *Provider) RedeliverWebhook(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	id, deliveryID string,
+) error {
+	if p.client == nil {
+		return errClientNotInitialized
+	}
+	hookID, err := parseHookID(id)
+	if err != nil {
+		return err
+	}
+	eventID, err := strconv.ParseInt(deliveryID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid delivery ID %q: %w", deliveryID, err)
+	}
+
+	path := fmt.Sprintf(
+		"projects/%s/hooks/%d/events/%d/resend",
+		gl.PathEscape(repo.Organization+"/"+repo.Name), hookID, eventID,
+	)
+	req, err := p.client.NewRequest(http.MethodPost, path, nil, []gl.RequestOptionFunc{gl.WithContext(ctx)})
+	if err != nil {
+		return fmt.Errorf("failed to build webhook resend request: %w", err)
+	}
+	if _, err = p.client.Do(req, nil); err != nil {
+		return fmt.Errorf("failed to redeliver delivery %s of webhook %s: %w", deliveryID, id, err)
+	}
+	return nil
+}
+
+func parseHookID(id string) (int64, error) {
+	hookID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook ID %q: %w", id, err)
+	}
+	return hookID, nil
+}
+
+func gitlabHookInput(input globalEntities.WebhookInput) (*gitlabHookEvents, error) {
+	if input.ContentTypeOrDefault() != globalEntities.WebhookContentTypeJSON {
+		return nil, fmt.Errorf("webhook content type %q: %w", input.ContentType, errors.ErrUnsupported)
+	}
+
+	events := &gitlabHookEvents{}
+	for _, event := range input.Events {
+		switch event {
+		case globalEntities.WebhookEventPush:
+			events.push = true
+			events.tagPush = true
+		case globalEntities.WebhookEventPullRequest:
+			events.mergeRequests = true
+		case globalEntities.WebhookEventComment:
+			events.notes = true
+		case globalEntities.WebhookEventIssue:
+			events.issues = true
+		case globalEntities.WebhookEventRelease:
+			events.releases = true
+		case globalEntities.WebhookEventPipeline:
+			events.pipelines = true
+		default:
+			return nil, fmt.Errorf("webhook event %q: %w", event, errors.ErrUnsupported)
+		}
+	}
+	return events, nil
+}
+
+func gitlabHookToDomain(repo globalEntities.Repository, hook *gl.ProjectHook) *globalEntities.Webhook {
+	webhook := &globalEntities.Webhook{
+		ID:          strconv.FormatInt(hook.ID, 10),
+		Repository:  repo,
+		URL:         hook.URL,
+		ContentType: globalEntities.WebhookContentTypeJSON,
+		// GitLab disables failing hooks for a while, or for good
+		Active: hook.AlertStatus == "" || hook.AlertStatus == "executable",
+	}
+	if hook.CreatedAt != nil {
+		webhook.CreatedAt = *hook.CreatedAt
+	}
+
+	switches := []struct {
+		on    bool
+		event globalEntities.WebhookEvent
+	}{
+		{hook.PushEvents || hook.TagPushEvents, globalEntities.WebhookEventPush},
+		{hook.MergeRequestsEvents, globalEntities.WebhookEventPullRequest},
+		{hook.NoteEvents, globalEntities.WebhookEventComment},
+		{hook.IssuesEvents, globalEntities.WebhookEventIssue},
+		{hook.ReleasesEvents, globalEntities.WebhookEventRelease},
+		{hook.PipelineEvents, globalEntities.WebhookEventPipeline},
+	}
+	for _, s := range switches {
+		if s.on {
+			webhook.Events = append(webhook.Events, s.event)
+		}
+	}
+	slices.Sort(webhook.Events)
+	return webhook
+}
@@ -0,0 +1,121 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func TestCreateWebhookInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should switch on the events of the input and send the secret as token", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var body map[string]any
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /api/v4/projects/my-group%2Fmy-repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":12,"url":"https://bot.example.com/hook","push_events":true,
+				"tag_push_events":true,"merge_requests_events":true,"alert_status":"executable"}`))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		webhook, err := p.CreateWebhook(context.Background(), repo, globalEntities.WebhookInput{
+			URL: "https://bot.example.com/hook",
+			Events: []globalEntities.WebhookEvent{
+				globalEntities.WebhookEventPush, globalEntities.WebhookEventPullRequest,
+			},
+			Secret: "s3cret",
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "s3cret", body["token"])
+		assert.Equal(t, true, body["push_events"])
+		assert.Equal(t, true, body["tag_push_events"])
+		assert.Equal(t, true, body["merge_requests_events"])
+		assert.Equal(t, false, body["note_events"])
+		assert.Equal(t, &globalEntities.Webhook{
+			ID:         "12",
+			Repository: repo,
+			URL:        "https://bot.example.com/hook",
+			Events: []globalEntities.WebhookEvent{
+				globalEntities.WebhookEventPullRequest, globalEntities.WebhookEventPush,
+			},
+			ContentType: globalEntities.WebhookContentTypeJSON,
+			Active:      true,
+		}, webhook)
+	})
+
+	t.Run("should reject form payloads", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		server := httptest.NewServer(http.NewServeMux())
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		_, err := p.CreateWebhook(context.Background(), repo, globalEntities.WebhookInput{
+			URL: "https://bot.example.com/hook", ContentType: globalEntities.WebhookContentTypeForm,
+		})
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
+
+func TestListWebhookDeliveriesInternal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should read the hook's event log", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		mux := http.NewServeMux()
+		mux.HandleFunc(
+			"GET /api/v4/projects/my-group%2Fmy-repo/hooks/12/events",
+			func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`[
+					{"id":7,"trigger":"push_hooks","response_status":"200","created_at":"2026-03-04T05:06:07Z"},
+					{"id":6,"trigger":"merge_request_hooks","response_status":"internal error",
+						"created_at":"2026-03-04T05:00:00Z"}
+				]`))
+			},
+		)
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		p := newTestProvider(t, server)
+		repo := globalEntities.Repository{Organization: "my-group", Name: "my-repo"}
+
+		// when
+		deliveries, err := p.ListWebhookDeliveries(context.Background(), repo, "12")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []globalEntities.WebhookDelivery{
+			{ID: "7", Event: "push_hooks", StatusCode: 200, DeliveredAt: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)},
+			{ID: "6", Event: "merge_request_hooks", DeliveredAt: time.Date(2026, 3, 4, 5, 0, 0, 0, time.UTC)},
+		}, deliveries)
+	})
+}
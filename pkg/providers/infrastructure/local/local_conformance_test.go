@@ -0,0 +1,133 @@
+package local_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/local"
+	"github.com/rios0rios0/gitforge/test/conformance"
+)
+
+// conformanceBackend is a forge root directory whose bare repositories are
+// seeded and read back with go-git, independently of the provider.
+type conformanceBackend struct {
+	rootDir string
+}
+
+func (b *conformanceBackend) repoDir(repo globalEntities.Repository) string {
+	return filepath.Join(b.rootDir, repo.Organization, repo.Name+".git")
+}
+
+func (b *conformanceBackend) SeedRepository(
+	t *testing.T,
+	org, name string,
+	files map[string]string,
+	tags ...string,
+) globalEntities.Repository {
+	t.Helper()
+
+	dir := filepath.Join(b.rootDir, org, name+".git")
+	createBareRepository(t, dir, files, tags...)
+	return globalEntities.Repository{
+		ID:            org + "/" + name + ".git",
+		Name:          name,
+		Organization:  org,
+		DefaultBranch: "refs/heads/main",
+		RemoteURL:     "file://" + filepath.ToSlash(dir),
+		SSHURL:        "file://" + filepath.ToSlash(dir),
+		ProviderName:  "local",
+	}
+}
+
+func (b *conformanceBackend) CreateBranch(
+	t *testing.T,
+	repo globalEntities.Repository,
+	branch string,
+	files map[string]string,
+) {
+	t.Helper()
+
+	workDir := t.TempDir()
+	work, err := git.PlainClone(workDir, false, &git.CloneOptions{URL: b.repoDir(repo)})
+	require.NoError(t, err)
+	worktree, err := work.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: true,
+	}))
+
+	for name, content := range files {
+		path := filepath.Join(workDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+	_, err = worktree.Commit("conformance: "+branch, &git.CommitOptions{
+		Author: &object.Signature{Name: "dev", Email: "dev@example.org", When: time.Now()},
+	})
+	require.NoError(t, err)
+	require.NoError(t, work.Push(&git.PushOptions{}))
+}
+
+func (b *conformanceBackend) FileContent(
+	t *testing.T,
+	repo globalEntities.Repository,
+	branch, path string,
+) (string, bool) {
+	t.Helper()
+
+	bare, err := git.PlainOpen(b.repoDir(repo))
+	require.NoError(t, err)
+	ref, err := bare.Reference(plumbing.NewBranchReferenceName(branch), true)
+	require.NoError(t, err)
+	commit, err := bare.CommitObject(ref.Hash())
+	require.NoError(t, err)
+
+	file, err := commit.File(path)
+	if errors.Is(err, object.ErrFileNotFound) {
+		return "", false
+	}
+	require.NoError(t, err)
+	content, err := file.Contents()
+	require.NoError(t, err)
+	return content, true
+}
+
+func (b *conformanceBackend) BranchExists(t *testing.T, repo globalEntities.Repository, branch string) bool {
+	t.Helper()
+
+	bare, err := git.PlainOpen(b.repoDir(repo))
+	require.NoError(t, err)
+	_, err = bare.Reference(plumbing.NewBranchReferenceName(branch), false)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestProviderConformance(t *testing.T) {
+	t.Parallel()
+
+	conformance.Run(t, conformance.Harness{
+		NewBackend: func(t *testing.T) conformance.Backend {
+			return &conformanceBackend{rootDir: t.TempDir()}
+		},
+		NewProvider: func(t *testing.T, backend conformance.Backend) globalEntities.ForgeProvider {
+			provider, err := local.NewProvider(backend.(*conformanceBackend).rootDir, "bot")
+			require.NoError(t, err)
+			return provider
+		},
+	})
+}
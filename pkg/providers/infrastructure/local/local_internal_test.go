@@ -0,0 +1,125 @@
+package local
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+func blob(id byte) treeFile {
+	return treeFile{hash: plumbing.Hash{id}, mode: filemode.Regular}
+}
+
+func TestMergeTrees(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should take the side that changed each path", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		base := map[string]treeFile{"kept": blob(1), "ours": blob(2), "theirs": blob(3), "deleted": blob(4)}
+		ours := map[string]treeFile{"kept": blob(1), "ours": blob(20), "theirs": blob(3), "deleted": blob(4)}
+		theirs := map[string]treeFile{"kept": blob(1), "ours": blob(2), "theirs": blob(30), "added": blob(5)}
+
+		// when
+		merged, conflicts := mergeTrees(base, ours, theirs)
+
+		// then
+		assert.Empty(t, conflicts)
+		assert.Equal(t, map[string]treeFile{
+			"kept":   blob(1),
+			"ours":   blob(20),
+			"theirs": blob(30),
+			"added":  blob(5),
+		}, merged)
+	})
+
+	t.Run("should report paths changed differently on both sides", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		base := map[string]treeFile{"go.mod": blob(1), "removed": blob(2)}
+		ours := map[string]treeFile{"go.mod": blob(10)}
+		theirs := map[string]treeFile{"go.mod": blob(11), "removed": blob(20)}
+
+		// when
+		_, conflicts := mergeTrees(base, ours, theirs)
+
+		// then
+		assert.Equal(t, []string{"go.mod", "removed"}, conflicts)
+	})
+}
+
+func TestBuildTree(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should store nested trees in git entry order", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		storage := memory.NewStorage()
+		hash, err := writeBlob(storage, "content\n")
+		require.NoError(t, err)
+		file := treeFile{hash: hash, mode: filemode.Regular}
+
+		// when
+		root, err := buildTree(storage, map[string]treeFile{"a.txt": file, "a/b.txt": file, "a-b": file})
+
+		// then
+		require.NoError(t, err)
+		tree, err := object.GetTree(storage, root)
+		require.NoError(t, err)
+		names := make([]string, 0, len(tree.Entries))
+		for _, entry := range tree.Entries {
+			names = append(names, entry.Name)
+		}
+		assert.Equal(t, []string{"a-b", "a.txt", "a"}, names)
+		nested, err := tree.File("a/b.txt")
+		require.NoError(t, err)
+		assert.Equal(t, hash, nested.Hash)
+	})
+}
+
+func TestProviderMetadata(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should persist pull requests as JSON beside the repository", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rootDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(rootDir+"/tools/my-repo.git/objects", 0o755))
+		require.NoError(t, os.MkdirAll(rootDir+"/tools/my-repo.git/refs", 0o755))
+		require.NoError(t, os.WriteFile(rootDir+"/tools/my-repo.git/HEAD", []byte("ref: refs/heads/main\n"), 0o600))
+		forge, err := NewProvider(rootDir, "")
+		require.NoError(t, err)
+		p := forge.(*Provider)
+		repo := globalEntities.Repository{Organization: "tools", Name: "my-repo"}
+
+		// when
+		err = p.updateMetadata(repo, func(md *metadata) error {
+			md.NextPullRequestID++
+			md.PullRequests = append(md.PullRequests, &pullRequestRecord{ID: 1, Status: statusOpen, Author: p.author()})
+			return nil
+		})
+
+		// then
+		require.NoError(t, err)
+		data, readErr := os.ReadFile(rootDir + "/tools/my-repo.forge.json")
+		require.NoError(t, readErr)
+		var stored metadata
+		require.NoError(t, json.Unmarshal(data, &stored))
+		assert.Equal(t, 1, stored.NextPullRequestID)
+		require.Len(t, stored.PullRequests, 1)
+		assert.Equal(t, "local", stored.PullRequests[0].Author)
+	})
+}
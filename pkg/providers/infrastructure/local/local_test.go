@@ -0,0 +1,553 @@
+package local_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/local"
+)
+
+// newForge creates a forge root holding the bare repository "tools/my-repo",
+// seeded with files on "main" and tagged v1.0.0.
+func newForge(t *testing.T, files map[string]string) (string, *local.Provider, globalEntities.Repository) {
+	t.Helper()
+
+	rootDir := t.TempDir()
+	createBareRepository(t, filepath.Join(rootDir, "tools", "my-repo.git"), files, "v1.0.0")
+
+	provider, err := local.NewProvider(rootDir, "bot")
+	require.NoError(t, err)
+
+	repos, err := provider.DiscoverRepositories(context.Background(), "tools")
+	require.NoError(t, err)
+	require.Len(t, repos, 1)
+
+	return rootDir, provider.(*local.Provider), repos[0]
+}
+
+// createBareRepository builds a working repository with one commit of files
+// and the given tags, and clones it as a bare repository into dir.
+func createBareRepository(t *testing.T, dir string, files map[string]string, tags ...string) {
+	t.Helper()
+
+	workDir := t.TempDir()
+	work, err := git.PlainInitWithOptions(workDir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.Main},
+	})
+	require.NoError(t, err)
+	worktree, err := work.Worktree()
+	require.NoError(t, err)
+
+	for name, content := range files {
+		path := filepath.Join(workDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+	head, err := worktree.Commit("initial commit", &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            &object.Signature{Name: "dev", Email: "dev@example.org", When: time.Now()},
+	})
+	require.NoError(t, err)
+	for _, tag := range tags {
+		_, err = work.CreateTag(tag, head, nil)
+		require.NoError(t, err)
+	}
+
+	_, err = git.PlainClone(dir, true, &git.CloneOptions{URL: workDir})
+	require.NoError(t, err)
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create provider with given token", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rootDir := t.TempDir()
+
+		// when
+		provider, err := local.NewProvider(rootDir, "bot")
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+		assert.Equal(t, "bot", provider.AuthToken())
+		assert.Equal(t, "local", provider.Name())
+	})
+
+	t.Run("should return error when the directory does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rootDir := filepath.Join(t.TempDir(), "missing")
+
+		// when
+		provider, err := local.NewProvider(rootDir, "")
+
+		// then
+		require.Error(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("should return error when the path is a file", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		path := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+
+		// when
+		provider, err := local.NewProvider(path, "")
+
+		// then
+		require.Error(t, err)
+		assert.Nil(t, provider)
+	})
+}
+
+func TestProviderMatchesURL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should match file URLs below the root directory", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, nil)
+
+		// when
+		result := provider.MatchesURL(repo.RemoteURL)
+
+		// then
+		assert.True(t, result)
+	})
+
+	t.Run("should not match other paths or hosted URLs", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, _ := newForge(t, nil)
+
+		// when
+		outside := provider.MatchesURL("file://" + t.TempDir())
+		hosted := provider.MatchesURL("https://github.com/org/my-repo.git")
+
+		// then
+		assert.False(t, outside)
+		assert.False(t, hosted)
+	})
+}
+
+func TestProviderDiscoverRepositories(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list bare repositories and skip everything else", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rootDir, provider, _ := newForge(t, nil)
+		createBareRepository(t, filepath.Join(rootDir, "tools", "another"), nil)
+		require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "tools", "not-a-repo"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(rootDir, "tools", "my-repo.forge.json"), []byte("{}"), 0o600))
+
+		// when
+		repos, err := provider.DiscoverRepositories(context.Background(), "tools")
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, repos, 2)
+		assert.Equal(t, "another", repos[0].Name)
+		assert.Equal(t, "my-repo", repos[1].Name)
+		assert.Equal(t, "tools", repos[1].Organization)
+		assert.Equal(t, "refs/heads/main", repos[1].DefaultBranch)
+		assert.Equal(t, "local", repos[1].ProviderName)
+		assert.Equal(t, "file://"+filepath.ToSlash(filepath.Join(rootDir, "tools", "my-repo.git")), repos[1].RemoteURL)
+	})
+
+	t.Run("should return error when the organization does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, _ := newForge(t, nil)
+
+		// when
+		repos, err := provider.DiscoverRepositories(context.Background(), "missing")
+
+		// then
+		require.Error(t, err)
+		assert.Nil(t, repos)
+	})
+}
+
+func TestProviderFileAccess(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should read files and tags from the default branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{
+			"go.mod":          "module example.com/app\n",
+			"cmd/app/main.go": "package main\n",
+		})
+		ctx := context.Background()
+
+		// when
+		content, contentErr := provider.GetFileContent(ctx, repo, "go.mod")
+		files, listErr := provider.ListFiles(ctx, repo, ".go")
+		tags, tagsErr := provider.GetTags(ctx, repo)
+
+		// then
+		require.NoError(t, contentErr)
+		require.NoError(t, listErr)
+		require.NoError(t, tagsErr)
+		assert.Equal(t, "module example.com/app\n", content)
+		require.Len(t, files, 1)
+		assert.Equal(t, "cmd/app/main.go", files[0].Path)
+		assert.NotEmpty(t, files[0].ObjectID)
+		assert.Equal(t, []string{"v1.0.0"}, tags)
+		assert.True(t, provider.HasFile(ctx, repo, "go.mod"))
+		assert.False(t, provider.HasFile(ctx, repo, "missing.txt"))
+	})
+
+	t.Run("should create a branch with added, edited and deleted files", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{
+			"go.mod":     "module example.com/app\n",
+			"README.md":  "# app\n",
+			"docs/a.txt": "a\n",
+		})
+		ctx := context.Background()
+
+		// when
+		err := provider.CreateBranchWithChanges(ctx, repo, globalEntities.BranchInput{
+			BranchName:    "chore/bump",
+			BaseBranch:    "main",
+			CommitMessage: "chore: bump",
+			Changes: []globalEntities.FileChange{
+				{Path: "go.mod", Content: "module example.com/app\n\ngo 1.27\n", ChangeType: "edit"},
+				{Path: "README.md", ChangeType: "delete"},
+				{Path: "docs/sub/b.txt", Content: "b\n", ChangeType: "add"},
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		branch := repo
+		branch.DefaultBranch = "refs/heads/chore/bump"
+		files, listErr := provider.ListFiles(ctx, branch, "")
+		require.NoError(t, listErr)
+		paths := make([]string, 0, len(files))
+		for _, f := range files {
+			paths = append(paths, f.Path)
+		}
+		assert.Equal(t, []string{"docs/a.txt", "docs/sub/b.txt", "go.mod"}, paths)
+		content, contentErr := provider.GetFileContent(ctx, branch, "go.mod")
+		require.NoError(t, contentErr)
+		assert.Equal(t, "module example.com/app\n\ngo 1.27\n", content)
+		assert.True(t, provider.HasFile(ctx, repo, "README.md"))
+	})
+
+	t.Run("should return error when the branch already exists", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+
+		// when
+		err := provider.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName: "main",
+			BaseBranch: "main",
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+
+	t.Run("should return error for an unsupported change type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+
+		// when
+		err := provider.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName: "chore/bump",
+			BaseBranch: "main",
+			Changes:    []globalEntities.FileChange{{Path: "go.mod", ChangeType: "rename"}},
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported change type "rename"`)
+	})
+}
+
+// openPullRequest creates a branch editing go.mod and opens a pull request for it.
+func openPullRequest(
+	t *testing.T,
+	provider *local.Provider,
+	repo globalEntities.Repository,
+	branch, content string,
+) *globalEntities.PullRequest {
+	t.Helper()
+	ctx := context.Background()
+
+	require.NoError(t, provider.CreateBranchWithChanges(ctx, repo, globalEntities.BranchInput{
+		BranchName:    branch,
+		BaseBranch:    "main",
+		CommitMessage: "chore: bump",
+		Changes:       []globalEntities.FileChange{{Path: "go.mod", Content: content, ChangeType: "edit"}},
+	}))
+
+	pr, err := provider.CreatePullRequest(ctx, repo, globalEntities.PullRequestInput{
+		SourceBranch: "refs/heads/" + branch,
+		TargetBranch: "refs/heads/main",
+		Title:        "chore: bump",
+		Description:  "Bumps the Go version.",
+	})
+	require.NoError(t, err)
+	return pr
+}
+
+func TestProviderPullRequests(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should create, find and close a pull request", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		rootDir, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+		ctx := context.Background()
+
+		// when
+		pr := openPullRequest(t, provider, repo, "chore/bump", "module example.com/app\n\ngo 1.27\n")
+		exists, existsErr := provider.PullRequestExists(ctx, repo, "chore/bump")
+		_, duplicateErr := provider.CreatePullRequest(ctx, repo, globalEntities.PullRequestInput{
+			SourceBranch: "chore/bump",
+			TargetBranch: "main",
+		})
+		closed, closeErr := provider.ClosePullRequest(ctx, repo, "chore/bump")
+		closedAgain, closeAgainErr := provider.ClosePullRequest(ctx, repo, "chore/bump")
+
+		// then
+		assert.Equal(t, 1, pr.ID)
+		assert.Equal(t, "open", pr.Status)
+		assert.Equal(t, repo.RemoteURL+"/pulls/1", pr.URL)
+		require.NoError(t, existsErr)
+		assert.True(t, exists)
+		require.Error(t, duplicateErr)
+		require.NoError(t, closeErr)
+		assert.True(t, closed)
+		require.NoError(t, closeAgainErr)
+		assert.False(t, closedAgain)
+		assert.FileExists(t, filepath.Join(rootDir, "tools", "my-repo.forge.json"))
+	})
+
+	t.Run("should return error when the source branch does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+
+		// when
+		pr, err := provider.CreatePullRequest(context.Background(), repo, globalEntities.PullRequestInput{
+			SourceBranch: "missing",
+			TargetBranch: "main",
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Nil(t, pr)
+	})
+}
+
+func TestProviderReview(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should list pull requests with their diff and files", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+		pr := openPullRequest(t, provider, repo, "chore/bump", "module example.com/app\n\ngo 1.27\n")
+		ctx := context.Background()
+
+		// when
+		open, listErr := provider.ListOpenPullRequests(ctx, repo)
+		diff, diffErr := provider.GetPullRequestDiff(ctx, repo, pr.ID)
+		files, filesErr := provider.GetPullRequestFiles(ctx, repo, pr.ID)
+
+		// then
+		require.NoError(t, listErr)
+		require.Len(t, open, 1)
+		assert.Equal(t, "chore/bump", open[0].SourceBranch)
+		assert.Equal(t, "main", open[0].TargetBranch)
+		assert.Equal(t, "bot", open[0].Author)
+		require.NoError(t, diffErr)
+		assert.Contains(t, diff, "diff --git a/go.mod b/go.mod")
+		assert.Contains(t, diff, "+go 1.27")
+		require.NoError(t, filesErr)
+		require.Len(t, files, 1)
+		assert.Equal(t, "go.mod", files[0].Path)
+		assert.Equal(t, "modified", files[0].Status)
+		assert.Equal(t, 2, files[0].Additions)
+		assert.Equal(t, 0, files[0].Deletions)
+	})
+
+	t.Run("should thread comments and replies", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+		pr := openPullRequest(t, provider, repo, "chore/bump", "module example.com/app\n\ngo 1.27\n")
+		ctx := context.Background()
+
+		// when
+		commentErr := provider.PostPullRequestComment(ctx, repo, pr.ID, "looks good")
+		threadID, threadErr := provider.PostPullRequestThreadComment(ctx, repo, pr.ID, "/go.mod", 3, "why 1.27?")
+		replyID, replyErr := provider.ReplyToThread(ctx, repo, pr.ID, threadID, "latest release")
+		statusErr := provider.UpdatePullRequestThreadStatus(ctx, repo, pr.ID, threadID, "fixed")
+		_, missingErr := provider.ReplyToThread(ctx, repo, pr.ID, replyID, "not a thread")
+		comments, listErr := provider.ListPullRequestComments(ctx, repo, pr.ID)
+
+		// then
+		require.NoError(t, commentErr)
+		require.NoError(t, threadErr)
+		require.NoError(t, replyErr)
+		require.NoError(t, statusErr)
+		require.Error(t, missingErr)
+		require.NoError(t, listErr)
+		require.Len(t, comments, 3)
+		assert.Equal(t, "looks good", comments[0].Body)
+		assert.Equal(t, comments[0].ID, comments[0].ThreadID)
+		assert.Equal(t, int64(threadID), comments[1].ID)
+		assert.Equal(t, "go.mod", comments[1].FilePath)
+		assert.Equal(t, 3, comments[1].Line)
+		assert.Equal(t, int64(replyID), comments[2].ID)
+		assert.Equal(t, int64(threadID), comments[2].ThreadID)
+		assert.Equal(t, int64(threadID), comments[2].InReplyToID)
+		assert.Equal(t, "go.mod", comments[2].FilePath)
+		assert.Equal(t, "bot", comments[2].Author)
+	})
+
+	t.Run("should squash merge and delete the source branch", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+		pr := openPullRequest(t, provider, repo, "chore/bump", "module example.com/app\n\ngo 1.27\n")
+		ctx := context.Background()
+
+		// when
+		checks, checksErr := provider.GetPullRequestCheckStatus(ctx, repo, pr.ID)
+		checkResults, checkResultsErr := provider.GetPullRequestChecks(ctx, repo, pr.ID)
+		reviewErr := provider.SubmitPullRequestReview(ctx, repo, pr.ID, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictApprove,
+		})
+		mergeErr := provider.MergePullRequest(ctx, repo, pr.ID, "squash", globalEntities.WithDeleteSourceBranch())
+
+		// then
+		require.NoError(t, checksErr)
+		assert.True(t, checks)
+		require.NoError(t, checkResultsErr)
+		assert.Empty(t, checkResults.Checks)
+		assert.Equal(t, globalEntities.CheckStateSuccess, checkResults.Verdict)
+		require.NoError(t, reviewErr)
+		require.NoError(t, mergeErr)
+		status, statusErr := provider.GetPullRequestStatus(ctx, repo, pr.ID)
+		require.NoError(t, statusErr)
+		assert.Equal(t, "merged", status)
+		content, contentErr := provider.GetFileContent(ctx, repo, "go.mod")
+		require.NoError(t, contentErr)
+		assert.Equal(t, "module example.com/app\n\ngo 1.27\n", content)
+		exists, existsErr := provider.PullRequestExists(ctx, repo, "chore/bump")
+		require.NoError(t, existsErr)
+		assert.False(t, exists)
+		bare, openErr := git.PlainOpen(repo.RemoteURL[len("file://"):])
+		require.NoError(t, openErr)
+		_, refErr := bare.Reference(plumbing.NewBranchReferenceName("chore/bump"), false)
+		require.ErrorIs(t, refErr, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("should return ErrMergeConflict when both branches changed the same file", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+		first := openPullRequest(t, provider, repo, "chore/first", "module example.com/app\n\ngo 1.26\n")
+		second := openPullRequest(t, provider, repo, "chore/second", "module example.com/app\n\ngo 1.27\n")
+		ctx := context.Background()
+		require.NoError(t, provider.MergePullRequest(ctx, repo, first.ID, "merge"))
+
+		// when
+		err := provider.MergePullRequest(ctx, repo, second.ID, "squash")
+
+		// then
+		require.ErrorIs(t, err, local.ErrMergeConflict)
+		status, statusErr := provider.GetPullRequestStatus(ctx, repo, second.ID)
+		require.NoError(t, statusErr)
+		assert.Equal(t, "open", status)
+	})
+
+	t.Run("should refuse a fast-forward when the target has diverged", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+		first := openPullRequest(t, provider, repo, "chore/first", "module example.com/app\n\ngo 1.26\n")
+		second := openPullRequest(t, provider, repo, "chore/second", "module example.com/app\n\ngo 1.27\n")
+		ctx := context.Background()
+		require.NoError(t, provider.MergePullRequest(ctx, repo, first.ID, "fast-forward-only"))
+
+		// when
+		err := provider.MergePullRequest(ctx, repo, second.ID, "fast-forward-only")
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "fast-forward is not possible")
+	})
+
+	t.Run("should return error for an unsupported merge strategy", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, map[string]string{"go.mod": "module example.com/app\n"})
+		pr := openPullRequest(t, provider, repo, "chore/bump", "module example.com/app\n\ngo 1.27\n")
+
+		// when
+		err := provider.MergePullRequest(context.Background(), repo, pr.ID, "rebase")
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported merge strategy "rebase"`)
+	})
+
+	t.Run("should return ErrPullRequestNotFound for an unknown pull request", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		_, provider, repo := newForge(t, nil)
+
+		// when
+		_, err := provider.GetPullRequestStatus(context.Background(), repo, 99)
+
+		// then
+		require.ErrorIs(t, err, local.ErrPullRequestNotFound)
+	})
+}
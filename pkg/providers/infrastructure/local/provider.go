@@ -0,0 +1,161 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	providerName = "local"
+
+	// defaultAuthor is the author of pull requests, comments, reviews, and
+	// commits when the provider is created without a token.
+	defaultAuthor = "local"
+
+	// metadataSuffix is appended to a repository name to form the name of the
+	// JSON file, beside the bare repository, that holds its pull requests.
+	metadataSuffix = ".forge.json"
+)
+
+// ErrRepositoryNotFound is returned when no bare repository exists for a
+// Repository under the provider's root directory.
+var ErrRepositoryNotFound = errors.New("local repository not found")
+
+// Provider implements ForgeProvider, FileAccessProvider, ReviewProvider, ChecksProvider, and
+// LocalGitAuthProvider on top of a directory of bare git repositories, giving
+// tests and developers a fully offline forge.
+//
+// Every subdirectory of the root is an organization and every bare repository
+// inside it ("name.git" or "name") is a repository. Pull requests, comments,
+// and reviews are kept in a "name.forge.json" file beside the repository, and
+// branches, commits, and merges are written straight into the bare repository
+// with go-git. The token is not a credential: it names the author of pull
+// requests, comments, reviews, and commits ("local" when empty).
+//
+// Calls are serialized within one Provider; separate processes sharing a root
+// directory are not coordinated.
+type Provider struct {
+	rootDir string
+	token   string
+	now     func() time.Time
+
+	// mu serializes every metadata read-modify-write and every ref update.
+	mu sync.Mutex
+}
+
+// NewProvider creates a new local provider serving the bare repositories
+// below rootDir, which must be an existing directory.
+func NewProvider(rootDir, token string) (globalEntities.ForgeProvider, error) {
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local forge directory %q: %w", rootDir, err)
+	}
+
+	info, err := os.Stat(absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local forge directory %q: %w", rootDir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("invalid local forge directory %q: not a directory", rootDir)
+	}
+
+	return &Provider{
+		rootDir: absRoot,
+		token:   token,
+		now:     time.Now,
+	}, nil
+}
+
+func (p *Provider) Name() string      { return providerName }
+func (p *Provider) AuthToken() string { return p.token }
+
+// MatchesURL reports whether rawURL is a path, or a file:// URL, below the
+// provider's root directory.
+func (p *Provider) MatchesURL(rawURL string) bool {
+	path := filepath.Clean(strings.TrimPrefix(rawURL, "file://"))
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	return path == p.rootDir || strings.HasPrefix(path, p.rootDir+string(filepath.Separator))
+}
+
+// author returns the name recorded as the author of pull requests, comments,
+// reviews, and commits.
+func (p *Provider) author() string {
+	if p.token == "" {
+		return defaultAuthor
+	}
+	return p.token
+}
+
+// orgDir returns the directory holding the repositories of an organization.
+func (p *Provider) orgDir(org string) string {
+	return filepath.Join(p.rootDir, filepath.FromSlash(org))
+}
+
+// repoDir returns the directory of the bare repository, preferring
+// "name.git" over "name".
+func (p *Provider) repoDir(repo globalEntities.Repository) (string, error) {
+	orgDir := p.orgDir(repo.Organization)
+	name := strings.TrimSuffix(repo.Name, ".git")
+
+	for _, candidate := range []string{name + ".git", name} {
+		dir := filepath.Join(orgDir, candidate)
+		if isBareRepository(dir) {
+			return dir, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s/%s", ErrRepositoryNotFound, repo.Organization, repo.Name)
+}
+
+// metadataPath returns the path of the JSON file holding the repository's
+// pull requests.
+func (p *Provider) metadataPath(repo globalEntities.Repository) string {
+	name := strings.TrimSuffix(repo.Name, ".git")
+	return filepath.Join(p.orgDir(repo.Organization), name+metadataSuffix)
+}
+
+// --- LocalGitAuthProvider ---
+
+func (p *Provider) GetServiceType() globalEntities.ServiceType {
+	return globalEntities.LOCAL
+}
+
+func (p *Provider) PrepareCloneURL(cloneURL string) string {
+	return cloneURL
+}
+
+func (p *Provider) ConfigureTransport() {
+	// the file transport needs no configuration
+}
+
+// GetAuthMethods returns no auth methods: the file transport does not
+// authenticate.
+func (p *Provider) GetAuthMethods(_ string) []transport.AuthMethod {
+	return nil
+}
+
+// CloneURL returns the file:// URL of the bare repository. When the
+// repository does not exist yet, the URL it would have as "name.git" is returned.
+func (p *Provider) CloneURL(repo globalEntities.Repository) string {
+	dir, err := p.repoDir(repo)
+	if err != nil {
+		dir = filepath.Join(p.orgDir(repo.Organization), strings.TrimSuffix(repo.Name, ".git")+".git")
+	}
+	return "file://" + filepath.ToSlash(dir)
+}
+
+// SSHCloneURL returns the same file:// URL as CloneURL: repositories on the
+// local filesystem are not served over SSH, so sshAlias is ignored.
+func (p *Provider) SSHCloneURL(repo globalEntities.Repository, _ string) string {
+	return p.CloneURL(repo)
+}
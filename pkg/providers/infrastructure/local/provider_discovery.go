@@ -0,0 +1,76 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// DiscoverRepositories lists every bare repository directly inside the
+// organization's directory. Other files and non-repository directories are skipped.
+func (p *Provider) DiscoverRepositories(
+	_ context.Context,
+	org string,
+) ([]globalEntities.Repository, error) {
+	entries, err := os.ReadDir(p.orgDir(org))
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover repositories for %q: %w", org, err)
+	}
+
+	var repos []globalEntities.Repository
+	for _, entry := range entries {
+		dir := filepath.Join(p.orgDir(org), entry.Name())
+		if !entry.IsDir() || !isBareRepository(dir) {
+			continue
+		}
+
+		repo := globalEntities.Repository{
+			ID:            filepath.ToSlash(filepath.Join(org, entry.Name())),
+			Name:          strings.TrimSuffix(entry.Name(), ".git"),
+			Organization:  org,
+			DefaultBranch: defaultBranch(dir),
+			ProviderName:  providerName,
+		}
+		repo.RemoteURL = p.CloneURL(repo)
+		repo.SSHURL = repo.RemoteURL
+		repos = append(repos, repo)
+	}
+
+	sort.Slice(repos, func(i, j int) bool { return repos[i].Name < repos[j].Name })
+	return repos, nil
+}
+
+// isBareRepository reports whether dir has the layout of a bare repository.
+func isBareRepository(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultBranch returns the branch HEAD points at, falling back to
+// "refs/heads/main" when it cannot be read.
+func defaultBranch(dir string) string {
+	const fallback = "refs/heads/main"
+
+	gitRepo, err := git.PlainOpen(dir)
+	if err != nil {
+		return fallback
+	}
+
+	head, err := gitRepo.Reference(plumbing.HEAD, false)
+	if err != nil || head.Type() != plumbing.SymbolicReference {
+		return fallback
+	}
+
+	return head.Target().String()
+}
@@ -0,0 +1,202 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+)
+
+func (p *Provider) GetFileContent(
+	_ context.Context,
+	repo globalEntities.Repository,
+	path string,
+) (string, error) {
+	gitRepo, err := p.openRepository(repo)
+	if err != nil {
+		return "", err
+	}
+
+	commit, err := branchCommit(gitRepo, repo.DefaultBranch)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := commit.File(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get file %q: %w", path, err)
+	}
+
+	content, err := file.Contents()
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+	return content, nil
+}
+
+// ListFiles lists every file of the default branch; directories are never listed.
+func (p *Provider) ListFiles(
+	_ context.Context,
+	repo globalEntities.Repository,
+	pattern string,
+) ([]globalEntities.File, error) {
+	gitRepo, err := p.openRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := branchCommit(gitRepo, repo.DefaultBranch)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := flattenTree(commit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var files []globalEntities.File
+	for path, file := range tree {
+		if pattern != "" && !strings.HasSuffix(path, pattern) {
+			continue
+		}
+		files = append(files, globalEntities.File{Path: path, ObjectID: file.hash.String()})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func (p *Provider) GetTags(
+	_ context.Context,
+	repo globalEntities.Repository,
+) ([]string, error) {
+	gitRepo, err := p.openRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := gitRepo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	var allTags []string
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		allTags = append(allTags, ref.Name().Short())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	globalHelpers.SortVersionsDescending(allTags)
+	return allTags, nil
+}
+
+func (p *Provider) HasFile(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	path string,
+) bool {
+	_, err := p.GetFileContent(ctx, repo, path)
+	return err == nil
+}
+
+// CreateBranchWithChanges writes one commit holding every change on top of the
+// base branch head and points the new branch at it. The branch must not exist yet.
+func (p *Provider) CreateBranchWithChanges(
+	_ context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.BranchInput,
+) error {
+	for _, change := range input.Changes {
+		changeType := strings.ToLower(strings.TrimSpace(change.ChangeType))
+
+		// treat unknown, non-empty change types as an error
+		if changeType != "" && changeType != "add" && changeType != "edit" && changeType != "create" &&
+			changeType != "update" && changeType != "delete" {
+			return fmt.Errorf("unsupported change type %q for file %q", change.ChangeType, change.Path)
+		}
+	}
+
+	gitRepo, err := p.openRepository(repo)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	branchRef := branchRefName(input.BranchName)
+	if _, refErr := gitRepo.Reference(branchRef, false); refErr == nil {
+		return fmt.Errorf("branch %q already exists", input.BranchName)
+	} else if !errors.Is(refErr, plumbing.ErrReferenceNotFound) {
+		return fmt.Errorf("failed to check branch %q: %w", input.BranchName, refErr)
+	}
+
+	base, err := branchCommit(gitRepo, input.BaseBranch)
+	if err != nil {
+		return err
+	}
+
+	head := base.Hash
+	if len(input.Changes) > 0 {
+		head, err = p.commitChanges(gitRepo.Storer, base, input)
+		if err != nil {
+			return fmt.Errorf("failed to commit changes on branch %q: %w", input.BranchName, err)
+		}
+	}
+
+	if setErr := gitRepo.Storer.SetReference(plumbing.NewHashReference(branchRef, head)); setErr != nil {
+		return fmt.Errorf("failed to create branch %q: %w", input.BranchName, setErr)
+	}
+	return nil
+}
+
+// commitChanges applies the changes to the tree of the base commit and returns
+// the hash of the resulting commit.
+func (p *Provider) commitChanges(
+	s storer.EncodedObjectStorer,
+	base *object.Commit,
+	input globalEntities.BranchInput,
+) (plumbing.Hash, error) {
+	files, err := flattenTree(base)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	for _, change := range input.Changes {
+		filePath := strings.TrimPrefix(change.Path, "/")
+		if strings.EqualFold(strings.TrimSpace(change.ChangeType), "delete") {
+			delete(files, filePath)
+			continue
+		}
+
+		hash, blobErr := writeBlob(s, change.Content)
+		if blobErr != nil {
+			return plumbing.ZeroHash, blobErr
+		}
+
+		mode := filemode.Regular
+		if existing, ok := files[filePath]; ok {
+			mode = existing.mode
+		}
+		files[filePath] = treeFile{hash: hash, mode: mode}
+	}
+
+	tree, err := buildTree(s, files)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return p.writeCommit(s, tree, []plumbing.Hash{base.Hash}, input.CommitMessage)
+}
@@ -0,0 +1,264 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// ErrMergeConflict is returned by MergePullRequest when the source and target
+// branches changed the same file in different ways.
+var ErrMergeConflict = errors.New("merge conflict")
+
+// treeFile is one blob of a flattened tree.
+type treeFile struct {
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+// openRepository opens the bare repository backing repo.
+func (p *Provider) openRepository(repo globalEntities.Repository) (*git.Repository, error) {
+	dir, err := p.repoDir(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	gitRepo, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository %q: %w", dir, err)
+	}
+	return gitRepo, nil
+}
+
+// branchRefName returns the full reference name of a branch given either its
+// short or its "refs/heads/" form.
+func branchRefName(branch string) plumbing.ReferenceName {
+	return plumbing.NewBranchReferenceName(strings.TrimPrefix(branch, "refs/heads/"))
+}
+
+// branchCommit returns the head commit of a branch. An empty branch resolves HEAD.
+func branchCommit(gitRepo *git.Repository, branch string) (*object.Commit, error) {
+	name := plumbing.HEAD
+	if branch != "" {
+		name = branchRefName(branch)
+	}
+
+	ref, err := gitRepo.Reference(name, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve branch %q: %w", branch, err)
+	}
+
+	commit, err := gitRepo.CommitObject(ref.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", ref.Hash(), err)
+	}
+	return commit, nil
+}
+
+// mergeBase returns the best common ancestor of two commits, or nil when they
+// share no history.
+func mergeBase(a, b *object.Commit) (*object.Commit, error) {
+	bases, err := a.MergeBase(b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute merge base: %w", err)
+	}
+	if len(bases) == 0 {
+		return nil, nil //nolint:nilnil // unrelated histories have no merge base
+	}
+	return bases[0], nil
+}
+
+// flattenTree maps every blob path of a commit's tree to its hash and mode. A
+// nil commit yields an empty map.
+func flattenTree(commit *object.Commit) (map[string]treeFile, error) {
+	files := make(map[string]treeFile)
+	if commit == nil {
+		return files, nil
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tree of %s: %w", commit.Hash, err)
+	}
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files[f.Name] = treeFile{hash: f.Hash, mode: f.Mode}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk tree of %s: %w", commit.Hash, err)
+	}
+	return files, nil
+}
+
+// mergeTrees performs a file-level three-way merge. A path changed on only one
+// side takes that side's version; a path changed identically on both sides is
+// kept; any other path is reported as a conflict.
+func mergeTrees(base, ours, theirs map[string]treeFile) (map[string]treeFile, []string) {
+	paths := make(map[string]struct{})
+	for _, files := range []map[string]treeFile{base, ours, theirs} {
+		for p := range files {
+			paths[p] = struct{}{}
+		}
+	}
+
+	merged := make(map[string]treeFile)
+	var conflicts []string
+	for p := range paths {
+		b, inBase := base[p]
+		o, inOurs := ours[p]
+		t, inTheirs := theirs[p]
+
+		var result treeFile
+		var keep bool
+		switch {
+		case inOurs == inTheirs && o == t:
+			result, keep = o, inOurs
+		case inOurs == inBase && o == b:
+			result, keep = t, inTheirs
+		case inTheirs == inBase && t == b:
+			result, keep = o, inOurs
+		default:
+			conflicts = append(conflicts, p)
+			continue
+		}
+		if keep {
+			merged[p] = result
+		}
+	}
+
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
+
+// writeBlob stores content as a blob object.
+func writeBlob(s storer.EncodedObjectStorer, content string) (plumbing.Hash, error) {
+	obj := s.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to write blob: %w", err)
+	}
+	if _, writeErr := w.Write([]byte(content)); writeErr != nil {
+		_ = w.Close()
+		return plumbing.ZeroHash, fmt.Errorf("failed to write blob: %w", writeErr)
+	}
+	if closeErr := w.Close(); closeErr != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to write blob: %w", closeErr)
+	}
+
+	hash, err := s.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to store blob: %w", err)
+	}
+	return hash, nil
+}
+
+// dirNode is one directory of a tree being built from flattened paths.
+type dirNode struct {
+	files map[string]treeFile
+	dirs  map[string]*dirNode
+}
+
+func newDirNode() *dirNode {
+	return &dirNode{files: make(map[string]treeFile), dirs: make(map[string]*dirNode)}
+}
+
+// buildTree stores the tree objects for a flattened file map and returns the
+// hash of the root tree.
+func buildTree(s storer.EncodedObjectStorer, files map[string]treeFile) (plumbing.Hash, error) {
+	root := newDirNode()
+	for filePath, file := range files {
+		node := root
+		dir, name := path.Split(filePath)
+		for _, segment := range strings.Split(strings.Trim(dir, "/"), "/") {
+			if segment == "" {
+				continue
+			}
+			child, ok := node.dirs[segment]
+			if !ok {
+				child = newDirNode()
+				node.dirs[segment] = child
+			}
+			node = child
+		}
+		node.files[name] = file
+	}
+
+	return writeDir(s, root)
+}
+
+func writeDir(s storer.EncodedObjectStorer, node *dirNode) (plumbing.Hash, error) {
+	entries := make([]object.TreeEntry, 0, len(node.files)+len(node.dirs))
+	for name, file := range node.files {
+		entries = append(entries, object.TreeEntry{Name: name, Mode: file.mode, Hash: file.hash})
+	}
+	for name, child := range node.dirs {
+		hash, err := writeDir(s, child)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: name, Mode: filemode.Dir, Hash: hash})
+	}
+
+	// git orders tree entries as if directory names ended with a slash
+	sortKey := func(e object.TreeEntry) string {
+		if e.Mode == filemode.Dir {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(entries, func(i, j int) bool { return sortKey(entries[i]) < sortKey(entries[j]) })
+
+	obj := s.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode tree: %w", err)
+	}
+	hash, err := s.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to store tree: %w", err)
+	}
+	return hash, nil
+}
+
+// writeCommit stores a commit of tree with the given parents, authored and
+// committed by the provider's author.
+func (p *Provider) writeCommit(
+	s storer.EncodedObjectStorer,
+	tree plumbing.Hash,
+	parents []plumbing.Hash,
+	message string,
+) (plumbing.Hash, error) {
+	signature := object.Signature{
+		Name:  p.author(),
+		Email: p.author() + "@localhost",
+		When:  p.now(),
+	}
+	commit := &object.Commit{
+		Author:       signature,
+		Committer:    signature,
+		Message:      message,
+		TreeHash:     tree,
+		ParentHashes: parents,
+	}
+
+	obj := s.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to encode commit: %w", err)
+	}
+	hash, err := s.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to store commit: %w", err)
+	}
+	return hash, nil
+}
@@ -0,0 +1,184 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// ErrPullRequestNotFound is returned when a pull request ID is not recorded in
+// the repository's metadata file.
+var ErrPullRequestNotFound = errors.New("local pull request not found")
+
+const (
+	statusOpen   = "open"
+	statusClosed = "closed"
+	statusMerged = "merged"
+)
+
+// metadata is the content of the "name.forge.json" file kept beside each bare
+// repository.
+type metadata struct {
+	NextPullRequestID int                  `json:"next_pull_request_id"`
+	NextCommentID     int64                `json:"next_comment_id"`
+	PullRequests      []*pullRequestRecord `json:"pull_requests"`
+}
+
+type pullRequestRecord struct {
+	ID           int              `json:"id"`
+	Title        string           `json:"title"`
+	Description  string           `json:"description,omitempty"`
+	SourceBranch string           `json:"source_branch"`
+	TargetBranch string           `json:"target_branch"`
+	Status       string           `json:"status"`
+	Author       string           `json:"author"`
+	MergeCommit  string           `json:"merge_commit,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	Comments     []*commentRecord `json:"comments,omitempty"`
+	Reviews      []*reviewRecord  `json:"reviews,omitempty"`
+}
+
+// commentRecord is a pull request comment. PR-wide comments have a zero
+// ThreadID; an inline comment opens a thread whose ID is its own, and replies
+// carry the thread ID plus the comment they answer. Status is only set on
+// thread roots.
+type commentRecord struct {
+	ID          int64     `json:"id"`
+	ThreadID    int64     `json:"thread_id,omitempty"`
+	InReplyToID int64     `json:"in_reply_to_id,omitempty"`
+	Body        string    `json:"body"`
+	Author      string    `json:"author"`
+	FilePath    string    `json:"file_path,omitempty"`
+	Line        int       `json:"line,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type reviewRecord struct {
+	Verdict     globalEntities.ReviewVerdict `json:"verdict"`
+	Body        string                       `json:"body,omitempty"`
+	Author      string                       `json:"author"`
+	SubmittedAt time.Time                    `json:"submitted_at"`
+}
+
+// pullRequest returns the pull request with the given ID.
+func (m *metadata) pullRequest(prID int) (*pullRequestRecord, error) {
+	for _, pr := range m.PullRequests {
+		if pr.ID == prID {
+			return pr, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: #%d", ErrPullRequestNotFound, prID)
+}
+
+// openPullRequest returns the open pull request whose source branch is
+// sourceBranch, or nil when there is none.
+func (m *metadata) openPullRequest(sourceBranch string) *pullRequestRecord {
+	for _, pr := range m.PullRequests {
+		if pr.Status == statusOpen && pr.SourceBranch == sourceBranch {
+			return pr
+		}
+	}
+	return nil
+}
+
+// addComment assigns the next comment ID to comment and appends it to pr.
+func (m *metadata) addComment(pr *pullRequestRecord, comment *commentRecord) {
+	m.NextCommentID++
+	comment.ID = m.NextCommentID
+	pr.Comments = append(pr.Comments, comment)
+}
+
+// readMetadata loads the repository's metadata file. A missing file yields
+// empty metadata. Callers must hold p.mu.
+func (p *Provider) readMetadata(repo globalEntities.Repository) (*metadata, error) {
+	if _, err := p.repoDir(repo); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(p.metadataPath(repo))
+	if errors.Is(err, os.ErrNotExist) {
+		return &metadata{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read forge metadata: %w", err)
+	}
+
+	var md metadata
+	if unmarshalErr := json.Unmarshal(data, &md); unmarshalErr != nil {
+		return nil, fmt.Errorf("failed to parse forge metadata: %w", unmarshalErr)
+	}
+	return &md, nil
+}
+
+// writeMetadata replaces the repository's metadata file atomically by writing
+// a temporary file beside it and renaming it into place. Callers must hold p.mu.
+func (p *Provider) writeMetadata(repo globalEntities.Repository, md *metadata) error {
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode forge metadata: %w", err)
+	}
+
+	path := p.metadataPath(repo)
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write forge metadata: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, writeErr := tmp.Write(append(data, '\n')); writeErr != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write forge metadata: %w", writeErr)
+	}
+	if closeErr := tmp.Close(); closeErr != nil {
+		return fmt.Errorf("failed to write forge metadata: %w", closeErr)
+	}
+	if renameErr := os.Rename(tmp.Name(), path); renameErr != nil {
+		return fmt.Errorf("failed to write forge metadata: %w", renameErr)
+	}
+	return nil
+}
+
+// updateMetadata loads the repository's metadata, applies fn, and writes the
+// result back when fn succeeds.
+func (p *Provider) updateMetadata(
+	repo globalEntities.Repository,
+	fn func(md *metadata) error,
+) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	md, err := p.readMetadata(repo)
+	if err != nil {
+		return err
+	}
+	if fnErr := fn(md); fnErr != nil {
+		return fnErr
+	}
+	return p.writeMetadata(repo, md)
+}
+
+// viewPullRequest loads the repository's metadata and returns a copy of the
+// pull request with the given ID.
+func (p *Provider) viewPullRequest(
+	repo globalEntities.Repository,
+	prID int,
+) (pullRequestRecord, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	md, err := p.readMetadata(repo)
+	if err != nil {
+		return pullRequestRecord{}, err
+	}
+	pr, err := md.pullRequest(prID)
+	if err != nil {
+		return pullRequestRecord{}, err
+	}
+	return *pr, nil
+}
@@ -0,0 +1,105 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// pullRequestURL returns a stable, file-based URL identifying a pull request.
+func (p *Provider) pullRequestURL(repo globalEntities.Repository, prID int) string {
+	return fmt.Sprintf("%s/pulls/%d", p.CloneURL(repo), prID)
+}
+
+// CreatePullRequest records a new open pull request in the repository's
+// metadata. Both branches must exist. AutoComplete is ignored: nothing merges
+// pull requests in the background.
+func (p *Provider) CreatePullRequest(
+	_ context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.PullRequestInput,
+) (*globalEntities.PullRequest, error) {
+	gitRepo, err := p.openRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceBranch := strings.TrimPrefix(input.SourceBranch, "refs/heads/")
+	targetBranch := strings.TrimPrefix(input.TargetBranch, "refs/heads/")
+	for _, branch := range []string{sourceBranch, targetBranch} {
+		if _, branchErr := branchCommit(gitRepo, branch); branchErr != nil {
+			return nil, fmt.Errorf("failed to create pull request: %w", branchErr)
+		}
+	}
+
+	var created *pullRequestRecord
+	err = p.updateMetadata(repo, func(md *metadata) error {
+		if existing := md.openPullRequest(sourceBranch); existing != nil {
+			return fmt.Errorf(
+				"failed to create pull request: #%d is already open for branch %q", existing.ID, sourceBranch,
+			)
+		}
+
+		md.NextPullRequestID++
+		created = &pullRequestRecord{
+			ID:           md.NextPullRequestID,
+			Title:        input.Title,
+			Description:  input.Description,
+			SourceBranch: sourceBranch,
+			TargetBranch: targetBranch,
+			Status:       statusOpen,
+			Author:       p.author(),
+			CreatedAt:    p.now().UTC(),
+		}
+		md.PullRequests = append(md.PullRequests, created)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &globalEntities.PullRequest{
+		ID:     created.ID,
+		Title:  created.Title,
+		URL:    p.pullRequestURL(repo, created.ID),
+		Status: created.Status,
+	}, nil
+}
+
+func (p *Provider) PullRequestExists(
+	_ context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	md, err := p.readMetadata(repo)
+	if err != nil {
+		return false, fmt.Errorf("failed to check pull requests: %w", err)
+	}
+	return md.openPullRequest(strings.TrimPrefix(sourceBranch, "refs/heads/")) != nil, nil
+}
+
+func (p *Provider) ClosePullRequest(
+	_ context.Context,
+	repo globalEntities.Repository,
+	sourceBranch string,
+) (bool, error) {
+	var closed bool
+	err := p.updateMetadata(repo, func(md *metadata) error {
+		pr := md.openPullRequest(strings.TrimPrefix(sourceBranch, "refs/heads/"))
+		if pr == nil {
+			return nil
+		}
+		pr.Status = statusClosed
+		closed = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to close pull request: %w", err)
+	}
+	return closed, nil
+}
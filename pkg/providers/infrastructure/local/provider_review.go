@@ -0,0 +1,542 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	globalHelpers "github.com/rios0rios0/gitforge/pkg/global/domain/helpers"
+	log "github.com/sirupsen/logrus"
+)
+
+// --- ReviewProvider ---
+
+func (p *Provider) ListOpenPullRequests(
+	_ context.Context,
+	repo globalEntities.Repository,
+) ([]globalEntities.PullRequestDetail, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	md, err := p.readMetadata(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	var result []globalEntities.PullRequestDetail
+	for _, pr := range md.PullRequests {
+		if pr.Status != statusOpen {
+			continue
+		}
+		result = append(result, globalEntities.PullRequestDetail{
+			PullRequest: globalEntities.PullRequest{
+				ID:     pr.ID,
+				Title:  pr.Title,
+				URL:    p.pullRequestURL(repo, pr.ID),
+				Status: pr.Status,
+			},
+			SourceBranch: pr.SourceBranch,
+			TargetBranch: pr.TargetBranch,
+			Author:       pr.Author,
+		})
+	}
+
+	return result, nil
+}
+
+// GetPullRequestDiff returns the diff between the merge base of both branches
+// and the source branch head, like a three-dot `git diff`.
+func (p *Provider) GetPullRequestDiff(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	patch, err := p.pullRequestPatch(ctx, repo, prID)
+	if err != nil {
+		return "", err
+	}
+	return patch.String(), nil
+}
+
+func (p *Provider) GetPullRequestFiles(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestFile, error) {
+	patch, err := p.pullRequestPatch(ctx, repo, prID)
+	if err != nil {
+		return nil, err
+	}
+	patches := globalHelpers.SplitDiffByFile(patch.String())
+
+	filePatches := patch.FilePatches()
+	result := make([]globalEntities.PullRequestFile, 0, len(filePatches))
+	for _, fp := range filePatches {
+		from, to := fp.Files()
+
+		file := globalEntities.PullRequestFile{Status: "modified"}
+		switch {
+		case from == nil:
+			file.Path = to.Path()
+			file.Status = "added"
+		case to == nil:
+			file.Path = from.Path()
+			file.Status = "deleted"
+		default:
+			file.Path = to.Path()
+			if from.Path() != to.Path() {
+				file.OldPath = from.Path()
+				file.Status = "renamed"
+			}
+		}
+
+		file.Patch = patches[file.Path]
+		file.Additions, file.Deletions = countDiffLines(file.Patch)
+		result = append(result, file)
+	}
+
+	return result, nil
+}
+
+// pullRequestPatch computes the patch from the merge base of the pull
+// request's branches to its source branch head.
+func (p *Provider) pullRequestPatch(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*object.Patch, error) {
+	pr, err := p.viewPullRequest(repo, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+
+	gitRepo, err := p.openRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := branchCommit(gitRepo, pr.SourceBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+	target, err := branchCommit(gitRepo, pr.TargetBranch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+
+	base, err := mergeBase(target, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+	if base == nil {
+		base = target
+	}
+
+	patch, err := base.PatchContext(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pull request diff: %w", err)
+	}
+	return patch, nil
+}
+
+// countDiffLines counts the added and removed lines of a single-file patch,
+// skipping the `+++` and `---` file headers.
+func countDiffLines(patch string) (int, int) {
+	var additions, deletions int
+	for line := range strings.SplitSeq(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			continue
+		case strings.HasPrefix(line, "+"):
+			additions++
+		case strings.HasPrefix(line, "-"):
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// ListPullRequestComments returns every comment in the order it was posted.
+// Every top-level comment, PR-wide or inline, opens a thread whose ID is its
+// own; replies carry the thread ID and the ID of the thread root.
+func (p *Provider) ListPullRequestComments(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) ([]globalEntities.PullRequestComment, error) {
+	pr, err := p.viewPullRequest(repo, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request comments: %w", err)
+	}
+
+	result := make([]globalEntities.PullRequestComment, 0, len(pr.Comments))
+	for _, c := range pr.Comments {
+		result = append(result, globalEntities.PullRequestComment{
+			ID:          c.ID,
+			ThreadID:    c.ThreadID,
+			Body:        c.Body,
+			Author:      c.Author,
+			FilePath:    c.FilePath,
+			Line:        c.Line,
+			InReplyToID: c.InReplyToID,
+		})
+	}
+
+	return result, nil
+}
+
+// PostPullRequestComment opens a PR-wide thread. Any status string supplied
+// through entities.WithThreadStatus is stored as the thread status.
+func (p *Provider) PostPullRequestComment(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	body string,
+	opts ...globalEntities.CommentOption,
+) error {
+	_, err := p.addRootComment(repo, prID, &commentRecord{
+		Body:   body,
+		Status: globalEntities.ResolveCommentOptions(opts...),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to post pull request comment: %w", err)
+	}
+	return nil
+}
+
+// PostPullRequestThreadComment opens a thread anchored to filePath and line and
+// returns its ID, which is both the comment ID and the thread ID.
+func (p *Provider) PostPullRequestThreadComment(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	filePath string,
+	line int,
+	body string,
+	opts ...globalEntities.CommentOption,
+) (int, error) {
+	id, err := p.addRootComment(repo, prID, &commentRecord{
+		Body:     body,
+		FilePath: strings.TrimPrefix(filePath, "/"),
+		Line:     line,
+		Status:   globalEntities.ResolveCommentOptions(opts...),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to post pull request thread comment: %w", err)
+	}
+	return int(id), nil
+}
+
+// addRootComment appends a comment that opens its own thread.
+func (p *Provider) addRootComment(
+	repo globalEntities.Repository,
+	prID int,
+	comment *commentRecord,
+) (int64, error) {
+	err := p.updateMetadata(repo, func(md *metadata) error {
+		pr, err := md.pullRequest(prID)
+		if err != nil {
+			return err
+		}
+
+		comment.Author = p.author()
+		comment.CreatedAt = p.now().UTC()
+		md.addComment(pr, comment)
+		comment.ThreadID = comment.ID
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return comment.ID, nil
+}
+
+// ReplyToThread appends a reply to the thread rooted at the comment threadID.
+// The reply inherits the root's file anchor.
+func (p *Provider) ReplyToThread(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	body string,
+) (int, error) {
+	var reply *commentRecord
+	err := p.updateMetadata(repo, func(md *metadata) error {
+		pr, err := md.pullRequest(prID)
+		if err != nil {
+			return err
+		}
+
+		root, err := findThread(pr, threadID)
+		if err != nil {
+			return err
+		}
+
+		reply = &commentRecord{
+			ThreadID:    root.ID,
+			InReplyToID: root.ID,
+			Body:        body,
+			Author:      p.author(),
+			FilePath:    root.FilePath,
+			Line:        root.Line,
+			CreatedAt:   p.now().UTC(),
+		}
+		md.addComment(pr, reply)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to reply to thread %d: %w", threadID, err)
+	}
+	return int(reply.ID), nil
+}
+
+// UpdatePullRequestThreadStatus stores status on the thread rooted at the
+// comment threadID. Any status string is accepted.
+func (p *Provider) UpdatePullRequestThreadStatus(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID, threadID int,
+	status string,
+) error {
+	err := p.updateMetadata(repo, func(md *metadata) error {
+		pr, err := md.pullRequest(prID)
+		if err != nil {
+			return err
+		}
+
+		root, err := findThread(pr, threadID)
+		if err != nil {
+			return err
+		}
+		root.Status = status
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update pull request thread status: %w", err)
+	}
+	return nil
+}
+
+// findThread returns the root comment of the thread threadID.
+func findThread(pr *pullRequestRecord, threadID int) (*commentRecord, error) {
+	for _, c := range pr.Comments {
+		if c.ID == int64(threadID) && c.ThreadID == c.ID {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("thread %d not found on pull request #%d", threadID, pr.ID)
+}
+
+// GetPullRequestStatus returns "open", "closed", or "merged".
+func (p *Provider) GetPullRequestStatus(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (string, error) {
+	pr, err := p.viewPullRequest(repo, prID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pull request status: %w", err)
+	}
+	return pr.Status, nil
+}
+
+// GetPullRequestCheckStatus always reports passing checks once the pull
+// request exists: the local forge runs no CI.
+func (p *Provider) GetPullRequestCheckStatus(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (bool, error) {
+	if _, err := p.viewPullRequest(repo, prID); err != nil {
+		return false, fmt.Errorf("failed to get pull request check status: %w", err)
+	}
+	return true, nil
+}
+
+// GetPullRequestChecks reports no checks, and so a success verdict, once the
+// pull request exists: the local forge runs no CI.
+func (p *Provider) GetPullRequestChecks(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*globalEntities.PullRequestChecks, error) {
+	if _, err := p.viewPullRequest(repo, prID); err != nil {
+		return nil, fmt.Errorf("failed to get pull request checks: %w", err)
+	}
+	return globalEntities.NewPullRequestChecks(nil), nil
+}
+
+// MergePullRequest merges the source branch into the target branch inside the
+// bare repository. Supported strategies are "squash" (also used for an empty
+// strategy), which writes one commit on top of the target; "merge", which
+// writes a two-parent merge commit; and "fast-forward-only", which moves the
+// target to the source head when the target is its ancestor. Squash and merge
+// perform a file-level three-way merge and fail with ErrMergeConflict when
+// both sides changed the same file differently. Policy bypass is meaningless
+// here and ignored; entities.WithDeleteSourceBranch removes the source branch.
+func (p *Provider) MergePullRequest(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	strategy string,
+	opts ...globalEntities.MergeOption,
+) error {
+	resolved := globalEntities.ResolveMergeOptions(opts...)
+
+	gitRepo, err := p.openRepository(repo)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	md, err := p.readMetadata(repo)
+	if err != nil {
+		return fmt.Errorf("failed to merge pull request: %w", err)
+	}
+	pr, err := md.pullRequest(prID)
+	if err != nil {
+		return fmt.Errorf("failed to merge pull request: %w", err)
+	}
+	if pr.Status != statusOpen {
+		return fmt.Errorf("failed to merge pull request #%d: pull request is %s", prID, pr.Status)
+	}
+
+	head, err := p.mergeBranches(gitRepo, pr, strategy)
+	if err != nil {
+		return fmt.Errorf("failed to merge pull request #%d: %w", prID, err)
+	}
+
+	targetRef := plumbing.NewHashReference(branchRefName(pr.TargetBranch), head)
+	if setErr := gitRepo.Storer.SetReference(targetRef); setErr != nil {
+		return fmt.Errorf("failed to update branch %q: %w", pr.TargetBranch, setErr)
+	}
+
+	pr.Status = statusMerged
+	pr.MergeCommit = head.String()
+	if writeErr := p.writeMetadata(repo, md); writeErr != nil {
+		return writeErr
+	}
+
+	if resolved.DeleteSourceBranch {
+		if removeErr := gitRepo.Storer.RemoveReference(branchRefName(pr.SourceBranch)); removeErr != nil {
+			log.Warnf("failed to delete source branch %q after merge: %v", pr.SourceBranch, removeErr)
+		}
+	}
+
+	return nil
+}
+
+// mergeBranches writes the result of merging the pull request's source branch
+// into its target branch and returns the new target head.
+func (p *Provider) mergeBranches(
+	gitRepo *git.Repository,
+	pr *pullRequestRecord,
+	strategy string,
+) (plumbing.Hash, error) {
+	source, err := branchCommit(gitRepo, pr.SourceBranch)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	target, err := branchCommit(gitRepo, pr.TargetBranch)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	var parents []plumbing.Hash
+	var message string
+	switch strategy {
+	case "fast-forward-only":
+		isAncestor, ancestorErr := target.IsAncestor(source)
+		if ancestorErr != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to compare branches: %w", ancestorErr)
+		}
+		if !isAncestor {
+			return plumbing.ZeroHash, errors.New("target branch has diverged; fast-forward is not possible")
+		}
+		return source.Hash, nil
+	case "", "squash":
+		parents = []plumbing.Hash{target.Hash}
+		message = strings.TrimSpace(pr.Title + "\n\n" + pr.Description)
+	case "merge":
+		parents = []plumbing.Hash{target.Hash, source.Hash}
+		message = fmt.Sprintf("Merge pull request #%d from %s\n\n%s", pr.ID, pr.SourceBranch, pr.Title)
+	default:
+		return plumbing.ZeroHash, fmt.Errorf("unsupported merge strategy %q", strategy)
+	}
+
+	tree, err := mergeCommitTrees(gitRepo, target, source)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return p.writeCommit(gitRepo.Storer, tree, parents, message)
+}
+
+// mergeCommitTrees three-way merges the trees of two commits against their
+// merge base and stores the resulting tree.
+func mergeCommitTrees(gitRepo *git.Repository, ours, theirs *object.Commit) (plumbing.Hash, error) {
+	base, err := mergeBase(ours, theirs)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	trees := make([]map[string]treeFile, 0, 3)
+	for _, commit := range []*object.Commit{base, ours, theirs} {
+		files, flattenErr := flattenTree(commit)
+		if flattenErr != nil {
+			return plumbing.ZeroHash, flattenErr
+		}
+		trees = append(trees, files)
+	}
+
+	merged, conflicts := mergeTrees(trees[0], trees[1], trees[2])
+	if len(conflicts) > 0 {
+		return plumbing.ZeroHash, fmt.Errorf("%w in %s", ErrMergeConflict, strings.Join(conflicts, ", "))
+	}
+	return buildTree(gitRepo.Storer, merged)
+}
+
+// SubmitPullRequestReview records the review in the pull request's metadata.
+// A comment-only review with an empty body is skipped.
+func (p *Provider) SubmitPullRequestReview(
+	_ context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	sub globalEntities.ReviewSubmission,
+) error {
+	switch sub.Verdict {
+	case globalEntities.ReviewVerdictApprove,
+		globalEntities.ReviewVerdictRequestChanges,
+		globalEntities.ReviewVerdictWaitingForAuthor:
+	case globalEntities.ReviewVerdictComment:
+		if sub.Body == "" {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unsupported review verdict %q", sub.Verdict)
+	}
+
+	err := p.updateMetadata(repo, func(md *metadata) error {
+		pr, err := md.pullRequest(prID)
+		if err != nil {
+			return err
+		}
+
+		pr.Reviews = append(pr.Reviews, &reviewRecord{
+			Verdict:     sub.Verdict,
+			Body:        sub.Body,
+			Author:      p.author(),
+			SubmittedAt: p.now().UTC(),
+		})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to submit pull request review: %w", err)
+	}
+	return nil
+}
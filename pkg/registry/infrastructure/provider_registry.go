@@ -113,7 +113,19 @@ func ServiceTypeToProviderName(serviceType globalEntities.ServiceType) string {
 		return "azuredevops"
 	case globalEntities.CODEBERG:
 		return "codeberg"
-	case globalEntities.UNKNOWN, globalEntities.BITBUCKET, globalEntities.CODECOMMIT:
+	case globalEntities.BITBUCKET:
+		return "bitbucket"
+	case globalEntities.CODECOMMIT:
+		return "codecommit"
+	case globalEntities.GITEA:
+		return "gitea"
+	case globalEntities.BITBUCKETDC:
+		return "bitbucketdc"
+	case globalEntities.GERRIT:
+		return "gerrit"
+	case globalEntities.LOCAL:
+		return "local"
+	case globalEntities.UNKNOWN:
 		return ""
 	default:
 		return ""
@@ -66,6 +66,84 @@ func TestServiceTypeToProviderName(t *testing.T) {
 		assert.Equal(t, "codeberg", name)
 	})
 
+	t.Run("should return bitbucket for BITBUCKET service type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		serviceType := globalEntities.BITBUCKET
+
+		// when
+		name := registryInfra.ServiceTypeToProviderName(serviceType)
+
+		// then
+		assert.Equal(t, "bitbucket", name)
+	})
+
+	t.Run("should return codecommit for CODECOMMIT service type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		serviceType := globalEntities.CODECOMMIT
+
+		// when
+		name := registryInfra.ServiceTypeToProviderName(serviceType)
+
+		// then
+		assert.Equal(t, "codecommit", name)
+	})
+
+	t.Run("should return gitea for GITEA service type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		serviceType := globalEntities.GITEA
+
+		// when
+		name := registryInfra.ServiceTypeToProviderName(serviceType)
+
+		// then
+		assert.Equal(t, "gitea", name)
+	})
+
+	t.Run("should return bitbucketdc for BITBUCKETDC service type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		serviceType := globalEntities.BITBUCKETDC
+
+		// when
+		name := registryInfra.ServiceTypeToProviderName(serviceType)
+
+		// then
+		assert.Equal(t, "bitbucketdc", name)
+	})
+
+	t.Run("should return gerrit for GERRIT service type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		serviceType := globalEntities.GERRIT
+
+		// when
+		name := registryInfra.ServiceTypeToProviderName(serviceType)
+
+		// then
+		assert.Equal(t, "gerrit", name)
+	})
+
+	t.Run("should return local for LOCAL service type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		serviceType := globalEntities.LOCAL
+
+		// when
+		name := registryInfra.ServiceTypeToProviderName(serviceType)
+
+		// then
+		assert.Equal(t, "local", name)
+	})
+
 	t.Run("should return empty string for UNKNOWN service type", func(t *testing.T) {
 		t.Parallel()
 
@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/gitea"
+	"github.com/rios0rios0/gitforge/pkg/providers/infrastructure/github"
 	infrastructure "github.com/rios0rios0/gitforge/pkg/registry/infrastructure"
 	"github.com/rios0rios0/gitforge/test/builders"
 	"github.com/rios0rios0/gitforge/test/doubles"
@@ -129,6 +131,40 @@ func TestProviderRegistryGetAdapterByURL(t *testing.T) {
 		// then
 		assert.Nil(t, result)
 	})
+
+	t.Run("should return the enterprise adapter when URL is on a GitHub Enterprise host", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		reg := infrastructure.NewProviderRegistry()
+		enterprise, err := github.NewEnterpriseProvider("token", "https://github.corp.example", "")
+		require.NoError(t, err)
+		reg.RegisterAdapter(github.NewProvider("token"))
+		reg.RegisterAdapter(enterprise)
+
+		// when
+		result := reg.GetAdapterByURL("git@github.corp.example:org/repo.git")
+
+		// then
+		assert.Same(t, enterprise, result)
+	})
+
+	t.Run("should return the gitea adapter when URL is on its instance host", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		reg := infrastructure.NewProviderRegistry()
+		instance, err := gitea.NewProvider("token", "https://git.corp.example")
+		require.NoError(t, err)
+		reg.RegisterAdapter(github.NewProvider("token"))
+		reg.RegisterAdapter(instance)
+
+		// when
+		result := reg.GetAdapterByURL("https://git.corp.example/org/repo.git")
+
+		// then
+		assert.Same(t, instance, result)
+	})
 }
 
 func TestProviderRegistryGetAdapterByServiceType(t *testing.T) {
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// Outcome is how a wait on a pull request ended.
+type Outcome string
+
+const (
+	// OutcomeChecksPassed ends WaitForChecks once every check completed and
+	// the verdict is a success.
+	OutcomeChecksPassed Outcome = "checks_passed"
+
+	// OutcomeMergeable ends WaitForMergeable once the verdict is a success;
+	// checks that are not required may still be running.
+	OutcomeMergeable Outcome = "mergeable"
+
+	// OutcomeChecksFailed ends either wait as soon as the verdict is a
+	// failure, without waiting for the remaining checks.
+	OutcomeChecksFailed Outcome = "checks_failed"
+
+	// OutcomeChecksTimedOut ends either wait when the context deadline
+	// passes first.
+	OutcomeChecksTimedOut Outcome = "checks_timed_out"
+
+	// OutcomePRClosed ends either wait when the pull request was closed
+	// (abandoned on Azure DevOps) without being merged.
+	OutcomePRClosed Outcome = "pr_closed"
+
+	// OutcomePRMerged ends either wait when the pull request was merged
+	// (completed on Azure DevOps) in the meantime.
+	OutcomePRMerged Outcome = "pr_merged"
+)
+
+// Result is the state a wait ended in.
+type Result struct {
+	Outcome Outcome
+
+	// Status is the pull request status last read, as GetPullRequestStatus
+	// reports it.
+	Status string
+
+	// Checks are the checks last read; nil when the wait ended before they
+	// were read once.
+	Checks *globalEntities.PullRequestChecks
+
+	Polls int // number of polls, including the one that ended the wait
+}
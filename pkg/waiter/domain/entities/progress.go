@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// Progress reports a poll that did not end the wait.
+type Progress struct {
+	Poll   int    // 1 for the first poll
+	Status string // pull request status; empty when the poll was rate limited before reading it
+
+	// Checks are the checks the poll read; nil when it was rate limited
+	// before reading them.
+	Checks *globalEntities.PullRequestChecks
+
+	// RateLimited is set when the forge throttled the poll, in which case
+	// NextPoll honors the delay it asked for.
+	RateLimited bool
+
+	NextPoll time.Duration // delay until the next poll
+}
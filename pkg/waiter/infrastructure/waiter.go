@@ -0,0 +1,226 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	waiterEntities "github.com/rios0rios0/gitforge/pkg/waiter/domain/entities"
+)
+
+// Defaults of the Options a Waiter polls with.
+const (
+	DefaultMinInterval = 10 * time.Second
+	DefaultMaxInterval = 2 * time.Minute
+)
+
+const (
+	backoffFactor = 2
+
+	// jitterFraction spreads each delay over ±20%, so waiters started
+	// together do not poll the forge in lockstep.
+	jitterFraction = 0.2
+)
+
+// Options tunes how a Waiter polls. Zero values take the defaults.
+type Options struct {
+	// MinInterval is the delay after the first poll and the floor of every
+	// delay, jitter included. Defaults to DefaultMinInterval.
+	MinInterval time.Duration
+
+	// MaxInterval caps the delay the backoff doubles up to; the delay a
+	// rate limited forge asks for may exceed it. Defaults to
+	// DefaultMaxInterval.
+	MaxInterval time.Duration
+
+	// OnProgress, when set, is called after every poll that does not end
+	// the wait, before sleeping until the next one.
+	OnProgress func(progress waiterEntities.Progress)
+}
+
+// Waiter polls a pull request through a ChecksProvider until its checks
+// settle, backing off exponentially with jitter between polls. A throttled
+// poll (an error wrapping globalEntities.RateLimitError) is retried after the
+// delay the forge asked for; any other provider error ends the wait.
+type Waiter struct {
+	provider globalEntities.ChecksProvider
+	options  Options
+}
+
+// NewWaiter creates a Waiter polling provider with options.
+func NewWaiter(provider globalEntities.ChecksProvider, options Options) *Waiter {
+	if options.MinInterval <= 0 {
+		options.MinInterval = DefaultMinInterval
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = DefaultMaxInterval
+	}
+	options.MaxInterval = max(options.MaxInterval, options.MinInterval)
+	return &Waiter{provider: provider, options: options}
+}
+
+// WaitForChecks blocks until every check of the pull request completed,
+// ending with OutcomeChecksPassed, or until the verdict fails, ending with
+// OutcomeChecksFailed right away. It ends with OutcomeChecksTimedOut when the
+// context deadline passes and with OutcomePRClosed or OutcomePRMerged when
+// the pull request stops being open; a canceled context returns its error.
+func (w *Waiter) WaitForChecks(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*waiterEntities.Result, error) {
+	return w.wait(ctx, repo, prID, checksSettled)
+}
+
+// WaitForMergeable blocks until the verdict of the pull request succeeds,
+// ending with OutcomeMergeable even while checks that are not required still
+// run. It ends like WaitForChecks otherwise.
+func (w *Waiter) WaitForMergeable(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+) (*waiterEntities.Result, error) {
+	return w.wait(ctx, repo, prID, mergeableSettled)
+}
+
+// settleFunc returns the outcome the checks of an open pull request end the
+// wait with, or an empty outcome to keep waiting.
+type settleFunc func(checks *globalEntities.PullRequestChecks) waiterEntities.Outcome
+
+func checksSettled(checks *globalEntities.PullRequestChecks) waiterEntities.Outcome {
+	isPending := func(check globalEntities.Check) bool {
+		return check.State == globalEntities.CheckStatePending
+	}
+	switch {
+	case checks.Verdict == globalEntities.CheckStateFailure:
+		return waiterEntities.OutcomeChecksFailed
+	case checks.Verdict == globalEntities.CheckStateSuccess && !slices.ContainsFunc(checks.Checks, isPending):
+		return waiterEntities.OutcomeChecksPassed
+	default:
+		return ""
+	}
+}
+
+func mergeableSettled(checks *globalEntities.PullRequestChecks) waiterEntities.Outcome {
+	switch checks.Verdict {
+	case globalEntities.CheckStateFailure:
+		return waiterEntities.OutcomeChecksFailed
+	case globalEntities.CheckStateSuccess:
+		return waiterEntities.OutcomeMergeable
+	default:
+		return ""
+	}
+}
+
+func (w *Waiter) wait(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	settle settleFunc,
+) (*waiterEntities.Result, error) {
+	result := &waiterEntities.Result{}
+	interval := w.options.MinInterval
+	for poll := 1; ; poll++ {
+		progress, outcome, err := w.poll(ctx, repo, prID, settle)
+		progress.Poll = poll
+		result.Polls = poll
+		if progress.Status != "" {
+			result.Status = progress.Status
+		}
+		if progress.Checks != nil {
+			result.Checks = progress.Checks
+		}
+		if outcome != "" {
+			result.Outcome = outcome
+			return result, nil
+		}
+
+		delay := w.jitter(interval)
+		var rateLimitErr *globalEntities.RateLimitError
+		switch {
+		case ctx.Err() != nil:
+			return contextEnded(ctx, result)
+		case errors.As(err, &rateLimitErr):
+			progress.RateLimited = true
+			delay = max(delay, rateLimitErr.RetryAfter)
+		case err != nil:
+			return nil, err
+		}
+		interval = min(interval*backoffFactor, w.options.MaxInterval)
+
+		progress.NextPoll = delay
+		if w.options.OnProgress != nil {
+			w.options.OnProgress(progress)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return contextEnded(ctx, result)
+		case <-timer.C:
+		}
+	}
+}
+
+// poll reads the pull request status and, while the pull request is open,
+// its checks. It returns the outcome they end the wait with, if any.
+func (w *Waiter) poll(
+	ctx context.Context,
+	repo globalEntities.Repository,
+	prID int,
+	settle settleFunc,
+) (waiterEntities.Progress, waiterEntities.Outcome, error) {
+	var progress waiterEntities.Progress
+
+	status, err := w.provider.GetPullRequestStatus(ctx, repo, prID)
+	if err != nil {
+		return progress, "", fmt.Errorf("failed to get pull request status: %w", err)
+	}
+	progress.Status = status
+	if outcome := closedOutcome(status); outcome != "" {
+		return progress, outcome, nil
+	}
+
+	checks, err := w.provider.GetPullRequestChecks(ctx, repo, prID)
+	if err != nil {
+		return progress, "", fmt.Errorf("failed to get pull request checks: %w", err)
+	}
+	progress.Checks = checks
+	return progress, settle(checks), nil
+}
+
+// closedOutcome maps the statuses providers report for a pull request that
+// is no longer open, and returns an empty outcome for any other status.
+func closedOutcome(status string) waiterEntities.Outcome {
+	switch strings.ToLower(status) {
+	case "merged", "completed":
+		return waiterEntities.OutcomePRMerged
+	case "closed", "abandoned":
+		return waiterEntities.OutcomePRClosed
+	default: // open, opened, active, locked
+		return ""
+	}
+}
+
+// jitter spreads interval by up to jitterFraction either way, without going
+// below MinInterval.
+func (w *Waiter) jitter(interval time.Duration) time.Duration {
+	spread := (rand.Float64()*2 - 1) * jitterFraction //nolint:gosec // jitter needs no cryptographic randomness
+	return max(time.Duration(float64(interval)*(1+spread)), w.options.MinInterval)
+}
+
+// contextEnded ends the wait with OutcomeChecksTimedOut when the context
+// deadline passed, and with the context error when it was canceled.
+func contextEnded(ctx context.Context, result *waiterEntities.Result) (*waiterEntities.Result, error) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		result.Outcome = waiterEntities.OutcomeChecksTimedOut
+		return result, nil
+	}
+	return nil, ctx.Err()
+}
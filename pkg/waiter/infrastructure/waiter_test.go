@@ -0,0 +1,329 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	waiterEntities "github.com/rios0rios0/gitforge/pkg/waiter/domain/entities"
+	waiterInfra "github.com/rios0rios0/gitforge/pkg/waiter/infrastructure"
+	"github.com/rios0rios0/gitforge/test/doubles"
+)
+
+func checksOf(states ...globalEntities.CheckState) *globalEntities.PullRequestChecks {
+	checks := make([]globalEntities.Check, 0, len(states))
+	for _, state := range states {
+		checks = append(checks, globalEntities.Check{Name: "build", State: state})
+	}
+	return globalEntities.NewPullRequestChecks(checks)
+}
+
+func newStub(statuses []string, checks ...*globalEntities.PullRequestChecks) *doubles.ChecksProviderStub {
+	return &doubles.ChecksProviderStub{
+		ForgeProviderStub: &doubles.ForgeProviderStub{},
+		Statuses:          statuses,
+		Checks:            checks,
+	}
+}
+
+func TestWaitForChecks(t *testing.T) {
+	t.Parallel()
+
+	repo := globalEntities.Repository{Organization: "org", Name: "repo"}
+
+	t.Run("should pass once the pending checks complete", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub(
+				[]string{"open"},
+				checksOf(globalEntities.CheckStatePending),
+				checksOf(globalEntities.CheckStateSuccess, globalEntities.CheckStateSkipped),
+			)
+			var progress []waiterEntities.Progress
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{
+				OnProgress: func(p waiterEntities.Progress) { progress = append(progress, p) },
+			})
+			start := time.Now()
+
+			// when
+			result, err := waiter.WaitForChecks(t.Context(), repo, 1)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, waiterEntities.OutcomeChecksPassed, result.Outcome)
+			assert.Equal(t, "open", result.Status)
+			assert.Equal(t, globalEntities.CheckStateSuccess, result.Checks.Verdict)
+			assert.Equal(t, 2, result.Polls)
+			require.Len(t, progress, 1)
+			assert.Equal(t, 1, progress[0].Poll)
+			assert.Equal(t, globalEntities.CheckStatePending, progress[0].Checks.Verdict)
+			assert.False(t, progress[0].RateLimited)
+			assert.Equal(t, progress[0].NextPoll, time.Since(start))
+		})
+	})
+
+	t.Run("should keep waiting while a check that does not count is pending", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			required := globalEntities.Check{Name: "build", State: globalEntities.CheckStateSuccess, Required: true}
+			lintRunning := globalEntities.Check{Name: "lint", State: globalEntities.CheckStatePending}
+			lintDone := globalEntities.Check{Name: "lint", State: globalEntities.CheckStateSuccess}
+			stub := newStub(
+				[]string{"open"},
+				globalEntities.NewPullRequestChecks([]globalEntities.Check{required, lintRunning}),
+				globalEntities.NewPullRequestChecks([]globalEntities.Check{required, lintDone}),
+			)
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{})
+
+			// when
+			result, err := waiter.WaitForChecks(t.Context(), repo, 1)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, waiterEntities.OutcomeChecksPassed, result.Outcome)
+			assert.Equal(t, 2, result.Polls)
+		})
+	})
+
+	t.Run("should fail as soon as the verdict fails", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub(
+				[]string{"open"},
+				checksOf(globalEntities.CheckStatePending),
+				checksOf(globalEntities.CheckStateFailure, globalEntities.CheckStatePending),
+			)
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{})
+
+			// when
+			result, err := waiter.WaitForChecks(t.Context(), repo, 1)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, waiterEntities.OutcomeChecksFailed, result.Outcome)
+			assert.Equal(t, globalEntities.CheckStateFailure, result.Checks.Verdict)
+			assert.Equal(t, 2, result.Polls)
+		})
+	})
+
+	t.Run("should time out when the context deadline passes", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub([]string{"open"}, checksOf(globalEntities.CheckStatePending))
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{})
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Minute)
+			defer cancel()
+			start := time.Now()
+
+			// when
+			result, err := waiter.WaitForChecks(ctx, repo, 1)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, waiterEntities.OutcomeChecksTimedOut, result.Outcome)
+			assert.Equal(t, "open", result.Status)
+			assert.Equal(t, globalEntities.CheckStatePending, result.Checks.Verdict)
+			assert.Equal(t, stub.ChecksCalls, result.Polls)
+			assert.Equal(t, 5*time.Minute, time.Since(start))
+		})
+	})
+
+	t.Run("should return the context error when canceled", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub([]string{"open"}, checksOf(globalEntities.CheckStatePending))
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{
+				OnProgress: func(waiterEntities.Progress) { cancel() },
+			})
+
+			// when
+			result, err := waiter.WaitForChecks(ctx, repo, 1)
+
+			// then
+			require.ErrorIs(t, err, context.Canceled)
+			assert.Nil(t, result)
+		})
+	})
+
+	t.Run("should end when the pull request stops being open", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			status   string
+			expected waiterEntities.Outcome
+		}{
+			{status: "closed", expected: waiterEntities.OutcomePRClosed},
+			{status: "abandoned", expected: waiterEntities.OutcomePRClosed},
+			{status: "merged", expected: waiterEntities.OutcomePRMerged},
+			{status: "completed", expected: waiterEntities.OutcomePRMerged},
+		}
+		for _, tt := range tests {
+			t.Run(tt.status, func(t *testing.T) {
+				t.Parallel()
+
+				synctest.Test(t, func(t *testing.T) {
+					// given
+					stub := newStub([]string{"open", tt.status}, checksOf(globalEntities.CheckStatePending))
+					waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{})
+
+					// when
+					result, err := waiter.WaitForChecks(t.Context(), repo, 1)
+
+					// then
+					require.NoError(t, err)
+					assert.Equal(t, tt.expected, result.Outcome)
+					assert.Equal(t, tt.status, result.Status)
+					assert.Equal(t, 2, result.Polls)
+					assert.Equal(t, 1, stub.ChecksCalls, "checks are not read once the pull request is no longer open")
+				})
+			})
+		}
+	})
+
+	t.Run("should wait as long as a rate limited forge asks", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub([]string{"open"}, checksOf(globalEntities.CheckStateSuccess))
+			stub.StatusErrs = []error{&globalEntities.RateLimitError{
+				RetryAfter: 5 * time.Minute, Err: errors.New("API error (status 429)"),
+			}}
+			var progress []waiterEntities.Progress
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{
+				OnProgress: func(p waiterEntities.Progress) { progress = append(progress, p) },
+			})
+			start := time.Now()
+
+			// when
+			result, err := waiter.WaitForChecks(t.Context(), repo, 1)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, waiterEntities.OutcomeChecksPassed, result.Outcome)
+			assert.Equal(t, 2, result.Polls)
+			require.Len(t, progress, 1)
+			assert.True(t, progress[0].RateLimited)
+			assert.Empty(t, progress[0].Status)
+			assert.Nil(t, progress[0].Checks)
+			assert.Equal(t, 5*time.Minute, progress[0].NextPoll)
+			assert.Equal(t, 5*time.Minute, time.Since(start))
+		})
+	})
+
+	t.Run("should return other provider errors", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			cause := errors.New("API error (status 500)")
+			stub := newStub([]string{"open"})
+			stub.ChecksErrs = []error{cause}
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{})
+
+			// when
+			result, err := waiter.WaitForChecks(t.Context(), repo, 1)
+
+			// then
+			require.ErrorIs(t, err, cause)
+			assert.ErrorContains(t, err, "failed to get pull request checks")
+			assert.Nil(t, result)
+		})
+	})
+
+	t.Run("should back off with jitter between the minimum and maximum intervals", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub([]string{"open"}, checksOf(globalEntities.CheckStatePending))
+			var delays []time.Duration
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{
+				MinInterval: 10 * time.Second,
+				MaxInterval: 40 * time.Second,
+				OnProgress:  func(p waiterEntities.Progress) { delays = append(delays, p.NextPoll) },
+			})
+			ctx, cancel := context.WithTimeout(t.Context(), 10*time.Minute)
+			defer cancel()
+
+			// when
+			_, err := waiter.WaitForChecks(ctx, repo, 1)
+
+			// then
+			require.NoError(t, err)
+			require.Greater(t, len(delays), 4)
+			for i, delay := range delays {
+				interval := min(10*time.Second<<i, 40*time.Second)
+				assert.GreaterOrEqual(t, delay, max(interval*8/10, 10*time.Second), "poll %d", i+1)
+				assert.LessOrEqual(t, delay, interval*12/10, "poll %d", i+1)
+			}
+		})
+	})
+}
+
+func TestWaitForMergeable(t *testing.T) {
+	t.Parallel()
+
+	repo := globalEntities.Repository{Organization: "org", Name: "repo"}
+
+	t.Run("should end once the verdict succeeds while optional checks still run", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub([]string{"active"}, globalEntities.NewPullRequestChecks([]globalEntities.Check{
+				{Name: "build", State: globalEntities.CheckStateSuccess, Required: true},
+				{Name: "lint", State: globalEntities.CheckStatePending},
+			}))
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{})
+
+			// when
+			result, err := waiter.WaitForMergeable(t.Context(), repo, 1)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, waiterEntities.OutcomeMergeable, result.Outcome)
+			assert.Equal(t, "active", result.Status)
+			assert.Equal(t, 1, result.Polls)
+		})
+	})
+
+	t.Run("should fail when a required check fails", func(t *testing.T) {
+		t.Parallel()
+
+		synctest.Test(t, func(t *testing.T) {
+			// given
+			stub := newStub([]string{"opened"},
+				checksOf(globalEntities.CheckStatePending),
+				checksOf(globalEntities.CheckStateFailure),
+			)
+			waiter := waiterInfra.NewWaiter(stub, waiterInfra.Options{})
+
+			// when
+			result, err := waiter.WaitForMergeable(t.Context(), repo, 1)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, waiterEntities.OutcomeChecksFailed, result.Outcome)
+			assert.Equal(t, 2, result.Polls)
+		})
+	})
+}
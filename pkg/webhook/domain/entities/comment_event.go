@@ -0,0 +1,19 @@
+package entities
+
+import (
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// CommentCreated is sent when someone comments on a pull request. Comments on
+// issues are not reported.
+//
+// PullRequest holds what the payload carries: GitHub's PR-wide comments
+// (issue comments) come without branches or draft state. Comment.ThreadID is
+// zero where the payload does not identify the thread the way the
+// ReviewProvider does (GitLab discussions).
+type CommentCreated struct {
+	EventMetadata
+
+	PullRequest globalEntities.PullRequestDetail
+	Comment     globalEntities.PullRequestComment
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// Event is a webhook delivery normalized across forges. Handlers switch on
+// its concrete type: PullRequestOpened, PullRequestUpdated, PullRequestMerged,
+// PullRequestClosed, CommentCreated, Push or TagCreated.
+type Event interface {
+	Metadata() EventMetadata
+}
+
+// EventMetadata describes the delivery an event came from. Every event embeds
+// it.
+type EventMetadata struct {
+	ServiceType globalEntities.ServiceType
+
+	// Name is the forge's own event name, e.g. "pull_request" on GitHub or
+	// "git.pullrequest.updated" on Azure DevOps.
+	Name string
+
+	// DeliveryID identifies the delivery, for deduplication and
+	// redelivery; empty when the forge does not send one.
+	DeliveryID string
+
+	// Repository is filled from the payload, which carries less than
+	// DiscoverRepositories reports: fields the payload lacks stay empty.
+	Repository globalEntities.Repository
+
+	// Sender is the login or display name of whoever triggered the event.
+	Sender string
+}
+
+// Metadata returns m, so every event embedding EventMetadata is an Event.
+func (m EventMetadata) Metadata() EventMetadata {
+	return m
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// PullRequestOpened is sent when a pull request is opened or reopened.
+type PullRequestOpened struct {
+	EventMetadata
+
+	PullRequest globalEntities.PullRequestDetail
+}
+
+// PullRequestUpdated is sent when a pull request gets new commits or its
+// title, description, target branch or draft state changes.
+type PullRequestUpdated struct {
+	EventMetadata
+
+	PullRequest globalEntities.PullRequestDetail
+
+	// HeadSHA is the latest commit of the source branch; empty when the
+	// forge does not send it.
+	HeadSHA string
+}
+
+// PullRequestMerged is sent when a pull request is merged (completed on Azure
+// DevOps).
+type PullRequestMerged struct {
+	EventMetadata
+
+	PullRequest    globalEntities.PullRequestDetail
+	MergeCommitSHA string
+}
+
+// PullRequestClosed is sent when a pull request is closed without being
+// merged (abandoned on Azure DevOps).
+type PullRequestClosed struct {
+	EventMetadata
+
+	PullRequest globalEntities.PullRequestDetail
+}
@@ -0,0 +1,41 @@
+package entities
+
+// Push is sent when commits are pushed to a branch, including when the
+// branch is created or deleted.
+type Push struct {
+	EventMetadata
+
+	Branch string // without the "refs/heads/" prefix
+
+	// Before and After are the branch's commit before and after the push;
+	// Before is all zeros for a new branch and After for a deleted one.
+	Before string
+	After  string
+}
+
+// Deleted reports whether the push deleted the branch.
+func (p Push) Deleted() bool {
+	return isZeroCommit(p.After)
+}
+
+// TagCreated is sent when a tag is pushed. Tag deletions are not reported.
+type TagCreated struct {
+	EventMetadata
+
+	Tag string // without the "refs/tags/" prefix
+
+	// CommitSHA is the tagged commit. Azure DevOps payloads only carry the
+	// object the ref points at, which for annotated tags is the tag object.
+	CommitSHA string
+}
+
+// isZeroCommit reports whether sha is the all-zeros object ID forges send
+// for a missing side of a ref update.
+func isZeroCommit(sha string) bool {
+	for _, c := range sha {
+		if c != '0' {
+			return false
+		}
+	}
+	return sha != ""
+}
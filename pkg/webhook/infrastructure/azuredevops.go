@@ -0,0 +1,242 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+)
+
+const (
+	adoStatusCompleted = "completed"
+	adoStatusAbandoned = "abandoned"
+)
+
+type adoIdentity struct {
+	DisplayName string `json:"displayName"`
+}
+
+type adoRepository struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	RemoteURL     string `json:"remoteUrl"`
+	SSHURL        string `json:"sshUrl"`
+	DefaultBranch string `json:"defaultBranch"`
+	Project       struct {
+		Name string `json:"name"`
+	} `json:"project"`
+}
+
+type adoPullRequest struct {
+	PullRequestID   int           `json:"pullRequestId"`
+	Title           string        `json:"title"`
+	Status          string        `json:"status"`
+	IsDraft         bool          `json:"isDraft"`
+	SourceRefName   string        `json:"sourceRefName"`
+	TargetRefName   string        `json:"targetRefName"`
+	CreatedBy       adoIdentity   `json:"createdBy"`
+	ClosedBy        adoIdentity   `json:"closedBy"`
+	Repository      adoRepository `json:"repository"`
+	LastMergeCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeCommit"`
+	LastMergeSourceCommit struct {
+		CommitID string `json:"commitId"`
+	} `json:"lastMergeSourceCommit"`
+}
+
+type adoPush struct {
+	RefUpdates []struct {
+		Name        string `json:"name"`
+		OldObjectID string `json:"oldObjectId"`
+		NewObjectID string `json:"newObjectId"`
+	} `json:"refUpdates"`
+	Repository adoRepository `json:"repository"`
+	PushedBy   adoIdentity   `json:"pushedBy"`
+}
+
+type adoCommentResource struct {
+	Comment struct {
+		ID              int64       `json:"id"`
+		ParentCommentID int64       `json:"parentCommentId"`
+		Content         string      `json:"content"`
+		Author          adoIdentity `json:"author"`
+		Links           struct {
+			Threads struct {
+				Href string `json:"href"`
+			} `json:"threads"`
+		} `json:"_links"`
+	} `json:"comment"`
+	PullRequest adoPullRequest `json:"pullRequest"`
+}
+
+type adoPayload struct {
+	ID                 string          `json:"id"`
+	EventType          string          `json:"eventType"`
+	Resource           json.RawMessage `json:"resource"`
+	ResourceContainers struct {
+		Account struct {
+			BaseURL string `json:"baseUrl"`
+		} `json:"account"`
+	} `json:"resourceContainers"`
+}
+
+// parseAzureDevOps normalizes an Azure DevOps service hook delivery. Service
+// hooks send one ref update per push subscription, so only the first is
+// reported.
+func parseAzureDevOps(body []byte) (webhookEntities.Event, error) {
+	var payload adoPayload
+	if unmarshalErr := json.Unmarshal(body, &payload); unmarshalErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+	}
+	if payload.EventType == "" {
+		return nil, fmt.Errorf("%w: no event type", ErrMalformedPayload)
+	}
+
+	metadata := webhookEntities.EventMetadata{
+		ServiceType: globalEntities.AZUREDEVOPS,
+		Name:        payload.EventType,
+		DeliveryID:  payload.ID,
+	}
+	organization := adoOrganization(payload.ResourceContainers.Account.BaseURL)
+
+	switch payload.EventType {
+	case "git.push":
+		var push adoPush
+		if unmarshalErr := json.Unmarshal(payload.Resource, &push); unmarshalErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+		}
+		if len(push.RefUpdates) == 0 {
+			return nil, fmt.Errorf("%w: push without ref updates", ErrMalformedPayload)
+		}
+		metadata.Repository = push.Repository.toDomain(organization)
+		metadata.Sender = push.PushedBy.DisplayName
+		update := push.RefUpdates[0]
+		return refUpdateEvent(metadata, update.Name, update.OldObjectID, update.NewObjectID, "")
+	case "git.pullrequest.created", "git.pullrequest.updated":
+		var pr adoPullRequest
+		if unmarshalErr := json.Unmarshal(payload.Resource, &pr); unmarshalErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+		}
+		metadata.Repository = pr.Repository.toDomain(organization)
+		return adoPullRequestEvent(metadata, pr)
+	case "ms.vss-code.git-pullrequest-comment-event":
+		var resource adoCommentResource
+		if unmarshalErr := json.Unmarshal(payload.Resource, &resource); unmarshalErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+		}
+		metadata.Repository = resource.PullRequest.Repository.toDomain(organization)
+		metadata.Sender = resource.Comment.Author.DisplayName
+
+		// the payload names the thread only in the link to it
+		threadID, _ := strconv.ParseInt(path.Base(resource.Comment.Links.Threads.Href), 10, 64)
+		return webhookEntities.CommentCreated{
+			EventMetadata: metadata,
+			PullRequest:   resource.PullRequest.toDomain(),
+			Comment: globalEntities.PullRequestComment{
+				ID:          resource.Comment.ID,
+				ThreadID:    threadID,
+				Body:        resource.Comment.Content,
+				Author:      resource.Comment.Author.DisplayName,
+				InReplyToID: resource.Comment.ParentCommentID,
+			},
+		}, nil
+	default:
+		return nil, unsupportedEvent(payload.EventType, "")
+	}
+}
+
+// adoPullRequestEvent tells the pull request events apart: Azure DevOps sends
+// completions and abandonments as updates.
+func adoPullRequestEvent(
+	metadata webhookEntities.EventMetadata,
+	pr adoPullRequest,
+) (webhookEntities.Event, error) {
+	detail := pr.toDomain()
+
+	switch {
+	case metadata.Name == "git.pullrequest.created":
+		metadata.Sender = pr.CreatedBy.DisplayName
+		return webhookEntities.PullRequestOpened{EventMetadata: metadata, PullRequest: detail}, nil
+	case pr.Status == adoStatusCompleted:
+		metadata.Sender = pr.ClosedBy.DisplayName
+		return webhookEntities.PullRequestMerged{
+			EventMetadata:  metadata,
+			PullRequest:    detail,
+			MergeCommitSHA: pr.LastMergeCommit.CommitID,
+		}, nil
+	case pr.Status == adoStatusAbandoned:
+		metadata.Sender = pr.ClosedBy.DisplayName
+		return webhookEntities.PullRequestClosed{EventMetadata: metadata, PullRequest: detail}, nil
+	default:
+		return webhookEntities.PullRequestUpdated{
+			EventMetadata: metadata,
+			PullRequest:   detail,
+			HeadSHA:       pr.LastMergeSourceCommit.CommitID,
+		}, nil
+	}
+}
+
+func (r adoRepository) toDomain(organization string) globalEntities.Repository {
+	return globalEntities.Repository{
+		ID:            r.ID,
+		Name:          r.Name,
+		Organization:  organization,
+		Project:       r.Project.Name,
+		DefaultBranch: r.DefaultBranch,
+		RemoteURL:     r.RemoteURL,
+		SSHURL:        r.SSHURL,
+		ProviderName:  "azuredevops",
+	}
+}
+
+func (pr adoPullRequest) toDomain() globalEntities.PullRequestDetail {
+	prURL := ""
+	if remoteURL := stripUserinfo(pr.Repository.RemoteURL); remoteURL != "" {
+		prURL = fmt.Sprintf("%s/pullrequest/%d", remoteURL, pr.PullRequestID)
+	}
+	return globalEntities.PullRequestDetail{
+		PullRequest: globalEntities.PullRequest{
+			ID:     pr.PullRequestID,
+			Title:  pr.Title,
+			URL:    prURL,
+			Status: pr.Status,
+		},
+		SourceBranch: strings.TrimPrefix(pr.SourceRefName, refHeadsPrefix),
+		TargetBranch: strings.TrimPrefix(pr.TargetRefName, refHeadsPrefix),
+		Author:       pr.CreatedBy.DisplayName,
+		IsDraft:      pr.IsDraft,
+	}
+}
+
+// adoOrganization returns the organization (or, on Azure DevOps Server, the
+// collection) a delivery's account base URL points at.
+func adoOrganization(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	if sub, ok := strings.CutSuffix(u.Host, ".visualstudio.com"); ok {
+		return sub
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Host == "dev.azure.com" {
+		return segments[0]
+	}
+	return segments[len(segments)-1]
+}
+
+// stripUserinfo removes the user name Azure DevOps puts in remote URLs.
+func stripUserinfo(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,206 @@
+package infrastructure_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+	webhookInfra "github.com/rios0rios0/gitforge/pkg/webhook/infrastructure"
+)
+
+const (
+	adoRepositoryJSON = `"repository":{"id":"repo-guid","name":"my-repo",` +
+		`"remoteUrl":"https://my-org@dev.azure.com/my-org/my-project/_git/my-repo",` +
+		`"defaultBranch":"refs/heads/main","project":{"name":"my-project"}}`
+	adoContainersJSON = `"resourceContainers":{"account":{"baseUrl":"https://dev.azure.com/my-org/"}}`
+)
+
+func parseAzureDevOpsDelivery(t *testing.T, body string) (webhookEntities.Event, error) {
+	t.Helper()
+	return webhookInfra.NewParser(unverified()).Parse(newDelivery(body, nil))
+}
+
+func TestParseAzureDevOps(t *testing.T) {
+	t.Parallel()
+
+	wantRepository := globalEntities.Repository{
+		ID:            "repo-guid",
+		Name:          "my-repo",
+		Organization:  "my-org",
+		Project:       "my-project",
+		DefaultBranch: "refs/heads/main",
+		RemoteURL:     "https://my-org@dev.azure.com/my-org/my-project/_git/my-repo",
+		ProviderName:  "azuredevops",
+	}
+
+	t.Run("should parse a created pull request", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"id":"delivery-1","eventType":"git.pullrequest.created",` + adoContainersJSON +
+			`,"resource":{"pullRequestId":12,"title":"Add feature","status":"active","isDraft":false,` +
+			`"sourceRefName":"refs/heads/feature","targetRefName":"refs/heads/main",` +
+			`"createdBy":{"displayName":"Alice"},` + adoRepositoryJSON + `}}`
+
+		// when
+		event, err := parseAzureDevOpsDelivery(t, body)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, webhookEntities.PullRequestOpened{
+			EventMetadata: webhookEntities.EventMetadata{
+				ServiceType: globalEntities.AZUREDEVOPS,
+				Name:        "git.pullrequest.created",
+				DeliveryID:  "delivery-1",
+				Repository:  wantRepository,
+				Sender:      "Alice",
+			},
+			PullRequest: globalEntities.PullRequestDetail{
+				PullRequest: globalEntities.PullRequest{
+					ID:     12,
+					Title:  "Add feature",
+					URL:    "https://dev.azure.com/my-org/my-project/_git/my-repo/pullrequest/12",
+					Status: "active",
+				},
+				SourceBranch: "feature",
+				TargetBranch: "main",
+				Author:       "Alice",
+			},
+		}, event)
+	})
+
+	t.Run("should tell updated, completed and abandoned pull requests apart", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			status string
+			want   webhookEntities.Event
+		}{
+			{status: "active", want: webhookEntities.PullRequestUpdated{}},
+			{status: "completed", want: webhookEntities.PullRequestMerged{}},
+			{status: "abandoned", want: webhookEntities.PullRequestClosed{}},
+		}
+		for _, tt := range tests {
+			// given
+			body := `{"eventType":"git.pullrequest.updated",` + adoContainersJSON +
+				`,"resource":{"pullRequestId":12,"status":"` + tt.status + `",` +
+				`"lastMergeCommit":{"commitId":"merge-sha"},` + adoRepositoryJSON + `}}`
+
+			// when
+			event, err := parseAzureDevOpsDelivery(t, body)
+
+			// then
+			require.NoError(t, err, tt.status)
+			assert.IsType(t, tt.want, event, tt.status)
+		}
+	})
+
+	t.Run("should parse a pull request comment with its thread", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"eventType":"ms.vss-code.git-pullrequest-comment-event",` + adoContainersJSON +
+			`,"resource":{"comment":{"id":2,"parentCommentId":1,"content":"Agreed",` +
+			`"author":{"displayName":"Bob"},"_links":{"threads":{"href":` +
+			`"https://dev.azure.com/my-org/_apis/git/repositories/repo-guid/pullRequests/12/threads/77"}}},` +
+			`"pullRequest":{"pullRequestId":12,` + adoRepositoryJSON + `}}}`
+
+		// when
+		event, err := parseAzureDevOpsDelivery(t, body)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, webhookEntities.CommentCreated{}, event)
+		comment := event.(webhookEntities.CommentCreated)
+		assert.Equal(t, "Bob", comment.Sender)
+		assert.Equal(t, 12, comment.PullRequest.ID)
+		assert.Equal(t, globalEntities.PullRequestComment{
+			ID: 2, ThreadID: 77, Body: "Agreed", Author: "Bob", InReplyToID: 1,
+		}, comment.Comment)
+	})
+
+	t.Run("should parse branch pushes and new tags", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		push := `{"eventType":"git.push",` + adoContainersJSON + `,"resource":{"refUpdates":[` +
+			`{"name":"refs/heads/main","oldObjectId":"aaa","newObjectId":"bbb"}],` +
+			`"pushedBy":{"displayName":"Alice"},` + adoRepositoryJSON + `}}`
+		tag := `{"eventType":"git.push",` + adoContainersJSON + `,"resource":{"refUpdates":[` +
+			`{"name":"refs/tags/v1","oldObjectId":"0000000000000000000000000000000000000000",` +
+			`"newObjectId":"ccc"}],` + adoRepositoryJSON + `}}`
+
+		// when
+		pushEvent, pushErr := parseAzureDevOpsDelivery(t, push)
+		tagEvent, tagErr := parseAzureDevOpsDelivery(t, tag)
+
+		// then
+		require.NoError(t, pushErr)
+		require.NoError(t, tagErr)
+		assert.Equal(t, webhookEntities.Push{
+			EventMetadata: webhookEntities.EventMetadata{
+				ServiceType: globalEntities.AZUREDEVOPS,
+				Name:        "git.push",
+				Repository:  wantRepository,
+				Sender:      "Alice",
+			},
+			Branch: "main", Before: "aaa", After: "bbb",
+		}, pushEvent)
+		require.IsType(t, webhookEntities.TagCreated{}, tagEvent)
+		assert.Equal(t, "ccc", tagEvent.(webhookEntities.TagCreated).CommitSHA)
+	})
+
+	t.Run("should read the organization from visualstudio.com and server URLs", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			baseURL string
+			want    string
+		}{
+			{baseURL: "https://legacy-org.visualstudio.com/", want: "legacy-org"},
+			{baseURL: "https://tfs.example.com/tfs/DefaultCollection/", want: "DefaultCollection"},
+		}
+		for _, tt := range tests {
+			// given
+			body := `{"eventType":"git.push","resourceContainers":{"account":{"baseUrl":"` + tt.baseURL + `"}},` +
+				`"resource":{"refUpdates":[{"name":"refs/heads/main"}]}}`
+
+			// when
+			event, err := parseAzureDevOpsDelivery(t, body)
+
+			// then
+			require.NoError(t, err, tt.baseURL)
+			assert.Equal(t, tt.want, event.Metadata().Repository.Organization, tt.baseURL)
+		}
+	})
+
+	t.Run("should return ErrUnsupported for other event types", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"eventType":"build.complete","resource":{}}`
+
+		// when
+		_, err := parseAzureDevOpsDelivery(t, body)
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+
+	t.Run("should return ErrMalformedPayload without an event type", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"hello":"world"}`
+
+		// when
+		_, err := parseAzureDevOpsDelivery(t, body)
+
+		// then
+		require.ErrorIs(t, err, webhookInfra.ErrMalformedPayload)
+	})
+}
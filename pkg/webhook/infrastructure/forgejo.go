@@ -0,0 +1,101 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+)
+
+// Forgejo sends its own headers alongside the Gitea ones it inherited; Gitea
+// only sends the latter.
+const (
+	headerForgejoEvent     = "X-Forgejo-Event"
+	headerForgejoSignature = "X-Forgejo-Signature"
+	headerForgejoDelivery  = "X-Forgejo-Delivery"
+	headerGiteaEvent       = "X-Gitea-Event"
+	headerGiteaSignature   = "X-Gitea-Signature"
+	headerGiteaDelivery    = "X-Gitea-Delivery"
+
+	codebergHost = "codeberg.org"
+)
+
+// forgejoEventName returns the event name of a Forgejo or Gitea delivery, or
+// an empty string for other forges. Gitea also sends X-GitHub-Event, so this
+// is checked before the GitHub header.
+func forgejoEventName(header http.Header) string {
+	return firstHeader(header, headerForgejoEvent, headerGiteaEvent)
+}
+
+// forgejoSignature returns the hex HMAC-SHA256 of a Forgejo or Gitea delivery.
+func forgejoSignature(header http.Header) string {
+	return firstHeader(header, headerForgejoSignature, headerGiteaSignature)
+}
+
+// parseForgejo normalizes a Forgejo or Gitea delivery. Deliveries from
+// codeberg.org are attributed to Codeberg, the rest to Gitea.
+func parseForgejo(header http.Header, body []byte) (webhookEntities.Event, error) {
+	var payload hubPayload
+	if unmarshalErr := json.Unmarshal(body, &payload); unmarshalErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+	}
+
+	serviceType, providerName := globalEntities.GITEA, "gitea"
+	if u, err := url.Parse(payload.Repository.HTMLURL); err == nil && u.Host == codebergHost {
+		serviceType, providerName = globalEntities.CODEBERG, "codeberg"
+	}
+
+	name := forgejoEventName(header)
+	metadata := webhookEntities.EventMetadata{
+		ServiceType: serviceType,
+		Name:        name,
+		DeliveryID:  firstHeader(header, headerForgejoDelivery, headerGiteaDelivery),
+		Repository:  payload.Repository.toDomain(providerName),
+		Sender:      payload.Sender.name(),
+	}
+
+	switch name {
+	case "pull_request":
+		return hubPullRequestEvent(metadata, payload)
+	case "issue_comment":
+		// comments on pull requests come as issue comments flagged is_pull
+		if payload.Action != "created" || !payload.IsPull || payload.Comment == nil {
+			return nil, unsupportedEvent(name, payload.Action)
+		}
+		var pr globalEntities.PullRequestDetail
+		switch {
+		case payload.PullRequest != nil:
+			pr = payload.PullRequest.toDomain()
+		case payload.Issue != nil:
+			pr = payload.Issue.toDomain()
+		default:
+			return nil, fmt.Errorf("%w: comment event without a pull request", ErrMalformedPayload)
+		}
+		return webhookEntities.CommentCreated{
+			EventMetadata: metadata,
+			PullRequest:   pr,
+			Comment: globalEntities.PullRequestComment{
+				ID:     payload.Comment.ID,
+				Body:   payload.Comment.Body,
+				Author: payload.Comment.User.name(),
+			},
+		}, nil
+	case "push":
+		return refUpdateEvent(metadata, payload.Ref, payload.Before, payload.After, payload.headCommitID())
+	default:
+		return nil, unsupportedEvent(name, payload.Action)
+	}
+}
+
+// firstHeader returns the first of keys set in header.
+func firstHeader(header http.Header, keys ...string) string {
+	for _, key := range keys {
+		if value := header.Get(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
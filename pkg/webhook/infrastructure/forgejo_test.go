@@ -0,0 +1,142 @@
+package infrastructure_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+	webhookInfra "github.com/rios0rios0/gitforge/pkg/webhook/infrastructure"
+)
+
+const codebergRepositoryJSON = `"repository":{"id":5,"name":"my-repo",` +
+	`"owner":{"login":"my-org","username":"my-org"},"default_branch":"main",` +
+	`"html_url":"https://codeberg.org/my-org/my-repo","clone_url":"https://codeberg.org/my-org/my-repo.git",` +
+	`"ssh_url":"git@codeberg.org:my-org/my-repo.git"},"sender":{"login":"alice"}`
+
+func parseForgejoDelivery(t *testing.T, event, body string) (webhookEntities.Event, error) {
+	t.Helper()
+	r := newDelivery(body, map[string]string{
+		"X-Forgejo-Event":    event,
+		"X-Forgejo-Delivery": "delivery-1",
+		"X-Gitea-Event":      event,
+		"X-GitHub-Event":     event,
+	})
+	return webhookInfra.NewParser(unverified()).Parse(r)
+}
+
+func TestParseForgejo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should attribute codeberg.org deliveries to Codeberg", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"opened","pull_request":{"number":3,"title":"Fix","state":"open",` +
+			`"html_url":"https://codeberg.org/my-org/my-repo/pulls/3","user":{"login":"alice"},` +
+			`"head":{"ref":"fix","sha":"abc"},"base":{"ref":"main"}},` + codebergRepositoryJSON + `}`
+
+		// when
+		event, err := parseForgejoDelivery(t, "pull_request", body)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, webhookEntities.PullRequestOpened{
+			EventMetadata: webhookEntities.EventMetadata{
+				ServiceType: globalEntities.CODEBERG,
+				Name:        "pull_request",
+				DeliveryID:  "delivery-1",
+				Repository: globalEntities.Repository{
+					ID:            "5",
+					Name:          "my-repo",
+					Organization:  "my-org",
+					DefaultBranch: "refs/heads/main",
+					RemoteURL:     "https://codeberg.org/my-org/my-repo.git",
+					SSHURL:        "git@codeberg.org:my-org/my-repo.git",
+					ProviderName:  "codeberg",
+				},
+				Sender: "alice",
+			},
+			PullRequest: globalEntities.PullRequestDetail{
+				PullRequest: globalEntities.PullRequest{
+					ID: 3, Title: "Fix", URL: "https://codeberg.org/my-org/my-repo/pulls/3", Status: "open",
+				},
+				SourceBranch: "fix",
+				TargetBranch: "main",
+				Author:       "alice",
+			},
+		}, event)
+	})
+
+	t.Run("should parse a synchronized pull request as updated", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"synchronized","pull_request":{"number":3,"head":{"sha":"def"}},` +
+			codebergRepositoryJSON + `}`
+
+		// when
+		event, err := parseForgejoDelivery(t, "pull_request", body)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, webhookEntities.PullRequestUpdated{}, event)
+		assert.Equal(t, "def", event.(webhookEntities.PullRequestUpdated).HeadSHA)
+	})
+
+	t.Run("should parse a comment on a pull request", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"created","is_pull":true,"issue":{"number":3,"title":"Fix"},` +
+			`"pull_request":{"number":3,"title":"Fix","head":{"ref":"fix"},"base":{"ref":"main"}},` +
+			`"comment":{"id":12,"body":"Thanks","user":{"login":"bob"}},` + codebergRepositoryJSON + `}`
+
+		// when
+		event, err := parseForgejoDelivery(t, "issue_comment", body)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, webhookEntities.CommentCreated{}, event)
+		comment := event.(webhookEntities.CommentCreated)
+		assert.Equal(t, "fix", comment.PullRequest.SourceBranch)
+		assert.Equal(t, globalEntities.PullRequestComment{ID: 12, Body: "Thanks", Author: "bob"}, comment.Comment)
+	})
+
+	t.Run("should not report comments on issues", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"created","is_pull":false,"issue":{"number":2},"comment":{"id":12},` +
+			codebergRepositoryJSON + `}`
+
+		// when
+		_, err := parseForgejoDelivery(t, "issue_comment", body)
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+
+	t.Run("should attribute self-hosted deliveries to Gitea", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"ref":"refs/heads/main","before":"aaa","after":"bbb",` +
+			`"repository":{"name":"my-repo","owner":{"username":"my-org"},"html_url":"https://git.example.com/my-org/my-repo"}}`
+
+		// when
+		event, err := parseForgejoDelivery(t, "push", body)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, webhookEntities.Push{}, event)
+		push := event.(webhookEntities.Push)
+		assert.Equal(t, globalEntities.GITEA, push.ServiceType)
+		assert.Equal(t, "gitea", push.Repository.ProviderName)
+		assert.Equal(t, "my-org", push.Repository.Organization)
+		assert.Equal(t, "main", push.Branch)
+	})
+}
@@ -0,0 +1,290 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+)
+
+const (
+	headerGitHubEvent     = "X-GitHub-Event"
+	headerGitHubSignature = "X-Hub-Signature-256"
+	headerGitHubDelivery  = "X-GitHub-Delivery"
+
+	refHeadsPrefix = "refs/heads/"
+	refTagsPrefix  = "refs/tags/"
+)
+
+// hubUser, hubRepository, hubPullRequest, hubIssue, hubComment and hubPayload
+// decode the payloads GitHub and Forgejo share: Forgejo copied GitHub's event
+// shapes, adding a few fields of its own.
+type hubUser struct {
+	Login    string `json:"login"`
+	Username string `json:"username"` // Forgejo
+}
+
+// name returns the user's login, whichever field carries it.
+func (u hubUser) name() string {
+	if u.Login != "" {
+		return u.Login
+	}
+	return u.Username
+}
+
+type hubRepository struct {
+	ID            int64   `json:"id"`
+	Name          string  `json:"name"`
+	Owner         hubUser `json:"owner"`
+	DefaultBranch string  `json:"default_branch"`
+	HTMLURL       string  `json:"html_url"`
+	CloneURL      string  `json:"clone_url"`
+	SSHURL        string  `json:"ssh_url"`
+	Fork          bool    `json:"fork"`
+	Archived      bool    `json:"archived"`
+}
+
+type hubPullRequest struct {
+	Number         int     `json:"number"`
+	Title          string  `json:"title"`
+	HTMLURL        string  `json:"html_url"`
+	State          string  `json:"state"`
+	Draft          bool    `json:"draft"`
+	Merged         bool    `json:"merged"`
+	MergeCommitSHA string  `json:"merge_commit_sha"`
+	User           hubUser `json:"user"`
+	Head           struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+type hubIssue struct {
+	Number      int             `json:"number"`
+	Title       string          `json:"title"`
+	HTMLURL     string          `json:"html_url"`
+	State       string          `json:"state"`
+	User        hubUser         `json:"user"`
+	PullRequest json.RawMessage `json:"pull_request"`
+}
+
+type hubComment struct {
+	ID          int64   `json:"id"`
+	Body        string  `json:"body"`
+	User        hubUser `json:"user"`
+	Path        string  `json:"path"`
+	Line        int     `json:"line"`
+	InReplyToID int64   `json:"in_reply_to_id"`
+}
+
+type hubPayload struct {
+	Action      string          `json:"action"`
+	Repository  hubRepository   `json:"repository"`
+	Sender      hubUser         `json:"sender"`
+	PullRequest *hubPullRequest `json:"pull_request"`
+	Issue       *hubIssue       `json:"issue"`
+	Comment     *hubComment     `json:"comment"`
+	IsPull      bool            `json:"is_pull"` // Forgejo
+	Ref         string          `json:"ref"`
+	Before      string          `json:"before"`
+	After       string          `json:"after"`
+	HeadCommit  *struct {
+		ID string `json:"id"`
+	} `json:"head_commit"`
+}
+
+// parseGitHub normalizes a GitHub delivery.
+func parseGitHub(header http.Header, body []byte) (webhookEntities.Event, error) {
+	var payload hubPayload
+	if unmarshalErr := json.Unmarshal(body, &payload); unmarshalErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+	}
+
+	name := header.Get(headerGitHubEvent)
+	metadata := webhookEntities.EventMetadata{
+		ServiceType: globalEntities.GITHUB,
+		Name:        name,
+		DeliveryID:  header.Get(headerGitHubDelivery),
+		Repository:  payload.Repository.toDomain("github"),
+		Sender:      payload.Sender.name(),
+	}
+
+	switch name {
+	case "pull_request":
+		return hubPullRequestEvent(metadata, payload)
+	case "issue_comment":
+		if payload.Action != "created" || payload.Issue == nil || payload.Comment == nil ||
+			!hasPullRequest(payload.Issue.PullRequest) {
+			return nil, unsupportedEvent(name, payload.Action)
+		}
+		return webhookEntities.CommentCreated{
+			EventMetadata: metadata,
+			PullRequest:   payload.Issue.toDomain(),
+			Comment: globalEntities.PullRequestComment{
+				ID:     payload.Comment.ID,
+				Body:   payload.Comment.Body,
+				Author: payload.Comment.User.name(),
+			},
+		}, nil
+	case "pull_request_review_comment":
+		if payload.Action != "created" || payload.PullRequest == nil || payload.Comment == nil {
+			return nil, unsupportedEvent(name, payload.Action)
+		}
+		// the thread is named after its root comment, as ListPullRequestComments does
+		threadID := payload.Comment.InReplyToID
+		if threadID == 0 {
+			threadID = payload.Comment.ID
+		}
+		return webhookEntities.CommentCreated{
+			EventMetadata: metadata,
+			PullRequest:   payload.PullRequest.toDomain(),
+			Comment: globalEntities.PullRequestComment{
+				ID:          payload.Comment.ID,
+				ThreadID:    threadID,
+				Body:        payload.Comment.Body,
+				Author:      payload.Comment.User.name(),
+				FilePath:    payload.Comment.Path,
+				Line:        payload.Comment.Line,
+				InReplyToID: payload.Comment.InReplyToID,
+			},
+		}, nil
+	case "push":
+		return refUpdateEvent(metadata, payload.Ref, payload.Before, payload.After, payload.headCommitID())
+	default:
+		return nil, unsupportedEvent(name, payload.Action)
+	}
+}
+
+// hubPullRequestEvent maps the actions of a GitHub or Forgejo pull_request
+// delivery.
+func hubPullRequestEvent(
+	metadata webhookEntities.EventMetadata,
+	payload hubPayload,
+) (webhookEntities.Event, error) {
+	if payload.PullRequest == nil {
+		return nil, fmt.Errorf("%w: pull_request event without a pull request", ErrMalformedPayload)
+	}
+	pr := payload.PullRequest.toDomain()
+
+	switch payload.Action {
+	case "opened", "reopened":
+		return webhookEntities.PullRequestOpened{EventMetadata: metadata, PullRequest: pr}, nil
+	case "synchronize", "synchronized", "edited", "ready_for_review", "converted_to_draft":
+		return webhookEntities.PullRequestUpdated{
+			EventMetadata: metadata,
+			PullRequest:   pr,
+			HeadSHA:       payload.PullRequest.Head.SHA,
+		}, nil
+	case "closed":
+		if payload.PullRequest.Merged {
+			return webhookEntities.PullRequestMerged{
+				EventMetadata:  metadata,
+				PullRequest:    pr,
+				MergeCommitSHA: payload.PullRequest.MergeCommitSHA,
+			}, nil
+		}
+		return webhookEntities.PullRequestClosed{EventMetadata: metadata, PullRequest: pr}, nil
+	default:
+		return nil, unsupportedEvent(metadata.Name, payload.Action)
+	}
+}
+
+func (r hubRepository) toDomain(providerName string) globalEntities.Repository {
+	repo := globalEntities.Repository{
+		Name:         r.Name,
+		Organization: r.Owner.name(),
+		RemoteURL:    r.CloneURL,
+		SSHURL:       r.SSHURL,
+		ProviderName: providerName,
+		IsFork:       r.Fork,
+		IsArchived:   r.Archived,
+	}
+	if r.ID != 0 {
+		repo.ID = strconv.FormatInt(r.ID, 10)
+	}
+	if r.DefaultBranch != "" {
+		repo.DefaultBranch = refHeadsPrefix + r.DefaultBranch
+	}
+	return repo
+}
+
+func (pr hubPullRequest) toDomain() globalEntities.PullRequestDetail {
+	return globalEntities.PullRequestDetail{
+		PullRequest: globalEntities.PullRequest{
+			ID:     pr.Number,
+			Title:  pr.Title,
+			URL:    pr.HTMLURL,
+			Status: pr.State,
+		},
+		SourceBranch: pr.Head.Ref,
+		TargetBranch: pr.Base.Ref,
+		Author:       pr.User.name(),
+		IsDraft:      pr.Draft,
+	}
+}
+
+func (i hubIssue) toDomain() globalEntities.PullRequestDetail {
+	return globalEntities.PullRequestDetail{
+		PullRequest: globalEntities.PullRequest{
+			ID:     i.Number,
+			Title:  i.Title,
+			URL:    i.HTMLURL,
+			Status: i.State,
+		},
+		Author: i.User.name(),
+	}
+}
+
+func (p hubPayload) headCommitID() string {
+	if p.HeadCommit == nil {
+		return ""
+	}
+	return p.HeadCommit.ID
+}
+
+// hasPullRequest reports whether an issue's pull_request field is set, which
+// tells pull requests apart from plain issues.
+func hasPullRequest(raw json.RawMessage) bool {
+	return len(raw) > 0 && string(raw) != "null"
+}
+
+// refUpdateEvent turns a ref update into a Push for branches or a
+// TagCreated for new tags. commitSHA is the tagged commit when the payload
+// carries it apart from after.
+func refUpdateEvent(
+	metadata webhookEntities.EventMetadata,
+	ref, before, after, commitSHA string,
+) (webhookEntities.Event, error) {
+	if branch, ok := strings.CutPrefix(ref, refHeadsPrefix); ok {
+		return webhookEntities.Push{EventMetadata: metadata, Branch: branch, Before: before, After: after}, nil
+	}
+	if tag, ok := strings.CutPrefix(ref, refTagsPrefix); ok && isZeroCommit(before) {
+		if commitSHA == "" {
+			commitSHA = after
+		}
+		return webhookEntities.TagCreated{EventMetadata: metadata, Tag: tag, CommitSHA: commitSHA}, nil
+	}
+	return nil, fmt.Errorf("push to %q is not supported: %w", ref, errors.ErrUnsupported)
+}
+
+// unsupportedEvent reports an event or action with no typed counterpart.
+func unsupportedEvent(name, action string) error {
+	if action == "" {
+		return fmt.Errorf("event %q is not supported: %w", name, errors.ErrUnsupported)
+	}
+	return fmt.Errorf("event %q with action %q is not supported: %w", name, action, errors.ErrUnsupported)
+}
+
+// isZeroCommit reports whether sha is the all-zeros object ID forges send
+// for a missing side of a ref update.
+func isZeroCommit(sha string) bool {
+	return sha != "" && strings.Trim(sha, "0") == ""
+}
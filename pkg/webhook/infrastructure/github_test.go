@@ -0,0 +1,219 @@
+package infrastructure_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+	webhookInfra "github.com/rios0rios0/gitforge/pkg/webhook/infrastructure"
+)
+
+const githubRepositoryJSON = `"repository":{"id":42,"name":"my-repo","owner":{"login":"my-org"},` +
+	`"default_branch":"main","clone_url":"https://github.com/my-org/my-repo.git",` +
+	`"ssh_url":"git@github.com:my-org/my-repo.git"},"sender":{"login":"octocat"}`
+
+const githubPullRequestJSON = `"pull_request":{"number":7,"title":"Add feature",` +
+	`"html_url":"https://github.com/my-org/my-repo/pull/7","state":"open","draft":true,` +
+	`"user":{"login":"author"},"head":{"ref":"feature","sha":"abc123"},"base":{"ref":"main"}}`
+
+func parseGitHubDelivery(t *testing.T, event, body string) (webhookEntities.Event, error) {
+	t.Helper()
+	r := newDelivery(body, map[string]string{"X-GitHub-Event": event, "X-GitHub-Delivery": "delivery-1"})
+	return webhookInfra.NewParser(unverified()).Parse(r)
+}
+
+func TestParseGitHub(t *testing.T) {
+	t.Parallel()
+
+	wantMetadata := func(name string) webhookEntities.EventMetadata {
+		return webhookEntities.EventMetadata{
+			ServiceType: globalEntities.GITHUB,
+			Name:        name,
+			DeliveryID:  "delivery-1",
+			Repository: globalEntities.Repository{
+				ID:            "42",
+				Name:          "my-repo",
+				Organization:  "my-org",
+				DefaultBranch: "refs/heads/main",
+				RemoteURL:     "https://github.com/my-org/my-repo.git",
+				SSHURL:        "git@github.com:my-org/my-repo.git",
+				ProviderName:  "github",
+			},
+			Sender: "octocat",
+		}
+	}
+	wantPR := globalEntities.PullRequestDetail{
+		PullRequest: globalEntities.PullRequest{
+			ID: 7, Title: "Add feature", URL: "https://github.com/my-org/my-repo/pull/7", Status: "open",
+		},
+		SourceBranch: "feature",
+		TargetBranch: "main",
+		Author:       "author",
+		IsDraft:      true,
+	}
+
+	t.Run("should parse an opened pull request", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"opened",` + githubPullRequestJSON + `,` + githubRepositoryJSON + `}`
+
+		// when
+		event, err := parseGitHubDelivery(t, "pull_request", body)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, webhookEntities.PullRequestOpened{
+			EventMetadata: wantMetadata("pull_request"),
+			PullRequest:   wantPR,
+		}, event)
+	})
+
+	t.Run("should parse a synchronized pull request as updated", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"synchronize",` + githubPullRequestJSON + `,` + githubRepositoryJSON + `}`
+
+		// when
+		event, err := parseGitHubDelivery(t, "pull_request", body)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, webhookEntities.PullRequestUpdated{
+			EventMetadata: wantMetadata("pull_request"),
+			PullRequest:   wantPR,
+			HeadSHA:       "abc123",
+		}, event)
+	})
+
+	t.Run("should tell merged pull requests apart from closed ones", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		merged := `{"action":"closed","pull_request":{"number":7,"merged":true,"merge_commit_sha":"def456"},` +
+			githubRepositoryJSON + `}`
+		closed := `{"action":"closed","pull_request":{"number":8,"merged":false},` + githubRepositoryJSON + `}`
+
+		// when
+		mergedEvent, mergedErr := parseGitHubDelivery(t, "pull_request", merged)
+		closedEvent, closedErr := parseGitHubDelivery(t, "pull_request", closed)
+
+		// then
+		require.NoError(t, mergedErr)
+		require.NoError(t, closedErr)
+		require.IsType(t, webhookEntities.PullRequestMerged{}, mergedEvent)
+		assert.Equal(t, "def456", mergedEvent.(webhookEntities.PullRequestMerged).MergeCommitSHA)
+		require.IsType(t, webhookEntities.PullRequestClosed{}, closedEvent)
+		assert.Equal(t, 8, closedEvent.(webhookEntities.PullRequestClosed).PullRequest.ID)
+	})
+
+	t.Run("should parse a comment on a pull request", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"created","issue":{"number":7,"title":"Add feature","state":"open",` +
+			`"user":{"login":"author"},"pull_request":{"url":"https://api.github.com/pulls/7"}},` +
+			`"comment":{"id":99,"body":"LGTM","user":{"login":"reviewer"}},` + githubRepositoryJSON + `}`
+
+		// when
+		event, err := parseGitHubDelivery(t, "issue_comment", body)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, webhookEntities.CommentCreated{}, event)
+		comment := event.(webhookEntities.CommentCreated)
+		assert.Equal(t, 7, comment.PullRequest.ID)
+		assert.Equal(t, globalEntities.PullRequestComment{ID: 99, Body: "LGTM", Author: "reviewer"}, comment.Comment)
+	})
+
+	t.Run("should not report comments on issues", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"created","issue":{"number":3},"comment":{"id":99},` + githubRepositoryJSON + `}`
+
+		// when
+		_, err := parseGitHubDelivery(t, "issue_comment", body)
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+
+	t.Run("should parse an inline review comment with its thread", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"action":"created",` + githubPullRequestJSON + `,"comment":{"id":101,"body":"nit",` +
+			`"user":{"login":"reviewer"},"path":"main.go","line":12,"in_reply_to_id":100},` +
+			githubRepositoryJSON + `}`
+
+		// when
+		event, err := parseGitHubDelivery(t, "pull_request_review_comment", body)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, webhookEntities.CommentCreated{
+			EventMetadata: wantMetadata("pull_request_review_comment"),
+			PullRequest:   wantPR,
+			Comment: globalEntities.PullRequestComment{
+				ID: 101, ThreadID: 100, Body: "nit", Author: "reviewer",
+				FilePath: "main.go", Line: 12, InReplyToID: 100,
+			},
+		}, event)
+	})
+
+	t.Run("should parse a branch push", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"ref":"refs/heads/main","before":"aaa","after":"bbb",` + githubRepositoryJSON + `}`
+
+		// when
+		event, err := parseGitHubDelivery(t, "push", body)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, webhookEntities.Push{
+			EventMetadata: wantMetadata("push"), Branch: "main", Before: "aaa", After: "bbb",
+		}, event)
+	})
+
+	t.Run("should parse a new tag and ignore a deleted one", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		created := `{"ref":"refs/tags/v1.0.0","before":"0000000000000000000000000000000000000000",` +
+			`"after":"bbb","head_commit":{"id":"ccc"},` + githubRepositoryJSON + `}`
+		deleted := `{"ref":"refs/tags/v1.0.0","before":"bbb",` +
+			`"after":"0000000000000000000000000000000000000000",` + githubRepositoryJSON + `}`
+
+		// when
+		createdEvent, createdErr := parseGitHubDelivery(t, "push", created)
+		_, deletedErr := parseGitHubDelivery(t, "push", deleted)
+
+		// then
+		require.NoError(t, createdErr)
+		assert.Equal(t, webhookEntities.TagCreated{
+			EventMetadata: wantMetadata("push"), Tag: "v1.0.0", CommitSHA: "ccc",
+		}, createdEvent)
+		require.ErrorIs(t, deletedErr, errors.ErrUnsupported)
+	})
+
+	t.Run("should return ErrUnsupported for a ping", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"zen":"Design for failure.","hook_id":1,` + githubRepositoryJSON + `}`
+
+		// when
+		_, err := parseGitHubDelivery(t, "ping", body)
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+}
@@ -0,0 +1,192 @@
+package infrastructure
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+)
+
+const (
+	headerGitLabEvent    = "X-Gitlab-Event"
+	headerGitLabToken    = "X-Gitlab-Token"
+	headerGitLabDelivery = "X-Gitlab-Event-UUID"
+)
+
+type gitlabProject struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	GitHTTPURL        string `json:"git_http_url"`
+	GitSSHURL         string `json:"git_ssh_url"`
+}
+
+// gitlabMergeRequest is a merge request as Merge Request Hook payloads carry
+// it in object_attributes and Note Hook payloads in merge_request. Both name
+// the author by ID only.
+type gitlabMergeRequest struct {
+	IID            int    `json:"iid"`
+	Title          string `json:"title"`
+	URL            string `json:"url"`
+	State          string `json:"state"`
+	Action         string `json:"action"`
+	SourceBranch   string `json:"source_branch"`
+	TargetBranch   string `json:"target_branch"`
+	Draft          bool   `json:"draft"`
+	WorkInProgress bool   `json:"work_in_progress"`
+	MergeCommitSHA string `json:"merge_commit_sha"`
+	LastCommit     struct {
+		ID string `json:"id"`
+	} `json:"last_commit"`
+}
+
+type gitlabNote struct {
+	ID           int64  `json:"id"`
+	Note         string `json:"note"`
+	NoteableType string `json:"noteable_type"`
+	Action       string `json:"action"`
+	Position     *struct {
+		NewPath string `json:"new_path"`
+		NewLine int    `json:"new_line"`
+	} `json:"position"`
+}
+
+type gitlabPayload struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	UserUsername     string              `json:"user_username"` // push events
+	Project          gitlabProject       `json:"project"`
+	ObjectAttributes json.RawMessage     `json:"object_attributes"`
+	MergeRequest     *gitlabMergeRequest `json:"merge_request"`
+	Ref              string              `json:"ref"`
+	Before           string              `json:"before"`
+	After            string              `json:"after"`
+	CheckoutSHA      string              `json:"checkout_sha"`
+}
+
+// parseGitLab normalizes a GitLab delivery. Merge request payloads name the
+// author by ID only, so PullRequest.Author is left empty.
+func parseGitLab(header http.Header, body []byte) (webhookEntities.Event, error) {
+	var payload gitlabPayload
+	if unmarshalErr := json.Unmarshal(body, &payload); unmarshalErr != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+	}
+
+	name := header.Get(headerGitLabEvent)
+	metadata := webhookEntities.EventMetadata{
+		ServiceType: globalEntities.GITLAB,
+		Name:        name,
+		DeliveryID:  header.Get(headerGitLabDelivery),
+		Repository:  payload.Project.toDomain(),
+		Sender:      payload.User.Username,
+	}
+
+	switch payload.ObjectKind {
+	case "merge_request":
+		var mr gitlabMergeRequest
+		if unmarshalErr := json.Unmarshal(payload.ObjectAttributes, &mr); unmarshalErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+		}
+		return gitlabMergeRequestEvent(metadata, mr)
+	case "note":
+		var note gitlabNote
+		if unmarshalErr := json.Unmarshal(payload.ObjectAttributes, &note); unmarshalErr != nil {
+			return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, unmarshalErr)
+		}
+		// older GitLab versions only send created notes, without an action
+		if note.NoteableType != "MergeRequest" || payload.MergeRequest == nil ||
+			(note.Action != "" && note.Action != "create") {
+			return nil, unsupportedEvent(name, note.Action)
+		}
+		comment := globalEntities.PullRequestComment{
+			ID:     note.ID,
+			Body:   note.Note,
+			Author: payload.User.Username,
+		}
+		if note.Position != nil {
+			comment.FilePath = note.Position.NewPath
+			comment.Line = note.Position.NewLine
+		}
+		return webhookEntities.CommentCreated{
+			EventMetadata: metadata,
+			PullRequest:   payload.MergeRequest.toDomain(),
+			Comment:       comment,
+		}, nil
+	case "push", "tag_push":
+		metadata.Sender = payload.UserUsername
+		return refUpdateEvent(metadata, payload.Ref, payload.Before, payload.After, payload.CheckoutSHA)
+	default:
+		return nil, unsupportedEvent(name, "")
+	}
+}
+
+// gitlabMergeRequestEvent maps the actions of a Merge Request Hook delivery.
+func gitlabMergeRequestEvent(
+	metadata webhookEntities.EventMetadata,
+	mr gitlabMergeRequest,
+) (webhookEntities.Event, error) {
+	pr := mr.toDomain()
+
+	switch mr.Action {
+	case "open", "reopen":
+		return webhookEntities.PullRequestOpened{EventMetadata: metadata, PullRequest: pr}, nil
+	case "update":
+		return webhookEntities.PullRequestUpdated{
+			EventMetadata: metadata,
+			PullRequest:   pr,
+			HeadSHA:       mr.LastCommit.ID,
+		}, nil
+	case "merge":
+		return webhookEntities.PullRequestMerged{
+			EventMetadata:  metadata,
+			PullRequest:    pr,
+			MergeCommitSHA: mr.MergeCommitSHA,
+		}, nil
+	case "close":
+		return webhookEntities.PullRequestClosed{EventMetadata: metadata, PullRequest: pr}, nil
+	default:
+		return nil, unsupportedEvent(metadata.Name, mr.Action)
+	}
+}
+
+func (p gitlabProject) toDomain() globalEntities.Repository {
+	repo := globalEntities.Repository{
+		Name:         p.Name,
+		RemoteURL:    p.GitHTTPURL,
+		SSHURL:       p.GitSSHURL,
+		ProviderName: "gitlab",
+	}
+	// the namespace may hold subgroups; the path is the repository's name
+	if i := strings.LastIndex(p.PathWithNamespace, "/"); i >= 0 {
+		repo.Organization = p.PathWithNamespace[:i]
+		repo.Name = p.PathWithNamespace[i+1:]
+	}
+	if p.ID != 0 {
+		repo.ID = strconv.FormatInt(p.ID, 10)
+	}
+	if p.DefaultBranch != "" {
+		repo.DefaultBranch = refHeadsPrefix + p.DefaultBranch
+	}
+	return repo
+}
+
+func (mr gitlabMergeRequest) toDomain() globalEntities.PullRequestDetail {
+	return globalEntities.PullRequestDetail{
+		PullRequest: globalEntities.PullRequest{
+			ID:     mr.IID,
+			Title:  mr.Title,
+			URL:    mr.URL,
+			Status: mr.State,
+		},
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		IsDraft:      mr.Draft || mr.WorkInProgress,
+	}
+}
@@ -0,0 +1,181 @@
+package infrastructure_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+	webhookInfra "github.com/rios0rios0/gitforge/pkg/webhook/infrastructure"
+)
+
+const gitlabProjectJSON = `"project":{"id":15,"name":"My Repo","path_with_namespace":"my-group/sub/my-repo",` +
+	`"default_branch":"main","git_http_url":"https://gitlab.com/my-group/sub/my-repo.git",` +
+	`"git_ssh_url":"git@gitlab.com:my-group/sub/my-repo.git"}`
+
+func parseGitLabDelivery(t *testing.T, event, body string) (webhookEntities.Event, error) {
+	t.Helper()
+	r := newDelivery(body, map[string]string{"X-Gitlab-Event": event, "X-Gitlab-Event-UUID": "uuid-1"})
+	return webhookInfra.NewParser(unverified()).Parse(r)
+}
+
+func TestParseGitLab(t *testing.T) {
+	t.Parallel()
+
+	wantRepository := globalEntities.Repository{
+		ID:            "15",
+		Name:          "my-repo",
+		Organization:  "my-group/sub",
+		DefaultBranch: "refs/heads/main",
+		RemoteURL:     "https://gitlab.com/my-group/sub/my-repo.git",
+		SSHURL:        "git@gitlab.com:my-group/sub/my-repo.git",
+		ProviderName:  "gitlab",
+	}
+
+	t.Run("should map merge request actions to events", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			action string
+			want   webhookEntities.Event
+		}{
+			{action: "open", want: webhookEntities.PullRequestOpened{}},
+			{action: "reopen", want: webhookEntities.PullRequestOpened{}},
+			{action: "update", want: webhookEntities.PullRequestUpdated{}},
+			{action: "merge", want: webhookEntities.PullRequestMerged{}},
+			{action: "close", want: webhookEntities.PullRequestClosed{}},
+		}
+		for _, tt := range tests {
+			// given
+			body := `{"object_kind":"merge_request","user":{"username":"alice"},` + gitlabProjectJSON +
+				`,"object_attributes":{"iid":4,"action":"` + tt.action + `"}}`
+
+			// when
+			event, err := parseGitLabDelivery(t, "Merge Request Hook", body)
+
+			// then
+			require.NoError(t, err, tt.action)
+			assert.IsType(t, tt.want, event, tt.action)
+		}
+	})
+
+	t.Run("should fill the pull request and metadata of an update", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"object_kind":"merge_request","user":{"username":"alice"},` + gitlabProjectJSON +
+			`,"object_attributes":{"iid":4,"title":"Draft: Refactor",` +
+			`"url":"https://gitlab.com/my-group/sub/my-repo/-/merge_requests/4",` +
+			`"state":"opened","action":"update","source_branch":"refactor","target_branch":"main","draft":true,` +
+			`"last_commit":{"id":"abc"}}}`
+
+		// when
+		event, err := parseGitLabDelivery(t, "Merge Request Hook", body)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, webhookEntities.PullRequestUpdated{
+			EventMetadata: webhookEntities.EventMetadata{
+				ServiceType: globalEntities.GITLAB,
+				Name:        "Merge Request Hook",
+				DeliveryID:  "uuid-1",
+				Repository:  wantRepository,
+				Sender:      "alice",
+			},
+			PullRequest: globalEntities.PullRequestDetail{
+				PullRequest: globalEntities.PullRequest{
+					ID:     4,
+					Title:  "Draft: Refactor",
+					URL:    "https://gitlab.com/my-group/sub/my-repo/-/merge_requests/4",
+					Status: "opened",
+				},
+				SourceBranch: "refactor",
+				TargetBranch: "main",
+				IsDraft:      true,
+			},
+			HeadSHA: "abc",
+		}, event)
+	})
+
+	t.Run("should return ErrUnsupported for approvals", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"object_kind":"merge_request",` + gitlabProjectJSON + `,"object_attributes":{"iid":4,"action":"approved"}}`
+
+		// when
+		_, err := parseGitLabDelivery(t, "Merge Request Hook", body)
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+
+	t.Run("should parse a diff note on a merge request", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"object_kind":"note","user":{"username":"bob"},` + gitlabProjectJSON +
+			`,"object_attributes":{"id":31,"note":"Typo","noteable_type":"MergeRequest",` +
+			`"position":{"new_path":"README.md","new_line":3}},"merge_request":{"iid":4,"source_branch":"refactor"}}`
+
+		// when
+		event, err := parseGitLabDelivery(t, "Note Hook", body)
+
+		// then
+		require.NoError(t, err)
+		require.IsType(t, webhookEntities.CommentCreated{}, event)
+		comment := event.(webhookEntities.CommentCreated)
+		assert.Equal(t, 4, comment.PullRequest.ID)
+		assert.Equal(t, globalEntities.PullRequestComment{
+			ID: 31, Body: "Typo", Author: "bob", FilePath: "README.md", Line: 3,
+		}, comment.Comment)
+	})
+
+	t.Run("should not report notes on issues", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		body := `{"object_kind":"note",` + gitlabProjectJSON + `,"object_attributes":{"id":31,"noteable_type":"Issue"}}`
+
+		// when
+		_, err := parseGitLabDelivery(t, "Note Hook", body)
+
+		// then
+		require.ErrorIs(t, err, errors.ErrUnsupported)
+	})
+
+	t.Run("should parse branch and tag pushes", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		push := `{"object_kind":"push","user_username":"alice","ref":"refs/heads/main","before":"aaa","after":"bbb",` +
+			gitlabProjectJSON + `}`
+		tag := `{"object_kind":"tag_push","user_username":"alice","ref":"refs/tags/v2",` +
+			`"before":"0000000000000000000000000000000000000000","after":"ddd","checkout_sha":"eee",` +
+			gitlabProjectJSON + `}`
+
+		// when
+		pushEvent, pushErr := parseGitLabDelivery(t, "Push Hook", push)
+		tagEvent, tagErr := parseGitLabDelivery(t, "Tag Push Hook", tag)
+
+		// then
+		require.NoError(t, pushErr)
+		require.NoError(t, tagErr)
+		assert.Equal(t, webhookEntities.Push{
+			EventMetadata: webhookEntities.EventMetadata{
+				ServiceType: globalEntities.GITLAB,
+				Name:        "Push Hook",
+				DeliveryID:  "uuid-1",
+				Repository:  wantRepository,
+				Sender:      "alice",
+			},
+			Branch: "main", Before: "aaa", After: "bbb",
+		}, pushEvent)
+		require.IsType(t, webhookEntities.TagCreated{}, tagEvent)
+		assert.Equal(t, "v2", tagEvent.(webhookEntities.TagCreated).Tag)
+		assert.Equal(t, "eee", tagEvent.(webhookEntities.TagCreated).CommitSHA)
+	})
+}
@@ -0,0 +1,176 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+)
+
+// maxPayloadSize caps the request bodies Parse reads. GitHub, the most
+// generous of the forges, truncates payloads at 25 MB.
+const maxPayloadSize = 25 << 20
+
+var (
+	// ErrInvalidSignature is returned when a delivery fails verification
+	// against the forge's secret.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+
+	// ErrMalformedPayload is returned when a delivery cannot be read or
+	// decoded.
+	ErrMalformedPayload = errors.New("malformed webhook payload")
+
+	// ErrSecretNotConfigured is returned when a delivery claims to come from
+	// a forge that has no secret and is not allowed unverified.
+	ErrSecretNotConfigured = errors.New("no webhook secret configured for forge")
+)
+
+// Secrets holds the secret each forge's webhooks were created with (see
+// WebhookInput.Secret). The forge is told from headers the sender controls,
+// so deliveries for a forge with an empty secret are rejected with
+// ErrSecretNotConfigured unless AllowUnverified opts that forge out of
+// verification.
+type Secrets struct {
+	GitHub      string // verified against the X-Hub-Signature-256 HMAC
+	GitLab      string // compared with the X-Gitlab-Token header
+	Forgejo     string // verified against the X-Forgejo-Signature (or X-Gitea-Signature) HMAC
+	AzureDevOps string // compared with the basic auth password
+
+	// AllowUnverified accepts any delivery claiming to come from a forge
+	// whose secret is empty. A forge with a secret is always verified.
+	AllowUnverified UnverifiedForges
+}
+
+// UnverifiedForges selects the forges whose deliveries are accepted without
+// a secret.
+//
+// Parse tells the forge only from request headers, checking the Forgejo ones
+// first, so allowing one forge unverified lets any sender reach it: with
+// Forgejo allowed, adding an X-Gitea-Event header to any body, even one shaped
+// like another forge's payload, gets it accepted without verification. Only
+// allow a forge unverified behind a network boundary that already
+// authenticates the sender.
+type UnverifiedForges struct {
+	GitHub      bool
+	GitLab      bool
+	Forgejo     bool
+	AzureDevOps bool
+}
+
+// Parser verifies webhook deliveries from GitHub, GitLab, Forgejo (and Gitea)
+// and Azure DevOps and normalizes them into webhookEntities events. The forge
+// is told apart by its event header; deliveries without one are taken for
+// Azure DevOps service hooks.
+type Parser struct {
+	secrets Secrets
+}
+
+// NewParser creates a Parser that verifies deliveries with secrets.
+func NewParser(secrets Secrets) *Parser {
+	return &Parser{secrets: secrets}
+}
+
+// Parse reads, verifies and decodes the delivery in r. Deliveries that fail
+// verification return ErrInvalidSignature, those for a forge without a secret
+// ErrSecretNotConfigured, unreadable ones ErrMalformedPayload. Events with no
+// typed counterpart, such as pings, issue comments or label changes, return
+// an error wrapping errors.ErrUnsupported; the delivery is genuine, so a
+// handler should still acknowledge it.
+func (p *Parser) Parse(r *http.Request) (webhookEntities.Event, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedPayload, err)
+	}
+	if len(body) > maxPayloadSize {
+		return nil, fmt.Errorf("%w: payload larger than %d bytes", ErrMalformedPayload, maxPayloadSize)
+	}
+
+	allow := p.secrets.AllowUnverified
+	switch {
+	case forgejoEventName(r.Header) != "":
+		err = verify("Forgejo", p.secrets.Forgejo, allow.Forgejo, func() error {
+			return verifyHMAC(p.secrets.Forgejo, forgejoSignature(r.Header), body)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return parseForgejo(r.Header, body)
+	case r.Header.Get(headerGitHubEvent) != "":
+		err = verify("GitHub", p.secrets.GitHub, allow.GitHub, func() error {
+			return verifyGitHubSignature(p.secrets.GitHub, r.Header.Get(headerGitHubSignature), body)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return parseGitHub(r.Header, body)
+	case r.Header.Get(headerGitLabEvent) != "":
+		err = verify("GitLab", p.secrets.GitLab, allow.GitLab, func() error {
+			return verifyToken(p.secrets.GitLab, r.Header.Get(headerGitLabToken))
+		})
+		if err != nil {
+			return nil, err
+		}
+		return parseGitLab(r.Header, body)
+	default:
+		err = verify("Azure DevOps", p.secrets.AzureDevOps, allow.AzureDevOps, func() error {
+			_, password, ok := r.BasicAuth()
+			if !ok {
+				return fmt.Errorf("%w: missing basic auth credentials", ErrInvalidSignature)
+			}
+			return verifyToken(p.secrets.AzureDevOps, password)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return parseAzureDevOps(body)
+	}
+}
+
+// verify runs check for a forge with a secret. A forge without one is
+// rejected with ErrSecretNotConfigured unless allowUnverified is set.
+func verify(forge, secret string, allowUnverified bool, check func() error) error {
+	if secret != "" {
+		return check()
+	}
+	if allowUnverified {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrSecretNotConfigured, forge)
+}
+
+// Handler returns an http.Handler that parses each delivery and passes the
+// event to handle. It answers 401 to deliveries failing verification or
+// claiming a forge without a secret, 400 to malformed ones, 500 when handle
+// fails and 204 otherwise, unsupported events included, so forges do not
+// retry or disable the webhook over them.
+func (p *Parser) Handler(handle func(ctx context.Context, event webhookEntities.Event) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		event, err := p.Parse(r)
+		switch {
+		case errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrSecretNotConfigured):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case errors.Is(err, errors.ErrUnsupported):
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if err = handle(r.Context(), event); err != nil {
+			http.Error(w, "failed to handle webhook event", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
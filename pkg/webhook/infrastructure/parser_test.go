@@ -0,0 +1,334 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+	webhookEntities "github.com/rios0rios0/gitforge/pkg/webhook/domain/entities"
+	webhookInfra "github.com/rios0rios0/gitforge/pkg/webhook/infrastructure"
+)
+
+const testSecret = "s3cret"
+
+// newDelivery builds a webhook delivery with the given headers.
+func newDelivery(body string, headers map[string]string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	return r
+}
+
+// unverified returns Secrets accepting every forge's deliveries without
+// verification, for tests about payload normalization.
+func unverified() webhookInfra.Secrets {
+	return webhookInfra.Secrets{AllowUnverified: webhookInfra.UnverifiedForges{
+		GitHub: true, GitLab: true, Forgejo: true, AzureDevOps: true,
+	}}
+}
+
+// sign returns the hex HMAC-SHA256 of body keyed with testSecret.
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestParserVerification(t *testing.T) {
+	t.Parallel()
+
+	body := `{"ref":"refs/heads/main","before":"a1","after":"b2"}`
+	secrets := webhookInfra.Secrets{
+		GitHub: testSecret, GitLab: testSecret, Forgejo: testSecret, AzureDevOps: testSecret,
+	}
+
+	t.Run("should accept a GitHub delivery with a valid signature", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		r := newDelivery(body, map[string]string{
+			"X-GitHub-Event":      "push",
+			"X-Hub-Signature-256": "sha256=" + sign(body),
+		})
+
+		// when
+		event, err := webhookInfra.NewParser(secrets).Parse(r)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.GITHUB, event.Metadata().ServiceType)
+	})
+
+	t.Run("should reject a GitHub delivery signed with another secret", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		r := newDelivery(body, map[string]string{
+			"X-GitHub-Event":      "push",
+			"X-Hub-Signature-256": "sha256=" + sign(body+"tampered"),
+		})
+
+		// when
+		_, err := webhookInfra.NewParser(secrets).Parse(r)
+
+		// then
+		require.ErrorIs(t, err, webhookInfra.ErrInvalidSignature)
+	})
+
+	t.Run("should reject an unsigned GitHub delivery", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		r := newDelivery(body, map[string]string{"X-GitHub-Event": "push"})
+
+		// when
+		_, err := webhookInfra.NewParser(secrets).Parse(r)
+
+		// then
+		require.ErrorIs(t, err, webhookInfra.ErrInvalidSignature)
+	})
+
+	t.Run("should skip verification for a forge allowed unverified", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		r := newDelivery(body, map[string]string{"X-GitHub-Event": "push"})
+		parser := webhookInfra.NewParser(webhookInfra.Secrets{
+			AllowUnverified: webhookInfra.UnverifiedForges{GitHub: true},
+		})
+
+		// when
+		_, err := parser.Parse(r)
+
+		// then
+		require.NoError(t, err)
+	})
+
+	t.Run("should verify a forge with a secret even when allowed unverified", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		r := newDelivery(body, map[string]string{"X-GitHub-Event": "push"})
+		parser := webhookInfra.NewParser(webhookInfra.Secrets{
+			GitHub:          testSecret,
+			AllowUnverified: webhookInfra.UnverifiedForges{GitHub: true},
+		})
+
+		// when
+		_, err := parser.Parse(r)
+
+		// then
+		require.ErrorIs(t, err, webhookInfra.ErrInvalidSignature)
+	})
+
+	t.Run("should reject forged headers of a forge without a secret", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			body    string
+			headers map[string]string
+		}{
+			{
+				name:    "GitLab",
+				body:    `{"object_kind":"push","ref":"refs/heads/main"}`,
+				headers: map[string]string{"X-Gitlab-Event": "Push Hook"},
+			},
+			{
+				name:    "Forgejo",
+				body:    body,
+				headers: map[string]string{"X-Forgejo-Event": "push", "X-Forgejo-Signature": "deadbeef"},
+			},
+			{
+				name: "Azure DevOps, taken for deliveries without an event header",
+				body: `{"eventType":"git.push","resource":{"refUpdates":[{"name":"refs/heads/main"}]}}`,
+			},
+		}
+		for _, tt := range tests {
+			// given
+			parser := webhookInfra.NewParser(webhookInfra.Secrets{GitHub: testSecret})
+
+			// when
+			_, err := parser.Parse(newDelivery(tt.body, tt.headers))
+
+			// then
+			require.ErrorIs(t, err, webhookInfra.ErrSecretNotConfigured, tt.name)
+			assert.NotErrorIs(t, err, webhookInfra.ErrInvalidSignature, tt.name)
+		}
+	})
+
+	t.Run("should compare the GitLab token", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		valid := newDelivery(`{"object_kind":"push","ref":"refs/heads/main"}`, map[string]string{
+			"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": testSecret,
+		})
+		invalid := newDelivery(`{"object_kind":"push","ref":"refs/heads/main"}`, map[string]string{
+			"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": "wrong",
+		})
+		parser := webhookInfra.NewParser(secrets)
+
+		// when
+		_, validErr := parser.Parse(valid)
+		_, invalidErr := parser.Parse(invalid)
+
+		// then
+		require.NoError(t, validErr)
+		require.ErrorIs(t, invalidErr, webhookInfra.ErrInvalidSignature)
+	})
+
+	t.Run("should verify the Forgejo signature before the GitHub headers Gitea also sends", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		r := newDelivery(body, map[string]string{
+			"X-Gitea-Event":       "push",
+			"X-Gitea-Signature":   sign(body),
+			"X-GitHub-Event":      "push",
+			"X-Hub-Signature-256": "sha256=deadbeef",
+		})
+
+		// when
+		event, err := webhookInfra.NewParser(secrets).Parse(r)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, globalEntities.GITEA, event.Metadata().ServiceType)
+	})
+
+	t.Run("should check the Azure DevOps basic auth password", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		adoBody := `{"eventType":"git.push","resource":{"refUpdates":[{"name":"refs/heads/main"}]}}`
+		valid := newDelivery(adoBody, nil)
+		valid.SetBasicAuth("gitforge", testSecret)
+		invalid := newDelivery(adoBody, nil)
+		invalid.SetBasicAuth("gitforge", "wrong")
+		missing := newDelivery(adoBody, nil)
+		parser := webhookInfra.NewParser(secrets)
+
+		// when
+		_, validErr := parser.Parse(valid)
+		_, invalidErr := parser.Parse(invalid)
+		_, missingErr := parser.Parse(missing)
+
+		// then
+		require.NoError(t, validErr)
+		require.ErrorIs(t, invalidErr, webhookInfra.ErrInvalidSignature)
+		require.ErrorIs(t, missingErr, webhookInfra.ErrInvalidSignature)
+	})
+
+	t.Run("should return ErrMalformedPayload for a body that is not JSON", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		r := newDelivery("not json", map[string]string{"X-GitHub-Event": "push"})
+
+		// when
+		_, err := webhookInfra.NewParser(unverified()).Parse(r)
+
+		// then
+		require.ErrorIs(t, err, webhookInfra.ErrMalformedPayload)
+	})
+}
+
+func TestParserHandler(t *testing.T) {
+	t.Parallel()
+
+	pushBody := `{"ref":"refs/heads/main","before":"a1","after":"b2"}`
+
+	t.Run("should pass the event to the handler and answer 204", func(t *testing.T) {
+		t.Parallel()
+
+		// given
+		var got webhookEntities.Event
+		handler := webhookInfra.NewParser(unverified()).Handler(
+			func(_ context.Context, event webhookEntities.Event) error {
+				got = event
+				return nil
+			},
+		)
+		w := httptest.NewRecorder()
+
+		// when
+		handler.ServeHTTP(w, newDelivery(pushBody, map[string]string{"X-GitHub-Event": "push"}))
+
+		// then
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.IsType(t, webhookEntities.Push{}, got)
+	})
+
+	t.Run("should answer with the status matching the outcome", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name       string
+			method     string
+			body       string
+			headers    map[string]string
+			secrets    webhookInfra.Secrets
+			handleErr  error
+			wantStatus int
+		}{
+			{
+				name: "non-POST", method: http.MethodGet, body: pushBody,
+				headers: map[string]string{"X-GitHub-Event": "push"}, wantStatus: http.StatusMethodNotAllowed,
+			},
+			{
+				name: "invalid signature", method: http.MethodPost, body: pushBody,
+				headers:    map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=00"},
+				secrets:    webhookInfra.Secrets{GitHub: testSecret},
+				wantStatus: http.StatusUnauthorized,
+			},
+			{
+				name: "forge without a secret", method: http.MethodPost, body: pushBody,
+				headers:    map[string]string{"X-Gitlab-Event": "Push Hook"},
+				secrets:    webhookInfra.Secrets{GitHub: testSecret},
+				wantStatus: http.StatusUnauthorized,
+			},
+			{
+				name: "unsupported event", method: http.MethodPost, body: `{"zen":"Keep it logically awesome."}`,
+				headers: map[string]string{"X-GitHub-Event": "ping"}, secrets: unverified(),
+				wantStatus: http.StatusNoContent,
+			},
+			{
+				name: "malformed payload", method: http.MethodPost, body: "{",
+				headers: map[string]string{"X-Gitlab-Event": "Push Hook"}, secrets: unverified(),
+				wantStatus: http.StatusBadRequest,
+			},
+			{
+				name: "handler failure", method: http.MethodPost, body: pushBody,
+				headers: map[string]string{"X-GitHub-Event": "push"}, secrets: unverified(),
+				handleErr: errors.New("boom"), wantStatus: http.StatusInternalServerError,
+			},
+		}
+		for _, tt := range tests {
+			// given
+			handler := webhookInfra.NewParser(tt.secrets).Handler(
+				func(context.Context, webhookEntities.Event) error { return tt.handleErr },
+			)
+			r := newDelivery(tt.body, tt.headers)
+			r.Method = tt.method
+			w := httptest.NewRecorder()
+
+			// when
+			handler.ServeHTTP(w, r)
+
+			// then
+			assert.Equal(t, tt.wantStatus, w.Code, tt.name)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// verifyGitHubSignature checks an X-Hub-Signature-256 header, the hex
+// HMAC-SHA256 of the body prefixed with "sha256=".
+func verifyGitHubSignature(secret, header string, body []byte) error {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return fmt.Errorf("%w: missing sha256 signature", ErrInvalidSignature)
+	}
+	return verifyHMAC(secret, signature, body)
+}
+
+// verifyHMAC checks that signature is the hex HMAC-SHA256 of body keyed with
+// secret.
+func verifyHMAC(secret, signature string, body []byte) error {
+	if signature == "" {
+		return fmt.Errorf("%w: missing signature", ErrInvalidSignature)
+	}
+
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not hex", ErrInvalidSignature)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+	return nil
+}
+
+// verifyToken compares a secret sent in the clear in constant time.
+func verifyToken(secret, got string) error {
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(got)) != 1 {
+		return fmt.Errorf("%w: token mismatch", ErrInvalidSignature)
+	}
+	return nil
+}
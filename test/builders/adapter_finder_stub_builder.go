@@ -12,6 +12,7 @@ type AdapterFinderStubBuilder struct {
 
 	adapterByServiceType globalEntities.LocalGitAuthProvider
 	adapterByURL         globalEntities.LocalGitAuthProvider
+	adapters             []globalEntities.LocalGitAuthProvider
 }
 
 // NewAdapterFinderStubBuilder creates a new builder with default values.
@@ -33,9 +34,18 @@ func (b *AdapterFinderStubBuilder) WithAdapterByURL(
 	return b
 }
 
+// WithAdapters registers adapters looked up by URL and service type, in order.
+func (b *AdapterFinderStubBuilder) WithAdapters(
+	adapters ...globalEntities.LocalGitAuthProvider,
+) *AdapterFinderStubBuilder {
+	b.adapters = adapters
+	return b
+}
+
 func (b *AdapterFinderStubBuilder) Build() any {
 	return &doubles.AdapterFinderStub{
 		AdapterByServiceTypeValue: b.adapterByServiceType,
 		AdapterByURLValue:         b.adapterByURL,
+		Adapters:                  b.adapters,
 	}
 }
@@ -0,0 +1,161 @@
+package conformance
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// RunFileAccessProvider checks the invariants of FileAccessProvider. The
+// provider built by the harness must implement it.
+func RunFileAccessProvider(t *testing.T, h Harness) {
+	t.Helper()
+	const group = "FileAccessProvider"
+
+	seedFiles := map[string]string{
+		"README.md":       "# files\n",
+		"go.mod":          "module example.com/files\n",
+		"cmd/app/main.go": "package main\n",
+	}
+
+	h.check(t, group, "should read files from the default branch", func(t *testing.T, f fixture) {
+		// given
+		provider := fileAccess(t, f)
+		ctx := context.Background()
+		repo := f.backend.SeedRepository(t, f.org, "files", seedFiles)
+
+		// when
+		content, contentErr := provider.GetFileContent(ctx, repo, "go.mod")
+		_, missingErr := provider.GetFileContent(ctx, repo, "missing.txt")
+
+		// then
+		require.NoError(t, contentErr)
+		assert.Equal(t, seedFiles["go.mod"], content)
+		require.Error(t, missingErr, "GetFileContent must fail for a missing file")
+		assert.True(t, provider.HasFile(ctx, repo, "cmd/app/main.go"))
+		assert.False(t, provider.HasFile(ctx, repo, "missing.txt"))
+	})
+
+	h.check(t, group, "should list files filtered by path suffix", func(t *testing.T, f fixture) {
+		// given
+		provider := fileAccess(t, f)
+		ctx := context.Background()
+		repo := f.backend.SeedRepository(t, f.org, "files", seedFiles)
+
+		// when
+		all, allErr := provider.ListFiles(ctx, repo, "")
+		goFiles, goErr := provider.ListFiles(ctx, repo, ".go")
+
+		// then
+		require.NoError(t, allErr)
+		assert.ElementsMatch(t, []string{"README.md", "go.mod", "cmd/app/main.go"}, filePaths(all))
+		require.NoError(t, goErr)
+		assert.Equal(t, []string{"cmd/app/main.go"}, filePaths(goFiles))
+	})
+
+	h.check(t, group, "should list tags by semantic version descending", func(t *testing.T, f fixture) {
+		// given
+		provider := fileAccess(t, f)
+		repo := f.backend.SeedRepository(t, f.org, "tags", seedFiles, "v1.0.0", "v1.10.0", "v1.2.0")
+
+		// when
+		tags, err := provider.GetTags(context.Background(), repo)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v1.10.0", "v1.2.0", "v1.0.0"}, tags)
+	})
+
+	h.check(t, group, "should create a branch with added and edited files", func(t *testing.T, f fixture) {
+		// given
+		provider := fileAccess(t, f)
+		repo := f.backend.SeedRepository(t, f.org, "branches", seedFiles)
+
+		// when
+		err := provider.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName:    "chore/update",
+			BaseBranch:    defaultBranch,
+			CommitMessage: "chore: update files",
+			Changes: []globalEntities.FileChange{
+				{Path: "go.mod", Content: "module example.com/files\n\ngo 1.27\n", ChangeType: "edit"},
+				{Path: "docs/notes.md", Content: "notes\n", ChangeType: "add"},
+			},
+		})
+
+		// then
+		require.NoError(t, err)
+		assertFile(t, f, repo, "chore/update", "go.mod", "module example.com/files\n\ngo 1.27\n")
+		assertFile(t, f, repo, "chore/update", "docs/notes.md", "notes\n")
+		assertFile(t, f, repo, "chore/update", "README.md", seedFiles["README.md"])
+		assertFile(t, f, repo, defaultBranch, "go.mod", seedFiles["go.mod"])
+	})
+
+	h.check(t, group, "should delete files on a new branch", func(t *testing.T, f fixture) {
+		// given
+		provider := fileAccess(t, f)
+		repo := f.backend.SeedRepository(t, f.org, "deletes", seedFiles)
+
+		// when
+		err := provider.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName:    "chore/delete",
+			BaseBranch:    defaultBranch,
+			CommitMessage: "chore: delete readme",
+			Changes:       []globalEntities.FileChange{{Path: "README.md", ChangeType: "delete"}},
+		})
+
+		// then
+		require.NoError(t, err)
+		_, exists := f.backend.FileContent(t, repo, "chore/delete", "README.md")
+		assert.False(t, exists, "README.md must be deleted on the new branch")
+		assertFile(t, f, repo, defaultBranch, "README.md", seedFiles["README.md"])
+	})
+
+	h.check(t, group, "should reject unsupported change types", func(t *testing.T, f fixture) {
+		// given
+		provider := fileAccess(t, f)
+		repo := f.backend.SeedRepository(t, f.org, "rejects", seedFiles)
+
+		// when
+		err := provider.CreateBranchWithChanges(context.Background(), repo, globalEntities.BranchInput{
+			BranchName:    "chore/rename",
+			BaseBranch:    defaultBranch,
+			CommitMessage: "chore: rename",
+			Changes:       []globalEntities.FileChange{{Path: "go.mod", ChangeType: "rename"}},
+		})
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unsupported change type "rename"`)
+	})
+}
+
+func fileAccess(t *testing.T, f fixture) globalEntities.FileAccessProvider {
+	t.Helper()
+	provider, ok := f.provider.(globalEntities.FileAccessProvider)
+	require.True(t, ok, "%s does not implement FileAccessProvider", f.provider.Name())
+	return provider
+}
+
+// filePaths returns the paths of the non-directory entries, without a leading slash.
+func filePaths(files []globalEntities.File) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		if !file.IsDir {
+			paths = append(paths, strings.TrimPrefix(file.Path, "/"))
+		}
+	}
+	return paths
+}
+
+// assertFile checks the content of path on branch through the backend.
+func assertFile(t *testing.T, f fixture, repo globalEntities.Repository, branch, path, want string) {
+	t.Helper()
+	content, exists := f.backend.FileContent(t, repo, branch, path)
+	require.True(t, exists, "%s must exist on %s", path, branch)
+	assert.Equal(t, want, content, "content of %s on %s", path, branch)
+}
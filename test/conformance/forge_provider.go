@@ -0,0 +1,145 @@
+package conformance
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// foreignURL belongs to no provider: the .invalid TLD is reserved.
+const foreignURL = "https://forge.invalid/someone/something.git"
+
+// RunForgeProvider checks the invariants of ForgeProvider.
+func RunForgeProvider(t *testing.T, h Harness) {
+	t.Helper()
+	const group = "ForgeProvider"
+
+	h.check(t, group, "should report a stable non-empty name", func(t *testing.T, f fixture) {
+		// given / when
+		name := f.provider.Name()
+
+		// then
+		assert.NotEmpty(t, name)
+		assert.Equal(t, name, f.provider.Name())
+	})
+
+	h.check(t, group, "should match its own clone URLs and reject foreign ones", func(t *testing.T, f fixture) {
+		// given
+		repo := f.backend.SeedRepository(t, f.org, "matched", map[string]string{"README.md": "# matched\n"})
+
+		// when
+		own := f.provider.MatchesURL(f.provider.CloneURL(repo))
+		foreign := f.provider.MatchesURL(foreignURL)
+
+		// then
+		assert.True(t, own, "MatchesURL(CloneURL(repo)) must be true")
+		assert.False(t, foreign, "MatchesURL must reject %s", foreignURL)
+	})
+
+	h.check(t, group, "should not suffix the SSH host without an alias", func(t *testing.T, f fixture) {
+		// given
+		repo := f.backend.SeedRepository(t, f.org, "ssh", map[string]string{"README.md": "# ssh\n"})
+
+		// when
+		sshURL := f.provider.SSHCloneURL(repo, "")
+
+		// then
+		require.NotEmpty(t, sshURL)
+		assert.False(t, strings.HasSuffix(sshHost(sshURL), "-"), "SSH host of %q ends with a dash", sshURL)
+	})
+
+	h.check(t, group, "should discover seeded repositories", func(t *testing.T, f fixture) {
+		// given
+		f.backend.SeedRepository(t, f.org, "first", map[string]string{"README.md": "# first\n"})
+		f.backend.SeedRepository(t, f.org, "second", map[string]string{"README.md": "# second\n"})
+
+		// when
+		repos, err := f.provider.DiscoverRepositories(context.Background(), f.org)
+
+		// then
+		require.NoError(t, err)
+		byName := make(map[string]globalEntities.Repository, len(repos))
+		for _, repo := range repos {
+			byName[repo.Name] = repo
+		}
+		for _, name := range []string{"first", "second"} {
+			repo, ok := byName[name]
+			require.True(t, ok, "repository %q was not discovered", name)
+			assert.Equal(t, f.org, repo.Organization)
+			assert.Equal(t, f.provider.Name(), repo.ProviderName)
+			assert.NotEmpty(t, repo.DefaultBranch)
+		}
+	})
+
+	h.check(t, group, "should report an open pull request until it is closed", func(t *testing.T, f fixture) {
+		// given
+		ctx := context.Background()
+		repo := f.backend.SeedRepository(t, f.org, "pulls", map[string]string{"README.md": "# pulls\n"})
+		f.backend.CreateBranch(t, repo, "feature/close", map[string]string{"README.md": "# closed\n"})
+
+		// when
+		pr, createErr := f.provider.CreatePullRequest(ctx, repo, globalEntities.PullRequestInput{
+			SourceBranch: "refs/heads/feature/close",
+			TargetBranch: "refs/heads/" + defaultBranch,
+			Title:        "conformance: close",
+		})
+		existsOpen, openErr := f.provider.PullRequestExists(ctx, repo, "feature/close")
+		closed, closeErr := f.provider.ClosePullRequest(ctx, repo, "feature/close")
+		existsClosed, closedErr := f.provider.PullRequestExists(ctx, repo, "feature/close")
+		closedAgain, againErr := f.provider.ClosePullRequest(ctx, repo, "feature/close")
+
+		// then
+		require.NoError(t, createErr)
+		require.NotNil(t, pr)
+		assert.Positive(t, pr.ID)
+		require.NoError(t, openErr)
+		assert.True(t, existsOpen, "PullRequestExists must see the open pull request")
+		require.NoError(t, closeErr)
+		assert.True(t, closed, "ClosePullRequest must report the pull request it closed")
+		require.NoError(t, closedErr)
+		assert.False(t, existsClosed, "PullRequestExists must ignore closed pull requests")
+		require.NoError(t, againErr)
+		assert.False(t, closedAgain, "ClosePullRequest must be a no-op without an open pull request")
+		assert.True(t, f.backend.BranchExists(t, repo, "feature/close"), "ClosePullRequest must keep the source branch")
+	})
+
+	h.check(t, group, "should find no pull request for a branch without one", func(t *testing.T, f fixture) {
+		// given
+		ctx := context.Background()
+		repo := f.backend.SeedRepository(t, f.org, "no-pulls", map[string]string{"README.md": "# none\n"})
+
+		// when
+		exists, existsErr := f.provider.PullRequestExists(ctx, repo, "feature/none")
+		closed, closeErr := f.provider.ClosePullRequest(ctx, repo, "feature/none")
+
+		// then
+		require.NoError(t, existsErr)
+		assert.False(t, exists)
+		require.NoError(t, closeErr)
+		assert.False(t, closed)
+	})
+}
+
+// sshHost extracts the host of an ssh:// URL or an scp-like "user@host:path"
+// address. Other URLs, such as file:// ones, yield an empty host.
+func sshHost(rawURL string) string {
+	if strings.Contains(rawURL, "://") {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || parsed.Scheme != "ssh" {
+			return ""
+		}
+		return parsed.Hostname()
+	}
+
+	host, _, _ := strings.Cut(rawURL, ":")
+	if _, after, found := strings.Cut(host, "@"); found {
+		host = after
+	}
+	return host
+}
@@ -0,0 +1,152 @@
+// Package conformance is an exported contract test suite for gitforge
+// providers. It drives any ForgeProvider, FileAccessProvider, ReviewProvider,
+// MirrorProvider, or MirrorLifecycleProvider against a fake backend and
+// checks the invariants documented on those interfaces, so built-in, new, and
+// third-party providers can prove they behave the same way.
+//
+// A provider opts in from its own tests:
+//
+//	conformance.Run(t, conformance.Harness{
+//		NewBackend:  func(t *testing.T) conformance.Backend { return newFakeServer(t) },
+//		NewProvider: func(t *testing.T, b conformance.Backend) globalEntities.ForgeProvider { ... },
+//	})
+package conformance
+
+import (
+	"strings"
+	"testing"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	// DefaultOrganization is the organization seeded repositories live in when
+	// Harness.Organization is empty.
+	DefaultOrganization = "conformance"
+
+	// DefaultMergeStrategy is the strategy passed to MergePullRequest when
+	// Harness.MergeStrategy is empty.
+	DefaultMergeStrategy = "squash"
+
+	// defaultBranch is the branch every seeded repository starts with.
+	defaultBranch = "main"
+)
+
+// Backend is the fake server or on-disk state behind the provider under test.
+// The suite seeds it and reads it back as the source of truth; it never
+// reaches the backend through the provider. Each test gets a fresh Backend.
+type Backend interface {
+	// SeedRepository creates repository name in org with files committed on
+	// its "main" default branch and the given lightweight tags pointing at
+	// that commit. It returns the repository the way the provider's
+	// DiscoverRepositories reports it.
+	SeedRepository(
+		t *testing.T, org, name string, files map[string]string, tags ...string,
+	) globalEntities.Repository
+
+	// CreateBranch creates branch from the head of "main" with files written
+	// in one commit on top of it.
+	CreateBranch(t *testing.T, repo globalEntities.Repository, branch string, files map[string]string)
+
+	// FileContent returns the content of path on branch and whether it exists.
+	FileContent(t *testing.T, repo globalEntities.Repository, branch, path string) (string, bool)
+
+	// BranchExists reports whether branch exists in the repository.
+	BranchExists(t *testing.T, repo globalEntities.Repository, branch string) bool
+}
+
+// Harness connects the suite to one provider implementation.
+type Harness struct {
+	// NewBackend creates a fresh, empty backend for one test. Required.
+	NewBackend func(t *testing.T) Backend
+
+	// NewProvider creates the provider under test, talking to backend. Required.
+	NewProvider func(t *testing.T, backend Backend) globalEntities.ForgeProvider
+
+	// Organization is the organization repositories are seeded in. Defaults
+	// to DefaultOrganization.
+	Organization string
+
+	// MergeStrategy is passed to MergePullRequest. Defaults to DefaultMergeStrategy.
+	MergeStrategy string
+
+	// Skip maps a check, named "<Interface>/<test name>" (for example
+	// "FileAccessProvider/should delete files on a new branch"), to the reason
+	// the provider documents it as unsupported. Skipped checks are reported
+	// with t.Skip rather than silently dropped.
+	Skip map[string]string
+}
+
+// Run runs every check for each interface the provider implements; checks for
+// interfaces it does not implement are skipped.
+func Run(t *testing.T, h Harness) {
+	t.Helper()
+
+	provider := h.NewProvider(t, h.NewBackend(t))
+
+	t.Run("ForgeProvider", func(t *testing.T) { RunForgeProvider(t, h) })
+	t.Run("FileAccessProvider", func(t *testing.T) {
+		if _, ok := provider.(globalEntities.FileAccessProvider); !ok {
+			t.Skipf("%s does not implement FileAccessProvider", provider.Name())
+		}
+		RunFileAccessProvider(t, h)
+	})
+	t.Run("ReviewProvider", func(t *testing.T) {
+		if _, ok := provider.(globalEntities.ReviewProvider); !ok {
+			t.Skipf("%s does not implement ReviewProvider", provider.Name())
+		}
+		RunReviewProvider(t, h)
+	})
+	t.Run("MirrorProvider", func(t *testing.T) {
+		if _, ok := provider.(globalEntities.MirrorProvider); !ok {
+			t.Skipf("%s does not implement MirrorProvider", provider.Name())
+		}
+		RunMirrorProvider(t, h)
+	})
+	t.Run("MirrorLifecycleProvider", func(t *testing.T) {
+		if _, ok := provider.(globalEntities.MirrorLifecycleProvider); !ok {
+			t.Skipf("%s does not implement MirrorLifecycleProvider", provider.Name())
+		}
+		RunMirrorLifecycleProvider(t, h)
+	})
+}
+
+// fixture is the per-test state handed to every check.
+type fixture struct {
+	backend  Backend
+	provider globalEntities.ForgeProvider
+	org      string
+}
+
+// check runs fn as the subtest name of group, in parallel with its siblings
+// and against a fresh backend, unless the harness skips it.
+func (h Harness) check(t *testing.T, group, name string, fn func(t *testing.T, f fixture)) {
+	t.Helper()
+
+	t.Run(name, func(t *testing.T) {
+		if reason, ok := h.Skip[group+"/"+name]; ok {
+			t.Skip(reason)
+		}
+		t.Parallel()
+
+		backend := h.NewBackend(t)
+		org := h.Organization
+		if org == "" {
+			org = DefaultOrganization
+		}
+		fn(t, fixture{backend: backend, provider: h.NewProvider(t, backend), org: org})
+	})
+}
+
+// mergeStrategy returns the configured merge strategy or its default.
+func (h Harness) mergeStrategy() string {
+	if h.MergeStrategy == "" {
+		return DefaultMergeStrategy
+	}
+	return h.MergeStrategy
+}
+
+// shortBranch strips the "refs/heads/" prefix some providers report.
+func shortBranch(branch string) string {
+	return strings.TrimPrefix(branch, "refs/heads/")
+}
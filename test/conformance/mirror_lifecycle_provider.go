@@ -0,0 +1,130 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// syncTimeout bounds how long the suite waits for an asynchronous mirror sync.
+const syncTimeout = 5 * time.Second
+
+// RunMirrorLifecycleProvider checks the invariants of MirrorLifecycleProvider.
+// The provider built by the harness must implement it.
+func RunMirrorLifecycleProvider(t *testing.T, h Harness) {
+	t.Helper()
+	const group = "MirrorLifecycleProvider"
+
+	h.check(t, group, "should list pull mirrors but not plain copies", func(t *testing.T, f fixture) {
+		// given
+		provider, source := seedMirror(t, f)
+		require.NoError(t, provider.MigrateRepository(context.Background(), globalEntities.MirrorInput{
+			CloneAddr: f.provider.CloneURL(source),
+			RepoName:  "copy",
+			RepoOwner: f.org,
+		}))
+
+		// when
+		mirrors, err := provider.ListMirrors(context.Background(), f.org)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, mirrors, 1)
+		assert.Equal(t, "downstream", mirrors[0].Repository.Name)
+		assert.Contains(t, mirrors[0].SourceURL, "upstream")
+		assert.NotContains(t, mirrors[0].SourceURL, "@", "source URL must not carry credentials")
+	})
+
+	h.check(t, group, "should pull new commits when synced", func(t *testing.T, f fixture) {
+		// given
+		provider, source := seedMirror(t, f)
+		mirror := findMirror(t, provider, f.org, "downstream")
+		f.backend.CreateBranch(t, source, "feature", map[string]string{"feature.txt": "feature\n"})
+
+		// when
+		err := provider.SyncMirror(context.Background(), mirror.Repository)
+
+		// then
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return f.backend.BranchExists(t, mirror.Repository, "feature")
+		}, syncTimeout, 10*time.Millisecond)
+	})
+
+	h.check(t, group, "should change the sync interval", func(t *testing.T, f fixture) {
+		// given
+		provider, _ := seedMirror(t, f)
+		mirror := findMirror(t, provider, f.org, "downstream")
+
+		// when
+		err := provider.SetMirrorInterval(context.Background(), mirror.Repository, time.Hour)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, findMirror(t, provider, f.org, "downstream").Interval)
+	})
+
+	h.check(t, group, "should convert a mirror into a normal repository", func(t *testing.T, f fixture) {
+		// given
+		provider, _ := seedMirror(t, f)
+		mirror := findMirror(t, provider, f.org, "downstream")
+
+		// when
+		err := provider.ConvertMirror(context.Background(), mirror.Repository)
+
+		// then
+		require.NoError(t, err)
+		mirrors, listErr := provider.ListMirrors(context.Background(), f.org)
+		require.NoError(t, listErr)
+		assert.Empty(t, mirrors)
+		repos, discoverErr := provider.DiscoverRepositories(context.Background(), f.org)
+		require.NoError(t, discoverErr)
+		names := make([]string, 0, len(repos))
+		for _, repo := range repos {
+			names = append(names, repo.Name)
+		}
+		assert.Contains(t, names, "downstream")
+	})
+}
+
+// seedMirror seeds repository "upstream" and creates pull mirror "downstream"
+// of it, returning the provider under test and the source repository.
+func seedMirror(
+	t *testing.T, f fixture,
+) (globalEntities.MirrorLifecycleProvider, globalEntities.Repository) {
+	t.Helper()
+
+	provider, ok := f.provider.(globalEntities.MirrorLifecycleProvider)
+	require.True(t, ok, "%s does not implement MirrorLifecycleProvider", f.provider.Name())
+	source := f.backend.SeedRepository(t, f.org, "upstream", map[string]string{"README.md": "# upstream\n"})
+	require.NoError(t, provider.MigrateRepository(context.Background(), globalEntities.MirrorInput{
+		CloneAddr: f.provider.CloneURL(source),
+		RepoName:  "downstream",
+		RepoOwner: f.org,
+		Mirror:    true,
+	}))
+	return provider, source
+}
+
+// findMirror returns the mirror called name in org, failing the test when it
+// is not listed.
+func findMirror(
+	t *testing.T, provider globalEntities.MirrorLifecycleProvider, org, name string,
+) globalEntities.Mirror {
+	t.Helper()
+
+	mirrors, err := provider.ListMirrors(context.Background(), org)
+	require.NoError(t, err)
+	for _, mirror := range mirrors {
+		if mirror.Repository.Name == name {
+			return mirror
+		}
+	}
+	require.Failf(t, "mirror not listed", "%q is not among the mirrors of %q", name, org)
+	return globalEntities.Mirror{}
+}
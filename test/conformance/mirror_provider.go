@@ -0,0 +1,51 @@
+package conformance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// RunMirrorProvider checks the invariants of MirrorProvider. The provider built
+// by the harness must implement it.
+func RunMirrorProvider(t *testing.T, h Harness) {
+	t.Helper()
+	const group = "MirrorProvider"
+
+	for _, mirror := range []bool{false, true} {
+		name := "should migrate a repository that is then discoverable"
+		if mirror {
+			name = "should create a pull mirror that is then discoverable"
+		}
+
+		h.check(t, group, name, func(t *testing.T, f fixture) {
+			// given
+			provider, ok := f.provider.(globalEntities.MirrorProvider)
+			require.True(t, ok, "%s does not implement MirrorProvider", f.provider.Name())
+			source := f.backend.SeedRepository(t, f.org, "upstream", map[string]string{"README.md": "# upstream\n"})
+
+			// when
+			err := provider.MigrateRepository(context.Background(), globalEntities.MirrorInput{
+				CloneAddr:   f.provider.CloneURL(source),
+				RepoName:    "downstream",
+				RepoOwner:   f.org,
+				Description: "conformance mirror",
+				Mirror:      mirror,
+			})
+
+			// then
+			require.NoError(t, err)
+			repos, discoverErr := provider.DiscoverRepositories(context.Background(), f.org)
+			require.NoError(t, discoverErr)
+			names := make([]string, 0, len(repos))
+			for _, repo := range repos {
+				names = append(names, repo.Name)
+			}
+			assert.Contains(t, names, "downstream")
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package conformance
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// RunReviewProvider checks the invariants of ReviewProvider. The provider built
+// by the harness must implement it.
+func RunReviewProvider(t *testing.T, h Harness) {
+	t.Helper()
+	const group = "ReviewProvider"
+
+	h.check(t, group, "should list open pull requests", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+
+		// when
+		open, err := provider.ListOpenPullRequests(context.Background(), repo)
+
+		// then
+		require.NoError(t, err)
+		var found *globalEntities.PullRequestDetail
+		for i := range open {
+			if open[i].ID == pr.ID {
+				found = &open[i]
+			}
+		}
+		require.NotNil(t, found, "pull request #%d is not listed as open", pr.ID)
+		assert.Equal(t, "feature/review", shortBranch(found.SourceBranch))
+		assert.Equal(t, defaultBranch, shortBranch(found.TargetBranch))
+		assert.Equal(t, "conformance: review", found.Title)
+	})
+
+	h.check(t, group, "should expose the diff and changed files", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+		ctx := context.Background()
+
+		// when
+		diff, diffErr := provider.GetPullRequestDiff(ctx, repo, pr.ID)
+		files, filesErr := provider.GetPullRequestFiles(ctx, repo, pr.ID)
+
+		// then
+		require.NoError(t, diffErr)
+		assert.Contains(t, diff, "README.md")
+		assert.Contains(t, diff, "added.txt")
+		require.NoError(t, filesErr)
+		statuses := make(map[string]string, len(files))
+		for _, file := range files {
+			statuses[strings.TrimPrefix(file.Path, "/")] = file.Status
+		}
+		assert.Equal(t, map[string]string{"README.md": "modified", "added.txt": "added"}, statuses)
+	})
+
+	h.check(t, group, "should list posted pull request comments", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+		ctx := context.Background()
+
+		// when
+		postErr := provider.PostPullRequestComment(ctx, repo, pr.ID, "conformance: general comment")
+		comments, listErr := provider.ListPullRequestComments(ctx, repo, pr.ID)
+
+		// then
+		require.NoError(t, postErr)
+		require.NoError(t, listErr)
+		comment := findComment(comments, "conformance: general comment")
+		require.NotNil(t, comment, "the posted comment is not listed")
+		assert.Empty(t, comment.FilePath)
+		assert.NotZero(t, comment.ID)
+	})
+
+	h.check(t, group, "should anchor thread comments and nest replies", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+		ctx := context.Background()
+		_, postErr := provider.PostPullRequestThreadComment(ctx, repo, pr.ID, "added.txt", 1, "conformance: inline")
+		require.NoError(t, postErr)
+		comments, listErr := provider.ListPullRequestComments(ctx, repo, pr.ID)
+		require.NoError(t, listErr)
+		root := findComment(comments, "conformance: inline")
+		require.NotNil(t, root, "the thread comment is not listed")
+
+		// when
+		_, replyErr := provider.ReplyToThread(ctx, repo, pr.ID, int(root.ThreadID), "conformance: reply")
+		comments, listErr = provider.ListPullRequestComments(ctx, repo, pr.ID)
+
+		// then
+		assert.Equal(t, "added.txt", strings.TrimPrefix(root.FilePath, "/"))
+		assert.Equal(t, 1, root.Line)
+		assert.NotZero(t, root.ThreadID, "inline comments must carry their thread ID")
+		require.NoError(t, replyErr)
+		require.NoError(t, listErr)
+		reply := findComment(comments, "conformance: reply")
+		require.NotNil(t, reply, "the reply is not listed")
+		assert.Equal(t, root.ThreadID, reply.ThreadID, "replies must stay in their thread")
+	})
+
+	h.check(t, group, "should update the status of a thread", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+		ctx := context.Background()
+		_, postErr := provider.PostPullRequestThreadComment(ctx, repo, pr.ID, "added.txt", 1, "conformance: status")
+		require.NoError(t, postErr)
+		comments, listErr := provider.ListPullRequestComments(ctx, repo, pr.ID)
+		require.NoError(t, listErr)
+		root := findComment(comments, "conformance: status")
+		require.NotNil(t, root, "the thread comment is not listed")
+
+		// when
+		fixedErr := provider.UpdatePullRequestThreadStatus(ctx, repo, pr.ID, int(root.ThreadID), "fixed")
+
+		// then
+		if errors.Is(fixedErr, errors.ErrUnsupported) {
+			return
+		}
+		require.NoError(t, fixedErr, "forges without thread status must return an error wrapping errors.ErrUnsupported")
+		require.NoError(t, provider.UpdatePullRequestThreadStatus(ctx, repo, pr.ID, int(root.ThreadID), "active"),
+			"a fixed thread must be reopenable")
+	})
+
+	h.check(t, group, "should report status and checks of an open pull request", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+		ctx := context.Background()
+
+		// when
+		status, statusErr := provider.GetPullRequestStatus(ctx, repo, pr.ID)
+		passed, checksErr := provider.GetPullRequestCheckStatus(ctx, repo, pr.ID)
+
+		// then
+		require.NoError(t, statusErr)
+		assert.NotEmpty(t, status)
+		require.NoError(t, checksErr)
+
+		checksProvider, ok := provider.(globalEntities.ChecksProvider)
+		if !ok {
+			return
+		}
+		checks, checkResultsErr := checksProvider.GetPullRequestChecks(ctx, repo, pr.ID)
+		require.NoError(t, checkResultsErr)
+		assert.Equal(t, passed, checks.Verdict == globalEntities.CheckStateSuccess,
+			"the verdict must agree with GetPullRequestCheckStatus when no check is required")
+	})
+
+	h.check(t, group, "should submit review verdicts", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+		ctx := context.Background()
+
+		// when
+		approveErr := provider.SubmitPullRequestReview(ctx, repo, pr.ID, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictApprove,
+			Body:    "conformance: approved",
+		})
+		emptyErr := provider.SubmitPullRequestReview(ctx, repo, pr.ID, globalEntities.ReviewSubmission{
+			Verdict: globalEntities.ReviewVerdictComment,
+		})
+
+		// then
+		require.NoError(t, approveErr)
+		require.NoError(t, emptyErr, "a comment verdict without a body must not fail")
+	})
+
+	h.check(t, group, "should merge into the target branch and delete the source branch", func(t *testing.T, f fixture) {
+		// given
+		provider, repo, pr := openPullRequest(t, f)
+		ctx := context.Background()
+		openStatus, statusErr := provider.GetPullRequestStatus(ctx, repo, pr.ID)
+		require.NoError(t, statusErr)
+
+		// when
+		err := provider.MergePullRequest(
+			ctx, repo, pr.ID, h.mergeStrategy(), globalEntities.WithDeleteSourceBranch(),
+		)
+
+		// then
+		require.NoError(t, err)
+		mergedStatus, mergedErr := provider.GetPullRequestStatus(ctx, repo, pr.ID)
+		require.NoError(t, mergedErr)
+		assert.NotEqual(t, openStatus, mergedStatus, "the status must change once merged")
+		exists, existsErr := provider.PullRequestExists(ctx, repo, "feature/review")
+		require.NoError(t, existsErr)
+		assert.False(t, exists, "a merged pull request is no longer open")
+		assertFile(t, f, repo, defaultBranch, "added.txt", "added\n")
+		assertFile(t, f, repo, defaultBranch, "README.md", "# reviewed\n")
+		assert.False(t, f.backend.BranchExists(t, repo, "feature/review"), "the source branch must be deleted")
+	})
+}
+
+// openPullRequest seeds a repository with a branch that edits README.md and
+// adds added.txt, and opens a pull request for it through the provider.
+func openPullRequest(
+	t *testing.T,
+	f fixture,
+) (globalEntities.ReviewProvider, globalEntities.Repository, *globalEntities.PullRequest) {
+	t.Helper()
+
+	provider, ok := f.provider.(globalEntities.ReviewProvider)
+	require.True(t, ok, "%s does not implement ReviewProvider", f.provider.Name())
+
+	repo := f.backend.SeedRepository(t, f.org, "review", map[string]string{"README.md": "# review\n"})
+	f.backend.CreateBranch(t, repo, "feature/review", map[string]string{
+		"README.md": "# reviewed\n",
+		"added.txt": "added\n",
+	})
+
+	pr, err := provider.CreatePullRequest(context.Background(), repo, globalEntities.PullRequestInput{
+		SourceBranch: "refs/heads/feature/review",
+		TargetBranch: "refs/heads/" + defaultBranch,
+		Title:        "conformance: review",
+		Description:  "Opened by the conformance suite.",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, pr)
+	return provider, repo, pr
+}
+
+// findComment returns the first comment whose body contains text.
+func findComment(comments []globalEntities.PullRequestComment, text string) *globalEntities.PullRequestComment {
+	for i := range comments {
+		if strings.Contains(comments[i].Body, text) {
+			return &comments[i]
+		}
+	}
+	return nil
+}
@@ -4,18 +4,38 @@ import (
 	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
 )
 
-// AdapterFinderStub implements git.AdapterFinder for testing.
+// AdapterFinderStub implements git.AdapterFinder for testing. The Value
+// fields answer every lookup when set; otherwise lookups go through Adapters
+// in order, like a registry would: the first adapter matching the URL or of
+// the service type wins.
 type AdapterFinderStub struct {
 	AdapterByServiceTypeValue globalEntities.LocalGitAuthProvider
 	AdapterByURLValue         globalEntities.LocalGitAuthProvider
+	Adapters                  []globalEntities.LocalGitAuthProvider
 }
 
 func (s *AdapterFinderStub) GetAdapterByServiceType(
-	_ globalEntities.ServiceType,
+	serviceType globalEntities.ServiceType,
 ) globalEntities.LocalGitAuthProvider {
-	return s.AdapterByServiceTypeValue
+	if s.AdapterByServiceTypeValue != nil {
+		return s.AdapterByServiceTypeValue
+	}
+	for _, adapter := range s.Adapters {
+		if adapter.GetServiceType() == serviceType {
+			return adapter
+		}
+	}
+	return nil
 }
 
-func (s *AdapterFinderStub) GetAdapterByURL(_ string) globalEntities.LocalGitAuthProvider {
-	return s.AdapterByURLValue
+func (s *AdapterFinderStub) GetAdapterByURL(url string) globalEntities.LocalGitAuthProvider {
+	if s.AdapterByURLValue != nil {
+		return s.AdapterByURLValue
+	}
+	for _, adapter := range s.Adapters {
+		if adapter.MatchesURL(url) {
+			return adapter
+		}
+	}
+	return nil
 }
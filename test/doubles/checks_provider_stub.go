@@ -0,0 +1,121 @@
+package doubles
+
+import (
+	"context"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// ChecksProviderStub implements ChecksProvider for testing status and check
+// polling. GetPullRequestStatus and GetPullRequestChecks answer Statuses and
+// Checks in turn and repeat the last entry once they run out; StatusErrs and
+// ChecksErrs fail the call with the same index when the entry is non-nil.
+type ChecksProviderStub struct {
+	*ForgeProviderStub
+
+	Statuses    []string
+	Checks      []*globalEntities.PullRequestChecks
+	StatusErrs  []error
+	ChecksErrs  []error
+	StatusCalls int
+	ChecksCalls int
+}
+
+func (s *ChecksProviderStub) GetPullRequestStatus(
+	_ context.Context, _ globalEntities.Repository, _ int,
+) (string, error) {
+	call := s.StatusCalls
+	s.StatusCalls++
+	if call < len(s.StatusErrs) && s.StatusErrs[call] != nil {
+		return "", s.StatusErrs[call]
+	}
+	return answer(s.Statuses, call), nil
+}
+
+func (s *ChecksProviderStub) GetPullRequestChecks(
+	_ context.Context, _ globalEntities.Repository, _ int,
+) (*globalEntities.PullRequestChecks, error) {
+	call := s.ChecksCalls
+	s.ChecksCalls++
+	if call < len(s.ChecksErrs) && s.ChecksErrs[call] != nil {
+		return nil, s.ChecksErrs[call]
+	}
+	return answer(s.Checks, call), nil
+}
+
+// answer returns the answer for the call with the given index, repeating the
+// last one once answers run out.
+func answer[T any](answers []T, call int) T {
+	var zero T
+	if len(answers) == 0 {
+		return zero
+	}
+	return answers[min(call, len(answers)-1)]
+}
+
+func (s *ChecksProviderStub) GetPullRequestCheckStatus(
+	_ context.Context, _ globalEntities.Repository, _ int,
+) (bool, error) {
+	return false, nil
+}
+
+func (s *ChecksProviderStub) ListOpenPullRequests(
+	_ context.Context, _ globalEntities.Repository,
+) ([]globalEntities.PullRequestDetail, error) {
+	return nil, nil
+}
+
+func (s *ChecksProviderStub) GetPullRequestDiff(
+	_ context.Context, _ globalEntities.Repository, _ int,
+) (string, error) {
+	return "", nil
+}
+
+func (s *ChecksProviderStub) GetPullRequestFiles(
+	_ context.Context, _ globalEntities.Repository, _ int,
+) ([]globalEntities.PullRequestFile, error) {
+	return nil, nil
+}
+
+func (s *ChecksProviderStub) PostPullRequestComment(
+	_ context.Context, _ globalEntities.Repository, _ int, _ string, _ ...globalEntities.CommentOption,
+) error {
+	return nil
+}
+
+func (s *ChecksProviderStub) PostPullRequestThreadComment(
+	_ context.Context, _ globalEntities.Repository, _ int, _ string, _ int, _ string,
+	_ ...globalEntities.CommentOption,
+) (int, error) {
+	return 0, nil
+}
+
+func (s *ChecksProviderStub) ReplyToThread(
+	_ context.Context, _ globalEntities.Repository, _, _ int, _ string,
+) (int, error) {
+	return 0, nil
+}
+
+func (s *ChecksProviderStub) UpdatePullRequestThreadStatus(
+	_ context.Context, _ globalEntities.Repository, _, _ int, _ string,
+) error {
+	return nil
+}
+
+func (s *ChecksProviderStub) MergePullRequest(
+	_ context.Context, _ globalEntities.Repository, _ int, _ string, _ ...globalEntities.MergeOption,
+) error {
+	return nil
+}
+
+func (s *ChecksProviderStub) ListPullRequestComments(
+	_ context.Context, _ globalEntities.Repository, _ int,
+) ([]globalEntities.PullRequestComment, error) {
+	return nil, nil
+}
+
+func (s *ChecksProviderStub) SubmitPullRequestReview(
+	_ context.Context, _ globalEntities.Repository, _ int, _ globalEntities.ReviewSubmission,
+) error {
+	return nil
+}
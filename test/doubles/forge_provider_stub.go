@@ -15,6 +15,13 @@ type ForgeProviderStub struct {
 	TokenValue       string
 	ServiceTypeValue globalEntities.ServiceType
 	AuthMethodsValue []transport.AuthMethod
+
+	// PreparedCloneURLValue, when set, is what PrepareCloneURL rewrites
+	// every URL to, e.g. a local repository standing in for the remote.
+	PreparedCloneURLValue string
+
+	AuthUsernames       []string // usernames GetAuthMethods was called with
+	TransportConfigured int      // number of ConfigureTransport calls
 }
 
 func (s *ForgeProviderStub) Name() string      { return s.NameValue }
@@ -47,8 +54,14 @@ func (s *ForgeProviderStub) ClosePullRequest(
 func (s *ForgeProviderStub) GetServiceType() globalEntities.ServiceType {
 	return s.ServiceTypeValue
 }
-func (s *ForgeProviderStub) PrepareCloneURL(url string) string { return url }
-func (s *ForgeProviderStub) ConfigureTransport()               {}
-func (s *ForgeProviderStub) GetAuthMethods(_ string) []transport.AuthMethod {
+func (s *ForgeProviderStub) PrepareCloneURL(url string) string {
+	if s.PreparedCloneURLValue != "" {
+		return s.PreparedCloneURLValue
+	}
+	return url
+}
+func (s *ForgeProviderStub) ConfigureTransport() { s.TransportConfigured++ }
+func (s *ForgeProviderStub) GetAuthMethods(username string) []transport.AuthMethod {
+	s.AuthUsernames = append(s.AuthUsernames, username)
 	return s.AuthMethodsValue
 }
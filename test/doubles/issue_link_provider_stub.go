@@ -0,0 +1,59 @@
+package doubles
+
+import (
+	"context"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// IssueLinkProviderStub implements IssueLinkProvider for testing on top of
+// Links. Linking an issue again only upgrades it to closing.
+type IssueLinkProviderStub struct {
+	*ForgeProviderStub
+
+	Links   map[int][]globalEntities.IssueLink // pull request ID -> links
+	LinkErr error
+	ListErr error
+}
+
+func (s *IssueLinkProviderStub) LinkPullRequestIssues(
+	_ context.Context,
+	_ globalEntities.Repository,
+	prID int,
+	links []globalEntities.IssueLink,
+) error {
+	if s.LinkErr != nil {
+		return s.LinkErr
+	}
+	if s.Links == nil {
+		s.Links = map[int][]globalEntities.IssueLink{}
+	}
+
+	existing := s.Links[prID]
+	for _, link := range links {
+		found := false
+		for i := range existing {
+			if existing[i].ID == link.ID {
+				existing[i].Closes = existing[i].Closes || link.Closes
+				found = true
+				break
+			}
+		}
+		if !found {
+			existing = append(existing, link)
+		}
+	}
+	s.Links[prID] = existing
+	return nil
+}
+
+func (s *IssueLinkProviderStub) ListPullRequestIssues(
+	_ context.Context,
+	_ globalEntities.Repository,
+	prID int,
+) ([]globalEntities.IssueLink, error) {
+	if s.ListErr != nil {
+		return nil, s.ListErr
+	}
+	return s.Links[prID], nil
+}
@@ -0,0 +1,167 @@
+package doubles
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var errIssueNotFound = errors.New("issue not found")
+
+// IssueProviderStub implements IssueProvider for testing on top of Issues,
+// which created issues are appended to with the next free ID. SearchIssues
+// filters Issues by state and labels only.
+type IssueProviderStub struct {
+	*ForgeProviderStub
+
+	Issues    []globalEntities.Issue
+	Comments  map[int][]string // issue ID -> comment bodies
+	CreateErr error
+	UpdateErr error // returned by every operation that changes an issue
+	SearchErr error
+}
+
+func (s *IssueProviderStub) CreateIssue(
+	_ context.Context,
+	_ globalEntities.Repository,
+	input globalEntities.IssueInput,
+) (*globalEntities.Issue, error) {
+	if s.CreateErr != nil {
+		return nil, s.CreateErr
+	}
+	issue := globalEntities.Issue{
+		ID:        len(s.Issues) + 1,
+		Title:     input.Title,
+		Body:      input.Body,
+		State:     globalEntities.IssueStateOpen,
+		Labels:    input.Labels,
+		Assignees: input.Assignees,
+	}
+	s.Issues = append(s.Issues, issue)
+	return &issue, nil
+}
+
+func (s *IssueProviderStub) GetIssue(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id int,
+) (*globalEntities.Issue, error) {
+	issue, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	result := *issue
+	return &result, nil
+}
+
+func (s *IssueProviderStub) UpdateIssueState(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id int,
+	state globalEntities.IssueState,
+) error {
+	return s.update(id, func(issue *globalEntities.Issue) { issue.State = state })
+}
+
+func (s *IssueProviderStub) AddIssueLabels(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	return s.update(id, func(issue *globalEntities.Issue) {
+		for _, label := range labels {
+			if !slices.Contains(issue.Labels, label) {
+				issue.Labels = append(issue.Labels, label)
+			}
+		}
+	})
+}
+
+func (s *IssueProviderStub) RemoveIssueLabels(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id int,
+	labels []string,
+) error {
+	return s.update(id, func(issue *globalEntities.Issue) {
+		issue.Labels = slices.DeleteFunc(issue.Labels, func(label string) bool {
+			return slices.Contains(labels, label)
+		})
+	})
+}
+
+func (s *IssueProviderStub) AssignIssue(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id int,
+	assignees []string,
+) error {
+	return s.update(id, func(issue *globalEntities.Issue) { issue.Assignees = assignees })
+}
+
+func (s *IssueProviderStub) CommentOnIssue(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id int,
+	body string,
+) (*globalEntities.IssueComment, error) {
+	if err := s.update(id, func(*globalEntities.Issue) {}); err != nil {
+		return nil, err
+	}
+	if s.Comments == nil {
+		s.Comments = map[int][]string{}
+	}
+	s.Comments[id] = append(s.Comments[id], body)
+	return &globalEntities.IssueComment{ID: int64(len(s.Comments[id])), Body: body}, nil
+}
+
+func (s *IssueProviderStub) SearchIssues(
+	_ context.Context,
+	_ globalEntities.Repository,
+	query globalEntities.IssueQuery,
+) ([]globalEntities.Issue, error) {
+	if s.SearchErr != nil {
+		return nil, s.SearchErr
+	}
+
+	var result []globalEntities.Issue
+	for _, issue := range s.Issues {
+		if query.State != "" && issue.State != query.State {
+			continue
+		}
+		if slices.ContainsFunc(query.Labels, func(label string) bool {
+			return !slices.Contains(issue.Labels, label)
+		}) {
+			continue
+		}
+		result = append(result, issue)
+		if query.Limit > 0 && len(result) == query.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (s *IssueProviderStub) find(id int) (*globalEntities.Issue, error) {
+	for i := range s.Issues {
+		if s.Issues[i].ID == id {
+			return &s.Issues[i], nil
+		}
+	}
+	return nil, errIssueNotFound
+}
+
+func (s *IssueProviderStub) update(id int, change func(*globalEntities.Issue)) error {
+	if s.UpdateErr != nil {
+		return s.UpdateErr
+	}
+	issue, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	change(issue)
+	return nil
+}
@@ -0,0 +1,57 @@
+package doubles
+
+import (
+	"context"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// MirrorLifecycleProviderStub implements MirrorLifecycleProvider for testing.
+type MirrorLifecycleProviderStub struct {
+	*MirrorProviderStub
+
+	Mirrors     []globalEntities.Mirror
+	ListErr     error
+	SyncErr     error
+	IntervalErr error
+	ConvertErr  error
+	Synced      []globalEntities.Repository
+	Intervals   map[string]time.Duration // repository name -> last interval set
+	Converted   []globalEntities.Repository
+}
+
+func (s *MirrorLifecycleProviderStub) ListMirrors(
+	_ context.Context,
+	_ string,
+) ([]globalEntities.Mirror, error) {
+	return s.Mirrors, s.ListErr
+}
+
+func (s *MirrorLifecycleProviderStub) SyncMirror(
+	_ context.Context,
+	repo globalEntities.Repository,
+) error {
+	s.Synced = append(s.Synced, repo)
+	return s.SyncErr
+}
+
+func (s *MirrorLifecycleProviderStub) SetMirrorInterval(
+	_ context.Context,
+	repo globalEntities.Repository,
+	interval time.Duration,
+) error {
+	if s.Intervals == nil {
+		s.Intervals = make(map[string]time.Duration)
+	}
+	s.Intervals[repo.Name] = interval
+	return s.IntervalErr
+}
+
+func (s *MirrorLifecycleProviderStub) ConvertMirror(
+	_ context.Context,
+	repo globalEntities.Repository,
+) error {
+	s.Converted = append(s.Converted, repo)
+	return s.ConvertErr
+}
@@ -0,0 +1,141 @@
+package doubles
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var errPipelineRunNotFound = errors.New("pipeline run not found")
+
+// PipelineProviderStub implements PipelineProvider for testing on top of
+// Runs, which triggered runs are prepended to with the next free ID and the
+// queued status. Jobs and Logs are keyed by run ID and by "{runID}/{jobID}";
+// cancellations and retries are recorded as the run ID.
+type PipelineProviderStub struct {
+	*ForgeProviderStub
+
+	Runs       []globalEntities.PipelineRun
+	Jobs       map[string][]globalEntities.PipelineJob // run ID -> jobs
+	Logs       map[string]string                       // "{runID}/{jobID}" -> log
+	Inputs     []globalEntities.PipelineRunInput       // inputs of the triggered runs
+	Canceled   []string
+	Retried    []string
+	TriggerErr error
+	ListErr    error // returned by ListPipelineRuns, ListPipelineJobs and GetPipelineJobLogs
+	UpdateErr  error // returned by CancelPipelineRun and RetryPipelineRun
+}
+
+func (s *PipelineProviderStub) TriggerPipeline(
+	_ context.Context,
+	_ globalEntities.Repository,
+	input globalEntities.PipelineRunInput,
+) (*globalEntities.PipelineRun, error) {
+	if s.TriggerErr != nil {
+		return nil, s.TriggerErr
+	}
+	s.Inputs = append(s.Inputs, input)
+	run := globalEntities.PipelineRun{
+		ID:       strconv.Itoa(len(s.Runs) + 1),
+		Pipeline: input.Pipeline,
+		Ref:      input.Ref,
+		Status:   globalEntities.PipelineStatusQueued,
+	}
+	s.Runs = slices.Insert(s.Runs, 0, run)
+	return &run, nil
+}
+
+func (s *PipelineProviderStub) ListPipelineRuns(
+	_ context.Context,
+	_ globalEntities.Repository,
+	query globalEntities.PipelineRunQuery,
+) ([]globalEntities.PipelineRun, error) {
+	if s.ListErr != nil {
+		return nil, s.ListErr
+	}
+	var runs []globalEntities.PipelineRun
+	for _, run := range s.Runs {
+		if (query.Branch != "" && run.Ref != query.Branch) ||
+			(query.CommitSHA != "" && run.CommitSHA != query.CommitSHA) {
+			continue
+		}
+		runs = append(runs, run)
+		if query.Limit > 0 && len(runs) == query.Limit {
+			break
+		}
+	}
+	return runs, nil
+}
+
+func (s *PipelineProviderStub) GetPipelineRun(
+	_ context.Context,
+	_ globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	index, err := s.find(runID)
+	if err != nil {
+		return nil, err
+	}
+	run := s.Runs[index]
+	return &run, nil
+}
+
+func (s *PipelineProviderStub) ListPipelineJobs(
+	_ context.Context,
+	_ globalEntities.Repository,
+	runID string,
+) ([]globalEntities.PipelineJob, error) {
+	return s.Jobs[runID], s.ListErr
+}
+
+func (s *PipelineProviderStub) CancelPipelineRun(_ context.Context, _ globalEntities.Repository, runID string) error {
+	if s.UpdateErr != nil {
+		return s.UpdateErr
+	}
+	if _, err := s.find(runID); err != nil {
+		return err
+	}
+	s.Canceled = append(s.Canceled, runID)
+	return nil
+}
+
+func (s *PipelineProviderStub) RetryPipelineRun(
+	_ context.Context,
+	_ globalEntities.Repository,
+	runID string,
+) (*globalEntities.PipelineRun, error) {
+	if s.UpdateErr != nil {
+		return nil, s.UpdateErr
+	}
+	index, err := s.find(runID)
+	if err != nil {
+		return nil, err
+	}
+	s.Retried = append(s.Retried, runID)
+	run := s.Runs[index]
+	return &run, nil
+}
+
+func (s *PipelineProviderStub) GetPipelineJobLogs(
+	_ context.Context,
+	_ globalEntities.Repository,
+	runID, jobID string,
+) (io.ReadCloser, error) {
+	if s.ListErr != nil {
+		return nil, s.ListErr
+	}
+	return io.NopCloser(strings.NewReader(s.Logs[runID+"/"+jobID])), nil
+}
+
+func (s *PipelineProviderStub) find(runID string) (int, error) {
+	index := slices.IndexFunc(s.Runs, func(r globalEntities.PipelineRun) bool { return r.ID == runID })
+	if index < 0 {
+		return 0, errPipelineRunNotFound
+	}
+	return index, nil
+}
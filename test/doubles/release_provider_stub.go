@@ -0,0 +1,108 @@
+package doubles
+
+import (
+	"context"
+	"io"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// ReleaseProviderStub implements ReleaseProvider for testing. Created and
+// updated releases are recorded and echoed back; uploaded asset content is
+// read into UploadedContent.
+type ReleaseProviderStub struct {
+	*ForgeProviderStub
+
+	Releases        []globalEntities.Release
+	Release         *globalEntities.Release // returned by GetRelease
+	Asset           *globalEntities.ReleaseAsset
+	CreateErr       error
+	UpdateErr       error
+	GetErr          error
+	ListErr         error
+	UploadErr       error
+	DeleteAssetErr  error
+	Created         []globalEntities.ReleaseInput
+	Updated         []globalEntities.ReleaseInput
+	UploadedContent map[string][]byte // asset name -> content
+	DeletedAssets   []string
+}
+
+func (s *ReleaseProviderStub) CreateRelease(
+	_ context.Context,
+	_ globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	s.Created = append(s.Created, input)
+	if s.CreateErr != nil {
+		return nil, s.CreateErr
+	}
+	return releaseFromInput(input), nil
+}
+
+func (s *ReleaseProviderStub) UpdateRelease(
+	_ context.Context,
+	_ globalEntities.Repository,
+	input globalEntities.ReleaseInput,
+) (*globalEntities.Release, error) {
+	s.Updated = append(s.Updated, input)
+	if s.UpdateErr != nil {
+		return nil, s.UpdateErr
+	}
+	return releaseFromInput(input), nil
+}
+
+func (s *ReleaseProviderStub) GetRelease(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ string,
+) (*globalEntities.Release, error) {
+	return s.Release, s.GetErr
+}
+
+func (s *ReleaseProviderStub) ListReleases(
+	_ context.Context,
+	_ globalEntities.Repository,
+) ([]globalEntities.Release, error) {
+	return s.Releases, s.ListErr
+}
+
+func (s *ReleaseProviderStub) UploadReleaseAsset(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_ string,
+	asset globalEntities.ReleaseAssetInput,
+) (*globalEntities.ReleaseAsset, error) {
+	if s.UploadErr != nil {
+		return nil, s.UploadErr
+	}
+	content, err := io.ReadAll(asset.Content)
+	if err != nil {
+		return nil, err
+	}
+	if s.UploadedContent == nil {
+		s.UploadedContent = make(map[string][]byte)
+	}
+	s.UploadedContent[asset.Name] = content
+	return s.Asset, nil
+}
+
+func (s *ReleaseProviderStub) DeleteReleaseAsset(
+	_ context.Context,
+	_ globalEntities.Repository,
+	_, assetID string,
+) error {
+	s.DeletedAssets = append(s.DeletedAssets, assetID)
+	return s.DeleteAssetErr
+}
+
+func releaseFromInput(input globalEntities.ReleaseInput) *globalEntities.Release {
+	return &globalEntities.Release{
+		ID:         input.TagName,
+		TagName:    input.TagName,
+		Name:       input.Name,
+		Body:       input.Body,
+		Draft:      input.Draft,
+		Prerelease: input.Prerelease,
+	}
+}
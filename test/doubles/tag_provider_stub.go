@@ -0,0 +1,34 @@
+package doubles
+
+import (
+	"context"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// TagProviderStub implements TagProvider for testing. Created tags are
+// recorded and echoed back as lightweight or annotated by their message.
+type TagProviderStub struct {
+	*ForgeProviderStub
+
+	CommitSHA string // reported as the tagged commit
+	CreateErr error
+	Created   []globalEntities.TagInput
+}
+
+func (s *TagProviderStub) CreateTag(
+	_ context.Context,
+	_ globalEntities.Repository,
+	input globalEntities.TagInput,
+) (*globalEntities.Tag, error) {
+	s.Created = append(s.Created, input)
+	if s.CreateErr != nil {
+		return nil, s.CreateErr
+	}
+	return &globalEntities.Tag{
+		Name:      input.Name,
+		CommitSHA: s.CommitSHA,
+		Annotated: input.Message != "",
+		Message:   input.Message,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package doubles
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+var errWebhookNotFound = errors.New("webhook not found")
+
+// WebhookProviderStub implements WebhookProvider for testing on top of
+// Webhooks, which created webhooks are appended to with the next free ID.
+// Pings and redeliveries are recorded as "{id}" and "{id}/{deliveryID}".
+type WebhookProviderStub struct {
+	*ForgeProviderStub
+
+	Webhooks    []globalEntities.Webhook
+	Deliveries  map[string][]globalEntities.WebhookDelivery // webhook ID -> deliveries
+	Pinged      []string
+	Redelivered []string
+	CreateErr   error
+	UpdateErr   error // returned by UpdateWebhook and DeleteWebhook
+	ListErr     error
+	PingErr     error // returned by PingWebhook and RedeliverWebhook
+}
+
+func (s *WebhookProviderStub) CreateWebhook(
+	_ context.Context,
+	repo globalEntities.Repository,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	if s.CreateErr != nil {
+		return nil, s.CreateErr
+	}
+	webhook := webhookFromInput(strconv.Itoa(len(s.Webhooks)+1), repo, input)
+	s.Webhooks = append(s.Webhooks, webhook)
+	return &webhook, nil
+}
+
+func (s *WebhookProviderStub) ListWebhooks(
+	_ context.Context,
+	_ globalEntities.Repository,
+) ([]globalEntities.Webhook, error) {
+	return s.Webhooks, s.ListErr
+}
+
+func (s *WebhookProviderStub) UpdateWebhook(
+	_ context.Context,
+	repo globalEntities.Repository,
+	id string,
+	input globalEntities.WebhookInput,
+) (*globalEntities.Webhook, error) {
+	if s.UpdateErr != nil {
+		return nil, s.UpdateErr
+	}
+	index, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	s.Webhooks[index] = webhookFromInput(id, repo, input)
+	return &s.Webhooks[index], nil
+}
+
+func (s *WebhookProviderStub) DeleteWebhook(_ context.Context, _ globalEntities.Repository, id string) error {
+	if s.UpdateErr != nil {
+		return s.UpdateErr
+	}
+	index, err := s.find(id)
+	if err != nil {
+		return err
+	}
+	s.Webhooks = slices.Delete(s.Webhooks, index, index+1)
+	return nil
+}
+
+func (s *WebhookProviderStub) PingWebhook(_ context.Context, _ globalEntities.Repository, id string) error {
+	if s.PingErr != nil {
+		return s.PingErr
+	}
+	s.Pinged = append(s.Pinged, id)
+	return nil
+}
+
+func (s *WebhookProviderStub) ListWebhookDeliveries(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id string,
+) ([]globalEntities.WebhookDelivery, error) {
+	return s.Deliveries[id], s.ListErr
+}
+
+func (s *WebhookProviderStub) RedeliverWebhook(
+	_ context.Context,
+	_ globalEntities.Repository,
+	id, deliveryID string,
+) error {
+	if s.PingErr != nil {
+		return s.PingErr
+	}
+	s.Redelivered = append(s.Redelivered, id+"/"+deliveryID)
+	return nil
+}
+
+func (s *WebhookProviderStub) find(id string) (int, error) {
+	index := slices.IndexFunc(s.Webhooks, func(w globalEntities.Webhook) bool { return w.ID == id })
+	if index < 0 {
+		return 0, errWebhookNotFound
+	}
+	return index, nil
+}
+
+func webhookFromInput(
+	id string,
+	repo globalEntities.Repository,
+	input globalEntities.WebhookInput,
+) globalEntities.Webhook {
+	return globalEntities.Webhook{
+		ID:          id,
+		Repository:  repo,
+		URL:         input.URL,
+		Events:      input.Events,
+		ContentType: input.ContentTypeOrDefault(),
+		Active:      true,
+	}
+}
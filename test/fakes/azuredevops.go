@@ -0,0 +1,1017 @@
+package fakes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	azureProjectPath     = "/{collection}/{project}/_apis"
+	azureRepositoryPath  = azureProjectPath + "/git/repositories/{repository}"
+	azurePullRequestPath = azureRepositoryPath + "/pullrequests/{id}"
+	azureZeroObjectID    = "0000000000000000000000000000000000000000"
+)
+
+// Azure DevOps pull request statuses.
+const (
+	azureActive    = "active"
+	azureAbandoned = "abandoned"
+	azureCompleted = "completed"
+)
+
+// AzureDevOpsServer fakes the Azure DevOps Server REST API, so
+// azuredevops.NewServerProvider(token, URL(), "", "") talks to it. Each seeded
+// organization becomes both a collection and the single project inside it,
+// and repositories are addressed by ID or by name.
+type AzureDevOpsServer struct {
+	*server
+
+	serviceEndpoints map[string]string // service endpoint ID -> URL
+}
+
+// NewAzureDevOpsServer starts a fake Azure DevOps server that stops when t ends.
+func NewAzureDevOpsServer(t *testing.T) *AzureDevOpsServer {
+	t.Helper()
+
+	s := &AzureDevOpsServer{server: newServer(), serviceEndpoints: make(map[string]string)}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{collection}/_apis/connectionData", s.getConnectionData)
+	mux.HandleFunc("GET /{collection}/_apis/projects", s.listProjects)
+	mux.HandleFunc("GET "+azureProjectPath+"/git/repositories", s.listRepositories)
+	mux.HandleFunc("POST "+azureProjectPath+"/git/repositories", s.createRepository)
+	mux.HandleFunc("GET "+azureRepositoryPath, s.getRepository)
+	mux.HandleFunc("POST /{collection}/_apis/serviceendpoint/endpoints", s.createServiceEndpoint)
+	mux.HandleFunc("POST "+azureRepositoryPath+"/importRequests", s.createImportRequest)
+	mux.HandleFunc("GET "+azureRepositoryPath+"/importRequests/{request}", s.getImportRequest)
+
+	mux.HandleFunc("GET "+azureRepositoryPath+"/items", s.getItems)
+	mux.HandleFunc("GET "+azureRepositoryPath+"/refs", s.listRefs)
+	mux.HandleFunc("POST "+azureRepositoryPath+"/pushes", s.createPush)
+	mux.HandleFunc("POST "+azureRepositoryPath+"/commits/{sha}/statuses", s.createCommitStatus)
+
+	mux.HandleFunc("GET "+azureRepositoryPath+"/pullrequests", s.listPullRequests)
+	mux.HandleFunc("POST "+azureRepositoryPath+"/pullrequests", s.createPullRequest)
+	mux.HandleFunc("GET "+azurePullRequestPath, s.getPullRequest)
+	mux.HandleFunc("PATCH "+azurePullRequestPath, s.updatePullRequest)
+	mux.HandleFunc("GET "+azurePullRequestPath+"/iterations", s.listIterations)
+	mux.HandleFunc("GET "+azurePullRequestPath+"/iterations/{iteration}/changes", s.listIterationChanges)
+	mux.HandleFunc("GET "+azurePullRequestPath+"/statuses", s.listPullRequestStatuses)
+	mux.HandleFunc("GET "+azureProjectPath+"/policy/evaluations", s.listPolicyEvaluations)
+	mux.HandleFunc("PUT "+azurePullRequestPath+"/reviewers/{reviewer}", s.putReviewer)
+	mux.HandleFunc("GET "+azurePullRequestPath+"/threads", s.listThreads)
+	mux.HandleFunc("POST "+azurePullRequestPath+"/threads", s.createThread)
+	mux.HandleFunc("PATCH "+azurePullRequestPath+"/threads/{thread}", s.updateThread)
+	mux.HandleFunc("POST "+azurePullRequestPath+"/threads/{thread}/comments", s.createThreadComment)
+
+	s.start(t, mux)
+	return s
+}
+
+// SeedRepository creates repository name in the collection and project org
+// with files committed on "main" and returns it the way the azuredevops
+// provider discovers it.
+func (s *AzureDevOpsServer) SeedRepository(
+	t *testing.T,
+	org, name string,
+	files map[string]string,
+	tags ...string,
+) globalEntities.Repository {
+	t.Helper()
+
+	r := s.seed(t, org, name, files, tags...)
+	return globalEntities.Repository{
+		ID:            azureGUID(r.id),
+		Name:          r.name,
+		Organization:  r.org,
+		Project:       r.org,
+		DefaultBranch: "refs/heads/" + r.defaultBranch,
+		RemoteURL:     s.remoteURL(r),
+		SSHURL:        s.sshRemoteURL(r),
+		ProviderName:  "azuredevops",
+	}
+}
+
+// azureGUID renders a numeric ID as the GUID form Azure DevOps uses for
+// projects, repositories and identities.
+func azureGUID(id int) string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", id)
+}
+
+func (s *AzureDevOpsServer) remoteURL(r *repository) string {
+	return fmt.Sprintf("%s/%s/%s/_git/%s", s.URL(), r.org, r.org, r.name)
+}
+
+func (s *AzureDevOpsServer) sshRemoteURL(r *repository) string {
+	host, _, _ := net.SplitHostPort(s.httpServer.Listener.Addr().String())
+	return fmt.Sprintf("ssh://%s:22/%s/%s/_git/%s", host, r.org, r.org, r.name)
+}
+
+// writeValues writes items in Azure DevOps' {"count": n, "value": [...]} envelope.
+func writeValues[T any](w http.ResponseWriter, items []T) {
+	if items == nil {
+		items = []T{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"count": len(items), "value": items})
+}
+
+// --- projects and repositories ---
+
+func (s *AzureDevOpsServer) getConnectionData(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"authenticatedUser": map[string]any{"id": azureGUID(0), "providerDisplayName": s.user},
+	})
+}
+
+func (s *AzureDevOpsServer) listProjects(w http.ResponseWriter, r *http.Request) {
+	collection := r.PathValue("collection")
+	if _, ok := s.reposOf(collection); !ok {
+		writeError(w, http.StatusNotFound, "TF200016: The following project does not exist: "+collection)
+		return
+	}
+	writeValues(w, []map[string]any{s.projectJSON(collection)})
+}
+
+func (s *AzureDevOpsServer) projectJSON(collection string) map[string]any {
+	return map[string]any{"id": "project-" + collection, "name": collection, "state": "wellFormed"}
+}
+
+func (s *AzureDevOpsServer) repositoryJSON(r *repository) map[string]any {
+	return map[string]any{
+		"id":            azureGUID(r.id),
+		"name":          r.name,
+		"project":       s.projectJSON(r.org),
+		"defaultBranch": "refs/heads/" + r.defaultBranch,
+		"remoteUrl":     s.remoteURL(r),
+		"sshUrl":        s.sshRemoteURL(r),
+		"webUrl":        s.remoteURL(r),
+		"isDisabled":    false,
+	}
+}
+
+// project returns the collection named by the request path when the project
+// segment names its single project, by name or by ID.
+func (s *AzureDevOpsServer) project(r *http.Request) (string, bool) {
+	collection := r.PathValue("collection")
+	project := r.PathValue("project")
+	if !strings.EqualFold(project, collection) && project != "project-"+collection {
+		return "", false
+	}
+	_, ok := s.reposOf(collection)
+	return collection, ok
+}
+
+func (s *AzureDevOpsServer) listRepositories(w http.ResponseWriter, r *http.Request) {
+	collection, ok := s.project(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "TF200016: The following project does not exist")
+		return
+	}
+	repos, _ := s.reposOf(collection)
+	out := make([]map[string]any, 0, len(repos))
+	for _, repo := range repos {
+		out = append(out, s.repositoryJSON(repo))
+	}
+	writeValues(w, out)
+}
+
+// lookup returns the repository named by the request path, answering 404
+// when it does not exist.
+func (s *AzureDevOpsServer) lookup(w http.ResponseWriter, r *http.Request) (*repository, bool) {
+	if collection, ok := s.project(r); ok {
+		repos, _ := s.reposOf(collection)
+		identifier := r.PathValue("repository")
+		for _, repo := range repos {
+			if azureGUID(repo.id) == identifier || strings.EqualFold(repo.name, identifier) {
+				return repo, true
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "TF401019: The Git repository with name or identifier "+
+		r.PathValue("repository")+" does not exist or you do not have permissions for the operation you are attempting.")
+	return nil, false
+}
+
+func (s *AzureDevOpsServer) getRepository(w http.ResponseWriter, r *http.Request) {
+	if repo, ok := s.lookup(w, r); ok {
+		writeJSON(w, http.StatusOK, s.repositoryJSON(repo))
+	}
+}
+
+func (s *AzureDevOpsServer) createRepository(w http.ResponseWriter, r *http.Request) {
+	collection, ok := s.project(r)
+	if !ok {
+		writeError(w, http.StatusNotFound, "TF200016: The following project does not exist")
+		return
+	}
+	var body struct {
+		Name string `json:"name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	repo, ok := s.addRepo(collection, body.Name)
+	if !ok {
+		writeError(w, http.StatusConflict, "TF400948: A Git repository with the name "+body.Name+
+			" already exists.")
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.repositoryJSON(repo))
+}
+
+// --- imports ---
+
+func (s *AzureDevOpsServer) createServiceEndpoint(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name          string `json:"name"`
+		Type          string `json:"type"`
+		URL           string `json:"url"`
+		Authorization struct {
+			Scheme string `json:"scheme"`
+		} `json:"authorization"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Type != "git" || body.Authorization.Scheme != "UsernamePassword" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf(
+			"unsupported service endpoint type %q with scheme %q", body.Type, body.Authorization.Scheme,
+		))
+		return
+	}
+	s.nextID++
+	id := azureGUID(int(s.nextID))
+	s.serviceEndpoints[id] = body.URL
+	writeJSON(w, http.StatusOK, map[string]any{"id": id, "name": body.Name, "type": body.Type, "url": body.URL})
+}
+
+// importRequestJSON renders the import into repo. Imports run while the
+// request is created, so the request ID is the repository's own ID and the
+// status is final at once.
+func (s *AzureDevOpsServer) importRequestJSON(repo *repository) map[string]any {
+	status, step := azureCompleted, 3
+	if repo.importError != "" {
+		status, step = "failed", 1
+	}
+	return map[string]any{
+		"importRequestId": repo.id,
+		"repository":      s.repositoryJSON(repo),
+		"status":          status,
+		"parameters":      map[string]any{"gitSource": map[string]any{"url": repo.importURL}},
+		"detailedStatus": map[string]any{
+			"currentStep":  step,
+			"allSteps":     []string{"Cloning source repository", "Updating references", "Import completed"},
+			"errorMessage": repo.importError,
+		},
+	}
+}
+
+func (s *AzureDevOpsServer) createImportRequest(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Parameters struct {
+			GitSource struct {
+				URL string `json:"url"`
+			} `json:"gitSource"`
+			ServiceEndpointID                      string `json:"serviceEndpointId"`
+			DeleteServiceEndpointAfterImportIsDone bool   `json:"deleteServiceEndpointAfterImportIsDone"`
+		} `json:"parameters"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	params := body.Parameters
+	if len(repo.branches) > 0 || repo.importURL != "" {
+		writeError(w, http.StatusConflict, "TF401260: The repository "+repo.name+" is not empty.")
+		return
+	}
+	if strings.Contains(params.GitSource.URL, "@") {
+		writeError(w, http.StatusBadRequest, "The source URL must not carry credentials, use a service endpoint")
+		return
+	}
+	if params.ServiceEndpointID != "" {
+		if _, found := s.serviceEndpoints[params.ServiceEndpointID]; !found {
+			writeError(w, http.StatusBadRequest, "Service endpoint "+params.ServiceEndpointID+" does not exist")
+			return
+		}
+		if params.DeleteServiceEndpointAfterImportIsDone {
+			delete(s.serviceEndpoints, params.ServiceEndpointID)
+		}
+	}
+
+	repo.importURL = params.GitSource.URL
+	if source, found := s.importSource(params.GitSource.URL); found {
+		copyRepository(repo, source)
+	} else {
+		repo.importError = "TF401263: Failed to clone " + params.GitSource.URL
+	}
+	writeJSON(w, http.StatusCreated, s.importRequestJSON(repo))
+}
+
+func (s *AzureDevOpsServer) getImportRequest(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if r.PathValue("request") != strconv.Itoa(repo.id) || repo.importURL == "" {
+		writeError(w, http.StatusNotFound, "TF401256: The import request does not exist")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.importRequestJSON(repo))
+}
+
+// --- items, refs and pushes ---
+
+func (s *AzureDevOpsServer) getItems(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	c, ok := repo.resolve(query.Get("versionDescriptor.version"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "TF401175: The version descriptor could not be resolved to a version")
+		return
+	}
+
+	if query.Get("recursionLevel") != "" {
+		items := []map[string]any{{"objectId": c.tree, "gitObjectType": "tree", "path": "/", "isFolder": true}}
+		for _, entry := range treeEntries(c.files) {
+			objectType := "blob"
+			if entry.isDir {
+				objectType = "tree"
+			}
+			items = append(items, map[string]any{
+				"objectId":      entry.sha,
+				"gitObjectType": objectType,
+				"path":          "/" + entry.path,
+				"isFolder":      entry.isDir,
+			})
+		}
+		writeValues(w, items)
+		return
+	}
+
+	path := strings.TrimPrefix(query.Get("path"), "/")
+	content, ok := c.files[path]
+	if !ok {
+		writeError(w, http.StatusNotFound, "TF401174: The item '/"+path+"' could not be found in the repository")
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write([]byte(content))
+}
+
+func (s *AzureDevOpsServer) refJSON(name, sha string) map[string]any {
+	return map[string]any{"name": name, "objectId": sha, "creator": map[string]any{"displayName": s.user}}
+}
+
+func (s *AzureDevOpsServer) listRefs(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	filter := "refs/" + r.URL.Query().Get("filter")
+	var refs []map[string]any
+	for _, branch := range slices.Sorted(maps.Keys(repo.branches)) {
+		if name := "refs/heads/" + branch; strings.HasPrefix(name, filter) {
+			refs = append(refs, s.refJSON(name, repo.branches[branch]))
+		}
+	}
+	for _, tag := range slices.Sorted(maps.Keys(repo.tags)) {
+		if name := "refs/tags/" + tag; strings.HasPrefix(name, filter) {
+			refs = append(refs, s.refJSON(name, repo.tags[tag]))
+		}
+	}
+	writeValues(w, refs)
+}
+
+func (s *AzureDevOpsServer) createPush(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		RefUpdates []struct {
+			Name        string `json:"name"`
+			OldObjectID string `json:"oldObjectId"`
+		} `json:"refUpdates"`
+		Commits []struct {
+			Comment string   `json:"comment"`
+			Parents []string `json:"parents"`
+			Changes []struct {
+				ChangeType string `json:"changeType"`
+				Item       struct {
+					Path string `json:"path"`
+				} `json:"item"`
+				NewContent struct {
+					Content     string `json:"content"`
+					ContentType string `json:"contentType"`
+				} `json:"newContent"`
+			} `json:"changes"`
+		} `json:"commits"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if len(body.RefUpdates) != 1 || len(body.Commits) != 1 {
+		writeError(w, http.StatusBadRequest, "a push must update exactly one ref with one commit")
+		return
+	}
+
+	update := body.RefUpdates[0]
+	branch := strings.TrimPrefix(update.Name, "refs/heads/")
+	current, exists := repo.branches[branch]
+	if (update.OldObjectID == azureZeroObjectID && exists) ||
+		(update.OldObjectID != azureZeroObjectID && current != update.OldObjectID) {
+		writeError(w, http.StatusConflict, "TF401028: The reference '"+update.Name+
+			"' has already been updated by another client, so you cannot update it.")
+		return
+	}
+
+	pushed := body.Commits[0]
+	var parent *commit
+	for _, sha := range pushed.Parents {
+		if parent, ok = repo.commits[sha]; !ok {
+			writeError(w, http.StatusBadRequest, "TF401035: The object '"+sha+"' does not exist.")
+			return
+		}
+	}
+	files := make(map[string]string)
+	if parent != nil {
+		files = maps.Clone(parent.files)
+	}
+
+	for _, change := range pushed.Changes {
+		path := strings.TrimPrefix(change.Item.Path, "/")
+		content := change.NewContent.Content
+		if change.NewContent.ContentType == "base64encoded" {
+			decoded, err := base64.StdEncoding.DecodeString(content)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "newContent is not valid base64")
+				return
+			}
+			content = string(decoded)
+		}
+
+		_, found := files[path]
+		switch change.ChangeType {
+		case "add":
+			if found {
+				writeError(w, http.StatusConflict, "TF402455: The path '/"+path+"' already exists.")
+				return
+			}
+			files[path] = content
+		case "edit":
+			if !found {
+				writeError(w, http.StatusNotFound, "TF401174: The item '/"+path+"' could not be found.")
+				return
+			}
+			files[path] = content
+		case "delete":
+			if !found {
+				writeError(w, http.StatusNotFound, "TF401174: The item '/"+path+"' could not be found.")
+				return
+			}
+			delete(files, path)
+		default:
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported change type %q", change.ChangeType))
+			return
+		}
+	}
+
+	c := repo.commit(pushed.Comment, files, pushed.Parents...)
+	repo.branches[branch] = c.sha
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"pushId":     len(repo.commits),
+		"commits":    []map[string]any{{"commitId": c.sha, "comment": c.message}},
+		"refUpdates": []map[string]any{{"name": update.Name, "newObjectId": c.sha}},
+	})
+}
+
+// azureStatuses renders commit states in Azure DevOps' status vocabulary.
+var azureStatuses = map[CheckState]string{ //nolint:gochecknoglobals // read-only lookup table
+	CheckSuccess: "succeeded",
+	CheckPending: "pending",
+	CheckFailure: "failed",
+}
+
+func (s *AzureDevOpsServer) createCommitStatus(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		State   string `json:"state"`
+		Context struct {
+			Name  string `json:"name"`
+			Genre string `json:"genre"`
+		} `json:"context"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	sha := r.PathValue("sha")
+	if _, found := repo.commits[sha]; !found {
+		writeError(w, http.StatusNotFound, "TF401035: The object '"+sha+"' does not exist.")
+		return
+	}
+
+	var state CheckState
+	switch body.State {
+	case "succeeded":
+		state = CheckSuccess
+	case "pending", "notSet", "notApplicable":
+		state = CheckPending
+	case "failed", "error":
+		state = CheckFailure
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid status state %q", body.State))
+		return
+	}
+	name := body.Context.Name
+	if body.Context.Genre != "" {
+		name = body.Context.Genre + "/" + name
+	}
+	repo.setStatus(sha, name, state)
+	writeJSON(w, http.StatusCreated, map[string]any{"state": body.State, "context": body.Context})
+}
+
+// --- pull requests ---
+
+func azureStatus(pr *pullRequest) string {
+	switch pr.state {
+	case stateMerged:
+		return azureCompleted
+	case stateClosed:
+		return azureAbandoned
+	}
+	return azureActive
+}
+
+func (s *AzureDevOpsServer) pullRequestJSON(repo *repository, pr *pullRequest) map[string]any {
+	out := map[string]any{
+		"pullRequestId":         pr.number,
+		"codeReviewId":          pr.number,
+		"repository":            s.repositoryJSON(repo),
+		"title":                 pr.title,
+		"description":           pr.description,
+		"status":                azureStatus(pr),
+		"isDraft":               pr.draft,
+		"sourceRefName":         "refs/heads/" + pr.source,
+		"targetRefName":         "refs/heads/" + pr.target,
+		"creationDate":          pr.createdAt.Format(time.RFC3339),
+		"createdBy":             map[string]any{"displayName": pr.author, "uniqueName": pr.author},
+		"lastMergeSourceCommit": map[string]any{"commitId": repo.sourceHead(pr).sha},
+		"url": fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/pullRequests/%d",
+			s.URL(), repo.org, repo.org, azureGUID(repo.id), pr.number),
+	}
+	if target, err := repo.head(pr.target); err == nil {
+		out["lastMergeTargetCommit"] = map[string]any{"commitId": target.sha}
+	}
+	if pr.state == stateMerged {
+		out["closedDate"] = pr.mergedAt.Format(time.RFC3339)
+		out["lastMergeCommit"] = map[string]any{"commitId": pr.mergeSHA}
+	}
+	return out
+}
+
+// lookupPullRequest returns the repository and pull request named by the
+// request path, answering 404 when either does not exist.
+func (s *AzureDevOpsServer) lookupPullRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+) (*repository, *pullRequest, bool) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	id, _ := strconv.Atoi(r.PathValue("id"))
+	pr, ok := repo.pullRequest(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf(
+			"TF401180: The requested pull request was not found: %s", r.PathValue("id"),
+		))
+	}
+	return repo, pr, ok
+}
+
+func (s *AzureDevOpsServer) listPullRequests(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	status := query.Get("searchCriteria.status")
+	if status == "" {
+		status = azureActive
+	}
+	var prs []map[string]any
+	for _, pr := range slices.Backward(repo.pullRequests) {
+		if status != "all" && status != azureStatus(pr) {
+			continue
+		}
+		if source := query.Get("searchCriteria.sourceRefName"); source != "" && source != "refs/heads/"+pr.source {
+			continue
+		}
+		if target := query.Get("searchCriteria.targetRefName"); target != "" && target != "refs/heads/"+pr.target {
+			continue
+		}
+		prs = append(prs, s.pullRequestJSON(repo, pr))
+	}
+	writeValues(w, prs)
+}
+
+func (s *AzureDevOpsServer) createPullRequest(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		SourceRefName string `json:"sourceRefName"`
+		TargetRefName string `json:"targetRefName"`
+		Title         string `json:"title"`
+		Description   string `json:"description"`
+		IsDraft       bool   `json:"isDraft"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	source := strings.TrimPrefix(body.SourceRefName, "refs/heads/")
+	target := strings.TrimPrefix(body.TargetRefName, "refs/heads/")
+	for _, pr := range repo.pullRequests {
+		if pr.state == stateOpen && pr.source == source && pr.target == target {
+			writeError(w, http.StatusConflict,
+				"TF401179: An active pull request for the source and target branch already exists.")
+			return
+		}
+	}
+
+	pr, err := repo.openPullRequest(body.Title, body.Description, source, target, s.user)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "TF401398: "+err.Error())
+		return
+	}
+	pr.draft = body.IsDraft
+	writeJSON(w, http.StatusCreated, s.pullRequestJSON(repo, pr))
+}
+
+func (s *AzureDevOpsServer) getPullRequest(w http.ResponseWriter, r *http.Request) {
+	if repo, pr, ok := s.lookupPullRequest(w, r); ok {
+		writeJSON(w, http.StatusOK, s.pullRequestJSON(repo, pr))
+	}
+}
+
+// azureMergeStrategies maps completionOptions.mergeStrategy to the fakes'
+// merge strategies; rebaseMerge (semi-linear) is recorded as a merge commit.
+var azureMergeStrategies = map[int]string{ //nolint:gochecknoglobals // read-only lookup table
+	1: mergeSquash,
+	2: mergeCommit,
+	3: mergeRebase,
+	4: mergeCommit,
+}
+
+func (s *AzureDevOpsServer) updatePullRequest(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title                 *string `json:"title"`
+		Description           *string `json:"description"`
+		Status                string  `json:"status"`
+		IsDraft               *bool   `json:"isDraft"`
+		LastMergeSourceCommit struct {
+			CommitID string `json:"commitId"`
+		} `json:"lastMergeSourceCommit"`
+		CompletionOptions struct {
+			MergeStrategy      int  `json:"mergeStrategy"`
+			DeleteSourceBranch bool `json:"deleteSourceBranch"`
+		} `json:"completionOptions"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Title != nil {
+		pr.title = *body.Title
+	}
+	if body.Description != nil {
+		pr.description = *body.Description
+	}
+	if body.IsDraft != nil {
+		pr.draft = *body.IsDraft
+	}
+
+	switch body.Status {
+	case "":
+	case azureAbandoned, azureActive:
+		if pr.state == stateMerged {
+			writeError(w, http.StatusBadRequest, "TF401181: The pull request cannot be edited due to its state.")
+			return
+		}
+		pr.state = stateClosed
+		if body.Status == azureActive {
+			pr.state = stateOpen
+		}
+	case azureCompleted:
+		if body.LastMergeSourceCommit.CommitID != repo.sourceHead(pr).sha {
+			writeError(w, http.StatusConflict,
+				"TF401192: The pull request source has been updated; lastMergeSourceCommit is out of date.")
+			return
+		}
+		strategy, known := azureMergeStrategies[body.CompletionOptions.MergeStrategy]
+		if !known {
+			strategy = mergeCommit
+		}
+		if err := repo.merge(pr, strategy, body.CompletionOptions.DeleteSourceBranch); err != nil {
+			writeError(w, http.StatusConflict, "TF401188: The pull request cannot be completed: "+err.Error())
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid pull request status %q", body.Status))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pullRequestJSON(repo, pr))
+}
+
+// listIterations reports a single iteration per pull request; the fake does
+// not track pushes to the source branch separately.
+func (s *AzureDevOpsServer) listIterations(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	writeValues(w, []map[string]any{{
+		"id":              1,
+		"sourceRefCommit": map[string]any{"commitId": repo.sourceHead(pr).sha},
+		"commonRefCommit": map[string]any{"commitId": repo.mergeBase(pr).sha},
+	}})
+}
+
+// azureChangeTypes renders file statuses as Azure DevOps change types.
+var azureChangeTypes = map[string]string{ //nolint:gochecknoglobals // read-only lookup table
+	fileAdded:    "add",
+	fileModified: "edit",
+	fileDeleted:  "delete",
+}
+
+func (s *AzureDevOpsServer) listIterationChanges(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	if r.PathValue("iteration") != "1" {
+		writeError(w, http.StatusNotFound, "TF401189: The pull request iteration was not found.")
+		return
+	}
+	changes := repo.changes(pr)
+	entries := make([]map[string]any, 0, len(changes))
+	for i, change := range changes {
+		entries = append(entries, map[string]any{
+			"changeTrackingId": i + 1,
+			"changeId":         i + 1,
+			"changeType":       azureChangeTypes[change.status],
+			"item":             map[string]any{"path": "/" + change.path, "objectId": blobSHA(change.newContent)},
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"changeEntries": entries})
+}
+
+func (s *AzureDevOpsServer) listPullRequestStatuses(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	var statuses []map[string]any
+	for i, status := range repo.statuses[repo.sourceHead(pr).sha] {
+		genre, name, found := strings.Cut(status.context, "/")
+		if !found {
+			genre, name = "", status.context
+		}
+		statuses = append(statuses, map[string]any{
+			"id":      i + 1,
+			"state":   azureStatuses[status.state],
+			"context": map[string]any{"name": name, "genre": genre},
+		})
+	}
+	writeValues(w, statuses)
+}
+
+// listPolicyEvaluations answers for a project without branch policies.
+func (s *AzureDevOpsServer) listPolicyEvaluations(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.project(r); !ok {
+		writeError(w, http.StatusNotFound, "TF200016: The following project does not exist")
+		return
+	}
+	writeValues(w, []map[string]any{})
+}
+
+// azureVotes maps reviewer votes to verdicts.
+var azureVotes = map[int]globalEntities.ReviewVerdict{ //nolint:gochecknoglobals // read-only lookup table
+	10:  globalEntities.ReviewVerdictApprove,
+	5:   globalEntities.ReviewVerdictApprove,
+	0:   globalEntities.ReviewVerdictComment,
+	-5:  globalEntities.ReviewVerdictWaitingForAuthor,
+	-10: globalEntities.ReviewVerdictRequestChanges,
+}
+
+func (s *AzureDevOpsServer) putReviewer(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Vote int `json:"vote"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	verdict, known := azureVotes[body.Vote]
+	if !known {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid vote %d", body.Vote))
+		return
+	}
+	s.addReview(pr, verdict, "")
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id": r.PathValue("reviewer"), "vote": body.Vote, "displayName": s.user,
+	})
+}
+
+// --- threads ---
+
+// azureThreadStatuses are the statuses a thread can be created or patched with.
+var azureThreadStatuses = []string{ //nolint:gochecknoglobals // read-only lookup table
+	"active", "fixed", "wontFix", "closed", "byDesign", "pending",
+}
+
+// threadComments returns the comments of the thread rooted at threadID.
+// Azure DevOps numbers comments per thread, starting at 1.
+func threadComments(pr *pullRequest, threadID int64) []*comment {
+	var out []*comment
+	for _, c := range pr.comments {
+		if c.threadID == threadID {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (s *AzureDevOpsServer) threadCommentJSON(c *comment, number, parent int) map[string]any {
+	return map[string]any{
+		"id":              number,
+		"parentCommentId": parent,
+		"content":         c.body,
+		"commentType":     "text",
+		"author":          map[string]any{"displayName": c.author, "uniqueName": c.author},
+	}
+}
+
+func (s *AzureDevOpsServer) threadJSON(pr *pullRequest, root *comment) map[string]any {
+	comments := threadComments(pr, root.id)
+	rendered := make([]map[string]any, 0, len(comments))
+	for i, c := range comments {
+		parent := 0
+		if c.inReplyTo != 0 {
+			parent = 1
+		}
+		rendered = append(rendered, s.threadCommentJSON(c, i+1, parent))
+	}
+	out := map[string]any{
+		"id":       root.id,
+		"status":   root.status,
+		"comments": rendered,
+	}
+	if root.path != "" {
+		out["threadContext"] = map[string]any{
+			"filePath":       root.path,
+			"rightFileStart": map[string]any{"line": root.line, "offset": 1},
+			"rightFileEnd":   map[string]any{"line": root.line, "offset": 1},
+		}
+	}
+	return out
+}
+
+// lookupThread returns the root comment of the thread named by the request
+// path, answering 404 when it does not exist.
+func (s *AzureDevOpsServer) lookupThread(w http.ResponseWriter, r *http.Request, pr *pullRequest) (*comment, bool) {
+	id, err := strconv.ParseInt(r.PathValue("thread"), 10, 64)
+	if err == nil {
+		if root, ok := pr.thread(id); ok && root.threadID == root.id {
+			return root, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "TF401181: The requested pull request comment thread was not found.")
+	return nil, false
+}
+
+func (s *AzureDevOpsServer) listThreads(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	var threads []map[string]any
+	for _, c := range pr.comments {
+		if c.threadID == c.id {
+			threads = append(threads, s.threadJSON(pr, c))
+		}
+	}
+	writeValues(w, threads)
+}
+
+func (s *AzureDevOpsServer) createThread(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Status   string `json:"status"`
+		Comments []struct {
+			Content string `json:"content"`
+		} `json:"comments"`
+		ThreadContext *struct {
+			FilePath       string `json:"filePath"`
+			RightFileStart struct {
+				Line int `json:"line"`
+			} `json:"rightFileStart"`
+		} `json:"threadContext"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if len(body.Comments) == 0 {
+		writeError(w, http.StatusBadRequest, "a thread must contain at least one comment")
+		return
+	}
+	if body.Status == "" {
+		body.Status = "active"
+	}
+	if !slices.Contains(azureThreadStatuses, body.Status) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid thread status %q", body.Status))
+		return
+	}
+
+	root := s.addComment(pr, &comment{body: body.Comments[0].Content, status: body.Status})
+	if context := body.ThreadContext; context != nil {
+		root.path, root.line = context.FilePath, context.RightFileStart.Line
+	}
+	for _, extra := range body.Comments[1:] {
+		s.addComment(pr, &comment{body: extra.Content, inReplyTo: root.id})
+	}
+	writeJSON(w, http.StatusOK, s.threadJSON(pr, root))
+}
+
+func (s *AzureDevOpsServer) updateThread(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	root, ok := s.lookupThread(w, r, pr)
+	if !ok {
+		return
+	}
+	var body struct {
+		Status string `json:"status"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if !slices.Contains(azureThreadStatuses, body.Status) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid thread status %q", body.Status))
+		return
+	}
+	root.status = body.Status
+	writeJSON(w, http.StatusOK, s.threadJSON(pr, root))
+}
+
+func (s *AzureDevOpsServer) createThreadComment(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPullRequest(w, r)
+	if !ok {
+		return
+	}
+	root, ok := s.lookupThread(w, r, pr)
+	if !ok {
+		return
+	}
+	var body struct {
+		Content string `json:"content"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	c := s.addComment(pr, &comment{body: body.Content, inReplyTo: root.id})
+	number := len(threadComments(pr, root.id))
+	writeJSON(w, http.StatusOK, s.threadCommentJSON(c, number, 1))
+}
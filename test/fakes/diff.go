@@ -0,0 +1,174 @@
+package fakes
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// File change statuses, named the way GitHub reports them.
+const (
+	fileAdded    = "added"
+	fileModified = "modified"
+	fileDeleted  = "deleted"
+)
+
+// fileMode is the mode every fake file is stored with.
+const fileMode = "100644"
+
+// fileChange is one file that differs between two snapshots.
+type fileChange struct {
+	path       string
+	status     string
+	oldContent string
+	newContent string
+}
+
+// diffFiles compares two file maps and returns the changed files sorted by path.
+func diffFiles(base, head map[string]string) []fileChange {
+	paths := make(map[string]bool)
+	for path := range base {
+		paths[path] = true
+	}
+	for path := range head {
+		paths[path] = true
+	}
+
+	var changes []fileChange
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		oldContent, inBase := base[path]
+		newContent, inHead := head[path]
+		switch {
+		case !inBase:
+			changes = append(changes, fileChange{path: path, status: fileAdded, newContent: newContent})
+		case !inHead:
+			changes = append(changes, fileChange{path: path, status: fileDeleted, oldContent: oldContent})
+		case oldContent != newContent:
+			changes = append(changes, fileChange{
+				path: path, status: fileModified, oldContent: oldContent, newContent: newContent,
+			})
+		}
+	}
+	return changes
+}
+
+// diffLine is one line of a hunk, prefixed with ' ', '-' or '+'.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// lines computes a line diff of the change through a longest common subsequence.
+func (c fileChange) lines() []diffLine {
+	oldLines := splitLines(c.oldContent)
+	newLines := splitLines(c.newContent)
+
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []diffLine
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			out = append(out, diffLine{op: ' ', text: oldLines[i]})
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || lcs[i][j+1] >= lcs[i+1][j]):
+			out = append(out, diffLine{op: '+', text: newLines[j]})
+			j++
+		default:
+			out = append(out, diffLine{op: '-', text: oldLines[i]})
+			i++
+		}
+	}
+	return out
+}
+
+// patch renders the change as a single hunk with full context, the form of
+// GitHub's "patch" and GitLab's "diff" fields.
+func (c fileChange) patch() string {
+	lines := c.lines()
+	var oldCount, newCount int
+	var body strings.Builder
+	for _, line := range lines {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+		body.WriteByte(line.op)
+		body.WriteString(line.text)
+		body.WriteByte('\n')
+	}
+
+	oldStart, newStart := 1, 1
+	if oldCount == 0 {
+		oldStart = 0
+	}
+	if newCount == 0 {
+		newStart = 0
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount) + body.String()
+}
+
+// gitDiff renders the change as a "diff --git" section.
+func (c fileChange) gitDiff() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "diff --git a/%s b/%s\n", c.path, c.path)
+	oldName, newName := "a/"+c.path, "b/"+c.path
+	switch c.status {
+	case fileAdded:
+		fmt.Fprintf(&sb, "new file mode %s\n", fileMode)
+		oldName = "/dev/null"
+	case fileDeleted:
+		fmt.Fprintf(&sb, "deleted file mode %s\n", fileMode)
+		newName = "/dev/null"
+	}
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	sb.WriteString(c.patch())
+	return sb.String()
+}
+
+// counts returns the number of added and deleted lines.
+func (c fileChange) counts() (int, int) {
+	var additions, deletions int
+	for _, line := range c.lines() {
+		switch line.op {
+		case '+':
+			additions++
+		case '-':
+			deletions++
+		}
+	}
+	return additions, deletions
+}
+
+// gitDiffs concatenates the "diff --git" sections of changes.
+func gitDiffs(changes []fileChange) string {
+	var sb strings.Builder
+	for _, change := range changes {
+		sb.WriteString(change.gitDiff())
+	}
+	return sb.String()
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
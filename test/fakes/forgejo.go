@@ -0,0 +1,959 @@
+package fakes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// forgejoDefaultMirrorInterval is the interval new pull mirrors get, as on a
+// default Forgejo instance.
+const forgejoDefaultMirrorInterval = 8 * time.Hour
+
+const (
+	forgejoAPI      = "/api/v1"
+	forgejoRepoPath = forgejoAPI + "/repos/{owner}/{repo}"
+	forgejoPullPath = forgejoRepoPath + "/pulls/{number}"
+)
+
+// ForgejoServer fakes the Forgejo (and Gitea) REST API under /api/v1, so
+// gitea.NewProvider(token, URL()) talks to it. Like Forgejo, it refuses to
+// let the authenticated user approve or request changes on their own pull
+// requests, and it requires the blob SHA to update or delete a file.
+type ForgejoServer struct {
+	*server
+}
+
+// NewForgejoServer starts a fake Forgejo server that stops when t ends.
+func NewForgejoServer(t *testing.T) *ForgejoServer {
+	t.Helper()
+
+	s := &ForgejoServer{server: newServer()}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+forgejoAPI+"/user", s.getUser)
+	mux.HandleFunc("GET "+forgejoAPI+"/orgs/{org}/repos", s.listOrgRepos)
+	mux.HandleFunc("GET "+forgejoAPI+"/users/{user}/repos", s.listUserRepos)
+	mux.HandleFunc("POST "+forgejoAPI+"/repos/migrate", s.migrate)
+	mux.HandleFunc("GET "+forgejoRepoPath, s.getRepo)
+	mux.HandleFunc("PATCH "+forgejoRepoPath, s.editRepo)
+	mux.HandleFunc("POST "+forgejoRepoPath+"/mirror-sync", s.mirrorSync)
+	mux.HandleFunc("POST "+forgejoRepoPath+"/convert", s.convert)
+
+	mux.HandleFunc("GET "+forgejoRepoPath+"/contents/{path...}", s.getContents)
+	mux.HandleFunc("POST "+forgejoRepoPath+"/contents/{path...}", s.createFile)
+	mux.HandleFunc("PUT "+forgejoRepoPath+"/contents/{path...}", s.updateFile)
+	mux.HandleFunc("DELETE "+forgejoRepoPath+"/contents/{path...}", s.deleteFile)
+	mux.HandleFunc("GET "+forgejoRepoPath+"/git/trees/{sha}", s.getTree)
+	mux.HandleFunc("GET "+forgejoRepoPath+"/tags", s.listTags)
+	mux.HandleFunc("POST "+forgejoRepoPath+"/branches", s.createBranch)
+	mux.HandleFunc("GET "+forgejoRepoPath+"/branches/{branch...}", s.getBranch)
+	mux.HandleFunc("GET "+forgejoRepoPath+"/commits/{ref}/status", s.getCombinedStatus)
+	mux.HandleFunc("POST "+forgejoRepoPath+"/statuses/{sha}", s.createStatus)
+
+	mux.HandleFunc("GET "+forgejoRepoPath+"/pulls", s.listPulls)
+	mux.HandleFunc("POST "+forgejoRepoPath+"/pulls", s.createPull)
+	mux.HandleFunc("GET "+forgejoPullPath, s.getPull)
+	mux.HandleFunc("PATCH "+forgejoPullPath, s.updatePull)
+	mux.HandleFunc("GET "+forgejoPullPath+"/files", s.listPullFiles)
+	mux.HandleFunc("POST "+forgejoPullPath+"/merge", s.mergePull)
+	mux.HandleFunc("GET "+forgejoPullPath+"/reviews", s.listReviews)
+	mux.HandleFunc("POST "+forgejoPullPath+"/reviews", s.createReview)
+	mux.HandleFunc("GET "+forgejoPullPath+"/reviews/{review}/comments", s.listReviewComments)
+	mux.HandleFunc("GET "+forgejoRepoPath+"/issues/{number}/comments", s.listIssueComments)
+	mux.HandleFunc("POST "+forgejoRepoPath+"/issues/{number}/comments", s.createIssueComment)
+
+	s.start(t, mux)
+	return s
+}
+
+// SeedRepository creates repository name in org with files committed on
+// "main" and returns it the way the gitea provider discovers it.
+func (s *ForgejoServer) SeedRepository(
+	t *testing.T,
+	org, name string,
+	files map[string]string,
+	tags ...string,
+) globalEntities.Repository {
+	t.Helper()
+
+	r := s.seed(t, org, name, files, tags...)
+	return globalEntities.Repository{
+		ID:            strconv.Itoa(r.id),
+		Name:          r.name,
+		Organization:  r.org,
+		DefaultBranch: "refs/heads/" + r.defaultBranch,
+		RemoteURL:     s.cloneURL(r),
+		SSHURL:        s.sshURL(r),
+		ProviderName:  "gitea",
+	}
+}
+
+// --- repositories ---
+
+func (s *ForgejoServer) repoJSON(r *repository) map[string]any {
+	interval := ""
+	if r.mirror {
+		interval = r.mirrorInterval.String()
+	}
+	return map[string]any{
+		"id":              r.id,
+		"name":            r.name,
+		"full_name":       r.org + "/" + r.name,
+		"owner":           map[string]any{"login": r.org},
+		"description":     r.description,
+		"default_branch":  r.defaultBranch,
+		"html_url":        fmt.Sprintf("%s/%s/%s", s.URL(), r.org, r.name),
+		"clone_url":       s.cloneURL(r),
+		"ssh_url":         s.sshURL(r),
+		"mirror":          r.mirror,
+		"mirror_interval": interval,
+		"mirror_updated":  r.lastSync,
+		"original_url":    r.importURL,
+		"fork":            false,
+		"archived":        false,
+		"private":         false,
+	}
+}
+
+func (s *ForgejoServer) writeRepos(w http.ResponseWriter, r *http.Request, repos []*repository) {
+	items, _ := page(r, repos, "limit")
+	out := make([]map[string]any, 0, len(items))
+	for _, repo := range items {
+		out = append(out, s.repoJSON(repo))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *ForgejoServer) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"id": 1, "login": s.user})
+}
+
+func (s *ForgejoServer) listOrgRepos(w http.ResponseWriter, r *http.Request) {
+	repos, ok := s.reposOf(r.PathValue("org"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "GetOrgByName")
+		return
+	}
+	s.writeRepos(w, r, repos)
+}
+
+func (s *ForgejoServer) listUserRepos(w http.ResponseWriter, r *http.Request) {
+	repos, _ := s.reposOf(r.PathValue("user"))
+	s.writeRepos(w, r, repos)
+}
+
+func (s *ForgejoServer) getRepo(w http.ResponseWriter, r *http.Request) {
+	if repo, ok := s.lookup(w, r); ok {
+		writeJSON(w, http.StatusOK, s.repoJSON(repo))
+	}
+}
+
+// migrate copies a repository served by this fake, named by clone_addr, into
+// a new repository. Sources on other hosts cannot be fetched and are rejected.
+func (s *ForgejoServer) migrate(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		CloneAddr   string `json:"clone_addr"`
+		RepoName    string `json:"repo_name"`
+		RepoOwner   string `json:"repo_owner"`
+		Mirror      bool   `json:"mirror"`
+		Description string `json:"description"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.RepoName == "" || body.RepoOwner == "" {
+		writeError(w, http.StatusUnprocessableEntity, "repo_name and repo_owner are required")
+		return
+	}
+
+	source, ok := s.importSource(body.CloneAddr)
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Invalid clone_addr: "+body.CloneAddr)
+		return
+	}
+	target, ok := s.addRepo(body.RepoOwner, body.RepoName)
+	if !ok {
+		writeError(w, http.StatusConflict, "The repository with the same name already exists.")
+		return
+	}
+
+	target.description = body.Description
+	target.importURL = redactURL(body.CloneAddr)
+	copyRepository(target, source)
+	if body.Mirror {
+		target.mirror = true
+		target.mirrorInterval = forgejoDefaultMirrorInterval
+		target.lastSync = time.Now().UTC()
+	}
+	writeJSON(w, http.StatusCreated, s.repoJSON(target))
+}
+
+// editRepo applies the repository settings the fake tracks. Like Forgejo, a
+// mirror_interval other than zero must be at least ten minutes.
+func (s *ForgejoServer) editRepo(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Description    *string `json:"description"`
+		MirrorInterval *string `json:"mirror_interval"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	if body.MirrorInterval != nil && repo.mirror {
+		interval, err := time.ParseDuration(*body.MirrorInterval)
+		if err != nil || (interval != 0 && interval < 10*time.Minute) {
+			writeError(w, http.StatusUnprocessableEntity, "Invalid mirror interval: "+*body.MirrorInterval)
+			return
+		}
+		repo.mirrorInterval = interval
+	}
+	if body.Description != nil {
+		repo.description = *body.Description
+	}
+	writeJSON(w, http.StatusOK, s.repoJSON(repo))
+}
+
+// mirrorSync syncs the pull mirror at once. Forgejo only queues the sync; the
+// fake runs it before answering.
+func (s *ForgejoServer) mirrorSync(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if !repo.mirror {
+		writeError(w, http.StatusBadRequest, "Repository is not a mirror")
+		return
+	}
+	s.syncMirror(repo)
+	w.WriteHeader(http.StatusOK)
+}
+
+// convert turns the pull mirror into a normal repository.
+func (s *ForgejoServer) convert(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if !repo.mirror {
+		writeError(w, http.StatusUnprocessableEntity, "Repository is not a mirror")
+		return
+	}
+	repo.mirror = false
+	repo.mirrorInterval = 0
+	writeJSON(w, http.StatusOK, s.repoJSON(repo))
+}
+
+// lookup returns the repository named by the request path, answering 404
+// when it does not exist.
+func (s *ForgejoServer) lookup(w http.ResponseWriter, r *http.Request) (*repository, bool) {
+	repo, ok := s.repo(r.PathValue("owner"), r.PathValue("repo"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "The target couldn't be found.")
+	}
+	return repo, ok
+}
+
+// --- contents ---
+
+func (s *ForgejoServer) getContents(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.resolve(r.URL.Query().Get("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "object does not exist")
+		return
+	}
+	path := strings.Trim(r.PathValue("path"), "/")
+	content, ok := c.files[path]
+	if !ok {
+		writeError(w, http.StatusNotFound, "object does not exist ["+path+"]")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.contentJSON(path, content))
+}
+
+func (s *ForgejoServer) contentJSON(path, content string) map[string]any {
+	return map[string]any{
+		"type":     "file",
+		"name":     path[strings.LastIndex(path, "/")+1:],
+		"path":     path,
+		"sha":      blobSHA(content),
+		"size":     len(content),
+		"encoding": "base64",
+		"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+	}
+}
+
+// fileRequest is the body of the create, update and delete file endpoints.
+type fileRequest struct {
+	Content string `json:"content"`
+	Message string `json:"message"`
+	Branch  string `json:"branch"`
+	SHA     string `json:"sha"`
+}
+
+// changeFile applies one file operation as a commit on the requested branch.
+// With requireSHA, the request must name the current blob of the file, as
+// Forgejo requires for updates and deletions.
+func (s *ForgejoServer) changeFile(
+	w http.ResponseWriter,
+	r *http.Request,
+	apply func(files map[string]string, path string, body fileRequest) (int, string),
+) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body fileRequest
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Branch == "" {
+		body.Branch = repo.defaultBranch
+	}
+	parent, err := repo.head(body.Branch)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "branch does not exist ["+body.Branch+"]")
+		return
+	}
+
+	path := strings.Trim(r.PathValue("path"), "/")
+	files := maps.Clone(parent.files)
+	if status, message := apply(files, path, body); status != 0 {
+		writeError(w, status, message)
+		return
+	}
+
+	if body.Message == "" {
+		body.Message = "Update " + path
+	}
+	c := repo.commit(body.Message, files, parent.sha)
+	repo.branches[strings.TrimPrefix(body.Branch, "refs/heads/")] = c.sha
+
+	status := http.StatusOK
+	if r.Method == http.MethodPost {
+		status = http.StatusCreated
+	}
+	out := map[string]any{"commit": map[string]any{"sha": c.sha, "message": c.message}, "content": nil}
+	if content, exists := files[path]; exists {
+		out["content"] = s.contentJSON(path, content)
+	}
+	writeJSON(w, status, out)
+}
+
+func decodeContent(body fileRequest) (string, bool) {
+	decoded, err := base64.StdEncoding.DecodeString(body.Content)
+	return string(decoded), err == nil
+}
+
+func (s *ForgejoServer) createFile(w http.ResponseWriter, r *http.Request) {
+	s.changeFile(w, r, func(files map[string]string, path string, body fileRequest) (int, string) {
+		if _, exists := files[path]; exists {
+			return http.StatusUnprocessableEntity, "repository file already exists [path: " + path + "]"
+		}
+		content, ok := decodeContent(body)
+		if !ok {
+			return http.StatusUnprocessableEntity, "content is not valid base64"
+		}
+		files[path] = content
+		return 0, ""
+	})
+}
+
+func (s *ForgejoServer) updateFile(w http.ResponseWriter, r *http.Request) {
+	s.changeFile(w, r, func(files map[string]string, path string, body fileRequest) (int, string) {
+		current, exists := files[path]
+		if !exists {
+			return http.StatusNotFound, "object does not exist [" + path + "]"
+		}
+		if body.SHA != blobSHA(current) {
+			return http.StatusUnprocessableEntity, "sha does not match [given: " + body.SHA + "]"
+		}
+		content, ok := decodeContent(body)
+		if !ok {
+			return http.StatusUnprocessableEntity, "content is not valid base64"
+		}
+		files[path] = content
+		return 0, ""
+	})
+}
+
+func (s *ForgejoServer) deleteFile(w http.ResponseWriter, r *http.Request) {
+	s.changeFile(w, r, func(files map[string]string, path string, body fileRequest) (int, string) {
+		current, exists := files[path]
+		if !exists {
+			return http.StatusNotFound, "object does not exist [" + path + "]"
+		}
+		if body.SHA == "" {
+			return http.StatusUnprocessableEntity, "[SHA]: Required"
+		}
+		if body.SHA != blobSHA(current) {
+			return http.StatusUnprocessableEntity, "sha does not match [given: " + body.SHA + "]"
+		}
+		delete(files, path)
+		return 0, ""
+	})
+}
+
+// --- git data ---
+
+func (s *ForgejoServer) getTree(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.resolve(r.PathValue("sha"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "sha not provided")
+		return
+	}
+	recursive := r.URL.Query().Get("recursive") == "true"
+	var entries []map[string]any
+	for _, entry := range treeEntries(c.files) {
+		if !recursive && strings.Contains(entry.path, "/") {
+			continue
+		}
+		mode, entryType := fileMode, "blob"
+		if entry.isDir {
+			mode, entryType = "040000", "tree"
+		}
+		entries = append(entries, map[string]any{
+			"path": entry.path, "mode": mode, "type": entryType, "sha": entry.sha,
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"sha": c.tree, "tree": entries, "truncated": false})
+}
+
+func (s *ForgejoServer) listTags(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	tags := make([]map[string]any, 0, len(repo.tags))
+	for _, name := range slices.Sorted(maps.Keys(repo.tags)) {
+		tags = append(tags, map[string]any{
+			"name": name, "id": repo.tags[name], "commit": map[string]any{"sha": repo.tags[name]},
+		})
+	}
+	items, _ := page(r, tags, "limit")
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *ForgejoServer) createBranch(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		NewBranchName string `json:"new_branch_name"`
+		OldBranchName string `json:"old_branch_name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.OldBranchName == "" {
+		body.OldBranchName = repo.defaultBranch
+	}
+	base, err := repo.head(body.OldBranchName)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "The old branch does not exist")
+		return
+	}
+	if err = repo.createBranch(body.NewBranchName, base.sha); err != nil {
+		writeError(w, http.StatusConflict, "The branch already exists.")
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"name":   body.NewBranchName,
+		"commit": map[string]any{"id": base.sha, "message": base.message},
+	})
+}
+
+func (s *ForgejoServer) getBranch(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	head, err := repo.head(r.PathValue("branch"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"name":                  r.PathValue("branch"),
+		"commit":                map[string]any{"id": head.sha, "message": head.message},
+		"protected":             false,
+		"enable_status_check":   false,
+		"status_check_contexts": []string{},
+	})
+}
+
+// --- statuses ---
+
+func (s *ForgejoServer) getCombinedStatus(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.resolve(r.PathValue("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "object does not exist")
+		return
+	}
+	state, count := repo.checkState(c.sha)
+	if count == 0 {
+		state = CheckPending
+	}
+	statuses := make([]map[string]any, 0, count)
+	for _, status := range repo.statuses[c.sha] {
+		statuses = append(statuses, map[string]any{"context": status.context, "status": string(status.state)})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sha": c.sha, "state": string(state), "total_count": count, "statuses": statuses,
+	})
+}
+
+func (s *ForgejoServer) createStatus(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		State   string `json:"state"`
+		Context string `json:"context"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	sha := r.PathValue("sha")
+	if _, found := repo.commits[sha]; !found {
+		writeError(w, http.StatusNotFound, "object does not exist ["+sha+"]")
+		return
+	}
+	if body.Context == "" {
+		body.Context = "default"
+	}
+
+	var state CheckState
+	switch body.State {
+	case "success":
+		state = CheckSuccess
+	case "pending", "warning":
+		state = CheckPending
+	case "failure", "error":
+		state = CheckFailure
+	default:
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid state %q", body.State))
+		return
+	}
+	repo.setStatus(sha, body.Context, state)
+	writeJSON(w, http.StatusCreated, map[string]any{"context": body.Context, "status": body.State})
+}
+
+// --- pull requests ---
+
+func (s *ForgejoServer) pullJSON(repo *repository, pr *pullRequest) map[string]any {
+	state := stateOpen
+	if pr.state != stateOpen {
+		state = stateClosed
+	}
+	var mergedAt any
+	if pr.state == stateMerged {
+		mergedAt = pr.mergedAt.Format(time.RFC3339)
+	}
+	var targetSHA string
+	if target, err := repo.head(pr.target); err == nil {
+		targetSHA = target.sha
+	}
+	return map[string]any{
+		"id":               pr.number,
+		"number":           pr.number,
+		"title":            pr.title,
+		"body":             pr.description,
+		"state":            state,
+		"draft":            pr.draft,
+		"merged":           pr.state == stateMerged,
+		"merged_at":        mergedAt,
+		"merge_commit_sha": pr.mergeSHA,
+		"created_at":       pr.createdAt.Format(time.RFC3339),
+		"html_url":         fmt.Sprintf("%s/%s/%s/pulls/%d", s.URL(), repo.org, repo.name, pr.number),
+		"user":             map[string]any{"login": pr.author},
+		"head": map[string]any{
+			"label": pr.source, "ref": pr.source, "sha": repo.sourceHead(pr).sha, "repo": s.repoJSON(repo),
+		},
+		"base": map[string]any{
+			"label": pr.target, "ref": pr.target, "sha": targetSHA, "repo": s.repoJSON(repo),
+		},
+	}
+}
+
+// lookupPull returns the repository and pull request named by the request
+// path, answering 404 when either does not exist.
+func (s *ForgejoServer) lookupPull(w http.ResponseWriter, r *http.Request) (*repository, *pullRequest, bool) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	number, _ := strconv.Atoi(strings.TrimSuffix(r.PathValue("number"), ".diff"))
+	pr, ok := repo.pullRequest(number)
+	if !ok {
+		writeError(w, http.StatusNotFound, "The target couldn't be found.")
+	}
+	return repo, pr, ok
+}
+
+func (s *ForgejoServer) listPulls(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	state := r.URL.Query().Get("state")
+	if state == "" {
+		state = stateOpen
+	}
+	var prs []map[string]any
+	for _, pr := range slices.Backward(repo.pullRequests) {
+		open := pr.state == stateOpen
+		if (state == stateOpen && !open) || (state == stateClosed && open) {
+			continue
+		}
+		prs = append(prs, s.pullJSON(repo, pr))
+	}
+	items, _ := page(r, prs, "limit")
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *ForgejoServer) createPull(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title string `json:"title"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+		Body  string `json:"body"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	for _, pr := range repo.pullRequests {
+		if pr.state == stateOpen && pr.source == body.Head && pr.target == body.Base {
+			writeError(w, http.StatusConflict, fmt.Sprintf(
+				"pull request already exists for these targets [id: %d, issue_id: %d, head_repo_id: %d, "+
+					"base_repo_id: %d, head_branch: %s, base_branch: %s]",
+				pr.number, pr.number, repo.id, repo.id, pr.source, pr.target,
+			))
+			return
+		}
+	}
+	pr, err := repo.openPullRequest(body.Title, body.Body, body.Head, body.Base, s.user)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	pr.draft = strings.HasPrefix(strings.ToUpper(body.Title), "WIP:")
+	writeJSON(w, http.StatusCreated, s.pullJSON(repo, pr))
+}
+
+// getPull answers GET /pulls/{number} and, when the number carries a ".diff"
+// suffix, the raw diff of the pull request.
+func (s *ForgejoServer) getPull(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	if strings.HasSuffix(r.PathValue("number"), ".diff") {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(gitDiffs(repo.changes(pr))))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pullJSON(repo, pr))
+}
+
+func (s *ForgejoServer) updatePull(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title *string `json:"title"`
+		Body  *string `json:"body"`
+		State *string `json:"state"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Title != nil {
+		pr.title = *body.Title
+	}
+	if body.Body != nil {
+		pr.description = *body.Body
+	}
+	if body.State != nil && pr.state != stateMerged {
+		switch *body.State {
+		case stateClosed:
+			pr.state = stateClosed
+		case stateOpen:
+			pr.state = stateOpen
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "state must be open or closed")
+			return
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.pullJSON(repo, pr))
+}
+
+// forgejoFileStatuses renders file statuses in Forgejo's vocabulary.
+var forgejoFileStatuses = map[string]string{ //nolint:gochecknoglobals // read-only lookup table
+	fileAdded:    "added",
+	fileModified: "changed",
+	fileDeleted:  "deleted",
+}
+
+func (s *ForgejoServer) listPullFiles(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var files []map[string]any
+	for _, change := range repo.changes(pr) {
+		additions, deletions := change.counts()
+		files = append(files, map[string]any{
+			"filename":  change.path,
+			"status":    forgejoFileStatuses[change.status],
+			"additions": additions,
+			"deletions": deletions,
+			"changes":   additions + deletions,
+		})
+	}
+	items, _ := page(r, files, "limit")
+	writeJSON(w, http.StatusOK, items)
+}
+
+// forgejoMergeStyles maps the merge "Do" field to the fakes' merge strategies;
+// rebase-merge (rebase, then merge commit) is recorded as a merge commit.
+var forgejoMergeStyles = map[string]string{ //nolint:gochecknoglobals // read-only lookup table
+	"merge":             mergeCommit,
+	"rebase":            mergeRebase,
+	"rebase-merge":      mergeCommit,
+	"squash":            mergeSquash,
+	"fast-forward-only": mergeFastForward,
+}
+
+func (s *ForgejoServer) mergePull(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Do                     string `json:"Do"`
+		DeleteBranchAfterMerge bool   `json:"delete_branch_after_merge"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	strategy, known := forgejoMergeStyles[body.Do]
+	if !known {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("[Do]: invalid merge style %q", body.Do))
+		return
+	}
+	if pr.state != stateOpen {
+		writeError(w, http.StatusMethodNotAllowed, "The PR is already merged or closed")
+		return
+	}
+	if err := repo.merge(pr, strategy, body.DeleteBranchAfterMerge); err != nil {
+		writeError(w, http.StatusConflict, "Merge conflict: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// --- comments and reviews ---
+
+// forgejoReviewEvents maps review events to verdicts.
+var forgejoReviewEvents = map[string]globalEntities.ReviewVerdict{ //nolint:gochecknoglobals // read-only lookup table
+	"APPROVED":        globalEntities.ReviewVerdictApprove,
+	"REQUEST_CHANGES": globalEntities.ReviewVerdictRequestChanges,
+	"COMMENT":         globalEntities.ReviewVerdictComment,
+}
+
+// forgejoReviewStates renders verdicts as Forgejo review states.
+var forgejoReviewStates = map[globalEntities.ReviewVerdict]string{ //nolint:gochecknoglobals // read-only lookup table
+	globalEntities.ReviewVerdictApprove:        "APPROVED",
+	globalEntities.ReviewVerdictRequestChanges: "REQUEST_CHANGES",
+	globalEntities.ReviewVerdictComment:        "COMMENT",
+}
+
+func (s *ForgejoServer) reviewJSON(pr *pullRequest, rv *review) map[string]any {
+	var count int
+	for _, c := range pr.comments {
+		if c.reviewID == rv.id {
+			count++
+		}
+	}
+	return map[string]any{
+		"id":             rv.id,
+		"body":           rv.body,
+		"state":          forgejoReviewStates[rv.verdict],
+		"user":           map[string]any{"login": rv.author},
+		"comments_count": count,
+		"submitted_at":   time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *ForgejoServer) listReviews(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	reviews := make([]map[string]any, 0, len(pr.reviews))
+	for _, rv := range pr.reviews {
+		reviews = append(reviews, s.reviewJSON(pr, rv))
+	}
+	items, _ := page(r, reviews, "limit")
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *ForgejoServer) createReview(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Event    string `json:"event"`
+		Body     string `json:"body"`
+		Comments []struct {
+			Path        string `json:"path"`
+			Body        string `json:"body"`
+			NewPosition int    `json:"new_position"`
+			OldPosition int    `json:"old_position"`
+		} `json:"comments"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	verdict, known := forgejoReviewEvents[body.Event]
+	if !known {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("unknown review event %q", body.Event))
+		return
+	}
+	if verdict != globalEntities.ReviewVerdictComment && pr.author == s.user {
+		action := "approve"
+		if verdict == globalEntities.ReviewVerdictRequestChanges {
+			action = "reject"
+		}
+		writeError(w, http.StatusUnprocessableEntity, action+" your own pull is not allowed")
+		return
+	}
+	if verdict != globalEntities.ReviewVerdictApprove && body.Body == "" && len(body.Comments) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "review event "+body.Event+" requires a body or a comment")
+		return
+	}
+
+	rv := s.addReview(pr, verdict, body.Body)
+	for _, draft := range body.Comments {
+		line := draft.NewPosition
+		if line == 0 {
+			line = -draft.OldPosition
+		}
+		c := &comment{reviewID: rv.id, body: draft.Body, path: draft.Path, line: line}
+		for _, existing := range pr.comments {
+			if existing.path == c.path && existing.line == c.line && existing.threadID == existing.id {
+				c.inReplyTo = existing.id
+				break
+			}
+		}
+		s.addComment(pr, c)
+	}
+	writeJSON(w, http.StatusOK, s.reviewJSON(pr, rv))
+}
+
+func (s *ForgejoServer) listReviewComments(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	reviewID, _ := strconv.ParseInt(r.PathValue("review"), 10, 64)
+	if !slices.ContainsFunc(pr.reviews, func(rv *review) bool { return rv.id == reviewID }) {
+		writeError(w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	var comments []map[string]any
+	for _, c := range pr.comments {
+		if c.reviewID != reviewID {
+			continue
+		}
+		position, originalPosition := c.line, 0
+		if c.line < 0 {
+			position, originalPosition = 0, -c.line
+		}
+		comments = append(comments, map[string]any{
+			"id":                     c.id,
+			"body":                   c.body,
+			"user":                   map[string]any{"login": c.author},
+			"path":                   c.path,
+			"position":               position,
+			"original_position":      originalPosition,
+			"pull_request_review_id": reviewID,
+		})
+	}
+	writeJSON(w, http.StatusOK, comments)
+}
+
+func (s *ForgejoServer) issueCommentJSON(c *comment) map[string]any {
+	return map[string]any{
+		"id":         c.id,
+		"body":       c.body,
+		"user":       map[string]any{"login": c.author},
+		"created_at": time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func (s *ForgejoServer) listIssueComments(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	comments := []map[string]any{}
+	for _, c := range pr.comments {
+		if c.path == "" {
+			comments = append(comments, s.issueCommentJSON(c))
+		}
+	}
+	writeJSON(w, http.StatusOK, comments)
+}
+
+func (s *ForgejoServer) createIssueComment(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Body == "" {
+		writeError(w, http.StatusUnprocessableEntity, "[Body]: Required")
+		return
+	}
+	c := s.addComment(pr, &comment{body: body.Body})
+	writeJSON(w, http.StatusCreated, s.issueCommentJSON(c))
+}
@@ -0,0 +1,927 @@
+package fakes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	githubAPI      = "/api/v3"
+	githubRepoPath = githubAPI + "/repos/{owner}/{repo}"
+	githubDiffType = "application/vnd.github.v3.diff"
+)
+
+// GitHubServer fakes the GitHub REST API under /api/v3, the layout GitHub
+// Enterprise Server uses, so github.NewEnterpriseProvider(token, URL(), "")
+// talks to it.
+type GitHubServer struct {
+	*server
+}
+
+// NewGitHubServer starts a fake GitHub server that stops when t ends.
+func NewGitHubServer(t *testing.T) *GitHubServer {
+	t.Helper()
+
+	s := &GitHubServer{server: newServer()}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+githubAPI+"/user", s.getUser)
+	mux.HandleFunc("GET "+githubAPI+"/user/repos", s.listUserRepos)
+	mux.HandleFunc("GET "+githubAPI+"/users/{user}/repos", s.listUserRepos)
+	mux.HandleFunc("GET "+githubAPI+"/orgs/{org}/repos", s.listOrgRepos)
+	mux.HandleFunc("GET "+githubRepoPath, s.getRepo)
+
+	mux.HandleFunc("GET "+githubRepoPath+"/contents/{path...}", s.getContents)
+	mux.HandleFunc("GET "+githubRepoPath+"/tags", s.listTags)
+	mux.HandleFunc("GET "+githubRepoPath+"/git/trees/{sha...}", s.getTree)
+	mux.HandleFunc("POST "+githubRepoPath+"/git/trees", s.createTree)
+	mux.HandleFunc("GET "+githubRepoPath+"/git/commits/{sha}", s.getCommit)
+	mux.HandleFunc("POST "+githubRepoPath+"/git/commits", s.createCommit)
+	mux.HandleFunc("GET "+githubRepoPath+"/git/ref/{ref...}", s.getRef)
+	mux.HandleFunc("POST "+githubRepoPath+"/git/refs", s.createRef)
+	mux.HandleFunc("DELETE "+githubRepoPath+"/git/refs/{ref...}", s.deleteRef)
+
+	mux.HandleFunc("GET "+githubRepoPath+"/pulls", s.listPulls)
+	mux.HandleFunc("POST "+githubRepoPath+"/pulls", s.createPull)
+	mux.HandleFunc("GET "+githubRepoPath+"/pulls/{number}", s.getPull)
+	mux.HandleFunc("PATCH "+githubRepoPath+"/pulls/{number}", s.updatePull)
+	mux.HandleFunc("GET "+githubRepoPath+"/pulls/{number}/files", s.listPullFiles)
+	mux.HandleFunc("PUT "+githubRepoPath+"/pulls/{number}/merge", s.mergePull)
+	mux.HandleFunc("GET "+githubRepoPath+"/pulls/{number}/comments", s.listReviewComments)
+	mux.HandleFunc("POST "+githubRepoPath+"/pulls/{number}/comments", s.createReviewComment)
+	mux.HandleFunc("GET "+githubRepoPath+"/pulls/{number}/reviews", s.listReviews)
+	mux.HandleFunc("POST "+githubRepoPath+"/pulls/{number}/reviews", s.createReview)
+	mux.HandleFunc("GET "+githubRepoPath+"/issues/{number}/comments", s.listIssueComments)
+	mux.HandleFunc("POST "+githubRepoPath+"/issues/{number}/comments", s.createIssueComment)
+
+	mux.HandleFunc("GET "+githubRepoPath+"/commits/{ref}/status", s.getCombinedStatus)
+	mux.HandleFunc("GET "+githubRepoPath+"/commits/{ref}/check-suites", s.listCheckSuites)
+	mux.HandleFunc("GET "+githubRepoPath+"/commits/{ref}/check-runs", s.listCheckRuns)
+	mux.HandleFunc("GET "+githubRepoPath+"/branches/{branch...}", s.getBranch)
+	mux.HandleFunc("POST "+githubRepoPath+"/statuses/{sha}", s.createStatus)
+
+	s.start(t, mux)
+	return s
+}
+
+// SeedRepository creates repository name in org with files committed on
+// "main" and returns it the way the github provider discovers it.
+func (s *GitHubServer) SeedRepository(
+	t *testing.T,
+	org, name string,
+	files map[string]string,
+	tags ...string,
+) globalEntities.Repository {
+	t.Helper()
+
+	r := s.seed(t, org, name, files, tags...)
+	return globalEntities.Repository{
+		ID:            strconv.Itoa(r.id),
+		Name:          r.name,
+		Organization:  r.org,
+		DefaultBranch: "refs/heads/" + r.defaultBranch,
+		RemoteURL:     s.cloneURL(r),
+		SSHURL:        s.sshURL(r),
+		ProviderName:  "github",
+	}
+}
+
+// --- repositories ---
+
+func (s *GitHubServer) repoJSON(r *repository) map[string]any {
+	return map[string]any{
+		"id":             r.id,
+		"name":           r.name,
+		"full_name":      r.org + "/" + r.name,
+		"owner":          map[string]any{"login": r.org},
+		"description":    r.description,
+		"default_branch": r.defaultBranch,
+		"html_url":       fmt.Sprintf("%s/%s/%s", s.URL(), r.org, r.name),
+		"clone_url":      s.cloneURL(r),
+		"ssh_url":        s.sshURL(r),
+		"fork":           false,
+		"archived":       false,
+		"private":        false,
+	}
+}
+
+func (s *GitHubServer) writeRepos(w http.ResponseWriter, r *http.Request, repos []*repository) {
+	items, next := page(r, repos, "per_page")
+	out := make([]map[string]any, 0, len(items))
+	for _, repo := range items {
+		out = append(out, s.repoJSON(repo))
+	}
+	if next != 0 {
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageURL(r, next)))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *GitHubServer) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"login": s.user, "type": "User"})
+}
+
+func (s *GitHubServer) listOrgRepos(w http.ResponseWriter, r *http.Request) {
+	repos, ok := s.reposOf(r.PathValue("org"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	s.writeRepos(w, r, repos)
+}
+
+func (s *GitHubServer) listUserRepos(w http.ResponseWriter, r *http.Request) {
+	user := r.PathValue("user")
+	if user == "" {
+		user = s.user
+	}
+	repos, _ := s.reposOf(user)
+	s.writeRepos(w, r, repos)
+}
+
+func (s *GitHubServer) getRepo(w http.ResponseWriter, r *http.Request) {
+	if repo, ok := s.lookup(w, r); ok {
+		writeJSON(w, http.StatusOK, s.repoJSON(repo))
+	}
+}
+
+// lookup returns the repository named by the request path, answering 404
+// when it does not exist.
+func (s *GitHubServer) lookup(w http.ResponseWriter, r *http.Request) (*repository, bool) {
+	repo, ok := s.repo(r.PathValue("owner"), r.PathValue("repo"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return repo, ok
+}
+
+// --- contents and git data ---
+
+func (s *GitHubServer) getContents(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.resolve(r.URL.Query().Get("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "No commit found for the ref "+r.URL.Query().Get("ref"))
+		return
+	}
+
+	path := strings.Trim(r.PathValue("path"), "/")
+	if content, isFile := c.files[path]; isFile {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"type":     "file",
+			"name":     path[strings.LastIndex(path, "/")+1:],
+			"path":     path,
+			"sha":      blobSHA(content),
+			"size":     len(content),
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+		return
+	}
+
+	var listing []map[string]any
+	for _, entry := range treeEntries(c.files) {
+		if parentDir(entry.path) != path {
+			continue
+		}
+		entryType := "file"
+		if entry.isDir {
+			entryType = "dir"
+		}
+		listing = append(listing, map[string]any{"type": entryType, "path": entry.path, "sha": entry.sha})
+	}
+	if len(listing) == 0 {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, listing)
+}
+
+func (s *GitHubServer) listTags(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	tags := make([]map[string]any, 0, len(repo.tags))
+	for _, name := range slices.Sorted(maps.Keys(repo.tags)) {
+		tags = append(tags, map[string]any{"name": name, "commit": map[string]any{"sha": repo.tags[name]}})
+	}
+	items, next := page(r, tags, "per_page")
+	if next != 0 {
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageURL(r, next)))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *GitHubServer) getTree(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	sha := r.PathValue("sha")
+	files, ok := repo.trees[sha]
+	if !ok {
+		c, found := repo.resolve(sha)
+		if !found {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		sha, files = c.tree, c.files
+	}
+	writeJSON(w, http.StatusOK, s.treeJSON(sha, files, r.URL.Query().Get("recursive") != ""))
+}
+
+func (s *GitHubServer) treeJSON(sha string, files map[string]string, recursive bool) map[string]any {
+	entries := make([]map[string]any, 0, len(files))
+	for _, entry := range treeEntries(files) {
+		if !recursive && strings.Contains(entry.path, "/") {
+			continue
+		}
+		mode, entryType := fileMode, "blob"
+		if entry.isDir {
+			mode, entryType = "040000", "tree"
+		}
+		entries = append(entries, map[string]any{
+			"path": entry.path, "mode": mode, "type": entryType, "sha": entry.sha,
+		})
+	}
+	return map[string]any{"sha": sha, "tree": entries, "truncated": false}
+}
+
+func (s *GitHubServer) createTree(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		BaseTree string           `json:"base_tree"`
+		Tree     []map[string]any `json:"tree"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	files := make(map[string]string)
+	if body.BaseTree != "" {
+		base, found := repo.trees[body.BaseTree]
+		if !found {
+			writeError(w, http.StatusUnprocessableEntity, "base_tree is not a valid tree")
+			return
+		}
+		maps.Copy(files, base)
+	}
+	for _, entry := range body.Tree {
+		path, _ := entry["path"].(string)
+		sha, hasSHA := entry["sha"]
+		content, hasContent := entry["content"].(string)
+		switch {
+		case hasContent:
+			files[path] = content
+		case hasSHA && sha == nil:
+			delete(files, path)
+		default:
+			blob, found := findBlob(repo, fmt.Sprint(sha))
+			if !found {
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("tree.sha %v is not a valid blob", sha))
+				return
+			}
+			files[path] = blob
+		}
+	}
+
+	sha := repo.storeTree(files)
+	writeJSON(w, http.StatusCreated, s.treeJSON(sha, files, true))
+}
+
+func (s *GitHubServer) commitJSON(c *commit) map[string]any {
+	parents := make([]map[string]any, 0, len(c.parents))
+	for _, parent := range c.parents {
+		parents = append(parents, map[string]any{"sha": parent})
+	}
+	return map[string]any{
+		"sha":     c.sha,
+		"message": c.message,
+		"tree":    map[string]any{"sha": c.tree},
+		"parents": parents,
+		"author":  map[string]any{"name": s.user, "email": s.user + "@localhost"},
+	}
+}
+
+func (s *GitHubServer) getCommit(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.commits[r.PathValue("sha")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.commitJSON(c))
+}
+
+func (s *GitHubServer) createCommit(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	files, ok := repo.trees[body.Tree]
+	if !ok {
+		writeError(w, http.StatusUnprocessableEntity, "Tree SHA does not exist")
+		return
+	}
+	for _, parent := range body.Parents {
+		if _, found := repo.commits[parent]; !found {
+			writeError(w, http.StatusUnprocessableEntity, "Parent SHA does not exist or is not a commit object")
+			return
+		}
+	}
+	c := repo.commit(body.Message, maps.Clone(files), body.Parents...)
+	writeJSON(w, http.StatusCreated, s.commitJSON(c))
+}
+
+func (s *GitHubServer) getRef(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	ref := r.PathValue("ref")
+	var sha string
+	switch {
+	case strings.HasPrefix(ref, "heads/"):
+		sha, ok = repo.branches[strings.TrimPrefix(ref, "heads/")]
+	case strings.HasPrefix(ref, "tags/"):
+		sha, ok = repo.tags[strings.TrimPrefix(ref, "tags/")]
+	default:
+		ok = false
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+	writeJSON(w, http.StatusOK, refJSON("refs/"+ref, sha))
+}
+
+func refJSON(ref, sha string) map[string]any {
+	return map[string]any{"ref": ref, "object": map[string]any{"sha": sha, "type": "commit"}}
+}
+
+func (s *GitHubServer) createRef(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if _, found := repo.commits[body.SHA]; !found {
+		writeError(w, http.StatusUnprocessableEntity, "Object does not exist")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(body.Ref, "refs/heads/"):
+		if err := repo.createBranch(body.Ref, body.SHA); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Reference already exists")
+			return
+		}
+	case strings.HasPrefix(body.Ref, "refs/tags/"):
+		tag := strings.TrimPrefix(body.Ref, "refs/tags/")
+		if _, exists := repo.tags[tag]; exists {
+			writeError(w, http.StatusUnprocessableEntity, "Reference already exists")
+			return
+		}
+		repo.tags[tag] = body.SHA
+	default:
+		writeError(w, http.StatusUnprocessableEntity, "Reference name must start with 'refs/heads/' or 'refs/tags/'")
+		return
+	}
+	writeJSON(w, http.StatusCreated, refJSON(body.Ref, body.SHA))
+}
+
+func (s *GitHubServer) deleteRef(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	ref := r.PathValue("ref")
+	refs := repo.branches
+	name := strings.TrimPrefix(ref, "heads/")
+	if strings.HasPrefix(ref, "tags/") {
+		refs, name = repo.tags, strings.TrimPrefix(ref, "tags/")
+	}
+	if _, exists := refs[name]; !exists {
+		writeError(w, http.StatusUnprocessableEntity, "Reference does not exist")
+		return
+	}
+	delete(refs, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// --- pull requests ---
+
+func (s *GitHubServer) pullJSON(repo *repository, pr *pullRequest) map[string]any {
+	state := stateOpen
+	if pr.state != stateOpen {
+		state = stateClosed
+	}
+	var mergedAt any
+	if pr.state == stateMerged {
+		mergedAt = pr.mergedAt.Format(time.RFC3339)
+	}
+	branchJSON := func(ref, sha string) map[string]any {
+		return map[string]any{
+			"ref":   ref,
+			"sha":   sha,
+			"label": repo.org + ":" + ref,
+			"repo":  s.repoJSON(repo),
+		}
+	}
+	var targetSHA string
+	if target, err := repo.head(pr.target); err == nil {
+		targetSHA = target.sha
+	}
+	return map[string]any{
+		"id":               pr.number,
+		"number":           pr.number,
+		"title":            pr.title,
+		"body":             pr.description,
+		"state":            state,
+		"draft":            pr.draft,
+		"merged":           pr.state == stateMerged,
+		"merged_at":        mergedAt,
+		"merge_commit_sha": pr.mergeSHA,
+		"created_at":       pr.createdAt.Format(time.RFC3339),
+		"html_url":         fmt.Sprintf("%s/%s/%s/pull/%d", s.URL(), repo.org, repo.name, pr.number),
+		"user":             map[string]any{"login": pr.author},
+		"head":             branchJSON(pr.source, repo.sourceHead(pr).sha),
+		"base":             branchJSON(pr.target, targetSHA),
+	}
+}
+
+// lookupPull returns the repository and pull request named by the request
+// path, answering 404 when either does not exist.
+func (s *GitHubServer) lookupPull(w http.ResponseWriter, r *http.Request) (*repository, *pullRequest, bool) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	number, _ := strconv.Atoi(r.PathValue("number"))
+	pr, ok := repo.pullRequest(number)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+	return repo, pr, ok
+}
+
+func (s *GitHubServer) listPulls(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	state := query.Get("state")
+	if state == "" {
+		state = stateOpen
+	}
+	_, head, hasOwner := strings.Cut(query.Get("head"), ":")
+	if !hasOwner {
+		head = query.Get("head")
+	}
+
+	var prs []map[string]any
+	for _, pr := range repo.pullRequests {
+		open := pr.state == stateOpen
+		if (state == stateOpen && !open) || (state == stateClosed && open) {
+			continue
+		}
+		if head != "" && pr.source != head {
+			continue
+		}
+		if base := query.Get("base"); base != "" && pr.target != base {
+			continue
+		}
+		prs = append(prs, s.pullJSON(repo, pr))
+	}
+	items, next := page(r, prs, "per_page")
+	if next != 0 {
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageURL(r, next)))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *GitHubServer) createPull(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title string `json:"title"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+		Body  string `json:"body"`
+		Draft bool   `json:"draft"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if _, head, hasOwner := strings.Cut(body.Head, ":"); hasOwner {
+		body.Head = head
+	}
+
+	pr, err := repo.openPullRequest(body.Title, body.Body, body.Head, body.Base, s.user)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed: "+err.Error())
+		return
+	}
+	pr.draft = body.Draft
+	writeJSON(w, http.StatusCreated, s.pullJSON(repo, pr))
+}
+
+func (s *GitHubServer) getPull(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	if r.Header.Get("Accept") == githubDiffType {
+		w.Header().Set("Content-Type", githubDiffType)
+		_, _ = w.Write([]byte(gitDiffs(repo.changes(pr))))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pullJSON(repo, pr))
+}
+
+func (s *GitHubServer) updatePull(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title *string `json:"title"`
+		Body  *string `json:"body"`
+		State *string `json:"state"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Title != nil {
+		pr.title = *body.Title
+	}
+	if body.Body != nil {
+		pr.description = *body.Body
+	}
+	if body.State != nil && pr.state != stateMerged {
+		switch *body.State {
+		case stateClosed:
+			pr.state = stateClosed
+		case stateOpen:
+			pr.state = stateOpen
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "state must be open or closed")
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, s.pullJSON(repo, pr))
+}
+
+func (s *GitHubServer) listPullFiles(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var files []map[string]any
+	for _, change := range repo.changes(pr) {
+		additions, deletions := change.counts()
+		status := change.status
+		if status == fileDeleted {
+			status = "removed"
+		}
+		files = append(files, map[string]any{
+			"sha":       blobSHA(change.newContent),
+			"filename":  change.path,
+			"status":    status,
+			"additions": additions,
+			"deletions": deletions,
+			"changes":   additions + deletions,
+			"patch":     change.patch(),
+		})
+	}
+	items, next := page(r, files, "per_page")
+	if next != 0 {
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageURL(r, next)))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *GitHubServer) mergePull(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		MergeMethod string `json:"merge_method"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	strategy := body.MergeMethod
+	if strategy == "" {
+		strategy = mergeCommit
+	}
+	if err := repo.merge(pr, strategy, false); err != nil {
+		writeError(w, http.StatusMethodNotAllowed, "Pull Request is not mergeable: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sha":     pr.mergeSHA,
+		"merged":  true,
+		"message": "Pull Request successfully merged",
+	})
+}
+
+// --- comments and reviews ---
+
+func (s *GitHubServer) commentJSON(c *comment) map[string]any {
+	out := map[string]any{
+		"id":         c.id,
+		"body":       c.body,
+		"user":       map[string]any{"login": c.author},
+		"created_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	if c.path != "" {
+		out["path"] = c.path
+		out["line"] = c.line
+		out["pull_request_review_id"] = c.reviewID
+		if c.inReplyTo != 0 {
+			out["in_reply_to_id"] = c.inReplyTo
+		}
+	}
+	return out
+}
+
+func (s *GitHubServer) listIssueComments(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var comments []map[string]any
+	for _, c := range pr.comments {
+		if c.path == "" {
+			comments = append(comments, s.commentJSON(c))
+		}
+	}
+	items, next := page(r, comments, "per_page")
+	if next != 0 {
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageURL(r, next)))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *GitHubServer) createIssueComment(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	c := s.addComment(pr, &comment{body: body.Body})
+	writeJSON(w, http.StatusCreated, s.commentJSON(c))
+}
+
+func (s *GitHubServer) listReviewComments(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var comments []map[string]any
+	for _, c := range pr.comments {
+		if c.path != "" {
+			comments = append(comments, s.commentJSON(c))
+		}
+	}
+	items, next := page(r, comments, "per_page")
+	if next != 0 {
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", pageURL(r, next)))
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+func (s *GitHubServer) createReviewComment(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body      string `json:"body"`
+		Path      string `json:"path"`
+		Line      int    `json:"line"`
+		InReplyTo int64  `json:"in_reply_to"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.InReplyTo != 0 {
+		if _, found := pr.thread(body.InReplyTo); !found {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+	} else if body.Path == "" || body.Line == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "path and line are required")
+		return
+	}
+	c := s.addComment(pr, &comment{body: body.Body, path: body.Path, line: body.Line, inReplyTo: body.InReplyTo})
+	writeJSON(w, http.StatusCreated, s.commentJSON(c))
+}
+
+// githubReviewEvents maps review events to verdicts.
+var githubReviewEvents = map[string]globalEntities.ReviewVerdict{ //nolint:gochecknoglobals // read-only lookup table
+	"APPROVE":         globalEntities.ReviewVerdictApprove,
+	"REQUEST_CHANGES": globalEntities.ReviewVerdictRequestChanges,
+	"COMMENT":         globalEntities.ReviewVerdictComment,
+}
+
+func (s *GitHubServer) listReviews(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	reviews := make([]map[string]any, 0, len(pr.reviews))
+	for _, rv := range pr.reviews {
+		state := "COMMENTED"
+		switch rv.verdict {
+		case globalEntities.ReviewVerdictApprove:
+			state = "APPROVED"
+		case globalEntities.ReviewVerdictRequestChanges:
+			state = "CHANGES_REQUESTED"
+		case globalEntities.ReviewVerdictComment, globalEntities.ReviewVerdictWaitingForAuthor:
+		}
+		reviews = append(reviews, map[string]any{
+			"id": rv.id, "state": state, "body": rv.body, "user": map[string]any{"login": rv.author},
+		})
+	}
+	writeJSON(w, http.StatusOK, reviews)
+}
+
+func (s *GitHubServer) createReview(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupPull(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Event    string `json:"event"`
+		Body     string `json:"body"`
+		Comments []struct {
+			Path string `json:"path"`
+			Line int    `json:"line"`
+			Body string `json:"body"`
+		} `json:"comments"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	verdict, known := githubReviewEvents[body.Event]
+	if !known {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("unknown review event %q", body.Event))
+		return
+	}
+	if verdict != globalEntities.ReviewVerdictApprove && body.Body == "" && len(body.Comments) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "Unprocessable Entity: body is required for this event")
+		return
+	}
+
+	rv := s.addReview(pr, verdict, body.Body)
+	for _, draft := range body.Comments {
+		s.addComment(pr, &comment{reviewID: rv.id, body: draft.Body, path: draft.Path, line: draft.Line})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"id": rv.id, "body": rv.body, "state": body.Event})
+}
+
+// --- statuses ---
+
+func (s *GitHubServer) getCombinedStatus(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.resolve(r.PathValue("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "No commit found for SHA: "+r.PathValue("ref"))
+		return
+	}
+	state, count := repo.checkState(c.sha)
+	if count == 0 {
+		state = CheckPending
+	}
+	statuses := make([]map[string]any, 0, count)
+	for _, status := range repo.statuses[c.sha] {
+		statuses = append(statuses, map[string]any{"context": status.context, "state": string(status.state)})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sha": c.sha, "state": string(state), "total_count": count, "statuses": statuses,
+	})
+}
+
+func (s *GitHubServer) listCheckSuites(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookup(w, r); ok {
+		writeJSON(w, http.StatusOK, map[string]any{"total_count": 0, "check_suites": []any{}})
+	}
+}
+
+func (s *GitHubServer) listCheckRuns(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.lookup(w, r); ok {
+		writeJSON(w, http.StatusOK, map[string]any{"total_count": 0, "check_runs": []any{}})
+	}
+}
+
+// getBranch reports every branch as unprotected.
+func (s *GitHubServer) getBranch(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	head, err := repo.head(r.PathValue("branch"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, "Branch not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"name":       r.PathValue("branch"),
+		"commit":     map[string]any{"sha": head.sha},
+		"protected":  false,
+		"protection": map[string]any{"enabled": false},
+	})
+}
+
+func (s *GitHubServer) createStatus(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		State   string `json:"state"`
+		Context string `json:"context"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	sha := r.PathValue("sha")
+	if _, found := repo.commits[sha]; !found {
+		writeError(w, http.StatusUnprocessableEntity, "No commit found for SHA: "+sha)
+		return
+	}
+	if body.Context == "" {
+		body.Context = "default"
+	}
+	state := CheckState(body.State)
+	switch state {
+	case CheckSuccess, CheckPending, CheckFailure:
+	default:
+		if body.State != "error" {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid state %q", body.State))
+			return
+		}
+		state = CheckFailure
+	}
+	repo.setStatus(sha, body.Context, state)
+	writeJSON(w, http.StatusCreated, map[string]any{"context": body.Context, "state": body.State})
+}
+
+// findBlob returns the content of the blob with the given SHA from any commit.
+func findBlob(repo *repository, sha string) (string, bool) {
+	for _, c := range repo.commits {
+		for _, content := range c.files {
+			if blobSHA(content) == sha {
+				return content, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,1009 @@
+package fakes
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+const (
+	gitlabAPI          = "/api/v4"
+	gitlabProjectPath  = gitlabAPI + "/projects/{pid}"
+	gitlabMergeRequest = gitlabProjectPath + "/merge_requests/{iid}"
+)
+
+// GitLab merge request states.
+const (
+	gitlabOpened = "opened"
+	gitlabClosed = "closed"
+	gitlabMerged = "merged"
+)
+
+// GitLabServer fakes the GitLab REST API under /api/v4, so
+// gitlab.NewSelfManagedProvider(token, URL(), 0) talks to it. Projects are
+// addressed by numeric ID or by their URL-encoded "org/name" path.
+type GitLabServer struct {
+	*server
+
+	namespaceIDs map[string]int // namespace path -> ID, assigned on first lookup
+}
+
+// NewGitLabServer starts a fake GitLab server that stops when t ends.
+func NewGitLabServer(t *testing.T) *GitLabServer {
+	t.Helper()
+
+	s := &GitLabServer{server: newServer(), namespaceIDs: make(map[string]int)}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET "+gitlabAPI+"/user", s.getUser)
+	mux.HandleFunc("GET "+gitlabAPI+"/projects", s.listProjects)
+	mux.HandleFunc("POST "+gitlabAPI+"/projects", s.createProject)
+	mux.HandleFunc("GET "+gitlabAPI+"/namespaces/{id}", s.getNamespace)
+	mux.HandleFunc("GET "+gitlabAPI+"/groups/{group}/projects", s.listGroupProjects)
+	mux.HandleFunc("GET "+gitlabProjectPath, s.getProject)
+	mux.HandleFunc("GET "+gitlabProjectPath+"/mirror/pull", s.getPullMirror)
+	mux.HandleFunc("POST "+gitlabProjectPath+"/mirror/pull", s.startPullMirror)
+	mux.HandleFunc("PUT "+gitlabProjectPath+"/mirror/pull", s.configurePullMirror)
+
+	mux.HandleFunc("GET "+gitlabProjectPath+"/repository/files/{path}/raw", s.getRawFile)
+	mux.HandleFunc("GET "+gitlabProjectPath+"/repository/tree", s.listTree)
+	mux.HandleFunc("GET "+gitlabProjectPath+"/repository/tags", s.listTags)
+	mux.HandleFunc("POST "+gitlabProjectPath+"/repository/branches", s.createBranch)
+	mux.HandleFunc("POST "+gitlabProjectPath+"/repository/commits", s.createCommit)
+	mux.HandleFunc("POST "+gitlabProjectPath+"/statuses/{sha}", s.createStatus)
+
+	mux.HandleFunc("GET "+gitlabProjectPath+"/merge_requests", s.listMergeRequests)
+	mux.HandleFunc("POST "+gitlabProjectPath+"/merge_requests", s.createMergeRequest)
+	mux.HandleFunc("GET "+gitlabMergeRequest, s.getMergeRequest)
+	mux.HandleFunc("PUT "+gitlabMergeRequest, s.updateMergeRequest)
+	mux.HandleFunc("GET "+gitlabMergeRequest+"/diffs", s.listDiffs)
+	mux.HandleFunc("PUT "+gitlabMergeRequest+"/merge", s.acceptMergeRequest)
+	mux.HandleFunc("POST "+gitlabMergeRequest+"/approve", s.approve)
+	mux.HandleFunc("POST "+gitlabMergeRequest+"/unapprove", s.unapprove)
+	mux.HandleFunc("POST "+gitlabMergeRequest+"/notes", s.createNote)
+	mux.HandleFunc("GET "+gitlabMergeRequest+"/discussions", s.listDiscussions)
+	mux.HandleFunc("POST "+gitlabMergeRequest+"/discussions", s.createDiscussion)
+	mux.HandleFunc("PUT "+gitlabMergeRequest+"/discussions/{discussion}", s.resolveDiscussion)
+	mux.HandleFunc("POST "+gitlabMergeRequest+"/discussions/{discussion}/notes", s.addDiscussionNote)
+
+	s.start(t, mux)
+	return s
+}
+
+// SeedRepository creates project name in group org with files committed on
+// "main" and returns it the way the gitlab provider discovers it.
+func (s *GitLabServer) SeedRepository(
+	t *testing.T,
+	org, name string,
+	files map[string]string,
+	tags ...string,
+) globalEntities.Repository {
+	t.Helper()
+
+	r := s.seed(t, org, name, files, tags...)
+	return globalEntities.Repository{
+		ID:            strconv.Itoa(r.id),
+		Name:          r.name,
+		Organization:  r.org,
+		DefaultBranch: "refs/heads/" + r.defaultBranch,
+		RemoteURL:     s.cloneURL(r),
+		SSHURL:        s.sshURL(r),
+		ProviderName:  "gitlab",
+	}
+}
+
+// --- projects ---
+
+func (s *GitLabServer) projectJSON(r *repository) map[string]any {
+	return map[string]any{
+		"id":                  r.id,
+		"name":                r.name,
+		"path":                r.name,
+		"path_with_namespace": r.org + "/" + r.name,
+		"namespace":           map[string]any{"path": r.org, "full_path": r.org},
+		"description":         r.description,
+		"default_branch":      r.defaultBranch,
+		"web_url":             fmt.Sprintf("%s/%s/%s", s.URL(), r.org, r.name),
+		"http_url_to_repo":    s.cloneURL(r),
+		"ssh_url_to_repo":     s.sshURL(r),
+		"mirror":              r.mirror,
+		"import_url":          r.importURL,
+		"import_status":       gitlabImportStatus(r),
+		"import_error":        r.importError,
+		"archived":            false,
+	}
+}
+
+// gitlabImportStatus returns the import_status of r. Imports run while the
+// project is created, so they are reported as finished or failed at once.
+func gitlabImportStatus(r *repository) string {
+	switch {
+	case r.importURL == "":
+		return "none"
+	case r.importError != "":
+		return "failed"
+	default:
+		return "finished"
+	}
+}
+
+// writePage writes one page of items with GitLab's X-Page/X-Next-Page headers.
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	pageItems, next := page(r, items, "per_page")
+	current := r.URL.Query().Get("page")
+	if current == "" {
+		current = "1"
+	}
+	w.Header().Set("X-Page", current)
+	w.Header().Set("X-Total", strconv.Itoa(len(items)))
+	if next != 0 {
+		w.Header().Set("X-Next-Page", strconv.Itoa(next))
+	} else {
+		w.Header().Set("X-Next-Page", "")
+	}
+	writeJSON(w, http.StatusOK, pageItems)
+}
+
+func (s *GitLabServer) writeProjects(w http.ResponseWriter, r *http.Request, repos []*repository) {
+	out := make([]map[string]any, 0, len(repos))
+	for _, repo := range repos {
+		out = append(out, s.projectJSON(repo))
+	}
+	writePage(w, r, out)
+}
+
+func (s *GitLabServer) getUser(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"id": 1, "username": s.user})
+}
+
+func (s *GitLabServer) listGroupProjects(w http.ResponseWriter, r *http.Request) {
+	repos, ok := s.reposOf(r.PathValue("group"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 Group Not Found")
+		return
+	}
+	s.writeProjects(w, r, repos)
+}
+
+func (s *GitLabServer) listProjects(w http.ResponseWriter, r *http.Request) {
+	repos := s.allRepos()
+	if r.URL.Query().Get("owned") == "true" {
+		repos, _ = s.reposOf(s.user)
+	}
+	s.writeProjects(w, r, repos)
+}
+
+func (s *GitLabServer) getProject(w http.ResponseWriter, r *http.Request) {
+	if repo, ok := s.lookup(w, r); ok {
+		writeJSON(w, http.StatusOK, s.projectJSON(repo))
+	}
+}
+
+// namespace returns the ID of namespace path, which exists when it is the
+// user's or owns projects.
+func (s *GitLabServer) namespace(path string) (int, bool) {
+	if _, ok := s.reposOf(path); !ok && path != s.user {
+		return 0, false
+	}
+	if _, ok := s.namespaceIDs[path]; !ok {
+		s.namespaceIDs[path] = len(s.namespaceIDs) + 1
+	}
+	return s.namespaceIDs[path], true
+}
+
+func (s *GitLabServer) getNamespace(w http.ResponseWriter, r *http.Request) {
+	identifier := r.PathValue("id")
+	for path, id := range s.namespaceIDs {
+		if strconv.Itoa(id) == identifier {
+			identifier = path
+		}
+	}
+	id, ok := s.namespace(identifier)
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 Namespace Not Found")
+		return
+	}
+	kind := "group"
+	if identifier == s.user {
+		kind = "user"
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id": id, "name": identifier, "path": identifier, "full_path": identifier, "kind": kind,
+	})
+}
+
+// createProject creates an empty project, or one imported from import_url
+// when that points at a project of this server. An import_url anywhere else
+// leaves the project with a failed import, as an unreachable source would.
+func (s *GitLabServer) createProject(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name        string `json:"name"`
+		Path        string `json:"path"`
+		NamespaceID int    `json:"namespace_id"`
+		Description string `json:"description"`
+		ImportURL   string `json:"import_url"`
+		Mirror      bool   `json:"mirror"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Path == "" {
+		body.Path = body.Name
+	}
+
+	namespace := s.user
+	if body.NamespaceID != 0 {
+		namespace = ""
+		for path, id := range s.namespaceIDs {
+			if id == body.NamespaceID {
+				namespace = path
+			}
+		}
+		if namespace == "" {
+			writeError(w, http.StatusNotFound, "404 Namespace Not Found")
+			return
+		}
+	}
+
+	repo, ok := s.addRepo(namespace, body.Path)
+	if !ok {
+		writeError(w, http.StatusBadRequest, "path has already been taken")
+		return
+	}
+	repo.description = body.Description
+	repo.mirror = body.Mirror
+	if body.ImportURL != "" {
+		repo.importURL = redactURL(body.ImportURL)
+		if source, found := s.importSource(body.ImportURL); found {
+			copyRepository(repo, source)
+			if repo.mirror {
+				repo.lastSync = time.Now().UTC()
+			}
+		} else {
+			repo.importError = "Unable to access repository " + repo.importURL
+		}
+	}
+	writeJSON(w, http.StatusCreated, s.projectJSON(repo))
+}
+
+// pullMirrorJSON returns the pull mirror details of r.
+func pullMirrorJSON(r *repository) map[string]any {
+	details := map[string]any{
+		"id":            r.id,
+		"url":           r.importURL,
+		"enabled":       r.mirror,
+		"update_status": "finished",
+		"last_error":    r.lastSyncError,
+	}
+	if r.lastSyncError != "" {
+		details["update_status"] = "failed"
+	}
+	if !r.lastSync.IsZero() {
+		details["last_successful_update_at"] = r.lastSync
+	}
+	return details
+}
+
+// getPullMirror answers the pull mirror details, or 400 like GitLab when the
+// project was never set up as a pull mirror.
+func (s *GitLabServer) getPullMirror(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if repo.importURL == "" {
+		writeError(w, http.StatusBadRequest, "Pull mirroring is not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, pullMirrorJSON(repo))
+}
+
+// startPullMirror syncs the pull mirror at once. GitLab only schedules the
+// sync; the fake runs it before answering.
+func (s *GitLabServer) startPullMirror(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	if !repo.mirror {
+		writeError(w, http.StatusBadRequest, "Pull mirroring is not enabled")
+		return
+	}
+	s.syncMirror(repo)
+	w.WriteHeader(http.StatusOK)
+}
+
+// configurePullMirror applies the enabled flag; disabling it turns the
+// project into a normal one.
+func (s *GitLabServer) configurePullMirror(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Enabled != nil {
+		if *body.Enabled && repo.importURL == "" {
+			writeError(w, http.StatusBadRequest, "url is missing")
+			return
+		}
+		repo.mirror = *body.Enabled
+	}
+	writeJSON(w, http.StatusOK, pullMirrorJSON(repo))
+}
+
+// lookup returns the project named by the request path, by numeric ID or by
+// "org/name", answering 404 when it does not exist.
+func (s *GitLabServer) lookup(w http.ResponseWriter, r *http.Request) (*repository, bool) {
+	pid := r.PathValue("pid")
+	if id, err := strconv.Atoi(pid); err == nil {
+		for _, repo := range s.repos {
+			if repo.id == id {
+				return repo, true
+			}
+		}
+	} else if org, name, found := strings.Cut(pid, "/"); found {
+		if repo, ok := s.repo(org, name); ok {
+			return repo, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "404 Project Not Found")
+	return nil, false
+}
+
+// --- repository ---
+
+func (s *GitLabServer) getRawFile(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	c, ok := repo.resolve(r.URL.Query().Get("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 Commit Not Found")
+		return
+	}
+	content, ok := c.files[r.PathValue("path")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 File Not Found")
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(content))
+}
+
+func (s *GitLabServer) listTree(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	c, ok := repo.resolve(query.Get("ref"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 Tree Not Found")
+		return
+	}
+	dir := strings.Trim(query.Get("path"), "/")
+	recursive := query.Get("recursive") == "true"
+
+	var nodes []map[string]any
+	for _, entry := range treeEntries(c.files) {
+		if dir != "" && !strings.HasPrefix(entry.path, dir+"/") {
+			continue
+		}
+		if !recursive && parentDir(entry.path) != dir {
+			continue
+		}
+		mode, nodeType := fileMode, "blob"
+		if entry.isDir {
+			mode, nodeType = "040000", "tree"
+		}
+		nodes = append(nodes, map[string]any{
+			"id":   entry.sha,
+			"name": entry.path[strings.LastIndex(entry.path, "/")+1:],
+			"type": nodeType,
+			"path": entry.path,
+			"mode": mode,
+		})
+	}
+	writePage(w, r, nodes)
+}
+
+func (s *GitLabServer) listTags(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	tags := make([]map[string]any, 0, len(repo.tags))
+	for _, name := range slices.Sorted(maps.Keys(repo.tags)) {
+		sha := repo.tags[name]
+		tags = append(tags, map[string]any{
+			"name":   name,
+			"target": sha,
+			"commit": map[string]any{"id": sha, "message": repo.commits[sha].message},
+		})
+	}
+	writePage(w, r, tags)
+}
+
+func (s *GitLabServer) branchJSON(repo *repository, name string) map[string]any {
+	sha := repo.branches[name]
+	return map[string]any{
+		"name":    name,
+		"default": name == repo.defaultBranch,
+		"commit":  map[string]any{"id": sha, "message": repo.commits[sha].message},
+	}
+}
+
+func (s *GitLabServer) createBranch(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Branch string `json:"branch"`
+		Ref    string `json:"ref"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	c, ok := repo.resolve(body.Ref)
+	if !ok || body.Ref == "" {
+		writeError(w, http.StatusBadRequest, "Invalid reference name: "+body.Ref)
+		return
+	}
+	if err := repo.createBranch(body.Branch, c.sha); err != nil {
+		writeError(w, http.StatusBadRequest, "Branch already exists")
+		return
+	}
+	writeJSON(w, http.StatusCreated, s.branchJSON(repo, body.Branch))
+}
+
+func (s *GitLabServer) createCommit(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Branch        string `json:"branch"`
+		StartBranch   string `json:"start_branch"`
+		CommitMessage string `json:"commit_message"`
+		Actions       []struct {
+			Action   string `json:"action"`
+			FilePath string `json:"file_path"`
+			Content  string `json:"content"`
+		} `json:"actions"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+
+	start := body.Branch
+	if body.StartBranch != "" {
+		start = body.StartBranch
+	}
+	parent, err := repo.head(start)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "You can only create or edit files when you are on a branch")
+		return
+	}
+
+	files := maps.Clone(parent.files)
+	for _, action := range body.Actions {
+		_, exists := files[action.FilePath]
+		switch action.Action {
+		case "create":
+			if exists {
+				writeError(w, http.StatusBadRequest, "A file with this name already exists")
+				return
+			}
+			files[action.FilePath] = action.Content
+		case "update":
+			if !exists {
+				writeError(w, http.StatusBadRequest, "A file with this name doesn't exist")
+				return
+			}
+			files[action.FilePath] = action.Content
+		case "delete":
+			if !exists {
+				writeError(w, http.StatusBadRequest, "A file with this name doesn't exist")
+				return
+			}
+			delete(files, action.FilePath)
+		default:
+			writeError(w, http.StatusBadRequest, "actions[action] does not have a valid value")
+			return
+		}
+	}
+
+	c := repo.commit(body.CommitMessage, files, parent.sha)
+	repo.branches[body.Branch] = c.sha
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"id":         c.sha,
+		"short_id":   c.sha[:8],
+		"title":      c.message,
+		"message":    c.message,
+		"parent_ids": c.parents,
+	})
+}
+
+// gitlabStatuses renders commit states in GitLab's pipeline vocabulary.
+var gitlabStatuses = map[CheckState]string{ //nolint:gochecknoglobals // read-only lookup table
+	CheckSuccess: "success",
+	CheckPending: "pending",
+	CheckFailure: "failed",
+}
+
+func (s *GitLabServer) createStatus(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		State string `json:"state"`
+		Name  string `json:"name"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	sha := r.PathValue("sha")
+	if _, found := repo.commits[sha]; !found {
+		writeError(w, http.StatusNotFound, "404 References Not Found")
+		return
+	}
+	if body.Name == "" {
+		body.Name = "default"
+	}
+
+	var state CheckState
+	switch body.State {
+	case "success":
+		state = CheckSuccess
+	case "pending", "running":
+		state = CheckPending
+	case "failed", "canceled":
+		state = CheckFailure
+	default:
+		writeError(w, http.StatusBadRequest, "state does not have a valid value")
+		return
+	}
+	repo.setStatus(sha, body.Name, state)
+	writeJSON(w, http.StatusCreated, map[string]any{"name": body.Name, "status": body.State, "sha": sha})
+}
+
+// --- merge requests ---
+
+func gitlabState(pr *pullRequest) string {
+	switch pr.state {
+	case stateMerged:
+		return gitlabMerged
+	case stateClosed:
+		return gitlabClosed
+	}
+	return gitlabOpened
+}
+
+func (s *GitLabServer) mergeRequestJSON(repo *repository, pr *pullRequest) map[string]any {
+	head := repo.sourceHead(pr)
+	base := repo.mergeBase(pr)
+
+	var pipeline any
+	if state, count := repo.checkState(head.sha); count > 0 {
+		pipeline = map[string]any{"id": head.sha[:8], "sha": head.sha, "status": gitlabStatuses[state]}
+	}
+	var mergedAt any
+	if pr.state == stateMerged {
+		mergedAt = pr.mergedAt.Format(time.RFC3339)
+	}
+	return map[string]any{
+		"id":                         pr.number,
+		"iid":                        pr.number,
+		"project_id":                 repo.id,
+		"title":                      pr.title,
+		"description":                pr.description,
+		"state":                      gitlabState(pr),
+		"draft":                      pr.draft,
+		"source_branch":              pr.source,
+		"target_branch":              pr.target,
+		"sha":                        head.sha,
+		"merge_commit_sha":           pr.mergeSHA,
+		"force_remove_source_branch": pr.removeSourceBranch,
+		"created_at":                 pr.createdAt.Format(time.RFC3339),
+		"merged_at":                  mergedAt,
+		"web_url":                    fmt.Sprintf("%s/%s/%s/-/merge_requests/%d", s.URL(), repo.org, repo.name, pr.number),
+		"author":                     map[string]any{"username": pr.author},
+		"head_pipeline":              pipeline,
+		"diff_refs": map[string]any{
+			"base_sha":  base.sha,
+			"start_sha": base.sha,
+			"head_sha":  head.sha,
+		},
+	}
+}
+
+// lookupMergeRequest returns the project and merge request named by the
+// request path, answering 404 when either does not exist.
+func (s *GitLabServer) lookupMergeRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+) (*repository, *pullRequest, bool) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	iid, _ := strconv.Atoi(r.PathValue("iid"))
+	pr, ok := repo.pullRequest(iid)
+	if !ok {
+		writeError(w, http.StatusNotFound, "404 Not found")
+	}
+	return repo, pr, ok
+}
+
+func (s *GitLabServer) listMergeRequests(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	query := r.URL.Query()
+	mrs := make([]map[string]any, 0, len(repo.pullRequests))
+	for _, pr := range slices.Backward(repo.pullRequests) {
+		if state := query.Get("state"); state != "" && state != "all" && state != gitlabState(pr) {
+			continue
+		}
+		if source := query.Get("source_branch"); source != "" && source != pr.source {
+			continue
+		}
+		if target := query.Get("target_branch"); target != "" && target != pr.target {
+			continue
+		}
+		mrs = append(mrs, s.mergeRequestJSON(repo, pr))
+	}
+	writePage(w, r, mrs)
+}
+
+func (s *GitLabServer) createMergeRequest(w http.ResponseWriter, r *http.Request) {
+	repo, ok := s.lookup(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title              string `json:"title"`
+		Description        string `json:"description"`
+		SourceBranch       string `json:"source_branch"`
+		TargetBranch       string `json:"target_branch"`
+		RemoveSourceBranch bool   `json:"remove_source_branch"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	for _, pr := range repo.pullRequests {
+		if pr.state == stateOpen && pr.source == body.SourceBranch && pr.target == body.TargetBranch {
+			writeError(w, http.StatusConflict, fmt.Sprintf(
+				"Another open merge request already exists for this source branch: !%d", pr.number,
+			))
+			return
+		}
+	}
+
+	pr, err := repo.openPullRequest(body.Title, body.Description, body.SourceBranch, body.TargetBranch, s.user)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pr.removeSourceBranch = body.RemoveSourceBranch
+	pr.draft = strings.HasPrefix(body.Title, "Draft:")
+	writeJSON(w, http.StatusCreated, s.mergeRequestJSON(repo, pr))
+}
+
+func (s *GitLabServer) getMergeRequest(w http.ResponseWriter, r *http.Request) {
+	if repo, pr, ok := s.lookupMergeRequest(w, r); ok {
+		writeJSON(w, http.StatusOK, s.mergeRequestJSON(repo, pr))
+	}
+}
+
+func (s *GitLabServer) updateMergeRequest(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Title       *string `json:"title"`
+		Description *string `json:"description"`
+		StateEvent  string  `json:"state_event"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Title != nil {
+		pr.title = *body.Title
+	}
+	if body.Description != nil {
+		pr.description = *body.Description
+	}
+	if pr.state != stateMerged {
+		switch body.StateEvent {
+		case "close":
+			pr.state = stateClosed
+		case "reopen":
+			pr.state = stateOpen
+		case "":
+		default:
+			writeError(w, http.StatusBadRequest, "state_event does not have a valid value")
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, s.mergeRequestJSON(repo, pr))
+}
+
+func (s *GitLabServer) listDiffs(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	changes := repo.changes(pr)
+	diffs := make([]map[string]any, 0, len(changes))
+	for _, change := range changes {
+		aMode, bMode := fileMode, fileMode
+		switch change.status {
+		case fileAdded:
+			aMode = "0"
+		case fileDeleted:
+			bMode = "0"
+		}
+		diffs = append(diffs, map[string]any{
+			"old_path":     change.path,
+			"new_path":     change.path,
+			"a_mode":       aMode,
+			"b_mode":       bMode,
+			"diff":         change.patch(),
+			"new_file":     change.status == fileAdded,
+			"renamed_file": false,
+			"deleted_file": change.status == fileDeleted,
+		})
+	}
+	writePage(w, r, diffs)
+}
+
+func (s *GitLabServer) acceptMergeRequest(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Squash                   bool `json:"squash"`
+		ShouldRemoveSourceBranch bool `json:"should_remove_source_branch"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if pr.state != stateOpen {
+		writeError(w, http.StatusMethodNotAllowed, "405 Method Not Allowed")
+		return
+	}
+	strategy := mergeCommit
+	if body.Squash {
+		strategy = mergeSquash
+	}
+	if err := repo.merge(pr, strategy, body.ShouldRemoveSourceBranch || pr.removeSourceBranch); err != nil {
+		writeError(w, http.StatusNotAcceptable, "Branch cannot be merged")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.mergeRequestJSON(repo, pr))
+}
+
+func (s *GitLabServer) approve(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	if s.approved(pr) {
+		writeError(w, http.StatusUnauthorized, "401 Unauthorized")
+		return
+	}
+	s.addReview(pr, globalEntities.ReviewVerdictApprove, "")
+	writeJSON(w, http.StatusCreated, map[string]any{"iid": pr.number, "approved": true})
+}
+
+func (s *GitLabServer) unapprove(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	if !s.approved(pr) {
+		writeError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	s.addReview(pr, globalEntities.ReviewVerdictRequestChanges, "")
+	w.WriteHeader(http.StatusCreated)
+}
+
+// approved reports whether the authenticated user's latest review approves.
+func (s *GitLabServer) approved(pr *pullRequest) bool {
+	for _, rv := range slices.Backward(pr.reviews) {
+		if rv.author == s.user {
+			return rv.verdict == globalEntities.ReviewVerdictApprove
+		}
+	}
+	return false
+}
+
+// --- notes and discussions ---
+
+// discussionID renders a thread ID as GitLab's opaque hexadecimal discussion ID.
+func discussionID(threadID int64) string {
+	return fmt.Sprintf("%040x", threadID)
+}
+
+func (s *GitLabServer) noteJSON(c *comment) map[string]any {
+	note := map[string]any{
+		"id":         c.id,
+		"type":       nil,
+		"body":       c.body,
+		"author":     map[string]any{"username": c.author},
+		"system":     false,
+		"resolvable": c.path != "",
+		"resolved":   c.status == "resolved",
+		"created_at": time.Now().UTC().Format(time.RFC3339),
+	}
+	if c.path != "" {
+		note["type"] = "DiffNote"
+		note["position"] = map[string]any{
+			"position_type": "text",
+			"new_path":      c.path,
+			"old_path":      c.path,
+			"new_line":      c.line,
+		}
+	}
+	return note
+}
+
+func (s *GitLabServer) discussionJSON(pr *pullRequest, threadID int64) map[string]any {
+	var notes []map[string]any
+	for _, c := range pr.comments {
+		if c.threadID == threadID {
+			notes = append(notes, s.noteJSON(c))
+		}
+	}
+	root, _ := pr.thread(threadID)
+	return map[string]any{
+		"id":              discussionID(threadID),
+		"individual_note": root.path == "" && root.status == "",
+		"notes":           notes,
+	}
+}
+
+// lookupDiscussion returns the root comment of the discussion named by the
+// request path, answering 404 when it does not exist.
+func (s *GitLabServer) lookupDiscussion(w http.ResponseWriter, pr *pullRequest, r *http.Request) (*comment, bool) {
+	threadID, err := strconv.ParseInt(r.PathValue("discussion"), 16, 64)
+	if err == nil {
+		if root, ok := pr.thread(threadID); ok && root.threadID == root.id {
+			return root, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "404 Discussion Not Found")
+	return nil, false
+}
+
+func (s *GitLabServer) createNote(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Body == "" {
+		writeError(w, http.StatusBadRequest, "body is missing")
+		return
+	}
+	c := s.addComment(pr, &comment{body: body.Body})
+	writeJSON(w, http.StatusCreated, s.noteJSON(c))
+}
+
+func (s *GitLabServer) listDiscussions(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	var discussions []map[string]any
+	for _, c := range pr.comments {
+		if c.threadID == c.id {
+			discussions = append(discussions, s.discussionJSON(pr, c.id))
+		}
+	}
+	writePage(w, r, discussions)
+}
+
+func (s *GitLabServer) createDiscussion(w http.ResponseWriter, r *http.Request) {
+	repo, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body     string `json:"body"`
+		Position *struct {
+			HeadSHA string `json:"head_sha"`
+			NewPath string `json:"new_path"`
+			NewLine int    `json:"new_line"`
+		} `json:"position"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if body.Body == "" {
+		writeError(w, http.StatusBadRequest, "body is missing")
+		return
+	}
+
+	// Discussions without a position are resolvable too, unlike plain notes.
+	c := &comment{body: body.Body, status: "unresolved"}
+	if position := body.Position; position != nil {
+		if position.HeadSHA != repo.sourceHead(pr).sha || position.NewPath == "" || position.NewLine <= 0 {
+			writeError(w, http.StatusBadRequest, "400 Bad request - Note {:line_code=>[\"can't be blank\"]}")
+			return
+		}
+		c.path, c.line = position.NewPath, position.NewLine
+	}
+	s.addComment(pr, c)
+	writeJSON(w, http.StatusCreated, s.discussionJSON(pr, c.threadID))
+}
+
+func (s *GitLabServer) addDiscussionNote(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	root, ok := s.lookupDiscussion(w, pr, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Body string `json:"body"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	c := s.addComment(pr, &comment{body: body.Body, inReplyTo: root.id})
+	writeJSON(w, http.StatusCreated, s.noteJSON(c))
+}
+
+func (s *GitLabServer) resolveDiscussion(w http.ResponseWriter, r *http.Request) {
+	_, pr, ok := s.lookupMergeRequest(w, r)
+	if !ok {
+		return
+	}
+	root, ok := s.lookupDiscussion(w, pr, r)
+	if !ok {
+		return
+	}
+	var body struct {
+		Resolved bool `json:"resolved"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	if root.path == "" && root.status == "" {
+		writeError(w, http.StatusBadRequest, "400 Bad request - discussion is not resolvable")
+		return
+	}
+	root.status = "unresolved"
+	if body.Resolved {
+		root.status = "resolved"
+	}
+	writeJSON(w, http.StatusOK, s.discussionJSON(pr, root.id))
+}
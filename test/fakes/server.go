@@ -0,0 +1,461 @@
+// Package fakes provides in-process fake forge servers for integration tests.
+// Each server speaks the REST API of one provider family (GitHub, GitLab,
+// Azure DevOps, or Forgejo/Gitea) on an httptest.Server and keeps
+// repositories, branches, files, pull requests, comments, reviews and commit
+// statuses in memory, so discovery, CreateBranchWithChanges, and pull request
+// and review flows can be exercised end to end with no network.
+//
+// A provider is pointed at its fake through its injectable base URL:
+//
+//	server := fakes.NewGitHubServer(t)
+//	provider, err := github.NewEnterpriseProvider("token", server.URL(), "")
+//
+// Every server implements conformance.Backend, so it can also back the
+// test/conformance suite.
+package fakes
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// User is the login every fake server reports as the authenticated user and
+// records as the author of pull requests, comments and reviews.
+const User = "fake-user"
+
+// defaultPageSize is the page size used when a list request does not set one.
+const defaultPageSize = 30
+
+// CheckState is the state of a commit status, in the fakes' own vocabulary;
+// each server renders it the way its API does.
+type CheckState string
+
+const (
+	// CheckSuccess is a passing check.
+	CheckSuccess CheckState = "success"
+
+	// CheckPending is a check that has not finished.
+	CheckPending CheckState = "pending"
+
+	// CheckFailure is a failing check.
+	CheckFailure CheckState = "failure"
+)
+
+// PullRequest is a snapshot of a pull request held by a fake server.
+type PullRequest struct {
+	Number       int
+	Title        string
+	Description  string
+	SourceBranch string
+	TargetBranch string
+	State        string // "open", "closed" or "merged"
+	Comments     []Comment
+	Reviews      []Review
+}
+
+// Comment is a snapshot of a pull request comment. Inline comments carry a
+// Path and Line; replies share the ThreadID of the comment they answer.
+type Comment struct {
+	ID       int64
+	ThreadID int64
+	Author   string
+	Body     string
+	Path     string
+	Line     int
+	Status   string
+}
+
+// Review is a snapshot of a submitted review.
+type Review struct {
+	Author  string
+	Verdict globalEntities.ReviewVerdict
+	Body    string
+}
+
+// server is the state and Backend behaviour shared by every fake API.
+type server struct {
+	mu         sync.Mutex
+	httpServer *httptest.Server
+	user       string
+	repos      map[string]*repository // "org/name" -> repository
+	nextRepoID int
+	nextID     int64
+}
+
+func newServer() *server {
+	return &server{user: User, repos: make(map[string]*repository)}
+}
+
+// start serves mux on a new httptest.Server, one request at a time, and stops
+// it when the test ends.
+func (s *server) start(t *testing.T, mux *http.ServeMux) {
+	t.Helper()
+
+	s.httpServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.httpServer.Close)
+}
+
+// URL returns the root URL of the fake server, e.g. "http://127.0.0.1:41234".
+func (s *server) URL() string {
+	return s.httpServer.URL
+}
+
+// cloneURL returns the HTTP clone URL of r, in the "{root}/{org}/{name}.git"
+// form GitHub, GitLab and Forgejo share.
+func (s *server) cloneURL(r *repository) string {
+	return fmt.Sprintf("%s/%s/%s.git", s.URL(), r.org, r.name)
+}
+
+// sshURL returns the scp-style SSH clone URL of r on the server's host.
+func (s *server) sshURL(r *repository) string {
+	host, _, _ := net.SplitHostPort(s.httpServer.Listener.Addr().String())
+	return fmt.Sprintf("git@%s:%s/%s.git", host, r.org, r.name)
+}
+
+// seed creates repository name in org with files committed on "main" and the
+// given tags pointing at that commit.
+func (s *server) seed(t *testing.T, org, name string, files map[string]string, tags ...string) *repository {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := org + "/" + name
+	if _, exists := s.repos[key]; exists {
+		t.Fatalf("fake repository %q already exists", key)
+	}
+
+	s.nextRepoID++
+	repo := newRepository(s.nextRepoID, org, name)
+	initial := repo.commit("Initial commit", maps.Clone(files))
+	repo.branches[repo.defaultBranch] = initial.sha
+	for _, tag := range tags {
+		repo.tags[tag] = initial.sha
+	}
+	s.repos[key] = repo
+	return repo
+}
+
+// CreateBranch creates branch from the head of "main" with files written in
+// one commit on top of it.
+func (s *server) CreateBranch(
+	t *testing.T,
+	repo globalEntities.Repository,
+	branch string,
+	files map[string]string,
+) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.mustRepo(t, repo)
+	base, err := r.head(r.defaultBranch)
+	if err != nil {
+		t.Fatalf("fake repository %s/%s: %v", r.org, r.name, err)
+	}
+	updated := maps.Clone(base.files)
+	maps.Copy(updated, files)
+	c := r.commit("Update "+branch, updated, base.sha)
+	if err = r.createBranch(branch, c.sha); err != nil {
+		t.Fatalf("fake repository %s/%s: %v", r.org, r.name, err)
+	}
+}
+
+// FileContent returns the content of path on branch and whether it exists.
+func (s *server) FileContent(t *testing.T, repo globalEntities.Repository, branch, path string) (string, bool) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	head, err := s.mustRepo(t, repo).head(branch)
+	if err != nil {
+		return "", false
+	}
+	content, ok := head.files[strings.TrimPrefix(path, "/")]
+	return content, ok
+}
+
+// BranchExists reports whether branch exists in the repository.
+func (s *server) BranchExists(t *testing.T, repo globalEntities.Repository, branch string) bool {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.mustRepo(t, repo).head(branch)
+	return err == nil
+}
+
+// SetCheckState reports a commit status named context with state on the head
+// of branch, replacing an earlier status with the same context.
+func (s *server) SetCheckState(
+	t *testing.T,
+	repo globalEntities.Repository,
+	branch, context string,
+	state CheckState,
+) {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.mustRepo(t, repo)
+	head, err := r.head(branch)
+	if err != nil {
+		t.Fatalf("fake repository %s/%s: %v", r.org, r.name, err)
+	}
+	r.setStatus(head.sha, context, state)
+}
+
+// PullRequests returns snapshots of every pull request in the repository,
+// ordered by number.
+func (s *server) PullRequests(t *testing.T, repo globalEntities.Repository) []PullRequest {
+	t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.mustRepo(t, repo)
+	out := make([]PullRequest, 0, len(r.pullRequests))
+	for _, pr := range r.pullRequests {
+		snapshot := PullRequest{
+			Number:       pr.number,
+			Title:        pr.title,
+			Description:  pr.description,
+			SourceBranch: pr.source,
+			TargetBranch: pr.target,
+			State:        pr.state,
+		}
+		for _, c := range pr.comments {
+			snapshot.Comments = append(snapshot.Comments, Comment{
+				ID:       c.id,
+				ThreadID: c.threadID,
+				Author:   c.author,
+				Body:     c.body,
+				Path:     c.path,
+				Line:     c.line,
+				Status:   c.status,
+			})
+		}
+		for _, rv := range pr.reviews {
+			snapshot.Reviews = append(snapshot.Reviews, Review{
+				Author:  rv.author,
+				Verdict: rv.verdict,
+				Body:    rv.body,
+			})
+		}
+		out = append(out, snapshot)
+	}
+	return out
+}
+
+// mustRepo returns the repository behind repo or fails the test.
+func (s *server) mustRepo(t *testing.T, repo globalEntities.Repository) *repository {
+	t.Helper()
+
+	r, ok := s.repos[repo.Organization+"/"+repo.Name]
+	if !ok {
+		t.Fatalf("fake repository %s/%s does not exist", repo.Organization, repo.Name)
+	}
+	return r
+}
+
+// repo returns repository org/name.
+func (s *server) repo(org, name string) (*repository, bool) {
+	r, ok := s.repos[org+"/"+name]
+	return r, ok
+}
+
+// reposOf returns the repositories of org sorted by name; the boolean reports
+// whether org owns any.
+func (s *server) reposOf(org string) ([]*repository, bool) {
+	var out []*repository
+	for _, key := range slices.Sorted(maps.Keys(s.repos)) {
+		if r := s.repos[key]; strings.EqualFold(r.org, org) {
+			out = append(out, r)
+		}
+	}
+	return out, len(out) > 0
+}
+
+// allRepos returns every repository sorted by organization and name.
+func (s *server) allRepos() []*repository {
+	out := make([]*repository, 0, len(s.repos))
+	for _, key := range slices.Sorted(maps.Keys(s.repos)) {
+		out = append(out, s.repos[key])
+	}
+	return out
+}
+
+// addRepo registers a new empty repository, or returns false if it exists.
+func (s *server) addRepo(org, name string) (*repository, bool) {
+	if _, exists := s.repo(org, name); exists {
+		return nil, false
+	}
+	s.nextRepoID++
+	r := newRepository(s.nextRepoID, org, name)
+	s.repos[org+"/"+name] = r
+	return r, true
+}
+
+// importSource returns the repository of this server that the clone URL
+// cloneAddr points at, in either the "{org}/{name}.git" layout or Azure
+// DevOps' "{org}/{project}/_git/{name}" one. Imports from other hosts cannot
+// be fetched by a fake, so they are not found.
+func (s *server) importSource(cloneAddr string) (*repository, bool) {
+	parsed, err := url.Parse(cloneAddr)
+	if err != nil || parsed.Host != s.httpServer.Listener.Addr().String() {
+		return nil, false
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	switch {
+	case len(segments) == 2:
+		return s.repo(segments[0], strings.TrimSuffix(segments[1], ".git"))
+	case len(segments) == 4 && segments[2] == "_git":
+		return s.repo(segments[0], segments[3])
+	default:
+		return nil, false
+	}
+}
+
+// redactURL returns rawURL without its userinfo, the way forges echo import
+// URLs back without the credentials they carried.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	parsed.User = nil
+	return parsed.String()
+}
+
+// copyRepository copies the commits, branches and tags of source into target,
+// the way an import fetches every ref.
+func copyRepository(target, source *repository) {
+	target.defaultBranch = source.defaultBranch
+	maps.Copy(target.commits, source.commits)
+	maps.Copy(target.trees, source.trees)
+	maps.Copy(target.branches, source.branches)
+	maps.Copy(target.tags, source.tags)
+}
+
+// syncMirror fetches the source of pull mirror r again, the way a scheduled
+// or triggered sync does, recording when it succeeded or why it failed.
+func (s *server) syncMirror(r *repository) {
+	source, ok := s.importSource(r.importURL)
+	if !ok {
+		r.lastSyncError = "Unable to access repository " + r.importURL
+		return
+	}
+	copyRepository(r, source)
+	r.lastSync = time.Now().UTC()
+	r.lastSyncError = ""
+}
+
+// addComment appends a comment to the pull request. A non-zero inReplyTo
+// places it in the thread of that comment.
+func (s *server) addComment(pr *pullRequest, c *comment) *comment {
+	s.nextID++
+	c.id = s.nextID
+	c.threadID = c.id
+	if c.author == "" {
+		c.author = s.user
+	}
+	if c.inReplyTo != 0 {
+		for _, existing := range pr.comments {
+			if existing.id == c.inReplyTo {
+				c.threadID = existing.threadID
+				c.inReplyTo = existing.threadID
+				if c.path == "" {
+					c.path, c.line = existing.path, existing.line
+				}
+			}
+		}
+	}
+	pr.comments = append(pr.comments, c)
+	return c
+}
+
+// addReview appends a review verdict to the pull request.
+func (s *server) addReview(pr *pullRequest, verdict globalEntities.ReviewVerdict, body string) *review {
+	s.nextID++
+	rv := &review{id: s.nextID, author: s.user, verdict: verdict, body: body}
+	pr.reviews = append(pr.reviews, rv)
+	return rv
+}
+
+// writeJSON writes v as a JSON response with status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the {"message": ...} error body all four APIs share.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
+
+// decodeJSON decodes the request body into v, answering 400 on failure.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// page returns the slice of items selected by the page and size query
+// parameters, counted from page 1, and the number of the next page, or zero
+// on the last page.
+func page[T any](r *http.Request, items []T, sizeParam string) ([]T, int) {
+	size, err := strconv.Atoi(r.URL.Query().Get(sizeParam))
+	if err != nil || size <= 0 {
+		size = defaultPageSize
+	}
+	number, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || number <= 0 {
+		number = 1
+	}
+
+	start := (number - 1) * size
+	if start >= len(items) {
+		return []T{}, 0
+	}
+	end := min(start+size, len(items))
+	if end == len(items) {
+		return items[start:end], 0
+	}
+	return items[start:end], number + 1
+}
+
+// pageURL returns the request URL with its page query parameter set to number.
+func pageURL(r *http.Request, number int) string {
+	u := *r.URL
+	query := u.Query()
+	query.Set("page", strconv.Itoa(number))
+	u.RawQuery = query.Encode()
+	u.Scheme = "http"
+	u.Host = r.Host
+	return u.String()
+}
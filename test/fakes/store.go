@@ -0,0 +1,480 @@
+package fakes
+
+import (
+	"crypto/sha1" //nolint:gosec // git object IDs are SHA-1
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	globalEntities "github.com/rios0rios0/gitforge/pkg/global/domain/entities"
+)
+
+// Pull request states shared by every fake; each API renders them in its own
+// vocabulary ("opened" on GitLab, "active" on Azure DevOps, and so on).
+const (
+	stateOpen   = "open"
+	stateClosed = "closed"
+	stateMerged = "merged"
+)
+
+// Merge strategies understood by repository.merge.
+const (
+	mergeSquash      = "squash"
+	mergeCommit      = "merge"
+	mergeRebase      = "rebase"
+	mergeFastForward = "fast-forward-only"
+)
+
+var (
+	errBranchNotFound = errors.New("branch not found")
+	errBranchExists   = errors.New("branch already exists")
+	errMergeConflict  = errors.New("merge conflict")
+	errNotFastForward = errors.New("target branch cannot be fast-forwarded")
+)
+
+// repository is one in-memory git repository. Commits store the full file
+// map, so trees, diffs and merges never need real git objects.
+type repository struct {
+	id            int
+	org           string
+	name          string
+	description   string
+	defaultBranch string
+	mirror        bool
+	importURL     string // source the repository was imported from, if any
+	importError   string // why that import failed, if it did
+
+	mirrorInterval time.Duration // how often a pull mirror syncs; zero when disabled
+	lastSync       time.Time     // when a pull mirror last synced successfully
+	lastSyncError  string        // why the last sync of a pull mirror failed, if it did
+
+	branches map[string]string            // branch name -> commit SHA
+	tags     map[string]string            // tag name -> commit SHA
+	commits  map[string]*commit           // commit SHA -> commit
+	trees    map[string]map[string]string // tree SHA -> files, for the GitHub git data API
+	statuses map[string][]commitStatus    // commit SHA -> statuses
+
+	pullRequests []*pullRequest
+}
+
+// commit is a snapshot of every file in the repository.
+type commit struct {
+	sha     string
+	tree    string
+	message string
+	parents []string
+	files   map[string]string
+}
+
+// commitStatus is one check reported against a commit.
+type commitStatus struct {
+	context string
+	state   CheckState
+}
+
+// pullRequest is a pull (or merge) request between two branches.
+type pullRequest struct {
+	number             int
+	title              string
+	description        string
+	source             string
+	target             string
+	author             string
+	state              string
+	draft              bool
+	removeSourceBranch bool
+	headSHA            string // source head, frozen once the pull request is merged
+	baseSHA            string // merge base, recorded when the pull request is merged
+	mergeSHA           string
+	createdAt          time.Time
+	mergedAt           time.Time
+	comments           []*comment
+	reviews            []*review
+}
+
+// comment is a pull request comment. Root comments have threadID equal to
+// their own ID; replies carry the root's ID in both threadID and inReplyTo.
+type comment struct {
+	id        int64
+	threadID  int64
+	inReplyTo int64
+	reviewID  int64 // review the comment was submitted with, if any
+	author    string
+	body      string
+	path      string
+	line      int
+	status    string
+}
+
+// review is a submitted review verdict.
+type review struct {
+	id      int64
+	author  string
+	verdict globalEntities.ReviewVerdict
+	body    string
+}
+
+func newRepository(id int, org, name string) *repository {
+	return &repository{
+		id:            id,
+		org:           org,
+		name:          name,
+		defaultBranch: "main",
+		branches:      make(map[string]string),
+		tags:          make(map[string]string),
+		commits:       make(map[string]*commit),
+		trees:         make(map[string]map[string]string),
+		statuses:      make(map[string][]commitStatus),
+	}
+}
+
+// head returns the commit at the tip of branch.
+func (r *repository) head(branch string) (*commit, error) {
+	sha, ok := r.branches[strings.TrimPrefix(branch, "refs/heads/")]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errBranchNotFound, branch)
+	}
+	return r.commits[sha], nil
+}
+
+// resolve returns the commit named by a branch, tag, or commit SHA.
+func (r *repository) resolve(ref string) (*commit, bool) {
+	ref = strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+	if ref == "" {
+		ref = r.defaultBranch
+	}
+	if sha, ok := r.branches[ref]; ok {
+		return r.commits[sha], true
+	}
+	if sha, ok := r.tags[ref]; ok {
+		return r.commits[sha], true
+	}
+	c, ok := r.commits[ref]
+	return c, ok
+}
+
+// commit records a new commit with files on top of parents and returns it.
+func (r *repository) commit(message string, files map[string]string, parents ...string) *commit {
+	tree := r.storeTree(files)
+	h := sha1.New() //nolint:gosec // git object IDs are SHA-1
+	fmt.Fprintf(h, "tree %s\n", tree)
+	for _, parent := range parents {
+		fmt.Fprintf(h, "parent %s\n", parent)
+	}
+	fmt.Fprintf(h, "sequence %d\n\n%s", len(r.commits), message)
+
+	c := &commit{
+		sha:     hex.EncodeToString(h.Sum(nil)),
+		tree:    tree,
+		message: message,
+		parents: parents,
+		files:   files,
+	}
+	r.commits[c.sha] = c
+	return c
+}
+
+// storeTree records files under their tree SHA and returns it.
+func (r *repository) storeTree(files map[string]string) string {
+	h := sha1.New() //nolint:gosec // git object IDs are SHA-1
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		fmt.Fprintf(h, "%s\x00%s\n", path, blobSHA(files[path]))
+	}
+	sha := hex.EncodeToString(h.Sum(nil))
+	r.trees[sha] = files
+	return sha
+}
+
+// createBranch points a new branch at sha.
+func (r *repository) createBranch(branch, sha string) error {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	if _, exists := r.branches[branch]; exists {
+		return fmt.Errorf("%w: %q", errBranchExists, branch)
+	}
+	r.branches[branch] = sha
+	return nil
+}
+
+// pullRequest returns the pull request with the given number.
+func (r *repository) pullRequest(number int) (*pullRequest, bool) {
+	for _, pr := range r.pullRequests {
+		if pr.number == number {
+			return pr, true
+		}
+	}
+	return nil, false
+}
+
+// thread returns the comment with the given ID.
+func (pr *pullRequest) thread(id int64) (*comment, bool) {
+	for _, c := range pr.comments {
+		if c.id == id {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// openPullRequest records a new open pull request from source into target.
+func (r *repository) openPullRequest(title, description, source, target, author string) (*pullRequest, error) {
+	source = strings.TrimPrefix(source, "refs/heads/")
+	target = strings.TrimPrefix(target, "refs/heads/")
+	sourceHead, err := r.head(source)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = r.head(target); err != nil {
+		return nil, err
+	}
+	for _, pr := range r.pullRequests {
+		if pr.state == stateOpen && pr.source == source && pr.target == target {
+			return nil, fmt.Errorf("a pull request for %q into %q already exists (#%d)", source, target, pr.number)
+		}
+	}
+
+	pr := &pullRequest{
+		number:      len(r.pullRequests) + 1,
+		title:       title,
+		description: description,
+		source:      source,
+		target:      target,
+		author:      author,
+		state:       stateOpen,
+		headSHA:     sourceHead.sha,
+		createdAt:   time.Now().UTC(),
+	}
+	r.pullRequests = append(r.pullRequests, pr)
+	return pr, nil
+}
+
+// sourceHead returns the current head of the pull request's source branch,
+// or the head it had when it was merged or its branch was deleted.
+func (r *repository) sourceHead(pr *pullRequest) *commit {
+	if pr.state == stateOpen {
+		if sha, ok := r.branches[pr.source]; ok {
+			pr.headSHA = sha
+		}
+	}
+	return r.commits[pr.headSHA]
+}
+
+// mergeBase returns the first common ancestor of the pull request's source and
+// target, or the source head when the target branch is gone.
+func (r *repository) mergeBase(pr *pullRequest) *commit {
+	if pr.baseSHA != "" {
+		return r.commits[pr.baseSHA]
+	}
+	head := r.sourceHead(pr)
+	targetSHA, ok := r.branches[pr.target]
+	if !ok {
+		return head
+	}
+
+	ancestors := make(map[string]bool)
+	queue := []string{targetSHA}
+	for len(queue) > 0 {
+		sha := queue[0]
+		queue = queue[1:]
+		if ancestors[sha] {
+			continue
+		}
+		ancestors[sha] = true
+		queue = append(queue, r.commits[sha].parents...)
+	}
+
+	queue = []string{head.sha}
+	seen := make(map[string]bool)
+	for len(queue) > 0 {
+		sha := queue[0]
+		queue = queue[1:]
+		if ancestors[sha] {
+			return r.commits[sha]
+		}
+		if seen[sha] {
+			continue
+		}
+		seen[sha] = true
+		queue = append(queue, r.commits[sha].parents...)
+	}
+	return r.commits[targetSHA]
+}
+
+// changes returns the files the pull request changes, compared against its
+// merge base the way the forges' three-dot diffs do.
+func (r *repository) changes(pr *pullRequest) []fileChange {
+	return diffFiles(r.mergeBase(pr).files, r.sourceHead(pr).files)
+}
+
+// merge merges an open pull request into its target with strategy and
+// optionally deletes the source branch.
+func (r *repository) merge(pr *pullRequest, strategy string, deleteSource bool) error {
+	if pr.state != stateOpen {
+		return fmt.Errorf("pull request #%d is %s", pr.number, pr.state)
+	}
+	target, err := r.head(pr.target)
+	if err != nil {
+		return err
+	}
+	source := r.sourceHead(pr)
+	base := r.mergeBase(pr)
+
+	var merged *commit
+	switch strategy {
+	case mergeFastForward:
+		if base.sha != target.sha {
+			return fmt.Errorf("%w: %q", errNotFastForward, pr.target)
+		}
+		merged = source
+	case mergeCommit, mergeSquash, mergeRebase:
+		files, mergeErr := mergeFiles(base.files, target.files, source.files)
+		if mergeErr != nil {
+			return mergeErr
+		}
+		message := fmt.Sprintf("%s (#%d)", pr.title, pr.number)
+		parents := []string{target.sha}
+		switch strategy {
+		case mergeCommit:
+			message = fmt.Sprintf("Merge pull request #%d from %s\n\n%s", pr.number, pr.source, pr.title)
+			parents = append(parents, source.sha)
+		case mergeRebase:
+			message = source.message
+		}
+		merged = r.commit(message, files, parents...)
+	default:
+		return fmt.Errorf("unsupported merge strategy %q", strategy)
+	}
+
+	r.branches[pr.target] = merged.sha
+	pr.state = stateMerged
+	pr.baseSHA = base.sha
+	pr.mergeSHA = merged.sha
+	pr.mergedAt = time.Now().UTC()
+	if deleteSource {
+		delete(r.branches, pr.source)
+	}
+	return nil
+}
+
+// setStatus records state for context on sha, replacing an earlier status
+// with the same context.
+func (r *repository) setStatus(sha, context string, state CheckState) {
+	statuses := r.statuses[sha]
+	for i := range statuses {
+		if statuses[i].context == context {
+			statuses[i].state = state
+			return
+		}
+	}
+	r.statuses[sha] = append(statuses, commitStatus{context: context, state: state})
+}
+
+// checkState folds the statuses of sha into one state; no statuses is success.
+func (r *repository) checkState(sha string) (CheckState, int) {
+	statuses := r.statuses[sha]
+	state := CheckSuccess
+	for _, status := range statuses {
+		switch status.state {
+		case CheckFailure:
+			return CheckFailure, len(statuses)
+		case CheckPending:
+			state = CheckPending
+		case CheckSuccess:
+		}
+	}
+	return state, len(statuses)
+}
+
+// mergeFiles three-way merges the file maps of ours and theirs against base.
+func mergeFiles(base, ours, theirs map[string]string) (map[string]string, error) {
+	merged := make(map[string]string)
+	paths := make(map[string]bool)
+	for _, files := range []map[string]string{base, ours, theirs} {
+		for path := range files {
+			paths[path] = true
+		}
+	}
+
+	var conflicts []string
+	for path := range paths {
+		baseContent, inBase := base[path]
+		ourContent, inOurs := ours[path]
+		theirContent, inTheirs := theirs[path]
+
+		switch {
+		case inOurs == inTheirs && ourContent == theirContent:
+			if inOurs {
+				merged[path] = ourContent
+			}
+		case inOurs == inBase && ourContent == baseContent:
+			if inTheirs {
+				merged[path] = theirContent
+			}
+		case inTheirs == inBase && theirContent == baseContent:
+			if inOurs {
+				merged[path] = ourContent
+			}
+		default:
+			conflicts = append(conflicts, path)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		slices.Sort(conflicts)
+		return nil, fmt.Errorf("%w in %s", errMergeConflict, strings.Join(conflicts, ", "))
+	}
+	return merged, nil
+}
+
+// blobSHA returns the git blob object ID of content.
+func blobSHA(content string) string {
+	h := sha1.New() //nolint:gosec // git object IDs are SHA-1
+	fmt.Fprintf(h, "blob %d\x00%s", len(content), content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// treeEntry is a file or directory in a recursive tree listing.
+type treeEntry struct {
+	path  string
+	isDir bool
+	sha   string
+}
+
+// treeEntries lists the files and directories of files, sorted by path.
+func treeEntries(files map[string]string) []treeEntry {
+	dirs := make(map[string]bool)
+	var entries []treeEntry
+	for path, content := range files {
+		entries = append(entries, treeEntry{path: path, sha: blobSHA(content)})
+		for dir := parentDir(path); dir != ""; dir = parentDir(dir) {
+			if !dirs[dir] {
+				dirs[dir] = true
+				entries = append(entries, treeEntry{path: dir, isDir: true, sha: treeSHA(files, dir)})
+			}
+		}
+	}
+	slices.SortFunc(entries, func(a, b treeEntry) int { return strings.Compare(a.path, b.path) })
+	return entries
+}
+
+// treeSHA derives a stable object ID for directory dir of files.
+func treeSHA(files map[string]string, dir string) string {
+	h := sha1.New() //nolint:gosec // git object IDs are SHA-1
+	for _, path := range slices.Sorted(maps.Keys(files)) {
+		if strings.HasPrefix(path, dir+"/") {
+			fmt.Fprintf(h, "%s\x00%s\n", path, blobSHA(files[path]))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func parentDir(path string) string {
+	idx := strings.LastIndex(path, "/")
+	if idx < 0 {
+		return ""
+	}
+	return path[:idx]
+}